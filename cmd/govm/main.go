@@ -1,52 +1,48 @@
 package main
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/liangyou/govm/internal/bootstrap"
 	"github.com/liangyou/govm/internal/cli"
-	"github.com/liangyou/govm/internal/env"
-	"github.com/liangyou/govm/internal/platform"
-	"github.com/liangyou/govm/internal/region"
-	"github.com/liangyou/govm/internal/remote"
-	"github.com/liangyou/govm/internal/storage"
-	"github.com/liangyou/govm/internal/version"
-	"github.com/liangyou/govm/pkg/models"
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/prompt"
+	"github.com/liangyou/govm/internal/selector"
 )
 
 const appVersion = "0.1.0"
 
 func main() {
-	cfg := models.Config{}
-	checker := platform.NewChecker(cfg)
-	if err := checker.Validate(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// goShim 启用时 shim 目录中的 go、gofmt 是指向 govm 的符号链接，此时直接转交给解析出的版本。
+	if tool := filepath.Base(os.Args[0]); selector.Handles(tool) {
+		os.Exit(selector.Main(tool, os.Args[1:]))
 	}
-
-	store := storage.NewFileStorage(cfg)
-
-	detector := region.NewDetector()
-	countryCode, err := detector.CountryCode(context.Background())
+	// prompt 在每次刷新提示符时执行，跳过服务加载（配置校验、区域探测等），只读取当前版本标记。
+	if len(os.Args) > 1 && os.Args[1] == "prompt" {
+		os.Exit(prompt.Run(os.Stdout, os.Stderr, os.Args[2:]))
+	}
+	// -changed-exit-code 面向配置管理工具，它们把 stderr 上的任何输出视为异常，警告改写到 stdout。
+	warn := os.Stderr
+	if cli.WantsChangedExitCode(os.Args[1:]) {
+		warn = os.Stdout
+	}
+	services, err := bootstrap.Load(config.Path(), bootstrap.WithWarnings(warn))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "warn: detect region failed, fallback to default source: %v\n", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	mirror := region.SelectMirror(countryCode)
-
-	remoteClient := remote.NewClient(
-		remote.WithBaseURL(mirror.APIBase),
-		remote.WithDownloadBase(mirror.DownloadBase),
-	)
-	downloader := version.NewDownloader(cfg)
-	installer := version.NewInstaller(store, downloader)
-	envManager := env.NewManager(store, cfg)
-	switcher := version.NewSwitcher(store, envManager)
-	uninstaller := version.NewUninstaller(store)
-	lister := version.NewLister(remoteClient, store)
-
-	app := cli.NewApp(os.Stdout, lister, installer, switcher, uninstaller, appVersion)
+	app := services.NewApp(os.Stdout, appVersion)
 	if err := app.Run(os.Args[1:]); err != nil {
+		var exitErr *cli.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				fmt.Fprintln(os.Stderr, exitErr.Err)
+			}
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,489 @@
+// Package bootstrap 根据配置构建 govm 的完整服务依赖图，供命令行入口、测试与 serve 模式共用。
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/cli"
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/internal/httpclient"
+	"github.com/liangyou/govm/internal/i18n"
+	"github.com/liangyou/govm/internal/platform"
+	"github.com/liangyou/govm/internal/policy"
+	"github.com/liangyou/govm/internal/provision"
+	"github.com/liangyou/govm/internal/region"
+	"github.com/liangyou/govm/internal/remote"
+	"github.com/liangyou/govm/internal/selector"
+	"github.com/liangyou/govm/internal/shims"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/timing"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// Services 是装配完成的服务集合，字段在 New 返回后不再变化。
+type Services struct {
+	Config     models.Config
+	ConfigPath string
+	Policy     *policy.Policy
+	Checker    *platform.Checker
+	Store      *storage.FileStorage
+	Clients    *httpclient.Clients
+	Mirror     region.MirrorConfig
+	Remote     remote.RemoteClient
+	Events     *events.Bus
+	Timings    *timing.Recorder
+
+	Downloader  *version.Downloader
+	Installer   *version.Installer
+	Env         *env.Manager
+	Switcher    *version.Switcher
+	Uninstaller *version.Uninstaller
+	Lister      *version.Lister
+	Verifier    *version.Verifier
+	Adopter     *version.Adopter
+	Shims       *shims.Manager
+
+	warn io.Writer
+}
+
+// Option 调整服务的装配方式。
+type Option func(*options)
+
+type options struct {
+	warn        io.Writer
+	configPath  string
+	countryCode *string
+	mirror      *region.MirrorConfig
+	detector    CountryDetector
+	heuristic   RegionGuesser
+	remote      remote.RemoteClient
+	bus         *events.Bus
+	timings     *timing.Recorder
+}
+
+// slowRegionDetection 为地区探测耗时的警告阈值，超过时提示用 region 配置跳过探测。
+const slowRegionDetection = 2 * time.Second
+
+// WithWarnings 指定警告输出位置，默认写入 os.Stderr。
+func WithWarnings(w io.Writer) Option {
+	return func(o *options) {
+		o.warn = w
+	}
+}
+
+// WithConfigPath 指定配置文件路径，供 backup、config 等命令读写；Load 会自动设置。
+func WithConfigPath(path string) Option {
+	return func(o *options) {
+		o.configPath = path
+	}
+}
+
+// WithCountryCode 直接指定地区代码，跳过配置中的 region 偏好与网络探测。
+func WithCountryCode(code string) Option {
+	return func(o *options) {
+		o.countryCode = &code
+	}
+}
+
+// CountryDetector 按公网 IP 探测国家代码，region 为 auto 时使用。
+type CountryDetector interface {
+	CountryCode(ctx context.Context) (string, error)
+}
+
+// WithRegionDetector 替换默认的公网 IP 探测器。
+func WithRegionDetector(d CountryDetector) Option {
+	return func(o *options) {
+		o.detector = d
+	}
+}
+
+// RegionGuesser 在网络探测失败时根据本机环境推断国家代码，reason 说明推断依据。
+type RegionGuesser interface {
+	Guess() (code, reason string)
+}
+
+// WithRegionHeuristic 替换默认的时区/语言环境推断。
+func WithRegionHeuristic(g RegionGuesser) Option {
+	return func(o *options) {
+		o.heuristic = g
+	}
+}
+
+// WithMirror 直接指定版本列表与下载地址，跳过地区选择；策略中的 requiredMirror 仍然生效。
+func WithMirror(m region.MirrorConfig) Option {
+	return func(o *options) {
+		o.mirror = &m
+	}
+}
+
+// WithRemote 替换版本列表来源，测试中可以注入固定的版本列表。
+func WithRemote(r remote.RemoteClient) Option {
+	return func(o *options) {
+		o.remote = r
+	}
+}
+
+// WithEventBus 使用给定的事件总线，便于宿主在构建前订阅事件。
+func WithEventBus(bus *events.Bus) Option {
+	return func(o *options) {
+		o.bus = bus
+	}
+}
+
+// WithTimings 指定记录各阶段耗时的 Recorder，测试中可以注入使用假时钟的记录器。
+func WithTimings(r *timing.Recorder) Option {
+	return func(o *options) {
+		o.timings = r
+	}
+}
+
+// Load 读取 path 处的配置文件并构建服务。
+func Load(path string, opts ...Option) (*Services, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg, append([]Option{WithConfigPath(path)}, opts...)...)
+}
+
+// New 按 cfg 构建全部服务；配置无效、无法确定根目录、策略文件无法读取或安装目录不可用时返回错误。
+func New(cfg models.Config, opts ...Option) (*Services, error) {
+	o := options{warn: os.Stderr}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cfg, err := storage.ResolveDirs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &Services{Config: cfg, ConfigPath: o.configPath, Timings: o.timings, warn: o.warn}
+	if s.Timings == nil {
+		s.Timings = timing.NewRecorder()
+	}
+
+	pol, err := policy.Load(cfg.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	s.Policy = pol
+
+	s.Checker = platform.NewChecker(cfg)
+	if err := s.Checker.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.SystemMode {
+		if user := s.Checker.SudoUser(); user != "" {
+			s.warnf("running under sudo for %s, per-user current version is recorded for root", user)
+		}
+	}
+
+	s.Store = storage.NewFileStorage(cfg)
+
+	s.Clients = httpclient.New(httpclient.TimeoutsFromConfig(cfg))
+	if err := s.Clients.ConfigureTLS(httpclient.TLSFromConfig(cfg)); err != nil {
+		return nil, err
+	}
+	if cfg.InsecureSkipVerify {
+		s.warnf("TLS certificate verification is DISABLED (insecureSkipVerify); downloads are only protected by SHA256 checksums from the same unverified source")
+	}
+
+	if o.mirror != nil {
+		s.Mirror = *o.mirror
+	} else {
+		s.Mirror = region.SelectMirror(s.countryCode(o))
+	}
+	if cfg.MirrorProbe && o.mirror == nil && (pol == nil || pol.RequiredMirror == "") {
+		s.probeDownloadBase()
+	}
+	if err := pol.CheckMirror(s.Mirror.DownloadBase); err != nil {
+		if pol.WarnOnly() {
+			s.warnf("%v", err)
+		} else {
+			s.Mirror.DownloadBase = pol.RequiredMirror
+		}
+	}
+	if err := s.enforceCatalogMirror(pol); err != nil {
+		return nil, err
+	}
+
+	s.Remote = o.remote
+	if s.Remote == nil {
+		s.Remote, err = remote.NewProvider(remote.ProviderConfig{
+			Type:         s.Config.Catalog,
+			URL:          s.Config.CatalogURL,
+			APIBase:      s.Mirror.APIBase,
+			DownloadBase: s.Mirror.DownloadBase,
+			HTTPClient:   s.Clients.API,
+			CacheDir:     s.Store.CacheDir(),
+			OnStale: func(err error, fetchedAt time.Time) {
+				s.warnf("%v; using cached release list from %s", err, fetchedAt.Local().Format(time.RFC3339))
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.Events = o.bus
+	if s.Events == nil {
+		s.Events = events.NewBus()
+	}
+	s.Timings.Observe(s.Events)
+	downloadOpts := []version.DownloaderOption{version.WithHTTPClient(s.Clients.Download), version.WithDownloadEvents(s.Events)}
+	if opt := s.checksumOption(); opt != nil {
+		downloadOpts = append(downloadOpts, opt)
+	}
+	s.Downloader = version.NewDownloader(cfg, downloadOpts...)
+	var shimOpts []shims.Option
+	if cfg.GoShim {
+		if exe, err := os.Executable(); err == nil {
+			shimOpts = append(shimOpts, shims.WithSelectors(exe))
+		} else {
+			s.warnf("goShim is enabled but the govm executable cannot be located (%v); the go shim is not updated", err)
+		}
+	}
+	s.Shims = shims.New(s.Store.ShimDir(), shimOpts...)
+	perms, err := s.permissions()
+	if err != nil {
+		return nil, err
+	}
+	s.Installer = version.NewInstaller(s.Store, s.Downloader,
+		version.WithInstallEvents(s.Events),
+		version.WithInstallPermissions(perms),
+		version.WithStreamingExtract(),
+		version.WithInstallShims(s.Shims),
+		version.WithRecoveryReporter(func(ver string, err error) {
+			if err != nil {
+				s.warnf("cleaning up an interrupted install failed: %v", err)
+				return
+			}
+			s.warnf("rolled back an interrupted install of go%s", ver)
+		}),
+	)
+	s.Env = env.NewManager(s.Store, cfg, env.WithShimDir(s.Shims.Dir()))
+	switcherOpts := []version.SwitcherOption{
+		version.WithShadowReporter(func(ver string, check env.PathCheck) {
+			for _, bin := range check.Shadows {
+				s.warnf("%s comes before govm in PATH and will run instead of go%s; remove %s from PATH or load the govm block in your shell config after it", bin, ver, filepath.Dir(bin))
+			}
+		}),
+	}
+	if cfg.UsageStats {
+		switcherOpts = append(switcherOpts, version.WithUsageRecorder(s.Store))
+	}
+	s.Switcher = version.NewSwitcher(s.Store, s.Env, switcherOpts...)
+	s.Uninstaller = version.NewUninstaller(s.Store,
+		version.WithLeftoverReporter(func(ver, dir string, files []string) {
+			s.warnf("kept %d user-added file(s) in %s after removing go%s", len(files), dir, ver)
+		}),
+		version.WithUninstallShims(s.Shims),
+	)
+	s.Lister = version.NewLister(timedRemote{s.Remote, s.Timings}, s.Store)
+	s.Verifier = version.NewVerifier(s.Store)
+	s.Adopter = version.NewAdopter(s.Store, version.WithAdoptShims(s.Shims))
+	return s, nil
+}
+
+// countryCode 依次使用显式指定的地区、配置中的 region 偏好（含 GOVM_REGION）、网络探测结果与时区/语言环境推断；
+// 只有偏好为 auto 时才会发起探测请求。
+func (s *Services) countryCode(o options) string {
+	if o.countryCode != nil {
+		return *o.countryCode
+	}
+	code, detect := region.FromPreference(s.Config.Region)
+	if !detect {
+		return code
+	}
+	detector := o.detector
+	if detector == nil {
+		detector = region.NewDetector(region.WithHTTPClient(s.Clients.API))
+	}
+	stop := s.Timings.Start(timing.Region)
+	code, err := detector.CountryCode(context.Background())
+	if elapsed := stop(); elapsed > slowRegionDetection {
+		s.warnf("region detection took %s; set \"region\" to cn or global in the config file or export %s to skip it", elapsed.Round(100*time.Millisecond), config.EnvRegion)
+	}
+	if err == nil {
+		return code
+	}
+	heuristic := o.heuristic
+	if heuristic == nil {
+		heuristic = region.NewHeuristic()
+	}
+	if guess, reason := heuristic.Guess(); guess != "" {
+		s.warnf("detect region failed, guessed %s from %s: %v", guess, reason, err)
+		return guess
+	}
+	s.warnf("detect region failed, fallback to default source: %v", err)
+	return ""
+}
+
+// timedRemote 记录获取版本列表的耗时。
+type timedRemote struct {
+	remote.RemoteClient
+	timings *timing.Recorder
+}
+
+func (r timedRemote) FetchVersions(ctx context.Context) ([]models.Version, error) {
+	defer r.timings.Start(timing.Catalog)()
+	return r.RemoteClient.FetchVersions(ctx)
+}
+
+// probeDownloadBase 对候选下载地址测速，用最快的一个替换按地区选择的下载地址；测速失败时保持原选择。
+func (s *Services) probeDownloadBase() {
+	candidates := s.Config.Mirrors
+	if len(candidates) == 0 {
+		candidates = region.DownloadCandidates
+	}
+	prober := region.NewLatencyProber(region.WithProbeHTTPClient(s.Clients.Download))
+	base, _, err := prober.Choose(context.Background(), candidates, s.Store.MirrorProbePath(), s.Config.MirrorProbeTTL)
+	if err != nil {
+		s.warnf("mirror probe failed, keeping %s: %v", s.Mirror.DownloadBase, err)
+		return
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	s.Mirror.DownloadBase = base
+}
+
+// runCacheDir 为 `govm run` 与 go 选择器共用的各版本 GOROOT 缓存目录。
+func (s *Services) runCacheDir() string {
+	return selector.RunCacheDir(s.Store.CacheDir())
+}
+
+// enforceCatalogMirror 让版本列表同样来自策略要求的镜像：官方 API 不在镜像之下时改为抓取镜像的目录索引，
+// 自定义的 catalogURL 不在镜像之下时拒绝启动；warn 模式下仅输出警告。
+func (s *Services) enforceCatalogMirror(pol *policy.Policy) error {
+	catalog := strings.ToLower(strings.TrimSpace(s.Config.Catalog))
+	endpoint := s.Config.CatalogURL
+	switch {
+	case catalog == "" || catalog == remote.CatalogOfficial:
+		endpoint = s.Mirror.APIBase
+	case catalog == remote.CatalogListing && endpoint == "":
+		endpoint = s.Mirror.DownloadBase
+	}
+	err := pol.CheckEndpoint(endpoint)
+	switch {
+	case err == nil:
+		return nil
+	case pol.WarnOnly():
+		s.warnf("%v", err)
+		return nil
+	case catalog == "" || catalog == remote.CatalogOfficial:
+		s.Mirror.APIBase = ""
+		s.Config.Catalog, s.Config.CatalogURL = remote.CatalogListing, ""
+		return nil
+	}
+	return err
+}
+
+// checksumOption 根据 checksumSource 决定是否用官方版本列表交叉校验；auto 模式下版本列表本身来自官方 API 时无需重复校验。
+func (s *Services) checksumOption() version.DownloaderOption {
+	switch s.Config.ChecksumSource {
+	case config.ChecksumMirror:
+		return nil
+	case config.ChecksumOfficial:
+		return version.WithCanonicalChecksums(remote.NewChecksumIndex(s.Clients.API, s.Store.CacheDir()), true, nil)
+	}
+	if catalog := strings.ToLower(s.Config.Catalog); (catalog == "" || catalog == remote.CatalogOfficial) && remote.IsOfficialAPI(s.Mirror.APIBase) {
+		return nil
+	}
+	return version.WithCanonicalChecksums(remote.NewChecksumIndex(s.Clients.API, s.Store.CacheDir()), false, func(ver string, err error) {
+		s.warnf("only the mirror's checksum is available for go%s (%v); set checksumSource to %q to require the official one", ver, err, config.ChecksumOfficial)
+	})
+}
+
+// permissions 根据 dirMode、fileMode 与 owner 配置生成安装目录树的权限设置，非 root 运行时忽略 owner。
+func (s *Services) permissions() (version.Permissions, error) {
+	perms := version.Permissions{DirMode: s.Config.DirMode, FileMode: s.Config.FileMode, ReadOnly: s.Config.ReadOnlyGoRoot}
+	if s.Config.Owner == "" {
+		return perms, nil
+	}
+	if os.Geteuid() != 0 {
+		s.warnf("owner %q is only applied when running as root", s.Config.Owner)
+		return perms, nil
+	}
+	uid, gid, err := platform.LookupOwner(s.Config.Owner)
+	if err != nil {
+		return perms, err
+	}
+	perms.Chown, perms.UID, perms.GID = true, uid, gid
+	return perms, nil
+}
+
+func (s *Services) warnf(format string, args ...any) {
+	fmt.Fprintf(s.warn, "warn: "+format+"\n", args...)
+}
+
+// AppOptions 返回把全部服务接入命令行应用所需的选项。
+func (s *Services) AppOptions() []cli.AppOption {
+	catalogCache, _ := s.Remote.(cli.CatalogCache)
+	opts := []cli.AppOption{
+		cli.WithEventBus(s.Events),
+		cli.WithWarnings(s.warn),
+		cli.WithTimings(s.Timings),
+		cli.WithGoPath(s.Config.GoPath),
+		cli.WithGoToolchain(s.Config.GoToolchain),
+		cli.WithShellRC(s.Config.ShellRC),
+		cli.WithRunCache(s.runCacheDir()),
+		cli.WithPermissionChecker(s.Checker),
+		cli.WithLocker(s.Store),
+		cli.WithVerifier(s.Verifier),
+		cli.WithDedup(version.NewDeduplicator(s.Store)),
+		cli.WithManifests(s.Store),
+		cli.WithReleaseNotes(remote.NewReleaseNotes(s.Clients.API, s.Store.CacheDir(), func(err error, fetchedAt time.Time) {
+			s.warnf("%v; using cached release notes from %s", err, fetchedAt.Local().Format(time.RFC3339))
+		})),
+		cli.WithNetwork(s.Clients),
+		cli.WithResolver(remote.NewURLResolver(s.Mirror.DownloadBase)),
+		cli.WithBackup(s.Store, s.ConfigPath),
+		cli.WithConfigFile(s.ConfigPath, s.Env),
+		cli.WithShellReview(s.Env),
+		cli.WithIDESetup(s.Env),
+		cli.WithImplode(s.Store, s.Env),
+		cli.WithCaches(s.Store),
+		cli.WithRecentVersions(s.Store),
+		cli.WithHistory(s.Store),
+		cli.WithVersionLocks(s.Store),
+		cli.WithPlugins(s.Config.RootDir),
+		cli.WithLocale(i18n.Detect(os.Getenv)),
+		cli.WithLayoutMigrator(config.NewLayoutMigrator()),
+		cli.WithShims(s.Shims),
+		cli.WithAdopter(s.Adopter),
+		cli.WithMarkerHealth(version.NewMarkerChecker(s.Store)),
+		cli.WithMetadataRepair(s.Store),
+		cli.WithWatcher(s.Store),
+		cli.WithProvisioner(provision.New(s.Remote, s.Downloader)),
+		cli.WithBundler(version.NewBundler(s.Remote, s.Downloader, s.Downloader.DownloadsDir(), s.Store.CacheDir())),
+		cli.WithStatusSources(s.Mirror.DownloadBase, catalogCache),
+	}
+	if config.Packaged() {
+		opts = append(opts, cli.WithPackaged())
+	}
+	if s.Policy != nil {
+		opts = append(opts, cli.WithPolicy(s.Policy))
+	}
+	if s.Config.UsageStats {
+		opts = append(opts, cli.WithUsageStats(s.Store))
+	}
+	if s.Config.SignMetadata {
+		opts = append(opts, cli.WithMetadataSignature(s.Store))
+	}
+	return opts
+}
+
+// NewApp 构建写入 out 的命令行应用，extra 中的选项在默认选项之后应用。
+func (s *Services) NewApp(out io.Writer, appVersion string, extra ...cli.AppOption) *cli.App {
+	opts := append(s.AppOptions(), extra...)
+	return cli.NewApp(out, s.Lister, s.Installer, s.Switcher, s.Uninstaller, appVersion, opts...)
+}
@@ -0,0 +1,274 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/internal/cli"
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/httpclient"
+	"github.com/liangyou/govm/internal/region"
+	"github.com/liangyou/govm/internal/remote"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/timing"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeRemote struct{ versions []models.Version }
+
+func (f fakeRemote) FetchVersions(context.Context) ([]models.Version, error) { return f.versions, nil }
+
+func TestNewWiresApp(t *testing.T) {
+	t.Parallel()
+
+	cfg := models.Config{RootDir: filepath.Join(t.TempDir(), "govm"), Region: "auto"}
+	var warnings bytes.Buffer
+	services, err := New(cfg,
+		WithWarnings(&warnings),
+		WithCountryCode("CN"),
+		WithRemote(fakeRemote{versions: []models.Version{{Number: "1.22.4"}}}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if services.Mirror != region.StudyGolangMirror {
+		t.Fatalf("Mirror = %+v, want %+v", services.Mirror, region.StudyGolangMirror)
+	}
+
+	var out bytes.Buffer
+	app := services.NewApp(&out, "test")
+	if err := app.Run([]string{"status", "--json"}); err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	var report cli.StatusReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decode status: %v\n%s", err, out.String())
+	}
+	if report.Installed != 0 || report.Mirror != region.StudyGolangMirror.DownloadBase || report.UpdatesError != "" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("unexpected warnings: %q", warnings.String())
+	}
+}
+
+func TestNewAppliesRequiredMirror(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	policyFile := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(policyFile, []byte(`{"requiredMirror":"https://mirror.example.com/go/","mode":"warn"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := models.Config{RootDir: filepath.Join(dir, "govm"), PolicyFile: policyFile}
+
+	var warnings bytes.Buffer
+	services, err := New(cfg, WithWarnings(&warnings), WithCountryCode("US"), WithRemote(fakeRemote{}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if services.Mirror.DownloadBase != region.GoDevMirror.DownloadBase {
+		t.Fatalf("warn mode should keep the selected mirror, got %s", services.Mirror.DownloadBase)
+	}
+	if !strings.HasPrefix(warnings.String(), "warn: ") {
+		t.Fatalf("expected a policy warning, got %q", warnings.String())
+	}
+
+	if err := os.WriteFile(policyFile, []byte(`{"requiredMirror":"https://mirror.example.com/go/"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	services, err = New(cfg, WithWarnings(&warnings), WithCountryCode("US"), WithRemote(fakeRemote{}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if services.Mirror.DownloadBase != "https://mirror.example.com/go/" {
+		t.Fatalf("enforce mode should switch to the required mirror, got %s", services.Mirror.DownloadBase)
+	}
+	// 官方 API 不在要求的镜像之下，版本列表改为抓取镜像的目录索引。
+	if services.Mirror.APIBase != "" || services.Config.Catalog != remote.CatalogListing || services.Config.CatalogURL != "" {
+		t.Fatalf("enforce mode must read the release list from the required mirror, got api=%q catalog=%q url=%q",
+			services.Mirror.APIBase, services.Config.Catalog, services.Config.CatalogURL)
+	}
+
+	cfg.Catalog, cfg.CatalogURL = remote.CatalogStatic, "https://catalog.example.org/go.json"
+	if _, err := New(cfg, WithWarnings(&warnings), WithCountryCode("US"), WithRemote(fakeRemote{})); err == nil || !strings.Contains(err.Error(), "required mirror") {
+		t.Fatalf("a catalogURL outside the required mirror must be refused, got %v", err)
+	}
+	cfg.CatalogURL = "https://mirror.example.com/go/catalog.json"
+	if _, err := New(cfg, WithWarnings(&warnings), WithCountryCode("US"), WithRemote(fakeRemote{})); err != nil {
+		t.Fatalf("a catalogURL under the required mirror must be accepted: %v", err)
+	}
+}
+
+func TestNewRejectsBadPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := models.Config{RootDir: t.TempDir(), PolicyFile: filepath.Join(t.TempDir(), "missing.json")}
+	if _, err := New(cfg, WithWarnings(&bytes.Buffer{}), WithCountryCode("")); err == nil {
+		t.Fatal("expected error for missing policy file")
+	}
+}
+
+type countingDetector struct {
+	code  string
+	calls int
+}
+
+func (d *countingDetector) CountryCode(context.Context) (string, error) {
+	d.calls++
+	return d.code, nil
+}
+
+func TestRegionPreferenceSkipsDetection(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		region    string
+		want      region.MirrorConfig
+		wantCalls int
+	}{
+		{region: "cn", want: region.StudyGolangMirror},
+		{region: "global", want: region.GoDevMirror},
+		{region: "go.dev", want: region.GoDevMirror},
+		{region: "DE", want: region.GoDevMirror},
+		{region: "auto", want: region.StudyGolangMirror, wantCalls: 1},
+	}
+	for _, tc := range cases {
+		detector := &countingDetector{code: "CN"}
+		cfg := models.Config{RootDir: t.TempDir(), Region: tc.region}
+		services, err := New(cfg, WithWarnings(&bytes.Buffer{}), WithRegionDetector(detector), WithRemote(fakeRemote{}))
+		if err != nil {
+			t.Fatalf("region %q: %v", tc.region, err)
+		}
+		if services.Mirror != tc.want || detector.calls != tc.wantCalls {
+			t.Fatalf("region %q: mirror %+v after %d detections, want %+v after %d", tc.region, services.Mirror, detector.calls, tc.want, tc.wantCalls)
+		}
+	}
+}
+
+type failingDetector struct{}
+
+func (failingDetector) CountryCode(context.Context) (string, error) {
+	return "", errors.New("region: all providers failed")
+}
+
+type fixedGuess struct{ code, reason string }
+
+func (g fixedGuess) Guess() (string, string) { return g.code, g.reason }
+
+func TestRegionFallsBackToHeuristic(t *testing.T) {
+	t.Parallel()
+
+	var warnings bytes.Buffer
+	services, err := New(models.Config{RootDir: t.TempDir()},
+		WithWarnings(&warnings),
+		WithRegionDetector(failingDetector{}),
+		WithRegionHeuristic(fixedGuess{code: "CN", reason: "TZ=Asia/Shanghai"}),
+		WithRemote(fakeRemote{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services.Mirror != region.StudyGolangMirror {
+		t.Fatalf("Mirror = %+v, want the CN mirror", services.Mirror)
+	}
+	if !strings.Contains(warnings.String(), "guessed CN from TZ=Asia/Shanghai") {
+		t.Fatalf("heuristic not reported: %q", warnings.String())
+	}
+
+	warnings.Reset()
+	services, err = New(models.Config{RootDir: t.TempDir()},
+		WithWarnings(&warnings),
+		WithRegionDetector(failingDetector{}),
+		WithRegionHeuristic(fixedGuess{}),
+		WithRemote(fakeRemote{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services.Mirror != region.GoDevMirror || !strings.Contains(warnings.String(), "fallback to default source") {
+		t.Fatalf("Mirror = %+v, warnings %q", services.Mirror, warnings.String())
+	}
+}
+
+func TestSlowRegionDetectionWarns(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	clock := func() time.Time {
+		now = now.Add(3 * time.Second)
+		return now
+	}
+	var warnings bytes.Buffer
+	services, err := New(models.Config{RootDir: t.TempDir()},
+		WithWarnings(&warnings),
+		WithRegionDetector(&countingDetector{code: "CN"}),
+		WithRemote(fakeRemote{}),
+		WithTimings(timing.NewRecorder(timing.WithClock(clock))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(warnings.String(), "region detection took 3s") || !strings.Contains(warnings.String(), config.EnvRegion) {
+		t.Fatalf("slow detection not reported: %q", warnings.String())
+	}
+	if _, err := services.Lister.RemoteVersions(); err != nil {
+		t.Fatal(err)
+	}
+	phases := services.Timings.Phases()
+	if len(phases) != 2 || phases[0].Name != timing.Region || phases[1].Name != timing.Catalog {
+		t.Fatalf("Phases = %+v", phases)
+	}
+}
+
+func TestMirrorProbeReplacesDownloadBase(t *testing.T) {
+	t.Parallel()
+
+	mirror := func(delay time.Duration) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(http.StatusPartialContent)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	slow, fast := mirror(150*time.Millisecond), mirror(0)
+
+	cfg := models.Config{RootDir: t.TempDir(), Region: "cn", MirrorProbe: true, Mirrors: []string{slow.URL, fast.URL}}
+	services, err := New(cfg, WithWarnings(&bytes.Buffer{}), WithRemote(fakeRemote{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services.Mirror.DownloadBase != fast.URL+"/" || services.Mirror.APIBase != region.StudyGolangMirror.APIBase {
+		t.Fatalf("Mirror = %+v, want downloads from %s", services.Mirror, fast.URL)
+	}
+}
+
+func TestChecksumOptionSkipsOfficialCatalog(t *testing.T) {
+	for _, tt := range []struct {
+		cfg    models.Config
+		mirror region.MirrorConfig
+		want   bool
+	}{
+		{mirror: region.GoDevMirror},
+		{mirror: region.StudyGolangMirror},
+		{mirror: region.MirrorConfig{APIBase: "https://mirror.example.com/dl/?mode=json"}, want: true},
+		{cfg: models.Config{Catalog: "listing"}, mirror: region.StudyGolangMirror, want: true},
+		{cfg: models.Config{ChecksumSource: config.ChecksumOfficial}, mirror: region.GoDevMirror, want: true},
+		{cfg: models.Config{Catalog: "static", ChecksumSource: config.ChecksumMirror}, mirror: region.GoDevMirror},
+	} {
+		s := &Services{Config: tt.cfg, Mirror: tt.mirror, Clients: &httpclient.Clients{}, Store: storage.NewFileStorage(models.Config{RootDir: t.TempDir()})}
+		if got := s.checksumOption() != nil; got != tt.want {
+			t.Errorf("checksumOption(%+v, %s) set = %v, want %v", tt.cfg, tt.mirror.APIBase, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/liangyou/govm/internal/cli"
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/region"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// releaseServer 模拟 go.dev/dl：/dl/?mode=json 返回版本列表，/dl/<文件名> 返回归档。
+type releaseServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	archives  map[string][]byte
+	checksums map[string]string
+	versions  []string
+	downloads map[string]int
+}
+
+func newReleaseServer(t *testing.T, versions ...string) *releaseServer {
+	t.Helper()
+
+	rs := &releaseServer{archives: map[string][]byte{}, checksums: map[string]string{}, downloads: map[string]int{}}
+	for _, v := range versions {
+		name := releaseFileName(v)
+		data := buildGoArchive(t, v)
+		sum := sha256.Sum256(data)
+		rs.archives[name] = data
+		rs.checksums[name] = hex.EncodeToString(sum[:])
+		rs.versions = append(rs.versions, v)
+	}
+	rs.Server = httptest.NewServer(http.HandlerFunc(rs.serve))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func releaseFileName(v string) string {
+	return "go" + v + ".linux-" + runtime.GOARCH + ".tar.gz"
+}
+
+func (rs *releaseServer) serve(w http.ResponseWriter, r *http.Request) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/dl/")
+	if name == "" && r.URL.Query().Get("mode") == "json" {
+		type file struct {
+			Filename string `json:"filename"`
+			OS       string `json:"os"`
+			Arch     string `json:"arch"`
+			Checksum string `json:"sha256"`
+			Size     int    `json:"size"`
+			Kind     string `json:"kind"`
+		}
+		type release struct {
+			Version string `json:"version"`
+			Files   []file `json:"files"`
+		}
+		var feed []release
+		for _, v := range rs.versions {
+			fn := releaseFileName(v)
+			feed = append(feed, release{Version: "go" + v, Files: []file{{
+				Filename: fn, OS: "linux", Arch: runtime.GOARCH,
+				Checksum: rs.checksums[fn], Size: len(rs.archives[fn]), Kind: "archive",
+			}}})
+		}
+		json.NewEncoder(w).Encode(feed)
+		return
+	}
+	data, ok := rs.archives[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rs.downloads[name]++
+	w.Write(data)
+}
+
+// corrupt 替换归档内容但保留列表中的校验和，模拟镜像返回损坏文件。
+func (rs *releaseServer) corrupt(v string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.archives[releaseFileName(v)] = []byte("not a tarball")
+}
+
+func (rs *releaseServer) downloadCount(v string) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.downloads[releaseFileName(v)]
+}
+
+// buildGoArchive 生成与官方归档布局一致的最小 Go 发行包，bin/go 是输出版本号的脚本。
+func buildGoArchive(t *testing.T, v string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	entries := []struct {
+		name, body string
+		dir        bool
+	}{
+		{name: "go/", dir: true},
+		{name: "go/bin/", dir: true},
+		{name: "go/bin/go", body: "#!/bin/sh\necho go version go" + v + " linux/" + runtime.GOARCH + "\n"},
+		{name: "go/bin/gofmt", body: "#!/bin/sh\nexit 0\n"},
+		{name: "go/VERSION", body: "go" + v + "\n"},
+	}
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o755, Typeflag: tar.TypeDir}
+		if !e.dir {
+			hdr = &tar.Header{Name: e.name, Mode: 0o755, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// harness 针对临时根目录与假发布服务器运行真实装配的 govm，每条命令都重新构建服务，与实际进程调用一致。
+type harness struct {
+	t      *testing.T
+	cfg    models.Config
+	server *releaseServer
+	home   string
+}
+
+func newHarness(t *testing.T, versions ...string) *harness {
+	t.Helper()
+	if runtime.GOOS != "linux" || (runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64") {
+		t.Skip("end-to-end tests use linux release archives")
+	}
+
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	if err := os.MkdirAll(home, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".bashrc"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/bash")
+	t.Setenv("GITHUB_PATH", "")
+	t.Setenv("GITHUB_ENV", "")
+	t.Setenv(storage.EnvHome, "")
+	t.Setenv(storage.EnvRoot, "")
+
+	return &harness{
+		t: t,
+		// 假服务器不是官方源，关闭与 go.dev 的交叉校验以免测试访问外网。
+		cfg:    models.Config{RootDir: filepath.Join(dir, "govm"), ChecksumSource: config.ChecksumMirror},
+		server: newReleaseServer(t, versions...),
+		home:   home,
+	}
+}
+
+// run 执行一条 govm 命令，返回标准输出与警告输出。
+func (h *harness) run(args ...string) (stdout, warnings string, err error) {
+	h.t.Helper()
+
+	var out, warn bytes.Buffer
+	services, err := New(h.cfg,
+		WithWarnings(&warn),
+		WithConfigPath(filepath.Join(h.home, ".govm", "config.json")),
+		WithMirror(region.MirrorConfig{
+			APIBase:      h.server.URL + "/dl/?mode=json&include=all",
+			DownloadBase: h.server.URL + "/dl/",
+		}),
+	)
+	if err != nil {
+		return "", warn.String(), err
+	}
+	err = services.NewApp(&out, "test").Run(args)
+	return out.String(), warn.String(), err
+}
+
+// mustRun 执行命令并要求成功。
+func (h *harness) mustRun(args ...string) string {
+	h.t.Helper()
+	out, warn, err := h.run(args...)
+	if err != nil {
+		h.t.Fatalf("govm %s: %v\nstdout:\n%s\nwarnings:\n%s", strings.Join(args, " "), err, out, warn)
+	}
+	return out
+}
+
+func TestEndToEndInstallUseListUninstall(t *testing.T) {
+	h := newHarness(t, "1.22.4", "1.21.9")
+
+	out := h.mustRun("install", "1.22.4")
+	if !strings.Contains(out, "Installed go1.22.4") {
+		t.Fatalf("install output = %q", out)
+	}
+	h.mustRun("install", "1.21.9")
+	if n := h.server.downloadCount("1.22.4"); n != 1 {
+		t.Fatalf("go1.22.4 downloaded %d times, want 1", n)
+	}
+
+	if out := h.mustRun("use", "1.22.4"); !strings.Contains(out, "Now using go1.22.4") {
+		t.Fatalf("use output = %q", out)
+	}
+	h.mustRun("use", "1.21.9")
+	if out := h.mustRun("use", "-"); !strings.Contains(out, "Now using go1.22.4") {
+		t.Fatalf("use - output = %q", out)
+	}
+	var history []models.HistoryEntry
+	if err := json.Unmarshal([]byte(h.mustRun("history", "--json")), &history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[2].Command != "govm use -" || history[2].Version != "1.22.4" || history[2].Previous != "1.21.9" {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	if out := h.mustRun("current", "--quiet"); out != "1.22.4\n" {
+		t.Fatalf("current --quiet = %q", out)
+	}
+	goroot := strings.TrimSpace(h.mustRun("current", "--path"))
+	if out := h.mustRun("which"); out != filepath.Join(goroot, "bin", "go")+"\n" {
+		t.Fatalf("which = %q, want go under %s", out, goroot)
+	}
+	rc, err := os.ReadFile(filepath.Join(h.home, ".bashrc"))
+	if err != nil || !strings.Contains(string(rc), goroot) {
+		t.Fatalf("shell config does not reference %s: %v\n%s", goroot, err, rc)
+	}
+
+	list := h.mustRun("-list")
+	for _, v := range []string{"1.22.4", "1.21.9"} {
+		if !strings.Contains(list, v) {
+			t.Fatalf("list missing %s:\n%s", v, list)
+		}
+	}
+
+	if out := h.mustRun("uninstall", "1.21.9"); !strings.Contains(out, "Uninstalled go1.21.9") {
+		t.Fatalf("uninstall output = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(goroot), "go1.21.9")); !os.IsNotExist(err) {
+		t.Fatalf("go1.21.9 install dir still present: %v", err)
+	}
+	h.mustRun("uninstall", "1.22.4", "--force")
+
+	_, _, err = h.run("current", "--quiet")
+	var exitErr *cli.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Fatalf("current --quiet after uninstall = %v, want exit status 1", err)
+	}
+}
+
+func TestEndToEndCorruptDownloadLeavesNoTrace(t *testing.T) {
+	h := newHarness(t, "1.22.4")
+	h.server.corrupt("1.22.4")
+
+	if _, _, err := h.run("install", "1.22.4"); err == nil {
+		t.Fatal("expected install to fail on checksum mismatch")
+	}
+	if out := h.mustRun("-list"); strings.Contains(out, "1.22.4") {
+		t.Fatalf("failed install is listed:\n%s", out)
+	}
+	entries, err := os.ReadDir(filepath.Join(h.cfg.RootDir, "versions"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("unexpected leftover in versions dir: %s", e.Name())
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// AdoptService 描述发现并登记 govm 之外已有 Go 安装的能力。
+type AdoptService interface {
+	Detect() ([]models.Version, error)
+	Adopt(goRoot string) (models.Version, error)
+}
+
+// ToolchainDetector 为可选能力：发现 go 命令按 GOTOOLCHAIN 下载到模块缓存中的工具链。
+type ToolchainDetector interface {
+	DetectToolchains(modCache string) ([]models.Version, error)
+}
+
+// WithAdopter 启用 `govm adopt` 以及首次运行时的已有安装提示。
+func WithAdopter(s AdoptService) AppOption {
+	return func(a *App) {
+		a.adopter = s
+	}
+}
+
+func (a *App) handleAdopt(args []string) error {
+	if a.adopter == nil {
+		return errors.New("adopt command is unavailable")
+	}
+	fs := newCommandFlagSet("adopt")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var roots []string
+	if len(rest) > 0 {
+		roots = rest
+	} else {
+		found, err := a.detectAdoptable()
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			a.println("No existing Go installations found outside govm.")
+			return nil
+		}
+		for _, v := range found {
+			roots = append(roots, v.InstallPath)
+		}
+	}
+
+	modCache := a.resolveModCache()
+	return a.withLock(func() error {
+		for _, root := range roots {
+			v, err := a.adopter.Adopt(root)
+			if err != nil {
+				return err
+			}
+			a.printf("Adopted go%s at %s as an external version (uninstall only unregisters it)\n", v.Number, v.InstallPath)
+			if inModCache(v.InstallPath, modCache) {
+				a.printf("go%s stays in the module cache and is linked, not copied; `go clean -modcache` would delete it\n", v.Number)
+			}
+		}
+		a.println("Run `govm use <version>` to activate one.")
+		return nil
+	})
+}
+
+// hintAdoptable 在尚未安装任何版本时提示可登记的已有 Go 安装。
+func (a *App) hintAdoptable() {
+	if a.adopter == nil || a.porcelain {
+		return
+	}
+	found, err := a.detectAdoptable()
+	if err != nil || len(found) == 0 {
+		return
+	}
+	names := make([]string, 0, len(found))
+	for _, v := range found {
+		names = append(names, fmt.Sprintf("go%s (%s)", v.Number, v.InstallPath))
+	}
+	a.printf("Found existing Go installation(s): %s. Run `govm adopt` to manage them with govm.\n", strings.Join(names, ", "))
+}
+
+// detectAdoptable 汇总 PATH、常见位置与 GOMODCACHE 中可登记的安装；同一版本优先采用模块缓存之外的安装。
+func (a *App) detectAdoptable() ([]models.Version, error) {
+	found, err := a.adopter.Detect()
+	if err != nil {
+		return nil, err
+	}
+	detector, ok := a.adopter.(ToolchainDetector)
+	if !ok {
+		return found, nil
+	}
+	toolchains, err := detector.DetectToolchains(a.resolveModCache())
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, v := range found {
+		seen[v.Number] = true
+	}
+	for _, v := range toolchains {
+		if !seen[v.Number] {
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+// inModCache 判断 path 是否位于模块缓存 modCache 之下；登记的路径已解析符号链接，modCache 也按解析后的路径比较。
+func inModCache(path, modCache string) bool {
+	if modCache == "" || path == "" {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(modCache); err == nil {
+		modCache = resolved
+	}
+	rel, err := filepath.Rel(modCache, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -1,15 +1,23 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/internal/i18n"
+	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/goversion"
 	"github.com/liangyou/govm/pkg/models"
 )
 
@@ -20,6 +28,11 @@ type ListService interface {
 	CurrentVersion() (*models.Version, error)
 }
 
+// AggregateLister 是 ListService 的可选能力：并发获取远程与本地版本，见 version.Lister.ListAll。
+type AggregateLister interface {
+	ListAll(ctx context.Context) (version.Listing, error)
+}
+
 // InstallService 描述安装能力。
 type InstallService interface {
 	Install(models.Version) error
@@ -30,13 +43,36 @@ type SwitchService interface {
 	UseVersion(string) error
 }
 
+// ActivateService 描述不修改 shell 配置文件的版本激活能力，SwitchService 的实现可选地提供。
+type ActivateService interface {
+	Activate(string) error
+}
+
 // UninstallService 描述卸载能力。
 type UninstallService interface {
 	Uninstall(version string, force bool) ([]models.Version, error)
 }
 
+// PolicyService 描述团队策略校验能力。
+type PolicyService interface {
+	CheckVersion(version string) error
+	WarnOnly() bool
+}
+
+// PermissionChecker 描述安装目录写权限校验能力，共享安装模式下用于提示 sudo。
+type PermissionChecker interface {
+	CheckWritable() error
+}
+
+// Locker 描述跨进程状态锁，安装与卸载期间持有，避免与守护进程或其他 govm 进程并发修改。
+type Locker interface {
+	Lock(blocking bool) (func() error, error)
+}
+
 const (
 	colorReset       = "\033[0m"
+	colorRed         = "\033[31m"
+	colorGreen       = "\033[32m"
 	colorBoldGreen   = "\033[1;32m"
 	colorCyan        = "\033[36m"
 	colorYellow      = "\033[33m"
@@ -45,27 +81,142 @@ const (
 
 // App 负责 CLI 命令解析与分发。
 type App struct {
-	out         io.Writer
-	version     string
-	lister      ListService
-	installer   InstallService
-	switcher    SwitchService
-	uninstaller UninstallService
+	out            io.Writer
+	warn           io.Writer
+	version        string
+	lister         ListService
+	installer      InstallService
+	switcher       SwitchService
+	uninstaller    UninstallService
+	events         *events.Bus
+	policy         PolicyService
+	permissions    PermissionChecker
+	locker         Locker
+	stats          StatsService
+	verifier       VerifyService
+	dedup          DedupService
+	manifests      ManifestService
+	releaseNotes   ReleaseNotesSource
+	network        NetworkConfigurer
+	resolver       VersionResolver
+	backup         BackupService
+	configPath     string
+	layout         LayoutMigrator
+	shims          ShimService
+	adopter        AdoptService
+	shell          ShellDetector
+	implode        ImplodeService
+	rcCleaner      ShellCleaner
+	caches         CacheService
+	recent         RecentVersionService
+	history        HistoryService
+	locks          VersionLockService
+	prompter       Prompter
+	markers        MarkerService
+	signature      SignatureService
+	metadataRepair MetadataRepairService
+	shellReview    ShellReviewService
+	ide            IDESetupService
+	timings        TimingSource
+	watcher        WatchService
+	bundler        BundleService
+	provisioner    ProvisionService
+	msg            *i18n.Printer
+
+	porcelain bool
+	wide      bool
+	assumeYes bool
+	format    string   // --format 模板，为空时输出表格
+	channels  []string // -channel 指定的发布渠道，为空时 -list/-remote 列出全部版本
+	// changedExit 非 0 时 install/use 改变了状态以该码退出，见 reportChange。
+	changedExit int
+
+	getenv func(string) string
+	runGo  func(goBin string, stdout io.Writer, args ...string) error
+	goPath string
+	// goToolchain 为配置中的 goToolchain，非空时 govm env 与 CI 环境文件一并导出 GOTOOLCHAIN。
+	goToolchain string
+	// shellRC 为配置中的 shellRC，非空时安装提示 source 该文件而不是 .bashrc/.zshrc。
+	shellRC string
+
+	plugins  bool
+	rootDir  string
+	packaged bool
+
+	mirror       string
+	catalogCache CatalogCache
+	runCache     string
+}
+
+// AppOption 配置 App 的可选依赖。
+type AppOption func(*App)
+
+// WithEventBus 指定安装流程使用的事件总线，-porcelain 模式会订阅它输出机器可读事件。
+func WithEventBus(bus *events.Bus) AppOption {
+	return func(a *App) {
+		a.events = bus
+	}
+}
+
+// WithPackaged 表示 govm 由发行版打包安装，二进制由包管理器负责，输出中相应提示使用包管理器。
+func WithPackaged() AppOption {
+	return func(a *App) {
+		a.packaged = true
+	}
+}
+
+// WithWarnings 指定策略警告等非结果输出的去向，默认写到 stderr，使 `install --silent` 的 stdout 只有 GOROOT。
+func WithWarnings(w io.Writer) AppOption {
+	return func(a *App) {
+		if w != nil {
+			a.warn = w
+		}
+	}
+}
+
+// WithPolicy 指定 install/use 前执行的团队策略校验。
+func WithPolicy(p PolicyService) AppOption {
+	return func(a *App) {
+		a.policy = p
+	}
+}
+
+// WithPermissionChecker 指定 install/uninstall 前执行的写权限校验。
+func WithPermissionChecker(c PermissionChecker) AppOption {
+	return func(a *App) {
+		a.permissions = c
+	}
+}
+
+// WithLocker 指定安装与卸载期间使用的跨进程锁。
+func WithLocker(l Locker) AppOption {
+	return func(a *App) {
+		a.locker = l
+	}
 }
 
 // NewApp 创建 CLI 应用实例。
-func NewApp(out io.Writer, lister ListService, installer InstallService, switcher SwitchService, uninstaller UninstallService, version string) *App {
+func NewApp(out io.Writer, lister ListService, installer InstallService, switcher SwitchService, uninstaller UninstallService, version string, opts ...AppOption) *App {
 	if out == nil {
 		out = os.Stdout
 	}
-	return &App{
+	a := &App{
 		out:         out,
+		warn:        os.Stderr,
 		version:     version,
 		lister:      lister,
 		installer:   installer,
 		switcher:    switcher,
 		uninstaller: uninstaller,
+		getenv:      os.Getenv,
+		runGo:       runGoCommand,
+		prompter:    newLinePrompter(os.Stdin, out),
+		msg:         i18n.New(i18n.English),
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	return a
 }
 
 // Run 解析参数并执行命令。
@@ -79,24 +230,65 @@ func (a *App) Run(args []string) error {
 	versionFlg := fs.Bool("version", false, "show version")
 	uninstallFlg := fs.String("uninstall", "", "uninstall specified version")
 	forceFlg := fs.Bool("force", false, "force uninstall when used with -uninstall")
+	porcelainFlg := fs.Bool("porcelain", false, "print machine-readable key=value events")
+	yesFlg := fs.Bool("yes", false, "answer yes to every confirmation prompt")
+	timeouts := registerTimeoutFlags(fs)
+	limitFlg := fs.Int("limit", 0, "show at most N remote versions per page (0 shows all)")
+	pageFlg := fs.Int("page", 1, "page number used with -limit")
+	wideFlg := fs.Bool("wide", false, "show extra columns in version tables")
+	flatFlg := fs.Bool("flat", false, "list every remote archive instead of grouping by series")
+	formatFlg := fs.String("format", "", "print each version of -list/-remote/current with a Go text/template")
+	channelFlg := fs.String("channel", "", "show only versions of these comma-separated channels in -list/-remote (stable, rc, beta, archived)")
+	changedFlg := fs.Int(changedExitFlag, 0, "exit with this code instead of 0 when install/use changed state")
+	verboseFlg := fs.Bool("verbose", false, "print how long region detection, the release list, downloads and extraction took")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	a.applyTimeoutFlags(timeouts)
+
+	a.porcelain = *porcelainFlg
+	a.wide = *wideFlg
+	a.assumeYes = *yesFlg
+	a.format = *formatFlg
+	channels, err := parseChannels(*channelFlg)
+	if err != nil {
+		return err
+	}
+	a.channels = channels
+	if err := validateChangedExit(*changedFlg); err != nil {
+		return err
+	}
+	a.changedExit = *changedFlg
+	if a.porcelain {
+		unsubscribe := a.events.Subscribe(func(e events.Event) {
+			fmt.Fprintln(a.out, events.FormatPorcelain(e))
+		})
+		defer unsubscribe()
+	}
+	if *verboseFlg {
+		defer a.printTimings()
+	}
+
+	defer a.trackActivation(args, fs.Args(), *uninstallFlg != "")()
 
 	switch {
 	case *helpFlg:
 		a.printHelp()
 		return nil
 	case *versionFlg:
-		fmt.Fprintf(a.out, "govm version %s\n", a.version)
+		if a.packaged {
+			a.printf("govm version %s (packaged)\n", a.version)
+		} else {
+			a.printf("govm version %s\n", a.version)
+		}
 		return nil
 	case *remoteFlg:
-		return a.handleRemote()
+		return a.handleRemote(*pageFlg, *limitFlg, *flatFlg)
 	case *listFlg:
 		return a.handleList()
 	case *uninstallFlg != "":
-		return a.handleUninstall(*uninstallFlg, *forceFlg)
+		return a.handleUninstall(*uninstallFlg, *forceFlg, false)
 	}
 
 	rest := fs.Args()
@@ -104,50 +296,170 @@ func (a *App) Run(args []string) error {
 		a.printHelp()
 		return nil
 	}
+	if doc := findCommandDoc(rest[0]); doc != nil && wantsHelp(helpScope(rest)) {
+		a.printCommandHelp(*doc)
+		return nil
+	}
 
 	switch rest[0] {
 	case "install":
-		if len(rest) < 2 {
-			return errors.New("install command requires a version")
-		}
-		return a.handleInstall(rest[1])
+		return a.reportChange(func() error { return a.handleInstallCommand(rest[1:]) })
 	case "use":
 		if len(rest) < 2 {
 			return errors.New("use command requires a version")
 		}
-		return a.handleUse(rest[1])
+		return a.reportChange(func() error { return a.handleUseCommand(rest[1:]) })
+	case "sync":
+		return a.reportChange(func() error { return a.handleSync(rest[1:]) })
 	case "current":
-		return a.handleCurrent()
+		return a.handleCurrent(rest[1:])
+	case "prompt":
+		return a.handlePrompt(rest[1:])
+	case "assert":
+		return a.handleAssert(rest[1:])
+	case "status":
+		return a.handleStatus(rest[1:])
+	case "clean":
+		return a.handleClean(rest[1:])
+	case "which":
+		return a.handleWhich(rest[1:])
+	case "run":
+		return a.handleRun(rest[1:])
+	case "bench":
+		return a.handleBench(rest[1:])
+	case "direnv":
+		return a.handleDirenv(rest[1:])
+	case "ide-setup":
+		return a.handleIDESetup(rest[1:])
+	case "ide":
+		return a.handleIDE(rest[1:])
+	case "env":
+		return a.handleEnv(rest[1:])
 	case "uninstall":
-		if len(rest) < 2 {
-			return errors.New("uninstall command requires a version")
-		}
-		force := len(rest) > 2 && rest[2] == "--force"
-		return a.handleUninstall(rest[1], force)
+		return a.handleUninstallCommand(rest[1:])
+	case "export":
+		return a.handleExport(rest[1:])
+	case "import":
+		return a.handleImport(rest[1:])
+	case "serve":
+		return a.handleServe(rest[1:])
+	case "stats":
+		return a.handleStats()
+	case "verify":
+		return a.handleVerify(rest[1:])
+	case "dedup":
+		return a.handleDedup(rest[1:])
+	case "doctor":
+		return a.handleDoctor(rest[1:])
+	case "diff":
+		return a.handleDiff(rest[1:])
+	case "backup":
+		return a.handleBackup(rest[1:])
+	case "restore":
+		return a.handleRestore(rest[1:])
+	case "bundle":
+		return a.handleBundle(rest[1:])
+	case "provision":
+		return a.handleProvision(rest[1:])
+	case "migrate-layout":
+		return a.handleMigrateLayout(rest[1:])
+	case "rehash":
+		return a.handleRehash()
+	case "adopt":
+		return a.handleAdopt(rest[1:])
+	case "init":
+		return a.handleInit(rest[1:])
+	case "implode":
+		return a.handleImplode(rest[1:])
+	case "cache":
+		return a.handleCache(rest[1:])
+	case "history":
+		return a.handleHistory(rest[1:])
+	case "lock":
+		return a.handleLock(rest[1:], true)
+	case "unlock":
+		return a.handleLock(rest[1:], false)
+	case "plugins":
+		return a.handlePlugins(rest[1:])
+	case "watch":
+		return a.handleWatch(rest[1:])
+	case "help":
+		return a.handleHelp(rest[1:])
+	case "gen-docs":
+		return a.handleGenDocs(rest[1:])
 	default:
-		return fmt.Errorf("unknown command: %s", rest[0])
+		return a.handleUnknown(rest[0], rest[1:])
 	}
 }
 
-func (a *App) handleRemote() error {
+func (a *App) handleRemote(page, limit int, flat bool) error {
 	if a.lister == nil {
 		return errors.New("remote listing is unavailable")
 	}
-	versions, err := a.lister.RemoteVersions()
+	versions, err := a.remoteVersions()
 	if err != nil {
 		return err
 	}
+	versions = filterChannels(versions, a.channels)
+	if a.format != "" {
+		rows, _ := version.Paginate(versions, page, limit)
+		return a.renderFormat(a.format, rows)
+	}
 	if len(versions) == 0 {
-		fmt.Fprintln(a.out, "No remote versions available.")
+		a.println("No remote versions available.")
 		return nil
 	}
-	fmt.Fprintln(a.out, "Remote versions:")
-	for _, v := range versions {
-		fmt.Fprintf(a.out, "  %s\n", version.FormatRemoteVersion(v))
+
+	var (
+		tbl          *table
+		shown, total int
+	)
+	if flat {
+		var rows []models.Version
+		rows, total = version.Paginate(versions, page, limit)
+		shown = len(rows)
+		tbl = remoteTable(rows, a.wide)
+	} else {
+		var groups []version.Series
+		groups, total = version.Paginate(version.GroupBySeries(versions), page, limit)
+		shown = len(groups)
+		tbl = seriesTable(groups, a.wide)
+	}
+	if shown == 0 {
+		return fmt.Errorf("page %d is out of range (%d entries)", page, total)
+	}
+	if err := tbl.render(a.out); err != nil {
+		return err
+	}
+	if shown < total {
+		page = max(page, 1)
+		start := (page - 1) * limit
+		end := start + shown
+		if end < total {
+			a.printf("Showing %d-%d of %d, use -page %d for more\n", start+1, end, total, page+1)
+		} else {
+			a.printf("Showing %d-%d of %d\n", start+1, end, total)
+		}
 	}
 	return nil
 }
 
+// remoteVersions 返回远程版本；lister 支持 ListAll 时同时读取本地版本，已安装的条目带有 InstallPath 与 IsCurrent。
+// 读取本地版本失败只会让列表少了安装标记；Ctrl-C 立即结束等待。
+func (a *App) remoteVersions() ([]models.Version, error) {
+	agg, ok := a.lister.(AggregateLister)
+	if !ok {
+		return a.lister.RemoteVersions()
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	listing, err := agg.ListAll(ctx)
+	if listing.Remote == nil && err != nil {
+		return nil, err
+	}
+	return listing.Remote, nil
+}
+
 func (a *App) handleList() error {
 	if a.lister == nil {
 		return errors.New("local listing is unavailable")
@@ -156,103 +468,370 @@ func (a *App) handleList() error {
 	if err != nil {
 		return err
 	}
-	if len(versions) == 0 {
-		fmt.Fprintln(a.out, "No versions installed.")
+	versions = filterChannels(versions, a.channels)
+	if a.format != "" {
+		return a.renderFormat(a.format, versions)
+	}
+	a.warnDamage()
+	a.warnMarker()
+	a.warnSignature()
+	if len(versions) == 0 && len(a.channels) > 0 {
+		a.printf("No installed versions in channel %s.\n", strings.Join(a.channels, ", "))
 		return nil
 	}
-	fmt.Fprintln(a.out, "Installed versions:")
-	for _, v := range versions {
-		fmt.Fprintf(a.out, "  %s\n", version.FormatLocalVersion(v))
+	if len(versions) == 0 {
+		a.println("No versions installed.")
+		a.hintAdoptable()
+		return nil
 	}
-	return nil
+	return localTable(versions, a.wide, a.loadUsage()).render(a.out)
 }
 
-func (a *App) handleCurrent() error {
-	if a.lister == nil {
-		return errors.New("current version query is unavailable")
-	}
-	current, err := a.lister.CurrentVersion()
+// installOptions 汇总 install 子命令的 flag。
+type installOptions struct {
+	silent     bool
+	globalPath bool
+	noCatalog  bool
+	sha256     string // 安装归档文件名或 URL 时使用的校验值
+	// skipExisting 与 failFast 仅作用于一次安装多个版本的批量模式。
+	skipExisting bool
+	failFast     bool
+}
+
+func (a *App) handleInstallCommand(args []string) error {
+	fs := newCommandFlagSet("install")
+	silent := fs.Bool("silent", false, "print only the GOROOT path")
+	globalPath := fs.Bool("global-path", false, "activate without editing rc files and export to GITHUB_PATH/GITHUB_ENV")
+	downloadTimeout := fs.Duration("download-timeout", 0, "total timeout for downloading an archive")
+	noCatalog := fs.Bool("no-catalog", false, "derive the download URL and fetch the .sha256 file without the version catalog")
+	jobs := fs.Int("jobs", defaultInstallJobs, "number of concurrent downloads when installing several versions")
+	file := fs.String("f", "", "install every version listed in a file (one per line, YAML or JSON manifest)")
+	skipExisting := fs.Bool("skip-existing", false, "skip versions that are already installed instead of failing")
+	failFast := fs.Bool("fail-fast", false, "stop starting new installs after the first failure")
+	sha256 := fs.String("sha256", "", "expected SHA256 when installing from an archive name or URL")
+	rest, err := parseCommandFlags(fs, args)
 	if err != nil {
 		return err
 	}
-	if current == nil {
-		fmt.Fprintln(a.out, "No active Go version.")
-		return nil
+	if *file != "" {
+		listed, err := readInstallList(*file)
+		if err != nil {
+			return err
+		}
+		rest = append(rest, listed...)
 	}
-	fmt.Fprintf(a.out, "Current version: %s\n", version.FormatLocalVersion(*current))
-	return nil
+	if len(rest) == 0 {
+		return errors.New("install command requires a version")
+	}
+	a.setDownloadTimeout(*downloadTimeout)
+	opts := installOptions{silent: *silent, globalPath: *globalPath, noCatalog: *noCatalog, sha256: strings.TrimSpace(*sha256), skipExisting: *skipExisting, failFast: *failFast}
+	if len(rest) > 1 || *file != "" {
+		if opts.sha256 != "" {
+			return errors.New("--sha256 accepts a single archive")
+		}
+		return a.handleInstallMany(rest, opts, *jobs)
+	}
+	return a.handleInstall(rest[0], opts)
 }
 
-func (a *App) handleInstall(input string) error {
+func (a *App) handleInstall(input string, opts installOptions) error {
 	if a.installer == nil || a.lister == nil {
 		return errors.New("install command is unavailable")
 	}
-	normalized := normalizeVersion(input)
-	versions, err := a.lister.RemoteVersions()
+	var (
+		target *models.Version
+		err    error
+	)
+	switch {
+	case isArtifactRef(input):
+		target, err = a.resolveArtifact(input, opts.sha256)
+	case opts.sha256 != "":
+		err = errors.New("--sha256 requires an archive name or URL; versions from the release list are verified automatically")
+	default:
+		var number string
+		if number, err = a.installNumber(input, opts.noCatalog); err == nil {
+			target, err = a.resolveInstallTarget(number, resolveOptions{quiet: opts.silent || a.porcelain, noCatalog: opts.noCatalog})
+		}
+	}
 	if err != nil {
 		return err
 	}
-	target, err := findVersion(versions, normalized)
-	if err != nil {
+	if err := a.checkPolicy(target.Number); err != nil {
 		return err
 	}
-	if err := a.installer.Install(*target); err != nil {
+	if err := a.checkWritable(); err != nil {
 		return err
 	}
-	fmt.Fprintf(a.out, "Installed %s\n", target.FullName)
+	if err := a.withLock(func() error { return a.installer.Install(*target) }); err != nil {
+		return err
+	}
+	if opts.silent || opts.globalPath {
+		return a.finishCIInstall(target.Number, opts)
+	}
+	if a.porcelain {
+		return nil
+	}
+	a.printf("Installed %s\n", target.FullName)
 	a.printInstallSummary(target.Number)
 	return nil
 }
 
-func (a *App) handleUse(ver string) error {
+// finishCIInstall 处理 --silent/--global-path：只输出 GOROOT，必要时激活版本并写入 CI 环境文件。
+func (a *App) finishCIInstall(ver string, opts installOptions) error {
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	goroot := findInstallPath(versions, ver)
+	if goroot == "" {
+		return fmt.Errorf("install path for go%s is unknown", ver)
+	}
+
+	if opts.globalPath {
+		activator, ok := a.switcher.(ActivateService)
+		if !ok {
+			return errors.New("--global-path is unavailable")
+		}
+		if err := activator.Activate(ver); err != nil {
+			return err
+		}
+		if _, err := env.ExportCI(goroot, a.goToolchain, os.Getenv); err != nil {
+			return err
+		}
+	}
+
+	if opts.silent || !a.porcelain {
+		fmt.Fprintln(a.out, goroot)
+	}
+	return nil
+}
+
+// handleUseCommand 解析 use 的参数；--strict 时拒绝切换到不满足当前模块 go/toolchain 要求的版本，
+// --local 时只把版本固定到当前目录；--verbose 与 --confirm 在改写 rc 文件前输出差异，后者还要求确认。
+func (a *App) handleUseCommand(args []string) error {
+	fs := newCommandFlagSet("use")
+	strict := fs.Bool("strict", false, "refuse versions older than the go/toolchain directive of the current module")
+	local := fs.Bool("local", false, "pin the version in .govm-version in the current directory instead of switching globally")
+	verbose := fs.Bool("verbose", false, "show a diff of the shell config change")
+	confirm := fs.Bool("confirm", false, "show a diff of the shell config change and ask before writing it")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return errors.New("use command requires a version")
+	}
+	if *local {
+		return a.handleUseLocal(rest[0], *strict)
+	}
+	if *verbose || *confirm {
+		defer a.reviewShellChanges(*confirm)()
+	}
+	return a.handleUse(rest[0], *strict)
+}
+
+func (a *App) handleUse(ver string, strict bool) error {
 	if a.switcher == nil {
 		return errors.New("use command is unavailable")
 	}
 	normalized := normalizeVersion(ver)
+	switch {
+	case ver == "-":
+		previous, err := a.previousVersion()
+		if err != nil {
+			return err
+		}
+		normalized = previous
+	case goversion.IsConstraint(normalized) && a.lister != nil:
+		resolved, err := a.resolveRange(normalized, true)
+		if err != nil {
+			return err
+		}
+		normalized = resolved
+	}
+	if err := a.checkPolicy(normalized); err != nil {
+		return err
+	}
+	if strict {
+		if err := a.checkModule(normalized, true); err != nil {
+			return err
+		}
+	}
+	defer a.reportShellCleanup()()
+	defer a.resolveShellConflicts()()
 	if err := a.switcher.UseVersion(normalized); err != nil {
+		if errors.Is(err, env.ErrShellConfigDeclined) {
+			return fmt.Errorf("use: shell config left unchanged, go%s is not activated", normalized)
+		}
+		if errors.Is(err, env.ErrShellConfigConflict) {
+			return fmt.Errorf("use: conflicting GOROOT/GOPATH exports left in place, go%s is not activated; comment them out or rerun with -yes", normalized)
+		}
+		return a.withLocalSuggestion(err, normalized)
+	}
+	a.printf("Now using go%s\n", normalized)
+	return a.checkModule(normalized, false)
+}
+
+// RecentVersionService 描述读取最近激活过的版本的能力，最近的在前。
+type RecentVersionService interface {
+	RecentVersions() ([]string, error)
+}
+
+// WithRecentVersions 启用 `govm use -`。
+func WithRecentVersions(s RecentVersionService) AppOption {
+	return func(a *App) {
+		a.recent = s
+	}
+}
+
+// previousVersion 返回最近一个仍已安装、且不是当前版本的历史版本。
+func (a *App) previousVersion() (string, error) {
+	if a.recent == nil || a.lister == nil {
+		return "", errors.New("use -: version history is unavailable")
+	}
+	recent, err := a.recent.RecentVersions()
+	if err != nil {
+		return "", fmt.Errorf("use -: %w", err)
+	}
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return "", err
+	}
+	installed := map[string]bool{}
+	current := ""
+	for _, v := range local {
+		installed[v.Number] = v.InstallPath != ""
+		if v.IsCurrent {
+			current = v.Number
+		}
+	}
+	for _, v := range recent {
+		if installed[v] && v != current {
+			return v, nil
+		}
+	}
+	return "", errors.New("use -: no previous version to switch back to")
+}
+
+func (a *App) handleUninstallCommand(args []string) error {
+	fs := newCommandFlagSet("uninstall")
+	force := fs.Bool("force", false, "remove the version even if it is active or locked, without asking")
+	yes := fs.Bool("yes", false, "confirm removing the active version without prompting")
+	purge := fs.Bool("purge-caches", false, "also remove GOTOOLCHAIN module downloads of this version from GOMODCACHE")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("uninstall command requires a version")
+	}
+	if err := a.handleUninstall(rest[0], *force, *yes); err != nil {
+		return err
+	}
+	if *purge {
+		return a.purgeToolchainCaches(normalizeVersion(rest[0]))
+	}
+	return nil
+}
+
+// purgeToolchainCaches 删除 GOMODCACHE 中属于该版本的 golang.org/toolchain 模块并报告释放的空间。
+// GOCACHE 中的构建结果为各版本共享，无法按版本区分，只提示用户自行清理。
+func (a *App) purgeToolchainCaches(number string) error {
+	modCache := a.resolveModCache()
+	removed, freed, err := version.PurgeToolchainModules(modCache, number)
+	for _, path := range removed {
+		a.printf("Removed %s\n", path)
+	}
+	if err != nil {
 		return err
 	}
-	fmt.Fprintf(a.out, "Now using go%s\n", normalized)
+	if len(removed) == 0 {
+		a.printf("No toolchain modules for go%s in %s\n", number, modCache)
+	} else {
+		a.printf("Freed %s of module cache for go%s\n", formatSize(freed), number)
+	}
+	a.println("GOCACHE is shared by all versions; run `go clean -cache` to reclaim build cache space.")
 	return nil
 }
 
-func (a *App) handleUninstall(ver string, force bool) error {
+func (a *App) handleUninstall(ver string, force, yes bool) error {
 	if a.uninstaller == nil || a.lister == nil {
 		return errors.New("uninstall command is unavailable")
 	}
 	normalized := normalizeVersion(ver)
-	if _, err := a.uninstaller.Uninstall(normalized, force); err != nil {
+	if err := a.checkWritable(); err != nil {
 		return err
 	}
-	fmt.Fprintf(a.out, "Uninstalled go%s\n", normalized)
+	if !force {
+		if err := a.checkUnlocked(normalized); err != nil {
+			return err
+		}
+		if current, err := a.lister.CurrentVersion(); err == nil && current != nil && current.Number == normalized {
+			if !a.confirm(a.tr("go%s is active — uninstall anyway?", normalized), yes) {
+				return fmt.Errorf("uninstall: go%s is active; aborted (pass --yes to confirm without prompting)", normalized)
+			}
+			force = true
+		}
+	}
+	if err := a.withLock(func() error {
+		_, err := a.uninstaller.Uninstall(normalized, force)
+		return err
+	}); err != nil {
+		return a.withLocalSuggestion(err, normalized)
+	}
+	a.printf("Uninstalled go%s\n", normalized)
 	versions, err := a.lister.LocalVersions()
 	if err != nil {
 		return err
 	}
-	fmt.Fprintln(a.out, "Remaining versions:")
 	if len(versions) == 0 {
-		fmt.Fprintln(a.out, "  (none)")
+		a.println("No versions remain installed.")
 		return nil
 	}
-	for _, v := range versions {
-		fmt.Fprintf(a.out, "  %s\n", version.FormatLocalVersion(v))
+	a.println("Remaining versions:")
+	return localTable(versions, a.wide, nil).render(a.out)
+}
+
+// checkPolicy 执行策略校验，warn 模式下仅输出警告。
+func (a *App) checkPolicy(ver string) error {
+	if a.policy == nil {
+		return nil
 	}
-	return nil
+	err := a.policy.CheckVersion(ver)
+	if err == nil {
+		return nil
+	}
+	if a.policy.WarnOnly() {
+		fmt.Fprintf(a.warn, "%s %v\n", colorize("warning:", colorYellow), err)
+		return nil
+	}
+	return err
 }
 
-func (a *App) printHelp() {
-	fmt.Fprintln(a.out, `govm - Go version manager
+func (a *App) checkWritable() error {
+	if a.permissions == nil {
+		return nil
+	}
+	return a.permissions.CheckWritable()
+}
 
-Commands:
-  govm -remote              List remote versions
-  govm -list                List installed versions
-  govm install <version>    Install a specific version
-  govm use <version>        Switch to an installed version
-  govm current              Show the active version
-  govm uninstall <version> [--force]  Remove an installed version
-  govm -uninstall <version> [-force]  Remove an installed version via flag
-  govm -help                Show this message
-  govm -version             Show govm version`)
+// withLock 在持有跨进程锁的情况下执行 fn，锁被占用时提示并等待。
+func (a *App) withLock(fn func() error) error {
+	if a.locker == nil {
+		return fn()
+	}
+	unlock, err := a.locker.Lock(false)
+	if errors.Is(err, storage.ErrLocked) {
+		if !a.porcelain {
+			a.println("Waiting for another govm process to finish...")
+		}
+		unlock, err = a.locker.Lock(true)
+	}
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
 }
 
 func normalizeVersion(input string) string {
@@ -261,13 +840,79 @@ func normalizeVersion(input string) string {
 	return cleaned
 }
 
+// parseChannels 解析 -channel 的逗号分隔列表。
+func parseChannels(value string) ([]string, error) {
+	var channels []string
+	for _, c := range strings.Split(value, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		switch c {
+		case "":
+		case models.ChannelStable, models.ChannelRC, models.ChannelBeta, models.ChannelArchived:
+			channels = append(channels, c)
+		default:
+			return nil, fmt.Errorf("invalid -channel %q: want stable, rc, beta or archived", c)
+		}
+	}
+	return channels, nil
+}
+
+// filterChannels 只保留属于 channels 的版本，channels 为空时原样返回。
+func filterChannels(versions []models.Version, channels []string) []models.Version {
+	if len(channels) == 0 {
+		return versions
+	}
+	var kept []models.Version
+	for _, v := range versions {
+		if slices.Contains(channels, version.ChannelOf(v)) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
 func findVersion(versions []models.Version, number string) (*models.Version, error) {
 	for i := range versions {
 		if versions[i].Number == number {
 			return &versions[i], nil
 		}
 	}
-	return nil, fmt.Errorf("version %s not found in remote list", number)
+	return nil, fmt.Errorf("version %s not found in remote list%s", number, didYouMean(number, versions))
+}
+
+// suggestionCount 为 "did you mean" 提示最多列出的版本数。
+const suggestionCount = 3
+
+// didYouMean 返回附加在 "未找到" 错误后的相近版本提示，没有相近版本时返回空串。
+func didYouMean(number string, versions []models.Version) string {
+	candidates := make([]string, len(versions))
+	for i, v := range versions {
+		candidates[i] = v.Number
+	}
+	suggestions := version.Suggest(number, candidates, suggestionCount)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return "; did you mean " + strings.Join(suggestions, ", ") + "?"
+}
+
+// withLocalSuggestion 在 number 未安装时为 err 附加已安装版本中的相近提示。
+func (a *App) withLocalSuggestion(err error, number string) error {
+	if a.lister == nil {
+		return err
+	}
+	local, listErr := a.lister.LocalVersions()
+	if listErr != nil {
+		return err
+	}
+	for _, v := range local {
+		if v.Number == number {
+			return err
+		}
+	}
+	if hint := didYouMean(number, local); hint != "" {
+		return fmt.Errorf("%w%s", err, hint)
+	}
+	return err
 }
 
 func (a *App) printInstallSummary(ver string) {
@@ -276,7 +921,7 @@ func (a *App) printInstallSummary(ver string) {
 	}
 	versions, err := a.lister.LocalVersions()
 	if err != nil {
-		fmt.Fprintf(a.out, "%s %v\n", colorize("warning:", colorYellow), err)
+		a.printf("%s %v\n", colorize("warning:", colorYellow), err)
 		return
 	}
 	goroot := findInstallPath(versions, ver)
@@ -285,14 +930,17 @@ func (a *App) printInstallSummary(ver string) {
 	}
 	gopath := inferGoPath()
 	sourceCmd := defaultSourceCommand()
+	if a.shellRC != "" {
+		sourceCmd = "source " + a.shellRC
+	}
 
 	fmt.Fprintln(a.out)
-	fmt.Fprintf(a.out, "%s %s\n", colorize("安装完成", colorBoldGreen), colorize("✓", colorBoldGreen))
-	fmt.Fprintf(a.out, "%s %s\n", colorize("go version:", colorCyan), emphasizeValue(ver))
-	fmt.Fprintf(a.out, "%s %s\n", colorize("goroot:", colorCyan), emphasizeValue(goroot))
-	fmt.Fprintf(a.out, "%s %s\n", colorize("gopath:", colorCyan), emphasizeValue(gopath))
-	fmt.Fprintf(a.out, "%s 运行 %s 让环境变量立即生效\n", colorize("下一步:", colorYellow), highlightCommand(sourceCmd))
-	fmt.Fprintf(a.out, "%s 执行 %s 切换到新安装版本\n", colorize("提示:", colorYellow), highlightCommand("govm use "+ver))
+	a.printf("%s %s\n", colorize(a.tr("Installation complete"), colorBoldGreen), colorize("✓", colorBoldGreen))
+	a.printf("%s %s\n", colorize("go version:", colorCyan), emphasizeValue(ver))
+	a.printf("%s %s\n", colorize("goroot:", colorCyan), emphasizeValue(goroot))
+	a.printf("%s %s\n", colorize("gopath:", colorCyan), emphasizeValue(gopath))
+	a.printf("%s run %s to apply the environment variables now\n", colorize(a.tr("Next:"), colorYellow), highlightCommand(sourceCmd))
+	a.printf("%s run %s to switch to the new version\n", colorize(a.tr("Tip:"), colorYellow), highlightCommand("govm use "+ver))
 }
 
 func findInstallPath(versions []models.Version, ver string) string {
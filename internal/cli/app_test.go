@@ -2,9 +2,21 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/internal/httpclient"
+	"github.com/liangyou/govm/internal/timing"
+	"github.com/liangyou/govm/internal/version"
 	"github.com/liangyou/govm/pkg/models"
 )
 
@@ -30,6 +42,7 @@ func (f *fakeLister) CurrentVersion() (*models.Version, error) {
 }
 
 type fakeInstaller struct {
+	mu        sync.Mutex
 	installed []models.Version
 	err       error
 }
@@ -38,6 +51,8 @@ func (f *fakeInstaller) Install(v models.Version) error {
 	if f.err != nil {
 		return f.err
 	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.installed = append(f.installed, v)
 	return nil
 }
@@ -82,11 +97,120 @@ func TestAppRemoteList(t *testing.T) {
 	}
 
 	output := buf.String()
-	if !strings.Contains(output, "Remote versions:") || !strings.Contains(output, "go1.21.0") {
+	if !strings.Contains(output, "VERSION") || !strings.Contains(output, "go1.21.0") {
 		t.Fatalf("unexpected output: %s", output)
 	}
 }
 
+func TestAppChannelFilter(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{
+		remote: []models.Version{
+			{Number: "1.23rc1", FullName: "go1.23rc1", Channel: "rc"},
+			{Number: "1.22.3", FullName: "go1.22.3", Channel: "stable"},
+			{Number: "1.20.14", FullName: "go1.20.14", Channel: "archived"},
+		},
+		// 旧的元数据没有 Channel，按版本号判断。
+		local: []models.Version{
+			{Number: "1.23rc1", InstallPath: "/govm/versions/go1.23rc1"},
+			{Number: "1.22.3", InstallPath: "/govm/versions/go1.22.3", Channel: "stable"},
+		},
+	}
+	run := func(args ...string) (string, error) {
+		buf := &bytes.Buffer{}
+		err := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test").Run(args)
+		return buf.String(), err
+	}
+
+	if out, err := run("-remote", "-channel", "stable", "-limit", "1", "-format", "{{.Number}}"); err != nil || out != "1.22.3\n" {
+		t.Fatalf("latest stable = %q, %v", out, err)
+	}
+	if out, err := run("-remote", "-flat", "-channel", "rc,archived", "-format", "{{.Number}} {{.Channel}}"); err != nil || out != "1.23rc1 rc\n1.20.14 archived\n" {
+		t.Fatalf("rc and archived = %q, %v", out, err)
+	}
+	if out, err := run("-list", "-channel", "rc", "-format", "{{.Number}}"); err != nil || out != "1.23rc1\n" {
+		t.Fatalf("local rc = %q, %v", out, err)
+	}
+	if out, err := run("-list", "-channel", "beta"); err != nil || !strings.Contains(out, "No installed versions in channel beta") {
+		t.Fatalf("empty channel = %q, %v", out, err)
+	}
+	if _, err := run("-list", "-channel", "nightly"); err == nil || !strings.Contains(err.Error(), "invalid -channel") {
+		t.Fatalf("expected an invalid channel error, got %v", err)
+	}
+}
+
+// aggregateLister 通过 version.Lister 的 ListAll 组合 fakeLister 的远程与本地版本。
+type aggregateLister struct {
+	*fakeLister
+}
+
+func (a aggregateLister) ListAll(ctx context.Context) (version.Listing, error) {
+	return version.NewLister(fakeRemote{a.remote, a.remoteErr}, &fakeStore{a.local, a.localErr}).ListAll(ctx)
+}
+
+type fakeRemote struct {
+	versions []models.Version
+	err      error
+}
+
+func (f fakeRemote) FetchVersions(context.Context) ([]models.Version, error) {
+	return f.versions, f.err
+}
+
+// fakeStore 为只读的内存元数据，当前版本取 IsCurrent 的条目。
+type fakeStore struct {
+	versions []models.Version
+	err      error
+}
+
+func (f *fakeStore) SaveMetadata(models.Version) error       { return nil }
+func (f *fakeStore) LoadMetadata() ([]models.Version, error) { return slices.Clone(f.versions), f.err }
+func (f *fakeStore) DeleteMetadata(string) error             { return nil }
+func (f *fakeStore) GetInstallPath(v string) string          { return "/govm/versions/go" + v }
+func (f *fakeStore) SetCurrentVersionMarker(string) error    { return nil }
+func (f *fakeStore) GetCurrentVersionMarker() (string, error) {
+	for _, v := range f.versions {
+		if v.IsCurrent {
+			return v.Number, nil
+		}
+	}
+	return "", nil
+}
+
+func TestAppRemoteMarksInstalledVersions(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{
+		remote: []models.Version{{Number: "1.22.1", FullName: "go1.22.1"}, {Number: "1.22.0", FullName: "go1.22.0"}, {Number: "1.21.8", FullName: "go1.21.8"}},
+		local:  []models.Version{{Number: "1.22.0", InstallPath: "/govm/versions/go1.22.0", IsCurrent: true}, {Number: "1.21.8", InstallPath: "/govm/versions/go1.21.8"}},
+	}
+	run := func(args ...string) (string, error) {
+		buf := &bytes.Buffer{}
+		err := NewApp(buf, aggregateLister{lister}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test").Run(args)
+		return buf.String(), err
+	}
+
+	out, err := run("-remote", "-flat")
+	if err != nil {
+		t.Fatalf("-remote -flat: %v", err)
+	}
+	lines := strings.Split(out, "\n")
+	if !strings.HasPrefix(lines[1], "   go1.22.1") || !strings.HasPrefix(lines[2], "*  go1.22.0") || !strings.HasPrefix(lines[3], "+  go1.21.8") {
+		t.Fatalf("installed markers missing:\n%s", out)
+	}
+
+	// 本地元数据不可读时仍列出远程版本，只是没有安装标记。
+	lister.localErr = errors.New("disk")
+	if out, err := run("-remote", "-flat", "-format", "{{.Number}} {{.IsCurrent}}"); err != nil || out != "1.22.1 false\n1.22.0 false\n1.21.8 false\n" {
+		t.Fatalf("remote without local = %q, %v", out, err)
+	}
+	lister.remoteErr = errors.New("offline")
+	if _, err := run("-remote"); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Fatalf("expected the remote error, got %v", err)
+	}
+}
+
 func TestAppInstallUsesRemoteVersion(t *testing.T) {
 	t.Parallel()
 
@@ -104,6 +228,160 @@ func TestAppInstallUsesRemoteVersion(t *testing.T) {
 	}
 }
 
+func TestAppInstallMany(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	bus := events.NewBus()
+	installs := &fakeInstaller{}
+	lister := &fakeLister{remote: []models.Version{
+		{Number: "1.21.8", FullName: "go1.21.8"},
+		{Number: "1.22.3", FullName: "go1.22.3"},
+	}}
+	app := NewApp(buf, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithEventBus(bus))
+
+	err := app.Run([]string{"install", "1.21.8", "go1.22.3", "1.99.0", "--jobs", "2"})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if len(installs.installed) != 2 {
+		t.Fatalf("expected two installs, got %#v", installs.installed)
+	}
+	out := buf.String()
+	for _, want := range []string{"VERSION", "go1.21.8", "go1.22.3", "installed", "go1.99.0", "failed"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("summary missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := app.Run([]string{"install", "1.21.8", "1.22.3", "--silent"}); err == nil {
+		t.Fatal("expected --silent to be rejected for several versions")
+	}
+}
+
+func TestAppInstallFromFile(t *testing.T) {
+	t.Parallel()
+
+	list := filepath.Join(t.TempDir(), "versions.txt")
+	if err := os.WriteFile(list, []byte("# toolchains\ngo1.21.8\n1.22.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lister := &fakeLister{
+		remote: []models.Version{{Number: "1.22.3", FullName: "go1.22.3"}},
+		local:  []models.Version{{Number: "1.21.8", FullName: "go1.21.8", InstallPath: "/opt/go1.21.8"}},
+	}
+
+	installs := &fakeInstaller{}
+	app := NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := app.Run([]string{"install", "-f", list}); err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Fatalf("expected existing version to fail without --skip-existing, got %v", err)
+	}
+
+	installs = &fakeInstaller{}
+	buf := &bytes.Buffer{}
+	app = NewApp(buf, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := app.Run([]string{"install", "-f", list, "--skip-existing"}); err != nil {
+		t.Fatalf("install -f --skip-existing failed: %v", err)
+	}
+	if len(installs.installed) != 1 || installs.installed[0].Number != "1.22.3" {
+		t.Fatalf("unexpected installs: %#v", installs.installed)
+	}
+	if !strings.Contains(buf.String(), "already installed") {
+		t.Fatalf("summary should report the skipped version:\n%s", buf.String())
+	}
+
+	installs = &fakeInstaller{}
+	app = NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := app.Run([]string{"install", "-f", list, "1.99.0", "--skip-existing", "--fail-fast"}); err == nil {
+		t.Fatal("expected --fail-fast to report the unknown version")
+	}
+	if len(installs.installed) != 0 {
+		t.Fatalf("--fail-fast should not start installs after a resolve failure: %#v", installs.installed)
+	}
+}
+
+func TestAppQuietQueries(t *testing.T) {
+	t.Parallel()
+
+	goroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(goroot, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(goroot, "bin", "go"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	current := &models.Version{Number: "1.22.3", FullName: "go1.22.3", InstallPath: goroot}
+	run := func(lister *fakeLister, args ...string) (string, error) {
+		buf := &bytes.Buffer{}
+		app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithGoPath("/src/go"))
+		app.getenv = func(string) string { return "" }
+		err := app.Run(args)
+		return buf.String(), err
+	}
+
+	active := &fakeLister{current: current}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"current", "--quiet"}, "1.22.3\n"},
+		{[]string{"current", "--path"}, goroot + "\n"},
+		{[]string{"which", "-q"}, filepath.Join(goroot, "bin", "go") + "\n"},
+		{[]string{"env", "--quiet"}, goroot + "\n"},
+		{[]string{"env", "GOPATH", "goversion"}, "/src/go\ngo1.22.3\n"},
+	} {
+		out, err := run(active, tc.args...)
+		if err != nil || out != tc.want {
+			t.Fatalf("%v = %q, %v; want %q", tc.args, out, err, tc.want)
+		}
+	}
+	if out, _ := run(active, "env"); !strings.Contains(out, "export GOROOT='"+goroot+"'") {
+		t.Fatalf("env should print export lines, got %q", out)
+	}
+	if _, err := run(active, "which", "gofmt"); err == nil {
+		t.Fatal("expected error for missing tool")
+	}
+
+	none := &fakeLister{}
+	for _, args := range [][]string{{"current", "-q"}, {"which", "--quiet"}, {"env", "-q"}} {
+		out, err := run(none, args...)
+		var exitErr *ExitError
+		if !errors.As(err, &exitErr) || exitErr.Code != 1 || exitErr.Err != nil || out != "" {
+			t.Fatalf("%v should exit 1 silently, got %q, %v", args, out, err)
+		}
+	}
+	if _, err := run(none, "which"); !errors.Is(err, ErrNoActiveVersion) {
+		t.Fatalf("which without --quiet should report no active version, got %v", err)
+	}
+}
+
+func TestAppAssertChecksActiveVersion(t *testing.T) {
+	t.Parallel()
+
+	run := func(lister *fakeLister, args ...string) (string, error) {
+		buf := &bytes.Buffer{}
+		err := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test").Run(args)
+		return buf.String(), err
+	}
+	active := &fakeLister{current: &models.Version{Number: "1.22.3", FullName: "go1.22.3"}}
+
+	if out, err := run(active, "assert", "1.22.x"); err != nil || out != "go1.22.3 satisfies 1.22.x\n" {
+		t.Fatalf("assert 1.22.x = %q, %v", out, err)
+	}
+	if out, err := run(active, "assert", "-q", ">=1.21", "<1.23"); err != nil || out != "" {
+		t.Fatalf("quiet assert = %q, %v", out, err)
+	}
+	if _, err := run(active, "assert", ">=1.21 <1.22"); err == nil || err.Error() != "assert: go1.22.3 does not satisfy >=1.21 <1.22" {
+		t.Fatalf("expected a mismatch error, got %v", err)
+	}
+	if _, err := run(active, "assert", "latest"); err == nil || !strings.Contains(err.Error(), "invalid constraint") {
+		t.Fatalf("expected an invalid constraint error, got %v", err)
+	}
+	if _, err := run(&fakeLister{}, "assert", "1.22.x"); err == nil || !strings.Contains(err.Error(), "no active Go version") {
+		t.Fatalf("expected an error without an active version, got %v", err)
+	}
+}
+
 func TestAppUninstallRequiresForce(t *testing.T) {
 	t.Parallel()
 
@@ -121,6 +399,171 @@ func TestAppUninstallRequiresForce(t *testing.T) {
 	}
 }
 
+// stubPrompter 按顺序返回预设的确认结果并记录问题。
+type stubPrompter struct {
+	answers   []bool
+	questions []string
+}
+
+func (p *stubPrompter) Ask(question, def string) string { return def }
+
+func (p *stubPrompter) Confirm(question string, def bool) bool {
+	p.questions = append(p.questions, question)
+	if len(p.answers) == 0 {
+		return def
+	}
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer
+}
+
+func TestAppUninstallActivePrompts(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	u := &fakeUninstaller{}
+	prompt := &stubPrompter{answers: []bool{false, true}}
+	lister := &fakeLister{current: &models.Version{Number: "1.22.0"}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, u, "test", WithPrompter(prompt))
+
+	if err := app.Run([]string{"uninstall", "1.22.0"}); err == nil || len(u.removed) != 0 {
+		t.Fatalf("declining must abort: err=%v removed=%v", err, u.removed)
+	}
+	if err := app.Run([]string{"uninstall", "1.22.0"}); err != nil {
+		t.Fatalf("confirmed uninstall failed: %v", err)
+	}
+	if len(prompt.questions) != 2 || !strings.Contains(prompt.questions[0], "go1.22.0 is active") {
+		t.Fatalf("unexpected prompts: %q", prompt.questions)
+	}
+	if len(u.forced) != 1 || !u.forced[0] {
+		t.Fatalf("confirmed uninstall must force removal: %v", u.forced)
+	}
+
+	for _, args := range [][]string{{"uninstall", "1.22.0", "--yes"}, {"-yes", "uninstall", "1.22.0"}} {
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+	if len(prompt.questions) != 2 {
+		t.Fatalf("--yes must skip the prompt: %q", prompt.questions)
+	}
+
+	if err := app.Run([]string{"uninstall", "1.21.0"}); err != nil || len(prompt.questions) != 2 {
+		t.Fatalf("inactive versions must not prompt: err=%v prompts=%q", err, prompt.questions)
+	}
+}
+
+type fakeRecent []string
+
+func (f fakeRecent) RecentVersions() ([]string, error) { return f, nil }
+
+func TestAppUsePrevious(t *testing.T) {
+	t.Parallel()
+
+	switcher := &fakeSwitcher{}
+	lister := &fakeLister{local: []models.Version{
+		{Number: "1.22.0", InstallPath: "/govm/versions/go1.22.0", IsCurrent: true},
+		{Number: "1.21.0", InstallPath: "/govm/versions/go1.21.0"},
+	}}
+	// 1.20.0 已被卸载，1.22.0 是当前版本，都应跳过。
+	app := NewApp(&bytes.Buffer{}, lister, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test", WithRecentVersions(fakeRecent{"1.20.0", "1.22.0", "1.21.0"}))
+	if err := app.Run([]string{"use", "-"}); err != nil {
+		t.Fatalf("use - failed: %v", err)
+	}
+	if len(switcher.used) != 1 || switcher.used[0] != "1.21.0" {
+		t.Fatalf("use - switched to %v, want 1.21.0", switcher.used)
+	}
+
+	empty := NewApp(&bytes.Buffer{}, lister, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test", WithRecentVersions(fakeRecent{}))
+	if err := empty.Run([]string{"use", "-"}); err == nil || !strings.Contains(err.Error(), "no previous version") {
+		t.Fatalf("use - without history = %v", err)
+	}
+}
+
+func TestAppInstallAndUseVersionRanges(t *testing.T) {
+	t.Parallel()
+
+	installs := &fakeInstaller{}
+	switcher := &fakeSwitcher{}
+	lister := &fakeLister{
+		remote: []models.Version{
+			{Number: "1.23rc1", FullName: "go1.23rc1"},
+			{Number: "1.22.10", FullName: "go1.22.10"},
+			{Number: "1.22.9", FullName: "go1.22.9"},
+			{Number: "1.21.8", FullName: "go1.21.8"},
+		},
+		local: []models.Version{
+			{Number: "1.22.3", InstallPath: "/govm/versions/go1.22.3"},
+			{Number: "1.21.8", InstallPath: "/govm/versions/go1.21.8"},
+		},
+	}
+	app := NewApp(&bytes.Buffer{}, lister, installs, switcher, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"install", ">=1.22"}); err != nil {
+		t.Fatalf("install >=1.22: %v", err)
+	}
+	if len(installs.installed) != 1 || installs.installed[0].Number != "1.22.10" {
+		t.Fatalf("expected the newest stable 1.22 release, got %#v", installs.installed)
+	}
+	if err := app.Run([]string{"install", "1.24.x"}); err == nil || !strings.Contains(err.Error(), "no released version satisfies 1.24.x") {
+		t.Fatalf("expected an unsatisfiable range error, got %v", err)
+	}
+	if err := app.Run([]string{"install", "--no-catalog", "1.22.x"}); err == nil {
+		t.Fatal("ranges must be rejected with --no-catalog")
+	}
+
+	if err := app.Run([]string{"use", "~1.21.0"}); err != nil {
+		t.Fatalf("use ~1.21.0: %v", err)
+	}
+	if err := app.Run([]string{"use", "1.22.x"}); err != nil {
+		t.Fatalf("use 1.22.x: %v", err)
+	}
+	if len(switcher.used) != 2 || switcher.used[0] != "1.21.8" || switcher.used[1] != "1.22.3" {
+		t.Fatalf("use should pick the newest installed match, got %v", switcher.used)
+	}
+	if err := app.Run([]string{"use", ">=1.23"}); err == nil || !strings.Contains(err.Error(), "no installed version satisfies >=1.23") {
+		t.Fatalf("expected an error for a range without installed matches, got %v", err)
+	}
+}
+
+type fakeLocks map[string]bool
+
+func (f fakeLocks) SetLocked(version string, locked bool) error {
+	f[version] = locked
+	return nil
+}
+
+func TestAppLockedUninstallRequiresForce(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	u := &fakeUninstaller{}
+	locks := fakeLocks{}
+	lister := &fakeLister{local: []models.Version{{Number: "1.22.0", InstallPath: "/govm/versions/go1.22.0", Locked: true}}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, u, "test", WithVersionLocks(locks), WithPrompter(&stubPrompter{answers: []bool{true}}))
+
+	if err := app.Run([]string{"lock", "go1.22.0"}); err != nil || !locks["1.22.0"] {
+		t.Fatalf("lock failed: %v %v", err, locks)
+	}
+	if err := app.Run([]string{"-list"}); err != nil || !strings.Contains(buf.String(), "(locked)") {
+		t.Fatalf("list does not show lock: %v\n%s", err, buf.String())
+	}
+	for _, args := range [][]string{{"uninstall", "1.22.0"}, {"uninstall", "1.22.0", "--yes"}, {"-uninstall", "1.22.0"}} {
+		if err := app.Run(args); err == nil || !strings.Contains(err.Error(), "is locked") {
+			t.Fatalf("%v on locked version = %v", args, err)
+		}
+	}
+	if len(u.removed) != 0 {
+		t.Fatalf("locked version removed: %v", u.removed)
+	}
+	if err := app.Run([]string{"uninstall", "1.22.0", "--force"}); err != nil || len(u.removed) != 1 {
+		t.Fatalf("forced uninstall: %v %v", err, u.removed)
+	}
+	if err := app.Run([]string{"unlock", "1.22.0"}); err != nil || locks["1.22.0"] {
+		t.Fatalf("unlock failed: %v %v", err, locks)
+	}
+}
+
 func TestAppUninstallFlag(t *testing.T) {
 	t.Parallel()
 
@@ -143,3 +586,515 @@ func TestAppUninstallFlag(t *testing.T) {
 		t.Fatalf("flag uninstall force not recorded: removed=%v forced=%v", u.removed, u.forced)
 	}
 }
+
+type eventInstaller struct {
+	bus *events.Bus
+}
+
+func (e *eventInstaller) Install(v models.Version) error {
+	e.bus.Publish(events.New(events.Done, "version", v.Number, "status", "installed"))
+	return nil
+}
+
+func TestAppPorcelainInstall(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	bus := events.NewBus()
+	lister := &fakeLister{remote: []models.Version{{Number: "1.20.3", FullName: "go1.20.3"}}}
+	app := NewApp(buf, lister, &eventInstaller{bus: bus}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithEventBus(bus))
+
+	if err := app.Run([]string{"-porcelain", "install", "1.20.3"}); err != nil {
+		t.Fatalf("porcelain install failed: %v", err)
+	}
+
+	if got := buf.String(); got != "event=done version=1.20.3 status=installed\n" {
+		t.Fatalf("unexpected porcelain output: %q", got)
+	}
+}
+
+func TestAppVerbosePrintsTimings(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	timings := timing.NewRecorder()
+	timings.Add(timing.Catalog, 250*time.Millisecond)
+	timings.Add(timing.Download, 1500*time.Millisecond)
+	lister := &fakeLister{remote: []models.Version{{Number: "1.20.3", FullName: "go1.20.3"}}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithTimings(timings))
+
+	if err := app.Run([]string{"-verbose", "install", "1.20.3"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	out := buf.String()
+	summary := out[strings.Index(out, "timing:"):]
+	for _, want := range []string{"release list  250ms", "download      1.5s"} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("summary is missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"install", "1.20.3"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "timing:") {
+		t.Fatalf("timings printed without -verbose:\n%s", buf.String())
+	}
+}
+
+type fakePolicy struct {
+	denied map[string]bool
+	warn   bool
+}
+
+func (f *fakePolicy) CheckVersion(version string) error {
+	if f.denied[version] {
+		return errors.New("policy: go" + version + " is denied by policy")
+	}
+	return nil
+}
+
+func (f *fakePolicy) WarnOnly() bool { return f.warn }
+
+func TestAppPolicyEnforceAndWarn(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{remote: []models.Version{{Number: "1.19.0", FullName: "go1.19.0"}}}
+
+	installs := &fakeInstaller{}
+	enforced := NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test",
+		WithPolicy(&fakePolicy{denied: map[string]bool{"1.19.0": true}}))
+	if err := enforced.Run([]string{"install", "1.19.0"}); err == nil {
+		t.Fatal("expected policy to refuse install")
+	}
+	if len(installs.installed) != 0 {
+		t.Fatalf("installer should not run: %#v", installs.installed)
+	}
+
+	buf, warnings := &bytes.Buffer{}, &bytes.Buffer{}
+	switcher := &fakeSwitcher{}
+	warned := NewApp(buf, lister, installs, switcher, &fakeUninstaller{}, "test",
+		WithPolicy(&fakePolicy{denied: map[string]bool{"1.19.0": true}, warn: true}), WithWarnings(warnings))
+	if err := warned.Run([]string{"use", "1.19.0"}); err != nil {
+		t.Fatalf("warn mode should not fail: %v", err)
+	}
+	if len(switcher.used) != 1 || !strings.Contains(warnings.String(), "denied by policy") {
+		t.Fatalf("expected warning and switch, got used=%v warnings=%s", switcher.used, warnings.String())
+	}
+	if strings.Contains(buf.String(), "denied by policy") {
+		t.Fatalf("policy warnings must not go to stdout: %s", buf.String())
+	}
+
+	// --silent 的 stdout 只能是 GOROOT，警告不能混入其中。
+	buf.Reset()
+	lister.local = []models.Version{{Number: "1.19.0", InstallPath: "/opt/govm/go1.19.0"}}
+	if err := warned.Run([]string{"install", "1.19.0", "--silent"}); err != nil {
+		t.Fatalf("silent install failed: %v", err)
+	}
+	if buf.String() != "/opt/govm/go1.19.0\n" {
+		t.Fatalf("expected only GOROOT on stdout, got %q", buf.String())
+	}
+}
+
+type fakeActivator struct {
+	fakeSwitcher
+	activated []string
+}
+
+func (f *fakeActivator) Activate(version string) error {
+	f.activated = append(f.activated, version)
+	return nil
+}
+
+func TestAppInstallSilentGlobalPath(t *testing.T) {
+	pathFile := filepath.Join(t.TempDir(), "github_path")
+	t.Setenv("GITHUB_PATH", pathFile)
+	t.Setenv("GITHUB_ENV", "")
+
+	buf := &bytes.Buffer{}
+	switcher := &fakeActivator{}
+	lister := &fakeLister{
+		remote: []models.Version{{Number: "1.22.0", FullName: "go1.22.0"}},
+		local:  []models.Version{{Number: "1.22.0", InstallPath: "/opt/govm/go1.22.0"}},
+	}
+	app := NewApp(buf, lister, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"install", "1.22.0", "--silent", "--global-path"}); err != nil {
+		t.Fatalf("ci install failed: %v", err)
+	}
+	if buf.String() != "/opt/govm/go1.22.0\n" {
+		t.Fatalf("expected only GOROOT on stdout, got %q", buf.String())
+	}
+	if len(switcher.activated) != 1 || len(switcher.used) != 0 {
+		t.Fatalf("expected activation without rc edits: activated=%v used=%v", switcher.activated, switcher.used)
+	}
+	data, err := os.ReadFile(pathFile)
+	if err != nil || string(data) != "/opt/govm/go1.22.0/bin\n" {
+		t.Fatalf("GITHUB_PATH not written: %q %v", data, err)
+	}
+}
+
+type fakeNetwork struct {
+	timeouts httpclient.Timeouts
+}
+
+func (f *fakeNetwork) Timeouts() httpclient.Timeouts     { return f.timeouts }
+func (f *fakeNetwork) SetTimeouts(t httpclient.Timeouts) { f.timeouts = t }
+
+func TestAppTimeoutFlags(t *testing.T) {
+	t.Parallel()
+
+	network := &fakeNetwork{timeouts: httpclient.DefaultTimeouts()}
+	lister := &fakeLister{remote: []models.Version{{Number: "1.22.0", FullName: "go1.22.0"}}}
+	app := NewApp(&bytes.Buffer{}, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithNetwork(network))
+
+	if err := app.Run([]string{"-connect-timeout", "2s", "-timeout", "5s", "install", "1.22.0", "--download-timeout", "1h"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	want := httpclient.Timeouts{Connect: 2 * time.Second, Request: 5 * time.Second, Download: time.Hour}
+	if network.timeouts != want {
+		t.Fatalf("unexpected timeouts: %#v", network.timeouts)
+	}
+	// -timeout 只表示元数据请求超时，install 不再接受同名的下载超时。
+	if err := app.Run([]string{"install", "1.22.0", "--timeout", "1h"}); err == nil {
+		t.Fatal("install --timeout must be rejected")
+	}
+}
+
+type fakeResolver struct{}
+
+func (fakeResolver) Resolve(number string) (models.Version, error) {
+	return models.Version{Number: number, FullName: "go" + number, DownloadURL: "https://mirror.example.com/go" + number + ".linux-amd64.tar.gz"}, nil
+}
+
+func TestAppInstallFallsBackWhenFeedUnreachable(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	installs := &fakeInstaller{}
+	lister := &fakeLister{remoteErr: errors.New("remote: request failed: dial tcp: i/o timeout")}
+	app := NewApp(buf, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithResolver(fakeResolver{}))
+
+	if err := app.Run([]string{"install", "1.22.1"}); err != nil {
+		t.Fatalf("install should fall back to derived URL: %v", err)
+	}
+	if len(installs.installed) != 1 || installs.installed[0].DownloadURL != "https://mirror.example.com/go1.22.1.linux-amd64.tar.gz" {
+		t.Fatalf("unexpected install target: %#v", installs.installed)
+	}
+	if !strings.Contains(buf.String(), "downloading https://mirror.example.com/go1.22.1.linux-amd64.tar.gz directly") {
+		t.Fatalf("expected fallback warning, got:\n%s", buf.String())
+	}
+
+	// 已安装的版本不需要访问版本源。
+	lister.local = []models.Version{{Number: "1.21.0", FullName: "go1.21.0", InstallPath: "/opt/go1.21.0"}}
+	noResolver := NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := noResolver.Run([]string{"install", "1.21.0"}); err != nil {
+		t.Fatalf("installed version should not need the feed: %v", err)
+	}
+}
+
+func TestAppInstallNoCatalogAndMissingFromFeed(t *testing.T) {
+	t.Parallel()
+
+	installs := &fakeInstaller{}
+	lister := &fakeLister{remote: []models.Version{{Number: "1.22.0", FullName: "go1.22.0"}}}
+	app := NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithResolver(fakeResolver{}))
+
+	if err := app.Run([]string{"install", "1.13.15", "--no-catalog"}); err != nil {
+		t.Fatalf("install --no-catalog failed: %v", err)
+	}
+	if err := app.Run([]string{"install", "1.12.17"}); err != nil {
+		t.Fatalf("install of version missing from feed failed: %v", err)
+	}
+	if len(installs.installed) != 2 || installs.installed[0].Number != "1.13.15" || installs.installed[1].Number != "1.12.17" {
+		t.Fatalf("unexpected installs: %#v", installs.installed)
+	}
+
+	lister.remoteErr = errors.New("must not be called")
+	lister.remote = nil
+	strict := NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := strict.Run([]string{"install", "1.13.15", "--no-catalog"}); err == nil {
+		t.Fatal("expected --no-catalog to require a resolver")
+	}
+}
+
+func TestAppSuggestsCloseVersions(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{
+		remote: []models.Version{{Number: "1.22.4"}, {Number: "1.22.3"}, {Number: "1.21.9"}},
+		local:  []models.Version{{Number: "1.21.9", InstallPath: "/govm/versions/go1.21.9"}},
+	}
+	switcher := &fakeSwitcher{err: errors.New("switcher: version 1.21.8 not installed")}
+	app := NewApp(&bytes.Buffer{}, lister, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test")
+
+	err := app.Run([]string{"install", "1.22.9"})
+	if err == nil || !strings.HasSuffix(err.Error(), "not found in remote list; did you mean 1.22.3, 1.22.4?") {
+		t.Fatalf("install error = %v", err)
+	}
+	err = app.Run([]string{"use", "1.21.8"})
+	if err == nil || !strings.HasSuffix(err.Error(), "; did you mean 1.21.9?") || !errors.Is(err, switcher.err) {
+		t.Fatalf("use error = %v", err)
+	}
+}
+
+// fakeArtifactResolver 额外支持按归档文件名或 URL 解析。
+type fakeArtifactResolver struct{ fakeResolver }
+
+func (fakeArtifactResolver) ResolveArtifact(ref string) (models.Version, error) {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	number := strings.TrimSuffix(strings.TrimPrefix(name, "go"), ".linux-amd64.tar.gz")
+	url := ref
+	if !strings.Contains(ref, "://") {
+		url = "https://mirror.example.com/" + name
+	}
+	return models.Version{Number: number, FullName: "go" + number, FileName: name, DownloadURL: url}, nil
+}
+
+func TestAppInstallArtifact(t *testing.T) {
+	t.Parallel()
+
+	sum := strings.Repeat("ab", 32)
+	installs := &fakeInstaller{}
+	lister := &fakeLister{remote: []models.Version{{Number: "1.22.3", FileName: "go1.22.3.linux-amd64.tar.gz", Checksum: sum}}}
+	app := NewApp(&bytes.Buffer{}, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithResolver(fakeArtifactResolver{}))
+
+	if err := app.Run([]string{"install", "go1.22.3.linux-amd64.tar.gz"}); err != nil {
+		t.Fatalf("install by file name failed: %v", err)
+	}
+	if err := app.Run([]string{"install", "https://example.org/go1.24rc1.linux-amd64.tar.gz"}); err == nil || !strings.Contains(err.Error(), "pass --sha256") {
+		t.Fatalf("unlisted URL without --sha256 = %v", err)
+	}
+	if err := app.Run([]string{"install", "https://example.org/go1.24rc1.linux-amd64.tar.gz", "--sha256", strings.ToUpper(sum)}); err != nil {
+		t.Fatalf("install by URL failed: %v", err)
+	}
+	if len(installs.installed) != 2 {
+		t.Fatalf("unexpected installs: %#v", installs.installed)
+	}
+	if got := installs.installed[0]; got.Checksum != sum || got.DownloadURL != "https://mirror.example.com/go1.22.3.linux-amd64.tar.gz" {
+		t.Fatalf("file name install = %#v", got)
+	}
+	if got := installs.installed[1]; got.Number != "1.24rc1" || got.Checksum != sum || got.DownloadURL != "https://example.org/go1.24rc1.linux-amd64.tar.gz" {
+		t.Fatalf("URL install = %#v", got)
+	}
+
+	if err := app.Run([]string{"install", "1.22.3", "--sha256", sum}); err == nil {
+		t.Fatal("expected --sha256 with a plain version to be rejected")
+	}
+	if err := app.Run([]string{"install", "https://example.org/go1.24rc1.linux-amd64.tar.gz", "--sha256", "xyz"}); err == nil {
+		t.Fatal("expected invalid --sha256 to be rejected")
+	}
+}
+
+func TestAppRemoteGroupedAndFlat(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{remote: []models.Version{
+		{Number: "1.22.1", FullName: "go1.22.1", OS: "linux", Arch: "amd64"},
+		{Number: "1.22.0", FullName: "go1.22.0", OS: "linux", Arch: "amd64"},
+		{Number: "1.21.8", FullName: "go1.21.8", OS: "linux", Arch: "amd64"},
+	}}
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := app.Run([]string{"-remote"}); err != nil {
+		t.Fatalf("remote failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "1.22") || !strings.Contains(lines[1], "go1.22.1") {
+		t.Fatalf("unexpected grouped output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"-remote", "-flat", "-limit", "2"}); err != nil {
+		t.Fatalf("remote -flat failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "CHANNEL") || !strings.Contains(out, "Showing 1-2 of 3, use -page 2 for more") {
+		t.Fatalf("unexpected flat output:\n%s", out)
+	}
+}
+
+type fakeShims struct {
+	installs map[string]string
+}
+
+func (f *fakeShims) Dir() string { return "/govm/bin" }
+
+func (f *fakeShims) Rehash(installs map[string]string) ([]string, error) {
+	f.installs = installs
+	var names []string
+	for v := range installs {
+		names = append(names, "go"+v, "gofmt"+v)
+	}
+	return names, nil
+}
+
+func TestAppRehash(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	shims := &fakeShims{}
+	lister := &fakeLister{local: []models.Version{{Number: "1.22.0", InstallPath: "/govm/versions/go1.22.0"}}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithShims(shims))
+
+	if err := app.Run([]string{"rehash"}); err != nil {
+		t.Fatalf("rehash failed: %v", err)
+	}
+	if shims.installs["1.22.0"] != "/govm/versions/go1.22.0" {
+		t.Fatalf("unexpected rehash input: %v", shims.installs)
+	}
+	if !strings.Contains(buf.String(), "Rehashed 2 shim(s) for 1 version(s) in /govm/bin") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+type fakeAdopter struct {
+	found   []models.Version
+	adopted []string
+}
+
+func (f *fakeAdopter) Detect() ([]models.Version, error) { return f.found, nil }
+
+func (f *fakeAdopter) Adopt(goRoot string) (models.Version, error) {
+	f.adopted = append(f.adopted, goRoot)
+	return models.Version{Number: "1.21.5", InstallPath: goRoot, External: true}, nil
+}
+
+func TestAppHintsAndAdoptsExistingGo(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	adopter := &fakeAdopter{found: []models.Version{{Number: "1.21.5", InstallPath: "/usr/local/go", External: true}}}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithAdopter(adopter))
+
+	if err := app.Run([]string{"-list"}); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "go1.21.5 (/usr/local/go). Run `govm adopt`") {
+		t.Fatalf("missing adopt hint:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"adopt"}); err != nil {
+		t.Fatalf("adopt failed: %v", err)
+	}
+	if len(adopter.adopted) != 1 || adopter.adopted[0] != "/usr/local/go" {
+		t.Fatalf("unexpected adoption: %v", adopter.adopted)
+	}
+}
+
+// toolchainAdopter 额外实现 ToolchainDetector，记录查询的模块缓存目录。
+type toolchainAdopter struct {
+	fakeAdopter
+	toolchains []models.Version
+	modCache   string
+}
+
+func (f *toolchainAdopter) DetectToolchains(modCache string) ([]models.Version, error) {
+	f.modCache = modCache
+	return f.toolchains, nil
+}
+
+func TestAppAdoptsModuleCacheToolchains(t *testing.T) {
+	t.Parallel()
+
+	modCache := t.TempDir()
+	root := filepath.Join(modCache, "golang.org", "toolchain@v0.0.1-go1.21.5.linux-amd64")
+	adopter := &toolchainAdopter{
+		fakeAdopter: fakeAdopter{found: []models.Version{{Number: "1.20.1", InstallPath: "/usr/local/go", External: true}}},
+		toolchains: []models.Version{
+			{Number: "1.20.1", InstallPath: filepath.Join(modCache, "golang.org", "toolchain@v0.0.1-go1.20.1.linux-amd64"), External: true},
+			{Number: "1.21.5", InstallPath: root, External: true},
+		},
+	}
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithAdopter(adopter))
+	app.getenv = func(key string) string {
+		if key == "GOMODCACHE" {
+			return modCache
+		}
+		return ""
+	}
+
+	if err := app.Run([]string{"adopt"}); err != nil {
+		t.Fatalf("adopt failed: %v", err)
+	}
+	if adopter.modCache != modCache {
+		t.Fatalf("DetectToolchains got %q, want %q", adopter.modCache, modCache)
+	}
+	if len(adopter.adopted) != 2 || adopter.adopted[0] != "/usr/local/go" || adopter.adopted[1] != root {
+		t.Fatalf("installs outside the module cache must win for the same version: %v", adopter.adopted)
+	}
+	if !strings.Contains(buf.String(), "stays in the module cache and is linked, not copied") {
+		t.Fatalf("missing module cache note:\n%s", buf.String())
+	}
+
+	lister.local = []models.Version{{Number: "1.21.5", InstallPath: root, External: true}}
+	lister.current = &lister.local[0]
+	app.runGo = func(string, io.Writer, ...string) error { return errors.New("go must not run") }
+	err := app.Run([]string{"clean", "--modcache"})
+	if err == nil || !strings.Contains(err.Error(), "go1.21.5 adopted from "+modCache+" would be deleted") {
+		t.Fatalf("clean --modcache must refuse while an adopted toolchain lives there, got %v", err)
+	}
+}
+
+type fakeImplode struct {
+	keep  []bool
+	paths []string
+}
+
+func (f *fakeImplode) Implode(keep bool) ([]string, error) {
+	f.keep = append(f.keep, keep)
+	return f.paths, nil
+}
+
+func (f *fakeImplode) RemoveShellConfig() ([]string, error) {
+	return []string{"/home/u/.bashrc"}, nil
+}
+
+func TestAppImplodeConfirms(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	implode := &fakeImplode{paths: []string{"/home/u/.govm"}}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test",
+		WithImplode(implode, implode), WithInput(strings.NewReader("n\n")))
+
+	if err := app.Run([]string{"implode"}); err == nil || len(implode.keep) != 0 {
+		t.Fatalf("declining must abort without removing anything: %v", err)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"implode", "--keep-versions", "--yes"}); err != nil {
+		t.Fatalf("implode failed: %v", err)
+	}
+	if len(implode.keep) != 1 || !implode.keep[0] {
+		t.Fatalf("keep-versions not passed: %v", implode.keep)
+	}
+	for _, want := range []string{"Removed govm block from /home/u/.bashrc", "Removed /home/u/.govm"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestAppLocalizedOutput(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{local: []models.Version{}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithLocale("zh_CN.UTF-8"))
+	if err := app.Run([]string{"uninstall", "1.18", "--force"}); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "已卸载 go1.18") || !strings.Contains(out, "已没有安装的版本。") {
+		t.Fatalf("expected zh-CN output, got %q", out)
+	}
+	// 错误信息保持英文，便于检索。
+	if err := app.Run([]string{"uninstall"}); err == nil || err.Error() != "uninstall command requires a version" {
+		t.Fatalf("errors must stay in English: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// handleAssert 检查当前版本是否满足约束表达式，不满足或没有当前版本时以非零状态退出，供 CI 与 git hook 使用。
+func (a *App) handleAssert(args []string) error {
+	fs := newCommandFlagSet("assert")
+	quiet := fs.Bool("quiet", false, "print nothing when the active version matches")
+	fs.BoolVar(quiet, "q", false, "shorthand for --quiet")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return errors.New("usage: govm assert [--quiet] <constraint>")
+	}
+	// 约束中的空格可以不加引号，例如 govm assert '>=1.21' '<1.23'。
+	constraint, err := goversion.ParseConstraint(strings.Join(rest, " "))
+	if err != nil {
+		return err
+	}
+	current, err := a.activeVersion(false)
+	if errors.Is(err, ErrNoActiveVersion) {
+		return fmt.Errorf("assert: no active Go version, expected %s", constraint)
+	}
+	if err != nil {
+		return err
+	}
+	if !constraint.Matches(current.Number) {
+		return fmt.Errorf("assert: go%s does not satisfy %s", current.Number, constraint)
+	}
+	if !*quiet {
+		a.printf("go%[1]s satisfies %[2]s\n", current.Number, constraint)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// BackupService 描述 govm 状态快照的备份与恢复能力。
+type BackupService interface {
+	Backup(w io.Writer, opts storage.BackupOptions) error
+	Restore(r io.Reader, opts storage.RestoreOptions) ([]string, error)
+}
+
+// WithBackup 启用 `govm backup` 与 `govm restore`，configPath 为快照中包含的配置文件路径。
+func WithBackup(b BackupService, configPath string) AppOption {
+	return func(a *App) {
+		a.backup = b
+		a.configPath = configPath
+	}
+}
+
+func (a *App) handleBackup(args []string) error {
+	if a.backup == nil {
+		return errors.New("backup command is unavailable")
+	}
+	fs := newCommandFlagSet("backup")
+	downloads := fs.Bool("downloads", false, "include archives kept in downloads/")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	target := fmt.Sprintf("govm-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+	if len(rest) > 0 {
+		target = rest[0]
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".govm-backup-*")
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	err = a.withLock(func() error {
+		return a.backup.Backup(tmp, storage.BackupOptions{ConfigPath: a.configPath, IncludeDownloads: *downloads})
+	})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	a.printf("Backed up govm state to %s\n", target)
+	return nil
+}
+
+func (a *App) handleRestore(args []string) error {
+	if a.backup == nil {
+		return errors.New("restore command is unavailable")
+	}
+	fs := newCommandFlagSet("restore")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return errors.New("restore command requires a backup file")
+	}
+	f, err := os.Open(rest[0])
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	defer f.Close()
+
+	var restored []string
+	err = a.withLock(func() error {
+		var err error
+		restored, err = a.backup.Restore(f, storage.RestoreOptions{ConfigPath: a.configPath})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	a.printf("Restored %d file(s) from %s\n", len(restored), rest[0])
+
+	if a.lister == nil {
+		return nil
+	}
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if _, err := os.Stat(v.InstallPath); err != nil {
+			a.printf("%s go%s is recorded but not installed at %s, run `govm install %s`\n",
+				colorize("warning:", colorYellow), v.Number, v.InstallPath, v.Number)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// benchResult 记录 `govm bench` 中单个版本的计时结果。
+type benchResult struct {
+	number string
+	times  []time.Duration
+	err    error
+}
+
+// mean 返回计时的平均值。
+func (r benchResult) mean() time.Duration {
+	var total time.Duration
+	for _, d := range r.times {
+		total += d
+	}
+	return total / time.Duration(len(r.times))
+}
+
+// handleBench 依次以每个版本的环境执行 "--" 之后的命令（与 govm run 相同），先执行 --warmup 次不计时，
+// 再计时 --runs 次，最后输出各版本的耗时及相对第一个版本的变化。某个版本失败时继续测量其余版本。
+func (a *App) handleBench(args []string) error {
+	if a.lister == nil {
+		return errors.New("bench command is unavailable")
+	}
+	const usage = "usage: govm bench [--runs N] [--warmup N] <version> <version> [version...] -- <command> [args...]"
+	sep := slices.Index(args, "--")
+	if sep < 0 || sep == len(args)-1 {
+		return errors.New(usage)
+	}
+	command := args[sep+1:]
+	fs := newCommandFlagSet("bench")
+	runs := fs.Int("runs", 3, "timed runs per version")
+	warmup := fs.Int("warmup", 1, "untimed runs per version before timing, e.g. to fill the build cache")
+	rest, err := parseCommandFlags(fs, args[:sep])
+	if err != nil {
+		return err
+	}
+	if len(rest) < 2 {
+		return errors.New(usage)
+	}
+	if *runs < 1 || *warmup < 0 {
+		return fmt.Errorf("bench: --runs must be at least 1 and --warmup at least 0")
+	}
+
+	var numbers []string
+	for _, v := range rest {
+		number := normalizeVersion(v)
+		if slices.Contains(numbers, number) {
+			return fmt.Errorf("bench: go%s is listed more than once", number)
+		}
+		if _, err := a.runRoot(number); err != nil {
+			return err
+		}
+		numbers = append(numbers, number)
+	}
+
+	results := make([]benchResult, len(numbers))
+	for i, number := range numbers {
+		results[i] = benchResult{number: number}
+		a.printf("Benchmarking go%[1]s: %[2]d warmup and %[3]d timed run(s)\n", number, *warmup, *runs)
+		for n := 0; n < *warmup+*runs; n++ {
+			elapsed, err := a.benchOnce(number, command)
+			if err != nil {
+				results[i].err = err
+				break
+			}
+			if n >= *warmup {
+				results[i].times = append(results[i].times, elapsed)
+			}
+		}
+	}
+	return a.printBenchResults(results)
+}
+
+// benchOnce 执行一次命令并返回耗时；命令的输出只在失败时写到 stderr，避免干扰计时结果。
+func (a *App) benchOnce(number string, command []string) (time.Duration, error) {
+	cmd, err := a.versionCommand(number, command)
+	if err != nil {
+		return 0, err
+	}
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start)
+	if err != nil {
+		os.Stderr.Write(output.Bytes())
+		return 0, fmt.Errorf("bench: %s with go%s: %w", command[0], number, err)
+	}
+	return elapsed, nil
+}
+
+// printBenchResults 输出各版本的平均、最短与最长耗时，DELTA 为平均耗时相对第一个版本的变化；存在失败的版本时返回错误。
+func (a *App) printBenchResults(results []benchResult) error {
+	t := newTable("version", "runs", "mean", "min", "max", "delta")
+	base := results[0]
+	failed := 0
+	for i, r := range results {
+		name := "go" + r.number
+		if r.err != nil {
+			failed++
+			t.addRow(name, "-", "-", "-", "-", "failed: "+r.err.Error())
+			continue
+		}
+		delta := "baseline"
+		if i > 0 {
+			delta = "-"
+			if base.err == nil {
+				delta = fmt.Sprintf("%+.1f%%", (float64(r.mean())/float64(base.mean())-1)*100)
+			}
+		}
+		t.addRow(name, fmt.Sprintf("%d", len(r.times)), formatElapsed(r.mean()),
+			formatElapsed(slices.Min(r.times)), formatElapsed(slices.Max(r.times)), delta)
+	}
+	if err := t.render(a.out); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("bench: %d of %d version(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
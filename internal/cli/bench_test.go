@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestBenchTimesEachVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "runs.log")
+	var local []models.Version
+	for _, number := range []string{"1.21.8", "1.22.3", "1.23.0"} {
+		goRoot := filepath.Join(dir, "go"+number)
+		if err := os.MkdirAll(filepath.Join(goRoot, "bin"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		script := "#!/bin/sh\necho \"$GOVM_VERSION $*\" >> " + log + "\n[ \"$GOVM_VERSION\" != 1.23.0 ] || exit 2\n"
+		if err := os.WriteFile(filepath.Join(goRoot, "bin", "go"), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		local = append(local, models.Version{Number: number, InstallPath: goRoot})
+	}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{local: local}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"bench", "--runs", "2", "1.21.8", "go1.22.3", "--", "go", "build", "-h"}); err != nil {
+		t.Fatalf("bench: %v", err)
+	}
+	data, _ := os.ReadFile(log)
+	if got := strings.Count(string(data), "1.21.8 build -h\n"); got != 3 {
+		t.Fatalf("expected 1 warmup and 2 timed runs, got %d:\n%s", got, data)
+	}
+	out := buf.String()
+	for _, want := range []string{"VERSION", "DELTA", "go1.21.8  2", "baseline", "%"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	err := app.Run([]string{"bench", "--warmup", "0", "1.21.8", "1.23.0", "--", "go", "vet"})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 version(s) failed") {
+		t.Fatalf("expected a failed version, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "failed: bench: go with go1.23.0: exit status 2") {
+		t.Fatalf("failure missing from table:\n%s", buf.String())
+	}
+
+	for _, args := range [][]string{
+		{"bench", "1.21.8", "--", "go", "build"},
+		{"bench", "1.21.8", "1.22.3", "go", "build"},
+		{"bench", "1.21.8", "1.21.8", "--", "go", "build"},
+		{"bench", "1.21.8", "1.20.0", "--", "go", "build"},
+	} {
+		if err := app.Run(args); err == nil {
+			t.Fatalf("%v must fail", args)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/version"
+)
+
+// BundleService 描述离线安装包的创建与应用能力。
+type BundleService interface {
+	Create(w io.Writer, numbers, arches []string) (version.BundleManifest, error)
+	Apply(r io.Reader) (version.BundleResult, error)
+}
+
+// WithBundler 启用 `govm bundle create/apply`。
+func WithBundler(b BundleService) AppOption {
+	return func(a *App) {
+		a.bundler = b
+	}
+}
+
+func (a *App) handleBundle(args []string) error {
+	if a.bundler == nil {
+		return errors.New("bundle command is unavailable")
+	}
+	if len(args) == 0 {
+		return errors.New("bundle command requires a subcommand: create or apply")
+	}
+	switch args[0] {
+	case "create":
+		return a.handleBundleCreate(args[1:])
+	case "apply":
+		return a.handleBundleApply(args[1:])
+	default:
+		return fmt.Errorf("unknown bundle subcommand %q", args[0])
+	}
+}
+
+// handleBundleCreate 在联网机器上下载所选版本并写出离线安装包，先写临时文件，成功后再改名。
+func (a *App) handleBundleCreate(args []string) error {
+	fs := newCommandFlagSet("bundle create")
+	arch := fs.String("arch", runtime.GOARCH, "comma-separated architectures to include")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 2 {
+		return errors.New("bundle create requires a file and at least one version")
+	}
+	target := rest[0]
+	numbers := make([]string, 0, len(rest)-1)
+	for _, input := range rest[1:] {
+		numbers = append(numbers, normalizeVersion(input))
+	}
+	var arches []string
+	for _, item := range strings.Split(*arch, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			arches = append(arches, item)
+		}
+	}
+	if len(arches) == 0 {
+		return errors.New("bundle create: --arch must name at least one architecture")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".govm-bundle-*")
+	if err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	manifest, err := a.bundler.Create(tmp, numbers, arches)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	for _, archive := range manifest.Archives {
+		a.printf("  go%s %s/%s (%s)\n", archive.Version, archive.OS, archive.Arch, formatSize(archive.Size))
+	}
+	a.printf("Bundled %d archive(s) and the release list into %s\n", len(manifest.Archives), target)
+	return nil
+}
+
+// handleBundleApply 在离线机器上把安装包中的归档放入下载缓存，并保存版本列表快照。
+func (a *App) handleBundleApply(args []string) error {
+	fs := newCommandFlagSet("bundle apply")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return errors.New("bundle apply requires a bundle file")
+	}
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+	f, err := os.Open(rest[0])
+	if err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	defer f.Close()
+
+	var result version.BundleResult
+	err = a.withLock(func() error {
+		var err error
+		result, err = a.bundler.Apply(f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	a.printf("Added %d archive(s) from %s to the download cache\n", len(result.Manifest.Archives), rest[0])
+	created := result.Manifest.CreatedAt.Local().Format(time.RFC3339)
+	if result.CatalogSeeded {
+		a.printf("Using the release list from %s as the cached catalog\n", created)
+		return nil
+	}
+	a.printf("Saved the release list from %s to %s; point catalogURL at it with catalog set to \"static\" to use it offline\n", created, result.CatalogPath)
+	return nil
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// CacheService 描述查看与清理本地缓存的能力。
+type CacheService interface {
+	Caches() ([]storage.CacheArea, error)
+	ClearCache(names ...string) ([]string, error)
+}
+
+// WithCaches 启用 `govm cache`。
+func WithCaches(s CacheService) AppOption {
+	return func(a *App) {
+		a.caches = s
+	}
+}
+
+func (a *App) handleCache(args []string) error {
+	if a.caches == nil {
+		return errors.New("cache command is unavailable")
+	}
+	action := "info"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+	switch action {
+	case "info":
+		if len(args) > 0 {
+			return errors.New("cache info takes no arguments")
+		}
+		return a.cacheInfo()
+	case "list":
+		return a.cacheList(args)
+	case "clear":
+		return a.cacheClear(args)
+	default:
+		return fmt.Errorf("unknown cache command %q (use list, info or clear)", action)
+	}
+}
+
+// cacheInfo 输出每类缓存的位置、文件数、总大小与最早写入时间。
+func (a *App) cacheInfo() error {
+	areas, err := a.caches.Caches()
+	if err != nil {
+		return err
+	}
+	tbl := newTable("cache", "files", "size", "oldest", "path")
+	for _, area := range areas {
+		tbl.addRow(area.Name, fmt.Sprint(len(area.Entries)), formatSize(area.Size()), formatAge(area.Oldest()), area.Path)
+	}
+	return tbl.render(a.out)
+}
+
+// cacheList 逐个列出缓存文件，可按类别过滤。
+func (a *App) cacheList(names []string) error {
+	areas, err := a.selectCaches(names)
+	if err != nil {
+		return err
+	}
+	tbl := newTable("cache", "file", "size", "age")
+	for _, area := range areas {
+		for _, e := range area.Entries {
+			tbl.addRow(area.Name, e.Name, formatSize(e.Size), formatAge(e.ModTime))
+		}
+	}
+	if len(tbl.rows) == 0 {
+		a.println("Cache is empty.")
+		return nil
+	}
+	return tbl.render(a.out)
+}
+
+func (a *App) cacheClear(args []string) error {
+	fs := newCommandFlagSet("cache clear")
+	dryRun := fs.Bool("dry-run", false, "print what would be removed")
+	names, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		areas, err := a.selectCaches(names)
+		if err != nil {
+			return err
+		}
+		var size int64
+		for _, area := range areas {
+			size += area.Size()
+			for _, e := range area.Entries {
+				a.printf("Would remove %s/%s\n", area.Name, e.Name)
+			}
+		}
+		a.printf("Would free %s\n", formatSize(size))
+		return nil
+	}
+
+	var removed []string
+	err = a.withLock(func() error {
+		var err error
+		removed, err = a.caches.ClearCache(names...)
+		return err
+	})
+	for _, path := range removed {
+		a.printf("Removed %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		a.println("Cache is already empty.")
+	}
+	return nil
+}
+
+// selectCaches 返回 names 指定的缓存类别，names 为空时返回全部类别。
+func (a *App) selectCaches(names []string) ([]storage.CacheArea, error) {
+	areas, err := a.caches.Caches()
+	if err != nil || len(names) == 0 {
+		return areas, err
+	}
+	var selected []storage.CacheArea
+	for _, name := range names {
+		found := false
+		for _, area := range areas {
+			if area.Name == name {
+				selected = append(selected, area)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown cache %q (use %s, %s or %s)", name, storage.CacheDownloads, storage.CacheCatalog, storage.CacheRegion)
+		}
+	}
+	return selected, nil
+}
+
+// formatAge 以分钟、小时或天为单位输出距今时长，时间未知时返回 "-"。
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
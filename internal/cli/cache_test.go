@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestAppCacheInfoListClear(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+	archive := filepath.Join(root, "downloads", "go1.22.0.linux-amd64.tar.gz")
+	for path, body := range map[string]string{archive: "archive", store.MirrorProbePath(): "{}"} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithCaches(store))
+	run := func(args ...string) string {
+		t.Helper()
+		buf.Reset()
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return buf.String()
+	}
+
+	if out := run("cache", "info"); !strings.Contains(out, "downloads  1") || !strings.Contains(out, "just now") {
+		t.Fatalf("cache info output:\n%s", out)
+	}
+	if out := run("cache", "list", "downloads"); !strings.Contains(out, "go1.22.0.linux-amd64.tar.gz") || strings.Contains(out, "mirror-probe") {
+		t.Fatalf("cache list downloads output:\n%s", out)
+	}
+	if out := run("cache", "clear", "region", "--dry-run"); !strings.Contains(out, "Would remove region/mirror-probe.json") {
+		t.Fatalf("dry-run output:\n%s", out)
+	}
+	if _, err := os.Stat(store.MirrorProbePath()); err != nil {
+		t.Fatalf("dry-run removed the probe cache: %v", err)
+	}
+	if out := run("cache", "clear", "region"); !strings.Contains(out, "Removed "+store.MirrorProbePath()) {
+		t.Fatalf("clear output:\n%s", out)
+	}
+	if err := app.Run([]string{"cache", "list", "nope"}); err == nil {
+		t.Fatal("expected error for unknown cache name")
+	}
+}
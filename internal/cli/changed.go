@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// changedExitFlag 为全局 flag 名：设置后 install/use 实际改变状态时以该退出码结束，未改变时退出码为 0。
+const changedExitFlag = "changed-exit-code"
+
+// WantsChangedExitCode 判断命令行是否启用了 -changed-exit-code；main 据此把警告写到 stdout，保证成功时 stderr 为空。
+func WantsChangedExitCode(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name != arg && (name == changedExitFlag || strings.HasPrefix(name, changedExitFlag+"=")) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateChangedExit 拒绝与失败（1）或 shell 保留值冲突的退出码。
+func validateChangedExit(code int) error {
+	if code == 0 || (code >= 2 && code <= 125) {
+		return nil
+	}
+	return fmt.Errorf("-%s must be between 2 and 125, got %d", changedExitFlag, code)
+}
+
+// reportChange 执行改变状态的命令，启用 -changed-exit-code 时比较前后的已安装版本与当前版本，
+// 有变化则返回只携带退出码的错误，供 Ansible/Salt 的 changed_when 判断。
+func (a *App) reportChange(run func() error) error {
+	if a.changedExit == 0 || a.lister == nil {
+		return run()
+	}
+	before := a.stateFingerprint()
+	if err := run(); err != nil {
+		return err
+	}
+	if a.stateFingerprint() == before {
+		return nil
+	}
+	return silentExit(a.changedExit)
+}
+
+// stateFingerprint 用已安装版本及其路径和当前版本标记概括本地状态。
+func (a *App) stateFingerprint() string {
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return ""
+	}
+	entries := make([]string, 0, len(versions)+1)
+	for _, v := range versions {
+		entries = append(entries, v.Number+"="+v.InstallPath)
+		if v.IsCurrent {
+			entries = append(entries, "current="+v.Number)
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// markingSwitcher 像真实的 Switcher 一样更新 fakeLister 中的当前版本。
+type markingSwitcher struct{ lister *fakeLister }
+
+func (s markingSwitcher) UseVersion(version string) error {
+	for i := range s.lister.local {
+		s.lister.local[i].IsCurrent = s.lister.local[i].Number == version
+	}
+	return nil
+}
+
+func TestChangedExitCode(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeLister{local: []models.Version{
+		{Number: "1.22.0", InstallPath: "/v/go1.22.0", IsCurrent: true},
+		{Number: "1.21.0", InstallPath: "/v/go1.21.0"},
+	}}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, markingSwitcher{lister}, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"-changed-exit-code", "2", "use", "1.22.0"}); err != nil {
+		t.Fatalf("unchanged use must exit 0, got %v", err)
+	}
+	err := app.Run([]string{"-changed-exit-code=2", "use", "1.21.0"})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 || exitErr.Err != nil {
+		t.Fatalf("changed use must exit 2 silently, got %v", err)
+	}
+	if err := app.Run([]string{"use", "1.22.0"}); err != nil {
+		t.Fatalf("without the flag a change still exits 0, got %v", err)
+	}
+	if err := app.Run([]string{"-changed-exit-code", "1", "use", "1.22.0"}); err == nil {
+		t.Fatal("exit code 1 is reserved for failures")
+	}
+}
+
+func TestWantsChangedExitCode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"-changed-exit-code 2 install 1.22.0":   true,
+		"--changed-exit-code=3 use 1.22.0":      true,
+		"install 1.22.0":                        false,
+		"run -- changed-exit-code":              false,
+		"plugin -- --changed-exit-code=2 x":     false,
+		"-porcelain -changed-exit-code 2 use 1": true,
+	}
+	for line, want := range cases {
+		if got := WantsChangedExitCode(strings.Fields(line)); got != want {
+			t.Errorf("WantsChangedExitCode(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/liangyou/govm/internal/version"
+)
+
+// DedupService 描述在已安装版本之间共享相同文件的能力，由 version.Deduplicator 实现。
+type DedupService interface {
+	Dedup(mode string, dryRun bool) (version.DedupReport, error)
+}
+
+// WithDedup 启用 `govm dedup`。
+func WithDedup(d DedupService) AppOption {
+	return func(a *App) {
+		a.dedup = d
+	}
+}
+
+// handleDedup 让各版本中内容相同的文件共享同一份数据，--dry-run 只估算可回收的空间。
+func (a *App) handleDedup(args []string) error {
+	if a.dedup == nil {
+		return errors.New("dedup command is unavailable")
+	}
+	fs := newCommandFlagSet("dedup")
+	dryRun := fs.Bool("dry-run", false, "only report how much space deduplication would reclaim")
+	mode := fs.String("mode", version.DedupAuto, "auto, reflink or hardlink")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return errors.New("dedup takes no arguments")
+	}
+
+	var report version.DedupReport
+	run := func() error {
+		var err error
+		report, err = a.dedup.Dedup(*mode, *dryRun)
+		return err
+	}
+	if *dryRun {
+		err = run()
+	} else {
+		err = a.withLock(run)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, ver := range report.Skipped {
+		a.printf("go%s: %s no file manifest recorded, reinstall to include it\n", ver, colorize("skipped:", colorYellow))
+	}
+	switch {
+	case report.Files == 0:
+		a.printf("No duplicate files to share across %d versions\n", len(report.Versions))
+	case *dryRun:
+		a.printf("%[1]d duplicate files across %[2]d versions; dedup would reclaim %[3]s\n", report.Files, len(report.Versions), formatSize(report.Saved))
+	default:
+		a.printf("Reclaimed %[1]s by sharing %[2]d files across %[3]d versions (%[4]d reflinks, %[5]d hard links)\n",
+			formatSize(report.Saved), report.Files, len(report.Versions), report.Reflinked, report.Hardlinked)
+	}
+	if report.Shared > 0 {
+		a.printf("%d files were already shared\n", report.Shared)
+	}
+	if report.Hardlinked > 0 {
+		a.printf("%s hard-linked files are the same file in every version; editing one in a GOROOT changes it everywhere\n", colorize("note:", colorCyan))
+	}
+	return nil
+}
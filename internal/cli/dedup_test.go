@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/version"
+)
+
+type fakeDedup struct {
+	report version.DedupReport
+	mode   string
+	dryRun bool
+}
+
+func (f *fakeDedup) Dedup(mode string, dryRun bool) (version.DedupReport, error) {
+	f.mode, f.dryRun = mode, dryRun
+	return f.report, nil
+}
+
+func TestAppDedup(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	dedup := &fakeDedup{report: version.DedupReport{
+		Versions:   []string{"1.22.1", "1.22.0"},
+		Files:      3,
+		Saved:      3 << 20,
+		Hardlinked: 3,
+		Skipped:    []string{"1.20.0"},
+	}}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithDedup(dedup))
+
+	if err := app.Run([]string{"dedup", "--dry-run"}); err != nil {
+		t.Fatalf("dedup --dry-run: %v", err)
+	}
+	if dedup.mode != version.DedupAuto || !dedup.dryRun || !strings.Contains(buf.String(), "dedup would reclaim 3.0 MiB") || !strings.Contains(buf.String(), "go1.20.0: ") {
+		t.Fatalf("unexpected dry run (mode %q, dry %v):\n%s", dedup.mode, dedup.dryRun, buf.String())
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"dedup", "--mode", "hardlink"}); err != nil {
+		t.Fatalf("dedup: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Reclaimed 3.0 MiB by sharing 3 files across 2 versions (0 reflinks, 3 hard links)", "editing one in a GOROOT changes it everywhere"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("dedup output missing %q:\n%s", want, out)
+		}
+	}
+	if dedup.mode != version.DedupHardlink || dedup.dryRun {
+		t.Fatalf("Dedup called with %q, dry %v", dedup.mode, dedup.dryRun)
+	}
+}
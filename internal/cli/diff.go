@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/goversion"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ManifestService 提供已安装版本的文件清单。
+type ManifestService interface {
+	LoadManifest(version string) (models.FileManifest, error)
+}
+
+// WithManifests 启用 `govm diff`。
+func WithManifests(m ManifestService) AppOption {
+	return func(a *App) {
+		a.manifests = m
+	}
+}
+
+// ReleaseNotesSource 提供各版本的发布说明摘要，offline 时只使用缓存。
+type ReleaseNotesSource interface {
+	ReleaseNotes(offline bool) ([]models.ReleaseNote, error)
+}
+
+// WithReleaseNotes 启用 `govm diff --notes`。
+func WithReleaseNotes(src ReleaseNotesSource) AppOption {
+	return func(a *App) {
+		a.releaseNotes = src
+	}
+}
+
+func (a *App) handleDiff(args []string) error {
+	fs := newCommandFlagSet("diff")
+	stat := fs.Bool("stat", false, "print only the summary line")
+	notes := fs.Bool("notes", false, "summarize the release notes between the versions instead of comparing files; neither version needs to be installed")
+	offline := fs.Bool("offline", false, "with --notes, use only the cached release notes")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *notes {
+		if len(rest) != 2 {
+			return errors.New("diff --notes requires two versions")
+		}
+		return a.diffNotes(normalizeVersion(rest[0]), normalizeVersion(rest[1]), *stat, *offline)
+	}
+	if a.manifests == nil {
+		return errors.New("diff command is unavailable")
+	}
+	if len(rest) != 2 {
+		return errors.New("diff requires two installed versions")
+	}
+
+	from, err := a.manifests.LoadManifest(normalizeVersion(rest[0]))
+	if err != nil {
+		return a.withNotesHint(err)
+	}
+	to, err := a.manifests.LoadManifest(normalizeVersion(rest[1]))
+	if err != nil {
+		return a.withNotesHint(err)
+	}
+
+	diff := version.DiffManifests(from, to)
+	a.printf("go%s -> go%s: %d added, %d removed, %d changed\n",
+		diff.From, diff.To, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	if *stat {
+		return nil
+	}
+	for _, p := range diff.Added {
+		a.printf("  + %s\n", p)
+	}
+	for _, p := range diff.Removed {
+		a.printf("  - %s\n", p)
+	}
+	for _, p := range diff.Changed {
+		a.printf("  ~ %s\n", p)
+	}
+	return nil
+}
+
+// withNotesHint 在文件清单不可用（通常是版本尚未安装）时提示改用 --notes。
+func (a *App) withNotesHint(err error) error {
+	if a.releaseNotes == nil {
+		return err
+	}
+	return fmt.Errorf("%w (use --notes to compare release notes without installing)", err)
+}
+
+// diffNotes 输出升级路径上的发布说明：to 所在系列中晚于 from 的版本，以及途经的主版本首个发布；
+// from 所在旧系列的后续补丁通常也已包含在新系列中，不再列出。
+func (a *App) diffNotes(from, to string, stat, offline bool) error {
+	if a.releaseNotes == nil {
+		return errors.New("diff --notes is unavailable")
+	}
+	if goversion.Compare(from, to) > 0 {
+		from, to = to, from
+	}
+	all, err := a.releaseNotes.ReleaseNotes(offline)
+	if err != nil {
+		return err
+	}
+	var notes []models.ReleaseNote
+	security := 0
+	for _, n := range all {
+		if goversion.Compare(n.Version, from) <= 0 || goversion.Compare(n.Version, to) > 0 {
+			continue
+		}
+		series := goversion.Series(n.Version)
+		if series != goversion.Series(to) && n.Version != series && n.Version != series+".0" {
+			continue
+		}
+		if n.Security {
+			security++
+		}
+		notes = append(notes, n)
+	}
+	slices.SortFunc(notes, func(x, y models.ReleaseNote) int { return goversion.Compare(x.Version, y.Version) })
+
+	a.printf("go%[1]s -> go%[2]s: %[3]d release(s), %[4]d with security fixes\n", from, to, len(notes), security)
+	if stat {
+		return nil
+	}
+	for _, n := range notes {
+		label := n.Date
+		if n.Security {
+			label += ", " + a.tr("security fixes")
+		}
+		a.printf("\ngo%[1]s (%[2]s)\n", n.Version, label)
+		fmt.Fprintf(a.out, "  %s\n  %s\n", n.Summary, n.URL)
+	}
+	return nil
+}
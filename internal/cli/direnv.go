@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// handleDirenv 输出可放入 .envrc 的配置块：未指定版本时使用当前目录向上找到的 .govm-version；--write 时合并进 .envrc。
+func (a *App) handleDirenv(args []string) error {
+	if a.lister == nil {
+		return errors.New("direnv command is unavailable")
+	}
+	fs := newCommandFlagSet("direnv")
+	write := fs.Bool("write", false, "merge the snippet into .envrc next to .govm-version (or in the current directory)")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("direnv: %w", err)
+	}
+	var number string
+	switch len(rest) {
+	case 0:
+		pin, path, err := version.FindPin(dir)
+		if err != nil {
+			return err
+		}
+		if pin == "" {
+			return fmt.Errorf("direnv: no version given and no %s found; run `govm use --local <version>` first", version.PinFile)
+		}
+		number, dir = pin, filepath.Dir(path)
+	case 1:
+		number = normalizeVersion(rest[0])
+	default:
+		return errors.New("direnv takes at most one version")
+	}
+	goRoot, err := a.installedRoot(number)
+	if err != nil {
+		return err
+	}
+	block := env.DirenvBlock(goRoot, a.goToolchain)
+	if !*write {
+		fmt.Fprintln(a.out, block)
+		return nil
+	}
+	path := filepath.Join(dir, env.EnvrcFile)
+	if _, err := a.writeEnvrc(path, block, true); err != nil {
+		return err
+	}
+	a.printf("Wrote %s; run `direnv allow` to load it\n", path)
+	return nil
+}
+
+// handleUseLocal 把版本固定到当前目录的 .govm-version，不改变全局当前版本；目录中的 .envrc 已有 govm 配置块时一并更新。
+func (a *App) handleUseLocal(ver string, strict bool) error {
+	if a.lister == nil {
+		return errors.New("use command is unavailable")
+	}
+	number := normalizeVersion(ver)
+	if goversion.IsConstraint(number) {
+		resolved, err := a.resolveRange(number, true)
+		if err != nil {
+			return err
+		}
+		number = resolved
+	}
+	if err := a.checkPolicy(number); err != nil {
+		return err
+	}
+	goRoot, err := a.installedRoot(number)
+	if err != nil {
+		return err
+	}
+	if strict {
+		if err := a.checkModule(number, true); err != nil {
+			return err
+		}
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("use: %w", err)
+	}
+	path, err := version.WritePin(dir, number)
+	if err != nil {
+		return err
+	}
+	a.printf("Pinned go%[1]s in %[2]s\n", number, path)
+	envrc := filepath.Join(dir, env.EnvrcFile)
+	updated, err := a.writeEnvrc(envrc, env.DirenvBlock(goRoot, a.goToolchain), false)
+	if err != nil {
+		return err
+	}
+	if updated {
+		a.printf("Updated %s; run `direnv allow` to reload it\n", envrc)
+	}
+	return a.checkModule(number, false)
+}
+
+// installedRoot 返回已安装版本的 GOROOT，未安装时给出安装建议。
+func (a *App) installedRoot(number string) (string, error) {
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return "", err
+	}
+	if root := findInstallPath(local, number); root != "" {
+		return root, nil
+	}
+	return "", a.withLocalSuggestion(fmt.Errorf("go%s is not installed (run `govm install %s`)", number, number), number)
+}
+
+// writeEnvrc 用 block 替换 path 中的 govm 配置块并保留其余内容与文件权限；create 为 false 时只更新已有配置块的文件。
+func (a *App) writeEnvrc(path, block string, create bool) (bool, error) {
+	mode := os.FileMode(0o644)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return false, fmt.Errorf("direnv: read %s: %w", path, err)
+	}
+	if !create && !env.HasBlock(string(data)) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(env.MergeBlock(string(data), block)), mode); err != nil {
+		return false, fmt.Errorf("direnv: write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestUseLocalPinsAndKeepsEnvrcInSync(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+
+	lister := &fakeLister{local: []models.Version{
+		{Number: "1.22.0", InstallPath: "/v/go1.22.0", IsCurrent: true},
+		{Number: "1.21.8", InstallPath: "/v/go1.21.8"},
+	}}
+	switcher := &fakeSwitcher{}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test")
+	app.getenv = func(string) string { return "" }
+
+	if err := app.Run([]string{"direnv"}); err == nil || !strings.Contains(err.Error(), "no version given and no .govm-version found") {
+		t.Fatalf("direnv without a pin must fail, got %v", err)
+	}
+	if err := app.Run([]string{"use", "--local", "1.20.0"}); err == nil {
+		t.Fatal("pinning a version that is not installed must fail")
+	}
+	if err := app.Run([]string{"use", "--local", "1.21.8"}); err != nil {
+		t.Fatalf("use --local: %v", err)
+	}
+	if len(switcher.used) != 0 {
+		t.Fatalf("use --local must not switch globally: %v", switcher.used)
+	}
+	if data, _ := os.ReadFile(filepath.Join(project, ".govm-version")); string(data) != "1.21.8\n" {
+		t.Fatalf("unexpected pin: %q", data)
+	}
+
+	sub := filepath.Join(project, "internal")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	buf.Reset()
+	if err := app.Run([]string{"direnv"}); err != nil {
+		t.Fatalf("direnv: %v", err)
+	}
+	if !strings.Contains(buf.String(), "export GOROOT=\"/v/go1.21.8\"\nPATH_add \"$GOROOT/bin\"\n") {
+		t.Fatalf("unexpected snippet:\n%s", buf.String())
+	}
+	if err := app.Run([]string{"direnv", "--write"}); err != nil {
+		t.Fatalf("direnv --write: %v", err)
+	}
+	envrc := filepath.Join(project, ".envrc")
+	written, _ := os.ReadFile(envrc)
+	if err := os.WriteFile(envrc, append([]byte("dotenv\n"), written...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir(project)
+	buf.Reset()
+	if err := app.Run([]string{"use", "--local", "1.22.0"}); err != nil {
+		t.Fatalf("use --local: %v", err)
+	}
+	updated, _ := os.ReadFile(envrc)
+	if data := string(updated); !strings.HasPrefix(data, "dotenv\n") || !strings.Contains(data, `export GOROOT="/v/go1.22.0"`) || strings.Contains(data, "go1.21.8") {
+		t.Fatalf(".envrc not updated in place:\n%s", updated)
+	}
+	if !strings.Contains(buf.String(), "run `direnv allow` to reload it") {
+		t.Fatalf("missing direnv allow hint:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envDoc 描述 govm 读取的一个环境变量，写入生成文档的 ENVIRONMENT 一节。
+type envDoc struct {
+	Name  string
+	Usage string
+}
+
+var environmentDocs = []envDoc{
+	{Name: "GOVM_HOME", Usage: "keep all govm data, caches and config in this directory"},
+	{Name: "GOVM_CONFIG", Usage: "path of the config file"},
+	{Name: "GOVM_SYSCONFDIR", Usage: "directory holding the system-wide govm/config.json (default /etc)"},
+	{Name: "GOVM_POLICY", Usage: "path of the team policy file, overrides the config file"},
+	{Name: "GOVM_SYSTEM", Usage: "set to 1 or true to use the shared multi-user install"},
+	{Name: "GOVM_REGION", Usage: "download source (auto, global, cn) instead of detecting it"},
+	{Name: "GOVM_LANG", Usage: "output language (en, zh-CN), takes precedence over LC_ALL, LC_MESSAGES and LANG"},
+}
+
+// docFormats 为 `govm gen-docs` 支持的格式及其文件扩展名。
+var docFormats = map[string]string{"man": ".1", "markdown": ".md"}
+
+func (a *App) handleGenDocs(args []string) error {
+	fs := newCommandFlagSet("gen-docs")
+	dir := fs.String("dir", "", "write one file per command into this directory instead of printing a single document")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("gen-docs requires a format: man or markdown")
+	}
+	format := rest[0]
+	ext, ok := docFormats[format]
+	if !ok {
+		return fmt.Errorf("unknown docs format %q (use man or markdown)", format)
+	}
+	render := a.writeManPage
+	if format == "markdown" {
+		render = a.writeMarkdown
+	}
+	if *dir == "" {
+		return render(a.out, nil)
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fmt.Errorf("gen-docs: %w", err)
+	}
+	// nil 表示总览页 govm，其后每个命令一页。
+	pages := []*commandDoc{nil}
+	for i := range commandDocs {
+		pages = append(pages, &commandDocs[i])
+	}
+	for _, doc := range pages {
+		name := "govm"
+		if doc != nil {
+			name = docPageName(*doc)
+		}
+		path := filepath.Join(*dir, name+ext)
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("gen-docs: %w", err)
+		}
+		err = render(f, doc)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("gen-docs: write %s: %w", path, err)
+		}
+		a.printf("Wrote %s\n", path)
+	}
+	return nil
+}
+
+// docPageName 返回单个命令的文档名，例如 govm-install；-list 等全局 flag 形式的命令同样以名称命名。
+func docPageName(doc commandDoc) string {
+	return "govm-" + doc.Name
+}
+
+// docDate 返回文档日期；设置 SOURCE_DATE_EPOCH 时使用它，便于发行版得到可复现的输出。
+func (a *App) docDate() string {
+	t := time.Now()
+	if v := strings.TrimSpace(a.getenv("SOURCE_DATE_EPOCH")); v != "" {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			t = time.Unix(sec, 0)
+		}
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+// writeManPage 输出 roff 格式的手册页；doc 为 nil 时输出包含全部命令的 govm(1)，否则输出单个命令的页面。
+func (a *App) writeManPage(w io.Writer, doc *commandDoc) error {
+	var b strings.Builder
+	title, name := "GOVM", "govm"
+	if doc != nil {
+		name = docPageName(*doc)
+		title = strings.ToUpper(name)
+	}
+	fmt.Fprintf(&b, ".TH %s 1 %q %q \"govm manual\"\n", title, a.docDate(), "govm "+a.version)
+	b.WriteString(".SH NAME\n")
+	if doc == nil {
+		b.WriteString("govm \\- Go version manager\n")
+	} else {
+		fmt.Fprintf(&b, "%s \\- %s\n", roffEscape(name), roffEscape(doc.Summary))
+	}
+
+	b.WriteString(".SH SYNOPSIS\n")
+	if doc == nil {
+		b.WriteString(".B govm\n[\\fIglobal flags\\fR] \\fIcommand\\fR [\\fIargs\\fR]\n")
+	} else {
+		for i, usage := range doc.Usage {
+			if i > 0 {
+				b.WriteString(".br\n")
+			}
+			fmt.Fprintf(&b, ".B govm\n%s\n", roffEscape(usage))
+		}
+	}
+
+	b.WriteString(".SH DESCRIPTION\n")
+	if doc == nil {
+		b.WriteString("govm installs, switches and removes Go toolchains.\n")
+		b.WriteString(".SH COMMANDS\n")
+		for _, c := range commandDocs {
+			fmt.Fprintf(&b, ".TP\n.B govm %s\n%s\n", roffEscape(c.Usage[0]), roffEscape(c.Summary))
+		}
+		b.WriteString(".SH GLOBAL FLAGS\n")
+		writeManFlags(&b, globalFlagDocs, "-")
+		b.WriteString(".SH ENVIRONMENT\n")
+		for _, e := range environmentDocs {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", e.Name, roffEscape(e.Usage))
+		}
+		b.WriteString(".SH SEE ALSO\n")
+		refs := make([]string, len(commandDocs))
+		for i, c := range commandDocs {
+			refs[i] = ".BR " + docPageName(c) + " (1)"
+		}
+		b.WriteString(strings.Join(refs, ",\n") + "\n")
+	} else {
+		b.WriteString(roffEscape(doc.Summary) + ".\n")
+		if doc.Description != "" {
+			b.WriteString(".PP\n" + roffEscape(doc.Description) + "\n")
+		}
+		if len(doc.Flags) > 0 {
+			b.WriteString(".SH FLAGS\n")
+			writeManFlags(&b, doc.Flags, doc.flagPrefix())
+		}
+		if len(doc.Examples) > 0 {
+			b.WriteString(".SH EXAMPLES\n.nf\n")
+			for _, example := range doc.Examples {
+				b.WriteString(roffEscape(example) + "\n")
+			}
+			b.WriteString(".fi\n")
+		}
+		b.WriteString(".SH SEE ALSO\n.BR govm (1)\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeManFlags(b *strings.Builder, flags []flagDoc, prefix string) {
+	for _, f := range flags {
+		fmt.Fprintf(b, ".TP\n.B %s", roffEscape(flagName(f, prefix)))
+		if f.Arg != "" {
+			fmt.Fprintf(b, " \\fI%s\\fR", roffEscape(f.Arg))
+		}
+		fmt.Fprintf(b, "\n%s\n", roffEscape(f.Usage))
+	}
+}
+
+// roffEscape 转义反斜杠与连字符，并避免以 . 或 ' 开头的行被当作 roff 请求。
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeMarkdown 输出 Markdown 文档；doc 为 nil 时输出包含全部命令的完整参考。
+func (a *App) writeMarkdown(w io.Writer, doc *commandDoc) error {
+	var b strings.Builder
+	if doc != nil {
+		writeMarkdownCommand(&b, *doc, "#")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	b.WriteString("# govm\n\nGo version manager.\n\n```\ngovm [global flags] <command> [args]\n```\n\n## Commands\n\n")
+	for _, c := range commandDocs {
+		fmt.Fprintf(&b, "- [`govm %s`](#%s): %s\n", c.Usage[0], markdownAnchor(docPageName(c)), c.Summary)
+	}
+	b.WriteString("\n## Global flags\n\n")
+	writeMarkdownFlags(&b, globalFlagDocs, "-")
+	b.WriteString("\n## Environment\n\n| Variable | Description |\n| --- | --- |\n")
+	for _, e := range environmentDocs {
+		fmt.Fprintf(&b, "| `%s` | %s |\n", e.Name, markdownCell(e.Usage))
+	}
+	for _, c := range commandDocs {
+		b.WriteString("\n")
+		writeMarkdownCommand(&b, c, "##")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownCommand(b *strings.Builder, doc commandDoc, heading string) {
+	fmt.Fprintf(b, "%s %s\n\n%s.\n\n```\n", heading, docPageName(doc), doc.Summary)
+	for _, usage := range doc.Usage {
+		b.WriteString("govm " + usage + "\n")
+	}
+	b.WriteString("```\n")
+	if doc.Description != "" {
+		b.WriteString("\n" + doc.Description + "\n")
+	}
+	if len(doc.Flags) > 0 {
+		fmt.Fprintf(b, "\n%s# Flags\n\n", heading)
+		writeMarkdownFlags(b, doc.Flags, doc.flagPrefix())
+	}
+	if len(doc.Examples) > 0 {
+		fmt.Fprintf(b, "\n%s# Examples\n\n```sh\n%s\n```\n", heading, strings.Join(doc.Examples, "\n"))
+	}
+}
+
+func writeMarkdownFlags(b *strings.Builder, flags []flagDoc, prefix string) {
+	b.WriteString("| Flag | Description |\n| --- | --- |\n")
+	for _, f := range flags {
+		name := flagName(f, prefix)
+		if f.Arg != "" {
+			name += " " + f.Arg
+		}
+		fmt.Fprintf(b, "| `%s` | %s |\n", markdownCell(name), markdownCell(f.Usage))
+	}
+}
+
+// markdownCell 转义表格单元格中的竖线。
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// markdownAnchor 返回与 GitHub 标题锚点一致的写法。
+func markdownAnchor(heading string) string {
+	return strings.ToLower(strings.ReplaceAll(heading, " ", "-"))
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppGenDocs(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "1.2.3")
+	app.getenv = func(key string) string {
+		if key == "SOURCE_DATE_EPOCH" {
+			return "1700000000"
+		}
+		return ""
+	}
+
+	if err := app.Run([]string{"gen-docs", "man"}); err != nil {
+		t.Fatalf("gen-docs man failed: %v", err)
+	}
+	man := buf.String()
+	for _, want := range []string{`.TH GOVM 1 "2023-11-14" "govm 1.2.3"`, ".SH COMMANDS", `.B govm uninstall <version> [\-\-yes]`, ".B GOVM_LANG", ".BR govm-install (1)"} {
+		if !strings.Contains(man, want) {
+			t.Fatalf("man page is missing %q:\n%s", want, man)
+		}
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"gen-docs", "markdown"}); err != nil {
+		t.Fatalf("gen-docs markdown failed: %v", err)
+	}
+	md := buf.String()
+	for _, doc := range commandDocs {
+		if !strings.Contains(md, "## "+docPageName(doc)+"\n") {
+			t.Errorf("markdown is missing a section for %s", doc.Name)
+		}
+	}
+	if !strings.Contains(md, "| `--format json\\|yaml` |") {
+		t.Fatalf("pipes in table cells must be escaped:\n%s", md)
+	}
+
+	dir := filepath.Join(t.TempDir(), "man1")
+	if err := app.Run([]string{"gen-docs", "man", "--dir", dir}); err != nil {
+		t.Fatalf("gen-docs --dir failed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(commandDocs)+1 {
+		t.Fatalf("expected %d pages, got %d", len(commandDocs)+1, len(entries))
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "govm-install.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), ".SH FLAGS") || !strings.Contains(string(page), `.B \-\-jobs \fIN\fR`) {
+		t.Fatalf("unexpected install page:\n%s", page)
+	}
+
+	if err := app.Run([]string{"gen-docs", "html"}); err == nil {
+		t.Fatal("unknown format must fail")
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	t.Parallel()
+
+	if got := roffEscape(`.hidden --flag C:\path`); got != `\&.hidden \-\-flag C:\epath` {
+		t.Fatalf("roffEscape = %q", got)
+	}
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// doctorIssue 是 doctor 发现的一条问题，warn 为 true 时不影响退出码。
+type doctorIssue struct {
+	warn bool
+	text string
+}
+
+func (a *App) handleDoctor(args []string) error {
+	if a.lister == nil {
+		return errors.New("doctor command is unavailable")
+	}
+	fs := newCommandFlagSet("doctor")
+	repair := fs.Bool("repair", false, "fix a current version marker that disagrees with metadata")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	// 先重建损坏的元数据，再处理标记：修复后其余检查看到的已是一致的状态。
+	issues, err := a.doctorDamage(*repair)
+	if err != nil {
+		return err
+	}
+	markerIssues, err := a.doctorMarker(*repair)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, markerIssues...)
+	signatureIssues, err := a.doctorSignature(*repair)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, signatureIssues...)
+	installIssues, err := a.doctorInstalls()
+	if err != nil {
+		return err
+	}
+	issues = append(issues, installIssues...)
+	pathIssues, err := a.doctorPath()
+	if err != nil {
+		return err
+	}
+	issues = append(issues, pathIssues...)
+	toolchainIssues, err := a.doctorToolchain()
+	if err != nil {
+		return err
+	}
+	issues = append(issues, toolchainIssues...)
+	if len(issues) == 0 {
+		a.printf("%s no problems found\n", colorize("OK", colorBoldGreen))
+		return nil
+	}
+
+	failures := 0
+	for _, issue := range issues {
+		label := colorize("warning:", colorYellow)
+		if !issue.warn {
+			label = colorize("problem:", colorYellow)
+			failures++
+		}
+		a.printf("%s %s\n", label, issue.text)
+	}
+	if failures > 0 {
+		return fmt.Errorf("doctor: found %d problem(s)", failures)
+	}
+	return nil
+}
+
+// doctorInstalls 对照安装清单检查每个版本的 GOROOT，报告被改动、缺失以及用户自行添加的文件。
+func (a *App) doctorInstalls() ([]doctorIssue, error) {
+	if a.verifier == nil {
+		return nil, nil
+	}
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []doctorIssue
+	for _, v := range versions {
+		if v.External {
+			continue
+		}
+		report, err := a.verifier.Verify(v.Number)
+		if errors.Is(err, storage.ErrNoManifest) {
+			issues = append(issues, doctorIssue{warn: true, text: fmt.Sprintf("go%s has no file manifest, reinstall to enable integrity checks", v.Number)})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !report.OK() {
+			issues = append(issues, doctorIssue{text: fmt.Sprintf("go%s: %d modified, %d missing files (run `govm verify %s` for details)",
+				v.Number, len(report.Modified), len(report.Missing), v.Number)})
+		}
+		for _, p := range report.Added {
+			issues = append(issues, doctorIssue{warn: true, text: fmt.Sprintf("go%s: user-added file in GOROOT: %s", v.Number, p)})
+		}
+	}
+	return issues, nil
+}
+
+// doctorPath 按 PATH 顺序解析 go，报告遮蔽当前版本的其他安装以及尚未生效的 shell 配置。
+func (a *App) doctorPath() ([]doctorIssue, error) {
+	current, err := a.lister.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.InstallPath == "" {
+		return nil, nil
+	}
+	check := env.CheckPath(a.getenv("PATH"), env.ManagedBy(filepath.Dir(current.InstallPath)))
+	var issues []doctorIssue
+	for _, bin := range check.Shadows {
+		issues = append(issues, doctorIssue{text: fmt.Sprintf("%s shadows go%s in PATH; remove %s from PATH or load the govm block in your shell config after it",
+			bin, current.Number, filepath.Dir(bin))})
+	}
+	if !check.Managed {
+		resolved := check.Resolved
+		if resolved == "" {
+			resolved = "nothing"
+		}
+		issues = append(issues, doctorIssue{warn: true, text: fmt.Sprintf("go%s is not on PATH in this shell (go resolves to %s); open a new shell or source your shell config",
+			current.Number, resolved)})
+	}
+	return issues, nil
+}
+
+// doctorToolchain 检查当前版本看到的 GOTOOLCHAIN：允许自动下载时，go.mod/go.work 要求更高版本会让 go 命令
+// 把工具链下载到 GOMODCACHE 并改用它，govm 管理的版本被悄悄绕过。Go 1.21 之前的版本不认识 GOTOOLCHAIN，不做检查。
+func (a *App) doctorToolchain() ([]doctorIssue, error) {
+	current, err := a.lister.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.InstallPath == "" || goversion.Compare(current.Number, "1.21") < 0 {
+		return nil, nil
+	}
+	setting := env.ResolveToolchain(current.InstallPath, a.getenv)
+	if a.goToolchain != "" && setting.Value != a.goToolchain {
+		return []doctorIssue{{warn: true, text: fmt.Sprintf("goToolchain is %q in the govm config but this shell has GOTOOLCHAIN=%q; open a new shell or source your shell config",
+			a.goToolchain, setting.Value)}}, nil
+	}
+	if !setting.Downloads() {
+		return nil, nil
+	}
+	text := fmt.Sprintf("GOTOOLCHAIN=%s (from %s) lets the go command download its own toolchain into GOMODCACHE whenever a go.mod or go.work asks for a newer Go, bypassing govm; "+
+		"set \"goToolchain\": \"local\" in the govm config to keep go%s, or run `govm sync` in the module", setting.Value, setting.Source, current.Number)
+	if req := a.moduleRequirement(); req != nil && !req.Satisfied(current.Number) {
+		minimum, directive := req.Minimum()
+		text += fmt.Sprintf(" (the %s %s directive in %s already triggers this)", directive, minimum, req.Path)
+	}
+	return []doctorIssue{{warn: true, text: text}}, nil
+}
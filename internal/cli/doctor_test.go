@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestAppDoctorReportsPathShadowing(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	installPath := filepath.Join(root, "versions", "go1.22.0")
+	system := filepath.Join(root, "usr", "bin")
+	for _, dir := range []string{filepath.Join(installPath, "bin"), system} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{current: &models.Version{Number: "1.22.0", InstallPath: installPath}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	app.getenv = func(string) string {
+		return system + string(os.PathListSeparator) + filepath.Join(installPath, "bin")
+	}
+
+	if err := app.Run([]string{"doctor"}); err == nil {
+		t.Fatal("expected shadowing to be reported as a problem")
+	}
+	if !strings.Contains(buf.String(), filepath.Join(system, "go")+" shadows go1.22.0 in PATH") {
+		t.Fatalf("doctor output missing shadowing:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	app.getenv = func(string) string { return system }
+	if err := app.Run([]string{"doctor"}); err != nil {
+		t.Fatalf("stale shell should only warn: %v", err)
+	}
+	if !strings.Contains(buf.String(), "go1.22.0 is not on PATH in this shell") {
+		t.Fatalf("doctor output missing reload hint:\n%s", buf.String())
+	}
+}
+
+// stubMarkers 记录修复调用，修复后不再报告问题。
+type stubMarkers struct {
+	issue    *version.MarkerIssue
+	repaired bool
+}
+
+func (s *stubMarkers) CheckMarker() (*version.MarkerIssue, error) {
+	return s.issue, nil
+}
+
+func (s *stubMarkers) RepairMarker(issue version.MarkerIssue) (string, error) {
+	s.repaired, s.issue = true, nil
+	return version.RepairCleared, nil
+}
+
+func TestAppMarkerHealth(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	markers := &stubMarkers{issue: &version.MarkerIssue{Kind: version.MarkerUnregistered, Version: "1.22.0"}}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithMarkerHealth(markers))
+	app.getenv = func(string) string { return "" }
+
+	for _, args := range [][]string{{"-list"}, {"current"}} {
+		buf.Reset()
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		if !strings.Contains(buf.String(), "current version marker names go1.22.0, which is not installed (run `govm doctor --repair` to fix)") {
+			t.Fatalf("%v output missing marker warning:\n%s", args, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"current", "--quiet"}); err == nil {
+		t.Fatal("current --quiet without an active version must exit 1")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("bare output must stay free of warnings:\n%s", buf.String())
+	}
+
+	if err := app.Run([]string{"doctor"}); err == nil {
+		t.Fatal("an inconsistent marker must be reported as a problem")
+	}
+	if markers.repaired {
+		t.Fatal("doctor must not repair without --repair")
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"doctor", "--repair"}); err != nil {
+		t.Fatalf("doctor --repair: %v", err)
+	}
+	if !markers.repaired || !strings.Contains(buf.String(), "cleared the marker") {
+		t.Fatalf("marker not repaired:\n%s", buf.String())
+	}
+}
+
+func TestAppMetadataSignature(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, SignMetadata: true})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "metadata.json"), []byte(`{"schemaVersion":3,"versions":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithMetadataSignature(store))
+	app.getenv = func(string) string { return "" }
+	if err := app.Run([]string{"-list"}); err != nil {
+		t.Fatalf("-list: %v", err)
+	}
+	if !strings.Contains(buf.String(), "metadata.json was modified outside govm") {
+		t.Fatalf("-list output missing tamper warning:\n%s", buf.String())
+	}
+	if err := app.Run([]string{"doctor"}); err == nil {
+		t.Fatal("an edited metadata.json must be reported as a problem")
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"doctor", "--repair"}); err != nil {
+		t.Fatalf("doctor --repair: %v", err)
+	}
+	if !strings.Contains(buf.String(), "signed them again") {
+		t.Fatalf("metadata not accepted:\n%s", buf.String())
+	}
+	if err := store.VerifyMetadata(); err != nil {
+		t.Fatalf("accepted metadata must verify: %v", err)
+	}
+}
+
+func TestAppDoctorRebuildsCorruptMetadata(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "metadata.json"), []byte(`{"versions":[{"Number":"1.22.0"},`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithMetadataRepair(store))
+	app.getenv = func(string) string { return "" }
+	if err := app.Run([]string{"-list"}); err != nil {
+		t.Fatalf("-list: %v", err)
+	}
+	if !strings.Contains(buf.String(), "showing 1 salvaged entries read-only") {
+		t.Fatalf("-list output missing corruption warning:\n%s", buf.String())
+	}
+	if err := app.Run([]string{"doctor"}); err == nil {
+		t.Fatal("corrupt metadata must be reported as a problem")
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"doctor", "--repair"}); err != nil {
+		t.Fatalf("doctor --repair: %v", err)
+	}
+	if !strings.Contains(buf.String(), "rebuilt metadata.json with 1 entries") || store.MetadataDamage() != nil {
+		t.Fatalf("metadata not rebuilt:\n%s", buf.String())
+	}
+}
+
+func TestAppDoctorExplainsGoToolchain(t *testing.T) {
+	root := t.TempDir()
+	installPath := filepath.Join(root, "versions", "go1.22.0")
+	if err := os.MkdirAll(filepath.Join(installPath, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "bin", "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "go.env"), []byte("GOTOOLCHAIN=auto\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	module := filepath.Join(root, "src")
+	if err := os.MkdirAll(module, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/m\n\ngo 1.23.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(module)
+
+	vars := map[string]string{"PATH": filepath.Join(installPath, "bin"), "GOENV": "off"}
+	lister := &fakeLister{current: &models.Version{Number: "1.22.0", InstallPath: installPath}}
+	run := func(opts ...AppOption) string {
+		buf := &bytes.Buffer{}
+		app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", opts...)
+		app.getenv = func(key string) string { return vars[key] }
+		if err := app.Run([]string{"doctor"}); err != nil {
+			t.Fatalf("toolchain findings must only warn: %v", err)
+		}
+		return buf.String()
+	}
+
+	out := run()
+	if !strings.Contains(out, "GOTOOLCHAIN=auto (from "+filepath.Join(installPath, "go.env")+") lets the go command download its own toolchain") ||
+		!strings.Contains(out, "the go 1.23.1 directive in "+filepath.Join(module, "go.mod")+" already triggers this") {
+		t.Fatalf("doctor output missing GOTOOLCHAIN explanation:\n%s", out)
+	}
+	if out := run(WithGoToolchain("local")); !strings.Contains(out, `goToolchain is "local" in the govm config but this shell has GOTOOLCHAIN="auto"`) {
+		t.Fatalf("doctor output missing reload hint:\n%s", out)
+	}
+	vars["GOTOOLCHAIN"] = "local"
+	if out := run(WithGoToolchain("local")); !strings.Contains(out, "no problems found") {
+		t.Fatalf("GOTOOLCHAIN=local must be accepted:\n%s", out)
+	}
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithGoToolchain("local"))
+	app.getenv = func(string) string { return "" }
+	if err := app.Run([]string{"env"}); err != nil || !strings.Contains(buf.String(), "export GOTOOLCHAIN='local'\n") {
+		t.Fatalf("env must export the configured GOTOOLCHAIN: %v\n%s", err, buf.String())
+	}
+}
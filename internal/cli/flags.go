@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"flag"
+	"io"
+)
+
+// newCommandFlagSet 创建子命令使用的 FlagSet，错误由调用方返回而不是直接打印。
+func newCommandFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseCommandFlags 解析子命令参数，允许 flag 与位置参数交替出现，例如 `install 1.22.0 --silent`。
+func parseCommandFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
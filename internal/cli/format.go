@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// formatFuncs 为 --format 模板提供的辅助函数。
+var formatFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseFormat 解析 --format 的 text/template 模板，字段与 models.Version 一致，例如 {{.Number}} {{.InstallPath}}。
+func parseFormat(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(formatFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderFormat 对每个版本执行一次模板，结果不以换行结尾时补上换行，便于逐行处理；输出为空的版本被略过，可用 {{if}} 过滤。
+func (a *App) renderFormat(text string, versions []models.Version) error {
+	tmpl, err := parseFormat(text)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, v := range versions {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, v); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+		if buf.Len() == 0 {
+			continue
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := a.out.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestAppFormatTemplates(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{
+		local:   []models.Version{{Number: "1.21.0", InstallPath: "/v/1.21.0"}, {Number: "1.22.0", InstallPath: "/v/1.22.0", IsCurrent: true}},
+		remote:  []models.Version{{Number: "1.23.0", FileName: "go1.23.0.linux-amd64.tar.gz"}, {Number: "1.22.5"}},
+		current: &models.Version{Number: "1.22.0", FullName: "go1.22.0", InstallPath: "/v/1.22.0"},
+	}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-list", "-format", "{{.Number}} {{.InstallPath}}"}, "1.21.0 /v/1.21.0\n1.22.0 /v/1.22.0\n"},
+		{[]string{"-format", "{{if .IsCurrent}}{{.Number}}\n{{end}}", "-list"}, "1.22.0\n"},
+		{[]string{"-remote", "-limit", "1", "--format", "{{.FileName}}"}, "go1.23.0.linux-amd64.tar.gz\n"},
+		{[]string{"current", "--format", "{{.FullName | upper}}"}, "GO1.22.0\n"},
+		{[]string{"current", "--format", `{{json .Number}}`}, "\"1.22.0\"\n"},
+	}
+	for _, tc := range cases {
+		buf.Reset()
+		if err := app.Run(tc.args); err != nil {
+			t.Fatalf("%v failed: %v", tc.args, err)
+		}
+		if buf.String() != tc.want {
+			t.Fatalf("%v printed %q, want %q", tc.args, buf.String(), tc.want)
+		}
+	}
+
+	if err := app.Run([]string{"-list", "-format", "{{.Number"}); err == nil || !strings.Contains(err.Error(), "invalid --format template") {
+		t.Fatalf("expected template parse error, got %v", err)
+	}
+	if err := app.Run([]string{"-list", "-format", "{{.Missing}}"}); err == nil {
+		t.Fatal("unknown field must fail")
+	}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/internal/env"
+)
+
+// GoCacheUsage 描述 go 命令自身维护的缓存（GOCACHE、GOMODCACHE），它们不属于 govm 的安装目录。
+type GoCacheUsage struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// goCacheNames 为 `govm status` 展示、`govm clean` 可清理的 go 缓存，对应 go clean 的 -cache 与 -modcache。
+var goCacheNames = []string{"GOCACHE", "GOMODCACHE"}
+
+// runGoCommand 以 GOTOOLCHAIN=local 执行 goBin，避免查询缓存时触发工具链下载。
+func runGoCommand(goBin string, stdout io.Writer, args ...string) error {
+	cmd := exec.Command(goBin, args...)
+	cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// activeGoBinary 返回当前生效的 go：优先 PATH 中的第一个 go，其次 govm 的默认版本。
+func (a *App) activeGoBinary() string {
+	if check := env.CheckPath(a.getenv("PATH"), func(string) bool { return false }); check.Resolved != "" {
+		return check.Resolved
+	}
+	if a.lister != nil {
+		if current, err := a.lister.CurrentVersion(); err == nil && current != nil && current.InstallPath != "" {
+			return filepath.Join(current.InstallPath, "bin", "go")
+		}
+	}
+	return ""
+}
+
+// goCacheDirs 通过 `go env` 查询 GOCACHE 与 GOMODCACHE；没有可用的 go 时退回环境变量与 GOPATH 推导的默认值。
+func (a *App) goCacheDirs(goBin string) map[string]string {
+	dirs := map[string]string{}
+	if goBin != "" {
+		var out bytes.Buffer
+		if err := a.runGo(goBin, &out, append([]string{"env"}, goCacheNames...)...); err == nil {
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			for i, name := range goCacheNames {
+				if i < len(lines) {
+					dirs[name] = strings.TrimSpace(lines[i])
+				}
+			}
+			return dirs
+		}
+	}
+	if v := strings.TrimSpace(a.getenv("GOCACHE")); v != "" {
+		dirs["GOCACHE"] = v
+	}
+	dirs["GOMODCACHE"] = a.resolveModCache()
+	return dirs
+}
+
+// goCacheUsage 统计 go 缓存的位置与大小，路径未知或为 off 的缓存被略过。
+func (a *App) goCacheUsage() []GoCacheUsage {
+	dirs := a.goCacheDirs(a.activeGoBinary())
+	usage := []GoCacheUsage{}
+	for _, name := range goCacheNames {
+		path := dirs[name]
+		if path == "" || path == "off" {
+			continue
+		}
+		usage = append(usage, GoCacheUsage{Name: name, Path: path, Size: dirSize(path)})
+	}
+	return usage
+}
+
+// handleClean 把清理请求转交给当前生效的 go clean，并报告释放的空间。
+func (a *App) handleClean(args []string) error {
+	fs := newCommandFlagSet("clean")
+	gocache := fs.Bool("gocache", false, "run go clean -cache to empty the build cache (GOCACHE)")
+	modcache := fs.Bool("modcache", false, "run go clean -modcache to empty the module cache (GOMODCACHE)")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("clean takes no arguments, got %q", rest[0])
+	}
+	if !*gocache && !*modcache {
+		return errors.New("clean: choose what to remove with --gocache and/or --modcache (govm's own downloads are cleared with govm cache clear)")
+	}
+	goBin := a.activeGoBinary()
+	if goBin == "" {
+		return errors.New("clean: no go command found; activate a version with govm use first")
+	}
+	dirs := a.goCacheDirs(goBin)
+	for _, c := range []struct {
+		enabled bool
+		name    string
+		flag    string
+	}{
+		{*gocache, "GOCACHE", "-cache"},
+		{*modcache, "GOMODCACHE", "-modcache"},
+	} {
+		if !c.enabled {
+			continue
+		}
+		if c.name == "GOMODCACHE" {
+			if err := a.checkModCacheAdoptions(dirs[c.name]); err != nil {
+				return err
+			}
+		}
+		before := dirSize(dirs[c.name])
+		if err := a.runGo(goBin, a.out, "clean", c.flag); err != nil {
+			return fmt.Errorf("clean: go clean %s: %w", c.flag, err)
+		}
+		freed := "0 B"
+		if n := before - dirSize(dirs[c.name]); n > 0 {
+			freed = formatSize(n)
+		}
+		a.printf("Freed %s from %s (%s)\n", freed, c.name, dirs[c.name])
+	}
+	return nil
+}
+
+// checkModCacheAdoptions 拒绝清空仍有已登记版本位于其中的模块缓存，否则这些版本（可能正是当前版本）会失去 GOROOT。
+func (a *App) checkModCacheAdoptions(modCache string) error {
+	if a.lister == nil {
+		return nil
+	}
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	var adopted []string
+	for _, v := range versions {
+		if inModCache(v.InstallPath, modCache) {
+			adopted = append(adopted, "go"+v.Number)
+		}
+	}
+	if len(adopted) > 0 {
+		return fmt.Errorf("clean: %s adopted from %s would be deleted; run govm uninstall for them first", strings.Join(adopted, ", "), modCache)
+	}
+	return nil
+}
@@ -0,0 +1,568 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commandDoc 描述一个子命令的帮助信息；概览与 `govm help <command>` 都由它生成，文字经 a.tr 翻译。
+type commandDoc struct {
+	Name        string
+	Aliases     []string
+	Usage       []string // 不含 "govm " 前缀的用法行
+	Summary     string   // 概览中的一行说明
+	Description string   // 详细说明，可以为空
+	Flags       []flagDoc
+	Examples    []string
+}
+
+// flagDoc 描述一个 flag，Arg 为取值的占位名，布尔 flag 为空。
+type flagDoc struct {
+	Name  string
+	Arg   string
+	Usage string
+}
+
+// globalFlagDocs 为放在子命令之前的全局 flag。
+var globalFlagDocs = []flagDoc{
+	{Name: "yes", Usage: "answer yes to every confirmation prompt (for scripts and CI)"},
+	{Name: "porcelain", Usage: "print stable key=value progress events"},
+	{Name: "wide", Usage: "add channel, arch, size and install date columns to version tables"},
+	{Name: "format", Arg: "TEMPLATE", Usage: "print each version of -list/-remote/current with a Go text/template"},
+	{Name: "channel", Arg: "LIST", Usage: "show only versions of these comma-separated channels in -list/-remote (stable, rc, beta, archived)"},
+	{Name: "changed-exit-code", Arg: "N", Usage: "exit N instead of 0 when install/use changed state; warnings go to stdout"},
+	{Name: "verbose", Usage: "after the command, print how long region detection, the release list, downloads and extraction took"},
+	{Name: "connect-timeout", Arg: "DURATION", Usage: "TCP connect and TLS handshake timeout"},
+	{Name: "timeout", Arg: "DURATION", Usage: "total timeout for metadata requests"},
+	{Name: "download-timeout", Arg: "DURATION", Usage: "total timeout for downloading an archive"},
+	{Name: "help", Usage: "show this message"},
+	{Name: "version", Usage: "show govm version"},
+}
+
+// commandDocs 按概览中的展示顺序排列。
+var commandDocs = []commandDoc{
+	{
+		Name:     "init",
+		Usage:    []string{"init [--yes]"},
+		Summary:  "Guided setup: download source, GOPATH, install root, latest stable Go",
+		Flags:    []flagDoc{{Name: "yes", Usage: "accept every default without prompting"}},
+		Examples: []string{"govm init", "govm init --yes"},
+	},
+	{
+		Name:    "list",
+		Usage:   []string{"-list [-channel LIST]"},
+		Summary: "List installed versions",
+		Flags: []flagDoc{
+			{Name: "wide", Usage: "add channel, arch, size and install date columns"},
+			{Name: "format", Arg: "TEMPLATE", Usage: "print each version with a Go text/template"},
+			{Name: "channel", Arg: "LIST", Usage: "show only these comma-separated channels: stable, rc, beta, archived"},
+		},
+		Examples: []string{"govm -list", "govm -wide -list", "govm -list -format '{{.Number}} {{.InstallPath}}'", "govm -list -channel rc,beta"},
+	},
+	{
+		Name:    "remote",
+		Usage:   []string{"-remote [-flat] [-channel LIST] [-limit N [-page N]]"},
+		Summary: "List remote versions grouped by major.minor series",
+		Flags: []flagDoc{
+			{Name: "flat", Usage: "list every remote archive instead of grouping by series"},
+			{Name: "limit", Arg: "N", Usage: "show at most N remote versions per page (0 shows all)"},
+			{Name: "page", Arg: "N", Usage: "page number used with -limit"},
+			{Name: "wide", Usage: "add channel, arch, size and install date columns"},
+			{Name: "format", Arg: "TEMPLATE", Usage: "print each version with a Go text/template"},
+			{Name: "channel", Arg: "LIST", Usage: "show only these comma-separated channels: stable, rc, beta, archived"},
+		},
+		Examples: []string{"govm -remote", "govm -remote -flat", "govm -remote -limit 20 -page 2", "govm -remote -flat -channel stable -limit 1 -format '{{.Number}}'"},
+	},
+	{
+		Name: "install",
+		Usage: []string{
+			"install <version> [flags]",
+			"install <v1> <v2>... [--jobs N] [--fail-fast]",
+			"install -f versions.txt [--skip-existing]",
+			"install go1.22.3.linux-amd64.tar.gz|<https URL> [--sha256 HEX]",
+		},
+		Summary:     "Install one or more versions",
+		Description: "Several versions are downloaded concurrently and summarized in a table. A version range such as 1.22.x or \">=1.21 <1.23\" installs the newest matching stable release. An archive name or URL installs that exact archive; its checksum comes from --sha256 or the release list.",
+		Flags: []flagDoc{
+			{Name: "silent", Usage: "print only the GOROOT path"},
+			{Name: "global-path", Usage: "activate without editing rc files and export to GITHUB_PATH/GITHUB_ENV"},
+			{Name: "download-timeout", Arg: "DURATION", Usage: "total timeout for downloading an archive"},
+			{Name: "no-catalog", Usage: "derive the download URL and fetch the .sha256 file without the version catalog"},
+			{Name: "jobs", Arg: "N", Usage: "number of concurrent downloads when installing several versions"},
+			{Name: "f", Arg: "FILE", Usage: "install every version listed in a file (one per line, YAML or JSON manifest)"},
+			{Name: "skip-existing", Usage: "skip versions that are already installed instead of failing"},
+			{Name: "fail-fast", Usage: "stop starting new installs after the first failure"},
+			{Name: "sha256", Arg: "HEX", Usage: "expected SHA256 when installing from an archive name or URL"},
+		},
+		Examples: []string{
+			"govm install 1.22.3",
+			"govm install 1.21.10 1.22.3 --jobs 2",
+			"govm install 1.22.3 --silent --global-path",
+		},
+	},
+	{
+		Name:        "use",
+		Usage:       []string{"use <version> [--strict] [--local] [--verbose|--confirm]", "use -"},
+		Summary:     "Switch to an installed version, or back to the previous one with -",
+		Description: "A version range such as 1.22.x selects the newest matching installed version. Inside a module or workspace, warns when the version is older than the go/toolchain directive of go.mod or go.work. With --local the version is pinned in .govm-version and a govm block in .envrc is updated to match. --verbose prints a colored diff of the change to .bashrc/.zshrc before writing it; --confirm also asks for confirmation, which -yes answers.",
+		Flags: []flagDoc{
+			{Name: "strict", Usage: "refuse versions older than the go/toolchain directive of the current module"},
+			{Name: "local", Usage: "pin the version in .govm-version in the current directory instead of switching globally"},
+			{Name: "verbose", Usage: "show a diff of the shell config change"},
+			{Name: "confirm", Usage: "show a diff of the shell config change and ask before writing it"},
+		},
+		Examples: []string{"govm use 1.22.3", "govm use 1.22.x", "govm use -", "govm use --local 1.21.8", "govm use --confirm 1.22.3"},
+	},
+	{
+		Name:        "sync",
+		Usage:       []string{"sync [--no-use]"},
+		Summary:     "Install and activate the toolchain selected by go.mod or go.work",
+		Description: "Uses the toolchain directive, or the go directive when there is none, of the current module or workspace; the version is installed first if missing.",
+		Flags:       []flagDoc{{Name: "no-use", Usage: "install the version without activating it"}},
+		Examples:    []string{"govm sync", "govm sync --no-use"},
+	},
+	{
+		Name:        "current",
+		Usage:       []string{"current [--quiet|--path|--format TEMPLATE] [--strict]"},
+		Summary:     "Show the active version",
+		Description: "Inside a module or workspace, warns when the active version is older than the go/toolchain directive of go.mod or go.work.",
+		Flags: []flagDoc{
+			{Name: "quiet", Usage: "print only the version number; exit 1 when none is active"},
+			{Name: "q", Usage: "shorthand for --quiet"},
+			{Name: "path", Usage: "print only the GOROOT of the active version"},
+			{Name: "format", Arg: "TEMPLATE", Usage: "print the active version with a Go text/template"},
+			{Name: "strict", Usage: "exit non-zero when the active version is older than the current module requires"},
+		},
+		Examples: []string{"govm current", "govm current --quiet", "govm current --format '{{.FullName}}'", "govm current --quiet --strict"},
+	},
+	{
+		Name:        "prompt",
+		Usage:       []string{"prompt [--number]"},
+		Summary:     "Print the active version compactly for shell prompts and tmux status lines",
+		Description: "Reads only the current version marker; its location is cached and the config is loaded again only when GOVM_HOME, GOVM_CONFIG and similar variables or the config file change. Prints nothing and exits 0 when no version is active or the state cannot be read. GOVM_VERSION takes precedence, as it does for the go shim.",
+		Flags:       []flagDoc{{Name: "number", Usage: "print only the version number, without the go prefix"}},
+		Examples:    []string{`PS1='$(govm prompt) '"$PS1"`, "set -g status-right '#(govm prompt)'"},
+	},
+	{
+		Name:        "assert",
+		Usage:       []string{"assert [--quiet] <constraint>"},
+		Summary:     "Exit non-zero unless the active version satisfies a constraint",
+		Description: "Conditions separated by spaces or commas must all hold; alternatives are separated by ||. Supports >=, >, <=, <, = and != comparisons, series wildcards such as 1.22.x or 1.22, ~1.22.3 for patch releases from 1.22.3, and exact versions. Prereleases match only when a condition names one of the same release, e.g. >=1.23rc1. Intended as a guard step in CI pipelines and git hooks.",
+		Flags: []flagDoc{
+			{Name: "quiet", Usage: "print nothing when the active version matches"},
+			{Name: "q", Usage: "shorthand for --quiet"},
+		},
+		Examples: []string{"govm assert 1.22.x", "govm assert '>=1.21 <1.23'", "govm assert --quiet '>=1.22, !=1.22.0'"},
+	},
+	{
+		Name:     "status",
+		Usage:    []string{"status [--json]"},
+		Summary:  "Summarize active/default version, installs, disk usage, mirror, cache, Go build caches and updates",
+		Flags:    []flagDoc{{Name: "json", Usage: "print the summary as JSON"}},
+		Examples: []string{"govm status", "govm status --json"},
+	},
+	{
+		Name:        "watch",
+		Usage:       []string{"watch [--interval D] [--json] [--exec CMD]"},
+		Summary:     "Print the current version, then a line every time it changes",
+		Description: "Prints an empty line when no version is active. Runs until interrupted; intended for tmux status bars and editor plugins.",
+		Flags: []flagDoc{
+			{Name: "interval", Arg: "D", Usage: "how often to check the current version marker"},
+			{Name: "json", Usage: "print each change as a JSON object with previous and current"},
+			{Name: "exec", Arg: "CMD", Usage: "run this shell command on every change, with GOVM_PREVIOUS and GOVM_CURRENT set"},
+		},
+		Examples: []string{"govm watch --exec 'tmux refresh-client -S'", "govm watch --json"},
+	},
+	{
+		Name:    "clean",
+		Usage:   []string{"clean --gocache [--modcache]"},
+		Summary: "Run go clean -cache/-modcache with the active go and report the freed space",
+		Flags: []flagDoc{
+			{Name: "gocache", Usage: "run go clean -cache to empty the build cache (GOCACHE)"},
+			{Name: "modcache", Usage: "run go clean -modcache to empty the module cache (GOMODCACHE)"},
+		},
+		Examples: []string{"govm clean --gocache"},
+	},
+	{
+		Name:    "which",
+		Usage:   []string{"which [tool] [-q]"},
+		Summary: "Print the path of a tool (default go) in the active version",
+		Flags: []flagDoc{
+			{Name: "quiet", Usage: "exit 1 without a message when no version is active"},
+			{Name: "q", Usage: "shorthand for --quiet"},
+		},
+		Examples: []string{"govm which", "govm which gofmt"},
+	},
+	{
+		Name:        "run",
+		Usage:       []string{"run <version> <command> [args...]"},
+		Summary:     "Run a command with GOROOT, PATH and GOVM_VERSION set for an installed version",
+		Description: "The resolved GOROOT is cached per version; with goShim enabled in the config, `GOVM_VERSION=<version> go ...` selects the same version through the go shim, which shares this cache.",
+		Examples:    []string{"govm run 1.21.8 go test ./...", "govm run 1.22.3 make"},
+	},
+	{
+		Name:        "bench",
+		Usage:       []string{"bench [--runs N] [--warmup N] <version> <version> [version...] -- <command> [args...]"},
+		Summary:     "Time a command under several installed versions and compare the results",
+		Description: "Each version runs the command in the same environment as `govm run`; the delta compares mean wall-clock time with the first version. Command output is shown only when a run fails.",
+		Flags: []flagDoc{
+			{Name: "runs", Usage: "timed runs per version"},
+			{Name: "warmup", Usage: "untimed runs per version before timing, e.g. to fill the build cache"},
+		},
+		Examples: []string{"govm bench 1.22.3 1.23.0 -- go build ./...", "govm bench --runs 5 1.21.8 1.22.3 -- go test ./..."},
+	},
+	{
+		Name:        "direnv",
+		Usage:       []string{"direnv [version] [--write]"},
+		Summary:     "Print an .envrc snippet that selects the pinned version for direnv",
+		Description: "Without a version it uses the nearest .govm-version; `govm use --local` keeps a written snippet up to date.",
+		Flags:       []flagDoc{{Name: "write", Usage: "merge the snippet into .envrc next to .govm-version (or in the current directory)"}},
+		Examples:    []string{"govm direnv >> .envrc", "govm direnv --write"},
+	},
+	{
+		Name:    "env",
+		Usage:   []string{"env [NAME...] [-q]"},
+		Summary: "Print export lines for the active version, or bare values of GOROOT/GOPATH/GOVERSION",
+		Flags: []flagDoc{
+			{Name: "quiet", Usage: "print only bare values (GOROOT when no name is given); exit 1 when none is active"},
+			{Name: "q", Usage: "shorthand for --quiet"},
+		},
+		Examples: []string{`eval "$(govm env)"`, "govm env GOROOT GOVERSION"},
+	},
+	{
+		Name:        "ide",
+		Usage:       []string{"ide [--json] [--watch [--interval D]]"},
+		Summary:     "Print the active GOROOT, installed versions and shim paths for editor plugins",
+		Description: "The JSON document carries a schema number that only changes when existing fields are removed or change meaning. watch lists the files whose changes can change the document; they are replaced atomically, so watch their directories. --watch prints the document as one JSON line at start and again whenever it changes.",
+		Flags: []flagDoc{
+			{Name: "json", Usage: "print the document as JSON"},
+			{Name: "watch", Usage: "keep running and print the document as one JSON line every time it changes"},
+			{Name: "interval", Arg: "D", Usage: "how often --watch checks for changes"},
+		},
+		Examples: []string{"govm ide --json", "govm ide --watch"},
+	},
+	{
+		Name:        "ide-setup",
+		Usage:       []string{"ide-setup <vscode|goland|session> [version] [--workspace] [--import]"},
+		Summary:     "Point editors and the desktop session at the active or given version",
+		Description: "Editors started from the desktop do not read .bashrc/.zshrc. vscode sets go.goroot and go.alternateTools in the user settings (the go shim when goShim is enabled); goland sets the GOROOT of the project in the current directory; session writes ~/.config/environment.d/60-govm.conf for systemd sessions. Run it again after switching versions.",
+		Flags: []flagDoc{
+			{Name: "workspace", Usage: "vscode: write .vscode/settings.json in the current directory instead of the user settings"},
+			{Name: "import", Usage: "session: also update the running systemd user session"},
+		},
+		Examples: []string{"govm ide-setup vscode", "govm ide-setup goland 1.22.3", "govm ide-setup session --import"},
+	},
+	{
+		Name:    "uninstall",
+		Usage:   []string{"uninstall <version> [--yes] [--force] [--purge-caches]", "-uninstall <version> [-force]"},
+		Summary: "Remove an installed version, asking first if it is active",
+		Flags: []flagDoc{
+			{Name: "force", Usage: "remove the version even if it is active or locked, without asking"},
+			{Name: "yes", Usage: "confirm removing the active version without prompting"},
+			{Name: "purge-caches", Usage: "also remove GOTOOLCHAIN module downloads of this version from GOMODCACHE"},
+		},
+		Examples: []string{"govm uninstall 1.21.0", "govm uninstall 1.21.0 --yes --purge-caches"},
+	},
+	{
+		Name:     "lock",
+		Aliases:  []string{"unlock"},
+		Usage:    []string{"lock <version>...", "unlock <version>..."},
+		Summary:  "Protect versions from uninstall unless --force is given",
+		Examples: []string{"govm lock 1.22.0", "govm unlock 1.22.0"},
+	},
+	{
+		Name:     "export",
+		Usage:    []string{"export [file] [--format json|yaml]"},
+		Summary:  "Export installed versions and default",
+		Flags:    []flagDoc{{Name: "format", Arg: "json|yaml", Usage: "manifest format: json or yaml"}},
+		Examples: []string{"govm export govm.json"},
+	},
+	{
+		Name:     "import",
+		Usage:    []string{"import <file>"},
+		Summary:  "Install missing versions from a manifest and apply default",
+		Examples: []string{"govm import govm.json"},
+	},
+	{
+		Name:        "serve",
+		Usage:       []string{"serve [--addr 127.0.0.1:7070]"},
+		Summary:     "Run the local REST API daemon",
+		Description: "Also serves Prometheus metrics (installs, failures, downloaded bytes, active version) at /metrics.",
+		Flags:       []flagDoc{{Name: "addr", Arg: "ADDR", Usage: "listen address"}},
+		Examples:    []string{"govm serve"},
+	},
+	{
+		Name:    "stats",
+		Usage:   []string{"stats"},
+		Summary: `Show local per-version usage (requires "usageStats": true)`,
+	},
+	{
+		Name:     "verify",
+		Usage:    []string{"verify [version|--all]"},
+		Summary:  "Re-hash installed files against the install manifest",
+		Flags:    []flagDoc{{Name: "all", Usage: "verify every installed version"}},
+		Examples: []string{"govm verify", "govm verify --all"},
+	},
+	{
+		Name:        "dedup",
+		Usage:       []string{"dedup [--dry-run] [--mode auto|reflink|hardlink]"},
+		Summary:     "Share identical files between installed versions to reclaim disk space",
+		Description: "Patch releases of a series share most of their files. Files whose content, size and permissions match the install manifests are replaced by reflinks on file systems that support them (btrfs, xfs) or by hard links otherwise; each file is compared byte by byte first. A hard-linked file is the same file in every version that shares it, so editing it in one GOROOT changes the others. External versions and versions installed without a manifest are left alone.",
+		Flags: []flagDoc{
+			{Name: "dry-run", Usage: "only report how much space would be reclaimed, based on the manifests"},
+			{Name: "mode", Arg: "MODE", Usage: "auto uses reflinks when supported and hard links otherwise; reflink fails instead of falling back"},
+		},
+		Examples: []string{"govm dedup --dry-run", "govm dedup", "govm dedup --mode reflink"},
+	},
+	{
+		Name:        "doctor",
+		Usage:       []string{"doctor [--repair]"},
+		Summary:     "Check installs for drift and user-added files in GOROOT",
+		Description: "Also reports a metadata.json that cannot be parsed (govm then lists the salvaged entries read-only), a current version marker that names a version missing from metadata or without a go binary, a signed metadata.json edited outside govm (signMetadata), and a GOTOOLCHAIN setting that lets the go command download toolchains behind govm's back.",
+		Flags:       []flagDoc{{Name: "repair", Usage: "rebuild a corrupt metadata.json from the salvaged entries, re-register the marked version from its GOROOT or clear the marker when it cannot be recovered, and accept outside edits to a signed metadata.json"}},
+	},
+	{
+		Name:        "diff",
+		Usage:       []string{"diff <from> <to> [--stat]", "diff <from> <to> --notes [--offline] [--stat]"},
+		Summary:     "Show files or release notes that changed between two versions",
+		Description: "--notes lists the releases on the upgrade path: every release of the target's series newer than <from>, plus the first release of each series in between. Notes come from go.dev and are cached for offline use.",
+		Flags: []flagDoc{
+			{Name: "stat", Usage: "print only the summary line"},
+			{Name: "notes", Usage: "summarize the release notes between the versions instead of comparing files; neither version needs to be installed"},
+			{Name: "offline", Usage: "with --notes, use only the cached release notes"},
+		},
+		Examples: []string{"govm diff 1.21.0 1.22.0 --stat", "govm diff 1.21.8 1.22.3 --notes"},
+	},
+	{
+		Name:     "backup",
+		Usage:    []string{"backup [file] [--downloads]"},
+		Summary:  "Snapshot metadata, manifests, config and current marker",
+		Flags:    []flagDoc{{Name: "downloads", Usage: "include archives kept in downloads/"}},
+		Examples: []string{"govm backup govm-backup.tar.gz"},
+	},
+	{
+		Name:     "restore",
+		Usage:    []string{"restore <file>"},
+		Summary:  "Restore govm state from a backup snapshot",
+		Examples: []string{"govm restore govm-backup.tar.gz"},
+	},
+	{
+		Name:        "bundle",
+		Usage:       []string{"bundle create <file> <version>... [--arch list]", "bundle apply <file>"},
+		Summary:     "Carry Go archives and the release list to an offline machine",
+		Description: "create downloads the archives and a snapshot of the release list; apply verifies them and fills the download cache so later installs need no network.",
+		Flags:       []flagDoc{{Name: "arch", Arg: "list", Usage: "comma-separated architectures to include"}},
+		Examples:    []string{"govm bundle create go.bundle 1.22.5 1.21.12 --arch amd64,arm64", "govm bundle apply go.bundle"},
+	},
+	{
+		Name:        "provision",
+		Usage:       []string{"provision <host>... <version> [--dir path] [--no-env] [--ssh-opts args]"},
+		Summary:     "Install a Go version on remote Linux hosts over SSH",
+		Description: "The archive comes from the local download cache (downloaded once if missing) and is streamed over ssh; the remote host needs only sh and tar.",
+		Flags: []flagDoc{
+			{Name: "dir", Arg: "path", Usage: "absolute versions directory on the remote host (default $HOME/.govm/versions)"},
+			{Name: "no-env", Usage: "install only, without writing the govm block to the remote shell config"},
+			{Name: "ssh-opts", Arg: "args", Usage: "extra arguments for ssh, e.g. \"-p 2222 -i ~/.ssh/build\""},
+		},
+		Examples: []string{"govm provision ci@build-01 1.22.3", "govm provision build-01 build-02 1.22.3 --ssh-opts \"-p 2222\""},
+	},
+	{
+		Name:        "adopt",
+		Usage:       []string{"adopt [goroot...]"},
+		Summary:     "Register existing Go installs (PATH, /usr/local/go, ...) as external versions",
+		Description: "Without arguments it also finds toolchains the go command downloaded into GOMODCACHE and links them in place instead of copying them.",
+		Examples:    []string{"govm adopt", "govm adopt /usr/local/go"},
+	},
+	{
+		Name:    "rehash",
+		Usage:   []string{"rehash"},
+		Summary: "Regenerate go<version>/gofmt<version> shims for every installed version",
+	},
+	{
+		Name:    "migrate-layout",
+		Usage:   []string{"migrate-layout [--dry-run]"},
+		Summary: "Move ~/.govm into XDG data, cache and config directories",
+		Flags:   []flagDoc{{Name: "dry-run", Usage: "print the planned moves without changing anything"}},
+	},
+	{
+		Name:    "implode",
+		Usage:   []string{"implode [--keep-versions] [--yes]"},
+		Summary: "Remove govm data, rc blocks and (optionally) installed versions",
+		Flags: []flagDoc{
+			{Name: "keep-versions", Usage: "keep installed Go versions on disk"},
+			{Name: "yes", Usage: "do not ask for confirmation"},
+		},
+	},
+	{
+		Name:    "history",
+		Usage:   []string{"history [-n 20] [--json]"},
+		Summary: "Show when the active version changed and which command changed it",
+		Flags: []flagDoc{
+			{Name: "n", Arg: "N", Usage: "show the last N entries (0 shows all)"},
+			{Name: "json", Usage: "print entries as JSON"},
+		},
+	},
+	{
+		Name:     "cache",
+		Usage:    []string{"cache info", "cache list [name...]", "cache clear [name...] [--dry-run]"},
+		Summary:  "Show or reset downloads, catalog and region caches",
+		Flags:    []flagDoc{{Name: "dry-run", Usage: "print what would be removed (cache clear)"}},
+		Examples: []string{"govm cache", "govm cache clear downloads --dry-run"},
+	},
+	{
+		Name:        "plugins",
+		Usage:       []string{"plugins", "<name> [args...]"},
+		Summary:     "List govm-<name> executables on PATH that extend govm",
+		Description: "Unknown commands run the govm-<name> plugin from PATH with GOVM_PLUGIN_ROOT, GOVM_CONFIG, GOVM_SELF_VERSION, GOVM_BIN, GOVM_ACTIVE_VERSION and GOVM_ACTIVE_GOROOT exported.",
+	},
+	{
+		Name:    "gen-docs",
+		Usage:   []string{"gen-docs man|markdown [--dir DIR]"},
+		Summary: "Generate man pages or Markdown docs from the command metadata",
+		Flags: []flagDoc{
+			{Name: "dir", Arg: "DIR", Usage: "write one file per command into this directory instead of printing a single document"},
+		},
+		Examples: []string{"govm gen-docs man --dir share/man/man1", "govm gen-docs markdown > docs/cli.md"},
+	},
+	{
+		Name:     "help",
+		Usage:    []string{"help [command]"},
+		Summary:  "Show help for govm or for one command",
+		Examples: []string{"govm help install", "govm install --help"},
+	},
+}
+
+// findCommandDoc 按名称或别名查找命令帮助。
+func findCommandDoc(name string) *commandDoc {
+	for i := range commandDocs {
+		if commandDocs[i].Name == name {
+			return &commandDocs[i]
+		}
+		for _, alias := range commandDocs[i].Aliases {
+			if alias == name {
+				return &commandDocs[i]
+			}
+		}
+	}
+	return nil
+}
+
+// helpScope 返回用于判断 -h 的命令参数；run 之后的参数属于被执行的命令，例如 `govm run 1.22.3 go build -h`。
+func helpScope(rest []string) []string {
+	if rest[0] == "run" && len(rest) > 2 {
+		return rest[1:2]
+	}
+	return rest[1:]
+}
+
+// wantsHelp 报告子命令参数中是否出现 -h/--help（"--" 之后的参数不算）。
+func wantsHelp(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "-h", "-help", "--help":
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) handleHelp(args []string) error {
+	if len(args) == 0 {
+		a.printHelp()
+		return nil
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("help takes at most one command, got %d", len(args))
+	}
+	name := strings.TrimLeft(args[0], "-")
+	if doc := findCommandDoc(name); doc != nil {
+		a.printCommandHelp(*doc)
+		return nil
+	}
+	if a.plugins {
+		if path, ok := a.lookupPlugin(name); ok {
+			return a.runPlugin(path, []string{"--help"})
+		}
+	}
+	return fmt.Errorf("unknown command %q; run `govm help` to list commands", args[0])
+}
+
+// printHelp 输出命令概览。
+func (a *App) printHelp() {
+	a.println("govm - Go version manager")
+	a.println("")
+	a.println("Usage:")
+	a.println("  govm [global flags] <command> [args]")
+	a.println("")
+	a.println("Commands:")
+	for _, doc := range commandDocs {
+		// 概览中按实际输入的写法展示，例如 -list 与 lock, unlock。
+		first, _, _ := strings.Cut(doc.Usage[0], " ")
+		name := strings.Join(append([]string{first}, doc.Aliases...), ", ")
+		a.printf("  %-16s %s\n", name, a.tr(doc.Summary))
+	}
+	a.println("")
+	a.println("Global flags:")
+	a.printFlags(globalFlagDocs, "-")
+	a.println("")
+	a.println("Run `govm help <command>` for usage, flags and examples of a command.")
+}
+
+// printCommandHelp 输出单个命令的用法、说明、flag 与示例。
+func (a *App) printCommandHelp(doc commandDoc) {
+	a.println("Usage:")
+	for _, usage := range doc.Usage {
+		a.printf("  govm %s\n", usage)
+	}
+	a.println("")
+	a.println(a.tr(doc.Summary))
+	if doc.Description != "" {
+		a.println(a.tr(doc.Description))
+	}
+	if len(doc.Flags) > 0 {
+		a.println("")
+		a.println("Flags:")
+		a.printFlags(doc.Flags, doc.flagPrefix())
+	}
+	if len(doc.Examples) > 0 {
+		a.println("")
+		a.println("Examples:")
+		for _, example := range doc.Examples {
+			a.printf("  %s\n", example)
+		}
+	}
+}
+
+// flagPrefix 返回命令 flag 的写法：-list 等全局 flag 形式的命令用单个短横线，子命令用 --。
+func (doc commandDoc) flagPrefix() string {
+	if strings.HasPrefix(doc.Usage[0], "-") {
+		return "-"
+	}
+	return "--"
+}
+
+// flagName 返回带前缀的 flag 名；单字母 flag（-f、-q、-n）总是使用单个短横线。
+func flagName(f flagDoc, prefix string) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return prefix + f.Name
+}
+
+// printFlags 按最长的 flag 对齐说明。
+func (a *App) printFlags(flags []flagDoc, prefix string) {
+	names := make([]string, len(flags))
+	width := 0
+	for i, f := range flags {
+		names[i] = flagName(f, prefix)
+		if f.Arg != "" {
+			names[i] += " " + f.Arg
+		}
+		width = max(width, len(names[i]))
+	}
+	for i, f := range flags {
+		a.printf("  %-*s  %s\n", width, names[i], a.tr(f.Usage))
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAppCommandHelp(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"help"}); err != nil {
+		t.Fatalf("help failed: %v", err)
+	}
+	overview := buf.String()
+	for _, doc := range commandDocs {
+		if !strings.Contains(overview, doc.Summary) {
+			t.Errorf("overview is missing %s", doc.Name)
+		}
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"help", "install"}); err != nil {
+		t.Fatalf("help install failed: %v", err)
+	}
+	detail := buf.String()
+	for _, want := range []string{"govm install <version> [flags]", "--jobs N", "-f FILE", "Examples:"} {
+		if !strings.Contains(detail, want) {
+			t.Fatalf("install help is missing %q:\n%s", want, detail)
+		}
+	}
+	for _, args := range [][]string{{"install", "--help"}, {"install", "1.22.0", "-h"}, {"help", "-install"}} {
+		buf.Reset()
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		if buf.String() != detail {
+			t.Fatalf("%v printed different help:\n%s", args, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"help", "unlock"}); err != nil || !strings.Contains(buf.String(), "govm unlock <version>...") {
+		t.Fatalf("aliases must resolve: %v %q", err, buf.String())
+	}
+	if err := app.Run([]string{"help", "nope"}); err == nil || !strings.Contains(err.Error(), `unknown command "nope"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAppHelpLocalized(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithLocale("zh-CN"))
+	if err := app.Run([]string{"-help"}); err != nil {
+		t.Fatalf("help failed: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "命令：") || !strings.Contains(out, "安装一个或多个版本") {
+		t.Fatalf("expected zh-CN help, got:\n%s", out)
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// HistoryService 描述当前版本变化记录的读写能力。
+type HistoryService interface {
+	AppendHistory(entry models.HistoryEntry) error
+	LoadHistory() ([]models.HistoryEntry, error)
+}
+
+// WithHistory 启用激活历史记录与 `govm history`。
+func WithHistory(s HistoryService) AppOption {
+	return func(a *App) {
+		a.history = s
+	}
+}
+
+// activationCommands 为可能改变当前版本的子命令，只有这些命令执行前后会比较当前版本。
+var activationCommands = map[string]bool{
+	"use": true, "install": true, "uninstall": true, "import": true, "restore": true, "init": true,
+}
+
+// trackActivation 记录命令执行前的当前版本，返回的函数在命令结束后比较并在变化时追加历史。
+func (a *App) trackActivation(args, rest []string, uninstallFlag bool) func() {
+	if a.history == nil || a.lister == nil || (!uninstallFlag && (len(rest) == 0 || !activationCommands[rest[0]])) {
+		return func() {}
+	}
+	before := a.markedVersion()
+	return func() {
+		after := a.markedVersion()
+		if after == before {
+			return
+		}
+		// 历史只用于排查问题，写入失败不影响命令结果。
+		_ = a.history.AppendHistory(models.HistoryEntry{
+			At:       time.Now(),
+			Version:  after,
+			Previous: before,
+			Command:  strings.TrimSpace("govm " + strings.Join(args, " ")),
+		})
+	}
+}
+
+// markedVersion 返回元数据中标记为当前的版本，不校验 go 可执行文件。
+func (a *App) markedVersion() string {
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return ""
+	}
+	for _, v := range versions {
+		if v.IsCurrent {
+			return v.Number
+		}
+	}
+	return ""
+}
+
+func (a *App) handleHistory(args []string) error {
+	if a.history == nil {
+		return errors.New("history command is unavailable")
+	}
+	fs := newCommandFlagSet("history")
+	limit := fs.Int("n", 20, "show the last N entries (0 shows all)")
+	asJSON := fs.Bool("json", false, "print entries as JSON")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	entries, err := a.history.LoadHistory()
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[len(entries)-*limit:]
+	}
+	if *asJSON {
+		if entries == nil {
+			entries = []models.HistoryEntry{}
+		}
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		a.println("No activation history yet.")
+		return nil
+	}
+	tbl := newTable("time", "version", "previous", "command")
+	for _, e := range entries {
+		tbl.addRow(e.At.Local().Format(time.DateTime), historyVersion(e.Version), historyVersion(e.Previous), e.Command)
+	}
+	return tbl.render(a.out)
+}
+
+func historyVersion(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return "go" + v
+}
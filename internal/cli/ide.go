@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/liangyou/govm/internal/shims"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+)
+
+// IDESetupService 描述把 govm 管理的 GOROOT 写入编辑器设置与图形会话环境的能力，由 env.Manager 实现。
+type IDESetupService interface {
+	SetupVSCode(goRoot, dir string) (string, error)
+	SetupGoLand(goRoot, dir string) (string, error)
+	WriteSessionEnvironment(goRoot string) (string, error)
+	ImportSessionEnvironment(goRoot string) error
+}
+
+// WithIDESetup 启用 `govm ide-setup`。
+func WithIDESetup(s IDESetupService) AppOption {
+	return func(a *App) {
+		a.ide = s
+	}
+}
+
+// handleIDESetup 让不读取 .bashrc 的编辑器（从桌面启动的 VS Code、GoLand）使用当前版本或指定版本。
+func (a *App) handleIDESetup(args []string) error {
+	if a.ide == nil || a.lister == nil {
+		return errors.New("ide-setup command is unavailable")
+	}
+	fs := newCommandFlagSet("ide-setup")
+	workspace := fs.Bool("workspace", false, "vscode: write .vscode/settings.json in the current directory instead of the user settings")
+	importEnv := fs.Bool("import", false, "session: also update the running systemd user session")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 || len(rest) > 2 {
+		return errors.New("ide-setup requires a target (vscode, goland or session) and takes at most one version")
+	}
+	var number, goRoot string
+	if len(rest) == 2 {
+		number = normalizeVersion(rest[1])
+		if goRoot, err = a.installedRoot(number); err != nil {
+			return err
+		}
+	} else {
+		current, err := a.activeVersion(false)
+		if err != nil {
+			return err
+		}
+		number, goRoot = current.Number, current.InstallPath
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("ide-setup: %w", err)
+	}
+
+	switch rest[0] {
+	case "vscode":
+		if !*workspace {
+			dir = ""
+		}
+		path, err := a.ide.SetupVSCode(goRoot, dir)
+		if err != nil {
+			return err
+		}
+		a.printf("Updated %[1]s; reload the VS Code window to use go%[2]s\n", path, number)
+	case "goland":
+		path, err := a.ide.SetupGoLand(goRoot, dir)
+		if err != nil {
+			return err
+		}
+		a.printf("Updated %[1]s; reopen the project in GoLand to use go%[2]s\n", path, number)
+	case "session":
+		path, err := a.ide.WriteSessionEnvironment(goRoot)
+		if err != nil {
+			return err
+		}
+		a.printf("Wrote %[1]s; applications started from the desktop use go%[2]s after the next login\n", path, number)
+		if *importEnv {
+			if err := a.ide.ImportSessionEnvironment(goRoot); err != nil {
+				return err
+			}
+			a.printf("Updated the systemd user session; applications started from now on use go%s\n", number)
+		}
+	default:
+		return fmt.Errorf("ide-setup: unknown target %q (use vscode, goland or session)", rest[0])
+	}
+	return nil
+}
+
+// IDESchema 为 IDEDocument 的格式版本：新增字段不改变版本，删除或改变已有字段的含义时递增。
+const IDESchema = 1
+
+// IDEDocument 是 `govm ide --json` 的输出，供编辑器插件读取，插件不必解析面向人的输出。
+type IDEDocument struct {
+	Schema    int          `json:"schema"`
+	Active    *IDEVersion  `json:"active"`
+	Project   *IDEProject  `json:"project"`
+	Installed []IDEVersion `json:"installed"`
+	Shims     IDEShims     `json:"shims"`
+	// Watch 为内容变化时文档可能随之变化的文件；文件通过重命名原子替换，应监听所在目录。
+	Watch []string `json:"watch"`
+}
+
+// IDEVersion 描述一个已安装版本。
+type IDEVersion struct {
+	Version  string `json:"version"`
+	GoRoot   string `json:"goroot"`
+	Go       string `json:"go"`
+	External bool   `json:"external"`
+}
+
+// IDEProject 描述当前目录向上找到的 .govm-version；Installed 为 false 时 GoRoot 与 Go 为空。
+type IDEProject struct {
+	Version   string `json:"version"`
+	File      string `json:"file"`
+	Installed bool   `json:"installed"`
+	GoRoot    string `json:"goroot,omitempty"`
+	Go        string `json:"go,omitempty"`
+}
+
+// IDEShims 描述 shim 目录：Dir 中有 go1.22.0 等版本化命令，启用 goShim 时 Selector 为按 .govm-version 选择版本的 go。
+type IDEShims struct {
+	Dir      string `json:"dir,omitempty"`
+	Selector string `json:"selector,omitempty"`
+}
+
+// StateFileSource 描述报告当前版本与已安装版本所在文件的能力，由 storage.FileStorage 实现。
+type StateFileSource interface {
+	StateFiles() []string
+}
+
+// handleIDE 输出供编辑器插件使用的 JSON 文档；--watch 时每当文档变化再输出一行紧凑的 JSON，直到收到中断信号。
+func (a *App) handleIDE(args []string) error {
+	if a.lister == nil {
+		return errors.New("ide command is unavailable")
+	}
+	fs := newCommandFlagSet("ide")
+	asJSON := fs.Bool("json", false, "print the document as JSON")
+	watch := fs.Bool("watch", false, "keep running and print the document as one JSON line every time it changes")
+	interval := fs.Duration("interval", storage.DefaultWatchInterval, "how often --watch checks for changes")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("ide: --interval must be positive, got %s", *interval)
+	}
+	doc, err := a.ideDocument()
+	if err != nil {
+		return err
+	}
+	switch {
+	case !*watch && *asJSON:
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case !*watch:
+		a.printIDEDocument(doc)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	last, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(a.out, string(last)); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// 读取错误（例如安装过程中元数据暂时不可读）跳过本轮，下次再试。
+		doc, err := a.ideDocument()
+		if err != nil {
+			continue
+		}
+		next, err := json.Marshal(doc)
+		if err != nil || bytes.Equal(next, last) {
+			continue
+		}
+		if _, err := fmt.Fprintln(a.out, string(next)); err != nil {
+			return err
+		}
+		last = next
+	}
+}
+
+// printIDEDocument 以便于阅读的形式输出文档中编辑器最关心的部分。
+func (a *App) printIDEDocument(doc IDEDocument) {
+	if doc.Active != nil {
+		a.printf("%s go%s %s\n", colorize("active:", colorCyan), doc.Active.Version, doc.Active.GoRoot)
+	} else {
+		a.printf("%s %s\n", colorize("active:", colorCyan), a.tr("(none)"))
+	}
+	if doc.Project != nil {
+		if doc.Project.Installed {
+			a.printf("%s go%s %s (%s)\n", colorize("project:", colorCyan), doc.Project.Version, doc.Project.GoRoot, doc.Project.File)
+		} else {
+			a.printf("%s go%s %s (%s)\n", colorize("project:", colorCyan), doc.Project.Version, a.tr("not installed"), doc.Project.File)
+		}
+	}
+	if doc.Shims.Selector != "" {
+		a.printf("%s %s\n", colorize("go shim:", colorCyan), doc.Shims.Selector)
+	}
+}
+
+// ideDocument 汇总编辑器插件需要的状态。
+func (a *App) ideDocument() (IDEDocument, error) {
+	doc := IDEDocument{Schema: IDESchema, Installed: []IDEVersion{}, Watch: []string{}}
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return doc, err
+	}
+	for _, v := range local {
+		entry := IDEVersion{Version: v.Number, GoRoot: v.InstallPath, Go: filepath.Join(v.InstallPath, "bin", "go"), External: v.External}
+		doc.Installed = append(doc.Installed, entry)
+		if v.IsCurrent {
+			doc.Active = &entry
+		}
+	}
+	if dir, err := os.Getwd(); err == nil {
+		if number, path, err := version.FindPin(dir); err == nil && number != "" {
+			project := &IDEProject{Version: number, File: path}
+			if root := findInstallPath(local, number); root != "" {
+				project.Installed, project.GoRoot, project.Go = true, root, filepath.Join(root, "bin", "go")
+			}
+			doc.Project = project
+		}
+	}
+	if a.shims != nil {
+		doc.Shims.Dir = a.shims.Dir()
+		if selector := filepath.Join(doc.Shims.Dir, "go"); shims.IsSelector(selector) {
+			doc.Shims.Selector = selector
+		}
+	}
+	if source, ok := a.watcher.(StateFileSource); ok {
+		doc.Watch = append(doc.Watch, source.StateFiles()...)
+	}
+	if doc.Project != nil {
+		doc.Watch = append(doc.Watch, doc.Project.File)
+	}
+	return doc, nil
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// fakeIDE 记录 ide-setup 写入的 GOROOT。
+type fakeIDE struct {
+	calls []string
+}
+
+func (f *fakeIDE) SetupVSCode(goRoot, dir string) (string, error) {
+	f.calls = append(f.calls, "vscode "+goRoot+" "+dir)
+	return "/home/dev/.config/Code/User/settings.json", nil
+}
+
+func (f *fakeIDE) SetupGoLand(goRoot, dir string) (string, error) {
+	f.calls = append(f.calls, "goland "+goRoot)
+	return dir + "/.idea/workspace.xml", nil
+}
+
+func (f *fakeIDE) WriteSessionEnvironment(goRoot string) (string, error) {
+	f.calls = append(f.calls, "session "+goRoot)
+	return "/home/dev/.config/environment.d/60-govm.conf", nil
+}
+
+func (f *fakeIDE) ImportSessionEnvironment(goRoot string) error {
+	f.calls = append(f.calls, "import "+goRoot)
+	return nil
+}
+
+func TestAppIDESetup(t *testing.T) {
+	t.Parallel()
+
+	current := models.Version{Number: "1.22.0", InstallPath: "/v/go1.22.0", IsCurrent: true}
+	lister := &fakeLister{current: &current, local: []models.Version{current, {Number: "1.21.8", InstallPath: "/v/go1.21.8"}}}
+	ide := &fakeIDE{}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithIDESetup(ide))
+
+	if err := app.Run([]string{"ide-setup", "vscode"}); err != nil || !strings.Contains(buf.String(), "reload the VS Code window to use go1.22.0") {
+		t.Fatalf("ide-setup vscode: %v\n%s", err, buf.String())
+	}
+	if err := app.Run([]string{"ide-setup", "session", "1.21.8", "--import"}); err != nil {
+		t.Fatalf("ide-setup session: %v", err)
+	}
+	if want := "vscode /v/go1.22.0 |session /v/go1.21.8|import /v/go1.21.8"; strings.Join(ide.calls, "|") != want {
+		t.Fatalf("calls = %q, want %q", ide.calls, want)
+	}
+	if err := app.Run([]string{"ide-setup", "vim"}); err == nil || !strings.Contains(err.Error(), "unknown target") {
+		t.Fatalf("unknown targets must be rejected, got %v", err)
+	}
+	if err := app.Run([]string{"ide-setup", "goland", "1.19.0"}); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Fatalf("versions that are not installed must be rejected, got %v", err)
+	}
+}
+
+// stateWatcher 在 fakeWatcher 之上报告状态文件，模拟 storage.FileStorage。
+type stateWatcher struct {
+	fakeWatcher
+}
+
+func (stateWatcher) StateFiles() []string { return []string{"/govm/current", "/govm/metadata.json"} }
+
+func TestAppIDEJSON(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+	if err := os.WriteFile(filepath.Join(project, ".govm-version"), []byte("1.21.8\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lister := &fakeLister{local: []models.Version{
+		{Number: "1.22.0", InstallPath: "/govm/versions/go1.22.0", IsCurrent: true},
+		{Number: "1.21.8", InstallPath: "/usr/lib/go-1.21", External: true},
+	}}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test",
+		WithShims(&fakeShims{}), WithWatcher(&stateWatcher{}))
+
+	if err := app.Run([]string{"ide", "--json"}); err != nil {
+		t.Fatalf("ide --json: %v", err)
+	}
+	var doc IDEDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Schema != IDESchema || doc.Active == nil || doc.Active.Go != "/govm/versions/go1.22.0/bin/go" || len(doc.Installed) != 2 || !doc.Installed[1].External {
+		t.Fatalf("unexpected document:\n%s", buf.String())
+	}
+	if doc.Project == nil || !doc.Project.Installed || doc.Project.GoRoot != "/usr/lib/go-1.21" || doc.Shims.Dir != "/govm/bin" {
+		t.Fatalf("unexpected project or shims:\n%s", buf.String())
+	}
+	pin := filepath.Join(project, ".govm-version")
+	if want := []string{"/govm/current", "/govm/metadata.json", pin}; !slices.Equal(doc.Watch, want) {
+		t.Fatalf("watch = %q, want %q", doc.Watch, want)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"ide"}); err != nil || !strings.Contains(buf.String(), "go1.22.0 /govm/versions/go1.22.0") {
+		t.Fatalf("ide: %v\n%s", err, buf.String())
+	}
+}
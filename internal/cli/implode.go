@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ImplodeService 描述删除 govm 本地数据的能力。
+type ImplodeService interface {
+	Implode(keepVersions bool) ([]string, error)
+}
+
+// ShellCleaner 描述从 shell 配置文件中删除 govm 配置块的能力。
+type ShellCleaner interface {
+	RemoveShellConfig() ([]string, error)
+}
+
+// WithImplode 启用 `govm implode`。
+func WithImplode(s ImplodeService, shells ShellCleaner) AppOption {
+	return func(a *App) {
+		a.implode = s
+		a.rcCleaner = shells
+	}
+}
+
+func (a *App) handleImplode(args []string) error {
+	if a.implode == nil || a.rcCleaner == nil {
+		return errors.New("implode command is unavailable")
+	}
+	fs := newCommandFlagSet("implode")
+	keep := fs.Bool("keep-versions", false, "keep installed Go versions on disk")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	what := "all govm data, installed Go versions and shell configuration"
+	if *keep {
+		what = "govm data and shell configuration (installed Go versions are kept)"
+	}
+	if !a.confirm(a.tr("This removes %s. Continue?", what), *yes) {
+		return errors.New("implode: aborted")
+	}
+
+	rcFiles, err := a.rcCleaner.RemoveShellConfig()
+	for _, path := range rcFiles {
+		a.printf("Removed govm block from %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	err = a.withLock(func() error {
+		var err error
+		removed, err = a.implode.Implode(*keep)
+		return err
+	})
+	for _, path := range removed {
+		a.printf("Removed %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+	if a.configPath != "" {
+		if err := os.Remove(a.configPath); err == nil {
+			a.printf("Removed %s\n", a.configPath)
+			os.Remove(filepath.Dir(a.configPath))
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("implode: %w", err)
+		}
+	}
+	if a.packaged {
+		a.println("govm data has been removed; uninstall the govm package with your package manager and open a new shell to finish.")
+	} else {
+		a.println("govm has been removed; delete the govm binary itself and open a new shell to finish.")
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ShellDetector 描述当前 shell 的探测能力。
+type ShellDetector interface {
+	DetectShell() (string, error)
+}
+
+// WithConfigFile 指定 `govm init` 写入的配置文件路径以及用于探测 shell 的服务。
+func WithConfigFile(path string, shell ShellDetector) AppOption {
+	return func(a *App) {
+		a.configPath = path
+		a.shell = shell
+	}
+}
+
+func (a *App) handleInit(args []string) error {
+	if a.configPath == "" {
+		return errors.New("init command is unavailable")
+	}
+	fs := newCommandFlagSet("init")
+	yes := fs.Bool("yes", false, "accept every default without prompting")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *yes {
+		a.prompter = newLinePrompter(strings.NewReader(""), a.out)
+	}
+
+	file, err := config.LoadFile(a.configPath)
+	if err != nil {
+		return err
+	}
+	a.printf("Setting up govm (config: %s)\n", a.configPath)
+
+	if a.shell != nil {
+		if shell, err := a.shell.DetectShell(); err == nil {
+			a.printf("Detected shell: %s\n", shell)
+		} else {
+			a.printf("%s %v; only bash and zsh rc files are managed\n", colorize("warning:", colorYellow), err)
+		}
+	}
+
+	region := a.ask(a.tr("Download source (auto, global, cn)"), orDefault(file.Region, "auto"))
+	switch strings.ToLower(region) {
+	case "auto", "global", "cn":
+	default:
+		return fmt.Errorf("init: unknown download source %q", region)
+	}
+	goPath := a.ask(a.tr("GOPATH"), orDefault(file.GoPath, "$HOME/go"))
+	defaultRoot := storage.DefaultRootDir()
+	root := a.ask(a.tr("Install root"), orDefault(file.RootDir, defaultRoot))
+
+	changed := !strings.EqualFold(region, orDefault(file.Region, "auto")) ||
+		goPath != orDefault(file.GoPath, "$HOME/go") ||
+		root != orDefault(file.RootDir, defaultRoot)
+	file.Region = strings.ToLower(region)
+	if file.Region == "auto" {
+		file.Region = ""
+	}
+	file.GoPath = goPath
+	if goPath == "$HOME/go" {
+		file.GoPath = ""
+	}
+	file.RootDir = root
+	if root == defaultRoot {
+		file.RootDir = ""
+	}
+	if err := config.SaveFile(a.configPath, file); err != nil {
+		return err
+	}
+	a.printf("Wrote %s\n", a.configPath)
+
+	latest, err := a.latestStable()
+	if err != nil {
+		a.printf("%s cannot look up the latest release: %v\n", colorize("warning:", colorYellow), err)
+		return nil
+	}
+	if !a.askYesNo(a.tr("Install and activate go%s now?", latest.Number), true) {
+		a.printf("Run `govm install %s && govm use %s` when ready; `govm use` writes the shell rc block.\n", latest.Number, latest.Number)
+		return nil
+	}
+	// 下载源、GOPATH 与安装根目录在进程启动时已确定，修改后需由新进程生效。
+	if changed {
+		a.printf("Settings changed, run `govm install %s && govm use %s` to apply them.\n", latest.Number, latest.Number)
+		return nil
+	}
+	if err := a.handleInstall(latest.Number, installOptions{}); err != nil {
+		return err
+	}
+	return a.handleUse(latest.Number, false)
+}
+
+// latestStable 返回远程列表中最新的稳定版本。
+func (a *App) latestStable() (*models.Version, error) {
+	if a.lister == nil {
+		return nil, errors.New("remote listing is unavailable")
+	}
+	versions, err := a.lister.RemoteVersions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if version.ChannelOf(versions[i]) == models.ChannelStable {
+			return &versions[i], nil
+		}
+	}
+	return nil, errors.New("no stable release found")
+}
+
+func orDefault(v, def string) string {
+	if strings.TrimSpace(v) == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeShell string
+
+func (f fakeShell) DetectShell() (string, error) { return string(f), nil }
+
+func TestAppInitWritesConfigAndInstallsLatestStable(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	buf := &bytes.Buffer{}
+	installs := &fakeInstaller{}
+	switcher := &fakeSwitcher{}
+	lister := &fakeLister{remote: []models.Version{{Number: "1.24rc1"}, {Number: "1.23.2", FullName: "go1.23.2"}}}
+	app := NewApp(buf, lister, installs, switcher, &fakeUninstaller{}, "test",
+		WithConfigFile(path, fakeShell("zsh")),
+		WithInput(strings.NewReader("\n\n\ny\n")),
+	)
+
+	if err := app.Run([]string{"init"}); err != nil {
+		t.Fatalf("init failed: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Detected shell: zsh") {
+		t.Fatalf("missing shell detection:\n%s", buf.String())
+	}
+	if len(installs.installed) != 1 || installs.installed[0].Number != "1.23.2" {
+		t.Fatalf("expected latest stable install, got %#v", installs.installed)
+	}
+	if len(switcher.used) != 1 || switcher.used[0] != "1.23.2" {
+		t.Fatalf("expected use of latest stable, got %v", switcher.used)
+	}
+	file, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if !reflect.DeepEqual(file, config.File{}) {
+		t.Fatalf("defaults should not be written explicitly: %#v", file)
+	}
+}
+
+func TestAppInitChangedSettingsDeferInstall(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	buf := &bytes.Buffer{}
+	installs := &fakeInstaller{}
+	lister := &fakeLister{remote: []models.Version{{Number: "1.23.2"}}}
+	app := NewApp(buf, lister, installs, &fakeSwitcher{}, &fakeUninstaller{}, "test",
+		WithConfigFile(path, fakeShell("bash")),
+		WithInput(strings.NewReader("cn\n/data/go\n/data/govm\ny\n")),
+	)
+
+	if err := app.Run([]string{"init"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	file, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if file.Region != "cn" || file.GoPath != "/data/go" || file.RootDir != "/data/govm" {
+		t.Fatalf("unexpected config: %#v", file)
+	}
+	if len(installs.installed) != 0 || !strings.Contains(buf.String(), "run `govm install 1.23.2 && govm use 1.23.2`") {
+		t.Fatalf("install should be deferred to a new process:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,46 @@
+package cli
+
+import "errors"
+
+// LayoutMigrator 描述从 ~/.govm 迁移到 XDG 目录布局的能力。
+type LayoutMigrator interface {
+	Migrate(dryRun bool) ([]string, error)
+}
+
+// WithLayoutMigrator 启用 `govm migrate-layout`。
+func WithLayoutMigrator(m LayoutMigrator) AppOption {
+	return func(a *App) {
+		a.layout = m
+	}
+}
+
+func (a *App) handleMigrateLayout(args []string) error {
+	if a.layout == nil {
+		return errors.New("migrate-layout command is unavailable")
+	}
+	fs := newCommandFlagSet("migrate-layout")
+	dryRun := fs.Bool("dry-run", false, "print the planned moves without changing anything")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	var moved []string
+	err := a.withLock(func() error {
+		var err error
+		moved, err = a.layout.Migrate(*dryRun)
+		return err
+	})
+	for _, line := range moved {
+		a.printf("  %s\n", line)
+	}
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		a.printf("%d item(s) would be moved, re-run without --dry-run to apply\n", len(moved))
+		return nil
+	}
+	a.printf("Moved %d item(s) to the XDG layout.\n", len(moved))
+	a.println("Run `govm use <version>` to point GOROOT in your shell configuration at the new location.")
+	return nil
+}
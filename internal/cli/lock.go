@@ -0,0 +1,58 @@
+package cli
+
+import "fmt"
+
+// VersionLockService 描述设置版本锁定状态的能力。
+type VersionLockService interface {
+	SetLocked(version string, locked bool) error
+}
+
+// WithVersionLocks 启用 `govm lock` 与 `govm unlock`。
+func WithVersionLocks(s VersionLockService) AppOption {
+	return func(a *App) {
+		a.locks = s
+	}
+}
+
+func (a *App) handleLock(args []string, locked bool) error {
+	name := "lock"
+	if !locked {
+		name = "unlock"
+	}
+	if a.locks == nil {
+		return fmt.Errorf("%s command is unavailable", name)
+	}
+	rest, err := parseCommandFlags(newCommandFlagSet(name), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return fmt.Errorf("%s command requires a version", name)
+	}
+	for _, arg := range rest {
+		ver := normalizeVersion(arg)
+		if err := a.withLock(func() error { return a.locks.SetLocked(ver, locked) }); err != nil {
+			return a.withLocalSuggestion(err, ver)
+		}
+		if locked {
+			a.printf("Locked go%s; uninstall now requires --force\n", ver)
+		} else {
+			a.printf("Unlocked go%s\n", ver)
+		}
+	}
+	return nil
+}
+
+// checkUnlocked 在未指定 force 时拒绝卸载被锁定的版本；确认提示与 --yes 都不能绕过锁定。
+func (a *App) checkUnlocked(ver string) error {
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return nil
+	}
+	for _, v := range local {
+		if v.Number == ver && v.Locked {
+			return fmt.Errorf("go%s is locked; run `govm unlock %s` or pass --force to remove it", ver, ver)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"github.com/liangyou/govm/internal/version"
+)
+
+// MarkerService 描述检查并修复当前版本标记与元数据一致性的能力。
+type MarkerService interface {
+	CheckMarker() (*version.MarkerIssue, error)
+	RepairMarker(issue version.MarkerIssue) (string, error)
+}
+
+// WithMarkerHealth 启用当前版本标记的一致性检查：list/current 输出警告，`govm doctor --repair` 负责修复。
+func WithMarkerHealth(s MarkerService) AppOption {
+	return func(a *App) {
+		a.markers = s
+	}
+}
+
+// markerIssueText 返回标记问题的本地化描述。
+func (a *App) markerIssueText(issue version.MarkerIssue) string {
+	switch {
+	case issue.Kind == version.MarkerBroken:
+		return a.tr("current version marker names go%s, but its go binary is missing from %s", issue.Version, issue.GoRoot)
+	case issue.GoRoot != "":
+		return a.tr("current version marker names go%s, which is missing from metadata although %s still exists", issue.Version, issue.GoRoot)
+	default:
+		return a.tr("current version marker names go%s, which is not installed", issue.Version)
+	}
+}
+
+// warnMarker 在当前版本标记与元数据不一致时输出一条警告；检查本身失败时保持沉默，交给后续命令报告。
+func (a *App) warnMarker() {
+	if a.markers == nil {
+		return
+	}
+	issue, err := a.markers.CheckMarker()
+	if err != nil || issue == nil {
+		return
+	}
+	a.printf("%s %s (run `govm doctor --repair` to fix)\n", colorize("warning:", colorYellow), a.markerIssueText(*issue))
+}
+
+// doctorMarker 检查当前版本标记；repair 为 true 时就地修复并以警告报告结果。
+func (a *App) doctorMarker(repair bool) ([]doctorIssue, error) {
+	if a.markers == nil {
+		return nil, nil
+	}
+	issue, err := a.markers.CheckMarker()
+	if err != nil || issue == nil {
+		return nil, err
+	}
+	text := a.markerIssueText(*issue)
+	if !repair {
+		return []doctorIssue{{text: a.tr("%s (run `govm doctor --repair` to fix)", text)}}, nil
+	}
+	action, err := a.markers.RepairMarker(*issue)
+	if err != nil {
+		return nil, err
+	}
+	if action == version.RepairRegistered {
+		return []doctorIssue{{warn: true, text: a.tr("%s; re-registered go%s from %s", text, issue.Version, issue.GoRoot)}}, nil
+	}
+	return []doctorIssue{{warn: true, text: a.tr("%s; cleared the marker, run `govm use <version>` to activate one", text)}}, nil
+}
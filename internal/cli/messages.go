@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/liangyou/govm/internal/i18n"
+)
+
+// WithLocale 指定输出语言（en、zh-CN），默认为英文；错误信息与表头保持英文，便于检索与脚本处理。
+func WithLocale(locale string) AppOption {
+	return func(a *App) {
+		a.msg = i18n.New(locale)
+	}
+}
+
+// tr 返回翻译后的消息。
+func (a *App) tr(format string, args ...any) string {
+	return a.msg.Sprintf(format, args...)
+}
+
+// printf 输出翻译后的格式化消息。
+func (a *App) printf(format string, args ...any) {
+	fmt.Fprint(a.out, a.msg.Sprintf(format, args...))
+}
+
+// println 输出翻译后的一行消息。
+func (a *App) println(msg string) {
+	fmt.Fprintln(a.out, a.msg.Sprintf(msg))
+}
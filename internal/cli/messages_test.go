@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/liangyou/govm/internal/i18n"
+)
+
+// untranslated 为有意不翻译的消息：环境变量名、shell 代码与命令行。
+var untranslated = map[string]bool{
+	"  govm %s\n":      true,
+	"GOPATH":           true,
+	"export %s='%s'\n": true,
+	"%s --exec: %v\n":  true,
+}
+
+// formatOnly 匹配格式化动词与版本号前的 go，只由它们与标点组成的消息不需要翻译。
+var formatOnly = regexp.MustCompile(`go%s|%[-+# 0-9.*]*[a-zA-Z]`)
+
+// TestMessagesHaveTranslations 确保经 a.printf、a.println 与 a.tr 输出的字面量消息都有 zh-CN 翻译。
+func TestMessagesHaveTranslations(t *testing.T) {
+	t.Parallel()
+
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zh := i18n.New(i18n.Chinese)
+	fset := token.NewFileSet()
+	var missing []string
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != "a" {
+				return true
+			}
+			switch sel.Sel.Name {
+			case "printf", "println", "tr":
+			default:
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			msg, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			words := formatOnly.ReplaceAllString(msg, "")
+			if untranslated[msg] || !strings.ContainsFunc(words, unicode.IsLetter) {
+				return true
+			}
+			if !zh.Has(msg) {
+				missing = append(missing, fmt.Sprintf("%s: %q", fset.Position(lit.Pos()), msg))
+			}
+			return true
+		})
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Fatalf("messages without a zh-CN translation in internal/i18n/zh_cn.go:\n%s", strings.Join(missing, "\n"))
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// moduleRequirement 读取当前目录所在工作区或模块的 go/toolchain 要求；不在模块内、没有相关指令或读取失败时返回 nil，
+// 这项检查只是提示，不影响命令本身。
+func (a *App) moduleRequirement() *version.ModuleRequirement {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	req, ok, err := version.FindModule(dir, a.getenv("GOWORK"))
+	if err != nil || !ok {
+		return nil
+	}
+	if minimum, _ := req.Minimum(); minimum == "" {
+		return nil
+	}
+	return &req
+}
+
+// checkModule 在 number 低于当前模块要求时输出警告并给出可执行的建议；strict 为 true 时改为返回错误。
+func (a *App) checkModule(number string, strict bool) error {
+	req := a.moduleRequirement()
+	if req == nil || req.Satisfied(number) {
+		return nil
+	}
+	minimum, directive := req.Minimum()
+	if strict {
+		return fmt.Errorf("go%s does not satisfy the %s %s directive in %s; %s", number, directive, minimum, req.Path, a.moduleSuggestion(*req))
+	}
+	a.printf("%s %s\n", colorize("warning:", colorYellow),
+		a.tr("go%[1]s is older than the %[2]s %[3]s directive in %[4]s; %[5]s", number, directive, minimum, req.Path, a.moduleSuggestion(*req)))
+	return nil
+}
+
+// moduleSuggestion 优先建议切换到已安装的满足要求的最新版本，没有时建议安装要求的版本。
+func (a *App) moduleSuggestion(req version.ModuleRequirement) string {
+	if a.lister != nil {
+		if local, err := a.lister.LocalVersions(); err == nil {
+			best := ""
+			for _, v := range local {
+				if req.Satisfied(v.Number) && (best == "" || goversion.Compare(v.Number, best) > 0) {
+					best = v.Number
+				}
+			}
+			if best != "" {
+				return a.tr("run `govm use %s`", best)
+			}
+		}
+	}
+	minimum, _ := req.Minimum()
+	return a.tr("run `govm install %s`", releaseVersion(minimum))
+}
+
+// releaseVersion 把 go 指令转换为发布版本号：Go 1.21 起 "go 1.22" 指 1.22.0，此前的版本原样使用。
+func releaseVersion(number string) string {
+	if strings.Count(number, ".") >= 2 || strings.Contains(number, "rc") || strings.Contains(number, "beta") || goversion.Compare(number, "1.21") < 0 {
+		return number
+	}
+	return number + ".0"
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestModuleRequirementWarnings(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	old := models.Version{Number: "1.21.5", FullName: "go1.21.5", InstallPath: "/v/go1.21.5", IsCurrent: true}
+	lister := &fakeLister{local: []models.Version{old}, current: &old}
+	newApp := func(buf *bytes.Buffer) *App {
+		app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+		app.getenv = func(string) string { return "" }
+		return app
+	}
+
+	buf := &bytes.Buffer{}
+	if err := newApp(buf).Run([]string{"current"}); err != nil {
+		t.Fatalf("current: %v", err)
+	}
+	if !strings.Contains(buf.String(), "older than the go 1.22 directive") || !strings.Contains(buf.String(), "govm install 1.22.0") {
+		t.Fatalf("missing go.mod warning:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := newApp(buf).Run([]string{"current", "--quiet"}); err != nil || buf.String() != "1.21.5\n" {
+		t.Fatalf("--quiet must stay bare: %q (%v)", buf.String(), err)
+	}
+	if err := newApp(&bytes.Buffer{}).Run([]string{"current", "--quiet", "--strict"}); err == nil {
+		t.Fatal("--strict must fail when go.mod needs a newer version")
+	}
+
+	newer := models.Version{Number: "1.22.3", InstallPath: "/v/go1.22.3"}
+	lister.local = append(lister.local, newer)
+	switcher := &fakeSwitcher{}
+	app := NewApp(&bytes.Buffer{}, lister, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test")
+	err := app.Run([]string{"use", "1.21.5", "--strict"})
+	if err == nil || !strings.Contains(err.Error(), "govm use 1.22.3") || len(switcher.used) != 0 {
+		t.Fatalf("use --strict must refuse and suggest the installed 1.22.3, got %v (used %v)", err, switcher.used)
+	}
+	if err := app.Run([]string{"use", "1.22.3", "--strict"}); err != nil || len(switcher.used) != 1 {
+		t.Fatalf("satisfying version must switch: %v", err)
+	}
+}
+
+func TestSyncInstallsAndActivatesToolchain(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.22\n\ntoolchain go1.22.4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	target := models.Version{Number: "1.22.4", FullName: "go1.22.4", DownloadURL: "https://go.dev/dl/go1.22.4.linux-amd64.tar.gz"}
+	lister := &fakeLister{remote: []models.Version{target}}
+	installer := &fakeInstaller{}
+	switcher := &fakeSwitcher{}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, installer, switcher, &fakeUninstaller{}, "test")
+	if err := app.Run([]string{"sync"}); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(installer.installed) != 1 || installer.installed[0].Number != "1.22.4" || len(switcher.used) != 1 || switcher.used[0] != "1.22.4" {
+		t.Fatalf("sync must install and use go1.22.4: installed=%v used=%v", installer.installed, switcher.used)
+	}
+	if !strings.Contains(buf.String(), "selects go1.22.4 (toolchain directive)") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	// 已安装且已激活时什么也不做。
+	lister.local = []models.Version{{Number: "1.22.4", InstallPath: "/v/go1.22.4", IsCurrent: true}}
+	buf.Reset()
+	if err := app.Run([]string{"sync"}); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if len(installer.installed) != 1 || len(switcher.used) != 1 || !strings.Contains(buf.String(), "Already using go1.22.4") {
+		t.Fatalf("sync must be idempotent: installed=%v used=%v\n%s", installer.installed, switcher.used, buf.String())
+	}
+}
@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// defaultInstallJobs 为一次安装多个版本时默认的并发下载数。
+const defaultInstallJobs = 3
+
+// installResult 记录批量安装中单个版本的结果。
+type installResult struct {
+	input   string
+	version string
+	status  string
+	elapsed time.Duration
+	err     error
+}
+
+// handleInstallMany 以最多 jobs 个并发安装多个版本，先整体解析并校验，再统一输出进度与结果汇总。
+// 批量模式下已安装的版本默认视为失败，--skip-existing 时跳过；--fail-fast 在首个失败后不再启动新的安装。
+func (a *App) handleInstallMany(inputs []string, opts installOptions, jobs int) error {
+	if a.installer == nil || a.lister == nil {
+		return errors.New("install command is unavailable")
+	}
+	if opts.silent || opts.globalPath {
+		return errors.New("--silent and --global-path accept a single version")
+	}
+	if jobs < 1 {
+		return fmt.Errorf("invalid --jobs %d: must be at least 1", jobs)
+	}
+
+	installed := map[string]bool{}
+	if local, err := a.lister.LocalVersions(); err == nil {
+		for _, v := range local {
+			if v.InstallPath != "" {
+				installed[v.Number] = true
+			}
+		}
+	}
+
+	results := make([]installResult, len(inputs))
+	targets := make([]*models.Version, len(inputs))
+	seen := map[string]bool{}
+	for i, input := range inputs {
+		results[i] = installResult{input: input, version: normalizeVersion(input)}
+		if isArtifactRef(input) {
+			results[i].err = errors.New("archive names and URLs are installed one at a time")
+			continue
+		}
+		number, err := a.installNumber(input, opts.noCatalog)
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+		results[i].version = number
+		if seen[results[i].version] {
+			results[i].status = "duplicate"
+			continue
+		}
+		if installed[results[i].version] {
+			seen[results[i].version] = true
+			if opts.skipExisting {
+				results[i].status = "skipped"
+			} else {
+				results[i].err = fmt.Errorf("go%s is already installed (pass --skip-existing to ignore)", results[i].version)
+			}
+			continue
+		}
+		target, err := a.resolveInstallTarget(results[i].version, resolveOptions{quiet: a.porcelain, noCatalog: opts.noCatalog})
+		if err == nil {
+			err = a.checkPolicy(target.Number)
+		}
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+		results[i].version = target.Number
+		seen[target.Number] = true
+		targets[i] = target
+	}
+	if opts.failFast && hasFailure(results) {
+		return a.printInstallResults(results)
+	}
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+
+	// 并发安装期间 porcelain 订阅者与进度行会同时写入输出，统一加锁避免交错。
+	out := &lockedWriter{w: a.out}
+	a.out = out
+	defer func() { a.out = out.w }()
+
+	var progress *multiProgress
+	if !a.porcelain {
+		progress = newMultiProgress(out, targets)
+		defer a.events.Subscribe(progress.handle)()
+	}
+
+	err := a.withLock(func() error {
+		sem := make(chan struct{}, jobs)
+		var failed atomic.Bool
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			if target == nil {
+				continue
+			}
+			wg.Add(1)
+			go func(i int, target models.Version) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				if opts.failFast && failed.Load() {
+					results[i].status = "cancelled"
+					progress.cancel(target.Number)
+					return
+				}
+				start := time.Now()
+				err := a.installer.Install(target)
+				results[i].elapsed = time.Since(start)
+				results[i].err = err
+				if err != nil {
+					failed.Store(true)
+					return
+				}
+				results[i].status = progress.status(target.Number)
+			}(i, *target)
+		}
+		wg.Wait()
+		return nil
+	})
+	progress.finish()
+	if err != nil {
+		return err
+	}
+	return a.printInstallResults(results)
+}
+
+// printInstallResults 输出批量安装的汇总表，存在失败项时返回错误。
+func (a *App) printInstallResults(results []installResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if !a.porcelain {
+		t := newTable("VERSION", "STATUS", "DETAIL")
+		for _, r := range results {
+			name := "go" + r.version
+			switch {
+			case r.err != nil:
+				t.addRow(name, "failed", r.err.Error())
+			case r.status == "duplicate":
+				t.addRow(name, "skipped", fmt.Sprintf("listed more than once (%s)", r.input))
+			case r.status == "skipped":
+				t.addRow(name, "skipped", "already installed")
+			case r.status == "cancelled", r.status == "":
+				t.addRow(name, "cancelled", "not started after an earlier failure")
+			default:
+				t.addRow(name, r.status, r.elapsed.Round(100*time.Millisecond).String())
+			}
+		}
+		if err := t.render(a.out); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("install: %d of %d version(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func hasFailure(results []installResult) bool {
+	for _, r := range results {
+		if r.err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// lockedWriter 为并发写入串行化底层输出。
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// multiProgress 订阅安装事件，把所有进行中的下载合并为一行进度，例如：
+//
+//	go1.21.8 45% | go1.22.3 12% | go1.23.0 waiting
+type multiProgress struct {
+	mu      sync.Mutex
+	out     io.Writer
+	order   []string
+	state   map[string]string
+	done    map[string]string
+	lastLen int
+}
+
+func newMultiProgress(out io.Writer, targets []*models.Version) *multiProgress {
+	p := &multiProgress{out: out, state: map[string]string{}, done: map[string]string{}}
+	for _, t := range targets {
+		if t == nil {
+			continue
+		}
+		p.order = append(p.order, t.Number)
+		p.state[t.Number] = "waiting"
+	}
+	return p
+}
+
+func (p *multiProgress) handle(e events.Event) {
+	ver := e.Get("version")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.state[ver]; !ok {
+		return
+	}
+	switch e.Type {
+	case events.DownloadStart:
+		p.state[ver] = "0%"
+	case events.DownloadProgress:
+		downloaded, _ := strconv.ParseInt(e.Get("downloaded"), 10, 64)
+		total, _ := strconv.ParseInt(e.Get("total"), 10, 64)
+		if total > 0 {
+			p.state[ver] = fmt.Sprintf("%d%%", downloaded*100/total)
+		} else {
+			p.state[ver] = fmt.Sprintf("%.1fMiB", float64(downloaded)/(1<<20))
+		}
+	case events.Extract:
+		p.state[ver] = "extracting"
+	case events.Done:
+		p.state[ver] = "done"
+		p.done[ver] = strings.ReplaceAll(e.Get("status"), "_", " ")
+	case events.Error:
+		p.state[ver] = "failed"
+	default:
+		return
+	}
+	p.renderLocked()
+}
+
+func (p *multiProgress) renderLocked() {
+	parts := make([]string, 0, len(p.order))
+	for _, ver := range p.order {
+		parts = append(parts, "go"+ver+" "+p.state[ver])
+	}
+	line := strings.Join(parts, " | ")
+	pad := ""
+	if n := p.lastLen - len(line); n > 0 {
+		pad = strings.Repeat(" ", n)
+	}
+	p.lastLen = len(line)
+	fmt.Fprint(p.out, "\r"+line+pad)
+}
+
+// status 返回版本安装完成后的状态描述，未收到事件时视为新安装。
+func (p *multiProgress) status(ver string) string {
+	if p == nil {
+		return "installed"
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := p.done[ver]; s != "" {
+		return s
+	}
+	return "installed"
+}
+
+// cancel 将未开始的版本标记为已取消。
+func (p *multiProgress) cancel(ver string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state[ver] = "cancelled"
+	p.renderLocked()
+}
+
+// finish 结束进度行，保证后续输出从新的一行开始。
+func (p *multiProgress) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lastLen > 0 {
+		fmt.Fprintln(p.out)
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"flag"
+	"time"
+
+	"github.com/liangyou/govm/internal/httpclient"
+)
+
+// NetworkConfigurer 允许根据命令行参数调整共享 HTTP 客户端的超时。
+type NetworkConfigurer interface {
+	Timeouts() httpclient.Timeouts
+	SetTimeouts(httpclient.Timeouts)
+}
+
+// WithNetwork 启用 -connect-timeout/-timeout/-download-timeout 以及 install --download-timeout。
+func WithNetwork(n NetworkConfigurer) AppOption {
+	return func(a *App) {
+		a.network = n
+	}
+}
+
+// timeoutFlags 保存全局超时参数，零值表示沿用配置文件中的设置。
+type timeoutFlags struct {
+	connect, request, download *time.Duration
+}
+
+func registerTimeoutFlags(fs *flag.FlagSet) timeoutFlags {
+	return timeoutFlags{
+		connect:  fs.Duration("connect-timeout", 0, "TCP connect and TLS handshake timeout"),
+		request:  fs.Duration("timeout", 0, "total timeout for metadata requests"),
+		download: fs.Duration("download-timeout", 0, "total timeout for downloading an archive"),
+	}
+}
+
+func (a *App) applyTimeoutFlags(f timeoutFlags) {
+	if a.network == nil {
+		return
+	}
+	t := a.network.Timeouts()
+	changed := false
+	for _, o := range []struct {
+		dst *time.Duration
+		src time.Duration
+	}{{&t.Connect, *f.connect}, {&t.Request, *f.request}, {&t.Download, *f.download}} {
+		if o.src > 0 {
+			*o.dst = o.src
+			changed = true
+		}
+	}
+	if changed {
+		a.network.SetTimeouts(t)
+	}
+}
+
+// setDownloadTimeout 应用 install --download-timeout，覆盖本次命令的下载总时长。
+func (a *App) setDownloadTimeout(d time.Duration) {
+	if a.network == nil || d <= 0 {
+		return
+	}
+	t := a.network.Timeouts()
+	t.Download = d
+	a.network.SetTimeouts(t)
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pluginPrefix 为插件可执行文件的名称前缀，`govm foo` 会执行 PATH 中的 govm-foo。
+const pluginPrefix = "govm-"
+
+// WithPlugins 启用插件：未知子命令交给 PATH 中的 govm-<name> 执行，root 通过 GOVM_PLUGIN_ROOT 传给插件。
+func WithPlugins(root string) AppOption {
+	return func(a *App) {
+		a.plugins = true
+		a.rootDir = root
+	}
+}
+
+// plugin 为 PATH 中发现的一个插件。
+type plugin struct {
+	Name string
+	Path string
+}
+
+// findPlugins 按 PATH 顺序列出插件，同名插件只保留最先出现的一个，与 shell 的查找规则一致。
+func (a *App) findPlugins() []plugin {
+	seen := map[string]bool{}
+	var plugins []plugin
+	for _, dir := range filepath.SplitList(a.getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), pluginPrefix)
+			if !ok || name == "" || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutableFile(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// lookupPlugin 返回名为 name 的插件路径，未找到时 ok 为 false。
+func (a *App) lookupPlugin(name string) (string, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	for _, dir := range filepath.SplitList(a.getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		if path := filepath.Join(dir, pluginPrefix+name); isExecutableFile(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// pluginEnv 返回传给插件的环境变量，描述 govm 的根目录、配置文件、自身版本与当前激活的版本。
+// 根目录与自身版本不以 GOVM_ROOT、GOVM_VERSION 导出：它们是 govm 与 go shim 的输入，插件回调 govm 或执行 go 时会被误用。
+func (a *App) pluginEnv() []string {
+	vars := []string{
+		"GOVM_PLUGIN_ROOT=" + a.rootDir,
+		"GOVM_CONFIG=" + a.configPath,
+		"GOVM_SELF_VERSION=" + a.version,
+	}
+	if exe, err := os.Executable(); err == nil {
+		vars = append(vars, "GOVM_BIN="+exe)
+	}
+	var active, goroot string
+	if a.lister != nil {
+		if current, err := a.lister.CurrentVersion(); err == nil && current != nil {
+			active, goroot = current.Number, current.InstallPath
+		}
+	}
+	return append(vars, "GOVM_ACTIVE_VERSION="+active, "GOVM_ACTIVE_GOROOT="+goroot)
+}
+
+// runPlugin 执行插件并透传输入输出；插件以非零状态退出时原样返回其退出码。
+func (a *App) runPlugin(path string, args []string) error {
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), a.pluginEnv()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = a.out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return silentExit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("plugin %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// handleUnknown 把未知子命令交给插件执行，没有对应插件时报告未知命令。
+func (a *App) handleUnknown(name string, args []string) error {
+	if a.plugins {
+		if path, ok := a.lookupPlugin(name); ok {
+			return a.runPlugin(path, args)
+		}
+	}
+	return fmt.Errorf("unknown command: %s", name)
+}
+
+func (a *App) handlePlugins(args []string) error {
+	if !a.plugins {
+		return errors.New("plugins are unavailable")
+	}
+	if len(args) > 0 {
+		return errors.New("plugins takes no arguments")
+	}
+	plugins := a.findPlugins()
+	if len(plugins) == 0 {
+		a.printf("No plugins found; put an executable named %s<name> on PATH to add `govm <name>`.\n", pluginPrefix)
+		return nil
+	}
+	tbl := newTable("plugin", "path")
+	for _, p := range plugins {
+		tbl.addRow(p.Name, p.Path)
+	}
+	return tbl.render(a.out)
+}
+
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestAppRunsPlugins(t *testing.T) {
+	t.Parallel()
+
+	first, second := t.TempDir(), t.TempDir()
+	script := "#!/bin/sh\necho \"$GOVM_PLUGIN_ROOT $GOVM_ACTIVE_VERSION $GOVM_ACTIVE_GOROOT $*\"\n"
+	if err := os.WriteFile(filepath.Join(first, "govm-hello"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(second, "govm-hello"), []byte("#!/bin/sh\necho shadowed\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(second, "govm-fail"), []byte("#!/bin/sh\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// 没有执行权限的文件不是插件。
+	if err := os.WriteFile(filepath.Join(second, "govm-notes"), []byte("text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{current: &models.Version{Number: "1.22.0", InstallPath: "/opt/go1.22.0"}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithPlugins("/srv/govm"))
+	app.getenv = func(key string) string {
+		if key == "PATH" {
+			return first + string(os.PathListSeparator) + second
+		}
+		return ""
+	}
+
+	if err := app.Run([]string{"hello", "a", "--flag"}); err != nil {
+		t.Fatalf("plugin failed: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "/srv/govm 1.22.0 /opt/go1.22.0 a --flag" {
+		t.Fatalf("unexpected plugin output: %q", got)
+	}
+	pluginEnv := app.pluginEnv()
+	for _, kv := range pluginEnv {
+		if strings.HasPrefix(kv, "GOVM_ROOT=") || strings.HasPrefix(kv, "GOVM_HOME=") || strings.HasPrefix(kv, "GOVM_VERSION=") {
+			t.Fatalf("plugins must not receive govm's own state as an input variable: %s", kv)
+		}
+	}
+	if !slices.Contains(pluginEnv, "GOVM_SELF_VERSION=test") {
+		t.Fatalf("plugins must receive the govm version: %v", pluginEnv)
+	}
+
+	var exitErr *ExitError
+	if err := app.Run([]string{"fail"}); !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("plugin exit code not propagated: %v", err)
+	}
+	if err := app.Run([]string{"notes"}); err == nil || !strings.Contains(err.Error(), "unknown command: notes") {
+		t.Fatalf("non-executable file must not run: %v", err)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"plugins"}); err != nil {
+		t.Fatalf("plugins failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, filepath.Join(first, "govm-hello")) || strings.Contains(out, filepath.Join(second, "govm-hello")) || !strings.Contains(out, "fail") {
+		t.Fatalf("unexpected plugin list: %q", out)
+	}
+}
+
+func TestAppPluginsDisabled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "govm-hello"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp(&bytes.Buffer{}, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	app.getenv = func(string) string { return dir }
+	if err := app.Run([]string{"hello"}); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Fatalf("plugins must be opt-in: %v", err)
+	}
+}
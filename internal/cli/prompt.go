@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompter 描述交互式提问，测试中可替换为返回固定回答的实现。
+type Prompter interface {
+	// Ask 输出问题并返回一行回答，直接回车或输入结束时返回 def。
+	Ask(question, def string) string
+	// Confirm 询问 y/n，无法识别的回答按 def 处理。
+	Confirm(question string, def bool) bool
+}
+
+// linePrompter 在 out 上提问并从 in 按行读取回答。
+type linePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newLinePrompter(in io.Reader, out io.Writer) *linePrompter {
+	return &linePrompter{in: bufio.NewReader(in), out: out}
+}
+
+func (p *linePrompter) Ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	answer := strings.TrimSpace(line)
+	if err != nil && answer == "" {
+		fmt.Fprintln(p.out)
+		return def
+	}
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+func (p *linePrompter) Confirm(question string, def bool) bool {
+	hint := "Y/n"
+	if !def {
+		hint = "y/N"
+	}
+	switch strings.ToLower(p.Ask(question, hint)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return def
+	}
+}
+
+// WithInput 指定交互式命令读取回答的输入，默认为标准输入。
+func WithInput(r io.Reader) AppOption {
+	return func(a *App) {
+		a.prompter = newLinePrompter(r, a.out)
+	}
+}
+
+// WithPrompter 替换交互式提问的实现。
+func WithPrompter(p Prompter) AppOption {
+	return func(a *App) {
+		a.prompter = p
+	}
+}
+
+// ask 输出问题并读取一行回答，直接回车或输入结束时返回默认值。
+func (a *App) ask(question, def string) string {
+	return a.prompter.Ask(question, def)
+}
+
+// askYesNo 读取 y/n 回答。
+func (a *App) askYesNo(question string, def bool) bool {
+	return a.prompter.Confirm(question, def)
+}
+
+// confirm 在执行破坏性操作前确认，默认回答为否；yes 或全局 -yes 时不再询问。
+func (a *App) confirm(question string, yes bool) bool {
+	return yes || a.assumeYes || a.prompter.Confirm(question, false)
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/liangyou/govm/internal/provision"
+)
+
+// ProvisionService 描述通过 SSH 把 Go 安装到远程主机的能力。
+type ProvisionService interface {
+	Provision(host, number string, opts provision.Options) (provision.Result, error)
+}
+
+// WithProvisioner 启用 `govm provision`。
+func WithProvisioner(p ProvisionService) AppOption {
+	return func(a *App) {
+		a.provisioner = p
+	}
+}
+
+// handleProvision 依次在每台主机上安装同一版本；单台失败时继续处理其余主机，最后汇总失败数。
+func (a *App) handleProvision(args []string) error {
+	if a.provisioner == nil {
+		return errors.New("provision command is unavailable")
+	}
+	fs := newCommandFlagSet("provision")
+	dir := fs.String("dir", "", "absolute versions directory on the remote host (default $HOME/.govm/versions)")
+	noEnv := fs.Bool("no-env", false, "install only, without writing the govm block to the remote shell config")
+	sshOpts := fs.String("ssh-opts", "", "extra arguments for ssh, e.g. \"-p 2222 -i ~/.ssh/build\"")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 2 {
+		return errors.New("provision command requires at least one host and a version")
+	}
+	if *dir != "" && !path.IsAbs(*dir) {
+		return fmt.Errorf("provision: --dir must be an absolute path, got %q", *dir)
+	}
+	hosts, number := rest[:len(rest)-1], normalizeVersion(rest[len(rest)-1])
+	if err := a.checkPolicy(number); err != nil {
+		return err
+	}
+	opts := provision.Options{Dir: *dir, NoEnv: *noEnv, GoPath: a.goPath, SSHOptions: strings.Fields(*sshOpts)}
+
+	failed := 0
+	for _, host := range hosts {
+		result, err := a.provisioner.Provision(host, number, opts)
+		if err != nil {
+			failed++
+			a.printf("Failed on %s: %v\n", host, err)
+			continue
+		}
+		if result.ShellConfig == "" {
+			a.printf("Installed %[1]s on %[2]s at %[3]s\n", result.Version.FullName, host, result.GoRoot)
+		} else {
+			a.printf("Installed %[1]s on %[2]s at %[3]s and updated %[4]s\n", result.Version.FullName, host, result.GoRoot, result.ShellConfig)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("provision: %d of %d host(s) failed", failed, len(hosts))
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/internal/prompt"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ErrNoActiveVersion 表示当前没有激活的 Go 版本。
+var ErrNoActiveVersion = errors.New("no active Go version")
+
+// ExitError 指定进程退出码；Err 为 nil 时不向 stderr 输出任何内容，供 --quiet 等脚本模式只通过退出码表达结果。
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// silentExit 返回只携带退出码、不输出提示的错误。
+func silentExit(code int) error {
+	return &ExitError{Code: code}
+}
+
+// WithGoPath 指定配置文件中的 GOPATH，`govm env` 在环境变量未设置时使用它。
+func WithGoPath(path string) AppOption {
+	return func(a *App) {
+		a.goPath = path
+	}
+}
+
+// WithGoToolchain 指定配置文件中的 goToolchain，`govm env` 与 --global-path 据此导出 GOTOOLCHAIN。
+func WithGoToolchain(value string) AppOption {
+	return func(a *App) {
+		a.goToolchain = value
+	}
+}
+
+// WithShellRC 指定配置文件中的 shellRC，安装完成后的提示据此给出需要 source 的文件。
+func WithShellRC(path string) AppOption {
+	return func(a *App) {
+		a.shellRC = path
+	}
+}
+
+// activeVersion 返回当前激活版本；quiet 时没有激活版本以退出码 1 表达，否则返回 ErrNoActiveVersion。
+func (a *App) activeVersion(quiet bool) (*models.Version, error) {
+	if a.lister == nil {
+		return nil, errors.New("current version query is unavailable")
+	}
+	current, err := a.lister.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		if quiet {
+			return nil, silentExit(1)
+		}
+		return nil, ErrNoActiveVersion
+	}
+	return current, nil
+}
+
+// handlePrompt 输出供 shell 提示符使用的当前版本；通常由 main 在加载服务之前直接处理，这里覆盖带全局参数的调用。
+func (a *App) handlePrompt(args []string) error {
+	if code := prompt.Run(a.out, a.out, args); code != 0 {
+		return silentExit(code)
+	}
+	return nil
+}
+
+func (a *App) handleCurrent(args []string) error {
+	fs := newCommandFlagSet("current")
+	quiet := fs.Bool("quiet", false, "print only the version number; exit 1 when none is active")
+	fs.BoolVar(quiet, "q", false, "shorthand for --quiet")
+	path := fs.Bool("path", false, "print only the GOROOT of the active version")
+	format := fs.String("format", a.format, "print the active version with a Go text/template")
+	strict := fs.Bool("strict", false, "exit non-zero when the active version is older than the current module requires")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	bare := *quiet || *path || *format != ""
+	if !bare {
+		a.warnDamage()
+		a.warnMarker()
+		a.warnSignature()
+	}
+	current, err := a.activeVersion(bare)
+	if errors.Is(err, ErrNoActiveVersion) {
+		a.println("No active Go version.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if *strict {
+		if err := a.checkModule(current.Number, true); err != nil {
+			return err
+		}
+	}
+	switch {
+	case *format != "":
+		return a.renderFormat(*format, []models.Version{*current})
+	case *path:
+		fmt.Fprintln(a.out, current.InstallPath)
+	case *quiet:
+		fmt.Fprintln(a.out, current.Number)
+	default:
+		a.printf("Current version: %s (%s)\n", displayName(*current), current.InstallPath)
+		return a.checkModule(current.Number, false)
+	}
+	return nil
+}
+
+// handleWhich 输出当前版本中某个工具（默认 go）的绝对路径。
+func (a *App) handleWhich(args []string) error {
+	fs := newCommandFlagSet("which")
+	quiet := fs.Bool("quiet", false, "exit 1 without a message when no version is active")
+	fs.BoolVar(quiet, "q", false, "shorthand for --quiet")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	tool := "go"
+	if len(rest) > 0 {
+		tool = rest[0]
+	}
+	if strings.ContainsRune(tool, filepath.Separator) {
+		return fmt.Errorf("which: invalid tool name %q", tool)
+	}
+	current, err := a.activeVersion(*quiet)
+	if err != nil {
+		return err
+	}
+	bin := filepath.Join(current.InstallPath, "bin", tool)
+	if info, err := os.Stat(bin); err != nil || info.IsDir() {
+		if *quiet {
+			return silentExit(1)
+		}
+		return fmt.Errorf("which: %s has no %s in %s", displayName(*current), tool, filepath.Dir(bin))
+	}
+	fmt.Fprintln(a.out, bin)
+	return nil
+}
+
+// handleEnv 输出当前版本的环境变量：默认为可 eval 的 export 语句，指定变量名或 --quiet 时只输出取值。
+func (a *App) handleEnv(args []string) error {
+	fs := newCommandFlagSet("env")
+	quiet := fs.Bool("quiet", false, "print only bare values (GOROOT when no name is given); exit 1 when none is active")
+	fs.BoolVar(quiet, "q", false, "shorthand for --quiet")
+	names, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	current, err := a.activeVersion(*quiet)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{
+		"GOROOT":    current.InstallPath,
+		"GOPATH":    a.resolveGoPath(),
+		"GOVERSION": "go" + current.Number,
+	}
+	exported := []string{"GOROOT", "GOPATH", "GOVERSION"}
+	if a.goToolchain != "" {
+		values["GOTOOLCHAIN"] = a.goToolchain
+		exported = append(exported, "GOTOOLCHAIN")
+	}
+	if len(names) == 0 && *quiet {
+		names = []string{"GOROOT"}
+	}
+	if len(names) > 0 {
+		for _, name := range names {
+			v, ok := values[strings.ToUpper(name)]
+			if !ok {
+				return fmt.Errorf("env: unknown variable %q (known: %s)", name, strings.Join(exported, ", "))
+			}
+			fmt.Fprintln(a.out, v)
+		}
+		return nil
+	}
+	for _, name := range exported {
+		a.printf("export %s='%s'\n", name, strings.ReplaceAll(values[name], "'", `'\''`))
+	}
+	fmt.Fprintln(a.out, `export PATH="$GOROOT/bin:$PATH"`)
+	return nil
+}
+
+// resolveModCache 返回 GOMODCACHE，未设置时与 go 命令一致取 GOPATH 第一项下的 pkg/mod。
+func (a *App) resolveModCache() string {
+	if v := strings.TrimSpace(a.getenv("GOMODCACHE")); v != "" {
+		return v
+	}
+	if list := filepath.SplitList(a.resolveGoPath()); len(list) > 0 && list[0] != "" {
+		return filepath.Join(list[0], "pkg", "mod")
+	}
+	return ""
+}
+
+// resolveGoPath 与 shell 配置块保持一致：优先环境变量，其次配置文件，最后 ~/go。
+func (a *App) resolveGoPath() string {
+	if v := strings.TrimSpace(a.getenv("GOPATH")); v != "" {
+		return v
+	}
+	if a.goPath != "" {
+		return a.goPath
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		return filepath.Join(home, "go")
+	}
+	return ""
+}
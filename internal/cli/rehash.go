@@ -0,0 +1,41 @@
+package cli
+
+import "errors"
+
+// ShimService 描述按已安装版本重建 shim 目录的能力。
+type ShimService interface {
+	Dir() string
+	Rehash(installs map[string]string) ([]string, error)
+}
+
+// WithShims 启用 `govm rehash`。
+func WithShims(s ShimService) AppOption {
+	return func(a *App) {
+		a.shims = s
+	}
+}
+
+func (a *App) handleRehash() error {
+	if a.shims == nil || a.lister == nil {
+		return errors.New("rehash command is unavailable")
+	}
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	installs := make(map[string]string, len(versions))
+	for _, v := range versions {
+		installs[v.Number] = v.InstallPath
+	}
+	var created []string
+	err = a.withLock(func() error {
+		var err error
+		created, err = a.shims.Rehash(installs)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	a.printf("Rehashed %d shim(s) for %d version(s) in %s\n", len(created), len(versions), a.shims.Dir())
+	return nil
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/goversion"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// VersionResolver 在版本目录不可用时推导版本的下载信息。
+type VersionResolver interface {
+	Resolve(number string) (models.Version, error)
+}
+
+// WithResolver 指定版本源不可达时用于推导下载地址的解析器。
+func WithResolver(r VersionResolver) AppOption {
+	return func(a *App) {
+		a.resolver = r
+	}
+}
+
+// ArtifactResolver 描述把归档文件名或下载 URL 解析为版本信息的能力。
+type ArtifactResolver interface {
+	ResolveArtifact(ref string) (models.Version, error)
+}
+
+// isArtifactRef 判断 install 的参数是归档文件名或 URL 而不是版本号。
+func isArtifactRef(input string) bool {
+	return strings.Contains(input, "://") || strings.HasSuffix(input, ".tar.gz")
+}
+
+// resolveArtifact 解析归档文件名或 URL；校验值取自 --sha256，否则在版本目录中按文件名查找，两者都没有时拒绝安装。
+func (a *App) resolveArtifact(ref, sha256 string) (*models.Version, error) {
+	resolver, ok := a.resolver.(ArtifactResolver)
+	if !ok {
+		return nil, errors.New("installing from an archive name or URL is unavailable")
+	}
+	target, err := resolver.ResolveArtifact(ref)
+	if err != nil {
+		return nil, err
+	}
+	if sha256 != "" {
+		if sum, err := hex.DecodeString(sha256); err != nil || len(sum) != 32 {
+			return nil, fmt.Errorf("invalid --sha256 %q: want 64 hex characters", sha256)
+		}
+		target.Checksum = strings.ToLower(sha256)
+		target.Provenance = &models.Provenance{ChecksumSource: models.ChecksumManual}
+		return &target, nil
+	}
+	versions, err := a.lister.RemoteVersions()
+	if err != nil {
+		return nil, fmt.Errorf("no checksum for %s (release list unavailable: %v); pass --sha256 to install it", target.FileName, err)
+	}
+	for _, v := range versions {
+		if v.FileName == target.FileName && v.Checksum != "" {
+			target.Checksum = v.Checksum
+			return &target, nil
+		}
+	}
+	return nil, fmt.Errorf("no checksum for %s (not in the release list); pass --sha256 to install it", target.FileName)
+}
+
+// resolveOptions 控制 resolveInstallTarget 的行为。
+type resolveOptions struct {
+	quiet     bool // 不输出回退提示（--silent/-porcelain）
+	noCatalog bool // 完全跳过版本目录，直接推导下载地址
+}
+
+// resolveInstallTarget 确定要安装的版本：已安装时不访问版本源；版本源不可达、目录中缺少该版本
+// 或指定 --no-catalog 时回退到按命名规则推导的下载地址。
+func (a *App) resolveInstallTarget(number string, opts resolveOptions) (*models.Version, error) {
+	if local, err := a.lister.LocalVersions(); err == nil {
+		for i := range local {
+			if local[i].Number == number && local[i].InstallPath != "" {
+				return &local[i], nil
+			}
+		}
+	}
+
+	if opts.noCatalog {
+		if a.resolver == nil {
+			return nil, errors.New("--no-catalog is unavailable")
+		}
+		target, err := a.resolver.Resolve(number)
+		if err != nil {
+			return nil, err
+		}
+		return &target, nil
+	}
+
+	versions, err := a.lister.RemoteVersions()
+	if err == nil {
+		target, findErr := findVersion(versions, number)
+		if findErr == nil {
+			return target, nil
+		}
+		err = findErr
+	}
+	if a.resolver == nil {
+		return nil, err
+	}
+	target, resolveErr := a.resolver.Resolve(number)
+	if resolveErr != nil {
+		return nil, err
+	}
+	if !opts.quiet {
+		a.printf("%s %v, downloading %s directly\n", colorize("warning:", colorYellow), err, target.DownloadURL)
+	}
+	return &target, nil
+}
+
+// installNumber 规范化 install 的版本参数；版本范围（例如 1.22.x）解析为版本列表中满足条件的最高版本。
+func (a *App) installNumber(input string, noCatalog bool) (string, error) {
+	number := normalizeVersion(input)
+	if !goversion.IsConstraint(number) {
+		return number, nil
+	}
+	if noCatalog {
+		return "", fmt.Errorf("version range %s needs the release list and cannot be used with --no-catalog", number)
+	}
+	return a.resolveRange(number, false)
+}
+
+// resolveRange 返回满足版本范围的最高版本：installed 时只在已安装版本中查找，否则在远程版本列表中查找。
+// 与 semver 一致，只有范围写明了预发布号时才会选中 beta/rc 版本。
+func (a *App) resolveRange(expr string, installed bool) (string, error) {
+	constraint, err := goversion.ParseConstraint(expr)
+	if err != nil {
+		return "", err
+	}
+	var versions []models.Version
+	if installed {
+		versions, err = a.lister.LocalVersions()
+	} else {
+		versions, err = a.lister.RemoteVersions()
+	}
+	if err != nil {
+		return "", err
+	}
+	var candidates []string
+	for _, v := range versions {
+		if !installed || v.InstallPath != "" {
+			candidates = append(candidates, v.Number)
+		}
+	}
+	best, ok := constraint.Best(candidates)
+	switch {
+	case ok:
+		return best, nil
+	case installed:
+		return "", fmt.Errorf("no installed version satisfies %s; install one with govm install '%s'", constraint, constraint)
+	default:
+		return "", fmt.Errorf("no released version satisfies %s", constraint)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/internal/selector"
+)
+
+// WithRunCache 指定 `govm run` 缓存各版本环境的目录，go 选择器也读取其中的缓存。
+func WithRunCache(dir string) AppOption {
+	return func(a *App) {
+		a.runCache = dir
+	}
+}
+
+// handleRun 以指定版本的环境执行命令：GOROOT 指向该版本，其 bin 目录排在 PATH 最前，并设置 GOVM_VERSION，
+// 使命令内部再调用的 go（经由 go 选择器）仍使用同一版本。命令以非零状态退出时原样返回其退出码。
+func (a *App) handleRun(args []string) error {
+	if a.lister == nil {
+		return errors.New("run command is unavailable")
+	}
+	if len(args) < 2 {
+		return errors.New("usage: govm run <version> <command> [args...]")
+	}
+	cmd, err := a.versionCommand(normalizeVersion(args[0]), args[1:])
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = a.out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return silentExit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("run: %w", err)
+	}
+	return nil
+}
+
+// versionCommand 构造以 number 的环境执行 command 的命令，不设置标准输入输出；`govm run` 与 `govm bench` 共用。
+func (a *App) versionCommand(number string, command []string) (*exec.Cmd, error) {
+	goRoot, err := a.runRoot(number)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(goRoot, "bin") + string(os.PathListSeparator) + os.Getenv("PATH")
+	name := filepath.Join(goRoot, "bin", command[0])
+	if strings.ContainsRune(command[0], filepath.Separator) || !isExecutableFile(name) {
+		if name, err = lookPathIn(command[0], path); err != nil {
+			return nil, fmt.Errorf("run: %w", err)
+		}
+	}
+	cmd := exec.Command(name, command[1:]...)
+	cmd.Env = append(os.Environ(), "GOROOT="+goRoot, "PATH="+path, "GOVM_VERSION="+number)
+	if a.goToolchain != "" {
+		cmd.Env = append(cmd.Env, "GOTOOLCHAIN="+a.goToolchain)
+	}
+	return cmd, nil
+}
+
+// runRoot 返回版本的 GOROOT：优先使用缓存且其中的 go 仍存在，否则查询已安装版本并刷新缓存。
+func (a *App) runRoot(number string) (string, error) {
+	if number == "" || strings.ContainsAny(number, `/\`) {
+		return "", fmt.Errorf("run: invalid version %q", number)
+	}
+	if root := selector.CachedRoot(a.runCache, number); root != "" {
+		return root, nil
+	}
+	root, err := a.installedRoot(number)
+	if err != nil {
+		return "", err
+	}
+	selector.SaveRoot(a.runCache, number, root)
+	return root, nil
+}
+
+// lookPathIn 在 pathEnv 中查找可执行文件；exec.LookPath 只看当前进程的 PATH，而这里需要包含目标版本的 bin 目录。
+func lookPathIn(name, pathEnv string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) {
+		if isExecutableFile(name) {
+			return name, nil
+		}
+		return "", fmt.Errorf("%s is not an executable file", name)
+	}
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			dir = "."
+		}
+		if candidate := filepath.Join(dir, name); isExecutableFile(candidate) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in PATH", name)
+}
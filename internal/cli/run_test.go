@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestRunUsesVersionEnvironmentAndCachesIt(t *testing.T) {
+	t.Parallel()
+
+	goRoot := filepath.Join(t.TempDir(), "go1.21.8")
+	if err := os.MkdirAll(filepath.Join(goRoot, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"$GOROOT $GOVM_VERSION $*\"\n[ \"$1\" != fail ] || exit 3\n"
+	if err := os.WriteFile(filepath.Join(goRoot, "bin", "go"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cache := filepath.Join(t.TempDir(), "run")
+	lister := &fakeLister{local: []models.Version{{Number: "1.21.8", InstallPath: goRoot}}}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithRunCache(cache))
+
+	if err := app.Run([]string{"run", "go1.21.8", "go", "build", "-h"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := buf.String(); got != goRoot+" 1.21.8 build -h\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(cache, "1.21.8")); string(data) != goRoot+"\n" {
+		t.Fatalf("GOROOT not cached: %q", data)
+	}
+
+	lister.local = nil
+	buf.Reset()
+	var exitErr *ExitError
+	if err := app.Run([]string{"run", "1.21.8", "go", "fail"}); !errors.As(err, &exitErr) || exitErr.Code != 3 || exitErr.Err != nil {
+		t.Fatalf("cached run must pass the exit code through, got %v", err)
+	}
+	if err := app.Run([]string{"run", "1.20.0", "go", "version"}); err == nil || !strings.Contains(err.Error(), "go1.20.0 is not installed") {
+		t.Fatalf("expected not installed error, got %v", err)
+	}
+	if err := app.Run([]string{"run", "1.21.8"}); err == nil {
+		t.Fatal("run without a command must fail")
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// MetadataRepairService 描述发现并修复无法解析的 metadata.json 的能力。
+type MetadataRepairService interface {
+	MetadataDamage() *storage.MetadataDamage
+	RepairMetadata() (int, error)
+}
+
+// WithMetadataRepair 启用损坏元数据的只读模式提示：list/current 输出警告，`govm doctor --repair` 用抢救出的条目重建文件。
+func WithMetadataRepair(s MetadataRepairService) AppOption {
+	return func(a *App) {
+		a.metadataRepair = s
+	}
+}
+
+// damageText 返回损坏情况的本地化描述。
+func (a *App) damageText(d *storage.MetadataDamage) string {
+	text := a.tr("metadata.json is corrupt (%[1]v); showing %[2]d salvaged entries read-only", d.Err, len(d.Salvaged))
+	if d.Backup != "" {
+		text += a.tr(", original saved to %s", d.Backup)
+	}
+	return text
+}
+
+// warnDamage 在 metadata.json 无法解析时输出一条警告。
+func (a *App) warnDamage() {
+	if a.metadataRepair == nil {
+		return
+	}
+	if d := a.metadataRepair.MetadataDamage(); d != nil {
+		a.printf("%s %s (run `govm doctor --repair` to fix)\n", colorize("warning:", colorYellow), a.damageText(d))
+	}
+}
+
+// doctorDamage 检查 metadata.json 能否解析；repair 为 true 时用抢救出的条目重写文件并以警告报告结果。
+func (a *App) doctorDamage(repair bool) ([]doctorIssue, error) {
+	if a.metadataRepair == nil {
+		return nil, nil
+	}
+	d := a.metadataRepair.MetadataDamage()
+	if d == nil {
+		return nil, nil
+	}
+	text := a.damageText(d)
+	if !repair {
+		return []doctorIssue{{text: a.tr("%s (run `govm doctor --repair` to fix)", text)}}, nil
+	}
+	kept, err := a.metadataRepair.RepairMetadata()
+	if err != nil {
+		return nil, err
+	}
+	return []doctorIssue{{warn: true, text: a.tr("%[1]s; rebuilt metadata.json with %[2]d entries, reinstall or `govm adopt` any missing versions", text, kept)}}, nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/liangyou/govm/internal/server"
+)
+
+func (a *App) handleServe(args []string) error {
+	if a.lister == nil || a.installer == nil || a.switcher == nil || a.uninstaller == nil {
+		return errors.New("serve command is unavailable")
+	}
+	fs := newCommandFlagSet("serve")
+	addr := fs.String("addr", server.DefaultAddr, "listen address")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var opts []server.Option
+	if a.locker != nil {
+		opts = append(opts, server.WithLocker(a.locker))
+	}
+	if a.policy != nil {
+		opts = append(opts, server.WithPolicy(a.policy))
+	}
+	srv := server.New(a.lister, a.installer, a.switcher, a.uninstaller, a.events, opts...)
+	return srv.ListenAndServe(ctx, *addr, func(bound net.Addr) {
+		a.printf("govm API listening on http://%s\n", bound)
+	})
+}
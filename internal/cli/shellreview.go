@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/liangyou/govm/internal/env"
+)
+
+// ShellReviewService 描述在改写 rc 文件前插入审阅回调的能力，由 env.Manager 实现。
+type ShellReviewService interface {
+	SetReview(fn env.ReviewFunc)
+}
+
+// WithShellReview 启用 `govm use --verbose/--confirm`：改写 .bashrc/.zshrc 前输出统一格式的差异。
+func WithShellReview(s ShellReviewService) AppOption {
+	return func(a *App) {
+		a.shellReview = s
+	}
+}
+
+// reviewShellChanges 让接下来的 rc 文件改写先输出差异；ask 为 true 时再请求确认，全局 -yes 跳过确认。
+// 返回的函数撤销审阅回调。
+func (a *App) reviewShellChanges(ask bool) func() {
+	if a.shellReview == nil {
+		return func() {}
+	}
+	a.shellReview.SetReview(func(path, before, after string) bool {
+		writeUnifiedDiff(a.out, path, before, after)
+		if !ask {
+			return true
+		}
+		return a.confirm(a.tr("Apply this change to %s?", path), false)
+	})
+	return func() { a.shellReview.SetReview(nil) }
+}
+
+// ShellCleanupReporter 描述报告 rc 文件中被清理的重复或旧版配置块的能力，由 env.Manager 实现；
+// 通过 WithShellReview 注册的服务实现了它时 `govm use` 会输出清理结果。
+type ShellCleanupReporter interface {
+	SetCleanupReport(fn env.CleanupFunc)
+}
+
+// reportShellCleanup 让接下来的 rc 文件改写输出清理掉的重复配置块、旧版配置块与多余的 GOROOT 导出。
+// 返回的函数撤销回调。
+func (a *App) reportShellCleanup() func() {
+	reporter, ok := a.shellReview.(ShellCleanupReporter)
+	if !ok {
+		return func() {}
+	}
+	reporter.SetCleanupReport(func(c env.Cleanup) {
+		if c.Duplicates > 0 {
+			a.printf("Merged %[1]d duplicate govm blocks in %[2]s\n", c.Duplicates, c.Path)
+		}
+		if c.Legacy > 0 {
+			a.printf("Replaced %[1]d govm blocks with outdated markers in %[2]s\n", c.Legacy, c.Path)
+		}
+		for _, line := range c.Exports {
+			a.printf("Removed stray %[1]s from %[2]s\n", line, c.Path)
+		}
+	})
+	return func() { reporter.SetCleanupReport(nil) }
+}
+
+// ShellConflictResolver 描述在写入配置块前检查 rc 文件与当前环境中冲突的 GOROOT/GOPATH 的能力，由 env.Manager 实现。
+type ShellConflictResolver interface {
+	SetConflictHandler(fn env.ConflictFunc)
+}
+
+// resolveShellConflicts 让接下来的 rc 文件改写先报告配置块之外指向其他位置的 GOROOT/GOPATH，并询问是否注释掉
+// rc 文件中的冲突行，拒绝时放弃切换；全局 -yes 直接注释掉。返回的函数撤销回调。
+func (a *App) resolveShellConflicts() func() {
+	resolver, ok := a.shellReview.(ShellConflictResolver)
+	if !ok {
+		return func() {}
+	}
+	resolver.SetConflictHandler(func(conflicts []env.Conflict) bool {
+		var path string
+		for _, c := range conflicts {
+			if c.Path == "" {
+				a.printf("%s %s\n", colorize("warning:", colorYellow), a.tr("%[1]s=%[2]s is set in the current environment and overrides the govm block until the shell is restarted", c.Var, c.Value))
+				continue
+			}
+			path = c.Path
+			a.printf("%s %s\n", colorize("warning:", colorYellow), a.tr("%[1]s sets %[2]s=%[3]s outside the govm block", c.Path, c.Var, c.Value))
+		}
+		if path == "" {
+			return true
+		}
+		return a.confirm(a.tr("Comment out the conflicting lines in %s?", path), false)
+	})
+	return func() { resolver.SetConflictHandler(nil) }
+}
+
+// diffContext 为差异中每处改动前后保留的未改动行数。
+const diffContext = 3
+
+// diffOp 是逐行差异中的一行：kind 为 ' '（未改动）、'-'（删除）或 '+'（新增）。
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// writeUnifiedDiff 以 diff -u 的格式输出 before 到 after 的改动，删除行标红、新增行标绿。
+func writeUnifiedDiff(w io.Writer, path, before, after string) {
+	ops := diffLines(splitLines(before), splitLines(after))
+	// oldAt[k]、newAt[k] 为 ops[k] 之前的旧、新行数，用于计算 hunk 头中的行号。
+	oldAt, newAt := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for k, op := range ops {
+		oldAt[k+1], newAt[k+1] = oldAt[k], newAt[k]
+		if op.kind != '+' {
+			oldAt[k+1]++
+		}
+		if op.kind != '-' {
+			newAt[k+1]++
+		}
+	}
+
+	fmt.Fprintln(w, colorize("--- "+path, colorRed))
+	fmt.Fprintln(w, colorize("+++ "+path, colorGreen))
+	for next := 0; next < len(ops); {
+		first := next
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		// 两处改动之间的未改动行不超过两倍上下文时合并为一个 hunk。
+		end := first
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				break
+			}
+			end = run
+		}
+		lo, hi := max(first-diffContext, next), min(end+diffContext, len(ops))
+		fmt.Fprintln(w, colorize(fmt.Sprintf("@@ -%s +%s @@", hunkRange(oldAt[lo], oldAt[hi]), hunkRange(newAt[lo], newAt[hi])), colorCyan))
+		for _, op := range ops[lo:hi] {
+			line := string(op.kind) + op.text
+			switch op.kind {
+			case '-':
+				line = colorize(line, colorRed)
+			case '+':
+				line = colorize(line, colorGreen)
+			}
+			fmt.Fprintln(w, line)
+		}
+		next = hi
+	}
+}
+
+// hunkRange 返回 hunk 头中的 start,count；没有行时按 diff -u 的约定使用前一行的行号。
+func hunkRange(from, to int) string {
+	if to == from {
+		return fmt.Sprintf("%d,0", from)
+	}
+	return fmt.Sprintf("%d,%d", from+1, to-from)
+}
+
+// splitLines 按行拆分文件内容，末尾换行不产生空行。
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines 基于最长公共子序列计算逐行差异；先去掉相同的首尾行，rc 文件的改动通常只涉及 govm 配置块。
+func diffLines(before, after []string) []diffOp {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix && before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	a, b := before[prefix:len(before)-suffix], after[prefix:len(after)-suffix]
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度。
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(before)+len(b))
+	for _, line := range before[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for _, line := range before[len(before)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/env"
+)
+
+func TestWriteUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	before := "alias ll='ls -l'\n\n# >>> govm initialize >>>\nexport GOROOT=\"/govm/go1.21.0\"\nexport PATH=\"$GOROOT/bin:$PATH\"\n# <<< govm initialize <<<\n"
+	after := strings.Replace(before, "go1.21.0", "go1.22.0", 1)
+	buf := &bytes.Buffer{}
+	writeUnifiedDiff(buf, "/home/dev/.bashrc", before, after)
+
+	want := []string{
+		colorize("--- /home/dev/.bashrc", colorRed),
+		colorize("+++ /home/dev/.bashrc", colorGreen),
+		colorize("@@ -1,6 +1,6 @@", colorCyan),
+		" alias ll='ls -l'",
+		" ",
+		" # >>> govm initialize >>>",
+		colorize(`-export GOROOT="/govm/go1.21.0"`, colorRed),
+		colorize(`+export GOROOT="/govm/go1.22.0"`, colorGreen),
+		` export PATH="$GOROOT/bin:$PATH"`,
+		" # <<< govm initialize <<<",
+	}
+	if got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected diff:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	writeUnifiedDiff(buf, "/home/dev/.zshrc", "", "# >>> govm initialize >>>\n# <<< govm initialize <<<\n")
+	if !strings.Contains(buf.String(), "@@ -0,0 +1,2 @@") {
+		t.Fatalf("new file must be diffed against an empty range:\n%s", buf.String())
+	}
+}
+
+// reviewingSwitcher 在 UseVersion 时把一次固定的 rc 文件改动交给审阅回调，模拟 env.Manager。
+type reviewingSwitcher struct {
+	fakeSwitcher
+	review env.ReviewFunc
+}
+
+func (r *reviewingSwitcher) SetReview(fn env.ReviewFunc) { r.review = fn }
+
+func (r *reviewingSwitcher) UseVersion(version string) error {
+	if r.review != nil && !r.review("/home/dev/.bashrc", "export GOROOT=\"/old\"\n", fmt.Sprintf("export GOROOT=\"/govm/go%s\"\n", version)) {
+		return fmt.Errorf("switcher: configure environment: %w", env.ErrShellConfigDeclined)
+	}
+	return r.fakeSwitcher.UseVersion(version)
+}
+
+func TestAppUseConfirmShowsShellDiff(t *testing.T) {
+	t.Parallel()
+
+	switcher := &reviewingSwitcher{}
+	prompt := &stubPrompter{answers: []bool{false, true}}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test",
+		WithShellReview(switcher), WithPrompter(prompt))
+
+	if err := app.Run([]string{"use", "--confirm", "1.22.0"}); err == nil || !strings.Contains(err.Error(), "go1.22.0 is not activated") {
+		t.Fatalf("declined change must fail, got %v", err)
+	}
+	if !strings.Contains(buf.String(), colorize(`+export GOROOT="/govm/go1.22.0"`, colorGreen)) || len(switcher.used) != 0 {
+		t.Fatalf("expected a diff and no switch:\n%s", buf.String())
+	}
+	if err := app.Run([]string{"use", "--confirm", "1.22.0"}); err != nil || len(switcher.used) != 1 {
+		t.Fatalf("confirmed change: %v, used %v", err, switcher.used)
+	}
+	if switcher.review != nil {
+		t.Fatal("the review hook must be removed after the command")
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"-yes", "use", "--confirm", "1.21.0"}); err != nil || len(prompt.questions) != 2 || len(switcher.used) != 2 {
+		t.Fatalf("-yes must skip the question: %v, asked %q", err, prompt.questions)
+	}
+	buf.Reset()
+	if err := app.Run([]string{"use", "--verbose", "1.21.0"}); err != nil || !strings.Contains(buf.String(), "--- /home/dev/.bashrc") {
+		t.Fatalf("--verbose must print the diff without asking: %v\n%s", err, buf.String())
+	}
+	buf.Reset()
+	if err := app.Run([]string{"use", "1.21.0"}); err != nil || strings.Contains(buf.String(), "---") {
+		t.Fatalf("plain use must not print a diff: %v\n%s", err, buf.String())
+	}
+}
+
+// conflictingSwitcher 在 UseVersion 时向冲突回调报告 rc 文件与当前环境中的 GOROOT，模拟 env.Manager。
+type conflictingSwitcher struct {
+	fakeSwitcher
+	handler env.ConflictFunc
+}
+
+func (c *conflictingSwitcher) SetReview(env.ReviewFunc) {}
+
+func (c *conflictingSwitcher) SetConflictHandler(fn env.ConflictFunc) { c.handler = fn }
+
+func (c *conflictingSwitcher) UseVersion(version string) error {
+	conflicts := []env.Conflict{
+		{Path: "/home/dev/.bashrc", Var: "GOROOT", Value: "/usr/local/go"},
+		{Var: "GOPATH", Value: "/srv/gopath"},
+	}
+	if c.handler != nil && !c.handler(conflicts) {
+		return fmt.Errorf("switcher: configure environment: %w", env.ErrShellConfigConflict)
+	}
+	return c.fakeSwitcher.UseVersion(version)
+}
+
+func TestAppUseAsksAboutConflictingExports(t *testing.T) {
+	t.Parallel()
+
+	switcher := &conflictingSwitcher{}
+	prompt := &stubPrompter{answers: []bool{false, true}}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, switcher, &fakeUninstaller{}, "test",
+		WithShellReview(switcher), WithPrompter(prompt))
+
+	if err := app.Run([]string{"use", "1.22.0"}); err == nil || !strings.Contains(err.Error(), "go1.22.0 is not activated") || len(switcher.used) != 0 {
+		t.Fatalf("declined conflicts must abort, got %v", err)
+	}
+	for _, want := range []string{"/home/dev/.bashrc sets GOROOT=/usr/local/go outside the govm block", "GOPATH=/srv/gopath is set in the current environment"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("missing %q:\n%s", want, buf.String())
+		}
+	}
+	if err := app.Run([]string{"use", "1.22.0"}); err != nil || len(switcher.used) != 1 {
+		t.Fatalf("accepted conflicts: %v, used %v", err, switcher.used)
+	}
+	if len(prompt.questions) != 2 || !strings.Contains(prompt.questions[0], "Comment out the conflicting lines in /home/dev/.bashrc?") {
+		t.Fatalf("unexpected questions %q", prompt.questions)
+	}
+	if switcher.handler != nil {
+		t.Fatal("the conflict handler must be removed after the command")
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// SignatureService 描述 metadata.json 签名的校验与重新签名能力。
+type SignatureService interface {
+	VerifyMetadata() error
+	AcceptMetadata() error
+}
+
+// WithMetadataSignature 启用 metadata.json 的签名检查：list/current 输出警告，`govm doctor --repair` 接受外部修改。
+func WithMetadataSignature(s SignatureService) AppOption {
+	return func(a *App) {
+		a.signature = s
+	}
+}
+
+// tamperedMetadata 为签名不符时的提示。
+const tamperedMetadata = "metadata.json was modified outside govm; review it, then run `govm doctor --repair` to accept the changes"
+
+// warnSignature 在 metadata.json 被 govm 之外修改时输出一条警告；其他校验错误交给 doctor 报告。
+func (a *App) warnSignature() {
+	if a.signature == nil {
+		return
+	}
+	if err := a.signature.VerifyMetadata(); errors.Is(err, storage.ErrMetadataTampered) {
+		a.printf("%s %s\n", colorize("warning:", colorYellow), a.tr(tamperedMetadata))
+	}
+}
+
+// doctorSignature 校验 metadata.json 的签名；repair 为 true 时以当前内容重新签名并以警告报告结果。
+func (a *App) doctorSignature(repair bool) ([]doctorIssue, error) {
+	if a.signature == nil {
+		return nil, nil
+	}
+	err := a.signature.VerifyMetadata()
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, storage.ErrMetadataTampered) {
+		return nil, err
+	}
+	if !repair {
+		return []doctorIssue{{text: a.tr(tamperedMetadata)}}, nil
+	}
+	if err := a.signature.AcceptMetadata(); err != nil {
+		return nil, err
+	}
+	return []doctorIssue{{warn: true, text: a.tr("metadata.json was modified outside govm; accepted the current contents and signed them again")}}, nil
+}
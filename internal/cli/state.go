@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/internal/state"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func (a *App) handleExport(args []string) error {
+	if a.lister == nil {
+		return errors.New("export command is unavailable")
+	}
+	fs := newCommandFlagSet("export")
+	format := fs.String("format", "", "manifest format: json or yaml")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	manifest := state.Manifest{Versions: make([]string, 0, len(versions))}
+	for _, v := range versions {
+		manifest.Versions = append(manifest.Versions, v.Number)
+		if v.IsCurrent {
+			manifest.Default = v.Number
+		}
+	}
+
+	if len(rest) == 0 {
+		return state.Encode(a.out, manifest, *format)
+	}
+
+	target := rest[0]
+	if *format == "" {
+		*format = formatFromExtension(target)
+	}
+	var buf bytes.Buffer
+	if err := state.Encode(&buf, manifest, *format); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("export: write manifest: %w", err)
+	}
+	a.printf("Exported %d versions to %s\n", len(manifest.Versions), target)
+	return nil
+}
+
+func (a *App) handleImport(args []string) error {
+	if a.lister == nil || a.installer == nil || a.switcher == nil {
+		return errors.New("import command is unavailable")
+	}
+	fs := newCommandFlagSet("import")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return errors.New("import command requires a manifest file")
+	}
+
+	file, err := os.Open(rest[0])
+	if err != nil {
+		return fmt.Errorf("import: open manifest: %w", err)
+	}
+	manifest, err := state.Decode(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	installed := map[string]struct{}{}
+	for _, v := range local {
+		installed[v.Number] = struct{}{}
+	}
+
+	var missing []string
+	for _, v := range manifest.Versions {
+		if _, ok := installed[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+
+	var failures []error
+	if len(missing) > 0 {
+		remoteVersions, err := a.lister.RemoteVersions()
+		if err != nil {
+			return err
+		}
+		for _, ver := range missing {
+			if err := a.installFromList(remoteVersions, ver); err != nil {
+				failures = append(failures, fmt.Errorf("install go%s: %w", ver, err))
+				continue
+			}
+			installed[ver] = struct{}{}
+			a.printf("Installed go%s\n", ver)
+		}
+	}
+	a.printf("Imported %d versions (%d already installed)\n", len(manifest.Versions)-len(failures), len(manifest.Versions)-len(missing))
+
+	if manifest.Default != "" {
+		if _, ok := installed[manifest.Default]; !ok {
+			failures = append(failures, fmt.Errorf("default version go%s is not installed", manifest.Default))
+		} else if err := a.checkPolicy(manifest.Default); err != nil {
+			failures = append(failures, err)
+		} else if err := a.switcher.UseVersion(manifest.Default); err != nil {
+			failures = append(failures, fmt.Errorf("use go%s: %w", manifest.Default, err))
+		} else {
+			a.printf("Now using go%s\n", manifest.Default)
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+// readInstallList 读取 install -f 指定的版本清单，格式与 import 相同，default 字段被忽略。
+func readInstallList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("install: open version list: %w", err)
+	}
+	defer file.Close()
+	manifest, err := state.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Versions, nil
+}
+
+func (a *App) installFromList(versions []models.Version, number string) error {
+	target, err := findVersion(versions, number)
+	if err != nil {
+		return err
+	}
+	if err := a.checkPolicy(target.Number); err != nil {
+		return err
+	}
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+	return a.withLock(func() error { return a.installer.Install(*target) })
+}
+
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return state.FormatYAML
+	default:
+		return state.FormatJSON
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestAppExportManifest(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{local: []models.Version{
+		{Number: "1.22.0", IsCurrent: true},
+		{Number: "1.21.5"},
+	}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"export", "--format", "yaml"}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	want := "default: 1.22.0\nversions:\n  - 1.22.0\n  - 1.21.5\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected export output:\n%s", buf.String())
+	}
+}
+
+func TestAppImportInstallsMissingAndUsesDefault(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "govm.json")
+	if err := os.WriteFile(path, []byte(`{"default":"1.22.0","versions":["1.21.5","1.22.0"]}`), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	installs := &fakeInstaller{}
+	switcher := &fakeSwitcher{}
+	lister := &fakeLister{
+		local:  []models.Version{{Number: "1.21.5"}},
+		remote: []models.Version{{Number: "1.22.0", FullName: "go1.22.0"}, {Number: "1.21.5", FullName: "go1.21.5"}},
+	}
+	app := NewApp(buf, lister, installs, switcher, &fakeUninstaller{}, "test")
+
+	if err := app.Run([]string{"import", path}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if len(installs.installed) != 1 || installs.installed[0].Number != "1.22.0" {
+		t.Fatalf("expected only missing version installed: %#v", installs.installed)
+	}
+	if len(switcher.used) != 1 || switcher.used[0] != "1.22.0" {
+		t.Fatalf("default not applied: %#v", switcher.used)
+	}
+	if !strings.Contains(buf.String(), "1 already installed") {
+		t.Fatalf("unexpected import output: %s", buf.String())
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// StatsService 描述本地使用统计的读取能力。
+type StatsService interface {
+	LoadUsage() (map[string]models.Usage, error)
+}
+
+// WithUsageStats 启用 `govm stats` 与列表中的使用统计展示。
+func WithUsageStats(s StatsService) AppOption {
+	return func(a *App) {
+		a.stats = s
+	}
+}
+
+func (a *App) handleStats() error {
+	if a.stats == nil {
+		return errors.New(`usage stats are disabled, set "usageStats": true in ~/.govm/config.json`)
+	}
+	if a.lister == nil {
+		return errors.New("stats command is unavailable")
+	}
+	versions, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	usage, err := a.stats.LoadUsage()
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		a.println("No versions installed.")
+		return nil
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return usage[versions[i].Number].LastUsedAt.After(usage[versions[j].Number].LastUsedAt)
+	})
+
+	a.println("Version usage:")
+	for _, v := range versions {
+		u := usage[v.Number]
+		line := fmt.Sprintf("  %-12s %4d uses  %s", "go"+v.Number, u.Count, formatLastUsed(u))
+		if u.Count == 0 && !v.IsCurrent {
+			line += "  (safe to prune)"
+		}
+		fmt.Fprintln(a.out, line)
+	}
+	return nil
+}
+
+func (a *App) loadUsage() map[string]models.Usage {
+	if a.stats == nil {
+		return nil
+	}
+	usage, err := a.stats.LoadUsage()
+	if err != nil {
+		return nil
+	}
+	return usage
+}
+
+func formatLastUsed(u models.Usage) string {
+	if u.LastUsedAt.IsZero() {
+		return "never used"
+	}
+	return "last used " + u.LastUsedAt.Local().Format(time.DateTime)
+}
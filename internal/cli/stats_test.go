@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeStats struct {
+	usage map[string]models.Usage
+}
+
+func (f *fakeStats) LoadUsage() (map[string]models.Usage, error) { return f.usage, nil }
+
+func TestAppStatsAndListUsage(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{local: []models.Version{
+		{Number: "1.21.0", InstallPath: "/opt/go1.21.0"},
+		{Number: "1.22.0", InstallPath: "/opt/go1.22.0", IsCurrent: true},
+	}}
+	stats := &fakeStats{usage: map[string]models.Usage{
+		"1.22.0": {Count: 5, LastUsedAt: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithUsageStats(stats))
+
+	if err := app.Run([]string{"stats"}); err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "go1.22.0") || !strings.Contains(lines[1], "5 uses") {
+		t.Fatalf("unexpected stats output:\n%s", out)
+	}
+	if !strings.Contains(lines[2], "never used") || !strings.Contains(lines[2], "safe to prune") {
+		t.Fatalf("unused version not flagged:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"-list"}); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], "USES") || !strings.Contains(lines[1], "go1.22.0") || !strings.Contains(lines[1], " 5 ") {
+		t.Fatalf("list output missing usage:\n%s", buf.String())
+	}
+}
+
+func TestAppStatsDisabled(t *testing.T) {
+	t.Parallel()
+
+	app := NewApp(&bytes.Buffer{}, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	if err := app.Run([]string{"stats"}); err == nil {
+		t.Fatal("expected error when usage stats are disabled")
+	}
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/goversion"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// CatalogCache 报告版本列表磁盘缓存最近一次刷新的时间，没有缓存时 ok 为 false。
+type CatalogCache interface {
+	CacheFetchedAt() (fetchedAt time.Time, ok bool)
+}
+
+// WithStatusSources 指定 `govm status` 展示的下载镜像与版本列表缓存，cache 可以为 nil。
+func WithStatusSources(mirror string, cache CatalogCache) AppOption {
+	return func(a *App) {
+		a.mirror = mirror
+		a.catalogCache = cache
+	}
+}
+
+// StatusReport 是 `govm status --json` 的输出结构。
+type StatusReport struct {
+	Active         string         `json:"active,omitempty"`
+	ActivePath     string         `json:"activePath,omitempty"`
+	Default        string         `json:"default,omitempty"`
+	Installed      int            `json:"installed"`
+	DiskUsage      int64          `json:"diskUsage"`
+	Mirror         string         `json:"mirror,omitempty"`
+	CacheFetchedAt *time.Time     `json:"cacheFetchedAt,omitempty"`
+	GoCaches       []GoCacheUsage `json:"goCaches"`
+	Updates        []StatusUpdate `json:"updates"`
+	UpdatesError   string         `json:"updatesError,omitempty"`
+}
+
+// StatusUpdate 描述某个已安装系列可升级到的最新补丁版本。
+type StatusUpdate struct {
+	Installed string `json:"installed"`
+	Latest    string `json:"latest"`
+}
+
+func (a *App) handleStatus(args []string) error {
+	if a.lister == nil {
+		return errors.New("status command is unavailable")
+	}
+	fs := newCommandFlagSet("status")
+	asJSON := fs.Bool("json", false, "print the summary as JSON")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	report, err := a.collectStatus()
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	a.printStatus(report)
+	return nil
+}
+
+// collectStatus 汇总本地状态；检查更新失败不影响其余字段，只记录在 UpdatesError 中。
+func (a *App) collectStatus() (StatusReport, error) {
+	report := StatusReport{Mirror: a.mirror, Updates: []StatusUpdate{}}
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return report, err
+	}
+	report.Installed = len(local)
+	for _, v := range local {
+		if v.IsCurrent {
+			report.Default = v.Number
+		}
+		if !v.External {
+			report.DiskUsage += dirSize(v.InstallPath)
+		}
+	}
+	if report.Default == "" {
+		if current, err := a.lister.CurrentVersion(); err == nil && current != nil {
+			report.Default = current.Number
+		}
+	}
+
+	check := env.CheckPath(a.getenv("PATH"), func(string) bool { return false })
+	report.ActivePath = check.Resolved
+	for _, v := range local {
+		if v.InstallPath != "" && check.Resolved == filepath.Join(v.InstallPath, "bin", "go") {
+			report.Active = v.Number
+		}
+	}
+
+	if a.catalogCache != nil {
+		if at, ok := a.catalogCache.CacheFetchedAt(); ok {
+			report.CacheFetchedAt = &at
+		}
+	}
+	report.GoCaches = a.goCacheUsage()
+
+	remote, err := a.lister.RemoteVersions()
+	if err != nil {
+		report.UpdatesError = err.Error()
+		return report, nil
+	}
+	report.Updates = pendingUpdates(local, remote)
+	return report, nil
+}
+
+// pendingUpdates 为每个已安装的稳定系列找出尚未安装的更新补丁版本。
+func pendingUpdates(local, remote []models.Version) []StatusUpdate {
+	installed := map[string]bool{}
+	newest := map[string]string{}
+	var order []string
+	for _, v := range local {
+		installed[v.Number] = true
+		if version.Channel(v.Number) != "stable" {
+			continue
+		}
+		series := goversion.Series(v.Number)
+		cur, ok := newest[series]
+		if !ok {
+			order = append(order, series)
+		}
+		if !ok || goversion.Compare(v.Number, cur) > 0 {
+			newest[series] = v.Number
+		}
+	}
+	latest := map[string]string{}
+	for _, v := range remote {
+		if version.Channel(v.Number) != "stable" {
+			continue
+		}
+		series := goversion.Series(v.Number)
+		if cur, ok := latest[series]; !ok || goversion.Compare(v.Number, cur) > 0 {
+			latest[series] = v.Number
+		}
+	}
+	updates := []StatusUpdate{}
+	for _, series := range order {
+		l, ok := latest[series]
+		if ok && !installed[l] && goversion.Compare(l, newest[series]) > 0 {
+			updates = append(updates, StatusUpdate{Installed: newest[series], Latest: l})
+		}
+	}
+	return updates
+}
+
+func (a *App) printStatus(r StatusReport) {
+	orNone := func(v string) string {
+		if v == "" {
+			return "(none)"
+		}
+		return v
+	}
+	active := orNone(r.Active)
+	switch {
+	case r.Active != "":
+		active = "go" + r.Active
+	case r.ActivePath != "":
+		active = fmt.Sprintf("(not managed by govm: %s)", r.ActivePath)
+	}
+	defaultVersion := orNone(r.Default)
+	if r.Default != "" {
+		defaultVersion = "go" + r.Default
+	}
+	cache := "(no cache)"
+	if r.CacheFetchedAt != nil {
+		cache = fmt.Sprintf("%s (%s ago)", r.CacheFetchedAt.Local().Format(time.RFC3339), time.Since(*r.CacheFetchedAt).Round(time.Minute))
+	}
+	a.printf("Active:      %s\n", active)
+	a.printf("Default:     %s\n", defaultVersion)
+	a.printf("Installed:   %d (%s)\n", r.Installed, formatSize(r.DiskUsage))
+	a.printf("Mirror:      %s\n", orNone(r.Mirror))
+	a.printf("Catalog:     %s\n", cache)
+	for _, c := range r.GoCaches {
+		label, clean := "Build cache:", "govm clean --gocache"
+		if c.Name == "GOMODCACHE" {
+			label, clean = "Mod cache:", "govm clean --modcache"
+		}
+		a.printf("%-12s %s (%s, managed by go; %s)\n", a.tr(label), formatSize(c.Size), c.Path, highlightCommand(clean))
+	}
+	switch {
+	case r.UpdatesError != "":
+		a.printf("Updates:     unknown (%s)\n", r.UpdatesError)
+	case len(r.Updates) == 0:
+		a.println("Updates:     up to date")
+	default:
+		a.printf("Updates:     %d available\n", len(r.Updates))
+		for _, u := range r.Updates {
+			a.printf("  go%s -> go%s  (%s)\n", u.Installed, u.Latest, highlightCommand("govm install "+u.Latest))
+		}
+	}
+}
+
+// dirSize 统计目录中普通文件的总大小，读取失败的条目被忽略。
+func dirSize(root string) int64 {
+	if root == "" {
+		return 0
+	}
+	var total int64
+	filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
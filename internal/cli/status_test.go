@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeCatalogCache struct{ at time.Time }
+
+func (f fakeCatalogCache) CacheFetchedAt() (time.Time, bool) { return f.at, !f.at.IsZero() }
+
+func TestPendingUpdates(t *testing.T) {
+	t.Parallel()
+
+	local := []models.Version{{Number: "1.21.3"}, {Number: "1.21.8"}, {Number: "1.22.0"}, {Number: "1.23rc1"}}
+	remote := []models.Version{{Number: "1.23.1"}, {Number: "1.22.4"}, {Number: "1.22.3"}, {Number: "1.21.8"}, {Number: "1.21.9rc1"}}
+	got := pendingUpdates(local, remote)
+	want := []StatusUpdate{{Installed: "1.22.0", Latest: "1.22.4"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("pendingUpdates = %#v, want %#v", got, want)
+	}
+}
+
+func TestAppStatusJSON(t *testing.T) {
+	t.Parallel()
+
+	goroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(goroot, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(goroot, "bin", "go"), []byte("1234"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fetched := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	lister := &fakeLister{
+		local:  []models.Version{{Number: "1.22.0", InstallPath: goroot, IsCurrent: true}},
+		remote: []models.Version{{Number: "1.22.1"}},
+	}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test",
+		WithStatusSources("https://go.dev/dl/", fakeCatalogCache{at: fetched}))
+	app.getenv = func(key string) string {
+		if key == "PATH" {
+			return filepath.Join(goroot, "bin")
+		}
+		return ""
+	}
+
+	if err := app.Run([]string{"status", "--json"}); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	var report StatusReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if report.Active != "1.22.0" || report.Default != "1.22.0" || report.Installed != 1 || report.DiskUsage != 4 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+	if report.Mirror != "https://go.dev/dl/" || report.CacheFetchedAt == nil || !report.CacheFetchedAt.Equal(fetched) {
+		t.Fatalf("unexpected sources: %#v", report)
+	}
+	if len(report.Updates) != 1 || report.Updates[0].Latest != "1.22.1" {
+		t.Fatalf("unexpected updates: %#v", report.Updates)
+	}
+
+	buf.Reset()
+	lister.remoteErr = errors.New("offline")
+	if err := app.Run([]string{"status"}); err != nil {
+		t.Fatalf("status should tolerate catalog errors: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Updates:     unknown (offline)") || !strings.Contains(buf.String(), "Active:      go1.22.0") {
+		t.Fatalf("unexpected text status:\n%s", buf.String())
+	}
+}
+
+func TestAppStatusGoCachesAndClean(t *testing.T) {
+	t.Parallel()
+
+	goroot := t.TempDir()
+	gocache, modcache := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(gocache, "a-d"), []byte("123456"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(modcache, "m.zip"), []byte("12"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lister := &fakeLister{current: &models.Version{Number: "1.22.0", InstallPath: goroot}}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test")
+	app.getenv = func(string) string { return "" }
+	var calls []string
+	app.runGo = func(goBin string, stdout io.Writer, args ...string) error {
+		if goBin != filepath.Join(goroot, "bin", "go") {
+			t.Errorf("unexpected go binary %s", goBin)
+		}
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "env":
+			fmt.Fprintf(stdout, "%s\n%s\n", gocache, modcache)
+		case "clean":
+			return os.Remove(filepath.Join(gocache, "a-d"))
+		}
+		return nil
+	}
+
+	if err := app.Run([]string{"status", "--json"}); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	var report StatusReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	want := []GoCacheUsage{{Name: "GOCACHE", Path: gocache, Size: 6}, {Name: "GOMODCACHE", Path: modcache, Size: 2}}
+	if !reflect.DeepEqual(report.GoCaches, want) {
+		t.Fatalf("GoCaches = %#v, want %#v", report.GoCaches, want)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"clean", "--gocache"}); err != nil {
+		t.Fatalf("clean failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Freed 6 B from GOCACHE") {
+		t.Fatalf("unexpected clean output: %q", buf.String())
+	}
+	if got := calls[len(calls)-1]; got != "clean -cache" {
+		t.Fatalf("last go invocation = %q", got)
+	}
+	if err := app.Run([]string{"clean"}); err == nil {
+		t.Fatal("clean without a target must fail")
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/liangyou/govm/internal/version"
+)
+
+// handleSync 按当前模块的 toolchain 指令（没有时按 go 指令）安装并激活对应版本，
+// 与 Go 1.21 起的工具链选择一致，但版本由 govm 管理而不是下载到模块缓存。
+func (a *App) handleSync(args []string) error {
+	if a.installer == nil || a.lister == nil || a.switcher == nil {
+		return errors.New("sync command is unavailable")
+	}
+	fs := newCommandFlagSet("sync")
+	noUse := fs.Bool("no-use", false, "install the version without activating it")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("sync takes no arguments, got %q", rest[0])
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	req, ok, err := version.FindModule(dir, a.getenv("GOWORK"))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("sync: no go.mod or go.work in the current directory or its parents")
+	}
+	target, directive := req.Toolchain, "toolchain"
+	if target == "" {
+		if req.Go == "" {
+			return fmt.Errorf("sync: %s has neither a toolchain nor a go directive", req.Path)
+		}
+		target, directive = releaseVersion(req.Go), "go"
+	}
+	a.printf("%[1]s selects go%[2]s (%[3]s directive)\n", req.Path, target, directive)
+
+	local, err := a.lister.LocalVersions()
+	if err != nil {
+		return err
+	}
+	if findInstallPath(local, target) == "" {
+		if err := a.handleInstall(target, installOptions{}); err != nil {
+			return err
+		}
+	}
+	if *noUse {
+		return nil
+	}
+	if a.markedVersion() == target {
+		a.printf("Already using go%s\n", target)
+		return nil
+	}
+	return a.handleUse(target, false)
+}
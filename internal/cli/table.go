@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// table 是列表类命令共用的对齐表格，表头统一大写。
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func newTable(headers ...string) *table {
+	return &table{headers: headers}
+}
+
+func (t *table) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// render 以两个空格为列间距输出表格，不包含颜色以保证列对齐。
+func (t *table) render(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(t.headers))
+	for i, h := range t.headers {
+		headers[i] = strings.ToUpper(h)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// remoteTable 构建远程版本表格，当前版本以 * 、其他已安装版本以 + 标记；wide 时附加归档大小与下载地址。
+func remoteTable(versions []models.Version, wide bool) *table {
+	headers := []string{"", "version", "channel", "arch"}
+	if wide {
+		headers = append(headers, "size", "url")
+	}
+	t := newTable(headers...)
+	for _, v := range versions {
+		marker := " "
+		switch {
+		case v.IsCurrent:
+			marker = "*"
+		case v.InstallPath != "":
+			marker = "+"
+		}
+		row := []string{marker, displayName(v), version.ChannelOf(v), v.OS + "/" + v.Arch}
+		if wide {
+			row = append(row, formatSize(v.Size), v.DownloadURL)
+		}
+		t.addRow(row...)
+	}
+	return t
+}
+
+// seriesPreview 为非 wide 模式下每个系列最多列出的版本数。
+const seriesPreview = 6
+
+// seriesTable 构建按 major.minor 分组的远程版本表格，每个系列一行，最新版本在前。
+func seriesTable(groups []version.Series, wide bool) *table {
+	t := newTable("series", "latest", "releases", "versions")
+	for _, g := range groups {
+		list := g.Versions
+		more := ""
+		if !wide && len(list) > seriesPreview {
+			more = fmt.Sprintf(" ... (+%d)", len(list)-seriesPreview)
+			list = list[:seriesPreview]
+		}
+		t.addRow(g.Name, "go"+g.Versions[0], fmt.Sprintf("%d", len(g.Versions)), strings.Join(list, " ")+more)
+	}
+	return t
+}
+
+// localTable 构建本地版本表格，当前版本以 * 标记；启用使用统计时附加使用次数列。
+func localTable(versions []models.Version, wide bool, usage map[string]models.Usage) *table {
+	headers := []string{"", "version", "path"}
+	if wide {
+		headers = append(headers, "channel", "arch", "installed", "source")
+	}
+	if usage != nil {
+		headers = append(headers, "uses", "last used")
+	}
+	t := newTable(headers...)
+	for _, v := range versions {
+		marker := " "
+		if v.IsCurrent {
+			marker = "*"
+		}
+		path := v.InstallPath
+		if path == "" {
+			path = "(unknown path)"
+		}
+		if v.External {
+			path += " (external)"
+		}
+		if v.Locked {
+			path += " (locked)"
+		}
+		row := []string{marker, displayName(v), path}
+		if wide {
+			arch := "-"
+			if v.Arch != "" {
+				arch = v.OS + "/" + v.Arch
+			}
+			row = append(row, version.ChannelOf(v), arch, formatDate(v.InstalledAt), provenanceLabel(v.Provenance))
+		}
+		if usage != nil {
+			u := usage[v.Number]
+			last := "never"
+			if !u.LastUsedAt.IsZero() {
+				last = u.LastUsedAt.Local().Format(time.DateTime)
+			}
+			row = append(row, fmt.Sprintf("%d", u.Count), last)
+		}
+		t.addRow(row...)
+	}
+	return t
+}
+
+// provenanceLabel 概括归档来源：镜像主机与校验值来源，复用缓存时标记 cached，未记录时为 -。
+func provenanceLabel(p *models.Provenance) string {
+	if p == nil {
+		return "-"
+	}
+	origin := "cached"
+	if !p.Cached {
+		origin = strings.TrimPrefix(strings.TrimPrefix(p.Mirror, "https://"), "http://")
+		if origin == "" {
+			origin = "-"
+		}
+	}
+	if p.ChecksumSource == "" {
+		return origin
+	}
+	return origin + " (" + p.ChecksumSource + ")"
+}
+
+func displayName(v models.Version) string {
+	if v.FullName != "" {
+		return v.FullName
+	}
+	return "go" + v.Number
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// formatSize 以 1024 进制输出可读的文件大小，未知时返回 "-"。
+func formatSize(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
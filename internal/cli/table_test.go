@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestTableAlignsColumns(t *testing.T) {
+	t.Parallel()
+
+	versions := []models.Version{
+		{Number: "1.22.0", FullName: "go1.22.0", OS: "linux", Arch: "amd64", Size: 68 << 20, DownloadURL: "https://go.dev/dl/go1.22.0.linux-amd64.tar.gz", InstallPath: "/opt/go1.22.0"},
+		{Number: "1.23rc1", FullName: "go1.23rc1", OS: "linux", Arch: "arm64"},
+	}
+	buf := &bytes.Buffer{}
+	if err := remoteTable(versions, true).render(buf); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected table:\n%s", buf.String())
+	}
+	col := strings.Index(lines[0], "CHANNEL")
+	if !strings.HasPrefix(lines[1][col:], "stable") || !strings.HasPrefix(lines[2][col:], "rc ") {
+		t.Fatalf("channel column not aligned:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[1], "68.0 MiB") || !strings.HasSuffix(strings.TrimRight(lines[2], " "), "  -") {
+		t.Fatalf("size column missing:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "+ ") || !strings.HasPrefix(lines[2], "  ") {
+		t.Fatalf("installed marker missing:\n%s", buf.String())
+	}
+}
+
+func TestLocalTableWideColumns(t *testing.T) {
+	t.Parallel()
+
+	installed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)
+	versions := []models.Version{{Number: "1.22.0", InstallPath: "/opt/go1.22.0", IsCurrent: true, OS: "linux", Arch: "amd64", InstalledAt: installed}}
+	buf := &bytes.Buffer{}
+	if err := localTable(versions, true, nil).render(buf); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"INSTALLED", "* ", "go1.22.0", "/opt/go1.22.0", "linux/amd64", "2024-03-01"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("local table missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/liangyou/govm/internal/timing"
+)
+
+// TimingSource 提供本次运行中地区探测、版本列表、下载与解压的耗时，由 timing.Recorder 实现。
+type TimingSource interface {
+	Phases() []timing.Phase
+}
+
+// WithTimings 启用全局 -verbose 的耗时汇总。
+func WithTimings(src TimingSource) AppOption {
+	return func(a *App) {
+		a.timings = src
+	}
+}
+
+// printTimings 在命令结束后输出各阶段耗时，没有任何阶段发生时只输出一行说明。
+func (a *App) printTimings() {
+	if a.timings == nil {
+		return
+	}
+	phases := a.timings.Phases()
+	if len(phases) == 0 {
+		a.printf("%s no network or install step ran\n", colorize("timing:", colorCyan))
+		return
+	}
+	a.printf("%s\n", colorize("timing:", colorCyan))
+	tbl := newTable("phase", "time", "count")
+	for _, p := range phases {
+		tbl.addRow(a.tr(p.Name), p.Duration.Round(time.Millisecond).String(), fmt.Sprint(p.Count))
+	}
+	// 汇总在命令输出之后，写入失败不改变命令本身的结果。
+	_ = tbl.render(a.out)
+}
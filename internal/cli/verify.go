@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+)
+
+// VerifyService 描述安装目录完整性校验能力。
+type VerifyService interface {
+	Verify(version string) (version.VerifyReport, error)
+}
+
+// WithVerifier 启用 `govm verify`。
+func WithVerifier(v VerifyService) AppOption {
+	return func(a *App) {
+		a.verifier = v
+	}
+}
+
+func (a *App) handleVerify(args []string) error {
+	if a.verifier == nil || a.lister == nil {
+		return errors.New("verify command is unavailable")
+	}
+	fs := newCommandFlagSet("verify")
+	all := fs.Bool("all", false, "verify every installed version")
+	rest, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var targets []string
+	switch {
+	case *all:
+		versions, err := a.lister.LocalVersions()
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			if !v.External {
+				targets = append(targets, v.Number)
+			}
+		}
+	case len(rest) > 0:
+		targets = append(targets, normalizeVersion(rest[0]))
+	default:
+		current, err := a.lister.CurrentVersion()
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return errors.New("verify requires a version, --all, or an active version")
+		}
+		targets = append(targets, current.Number)
+	}
+	if len(targets) == 0 {
+		a.println("No versions installed.")
+		return nil
+	}
+
+	drifted := 0
+	for _, ver := range targets {
+		report, err := a.verifier.Verify(ver)
+		if errors.Is(err, storage.ErrNoManifest) {
+			a.printf("go%s: %s no file manifest recorded, reinstall to enable verification\n", ver, colorize("skipped:", colorYellow))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if report.OK() {
+			a.printf("go%s: %s (%d files)\n", ver, colorize("OK", colorBoldGreen), report.Checked)
+			continue
+		}
+		drifted++
+		a.printf("go%s: %s %d modified, %d missing (%d files checked)\n",
+			ver, colorize("DRIFT", colorYellow), len(report.Modified), len(report.Missing), report.Checked)
+		for _, p := range report.Modified {
+			a.printf("  modified: %s\n", p)
+		}
+		for _, p := range report.Missing {
+			a.printf("  missing:  %s\n", p)
+		}
+	}
+	if drifted > 0 {
+		return fmt.Errorf("verify: %d version(s) differ from their install manifest", drifted)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeVerifier struct {
+	reports map[string]version.VerifyReport
+}
+
+func (f *fakeVerifier) Verify(ver string) (version.VerifyReport, error) {
+	report, ok := f.reports[ver]
+	if !ok {
+		return version.VerifyReport{}, storage.ErrNoManifest
+	}
+	return report, nil
+}
+
+func TestAppVerifyAll(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{local: []models.Version{{Number: "1.22.0"}, {Number: "1.21.0"}, {Number: "1.20.0"}}}
+	verifier := &fakeVerifier{reports: map[string]version.VerifyReport{
+		"1.22.0": {Version: "1.22.0", Checked: 10},
+		"1.21.0": {Version: "1.21.0", Checked: 10, Modified: []string{"src/fmt/print.go"}},
+	}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithVerifier(verifier))
+
+	err := app.Run([]string{"verify", "--all"})
+	if err == nil {
+		t.Fatal("expected drift to produce an error")
+	}
+	out := buf.String()
+	for _, want := range []string{"go1.22.0: ", "(10 files)", "modified: src/fmt/print.go", "go1.20.0: ", "no file manifest"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("verify output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAppDoctorReportsUserAddedFiles(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	lister := &fakeLister{local: []models.Version{{Number: "1.22.0"}}}
+	verifier := &fakeVerifier{reports: map[string]version.VerifyReport{
+		"1.22.0": {Version: "1.22.0", Checked: 10, Added: []string{"bin/mytool"}},
+	}}
+	app := NewApp(buf, lister, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithVerifier(verifier))
+
+	if err := app.Run([]string{"doctor"}); err != nil {
+		t.Fatalf("user-added files should only warn: %v", err)
+	}
+	if !strings.Contains(buf.String(), "user-added file in GOROOT: bin/mytool") {
+		t.Fatalf("doctor output missing user-added file:\n%s", buf.String())
+	}
+}
+
+type fakeManifests map[string]models.FileManifest
+
+func (f fakeManifests) LoadManifest(ver string) (models.FileManifest, error) {
+	m, ok := f[ver]
+	if !ok {
+		return models.FileManifest{}, storage.ErrNoManifest
+	}
+	return m, nil
+}
+
+func TestAppDiff(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	manifests := fakeManifests{
+		"1.22.0": {Version: "1.22.0", Files: []models.FileEntry{{Path: "VERSION", SHA256: "a"}}},
+		"1.22.1": {Version: "1.22.1", Files: []models.FileEntry{{Path: "VERSION", SHA256: "b"}, {Path: "src/new.go", SHA256: "c"}}},
+	}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithManifests(manifests))
+
+	if err := app.Run([]string{"diff", "go1.22.0", "1.22.1"}); err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"1 added, 0 removed, 1 changed", "+ src/new.go", "~ VERSION"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("diff output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+type fakeReleaseNotes []models.ReleaseNote
+
+func (f fakeReleaseNotes) ReleaseNotes(bool) ([]models.ReleaseNote, error) { return f, nil }
+
+func TestAppDiffNotesFollowsUpgradePath(t *testing.T) {
+	t.Parallel()
+
+	notes := fakeReleaseNotes{
+		{Version: "1.22.3", Date: "2024-05-07", Summary: "Includes security fixes to net/http.", Security: true},
+		{Version: "1.22.2", Date: "2024-04-03", Summary: "Includes bug fixes to the compiler."},
+		{Version: "1.22.0", Date: "2024-02-06", Summary: "Go 1.22 is a major release of Go."},
+		{Version: "1.21.9", Date: "2024-04-03", Summary: "Backported fixes."},
+		{Version: "1.21.8", Date: "2024-03-05", Summary: "Already installed."},
+	}
+	buf := &bytes.Buffer{}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test",
+		WithManifests(fakeManifests{}), WithReleaseNotes(notes))
+
+	if err := app.Run([]string{"diff", "1.22.2", "go1.21.8", "--notes"}); err != nil {
+		t.Fatalf("diff --notes: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "go1.21.8 -> go1.22.2: 2 release(s), 0 with security fixes\n") {
+		t.Fatalf("unexpected summary:\n%s", out)
+	}
+	if strings.Contains(out, "1.21.9") || strings.Contains(out, "1.22.3") || strings.Index(out, "go1.22.0") > strings.Index(out, "go1.22.2 (") {
+		t.Fatalf("only the upgrade path must be listed, oldest first:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"diff", "1.22.0", "1.22.3", "--notes", "--stat"}); err != nil || buf.String() != "go1.22.0 -> go1.22.3: 2 release(s), 1 with security fixes\n" {
+		t.Fatalf("unexpected --stat output %q, %v", buf.String(), err)
+	}
+	if err := app.Run([]string{"diff", "1.21.8", "1.22.3"}); err == nil || !strings.Contains(err.Error(), "use --notes") {
+		t.Fatalf("missing manifests must suggest --notes, got %v", err)
+	}
+}
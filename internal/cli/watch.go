@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// WatchService 描述监听当前版本变化的能力。
+type WatchService interface {
+	WatchCurrent(ctx context.Context, interval time.Duration) (string, <-chan storage.CurrentChange, error)
+}
+
+// WithWatcher 启用 `govm watch`。
+func WithWatcher(s WatchService) AppOption {
+	return func(a *App) {
+		a.watcher = s
+	}
+}
+
+// handleWatch 先输出当前版本，之后每次变化输出一行，直到收到中断信号。
+func (a *App) handleWatch(args []string) error {
+	if a.watcher == nil {
+		return errors.New("watch command is unavailable")
+	}
+	fs := newCommandFlagSet("watch")
+	interval := fs.Duration("interval", storage.DefaultWatchInterval, "how often to check the current version marker")
+	asJSON := fs.Bool("json", false, "print each change as a JSON object with previous and current")
+	command := fs.String("exec", "", "run this shell command on every change, with GOVM_PREVIOUS and GOVM_CURRENT set")
+	if _, err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("watch: --interval must be positive, got %s", *interval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	current, changes, err := a.watcher.WatchCurrent(ctx, *interval)
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+	if err := a.printWatchChange(storage.CurrentChange{Current: current}, *asJSON); err != nil {
+		return err
+	}
+	for change := range changes {
+		if err := a.printWatchChange(change, *asJSON); err != nil {
+			return err
+		}
+		if *command != "" {
+			a.runWatchCommand(*command, change)
+		}
+	}
+	return nil
+}
+
+// printWatchChange 输出一次变化：默认只输出新的版本号（没有激活版本时为空行），便于状态栏直接读取。
+func (a *App) printWatchChange(change storage.CurrentChange, asJSON bool) error {
+	if asJSON {
+		data, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(a.out, string(data))
+		return err
+	}
+	_, err := fmt.Fprintln(a.out, change.Current)
+	return err
+}
+
+// runWatchCommand 通过 sh -c 执行 --exec 命令；命令失败只输出警告，不会中断监听。
+func (a *App) runWatchCommand(command string, change storage.CurrentChange) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), "GOVM_PREVIOUS="+change.Previous, "GOVM_CURRENT="+change.Current)
+	cmd.Stdout, cmd.Stderr = a.out, os.Stderr
+	if err := cmd.Run(); err != nil {
+		a.printf("%s --exec: %v\n", colorize("warning:", colorYellow), err)
+	}
+}
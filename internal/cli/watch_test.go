@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// fakeWatcher 依次回放预设的变化后关闭通道。
+type fakeWatcher struct {
+	current string
+	changes []storage.CurrentChange
+}
+
+func (f *fakeWatcher) WatchCurrent(ctx context.Context, interval time.Duration) (string, <-chan storage.CurrentChange, error) {
+	ch := make(chan storage.CurrentChange, len(f.changes))
+	for _, c := range f.changes {
+		ch <- c
+	}
+	close(ch)
+	return f.current, ch, nil
+}
+
+func TestAppWatchPrintsChanges(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	watcher := &fakeWatcher{current: "1.21.0", changes: []storage.CurrentChange{{Previous: "1.21.0", Current: "1.22.0"}, {Previous: "1.22.0"}}}
+	app := NewApp(buf, &fakeLister{}, &fakeInstaller{}, &fakeSwitcher{}, &fakeUninstaller{}, "test", WithWatcher(watcher))
+
+	if err := app.Run([]string{"watch"}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if got, want := buf.String(), "1.21.0\n1.22.0\n\n"; got != want {
+		t.Fatalf("watch output = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := app.Run([]string{"watch", "--json", "--exec", "echo changed:$GOVM_PREVIOUS:$GOVM_CURRENT"}); err != nil {
+		t.Fatalf("watch --json: %v", err)
+	}
+	for _, want := range []string{`{"previous":"","current":"1.21.0"}`, `{"previous":"1.21.0","current":"1.22.0"}`, "changed:1.21.0:1.22.0", "changed:1.22.0:"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("watch --json output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := app.Run([]string{"watch", "--interval", "0s"}); err == nil {
+		t.Fatal("expected a non-positive interval to be rejected")
+	}
+}
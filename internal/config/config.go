@@ -0,0 +1,308 @@
+// Package config 负责加载 govm 的配置文件并叠加环境变量覆盖。
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/region"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+const (
+	// EnvConfigPath 指定配置文件路径。
+	EnvConfigPath = "GOVM_CONFIG"
+	// EnvPolicyPath 指定团队策略文件路径，优先级高于配置文件。
+	EnvPolicyPath = "GOVM_POLICY"
+	// EnvSystemMode 为 1/true 时启用多用户共享安装模式。
+	EnvSystemMode = "GOVM_SYSTEM"
+	// EnvRegion 覆盖配置文件中的 region，设置后不再按公网 IP 探测（auto 除外）。
+	EnvRegion = "GOVM_REGION"
+
+	// ChecksumAuto 在版本列表并非来自官方 API 时用 go.dev 交叉校验，取不到官方值时警告后继续。
+	ChecksumAuto = "auto"
+	// ChecksumOfficial 始终要求与 go.dev 官方校验值一致，取不到时拒绝下载。
+	ChecksumOfficial = "go.dev"
+	// ChecksumMirror 只使用镜像或版本列表提供的校验值。
+	ChecksumMirror = "mirror"
+
+	// DefaultSystemRoot 为共享安装模式的默认根目录，打包模式下改用 PackagedSystemRoot。
+	DefaultSystemRoot = "/usr/local/govm"
+)
+
+// File 表示 config.json 的结构。
+type File struct {
+	RootDir     string `json:"rootDir,omitempty"`
+	VersionsDir string `json:"versionsDir,omitempty"`
+	GoPath      string `json:"goPath,omitempty"`
+	PolicyFile  string `json:"policyFile,omitempty"`
+	System      bool   `json:"system,omitempty"`
+	SystemRoot  string `json:"systemRoot,omitempty"`
+	UsageStats  bool   `json:"usageStats,omitempty"`
+	Catalog     string `json:"catalog,omitempty"`
+	CatalogURL  string `json:"catalogURL,omitempty"`
+	Region      string `json:"region,omitempty"`
+
+	// goToolchain 非空时作为 GOTOOLCHAIN 写入 govm 管理的环境，通常设为 local，避免 go 命令绕过 govm 自行下载工具链。
+	GoToolchain string `json:"goToolchain,omitempty"`
+	// goShim 为 true 时 shim 目录中的 go/gofmt 链接到 govm，每次执行时按 GOVM_VERSION、.govm-version、默认版本选择版本。
+	GoShim bool `json:"goShim,omitempty"`
+	// shellRC 指定接收配置块的 rc 文件（例如 ~/.profile），为空时按 shell 选择。
+	ShellRC string `json:"shellRC,omitempty"`
+	// shellMode 为 managed 时配置块写入 govm 目录下的 env.sh，rc 文件中只保留 source 它的一行。
+	ShellMode string `json:"shellMode,omitempty"`
+
+	// mirrorProbe 为 true 时对 mirrors（为空时使用内置列表）测速并选用最快的下载地址。
+	MirrorProbe    bool     `json:"mirrorProbe,omitempty"`
+	MirrorProbeTTL string   `json:"mirrorProbeTTL,omitempty"`
+	Mirrors        []string `json:"mirrors,omitempty"`
+	// checksumSource 决定以哪一方的 SHA256 为准：auto、go.dev 或 mirror。
+	ChecksumSource string `json:"checksumSource,omitempty"`
+
+	// 超时使用 Go duration 字符串，例如 "10s"、"30m"。
+	ConnectTimeout  string `json:"connectTimeout,omitempty"`
+	Timeout         string `json:"timeout,omitempty"`
+	DownloadTimeout string `json:"downloadTimeout,omitempty"`
+
+	CABundle           string `json:"caBundle,omitempty"`
+	ClientCert         string `json:"clientCert,omitempty"`
+	ClientKey          string `json:"clientKey,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+
+	// 权限使用八进制字符串，例如 "0775"、"0664"。
+	DirMode  string `json:"dirMode,omitempty"`
+	FileMode string `json:"fileMode,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+	// readOnlyGoRoot 为 true 时安装后去掉 GOROOT 目录树的写权限，卸载与重新安装时由 govm 临时恢复。
+	ReadOnlyGoRoot bool `json:"readOnlyGoRoot,omitempty"`
+	// signMetadata 为 true 时用根目录下的 metadata.key 为 metadata.json 写入 HMAC 签名，检测 govm 之外的修改。
+	SignMetadata bool `json:"signMetadata,omitempty"`
+}
+
+// Path 返回配置文件路径：优先 GOVM_CONFIG，其次 GOVM_HOME 或当前目录布局下的 config.json。
+func Path() string {
+	if p := strings.TrimSpace(os.Getenv(EnvConfigPath)); p != "" {
+		return p
+	}
+	return DetectLayout().ConfigPath
+}
+
+// LoadFile 读取原始配置文件，不叠加环境变量；文件不存在时返回空配置。
+func LoadFile(path string) (File, error) {
+	var file File
+	if err := decodeFile(path, &file); err != nil {
+		return File{}, err
+	}
+	return file, nil
+}
+
+// decodeFile 把 path 中出现的字段解码到 file，文件不存在或为空时保持 file 不变。
+func decodeFile(path string, file *File) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if len(strings.TrimSpace(string(data))) > 0 {
+			if err := json.Unmarshal(data, file); err != nil {
+				return fmt.Errorf("config: decode %s: %w", path, err)
+			}
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return fmt.Errorf("config: read %s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveFile 以缩进 JSON 原子写入配置文件，必要时创建父目录。
+func SaveFile(path string, file File) error {
+	if path == "" {
+		return errors.New("config: path is not configured")
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("config: encode: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("config: create dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*")
+	if err != nil {
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load 在厂商与系统配置之上叠加配置文件 path 与环境变量，文件都不存在时返回仅包含环境变量覆盖的配置。
+func Load(path string) (models.Config, error) {
+	file, err := LoadLayered(path)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	cfg := models.Config{
+		RootDir:     expandHome(file.RootDir),
+		VersionsDir: expandHome(file.VersionsDir),
+		GoPath:      file.GoPath,
+		PolicyFile:  expandHome(file.PolicyFile),
+		UsageStats:  file.UsageStats,
+		Catalog:     strings.TrimSpace(file.Catalog),
+		CatalogURL:  expandHome(file.CatalogURL),
+		Region:      strings.TrimSpace(file.Region),
+		MirrorProbe: file.MirrorProbe,
+		GoShim:      file.GoShim,
+		ShellRC:     expandHome(file.ShellRC),
+
+		CABundle:           expandHome(file.CABundle),
+		ClientCert:         expandHome(file.ClientCert),
+		ClientKey:          expandHome(file.ClientKey),
+		InsecureSkipVerify: file.InsecureSkipVerify,
+	}
+	for _, d := range []struct {
+		key string
+		raw string
+		dst *time.Duration
+	}{
+		{"connectTimeout", file.ConnectTimeout, &cfg.ConnectTimeout},
+		{"timeout", file.Timeout, &cfg.RequestTimeout},
+		{"downloadTimeout", file.DownloadTimeout, &cfg.DownloadTimeout},
+		{"mirrorProbeTTL", file.MirrorProbeTTL, &cfg.MirrorProbeTTL},
+	} {
+		if strings.TrimSpace(d.raw) == "" {
+			continue
+		}
+		v, err := time.ParseDuration(strings.TrimSpace(d.raw))
+		if err != nil || v < 0 {
+			return models.Config{}, fmt.Errorf("config: invalid %s %q", d.key, d.raw)
+		}
+		*d.dst = v
+	}
+	for _, m := range []struct {
+		key  string
+		raw  string
+		need fs.FileMode
+		dst  *fs.FileMode
+	}{
+		{"dirMode", file.DirMode, 0o700, &cfg.DirMode},
+		{"fileMode", file.FileMode, 0o600, &cfg.FileMode},
+	} {
+		if strings.TrimSpace(m.raw) == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(m.raw), 8, 32)
+		if err != nil || v > 0o777 {
+			return models.Config{}, fmt.Errorf("config: invalid %s %q", m.key, m.raw)
+		}
+		if fs.FileMode(v)&m.need != m.need {
+			return models.Config{}, fmt.Errorf("config: %s %q must grant the owner at least %#o", m.key, m.raw, m.need)
+		}
+		*m.dst = fs.FileMode(v)
+	}
+	cfg.Owner = strings.TrimSpace(file.Owner)
+	cfg.ReadOnlyGoRoot = file.ReadOnlyGoRoot
+	cfg.SignMetadata = file.SignMetadata
+	if cfg.GoToolchain = strings.TrimSpace(file.GoToolchain); !validToolchain(cfg.GoToolchain) {
+		return models.Config{}, fmt.Errorf("config: invalid goToolchain %q (use local, auto, path or a name such as go1.22.0, optionally followed by +auto or +path)", file.GoToolchain)
+	}
+	switch cfg.ShellMode = strings.ToLower(strings.TrimSpace(file.ShellMode)); cfg.ShellMode {
+	case "", models.ShellInline, models.ShellManaged:
+	default:
+		return models.Config{}, fmt.Errorf("config: invalid shellMode %q (use inline or managed)", file.ShellMode)
+	}
+	switch cfg.ChecksumSource = strings.ToLower(strings.TrimSpace(file.ChecksumSource)); cfg.ChecksumSource {
+	case "", ChecksumAuto, ChecksumOfficial, ChecksumMirror:
+	default:
+		return models.Config{}, fmt.Errorf("config: invalid checksumSource %q (use auto, go.dev or mirror)", file.ChecksumSource)
+	}
+	for _, m := range file.Mirrors {
+		if m = strings.TrimSpace(m); m != "" {
+			cfg.Mirrors = append(cfg.Mirrors, m)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvRegion)); v != "" {
+		cfg.Region = v
+	}
+	if !region.ValidPreference(cfg.Region) {
+		return models.Config{}, fmt.Errorf("config: invalid region %q (use auto, global, cn or a two-letter country code)", cfg.Region)
+	}
+	if p := strings.TrimSpace(os.Getenv(EnvPolicyPath)); p != "" {
+		cfg.PolicyFile = expandHome(p)
+	}
+
+	if root := storage.EnvRootDir(); root != "" {
+		cfg.RootDir = root
+	}
+
+	cfg.SystemMode = file.System
+	if v, ok := os.LookupEnv(EnvSystemMode); ok {
+		cfg.SystemMode = parseBool(v)
+	}
+	if cfg.SystemMode {
+		if cfg.RootDir == "" {
+			cfg.RootDir = expandHome(file.SystemRoot)
+		}
+		if cfg.RootDir == "" {
+			cfg.RootDir = defaultSystemRoot()
+		}
+	}
+	applyLayout(&cfg, DetectLayout())
+	return cfg, nil
+}
+
+// validToolchain 按 go 命令的规则检查 GOTOOLCHAIN 取值：local、auto、path，或 local/goX 加上可选的 +auto、+path 后缀。
+func validToolchain(v string) bool {
+	switch v {
+	case "", "local", "auto", "path":
+		return true
+	}
+	name, mode, hasMode := strings.Cut(v, "+")
+	if hasMode && mode != "auto" && mode != "path" {
+		return false
+	}
+	return name == "local" || (strings.HasPrefix(name, "go1") && !strings.ContainsAny(name, " /\\"))
+}
+
+func parseBool(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+func expandHome(path string) string {
+	path = strings.TrimSpace(path)
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
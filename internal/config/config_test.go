@@ -0,0 +1,220 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestLoadReadsFileAndEnvOverrides(t *testing.T) {
+	temp := t.TempDir()
+	path := filepath.Join(temp, "config.json")
+	content := `{"rootDir":"/data/govm","goPath":"/data/go","policyFile":"/etc/govm/policy.json"}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	t.Setenv(EnvPolicyPath, "/tmp/team-policy.json")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.RootDir != "/data/govm" || cfg.GoPath != "/data/go" {
+		t.Fatalf("unexpected config: %#v", cfg)
+	}
+	if cfg.PolicyFile != "/tmp/team-policy.json" {
+		t.Fatalf("env override not applied: %s", cfg.PolicyFile)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	isolateHome(t)
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load missing file failed: %v", err)
+	}
+	if cfg.RootDir != "" || cfg.PolicyFile != "" {
+		t.Fatalf("expected empty config, got %#v", cfg)
+	}
+}
+
+func TestLoadInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
+func TestLoadSystemModeDefaultsRoot(t *testing.T) {
+	isolateHome(t)
+	t.Setenv(EnvSystemMode, "1")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.SystemMode || cfg.RootDir != DefaultSystemRoot {
+		t.Fatalf("unexpected system config: %#v", cfg)
+	}
+}
+
+func TestLoadTimeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"connectTimeout":"5s","timeout":"1m","downloadTimeout":"2h"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.ConnectTimeout != 5*time.Second || cfg.RequestTimeout != time.Minute || cfg.DownloadTimeout != 2*time.Hour {
+		t.Fatalf("unexpected timeouts: %#v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"timeout":"soon"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for invalid duration")
+	}
+}
+
+func TestLoadPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"dirMode":"0775","fileMode":"664","owner":" builder:dev "}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.DirMode != 0o775 || cfg.FileMode != 0o664 || cfg.Owner != "builder:dev" {
+		t.Fatalf("unexpected permissions: %#v", cfg)
+	}
+
+	for _, content := range []string{`{"dirMode":"rwx"}`, `{"fileMode":"01777"}`, `{"dirMode":"0655"}`} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Fatalf("expected error for %s", content)
+		}
+	}
+}
+
+func TestLoadHonorsGovmHome(t *testing.T) {
+	home := isolateHome(t)
+	root := filepath.Join(home, "bigdisk", "govm")
+	t.Setenv("GOVM_ROOT", "~/bigdisk/govm")
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	if Path() != filepath.Join(root, "config.json") {
+		t.Fatalf("unexpected config path %s", Path())
+	}
+	cfg, err := Load(Path())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.RootDir != root || cfg.CacheDir != "" {
+		t.Fatalf("GOVM_ROOT not applied: %#v", cfg)
+	}
+
+	t.Setenv("GOVM_HOME", "/srv/govm")
+	if cfg, _ := Load(""); cfg.RootDir != "/srv/govm" {
+		t.Fatalf("GOVM_HOME must win over GOVM_ROOT: %#v", cfg)
+	}
+}
+
+func TestLoadRegionOverride(t *testing.T) {
+	isolateHome(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"region":"cn"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvRegion, "global")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Region != "global" {
+		t.Fatalf("GOVM_REGION not applied: %q", cfg.Region)
+	}
+
+	t.Setenv(EnvRegion, "mars")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "invalid region") {
+		t.Fatalf("expected invalid region error, got %v", err)
+	}
+}
+
+func TestLoadChecksumSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"checksumSource":" Go.Dev "}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.ChecksumSource != ChecksumOfficial {
+		t.Fatalf("checksumSource = %q, want %q", cfg.ChecksumSource, ChecksumOfficial)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"checksumSource":"sometimes"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "invalid checksumSource") {
+		t.Fatalf("expected invalid checksumSource error, got %v", err)
+	}
+}
+
+func TestLoadGoToolchain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for raw, ok := range map[string]bool{
+		"local":          true,
+		"go1.22.0+auto":  true,
+		"local+path":     true,
+		"go1.22.0+never": false,
+		"1.22.0":         false,
+	} {
+		if err := os.WriteFile(path, []byte(`{"goToolchain":"`+raw+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if ok && (err != nil || cfg.GoToolchain != raw) {
+			t.Fatalf("goToolchain %q: cfg=%q err=%v", raw, cfg.GoToolchain, err)
+		}
+		if !ok && (err == nil || !strings.Contains(err.Error(), "invalid goToolchain")) {
+			t.Fatalf("goToolchain %q: expected invalid goToolchain error, got %v", raw, err)
+		}
+	}
+}
+
+func TestLoadShellTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"shellRC":"~/.profile","shellMode":"Managed"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.ShellRC != filepath.Join(home, ".profile") || cfg.ShellMode != models.ShellManaged {
+		t.Fatalf("shellRC = %q, shellMode = %q", cfg.ShellRC, cfg.ShellMode)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"shellMode":"symlink"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "invalid shellMode") {
+		t.Fatalf("expected invalid shellMode error, got %v", err)
+	}
+}
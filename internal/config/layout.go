@@ -0,0 +1,185 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+const (
+	// LayoutLegacy 表示所有文件位于 ~/.govm 的传统布局。
+	LayoutLegacy = "legacy"
+	// LayoutXDG 表示按 XDG Base Directory 规范拆分数据、缓存与配置目录的布局。
+	LayoutXDG = "xdg"
+)
+
+// Layout 描述 govm 的目录布局；传统布局下三个目录相同。
+type Layout struct {
+	Kind       string
+	DataDir    string // 版本、元数据与当前版本标记
+	CacheDir   string // 下载归档与版本列表缓存
+	ConfigPath string // config.json 路径
+}
+
+// LegacyLayout 返回 home 下的 ~/.govm 布局。
+func LegacyLayout(home string) Layout {
+	return rootLayout(filepath.Join(home, ".govm"))
+}
+
+// rootLayout 返回所有文件位于 root 下的传统布局。
+func rootLayout(root string) Layout {
+	return Layout{
+		Kind:       LayoutLegacy,
+		DataDir:    root,
+		CacheDir:   filepath.Join(root, "cache"),
+		ConfigPath: filepath.Join(root, "config.json"),
+	}
+}
+
+// XDGLayout 根据 XDG_DATA_HOME/XDG_CACHE_HOME/XDG_CONFIG_HOME 返回 XDG 布局，未设置或非绝对路径时使用规范默认值。
+func XDGLayout(home string) Layout {
+	return Layout{
+		Kind:       LayoutXDG,
+		DataDir:    filepath.Join(xdgDir("XDG_DATA_HOME", home, ".local/share"), "govm"),
+		CacheDir:   filepath.Join(xdgDir("XDG_CACHE_HOME", home, ".cache"), "govm"),
+		ConfigPath: filepath.Join(xdgDir("XDG_CONFIG_HOME", home, ".config"), "govm", "config.json"),
+	}
+}
+
+// DetectLayout 选择当前使用的布局：设置 GOVM_HOME 时全部位于该目录；已有 ~/.govm 时保持传统布局；
+// 否则在设置了任一 XDG 目录变量、XDG 数据或配置已存在或处于打包模式时使用 XDG 布局，其余情况仍使用 ~/.govm。
+func DetectLayout() Layout {
+	if root := storage.EnvRootDir(); root != "" {
+		return rootLayout(root)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return Layout{Kind: LayoutLegacy}
+	}
+	legacy := LegacyLayout(home)
+	if exists(legacy.DataDir) {
+		return legacy
+	}
+	xdg := XDGLayout(home)
+	for _, key := range []string{"XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_CONFIG_HOME"} {
+		if filepath.IsAbs(os.Getenv(key)) {
+			return xdg
+		}
+	}
+	if exists(xdg.DataDir) || exists(xdg.ConfigPath) || Packaged() {
+		return xdg
+	}
+	return legacy
+}
+
+// applyLayout 在 XDG 布局下为未显式配置的目录填入 XDG 路径。
+func applyLayout(cfg *models.Config, layout Layout) {
+	if layout.Kind != LayoutXDG {
+		return
+	}
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = layout.CacheDir
+	}
+	if cfg.SystemMode {
+		if cfg.UserDir == "" {
+			cfg.UserDir = layout.DataDir
+		}
+		return
+	}
+	if cfg.RootDir == "" {
+		cfg.RootDir = layout.DataDir
+	}
+}
+
+func xdgDir(key, home, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); filepath.IsAbs(v) {
+		return v
+	}
+	return filepath.Join(home, fallback)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// LayoutMigrator 将 ~/.govm 中的文件迁移到 XDG 布局。
+type LayoutMigrator struct {
+	From Layout
+	To   Layout
+}
+
+// NewLayoutMigrator 创建从传统布局迁移到当前 XDG 变量对应布局的迁移器。
+func NewLayoutMigrator() *LayoutMigrator {
+	home, _ := os.UserHomeDir()
+	return &LayoutMigrator{From: LegacyLayout(home), To: XDGLayout(home)}
+}
+
+// Migrate 移动传统布局中的每个条目并改写元数据中的安装路径，返回 "源 -> 目标" 列表；dryRun 时只返回计划。
+func (m *LayoutMigrator) Migrate(dryRun bool) ([]string, error) {
+	entries, err := os.ReadDir(m.From.DataDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("config: nothing to migrate, %s does not exist", m.From.DataDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", m.From.DataDir, err)
+	}
+
+	type move struct{ src, dst string }
+	var moves []move
+	for _, entry := range entries {
+		src := filepath.Join(m.From.DataDir, entry.Name())
+		switch entry.Name() {
+		case "config.json":
+			moves = append(moves, move{src, m.To.ConfigPath})
+		case "downloads":
+			moves = append(moves, move{src, filepath.Join(m.To.CacheDir, "downloads")})
+		case "cache":
+			cached, err := os.ReadDir(src)
+			if err != nil {
+				return nil, fmt.Errorf("config: read %s: %w", src, err)
+			}
+			for _, c := range cached {
+				moves = append(moves, move{filepath.Join(src, c.Name()), filepath.Join(m.To.CacheDir, c.Name())})
+			}
+		case "govm.lock":
+			// 锁文件无需迁移，保留给旧进程使用后随目录一并清理。
+		default:
+			moves = append(moves, move{src, filepath.Join(m.To.DataDir, entry.Name())})
+		}
+	}
+
+	var plan []string
+	for _, mv := range moves {
+		if _, err := os.Lstat(mv.dst); err == nil {
+			return nil, fmt.Errorf("config: migrate: %s already exists", mv.dst)
+		}
+		plan = append(plan, mv.src+" -> "+mv.dst)
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	for i, mv := range moves {
+		if err := os.MkdirAll(filepath.Dir(mv.dst), 0o755); err != nil {
+			return plan[:i], fmt.Errorf("config: migrate: %w", err)
+		}
+		if err := os.Rename(mv.src, mv.dst); err != nil {
+			return plan[:i], fmt.Errorf("config: migrate %s: %w", mv.src, err)
+		}
+	}
+
+	store := storage.NewFileStorage(models.Config{RootDir: m.To.DataDir})
+	if err := store.RelocateInstalls(filepath.Join(m.From.DataDir, "versions"), filepath.Join(m.To.DataDir, "versions")); err != nil {
+		return plan, err
+	}
+	os.Remove(filepath.Join(m.From.DataDir, "cache"))
+	os.Remove(filepath.Join(m.From.DataDir, "govm.lock"))
+	os.Remove(m.From.DataDir)
+	return plan, nil
+}
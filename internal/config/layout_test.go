@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func isolateHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, key := range []string{"XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_CONFIG_HOME", EnvConfigPath, EnvPolicyPath, EnvSystemMode, EnvRegion, storage.EnvHome, storage.EnvRoot} {
+		t.Setenv(key, "")
+	}
+	t.Setenv(EnvSysConfDir, filepath.Join(home, "etc"))
+	return home
+}
+
+func TestDetectLayout(t *testing.T) {
+	home := isolateHome(t)
+	if got := DetectLayout(); got.Kind != LayoutLegacy || got.DataDir != filepath.Join(home, ".govm") {
+		t.Fatalf("expected legacy default, got %#v", got)
+	}
+
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	got := DetectLayout()
+	if got.Kind != LayoutXDG || got.DataDir != filepath.Join(home, "data", "govm") || got.CacheDir != filepath.Join(home, ".cache", "govm") {
+		t.Fatalf("unexpected xdg layout: %#v", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".govm"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := DetectLayout(); got.Kind != LayoutLegacy {
+		t.Fatalf("existing ~/.govm must keep the legacy layout, got %#v", got)
+	}
+}
+
+func TestLoadAppliesXDGLayout(t *testing.T) {
+	home := isolateHome(t)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+
+	cfg, err := Load(Path())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.RootDir != filepath.Join(home, ".local", "share", "govm") || cfg.CacheDir != filepath.Join(home, "cache", "govm") {
+		t.Fatalf("unexpected xdg config: %#v", cfg)
+	}
+	if Path() != filepath.Join(home, ".config", "govm", "config.json") {
+		t.Fatalf("unexpected config path %s", Path())
+	}
+}
+
+func TestLayoutMigratorMovesLegacyTree(t *testing.T) {
+	home := isolateHome(t)
+	from := LegacyLayout(home)
+	to := XDGLayout(home)
+
+	legacyStore := storage.NewFileStorage(models.Config{RootDir: from.DataDir})
+	installPath := legacyStore.GetInstallPath("1.22.0")
+	if err := os.MkdirAll(filepath.Join(installPath, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := legacyStore.SaveMetadata(models.Version{Number: "1.22.0", InstallPath: installPath}); err != nil {
+		t.Fatal(err)
+	}
+	for path, data := range map[string]string{
+		from.ConfigPath: `{}`,
+		filepath.Join(from.CacheDir, "releases.json"):                           `{}`,
+		filepath.Join(from.DataDir, "downloads", "go1.22.0.linux-amd64.tar.gz"): "x",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &LayoutMigrator{From: from, To: to}
+	plan, err := m.Migrate(true)
+	if err != nil || len(plan) != 5 {
+		t.Fatalf("unexpected plan %v: %v", plan, err)
+	}
+	if _, err := os.Stat(to.DataDir); !os.IsNotExist(err) {
+		t.Fatal("dry run must not move anything")
+	}
+
+	if _, err := m.Migrate(false); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if _, err := os.Stat(from.DataDir); !os.IsNotExist(err) {
+		t.Fatalf("legacy directory should be removed: %v", err)
+	}
+	for _, path := range []string{
+		to.ConfigPath,
+		filepath.Join(to.CacheDir, "releases.json"),
+		filepath.Join(to.CacheDir, "downloads", "go1.22.0.linux-amd64.tar.gz"),
+		filepath.Join(to.DataDir, "versions", "go1.22.0", "bin"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s after migration: %v", path, err)
+		}
+	}
+	versions, err := storage.NewFileStorage(models.Config{RootDir: to.DataDir}).LoadMetadata()
+	if err != nil || len(versions) != 1 || versions[0].InstallPath != filepath.Join(to.DataDir, "versions", "go1.22.0") {
+		t.Fatalf("install path not relocated: %#v, %v", versions, err)
+	}
+}
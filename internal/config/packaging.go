@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 以下变量供发行版在构建时通过 -ldflags "-X github.com/liangyou/govm/internal/config.<名称>=<值>" 设置，无需修改源码。
+var (
+	// Packaging 为 1/true 时启用打包模式：程序文件只读，可变状态一律写入用户或 /var/lib 下的目录。
+	Packaging = ""
+	// SysConfDir 为系统配置目录，系统级配置位于 <SysConfDir>/govm/config.json。
+	SysConfDir = "/etc"
+	// VendorConfig 为随包提供的只读默认配置；为空时打包模式使用 DefaultVendorConfig，非打包模式不读取。
+	VendorConfig = ""
+	// PackagedSystemRoot 为打包模式下共享安装的默认根目录。
+	PackagedSystemRoot = "/var/lib/govm"
+)
+
+const (
+	// EnvSysConfDir 在运行时覆盖 SysConfDir。
+	EnvSysConfDir = "GOVM_SYSCONFDIR"
+	// DefaultVendorConfig 为打包模式下默认的厂商配置路径。
+	DefaultVendorConfig = "/usr/share/govm/config.json"
+)
+
+// Packaged 报告当前二进制是否以打包模式构建。
+func Packaged() bool {
+	return parseBool(Packaging)
+}
+
+// SystemConfigPath 返回系统级配置文件路径：优先 GOVM_SYSCONFDIR，其次构建时设置的 SysConfDir。
+func SystemConfigPath() string {
+	dir := strings.TrimSpace(os.Getenv(EnvSysConfDir))
+	if dir == "" {
+		dir = strings.TrimSpace(SysConfDir)
+	}
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "govm", "config.json")
+}
+
+// VendorConfigPath 返回厂商默认配置路径，未启用打包模式且未设置 VendorConfig 时返回空字符串。
+func VendorConfigPath() string {
+	if p := strings.TrimSpace(VendorConfig); p != "" {
+		return p
+	}
+	if Packaged() {
+		return DefaultVendorConfig
+	}
+	return ""
+}
+
+// Files 返回按优先级从低到高叠加的配置文件：厂商默认配置、系统配置与用户配置 path，重复路径只保留一次。
+func Files(path string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, p := range []string{VendorConfigPath(), SystemConfigPath(), path} {
+		if p == "" || seen[filepath.Clean(p)] {
+			continue
+		}
+		seen[filepath.Clean(p)] = true
+		files = append(files, p)
+	}
+	return files
+}
+
+// LoadLayered 依次读取 Files(path)，后面的文件覆盖前面文件中出现的字段；不存在的文件被跳过。
+// 只有用户配置 path 由 govm 写入，厂商与系统配置始终只读。
+func LoadLayered(path string) (File, error) {
+	var file File
+	for _, p := range Files(path) {
+		if err := decodeFile(p, &file); err != nil {
+			return File{}, err
+		}
+	}
+	return file, nil
+}
+
+// defaultSystemRoot 返回共享安装模式的默认根目录，打包模式下位于 /var/lib 以免写入只读的程序目录。
+func defaultSystemRoot() string {
+	if Packaged() {
+		return PackagedSystemRoot
+	}
+	return DefaultSystemRoot
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// setPackaging 在测试期间模拟以 -ldflags -X 设置的打包变量。
+func setPackaging(t *testing.T, packaging, vendorConfig string) {
+	t.Helper()
+	oldPackaging, oldVendor := Packaging, VendorConfig
+	Packaging, VendorConfig = packaging, vendorConfig
+	t.Cleanup(func() { Packaging, VendorConfig = oldPackaging, oldVendor })
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLayersVendorSystemAndUserConfig(t *testing.T) {
+	home := isolateHome(t)
+	vendor := filepath.Join(home, "usr", "share", "govm", "config.json")
+	setPackaging(t, "", vendor)
+	user := filepath.Join(home, "user.json")
+	writeConfig(t, vendor, `{"region":"cn","catalog":"listing","mirrors":["https://vendor.example/"]}`)
+	writeConfig(t, SystemConfigPath(), `{"region":"global","goPath":"/srv/go"}`)
+	writeConfig(t, user, `{"goPath":"~/go","mirrors":["https://user.example/"]}`)
+
+	if got, want := Files(user), []string{vendor, filepath.Join(home, "etc", "govm", "config.json"), user}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Files = %v, want %v", got, want)
+	}
+	cfg, err := Load(user)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Catalog != "listing" || cfg.Region != "global" || cfg.GoPath != "~/go" {
+		t.Fatalf("layers not merged in order: %#v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Mirrors, []string{"https://user.example/"}) {
+		t.Fatalf("user mirrors must replace vendor mirrors, got %v", cfg.Mirrors)
+	}
+
+	// 直接读取用户配置（govm init 的编辑对象）时不应混入其他层。
+	file, err := LoadFile(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Region != "" || file.Catalog != "" {
+		t.Fatalf("LoadFile must only read the user config, got %#v", file)
+	}
+}
+
+func TestLoadReportsBrokenSystemConfig(t *testing.T) {
+	isolateHome(t)
+	writeConfig(t, SystemConfigPath(), "{")
+	if _, err := Load(""); err == nil {
+		t.Fatal("expected decode error for the system config")
+	}
+}
+
+func TestPackagingModeDefaults(t *testing.T) {
+	home := isolateHome(t)
+	setPackaging(t, "1", "")
+
+	if VendorConfigPath() != DefaultVendorConfig {
+		t.Fatalf("VendorConfigPath = %q, want %q", VendorConfigPath(), DefaultVendorConfig)
+	}
+	if got := DetectLayout(); got.Kind != LayoutXDG || got.DataDir != filepath.Join(home, ".local", "share", "govm") {
+		t.Fatalf("packaging mode must default to the xdg layout, got %#v", got)
+	}
+
+	t.Setenv(EnvSystemMode, "1")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.RootDir != PackagedSystemRoot {
+		t.Fatalf("system root = %q, want %q", cfg.RootDir, PackagedSystemRoot)
+	}
+}
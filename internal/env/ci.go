@@ -0,0 +1,52 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportCI 在检测到 GitHub Actions（GITHUB_PATH/GITHUB_ENV）时写入 GOROOT/bin 与 GOROOT（goToolchain 非空时还有 GOTOOLCHAIN），
+// 让后续步骤直接使用该版本而无需修改 rc 文件，返回是否写入了任意文件。
+func ExportCI(goRoot, goToolchain string, getenv func(string) string) (bool, error) {
+	if strings.TrimSpace(goRoot) == "" {
+		return false, errors.New("env: goRoot is required")
+	}
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	wrote := false
+	if path := strings.TrimSpace(getenv("GITHUB_PATH")); path != "" {
+		if err := appendLine(path, filepath.Join(goRoot, "bin")); err != nil {
+			return wrote, fmt.Errorf("env: write GITHUB_PATH: %w", err)
+		}
+		wrote = true
+	}
+	if path := strings.TrimSpace(getenv("GITHUB_ENV")); path != "" {
+		if err := appendLine(path, "GOROOT="+goRoot); err != nil {
+			return wrote, fmt.Errorf("env: write GITHUB_ENV: %w", err)
+		}
+		if goToolchain != "" {
+			if err := appendLine(path, "GOTOOLCHAIN="+goToolchain); err != nil {
+				return wrote, fmt.Errorf("env: write GITHUB_ENV: %w", err)
+			}
+		}
+		wrote = true
+	}
+	return wrote, nil
+}
+
+func appendLine(path, line string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportCIWritesGitHubFiles(t *testing.T) {
+	t.Parallel()
+
+	temp := t.TempDir()
+	pathFile := filepath.Join(temp, "path")
+	envFile := filepath.Join(temp, "env")
+	getenv := func(key string) string {
+		switch key {
+		case "GITHUB_PATH":
+			return pathFile
+		case "GITHUB_ENV":
+			return envFile
+		}
+		return ""
+	}
+
+	wrote, err := ExportCI("/opt/go1.22.0", "local", getenv)
+	if err != nil || !wrote {
+		t.Fatalf("ExportCI failed: wrote=%v err=%v", wrote, err)
+	}
+
+	data, _ := os.ReadFile(pathFile)
+	if string(data) != "/opt/go1.22.0/bin\n" {
+		t.Fatalf("unexpected GITHUB_PATH content: %q", data)
+	}
+	data, _ = os.ReadFile(envFile)
+	if string(data) != "GOROOT=/opt/go1.22.0\nGOTOOLCHAIN=local\n" {
+		t.Fatalf("unexpected GITHUB_ENV content: %q", data)
+	}
+}
+
+func TestExportCINoopOutsideActions(t *testing.T) {
+	t.Parallel()
+
+	wrote, err := ExportCI("/opt/go", "", func(string) string { return "" })
+	if err != nil || wrote {
+		t.Fatalf("expected noop, got wrote=%v err=%v", wrote, err)
+	}
+}
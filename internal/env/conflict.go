@@ -0,0 +1,102 @@
+package env
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// Conflict 描述 govm 配置块之外、指向其他位置的 GOROOT/GOPATH 设置，它们会与配置块互相覆盖。
+type Conflict struct {
+	Path  string // 所在 rc 文件，来自当前环境时为空
+	Var   string // GOROOT 或 GOPATH
+	Value string
+}
+
+// ConflictFunc 在写入 rc 文件前收到检测到的冲突，返回 true 时注释掉 rc 文件中的冲突行后继续，返回 false 时放弃写入。
+// 只有来自当前环境的冲突无法修改，此时返回值被忽略。
+type ConflictFunc func(conflicts []Conflict) bool
+
+// ErrShellConfigConflict 表示 rc 文件中存在冲突的 GOROOT/GOPATH 设置且 ConflictFunc 选择了放弃。
+var ErrShellConfigConflict = errors.New("env: conflicting GOROOT/GOPATH exports outside the govm block")
+
+// disabledSuffix 标注被 govm 注释掉的冲突行，行首的注释符号之后保留原内容，便于手工恢复。
+const disabledSuffix = "  # disabled by govm, conflicts with the govm block"
+
+// SetConflictHandler 设置 GOROOT/GOPATH 冲突的处理回调；nil 表示不检查。
+func (m *Manager) SetConflictHandler(fn ConflictFunc) {
+	m.conflicts = fn
+}
+
+// resolveConflicts 检查已删除 govm 配置块的 rc 内容与当前环境中的冲突并交给回调处理，返回处理后的 rc 内容。
+func (m *Manager) resolveConflicts(path, content string) (string, error) {
+	if m.conflicts == nil {
+		return content, nil
+	}
+	lines := strings.Split(content, "\n")
+	var found []Conflict
+	var matched []int
+	for i, line := range lines {
+		name, value, ok := assignment(line)
+		if ok && m.conflicting(name, value) {
+			found = append(found, Conflict{Path: path, Var: name, Value: value})
+			matched = append(matched, i)
+		}
+	}
+	inFile := len(found)
+	for _, name := range []string{"GOROOT", "GOPATH"} {
+		value := strings.TrimSpace(m.envFn(name))
+		if value == "" || !m.conflicting(name, value) || containsValue(found, name, value) {
+			continue
+		}
+		found = append(found, Conflict{Var: name, Value: value})
+	}
+	if len(found) == 0 {
+		return content, nil
+	}
+	if !m.conflicts(found) && inFile > 0 {
+		return "", ErrShellConfigConflict
+	}
+	for _, i := range matched {
+		lines[i] = "# " + strings.TrimSpace(lines[i]) + disabledSuffix
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// conflicting 报告 name=value 是否与配置块冲突：GOROOT 不在 govm 目录之下，或 GOPATH 与配置中的 gopath 不同。
+// 配置块以 ${GOPATH:-...} 尊重已有的 GOPATH，只有配置文件显式指定了 gopath 时 GOPATH 才算冲突。
+func (m *Manager) conflicting(name, value string) bool {
+	switch name {
+	case "GOROOT":
+		return m.cfg.RootDir == "" || !ownedGoRoot("export GOROOT="+value, m.cfg.RootDir)
+	case "GOPATH":
+		return m.cfg.GoPath != "" && filepath.Clean(value) != filepath.Clean(m.cfg.GoPath)
+	}
+	return false
+}
+
+// assignment 解析 `export GOROOT=...`、`GOPATH=...` 这类未注释的赋值语句，只识别 GOROOT 与 GOPATH。
+func assignment(line string) (name, value string, ok bool) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(line), "export ")
+	name, value, ok = strings.Cut(strings.TrimSpace(trimmed), "=")
+	if !ok || (name != "GOROOT" && name != "GOPATH") {
+		return "", "", false
+	}
+	value, _, _ = strings.Cut(value, " #")
+	// 引用自身的写法（例如 GOPATH="$GOPATH:/extra"）是追加而不是覆盖。
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	if value == "" || strings.Contains(value, "$"+name) || strings.Contains(value, "${"+name) {
+		return "", "", false
+	}
+	return name, value, true
+}
+
+// containsValue 报告 conflicts 中是否已有同名同值的冲突。
+func containsValue(conflicts []Conflict, name, value string) bool {
+	for _, c := range conflicts {
+		if c.Var == name && c.Value == value {
+			return true
+		}
+	}
+	return false
+}
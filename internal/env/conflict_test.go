@@ -0,0 +1,93 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestUpdateShellConfigConflicts(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/home/dev/.govm", GoPath: "/home/dev/go"})
+	mgr.homeFn = func() (string, error) { return home, nil }
+	mgr.envFn = func(key string) string {
+		switch key {
+		case "GOROOT":
+			return "/usr/lib/go"
+		case "SHELL":
+			return "/bin/zsh"
+		}
+		return ""
+	}
+
+	rc := home + "/.zshrc"
+	original := strings.Join([]string{
+		`export GOROOT="/usr/local/go" # system go`,
+		`GOPATH=/srv/gopath`,
+		`export GOPATH="$GOPATH:/extra"`,
+		`# export GOROOT=/opt/old`,
+		`export GOPATH=/home/dev/go`,
+		"",
+	}, "\n")
+	if err := os.WriteFile(rc, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var seen []Conflict
+	resolve := false
+	mgr.SetConflictHandler(func(conflicts []Conflict) bool {
+		seen = conflicts
+		return resolve
+	})
+	if err := mgr.UpdateShellConfig("zsh", "/home/dev/.govm/versions/go1.22.0"); !errors.Is(err, ErrShellConfigConflict) {
+		t.Fatalf("declined conflicts must abort, got %v", err)
+	}
+	want := []Conflict{
+		{Path: rc, Var: "GOROOT", Value: "/usr/local/go"},
+		{Path: rc, Var: "GOPATH", Value: "/srv/gopath"},
+		{Var: "GOROOT", Value: "/usr/lib/go"},
+	}
+	if len(seen) != len(want) {
+		t.Fatalf("conflicts = %+v, want %+v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("conflict %d = %+v, want %+v", i, seen[i], want[i])
+		}
+	}
+	if data, _ := os.ReadFile(rc); string(data) != original {
+		t.Fatalf("aborted change was written:\n%s", data)
+	}
+
+	resolve = true
+	if err := mgr.UpdateShellConfig("zsh", "/home/dev/.govm/versions/go1.22.0"); err != nil {
+		t.Fatalf("UpdateShellConfig: %v", err)
+	}
+	data, _ := os.ReadFile(rc)
+	content := string(data)
+	for _, line := range []string{
+		`# export GOROOT="/usr/local/go" # system go` + disabledSuffix,
+		`# GOPATH=/srv/gopath` + disabledSuffix,
+		`export GOPATH="$GOPATH:/extra"`,
+		`export GOPATH=/home/dev/go`,
+		blockStart,
+	} {
+		if !strings.Contains(content, line+"\n") {
+			t.Fatalf("missing %q:\n%s", line, content)
+		}
+	}
+
+	// 只剩当前环境中的冲突时仍然写入，回调的返回值被忽略。
+	resolve = false
+	if err := mgr.UpdateShellConfig("zsh", "/home/dev/.govm/versions/go1.22.1"); err != nil {
+		t.Fatalf("environment-only conflicts must not abort: %v", err)
+	}
+	if len(seen) != 1 || seen[0].Path != "" {
+		t.Fatalf("expected only the environment conflict, got %+v", seen)
+	}
+}
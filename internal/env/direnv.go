@@ -0,0 +1,28 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnvrcFile 为 direnv 读取的项目环境文件名。
+const EnvrcFile = ".envrc"
+
+// DirenvBlock 返回写入 .envrc 的配置块：用 direnv 的 PATH_add 把 goRoot/bin 放在 PATH 最前，goToolchain 非空时同时导出 GOTOOLCHAIN。
+func DirenvBlock(goRoot, goToolchain string) string {
+	lines := []string{
+		blockStart,
+		fmt.Sprintf("export GOROOT=\"%s\"", goRoot),
+		"PATH_add \"$GOROOT/bin\"",
+	}
+	if goToolchain != "" {
+		lines = append(lines, fmt.Sprintf("export GOTOOLCHAIN=\"%s\"", goToolchain))
+	}
+	return strings.Join(append(lines, blockEnd), "\n")
+}
+
+// HasBlock 报告 content 中是否已有 govm 配置块。
+func HasBlock(content string) bool {
+	_, blocks, _, _ := cleanConfig(content, "")
+	return blocks > 0
+}
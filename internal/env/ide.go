@@ -0,0 +1,126 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// vscodeSettings 返回 VS Code 的设置文件：dir 非空时为工作区的 .vscode/settings.json，否则为用户设置。
+func (m *Manager) vscodeSettings(dir string) (string, error) {
+	if dir != "" {
+		return filepath.Join(dir, ".vscode", "settings.json"), nil
+	}
+	home, err := m.homeFn()
+	if err != nil {
+		return "", fmt.Errorf("env: home dir: %w", err)
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support", "Code", "User", "settings.json"), nil
+	}
+	config := m.envFn("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(config) {
+		config = filepath.Join(home, ".config")
+	}
+	return filepath.Join(config, "Code", "User", "settings.json"), nil
+}
+
+// SetupVSCode 把 Go 扩展的 go.goroot 与 go.alternateTools.go 指向 goRoot，启用 goShim 时改为指向 go 选择器并去掉
+// govm 写入的 go.goroot；dir 非空时写入该目录的工作区设置。只改动这两个键，其余设置的顺序与格式保持不变，返回写入的文件。
+func (m *Manager) SetupVSCode(goRoot, dir string) (string, error) {
+	if goRoot == "" {
+		return "", errors.New("env: goRoot is required")
+	}
+	path, err := m.vscodeSettings(dir)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) || (err == nil && len(bytes.TrimSpace(data)) == 0):
+		data = []byte("{}\n")
+	case err != nil:
+		return "", fmt.Errorf("env: read %s: %w", path, err)
+	}
+	// settings.json 允许注释与尾随逗号，解析失败时不冒险改写。
+	notPlain := fmt.Errorf("env: %s is not plain JSON (comments or trailing commas?); set \"go.goroot\": %q there manually", path, goRoot)
+	settings, err := parseJSONObject(data, "")
+	if err != nil {
+		return "", notPlain
+	}
+
+	goBin := filepath.Join(goRoot, "bin", "go")
+	if m.shimDir != "" && m.cfg.GoShim {
+		goBin = filepath.Join(m.shimDir, "go")
+		var current string
+		if member, _, ok := settings.member("go.goroot"); ok && json.Unmarshal(member.value, &current) == nil && isManagedPath(current, m.cfg.RootDir) {
+			data = settings.remove("go.goroot")
+		}
+	} else {
+		data = settings.set("go.goroot", jsonString(goRoot))
+	}
+
+	// go.alternateTools 不是对象时整体替换，再在其中设置 go。
+	if settings, err = parseJSONObject(data, ""); err != nil {
+		return "", notPlain
+	}
+	if member, _, ok := settings.member("go.alternateTools"); !ok || !bytes.HasPrefix(member.value, []byte("{")) {
+		data = settings.set("go.alternateTools", []byte("{}"))
+		if settings, err = parseJSONObject(data, ""); err != nil {
+			return "", notPlain
+		}
+	}
+	member, _, _ := settings.member("go.alternateTools")
+	tools, err := parseJSONObject(member.value, lineIndent(data, member.start))
+	if err != nil {
+		return "", notPlain
+	}
+	data = settings.set("go.alternateTools", tools.set("go", jsonString(goBin)))
+
+	if err := writeConfig(path, string(data)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// golandGoRoot 匹配 GoLand 在 workspace.xml 中记录项目 GOROOT 的组件。
+var golandGoRoot = regexp.MustCompile(`<component name="GOROOT"[^>]*/>`)
+
+// SetupGoLand 把 dir 中 GoLand 项目（.idea/workspace.xml）的 GOROOT 指向 goRoot，返回写入的文件。
+func (m *Manager) SetupGoLand(goRoot, dir string) (string, error) {
+	if goRoot == "" {
+		return "", errors.New("env: goRoot is required")
+	}
+	ideaDir := filepath.Join(dir, ".idea")
+	if info, err := os.Stat(ideaDir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("env: %s has no .idea directory; open the project in GoLand once first", dir)
+	}
+	path := filepath.Join(ideaDir, "workspace.xml")
+	component := fmt.Sprintf(`<component name="GOROOT" url="file://%s" />`, filepath.ToSlash(goRoot))
+	content, err := readConfig(path)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case strings.TrimSpace(content) == "":
+		content = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<project version=\"4\">\n  " + component + "\n</project>\n"
+	case golandGoRoot.MatchString(content):
+		content = golandGoRoot.ReplaceAllLiteralString(content, component)
+	default:
+		end := strings.LastIndex(content, "</project>")
+		if end < 0 {
+			return "", fmt.Errorf("env: %s is not a GoLand workspace file", path)
+		}
+		content = content[:end] + "  " + component + "\n" + content[end:]
+	}
+	if err := writeConfig(path, content); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,212 @@
+package env
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestSetupVSCodeMergesSettings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/govm"})
+	path := filepath.Join(dir, ".vscode", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"editor.tabSize": 4, "go.alternateTools": {"gopls": "/opt/gopls"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := mgr.SetupVSCode("/govm/versions/go1.22.0", dir); err != nil || got != path {
+		t.Fatalf("SetupVSCode = %q, %v", got, err)
+	}
+
+	var settings struct {
+		TabSize int               `json:"editor.tabSize"`
+		GoRoot  string            `json:"go.goroot"`
+		Tools   map[string]string `json:"go.alternateTools"`
+	}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("settings are not valid JSON: %v\n%s", err, data)
+	}
+	if settings.TabSize != 4 || settings.GoRoot != "/govm/versions/go1.22.0" || settings.Tools["go"] != "/govm/versions/go1.22.0/bin/go" || settings.Tools["gopls"] != "/opt/gopls" {
+		t.Fatalf("unexpected settings:\n%s", data)
+	}
+
+	if err := os.WriteFile(path, []byte("{\n  // comment\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.SetupVSCode("/govm/versions/go1.22.0", dir); err == nil || !strings.Contains(err.Error(), "not plain JSON") {
+		t.Fatalf("settings with comments must not be rewritten, got %v", err)
+	}
+}
+
+func TestSetupVSCodeKeepsFormatting(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/govm"})
+	path := filepath.Join(dir, ".vscode", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	original := "{\n  \"zeta\": 1,\n  \"go.alternateTools\": {\n    \"gopls\": \"/opt/gopls\"\n  },\n  \"alpha\": [1,2]\n}\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"zeta\": 1,\n  \"go.alternateTools\": {\n    \"gopls\": \"/opt/gopls\",\n    \"go\": \"/govm/versions/go1.22.0/bin/go\"\n  },\n  \"alpha\": [1,2],\n  \"go.goroot\": \"/govm/versions/go1.22.0\"\n}\n"
+	for i := 0; i < 2; i++ {
+		if _, err := mgr.SetupVSCode("/govm/versions/go1.22.0", dir); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != want {
+			t.Fatalf("run %d: settings were reformatted:\n%s\nwant:\n%s", i+1, data, want)
+		}
+	}
+
+	// 新文件按 VS Code 的四空格缩进生成。
+	fresh := t.TempDir()
+	if _, err := mgr.SetupVSCode("/govm/versions/go1.22.0", fresh); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(fresh, ".vscode", "settings.json"))
+	if want := "{\n    \"go.goroot\": \"/govm/versions/go1.22.0\",\n    \"go.alternateTools\": {\n        \"go\": \"/govm/versions/go1.22.0/bin/go\"\n    }\n}\n"; string(data) != want {
+		t.Fatalf("unexpected new settings:\n%s", data)
+	}
+}
+
+func TestSetupVSCodeUsesShimWithGoShim(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/govm", GoShim: true}, WithShimDir("/govm/shims"))
+	path := filepath.Join(dir, ".vscode", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"go.goroot": "/govm/versions/go1.21.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.SetupVSCode("/govm/versions/go1.22.0", dir); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "go.goroot") || !strings.Contains(string(data), `"go": "/govm/shims/go"`) {
+		t.Fatalf("expected the go shim without go.goroot:\n%s", data)
+	}
+
+	// 只删除 govm 目录下的 go.goroot，相邻成员与逗号保持完整。
+	for _, tc := range []struct{ in, want string }{
+		{`{"go.goroot": "/govm/versions/go1.21.0", "x": 1}`, `{"x": 1, "go.alternateTools": {` + "\n" + `    "go": "/govm/shims/go"` + "\n" + `}}`},
+		{`{"x": 1, "go.goroot": "/govm-other/go"}`, `{"x": 1, "go.goroot": "/govm-other/go", "go.alternateTools": {` + "\n" + `    "go": "/govm/shims/go"` + "\n" + `}}`},
+	} {
+		if err := os.WriteFile(path, []byte(tc.in), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mgr.SetupVSCode("/govm/versions/go1.22.0", dir); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != tc.want {
+			t.Fatalf("SetupVSCode(%s) =\n%s\nwant\n%s", tc.in, data, tc.want)
+		}
+	}
+}
+
+func TestSetupVSCodeUserSettings(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("user settings live under ~/Library on macOS")
+	}
+	t.Parallel()
+
+	home := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{})
+	mgr.homeFn = func() (string, error) { return home, nil }
+	mgr.envFn = func(string) string { return "" }
+	path, err := mgr.SetupVSCode("/opt/go", "")
+	if err != nil || path != filepath.Join(home, ".config", "Code", "User", "settings.json") {
+		t.Fatalf("SetupVSCode = %q, %v", path, err)
+	}
+}
+
+func TestSetupGoLand(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{})
+	if _, err := mgr.SetupGoLand("/opt/go1.22.0", dir); err == nil || !strings.Contains(err.Error(), "no .idea directory") {
+		t.Fatalf("a directory without .idea must be rejected, got %v", err)
+	}
+
+	path := filepath.Join(dir, ".idea", "workspace.xml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	workspace := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<project version=\"4\">\n  <component name=\"ChangeListManager\" />\n</project>\n"
+	if err := os.WriteFile(path, []byte(workspace), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, goRoot := range []string{"/opt/go1.21.0", "/opt/go1.22.0"} {
+		if _, err := mgr.SetupGoLand(goRoot, dir); err != nil {
+			t.Fatalf("SetupGoLand %s: %v", goRoot, err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if strings.Count(content, `name="GOROOT"`) != 1 || !strings.Contains(content, `<component name="GOROOT" url="file:///opt/go1.22.0" />`) || !strings.Contains(content, "ChangeListManager") {
+		t.Fatalf("unexpected workspace.xml:\n%s", content)
+	}
+}
+
+func TestSessionEnvironment(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/govm", GoToolchain: "local"}, WithShimDir("/govm/shims"))
+	mgr.homeFn = func() (string, error) { return home, nil }
+	mgr.envFn = func(string) string { return "" }
+	var calls [][]string
+	mgr.runFn = func(name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+		return []byte("HOME=" + home + "\nPATH=/govm/versions/go1.21.0/bin:/govm/shims:/usr/bin:/bin\n"), nil
+	}
+
+	path, err := mgr.WriteSessionEnvironment("/govm/versions/go1.22.0")
+	if err != nil {
+		t.Fatalf("WriteSessionEnvironment: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, line := range []string{
+		"GOROOT=/govm/versions/go1.22.0",
+		"GOTOOLCHAIN=local",
+		"GOPATH=${GOPATH:-" + filepath.Join(home, "go") + "}",
+		"PATH=/govm/versions/go1.22.0/bin:/govm/shims:${PATH}",
+	} {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Fatalf("%s is missing %q:\n%s", path, line, data)
+		}
+	}
+	if path != filepath.Join(home, ".config", "environment.d", SessionEnvFile) {
+		t.Fatalf("unexpected path %s", path)
+	}
+
+	if err := mgr.ImportSessionEnvironment("/govm/versions/go1.22.0"); err != nil {
+		t.Fatalf("ImportSessionEnvironment: %v", err)
+	}
+	want := []string{"systemctl", "--user", "set-environment",
+		"GOROOT=/govm/versions/go1.22.0",
+		"GOTOOLCHAIN=local",
+		"GOPATH=" + filepath.Join(home, "go"),
+		"PATH=/govm/versions/go1.22.0/bin:/govm/shims:/usr/bin:/bin",
+	}
+	if len(calls) != 2 || !slices.Equal(calls[1], want) {
+		t.Fatalf("unexpected systemctl calls:\n%q\nwant %q", calls, want)
+	}
+}
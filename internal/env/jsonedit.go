@@ -0,0 +1,138 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// jsonIndent 为向空对象添加成员时使用的缩进，与 VS Code 写入 settings.json 的格式一致。
+const jsonIndent = "    "
+
+// jsonMember 为对象成员在原文中的位置：start 为键的引号，end 为值之后。
+type jsonMember struct {
+	key        string
+	start, end int
+	value      json.RawMessage
+}
+
+// jsonObject 记录 JSON 对象各成员在原文中的位置，用于只改动个别键而保留其余内容的顺序与格式。
+type jsonObject struct {
+	data        []byte
+	open, close int
+	// base 为左花括号所在行的缩进。
+	base    string
+	members []jsonMember
+}
+
+// parseJSONObject 解析 data 中的 JSON 对象；data 是更大文档中的一个值时，base 为它所在行的缩进。
+func parseJSONObject(data []byte, base string) (*jsonObject, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("invalid JSON")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("not a JSON object")
+	}
+	obj := &jsonObject{data: data, open: int(dec.InputOffset()) - 1, base: base}
+	for dec.More() {
+		prev := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		start := prev + bytes.IndexByte(data[prev:], '"')
+		obj.members = append(obj.members, jsonMember{key: key, start: start, end: int(dec.InputOffset()), value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	obj.close = int(dec.InputOffset()) - 1
+	return obj, nil
+}
+
+// member 返回键为 key 的成员；键重复时与 json.Unmarshal 一样取最后一个。
+func (o *jsonObject) member(key string) (jsonMember, int, bool) {
+	for i := len(o.members) - 1; i >= 0; i-- {
+		if o.members[i].key == key {
+			return o.members[i], i, true
+		}
+	}
+	return jsonMember{}, -1, false
+}
+
+// set 返回把 key 设为 value（已编码的 JSON）后的文档：已有的键原地替换值，否则追加到对象末尾并沿用上一个成员的缩进。
+func (o *jsonObject) set(key string, value []byte) []byte {
+	if m, _, ok := o.member(key); ok {
+		return splice(o.data, m.end-len(m.value), m.end, value)
+	}
+	entry := append(jsonString(key), ": "...)
+	entry = append(entry, value...)
+	if len(o.members) == 0 {
+		inner := "\n" + o.base + jsonIndent + string(entry) + "\n" + o.base
+		return splice(o.data, o.open+1, o.close, []byte(inner))
+	}
+	last := o.members[len(o.members)-1]
+	sep := ", "
+	if indent, ok := lineIndentBefore(o.data, last.start); ok {
+		sep = ",\n" + indent
+	}
+	return splice(o.data, last.end, last.end, append([]byte(sep), entry...))
+}
+
+// remove 返回删除 key 后的文档，连同它与相邻成员之间的逗号与空白。
+func (o *jsonObject) remove(key string) []byte {
+	m, i, ok := o.member(key)
+	switch {
+	case !ok:
+		return o.data
+	case len(o.members) == 1:
+		return splice(o.data, o.open+1, o.close, nil)
+	case i > 0:
+		return splice(o.data, o.members[i-1].end, m.end, nil)
+	default:
+		return splice(o.data, m.start, o.members[1].start, nil)
+	}
+}
+
+// splice 返回把 data[start:end] 替换为 repl 后的新切片，不修改 data。
+func splice(data []byte, start, end int, repl []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(repl))
+	out = append(out, data[:start]...)
+	out = append(out, repl...)
+	return append(out, data[end:]...)
+}
+
+// lineIndentBefore 返回 pos 所在行中 pos 之前的内容，仅当它全部是空白时 ok 为 true。
+func lineIndentBefore(data []byte, pos int) (string, bool) {
+	lineStart := bytes.LastIndexByte(data[:pos], '\n') + 1
+	prefix := data[lineStart:pos]
+	if lineStart == 0 || len(bytes.TrimLeft(prefix, " \t")) != 0 {
+		return "", false
+	}
+	return string(prefix), true
+}
+
+// lineIndent 返回 pos 所在行开头的空白。
+func lineIndent(data []byte, pos int) string {
+	line := data[bytes.LastIndexByte(data[:pos], '\n')+1:]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// jsonString 把 s 编码为 JSON 字符串，不转义路径中可能出现的 <、>、&。
+func jsonString(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
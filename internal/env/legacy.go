@@ -0,0 +1,131 @@
+package env
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Cleanup 描述写入配置块时顺带清理掉的内容，用于向用户说明 rc 文件中被删除了什么。
+type Cleanup struct {
+	Path       string   // 被清理的 rc 文件
+	Duplicates int      // 除被替换的配置块外多出的配置块数
+	Legacy     int      // 使用旧版或手工编辑标记的配置块数
+	Exports    []string // 配置块之外指向 govm 目录的 GOROOT 导出
+}
+
+// CleanupFunc 在 rc 文件写入后收到清理结果，只有确实清理了多余内容时才会调用。
+type CleanupFunc func(Cleanup)
+
+// SetCleanupReport 设置 rc 文件清理结果的回调；nil 表示不报告。
+func (m *Manager) SetCleanupReport(fn CleanupFunc) {
+	m.cleanupReport = fn
+}
+
+// blockMarker 判断 line 是否为配置块的开始或结束标记：当前标记之外，也识别旧版本与手工编辑留下的变体，
+// 例如 "# >>> govm >>>"、"# BEGIN govm"、"# govm end"。
+func blockMarker(line string) (start, end, legacy bool) {
+	trimmed := strings.TrimSpace(line)
+	switch trimmed {
+	case blockStart:
+		return true, false, false
+	case blockEnd:
+		return false, true, false
+	}
+	if !strings.HasPrefix(trimmed, "#") {
+		return false, false, false
+	}
+	words := strings.Fields(strings.ToLower(strings.TrimLeft(trimmed, "# ")))
+	// 过长的注释是普通说明文字，不当作标记。
+	if len(words) == 0 || len(words) > 4 {
+		return false, false, false
+	}
+	var govm bool
+	for _, word := range words {
+		switch strings.Trim(word, ":-=[]()") {
+		case "govm":
+			govm = true
+		case ">>>", "begin", "start":
+			start = true
+		case "<<<", "end", "stop":
+			end = true
+		}
+	}
+	if !govm || start == end {
+		return false, false, false
+	}
+	return start, end, true
+}
+
+// cleanConfig 删除 content 中所有成对的 govm 配置块，root 非空时一并删除块外指向 root 之下的 GOROOT 导出。
+// 开始标记只与其后、下一个开始标记之前的同类结束标记配对，不成对的标记原样保留，避免误删其后的用户配置。
+// 返回清理后的内容、删除的配置块数、其中旧版标记的块数与删除的 GOROOT 导出。
+func cleanConfig(content, root string) (string, int, int, []string) {
+	lines := strings.Split(content, "\n")
+	drop := make([]bool, len(lines))
+	var blocks, legacy int
+	for i := 0; i < len(lines); i++ {
+		start, _, old := blockMarker(lines[i])
+		if !start {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			nextStart, end, nextOld := blockMarker(lines[j])
+			if nextStart {
+				break
+			}
+			if end && nextOld == old {
+				for k := i; k <= j; k++ {
+					drop[k] = true
+				}
+				blocks++
+				if old {
+					legacy++
+				}
+				i = j
+				break
+			}
+		}
+	}
+	var exports []string
+	if root != "" {
+		for i, line := range lines {
+			if !drop[i] && ownedGoRoot(line, root) {
+				drop[i] = true
+				exports = append(exports, strings.TrimSpace(line))
+			}
+		}
+	}
+
+	var builder strings.Builder
+	for i, line := range lines {
+		if drop[i] {
+			continue
+		}
+		if line == "" && builder.Len() == 0 {
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(line)
+	}
+	return strings.Trim(builder.String(), "\n"), blocks, legacy, exports
+}
+
+// ownedGoRoot 报告 line 是否为导出 root 之下某个 GOROOT 的语句，例如从旧配置块中手工复制出来的一行。
+func ownedGoRoot(line, root string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "export GOROOT=") {
+		return false
+	}
+	return isManagedPath(strings.Trim(strings.TrimPrefix(trimmed, "export GOROOT="), `"'`), root)
+}
+
+// isManagedPath 报告 path 是否位于 govm 根目录 root 之下（不含 root 本身）；root 为空时总是 false。
+func isManagedPath(path, root string) bool {
+	if root == "" || path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(path))
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
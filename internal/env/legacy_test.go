@@ -0,0 +1,104 @@
+package env
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestUpdateShellConfigConsolidatesLegacyBlocks(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/home/dev/.govm"})
+	mgr.homeFn = func() (string, error) { return home, nil }
+	var reports []Cleanup
+	mgr.SetCleanupReport(func(c Cleanup) { reports = append(reports, c) })
+
+	rc := home + "/.zshrc"
+	existing := strings.Join([]string{
+		"alias ll='ls -l'",
+		"# >>> govm >>>",
+		`export GOROOT="/home/dev/.govm/versions/go1.19.0"`,
+		"# <<< govm <<<",
+		blockStart,
+		`export GOROOT="/home/dev/.govm/versions/go1.20.0"`,
+		blockEnd,
+		`export GOROOT="/home/dev/.govm/versions/go1.20.0"`,
+		`export GOROOT="/usr/local/go"`,
+		"# govm start: unmatched markers are left alone",
+		"# BEGIN govm",
+		"export PATH=\"$HOME/bin:$PATH\"",
+		"",
+	}, "\n")
+	if err := os.WriteFile(rc, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.UpdateShellConfig("zsh", "/home/dev/.govm/versions/go1.22.0"); err != nil {
+		t.Fatalf("UpdateShellConfig: %v", err)
+	}
+
+	data, _ := os.ReadFile(rc)
+	content := string(data)
+	if strings.Count(content, blockStart) != 1 || strings.Contains(content, "go1.19.0") || strings.Contains(content, "go1.20.0") {
+		t.Fatalf("blocks not consolidated:\n%s", content)
+	}
+	for _, kept := range []string{"alias ll='ls -l'", `export GOROOT="/usr/local/go"`, "# BEGIN govm", `export PATH="$HOME/bin:$PATH"`} {
+		if !strings.Contains(content, kept) {
+			t.Fatalf("user line %q lost:\n%s", kept, content)
+		}
+	}
+	want := Cleanup{Path: rc, Duplicates: 1, Legacy: 1, Exports: []string{`export GOROOT="/home/dev/.govm/versions/go1.20.0"`}}
+	if len(reports) != 1 || reports[0].Path != want.Path || reports[0].Duplicates != 1 || reports[0].Legacy != 1 || !slices.Equal(reports[0].Exports, want.Exports) {
+		t.Fatalf("reports = %+v, want %+v", reports, want)
+	}
+
+	if err := mgr.UpdateShellConfig("zsh", "/home/dev/.govm/versions/go1.22.1"); err != nil || len(reports) != 1 {
+		t.Fatalf("a clean file must not be reported again: %v, %+v", err, reports)
+	}
+}
+
+func TestBlockMarker(t *testing.T) {
+	t.Parallel()
+
+	for line, want := range map[string][2]bool{
+		blockStart:                   {true, false},
+		"  " + blockEnd:              {false, true},
+		"# >>> govm >>>":             {true, false},
+		"# BEGIN govm":               {true, false},
+		"## govm: end":               {false, true},
+		"# govm":                     {false, false},
+		"# start govm, then the end": {false, false},
+		"echo govm start":            {false, false},
+	} {
+		start, end, _ := blockMarker(line)
+		if start != want[0] || end != want[1] {
+			t.Errorf("blockMarker(%q) = %v, %v, want %v, %v", line, start, end, want[0], want[1])
+		}
+	}
+}
+
+func TestIsManagedPath(t *testing.T) {
+	t.Parallel()
+
+	for path, want := range map[string]bool{
+		"/govm/versions/go1.22.0": true,
+		"/govm/shims/":            true,
+		"/govm/..cache":           true,
+		"/govm":                   false,
+		"/govm-other/go":          false,
+		"/govm/../usr/bin":        false,
+		"versions/go1.22.0":       false,
+		"":                        false,
+	} {
+		if got := isManagedPath(path, "/govm"); got != want {
+			t.Errorf("isManagedPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if isManagedPath("/govm/versions/go1.22.0", "") {
+		t.Error("an empty root must not own any path")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/liangyou/govm/internal/storage"
@@ -24,23 +25,55 @@ type EnvManager interface {
 	UpdateShellConfig(shellType, goRoot string) error
 }
 
+// ReviewFunc 在改写 rc 文件或 managed 模式的 env.sh 前收到文件路径与改动前后的完整内容，返回 false 时放弃写入。
+type ReviewFunc func(path, before, after string) bool
+
+// ErrShellConfigDeclined 表示 ReviewFunc 拒绝了对 rc 文件的修改。
+var ErrShellConfigDeclined = errors.New("env: shell config change declined")
+
 // Manager 实现 EnvManager。
 type Manager struct {
 	storage storage.LocalStorage
 	cfg     models.Config
 
+	shimDir       string
+	review        ReviewFunc
+	cleanupReport CleanupFunc
+	conflicts     ConflictFunc
+
 	homeFn func() (string, error)
 	envFn  func(string) string
+	runFn  func(name string, args ...string) ([]byte, error)
+}
+
+// ManagerOption 配置 Manager。
+type ManagerOption func(*Manager)
+
+// WithShimDir 将版本化 shim 所在目录加入配置块中的 PATH。
+func WithShimDir(dir string) ManagerOption {
+	return func(m *Manager) {
+		m.shimDir = dir
+	}
 }
 
 // NewManager 构造环境配置服务。
-func NewManager(store storage.LocalStorage, cfg models.Config) *Manager {
-	return &Manager{
+func NewManager(store storage.LocalStorage, cfg models.Config, opts ...ManagerOption) *Manager {
+	m := &Manager{
 		storage: store,
 		cfg:     cfg,
 		homeFn:  os.UserHomeDir,
 		envFn:   os.Getenv,
+		runFn:   runCommand,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
+}
+
+// SetReview 设置改写 rc 文件前的审阅回调，例如 `govm use --confirm` 展示差异并请求确认；nil 表示直接写入。
+func (m *Manager) SetReview(fn ReviewFunc) {
+	m.review = fn
 }
 
 // SetCurrentVersion 将版本写入存储标记。
@@ -75,32 +108,150 @@ func (m *Manager) DetectShell() (string, error) {
 	}
 }
 
-// UpdateShellConfig 对指定 shell 写入配置块。
+// UpdateShellConfig 对指定 shell 写入配置块；shellRC 指定了文件时写入该文件，shellMode 为 managed 时配置块写入
+// env.sh，rc 文件中只保留 source 它的一行。
 func (m *Manager) UpdateShellConfig(shellType, goRoot string) error {
 	if goRoot == "" {
 		return errors.New("env: goRoot is required")
 	}
 
-	configPath, err := m.configFileForShell(shellType)
+	configPath, err := m.rcFile(shellType)
 	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
-		return fmt.Errorf("env: ensure config dir: %w", err)
+	// managed 模式下配置块写入 env.sh：它与 rc 文件一起经过冲突检查与审阅，rc 文件被接受后才写入，
+	// 放弃时两者都保持原样。
+	block := m.buildConfigBlock(goRoot)
+	var envPath, envBefore, envAfter string
+	if m.cfg.ShellMode == models.ShellManaged {
+		envPath = m.envFile()
+		if envBefore, err = readConfig(envPath); err != nil {
+			return err
+		}
+		envAfter = block + "\n"
+		block = sourceBlock(envPath)
+	}
+
+	existing, err := readConfig(configPath)
+	if err != nil {
+		return err
+	}
+	cleaned, blocks, legacy, exports := cleanConfig(existing, m.cfg.RootDir)
+	if cleaned, err = m.resolveConflicts(configPath, cleaned); err != nil {
+		return err
+	}
+	merged := appendBlock(cleaned, block)
+	rcChanged, envChanged := merged != existing, envBefore != envAfter
+	if m.review != nil {
+		if rcChanged && !m.review(configPath, existing, merged) {
+			return ErrShellConfigDeclined
+		}
+		if envChanged && !m.review(envPath, envBefore, envAfter) {
+			return ErrShellConfigDeclined
+		}
 	}
+	if envChanged {
+		if err := writeConfig(envPath, envAfter); err != nil {
+			return err
+		}
+	}
+	if !rcChanged {
+		return nil
+	}
+	if err := writeConfig(configPath, merged); err != nil {
+		return err
+	}
+	if m.cleanupReport != nil && (blocks > 1 || legacy > 0 || len(exports) > 0) {
+		m.cleanupReport(Cleanup{Path: configPath, Duplicates: max(blocks-1, 0), Legacy: legacy, Exports: exports})
+	}
+	return nil
+}
 
-	var existing []byte
-	if data, err := os.ReadFile(configPath); err == nil {
-		existing = data
+// RemoveShellConfig 从所有可能写入过的 rc 文件（.bashrc、.bash_profile、.zshrc 与 shellRC）中删除 govm 配置块（包括旧版
+// 标记的块与块外指向 govm 目录的 GOROOT 导出）并删除 managed 模式的 env.sh，返回被修改的文件。
+func (m *Manager) RemoveShellConfig() ([]string, error) {
+	home, err := m.homeFn()
+	if err != nil {
+		return nil, fmt.Errorf("env: home dir: %w", err)
+	}
+	paths := []string{filepath.Join(home, ".bashrc"), filepath.Join(home, ".bash_profile"), filepath.Join(home, ".zshrc")}
+	if m.cfg.ShellRC != "" && !slices.Contains(paths, m.cfg.ShellRC) {
+		paths = append(paths, m.cfg.ShellRC)
+	}
+	var changed []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return changed, fmt.Errorf("env: read %s: %w", path, err)
+		}
+		cleaned, blocks, _, exports := cleanConfig(string(data), m.cfg.RootDir)
+		if blocks == 0 && len(exports) == 0 {
+			continue
+		}
+		if cleaned != "" {
+			cleaned += "\n"
+		}
+		if err := os.WriteFile(path, []byte(cleaned), 0o644); err != nil {
+			return changed, fmt.Errorf("env: write %s: %w", path, err)
+		}
+		changed = append(changed, path)
+	}
+	if m.cfg.UserDir == "" && m.cfg.RootDir == "" {
+		return changed, nil
+	}
+	envPath := m.envFile()
+	if err := os.Remove(envPath); err == nil {
+		changed = append(changed, envPath)
 	} else if !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("env: read config: %w", err)
+		return changed, fmt.Errorf("env: remove %s: %w", envPath, err)
 	}
+	return changed, nil
+}
 
-	block := m.buildConfigBlock(goRoot)
-	merged := mergeConfig(string(existing), block)
+// rcFile 返回接收配置块的 rc 文件：shellRC 优先，否则按 shell 选择。
+func (m *Manager) rcFile(shellType string) (string, error) {
+	if m.cfg.ShellRC != "" {
+		return m.cfg.ShellRC, nil
+	}
+	return m.configFileForShell(shellType)
+}
+
+// envFile 返回 managed 模式下保存配置块的 env.sh；共享安装时位于用户目录，每个用户各自一份。
+func (m *Manager) envFile() string {
+	dir := m.cfg.UserDir
+	if dir == "" {
+		dir = m.cfg.RootDir
+	}
+	return filepath.Join(dir, "env.sh")
+}
+
+// sourceBlock 返回 managed 模式写入 rc 文件的配置块，只在 env.sh 存在时 source 它。
+func sourceBlock(envPath string) string {
+	return strings.Join([]string{blockStart, fmt.Sprintf("[ -f \"%[1]s\" ] && . \"%[1]s\"", envPath), blockEnd}, "\n")
+}
+
+// readConfig 读取 shell 配置文件，文件不存在时返回空内容。
+func readConfig(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("env: read config: %w", err)
+	}
+	return string(data), nil
+}
 
-	return os.WriteFile(configPath, []byte(merged), 0o644)
+// writeConfig 写入 shell 配置文件，必要时创建所在目录。
+func writeConfig(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("env: ensure config dir: %w", err)
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
 }
 
 func (m *Manager) configFileForShell(shellType string) (string, error) {
@@ -125,22 +276,67 @@ func (m *Manager) configFileForShell(shellType string) (string, error) {
 }
 
 func (m *Manager) buildConfigBlock(goRoot string) string {
-	defaultGopath := m.cfg.GoPath
-	if defaultGopath == "" {
-		defaultGopath = "$HOME/go"
+	lines := blockLines(goRoot, m.cfg.GoPath)
+	switch {
+	case m.shimDir != "" && m.cfg.GoShim:
+		// go 选择器在每次执行时解析版本并设置 GOROOT，shell 中不再固定 GOROOT，也不把某个版本的 bin 加入 PATH。
+		lines = []string{lines[0], lines[2], fmt.Sprintf("export PATH=\"%s:$PATH\"", m.shimDir)}
+	case m.shimDir != "":
+		lines[len(lines)-1] = fmt.Sprintf("export PATH=\"$GOROOT/bin:%s:$PATH\"", m.shimDir)
 	}
-	lines := []string{
+	if m.cfg.GoToolchain != "" {
+		lines = append(lines, fmt.Sprintf("export GOTOOLCHAIN=\"%s\"", m.cfg.GoToolchain))
+	}
+	// 通过 GOVM_HOME/GOVM_ROOT 迁移了根目录时写入配置块，使新 shell 中的 govm 仍使用同一根目录。
+	if root := m.relocatedRoot(); root != "" {
+		lines = append(lines, fmt.Sprintf("export %s=\"%s\"", storage.EnvHome, root))
+	}
+	lines = append(lines, blockEnd)
+	return strings.Join(lines, "\n")
+}
+
+// Block 返回只设置 GOROOT、GOPATH 与 PATH 的配置块，不含 shim 目录等本机设置，用于写入远程主机。
+func Block(goRoot, gopath string) string {
+	return strings.Join(append(blockLines(goRoot, gopath), blockEnd), "\n")
+}
+
+// MergeBlock 用 block 替换 existing 中已有的 govm 配置块，没有时追加到末尾。
+func MergeBlock(existing, block string) string {
+	return mergeConfig(existing, block)
+}
+
+// blockLines 返回配置块中除结束标记外的公共部分，gopath 为空时默认 $HOME/go。
+func blockLines(goRoot, gopath string) []string {
+	if gopath == "" {
+		gopath = "$HOME/go"
+	}
+	return []string{
 		blockStart,
 		fmt.Sprintf("export GOROOT=\"%s\"", goRoot),
-		fmt.Sprintf("export GOPATH=\"${GOPATH:-%s}\"", defaultGopath),
+		fmt.Sprintf("export GOPATH=\"${GOPATH:-%s}\"", gopath),
 		"export PATH=\"$GOROOT/bin:$PATH\"",
-		blockEnd,
 	}
-	return strings.Join(lines, "\n")
+}
+
+func (m *Manager) relocatedRoot() string {
+	for _, key := range []string{storage.EnvHome, storage.EnvRoot} {
+		if strings.TrimSpace(m.envFn(key)) == "" {
+			continue
+		}
+		if m.cfg.RootDir != "" {
+			return m.cfg.RootDir
+		}
+		return strings.TrimSpace(m.envFn(key))
+	}
+	return ""
 }
 
 func mergeConfig(existing, block string) string {
-	cleaned := removeExistingBlock(existing)
+	return appendBlock(removeExistingBlock(existing), block)
+}
+
+// appendBlock 把 block 追加到已删除旧配置块的 cleaned 末尾。
+func appendBlock(cleaned, block string) string {
 	cleaned = strings.TrimRight(cleaned, "\n")
 	if strings.TrimSpace(cleaned) == "" {
 		return block + "\n"
@@ -149,32 +345,8 @@ func mergeConfig(existing, block string) string {
 }
 
 func removeExistingBlock(content string) string {
-	var builder strings.Builder
-	lines := strings.Split(content, "\n")
-	skipping := false
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == blockStart {
-			skipping = true
-			continue
-		}
-		if trimmed == blockEnd {
-			skipping = false
-			continue
-		}
-		if skipping {
-			continue
-		}
-		if line == "" && builder.Len() == 0 {
-			continue
-		}
-		if builder.Len() > 0 {
-			builder.WriteByte('\n')
-		}
-		builder.WriteString(line)
-	}
-	result := builder.String()
-	return strings.Trim(result, "\n")
+	cleaned, _, _, _ := cleanConfig(content, "")
+	return cleaned
 }
 
 func fileExists(path string) bool {
@@ -1,6 +1,7 @@
 package env
 
 import (
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -83,6 +84,127 @@ func TestUpdateShellConfigCreatesAndReplacesBlock(t *testing.T) {
 	}
 }
 
+func TestUpdateShellConfigReview(t *testing.T) {
+	t.Parallel()
+
+	temp := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{})
+	mgr.homeFn = func() (string, error) { return temp, nil }
+	if err := mgr.UpdateShellConfig("zsh", "/opt/go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	path := temp + "/.zshrc"
+	original, _ := os.ReadFile(path)
+
+	var reviews int
+	approve := false
+	mgr.SetReview(func(p, before, after string) bool {
+		reviews++
+		if p != path || before != string(original) || !strings.Contains(after, "/opt/go1.22.0") {
+			t.Errorf("unexpected review of %s:\n%s\n---\n%s", p, before, after)
+		}
+		return approve
+	})
+	if err := mgr.UpdateShellConfig("zsh", "/opt/go1.22.0"); !errors.Is(err, ErrShellConfigDeclined) {
+		t.Fatalf("declined change must fail, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Fatalf("declined change was written:\n%s", data)
+	}
+	approve = true
+	if err := mgr.UpdateShellConfig("zsh", "/opt/go1.22.0"); err != nil {
+		t.Fatalf("approved change: %v", err)
+	}
+	if err := mgr.UpdateShellConfig("zsh", "/opt/go1.22.0"); err != nil || reviews != 2 {
+		t.Fatalf("an unchanged file must not be reviewed again: %v, %d reviews", err, reviews)
+	}
+}
+
+func TestUpdateShellConfigManagedFile(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	root := home + "/.govm"
+	rc := home + "/.profile"
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: root, ShellRC: rc, ShellMode: models.ShellManaged})
+	mgr.homeFn = func() (string, error) { return home, nil }
+	if err := os.WriteFile(rc, []byte("export EDITOR=vi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, goRoot := range []string{"/opt/go1.21.0", "/opt/go1.22.0"} {
+		if err := mgr.UpdateShellConfig("bash", goRoot); err != nil {
+			t.Fatalf("UpdateShellConfig %s: %v", goRoot, err)
+		}
+	}
+
+	want := "export EDITOR=vi\n\n" + blockStart + "\n[ -f \"" + root + "/env.sh\" ] && . \"" + root + "/env.sh\"\n" + blockEnd + "\n"
+	if data, _ := os.ReadFile(rc); string(data) != want {
+		t.Fatalf("rc file = %q, want %q", data, want)
+	}
+	envSh, _ := os.ReadFile(root + "/env.sh")
+	if !strings.Contains(string(envSh), `GOROOT="/opt/go1.22.0"`) || strings.Count(string(envSh), blockStart) != 1 {
+		t.Fatalf("env.sh not updated: %s", envSh)
+	}
+	if _, err := os.Stat(home + "/.bashrc"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf(".bashrc must not be created when shellRC is set: %v", err)
+	}
+
+	changed, err := mgr.RemoveShellConfig()
+	if err != nil || len(changed) != 2 || changed[0] != rc || changed[1] != root+"/env.sh" {
+		t.Fatalf("RemoveShellConfig = %v, %v", changed, err)
+	}
+	if data, _ := os.ReadFile(rc); string(data) != "export EDITOR=vi\n" {
+		t.Fatalf("user content not preserved: %q", data)
+	}
+}
+
+func TestUpdateShellConfigManagedFileKeptWhenAborted(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	root := home + "/.govm"
+	rc := home + "/.profile"
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: root, ShellRC: rc, ShellMode: models.ShellManaged})
+	mgr.homeFn = func() (string, error) { return home, nil }
+	mgr.envFn = func(string) string { return "" }
+	if err := mgr.UpdateShellConfig("bash", root+"/versions/go1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	envPath := root + "/env.sh"
+	original, _ := os.ReadFile(envPath)
+
+	var reviewed []string
+	mgr.SetReview(func(p, before, after string) bool {
+		reviewed = append(reviewed, p)
+		if p == envPath && (before != string(original) || !strings.Contains(after, "go1.22.0")) {
+			t.Errorf("unexpected env.sh review:\n%s\n---\n%s", before, after)
+		}
+		return false
+	})
+	if err := mgr.UpdateShellConfig("bash", root+"/versions/go1.22.0"); !errors.Is(err, ErrShellConfigDeclined) {
+		t.Fatalf("declined change must fail, got %v", err)
+	}
+	if len(reviewed) != 1 || reviewed[0] != envPath {
+		t.Fatalf("the env.sh change must be reviewed, got %v", reviewed)
+	}
+	if data, _ := os.ReadFile(envPath); string(data) != string(original) {
+		t.Fatalf("declined change was written to env.sh:\n%s", data)
+	}
+
+	mgr.SetReview(nil)
+	rcData, _ := os.ReadFile(rc)
+	if err := os.WriteFile(rc, append([]byte("export GOROOT=/usr/local/go\n"), rcData...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mgr.SetConflictHandler(func([]Conflict) bool { return false })
+	if err := mgr.UpdateShellConfig("bash", root+"/versions/go1.22.0"); !errors.Is(err, ErrShellConfigConflict) {
+		t.Fatalf("declined conflicts must abort, got %v", err)
+	}
+	if data, _ := os.ReadFile(envPath); string(data) != string(original) {
+		t.Fatalf("aborted change was written to env.sh:\n%s", data)
+	}
+}
+
 func TestDetectShell(t *testing.T) {
 	t.Parallel()
 
@@ -102,3 +224,91 @@ func TestDetectShell(t *testing.T) {
 		t.Fatalf("expected zsh, got %s", shell)
 	}
 }
+
+func TestConfigBlockExportsRelocatedRoot(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewManager(&stubStorage{}, models.Config{RootDir: "/data/govm"})
+	mgr.envFn = func(key string) string {
+		if key == "GOVM_ROOT" {
+			return "/data/govm"
+		}
+		return ""
+	}
+	block := mgr.buildConfigBlock("/data/govm/versions/go1.22.0")
+	if !strings.Contains(block, `export GOVM_HOME="/data/govm"`) {
+		t.Fatalf("relocated root not exported: %s", block)
+	}
+
+	mgr.envFn = func(string) string { return "" }
+	if strings.Contains(mgr.buildConfigBlock("/tmp/go"), "GOVM_HOME") {
+		t.Fatal("default root must not be exported")
+	}
+}
+
+func TestConfigBlockAddsShimDir(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewManager(&stubStorage{}, models.Config{}, WithShimDir("/home/u/.govm/bin"))
+	block := mgr.buildConfigBlock("/tmp/go")
+	if !strings.Contains(block, `export PATH="$GOROOT/bin:/home/u/.govm/bin:$PATH"`) {
+		t.Fatalf("shim dir missing from PATH: %s", block)
+	}
+
+	mgr = NewManager(&stubStorage{}, models.Config{GoShim: true}, WithShimDir("/home/u/.govm/bin"))
+	block = mgr.buildConfigBlock("/tmp/go")
+	if !strings.Contains(block, `export PATH="/home/u/.govm/bin:$PATH"`) || strings.Contains(block, "GOROOT") {
+		t.Fatalf("the go shim must replace GOROOT and its bin in PATH: %s", block)
+	}
+}
+
+func TestConfigBlockSetsGoToolchain(t *testing.T) {
+	t.Parallel()
+
+	if block := NewManager(&stubStorage{}, models.Config{}).buildConfigBlock("/tmp/go"); strings.Contains(block, "GOTOOLCHAIN") {
+		t.Fatalf("GOTOOLCHAIN must be left alone by default: %s", block)
+	}
+	block := NewManager(&stubStorage{}, models.Config{GoToolchain: "local"}).buildConfigBlock("/tmp/go")
+	if !strings.Contains(block, `export GOTOOLCHAIN="local"`) {
+		t.Fatalf("GOTOOLCHAIN missing from block: %s", block)
+	}
+}
+
+func TestRemoveShellConfigCleansEveryRcFile(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	mgr := NewManager(&stubStorage{}, models.Config{})
+	mgr.homeFn = func() (string, error) { return home, nil }
+	if err := os.WriteFile(home+"/.bashrc", []byte("alias ll='ls -l'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, shell := range []string{"bash", "zsh"} {
+		if err := mgr.UpdateShellConfig(shell, "/tmp/go"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(home+"/.bash_profile", []byte("export EDITOR=vi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := mgr.RemoveShellConfig()
+	if err != nil {
+		t.Fatalf("RemoveShellConfig: %v", err)
+	}
+	if len(changed) != 2 || !strings.HasSuffix(changed[0], ".bashrc") || !strings.HasSuffix(changed[1], ".zshrc") {
+		t.Fatalf("unexpected changed files: %v", changed)
+	}
+	if data, _ := os.ReadFile(home + "/.bashrc"); string(data) != "alias ll='ls -l'\n" {
+		t.Fatalf("user content not preserved: %q", data)
+	}
+	for _, name := range changed {
+		data, _ := os.ReadFile(name)
+		if strings.Contains(string(data), blockStart) {
+			t.Fatalf("block left in %s", name)
+		}
+	}
+	if data, _ := os.ReadFile(home + "/.bash_profile"); string(data) != "export EDITOR=vi\n" {
+		t.Fatalf("untouched file changed: %q", data)
+	}
+}
@@ -0,0 +1,78 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/liangyou/govm/internal/shims"
+)
+
+// PathCheck 描述按 PATH 顺序解析 go 命令（which go）的结果。
+type PathCheck struct {
+	// Resolved 为 PATH 中第一个 go 可执行文件，未找到时为空。
+	Resolved string
+	// Managed 为 true 时 PATH 中存在 govm 管理的 GOROOT/bin 或 go 选择器。
+	Managed bool
+	// Shadows 为排在 govm 管理目录之前、会优先生效的其他 go 可执行文件。
+	Shadows []string
+}
+
+// Shadowed 报告 go 命令是否会解析到 govm 之外的安装。
+func (c PathCheck) Shadowed() bool {
+	return len(c.Shadows) > 0
+}
+
+// CheckPath 按 pathEnv 的顺序查找 go，isManaged 判断目录是否属于 govm 管理的安装。
+func CheckPath(pathEnv string, isManaged func(dir string) bool) PathCheck {
+	var check PathCheck
+	var foreign []string
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			dir = "."
+		}
+		bin := filepath.Join(dir, "go")
+		// govm 的 go 选择器总是转交给 govm 管理的某个版本，同样算作受管理的 go。
+		selector := shims.IsSelector(bin)
+		if !selector && !isExecutable(bin) {
+			continue
+		}
+		if check.Resolved == "" {
+			check.Resolved = bin
+		}
+		if selector || isManaged(filepath.Clean(dir)) {
+			check.Managed = true
+			check.Shadows = foreign
+			return check
+		}
+		if !sameFileAsAny(bin, foreign) {
+			foreign = append(foreign, bin)
+		}
+	}
+	return check
+}
+
+// ManagedBy 返回判断目录是否位于 versionsDir 下某个版本 bin 目录的函数。
+func ManagedBy(versionsDir string) func(dir string) bool {
+	versionsDir = filepath.Clean(versionsDir)
+	return func(dir string) bool {
+		return filepath.Base(dir) == "bin" && filepath.Dir(filepath.Dir(dir)) == versionsDir
+	}
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+func sameFileAsAny(path string, others []string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	for _, o := range others {
+		if oi, err := os.Stat(o); err == nil && os.SameFile(info, oi) {
+			return true
+		}
+	}
+	return false
+}
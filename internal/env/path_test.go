@@ -0,0 +1,62 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeGo(t *testing.T, dir string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "go")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return bin
+}
+
+func TestCheckPathReportsShadowingGo(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	versions := filepath.Join(root, "versions")
+	system := writeGo(t, filepath.Join(root, "usr", "bin"))
+	managed := writeGo(t, filepath.Join(versions, "go1.22.0", "bin"))
+	empty := filepath.Join(root, "empty")
+
+	pathEnv := strings.Join([]string{empty, filepath.Dir(system), filepath.Dir(managed)}, string(os.PathListSeparator))
+	check := CheckPath(pathEnv, ManagedBy(versions))
+	if !check.Managed || check.Resolved != system || len(check.Shadows) != 1 || check.Shadows[0] != system {
+		t.Fatalf("unexpected check: %#v", check)
+	}
+
+	pathEnv = strings.Join([]string{filepath.Dir(managed), filepath.Dir(system)}, string(os.PathListSeparator))
+	if check := CheckPath(pathEnv, ManagedBy(versions)); check.Shadowed() || check.Resolved != managed {
+		t.Fatalf("managed go first must not be shadowed: %#v", check)
+	}
+
+	if check := CheckPath(filepath.Dir(system), ManagedBy(versions)); check.Managed || check.Shadowed() {
+		t.Fatalf("missing govm entry must not count as shadowing: %#v", check)
+	}
+
+	shimDir := filepath.Join(root, "shims")
+	if err := os.MkdirAll(shimDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	govm := filepath.Join(root, "govm")
+	if err := os.WriteFile(govm, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	selector := filepath.Join(shimDir, "go")
+	if err := os.Symlink(govm, selector); err != nil {
+		t.Fatal(err)
+	}
+	pathEnv = strings.Join([]string{shimDir, filepath.Dir(system)}, string(os.PathListSeparator))
+	if check := CheckPath(pathEnv, ManagedBy(versions)); !check.Managed || check.Shadowed() || check.Resolved != selector {
+		t.Fatalf("the go selector must count as the managed go: %#v", check)
+	}
+}
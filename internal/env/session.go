@@ -0,0 +1,136 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// runCommand 执行外部命令并返回标准输出。
+func runCommand(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// SessionEnvFile 为写入 environment.d 的文件名；systemd 按文件名顺序读取，60 排在发行版默认配置之后。
+const SessionEnvFile = "60-govm.conf"
+
+// sessionEnv 返回图形会话需要设置的变量（不含 GOPATH 与 PATH）以及需要加到 PATH 最前面的目录，与 shell 配置块保持一致。
+func (m *Manager) sessionEnv(goRoot string) ([][2]string, []string) {
+	var vars [][2]string
+	var dirs []string
+	switch {
+	case m.shimDir != "" && m.cfg.GoShim:
+		dirs = []string{m.shimDir}
+	case m.shimDir != "":
+		vars = append(vars, [2]string{"GOROOT", goRoot})
+		dirs = []string{filepath.Join(goRoot, "bin"), m.shimDir}
+	default:
+		vars = append(vars, [2]string{"GOROOT", goRoot})
+		dirs = []string{filepath.Join(goRoot, "bin")}
+	}
+	if m.cfg.GoToolchain != "" {
+		vars = append(vars, [2]string{"GOTOOLCHAIN", m.cfg.GoToolchain})
+	}
+	if root := m.relocatedRoot(); root != "" {
+		vars = append(vars, [2]string{storage.EnvHome, root})
+	}
+	return vars, dirs
+}
+
+// defaultGoPath 返回配置中的 gopath，未配置时为 ~/go；environment.d 不展开 $HOME，因此使用绝对路径。
+func (m *Manager) defaultGoPath(home string) string {
+	if m.cfg.GoPath != "" {
+		return m.cfg.GoPath
+	}
+	return filepath.Join(home, "go")
+}
+
+// sessionEnvDir 返回 systemd 用户会话读取的 environment.d 目录，遵循 XDG_CONFIG_HOME。
+func (m *Manager) sessionEnvDir(home string) string {
+	if dir := m.envFn("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "environment.d")
+	}
+	return filepath.Join(home, ".config", "environment.d")
+}
+
+// WriteSessionEnvironment 把 goRoot 写入 ~/.config/environment.d/60-govm.conf，供不读取 .bashrc 的图形会话
+// （从桌面启动的 VS Code、GoLand）在下次登录时使用，返回写入的文件。
+func (m *Manager) WriteSessionEnvironment(goRoot string) (string, error) {
+	if goRoot == "" {
+		return "", errors.New("env: goRoot is required")
+	}
+	home, err := m.homeFn()
+	if err != nil {
+		return "", fmt.Errorf("env: home dir: %w", err)
+	}
+	vars, dirs := m.sessionEnv(goRoot)
+	lines := []string{"# Managed by govm: GUI sessions started by systemd read this file at login. Regenerate with `govm ide-setup session`."}
+	for _, v := range vars {
+		lines = append(lines, v[0]+"="+v[1])
+	}
+	lines = append(lines,
+		fmt.Sprintf("GOPATH=${GOPATH:-%s}", m.defaultGoPath(home)),
+		"PATH="+strings.Join(append(dirs, "${PATH}"), ":"),
+	)
+	path := filepath.Join(m.sessionEnvDir(home), SessionEnvFile)
+	if err := writeConfig(path, strings.Join(lines, "\n")+"\n"); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ImportSessionEnvironment 通过 `systemctl --user set-environment` 把 goRoot 推送到正在运行的 systemd 用户管理器，
+// 之后由桌面启动的程序无需重新登录即可看到新版本；PATH 中此前由 govm 加入的目录会被替换，已有的 GOPATH 保持不变。
+func (m *Manager) ImportSessionEnvironment(goRoot string) error {
+	if goRoot == "" {
+		return errors.New("env: goRoot is required")
+	}
+	home, err := m.homeFn()
+	if err != nil {
+		return fmt.Errorf("env: home dir: %w", err)
+	}
+	out, err := m.runFn("systemctl", "--user", "show-environment")
+	if err != nil {
+		return fmt.Errorf("env: systemctl --user show-environment: %w", err)
+	}
+	current := map[string]string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			current[key] = value
+		}
+	}
+
+	vars, dirs := m.sessionEnv(goRoot)
+	if current["GOPATH"] == "" {
+		vars = append(vars, [2]string{"GOPATH", m.defaultGoPath(home)})
+	}
+	for _, dir := range filepath.SplitList(current["PATH"]) {
+		if dir != "" && !m.managedDir(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	vars = append(vars, [2]string{"PATH", strings.Join(dirs, string(os.PathListSeparator))})
+
+	args := []string{"--user", "set-environment"}
+	for _, v := range vars {
+		args = append(args, v[0]+"="+v[1])
+	}
+	if _, err := m.runFn("systemctl", args...); err != nil {
+		return fmt.Errorf("env: systemctl --user set-environment: %w", err)
+	}
+	return nil
+}
+
+// managedDir 报告 PATH 中的 dir 是否由 govm 加入：govm 根目录下的 bin 目录或 shim 目录。
+func (m *Manager) managedDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	if m.shimDir != "" && dir == filepath.Clean(m.shimDir) {
+		return true
+	}
+	return isManagedPath(dir, m.cfg.RootDir)
+}
@@ -0,0 +1,82 @@
+package env
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ToolchainSetting 描述 go 命令实际采用的 GOTOOLCHAIN 及其来源。
+type ToolchainSetting struct {
+	// Value 为 GOTOOLCHAIN 的取值，各处都未设置时为空（go 命令按 local 处理）。
+	Value string
+	// Source 为取值来源：环境变量名 GOTOOLCHAIN，或 go env -w 配置文件、$GOROOT/go.env 的路径。
+	Source string
+}
+
+// Downloads 报告该设置是否允许 go 命令在 go.mod 要求更高版本时自行下载工具链。
+func (s ToolchainSetting) Downloads() bool {
+	return s.Value == "auto" || strings.HasSuffix(s.Value, "+auto")
+}
+
+// ResolveToolchain 按 go 命令的优先级查找 GOTOOLCHAIN：环境变量、GOENV 指向的 go env -w 配置文件，最后是 goRoot 下的 go.env。
+func ResolveToolchain(goRoot string, getenv func(string) string) ToolchainSetting {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	if v := strings.TrimSpace(getenv("GOTOOLCHAIN")); v != "" {
+		return ToolchainSetting{Value: v, Source: "GOTOOLCHAIN"}
+	}
+	candidates := []string{goEnvFile(getenv)}
+	if goRoot != "" {
+		candidates = append(candidates, filepath.Join(goRoot, "go.env"))
+	}
+	for _, path := range candidates {
+		if path == "" {
+			continue
+		}
+		if v := readEnvFileValue(path, "GOTOOLCHAIN"); v != "" {
+			return ToolchainSetting{Value: v, Source: path}
+		}
+	}
+	return ToolchainSetting{}
+}
+
+// goEnvFile 返回 go env -w 写入的配置文件，GOENV=off 时为空。
+func goEnvFile(getenv func(string) string) string {
+	switch v := strings.TrimSpace(getenv("GOENV")); v {
+	case "off":
+		return ""
+	case "":
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(dir, "go", "env")
+	default:
+		return v
+	}
+}
+
+// readEnvFileValue 读取 KEY=VALUE 格式文件中 key 的取值，文件不存在或没有该项时返回空。
+func readEnvFileValue(path, key string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	value := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && strings.TrimSpace(k) == key {
+			value = strings.TrimSpace(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveToolchainFollowsGoPrecedence(t *testing.T) {
+	t.Parallel()
+
+	temp := t.TempDir()
+	goRoot := filepath.Join(temp, "go1.22.0")
+	if err := os.MkdirAll(goRoot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(goRoot, "go.env"), []byte("# defaults\nGOPROXY=https://proxy.golang.org,direct\nGOTOOLCHAIN=auto\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	userEnv := filepath.Join(temp, "env")
+	vars := map[string]string{"GOENV": userEnv}
+	getenv := func(key string) string { return vars[key] }
+
+	if got := ResolveToolchain(goRoot, getenv); got.Value != "auto" || got.Source != filepath.Join(goRoot, "go.env") || !got.Downloads() {
+		t.Fatalf("go.env default not picked up: %+v", got)
+	}
+	if err := os.WriteFile(userEnv, []byte("GOTOOLCHAIN=go1.22.0+path\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveToolchain(goRoot, getenv); got.Value != "go1.22.0+path" || got.Source != userEnv || got.Downloads() {
+		t.Fatalf("go env -w setting must override go.env: %+v", got)
+	}
+	vars["GOTOOLCHAIN"] = "local"
+	if got := ResolveToolchain(goRoot, getenv); got.Value != "local" || got.Source != "GOTOOLCHAIN" {
+		t.Fatalf("environment must win: %+v", got)
+	}
+	if got := ResolveToolchain("", func(key string) string {
+		if key == "GOENV" {
+			return "off"
+		}
+		return ""
+	}); got.Value != "" {
+		t.Fatalf("nothing configured must resolve to empty: %+v", got)
+	}
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 事件类型常量，作为 porcelain 输出中 event 字段的稳定取值。
+const (
+	DownloadStart    = "download_start"
+	DownloadProgress = "download_progress"
+	Extract          = "extract"
+	Done             = "done"
+	Error            = "error"
+)
+
+// Field 表示事件中的一个键值对，保持插入顺序以便输出稳定。
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Event 描述一次安装流程中的生命周期事件。
+type Event struct {
+	Type   string
+	Fields []Field
+}
+
+// New 根据交替出现的 key、value 构造事件，落单的 key 会被忽略。
+func New(eventType string, kv ...string) Event {
+	e := Event{Type: eventType}
+	for i := 0; i+1 < len(kv); i += 2 {
+		e.Fields = append(e.Fields, Field{Key: kv[i], Value: kv[i+1]})
+	}
+	return e
+}
+
+// Get 返回指定 key 的取值。
+func (e Event) Get(key string) string {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Handler 处理单个事件。
+type Handler func(Event)
+
+// Bus 是一个同步的进程内事件总线，nil Bus 上的发布操作是安全的空操作。
+type Bus struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]Handler
+}
+
+// NewBus 创建事件总线。
+func NewBus() *Bus {
+	return &Bus{handlers: map[int]Handler{}}
+}
+
+// Subscribe 注册事件处理器，返回的函数用于取消订阅。
+func (b *Bus) Subscribe(h Handler) func() {
+	if b == nil || h == nil {
+		return func() {}
+	}
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.handlers[id] = h
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish 按订阅顺序同步分发事件。
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	ids := make([]int, 0, len(b.handlers))
+	for id := range b.handlers {
+		ids = append(ids, id)
+	}
+	handlers := make([]Handler, 0, len(ids))
+	sort.Ints(ids)
+	for _, id := range ids {
+		handlers = append(handlers, b.handlers[id])
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
+
+// FormatPorcelain 将事件格式化为单行 key=value 文本，例如：
+//
+//	event=download_progress version=1.21.0 downloaded=1024 total=4096
+func FormatPorcelain(e Event) string {
+	var b strings.Builder
+	b.WriteString("event=")
+	b.WriteString(quoteValue(e.Type))
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(quoteValue(f.Value))
+	}
+	return b.String()
+}
+
+func quoteValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\n\"=\\") {
+		return strconv.Quote(v)
+	}
+	return v
+}
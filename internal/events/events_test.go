@@ -0,0 +1,57 @@
+package events
+
+import "testing"
+
+func TestBusPublishAndUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	var got []string
+	unsubscribe := bus.Subscribe(func(e Event) {
+		got = append(got, e.Type)
+	})
+
+	bus.Publish(New(DownloadStart, "version", "1.21.0"))
+	unsubscribe()
+	bus.Publish(New(Done, "version", "1.21.0"))
+
+	if len(got) != 1 || got[0] != DownloadStart {
+		t.Fatalf("unexpected events: %v", got)
+	}
+}
+
+func TestNilBusIsNoop(t *testing.T) {
+	t.Parallel()
+
+	var bus *Bus
+	bus.Publish(New(Done))
+	bus.Subscribe(func(Event) {})()
+}
+
+func TestFormatPorcelain(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		event Event
+		want  string
+	}{
+		{
+			event: New(DownloadProgress, "version", "1.21.0", "downloaded", "10", "total", "20"),
+			want:  "event=download_progress version=1.21.0 downloaded=10 total=20",
+		},
+		{
+			event: New(Error, "version", "1.21.0", "message", "checksum mismatch"),
+			want:  `event=error version=1.21.0 message="checksum mismatch"`,
+		},
+		{
+			event: New(Done, "path", ""),
+			want:  `event=done path=""`,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := FormatPorcelain(tc.event); got != tc.want {
+			t.Fatalf("FormatPorcelain()=%q want %q", got, tc.want)
+		}
+	}
+}
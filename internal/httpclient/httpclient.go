@@ -0,0 +1,97 @@
+// Package httpclient 构建 govm 各网络组件共享的 HTTP 客户端，并统一管理超时设置。
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// 默认超时：版本列表等元数据请求应当很快返回，下载大文件则需要更宽裕的总时长。
+const (
+	DefaultConnectTimeout  = 10 * time.Second
+	DefaultRequestTimeout  = 30 * time.Second
+	DefaultDownloadTimeout = 30 * time.Minute
+)
+
+// Timeouts 描述网络超时，零值表示不限制。
+type Timeouts struct {
+	Connect  time.Duration // 建立 TCP 连接与 TLS 握手
+	Request  time.Duration // 元数据请求（如版本列表）的总时长
+	Download time.Duration // 下载单个归档的总时长
+}
+
+// DefaultTimeouts 返回默认超时设置。
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Connect:  DefaultConnectTimeout,
+		Request:  DefaultRequestTimeout,
+		Download: DefaultDownloadTimeout,
+	}
+}
+
+// TimeoutsFromConfig 以默认值为基础叠加配置文件中的非零超时。
+func TimeoutsFromConfig(cfg models.Config) Timeouts {
+	t := DefaultTimeouts()
+	if cfg.ConnectTimeout > 0 {
+		t.Connect = cfg.ConnectTimeout
+	}
+	if cfg.RequestTimeout > 0 {
+		t.Request = cfg.RequestTimeout
+	}
+	if cfg.DownloadTimeout > 0 {
+		t.Download = cfg.DownloadTimeout
+	}
+	return t
+}
+
+// Clients 持有共享 Transport 上的两个客户端：API 用于元数据请求，Download 用于下载归档。
+type Clients struct {
+	API      *http.Client
+	Download *http.Client
+
+	mu       sync.Mutex
+	timeouts Timeouts
+	dialer   *net.Dialer
+	base     *http.Transport
+}
+
+// New 按给定超时创建客户端。
+func New(t Timeouts) *Clients {
+	c := &Clients{dialer: &net.Dialer{KeepAlive: 30 * time.Second}}
+	c.base = http.DefaultTransport.(*http.Transport).Clone()
+	c.base.DialContext = c.dialContext
+	c.API = &http.Client{Transport: c.base}
+	c.Download = &http.Client{Transport: c.base}
+	c.SetTimeouts(t)
+	return c
+}
+
+// Timeouts 返回当前生效的超时设置。
+func (c *Clients) Timeouts() Timeouts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.timeouts
+}
+
+// SetTimeouts 更新超时设置，应在发起请求前调用（例如解析完命令行参数后）。
+func (c *Clients) SetTimeouts(t Timeouts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeouts = t
+	c.base.TLSHandshakeTimeout = t.Connect
+	c.API.Timeout = t.Request
+	c.Download.Timeout = t.Download
+}
+
+func (c *Clients) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	c.mu.Lock()
+	d := *c.dialer
+	d.Timeout = c.timeouts.Connect
+	c.mu.Unlock()
+	return d.DialContext(ctx, network, addr)
+}
@@ -0,0 +1,43 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestTimeoutsFromConfig(t *testing.T) {
+	t.Parallel()
+
+	got := TimeoutsFromConfig(models.Config{RequestTimeout: time.Second})
+	want := Timeouts{Connect: DefaultConnectTimeout, Request: time.Second, Download: DefaultDownloadTimeout}
+	if got != want {
+		t.Fatalf("unexpected timeouts: %#v", got)
+	}
+}
+
+func TestRequestTimeoutAppliesToAPIClient(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	clients := New(DefaultTimeouts())
+	clients.SetTimeouts(Timeouts{Connect: time.Second, Request: 50 * time.Millisecond})
+	if clients.Download.Timeout != 0 {
+		t.Fatalf("download timeout should be unlimited, got %v", clients.Download.Timeout)
+	}
+
+	_, err := clients.API.Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// TLS 描述访问私有镜像或经过 TLS 拦截代理时所需的证书设置。
+type TLS struct {
+	CABundle           string // 额外信任的 CA 证书（PEM），追加到系统证书池
+	ClientCert         string // mTLS 客户端证书（PEM）
+	ClientKey          string // mTLS 客户端私钥（PEM）
+	InsecureSkipVerify bool   // 跳过服务端证书校验，仅用于排障
+}
+
+// TLSFromConfig 从全局配置中提取 TLS 设置。
+func TLSFromConfig(cfg models.Config) TLS {
+	return TLS{
+		CABundle:           cfg.CABundle,
+		ClientCert:         cfg.ClientCert,
+		ClientKey:          cfg.ClientKey,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+}
+
+// ConfigureTLS 将证书设置应用到共享 Transport，所有客户端随之生效。
+func (c *Clients) ConfigureTLS(t TLS) error {
+	tlsCfg, err := t.build()
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base.TLSClientConfig = tlsCfg
+	return nil
+}
+
+func (t TLS) build() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: t.InsecureSkipVerify}
+
+	if t.CABundle != "" {
+		pem, err := os.ReadFile(t.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("httpclient: read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("httpclient: no certificates found in %s", t.CABundle)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (t.ClientCert == "") != (t.ClientKey == "") {
+		return nil, errors.New("httpclient: clientCert and clientKey must be set together")
+	}
+	if t.ClientCert != "" {
+		pair, err := tls.LoadX509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("httpclient: load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	return cfg, nil
+}
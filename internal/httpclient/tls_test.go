@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigureTLSCustomCABundle(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, block, 0o644); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+
+	untrusted := New(DefaultTimeouts())
+	if _, err := untrusted.API.Get(server.URL); err == nil {
+		t.Fatal("expected certificate error without CA bundle")
+	}
+
+	trusted := New(DefaultTimeouts())
+	if err := trusted.ConfigureTLS(TLS{CABundle: bundle}); err != nil {
+		t.Fatalf("ConfigureTLS: %v", err)
+	}
+	resp, err := trusted.Download.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+
+	insecure := New(DefaultTimeouts())
+	if err := insecure.ConfigureTLS(TLS{InsecureSkipVerify: true}); err != nil {
+		t.Fatalf("ConfigureTLS: %v", err)
+	}
+	resp, err = insecure.API.Get(server.URL)
+	if err != nil {
+		t.Fatalf("insecure request failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestConfigureTLSRejectsInvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	clients := New(DefaultTimeouts())
+	for name, cfg := range map[string]TLS{
+		"missing bundle": {CABundle: filepath.Join(dir, "missing.pem")},
+		"empty bundle":   {CABundle: empty},
+		"cert only":      {ClientCert: empty},
+		"bad pair":       {ClientCert: empty, ClientKey: empty},
+	} {
+		if err := clients.ConfigureTLS(cfg); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
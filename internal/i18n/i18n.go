@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 支持的语言标识。
+const (
+	English = "en"
+	Chinese = "zh-CN"
+)
+
+// EnvLang 优先于 LC_ALL、LC_MESSAGES 与 LANG，只影响 govm 自身的输出语言。
+const EnvLang = "GOVM_LANG"
+
+// bundles 按语言保存消息翻译，键为英文原文（含格式化动词）；en 即原文，无需条目。
+var bundles = map[string]map[string]string{
+	English: {},
+	Chinese: zhCN,
+}
+
+// Locales 返回支持的语言标识，按字母排序。
+func Locales() []string {
+	locales := make([]string, 0, len(bundles))
+	for l := range bundles {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Detect 依次读取 GOVM_LANG、LC_ALL、LC_MESSAGES 与 LANG，返回第一个可识别的语言，都不可识别时返回 en。
+func Detect(getenv func(string) string) string {
+	for _, key := range []string{EnvLang, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := strings.TrimSpace(getenv(key)); v != "" {
+			if locale, ok := Normalize(v); ok {
+				return locale
+			}
+			// 与 POSIX 一致：已设置但无法识别（例如 C）的变量同样决定结果，不再查看后面的变量。
+			return English
+		}
+	}
+	return English
+}
+
+// Normalize 把 zh_CN.UTF-8、zh-Hans、en_US 等写法归一为支持的语言标识。
+func Normalize(value string) (string, bool) {
+	v := strings.ToLower(value)
+	if i := strings.IndexAny(v, ".@"); i >= 0 {
+		v = v[:i]
+	}
+	v = strings.ReplaceAll(v, "_", "-")
+	switch {
+	case v == "zh" || strings.HasPrefix(v, "zh-"):
+		return Chinese, true
+	case v == "en" || strings.HasPrefix(v, "en-"):
+		return English, true
+	default:
+		return "", false
+	}
+}
+
+// Printer 按所选语言翻译消息，缺少翻译的消息原样输出英文。
+type Printer struct {
+	locale   string
+	messages map[string]string
+}
+
+// New 返回 locale 对应的 Printer，不支持的语言退回 en。
+func New(locale string) *Printer {
+	if normalized, ok := Normalize(locale); ok {
+		locale = normalized
+	} else {
+		locale = English
+	}
+	return &Printer{locale: locale, messages: bundles[locale]}
+}
+
+// Locale 返回实际使用的语言标识。
+func (p *Printer) Locale() string {
+	return p.locale
+}
+
+// Has 报告当前语言是否有 format 的翻译；en 使用原文，总是返回 true。
+func (p *Printer) Has(format string) bool {
+	if p == nil || p.locale == English {
+		return true
+	}
+	_, ok := p.messages[format]
+	return ok
+}
+
+// Sprintf 翻译 format 后再格式化；没有参数时不做格式化，消息中的 % 原样保留。
+func (p *Printer) Sprintf(format string, args ...any) string {
+	msg := format
+	if p != nil {
+		if translated, ok := p.messages[format]; ok {
+			msg = translated
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
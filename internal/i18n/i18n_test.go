@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, English},
+		{map[string]string{"LANG": "zh_CN.UTF-8"}, Chinese},
+		{map[string]string{"LANG": "zh_TW.UTF-8"}, Chinese},
+		{map[string]string{"LANG": "zh_CN.UTF-8", "LC_ALL": "C"}, English},
+		{map[string]string{"LANG": "en_US.UTF-8", "LC_MESSAGES": "zh_CN"}, Chinese},
+		{map[string]string{"LANG": "zh_CN.UTF-8", EnvLang: "en"}, English},
+		{map[string]string{"LC_ALL": "en_US", EnvLang: "zh-cn"}, Chinese},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, English},
+	}
+	for _, tc := range cases {
+		if got := Detect(func(key string) string { return tc.env[key] }); got != tc.want {
+			t.Errorf("Detect(%v) = %s, want %s", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestPrinterFallsBackToEnglish(t *testing.T) {
+	t.Parallel()
+
+	p := New("zh_CN.UTF-8")
+	if p.Locale() != Chinese {
+		t.Fatalf("locale = %s", p.Locale())
+	}
+	if got := p.Sprintf("Uninstalled go%s\n", "1.22.0"); got != "已卸载 go1.22.0\n" {
+		t.Fatalf("translated = %q", got)
+	}
+	if got := p.Sprintf("untranslated %d%%", 5); got != "untranslated 5%" {
+		t.Fatalf("fallback = %q", got)
+	}
+	if got := p.Sprintf("100% literal"); got != "100% literal" {
+		t.Fatalf("messages without args must not be formatted: %q", got)
+	}
+	if got := New("de").Sprintf("Uninstalled go%s\n", "1.22.0"); got != "Uninstalled go1.22.0\n" {
+		t.Fatalf("unsupported locale must use English: %q", got)
+	}
+}
+
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0-9]*[a-z]`)
+
+// 每条翻译使用的参数必须与英文原文一致，否则输出会出现 %!s(MISSING) 或丢失信息。
+func TestTranslationsKeepArguments(t *testing.T) {
+	t.Parallel()
+
+	for _, locale := range Locales() {
+		for key, msg := range bundles[locale] {
+			verbs := verbPattern.FindAllString(key, -1)
+			args := make([]any, len(verbs))
+			for i, v := range verbs {
+				if strings.HasSuffix(v, "d") {
+					args[i] = 1000 + i
+				} else {
+					args[i] = fmt.Sprintf("<arg%d>", i)
+				}
+			}
+			if len(args) == 0 {
+				if verbPattern.MatchString(msg) {
+					t.Errorf("%s: %q adds verbs to %q", locale, msg, key)
+				}
+				continue
+			}
+			got := fmt.Sprintf(msg, args...)
+			if strings.Contains(got, "%!") {
+				t.Errorf("%s: %q does not match the arguments of %q: %s", locale, msg, key, got)
+			}
+			for _, arg := range args {
+				if !strings.Contains(got, fmt.Sprint(arg)) {
+					t.Errorf("%s: %q drops argument %v of %q", locale, msg, arg, key)
+				}
+			}
+		}
+	}
+}
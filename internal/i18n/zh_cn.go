@@ -0,0 +1,308 @@
+package i18n
+
+// zhCN 为简体中文翻译；新增面向用户的消息时在此补充，缺少的条目输出英文原文。
+var zhCN = map[string]string{
+	// 安装与切换
+	"Installation complete": "安装完成",
+	"Next:":                 "下一步:",
+	"Tip:":                  "提示:",
+	"%s run %s to apply the environment variables now\n": "%s 运行 %s 让环境变量立即生效\n",
+	"%s run %s to switch to the new version\n":           "%s 执行 %s 切换到新安装版本\n",
+	"Installed go%s\n":                              "已安装 go%s\n",
+	"Installed %s\n":                                "已安装 %s\n",
+	"Now using go%s\n":                              "正在使用 go%s\n",
+	"Current version: %s (%s)\n":                    "当前版本：%s（%s）\n",
+	"No active Go version.":                         "当前没有激活的 Go 版本。",
+	"Waiting for another govm process to finish...": "正在等待另一个 govm 进程结束...",
+	"Showing %d-%d of %d, use -page %d for more\n":  "显示第 %d-%d 项，共 %d 项，使用 -page %d 查看更多\n",
+	"Showing %d-%d of %d\n":                         "显示第 %d-%d 项，共 %d 项\n",
+	"No remote versions available.":                 "没有可用的远程版本。",
+	"No versions installed.":                        "尚未安装任何版本。",
+
+	// 卸载与锁定
+	"go%s is active — uninstall anyway?":            "go%s 正在使用中，仍要卸载吗？",
+	"Uninstalled go%s\n":                            "已卸载 go%s\n",
+	"No versions remain installed.":                 "已没有安装的版本。",
+	"Remaining versions:":                           "剩余版本：",
+	"Locked go%s; uninstall now requires --force\n": "已锁定 go%s；卸载时需要 --force\n",
+	"Unlocked go%s\n":                               "已解锁 go%s\n",
+	"Freed %s of module cache for go%s\n":           "已释放 go%[2]s 占用的模块缓存 %[1]s\n",
+	"No toolchain modules for go%s in %s\n":         "%[2]s 中没有 go%[1]s 的工具链模块\n",
+	"Removed %s\n":                                  "已删除 %s\n",
+	"GOCACHE is shared by all versions; run `go clean -cache` to reclaim build cache space.": "GOCACHE 由所有版本共享；如需回收构建缓存空间，请执行 `go clean -cache`。",
+
+	// 状态
+	"Active:      %s\n":                  "当前生效：%s\n",
+	"Default:     %s\n":                  "默认版本：%s\n",
+	"Installed:   %d (%s)\n":             "已安装：  %d（%s）\n",
+	"Mirror:      %s\n":                  "下载镜像：%s\n",
+	"Catalog:     %s\n":                  "版本列表：%s\n",
+	"Build cache:":                       "构建缓存：",
+	"Mod cache:":                         "模块缓存：",
+	"%-12s %s (%s, managed by go; %s)\n": "%s%s（%s，由 go 管理；%s）\n",
+	"Updates:     up to date":            "可用更新：已是最新",
+	"Updates:     unknown (%s)\n":        "可用更新：未知（%s）\n",
+	"Updates:     %d available\n":        "可用更新：%d 个\n",
+	"Freed %s from %s (%s)\n":            "已从 %[2]s（%[3]s）释放 %[1]s\n",
+	"Version usage:":                     "版本使用情况：",
+	"No activation history yet.":         "还没有切换记录。",
+	"current version marker names go%s, but its go binary is missing from %s":                    "当前版本标记指向 go%s，但 %s 中缺少 go 可执行文件",
+	"current version marker names go%s, which is missing from metadata although %s still exists": "当前版本标记指向 go%s，元数据中没有该版本，但 %s 仍然存在",
+	"current version marker names go%s, which is not installed":                                  "当前版本标记指向 go%s，但该版本未安装",
+	"%s %s (run `govm doctor --repair` to fix)\n":                                                "%s %s（执行 `govm doctor --repair` 修复）\n",
+	"%s (run `govm doctor --repair` to fix)":                                                     "%s（执行 `govm doctor --repair` 修复）",
+	"%s; re-registered go%s from %s":                                                             "%[1]s；已根据 %[3]s 重新登记 go%[2]s",
+	"%s; cleared the marker, run `govm use <version>` to activate one":                           "%s；已清空标记，执行 `govm use <版本>` 激活一个版本",
+
+	// 缓存
+	"Cache is empty.":         "缓存为空。",
+	"Cache is already empty.": "缓存已经是空的。",
+	"Would remove %s/%s\n":    "将删除 %s/%s\n",
+	"Would free %s\n":         "将释放 %s\n",
+
+	// 初始化、备份与迁移
+	"Setting up govm (config: %s)\n":     "正在配置 govm（配置文件：%s）\n",
+	"Detected shell: %s\n":               "检测到的 shell：%s\n",
+	"Download source (auto, global, cn)": "下载源（auto、global、cn）",
+	"Install root":                       "安装根目录",
+	"Install and activate go%s now?":     "现在安装并激活 go%s 吗？",
+	"Wrote %s\n":                         "已写入 %s\n",
+	"Run `govm install %s && govm use %s` when ready; `govm use` writes the shell rc block.\n":                                                              "准备好后执行 `govm install %s && govm use %s`；`govm use` 会写入 shell 配置块。\n",
+	"Settings changed, run `govm install %s && govm use %s` to apply them.\n":                                                                               "配置已更改，执行 `govm install %s && govm use %s` 使其生效。\n",
+	"comma-separated architectures to include":                                                                                                              "要包含的架构，以逗号分隔",
+	"Bundled %d archive(s) and the release list into %s\n":                                                                                                  "已将 %d 个归档和版本列表打包到 %s\n",
+	"Added %d archive(s) from %s to the download cache\n":                                                                                                   "已将 %[2]s 中的 %[1]d 个归档加入下载缓存\n",
+	"Using the release list from %s as the cached catalog\n":                                                                                                "已将 %s 的版本列表作为缓存的版本目录\n",
+	"Saved the release list from %s to %s; point catalogURL at it with catalog set to \"static\" to use it offline\n":                                       "已将 %s 的版本列表保存到 %s；将 catalog 设为 \"static\" 并让 catalogURL 指向该文件即可离线使用\n",
+	"Carry Go archives and the release list to an offline machine":                                                                                          "把 Go 归档和版本列表带到离线机器",
+	"create downloads the archives and a snapshot of the release list; apply verifies them and fills the download cache so later installs need no network.": "create 下载归档与版本列表快照；apply 校验后填充下载缓存，之后的安装无需联网。",
+	"absolute versions directory on the remote host (default $HOME/.govm/versions)":                                                                         "远程主机上的版本目录，必须为绝对路径（默认 $HOME/.govm/versions）",
+	"install only, without writing the govm block to the remote shell config":                                                                               "只安装，不向远程 shell 配置写入 govm 配置块",
+	"extra arguments for ssh, e.g. \"-p 2222 -i ~/.ssh/build\"":                                                                                             "传给 ssh 的附加参数，例如 \"-p 2222 -i ~/.ssh/build\"",
+	"Failed on %s: %v\n":                                    "%s 上失败：%v\n",
+	"Installed %[1]s on %[2]s at %[3]s\n":                   "已在 %[2]s 上安装 %[1]s，位于 %[3]s\n",
+	"Installed %[1]s on %[2]s at %[3]s and updated %[4]s\n": "已在 %[2]s 上安装 %[1]s，位于 %[3]s，并更新了 %[4]s\n",
+	"Install a Go version on remote Linux hosts over SSH":   "通过 SSH 在远程 Linux 主机上安装 Go 版本",
+	"The archive comes from the local download cache (downloaded once if missing) and is streamed over ssh; the remote host needs only sh and tar.": "归档取自本机下载缓存（缺失时下载一次）并通过 ssh 传输，远程主机只需要 sh 和 tar。",
+	"go%[1]s is older than the %[2]s %[3]s directive in %[4]s; %[5]s":                                                                               "go%[1]s 低于 %[4]s 中 %[2]s %[3]s 指令的要求；%[5]s",
+	"run `govm use %s`":     "请执行 `govm use %s`",
+	"run `govm install %s`": "请执行 `govm install %s`",
+	"refuse versions older than the go/toolchain directive of the current module":                                                "拒绝切换到低于当前模块 go/toolchain 指令要求的版本",
+	"exit non-zero when the active version is older than the current module requires":                                            "当前版本低于当前模块的要求时以非零状态退出",
+	"Inside a module or workspace, warns when the active version is older than the go/toolchain directive of go.mod or go.work.": "在模块或工作区内，当前版本低于 go.mod 或 go.work 中 go/toolchain 指令的要求时输出警告。",
+	"install the version without activating it":                                                                                  "只安装，不激活该版本",
+	"%[1]s selects go%[2]s (%[3]s directive)\n":                                                                                  "%[1]s 选择 go%[2]s（%[3]s 指令）\n",
+	"Already using go%s\n": "已在使用 go%s\n",
+	"Install and activate the toolchain selected by go.mod or go.work":                                                                                     "安装并激活 go.mod 或 go.work 选择的工具链",
+	"Uses the toolchain directive, or the go directive when there is none, of the current module or workspace; the version is installed first if missing.": "使用当前模块或工作区的 toolchain 指令（没有时使用 go 指令），版本未安装时先安装。",
+	"go%s stays in the module cache and is linked, not copied; `go clean -modcache` would delete it\n":                                                     "go%s 仍位于模块缓存中，只登记而未复制；执行 `go clean -modcache` 会删除它\n",
+	"Without arguments it also finds toolchains the go command downloaded into GOMODCACHE and links them in place instead of copying them.":                "不带参数时还会发现 go 命令下载到 GOMODCACHE 中的工具链，直接登记其所在目录而不复制。",
+	"Pinned go%[1]s in %[2]s\n":                     "已在 %[2]s 中固定 go%[1]s\n",
+	"Updated %s; run `direnv allow` to reload it\n": "已更新 %s，执行 `direnv allow` 重新加载\n",
+	"Wrote %s; run `direnv allow` to load it\n":     "已写入 %s，执行 `direnv allow` 加载\n",
+	"A version range such as 1.22.x selects the newest matching installed version. Inside a module or workspace, warns when the version is older than the go/toolchain directive of go.mod or go.work. With --local the version is pinned in .govm-version and a govm block in .envrc is updated to match. --verbose prints a colored diff of the change to .bashrc/.zshrc before writing it; --confirm also asks for confirmation, which -yes answers.": "1.22.x 这样的版本范围选择满足条件的最新已安装版本。在模块或工作区内，版本低于 go.mod 或 go.work 中 go/toolchain 指令的要求时输出警告。使用 --local 时把版本固定到 .govm-version，并同步更新 .envrc 中的 govm 配置块。--verbose 在改写 .bashrc/.zshrc 前输出带颜色的差异；--confirm 还会请求确认，-yes 自动确认。",
+	"pin the version in .govm-version in the current directory instead of switching globally":                                                                                              "把版本固定到当前目录的 .govm-version，而不是切换全局版本",
+	"Print an .envrc snippet that selects the pinned version for direnv":                                                                                                                   "输出供 direnv 使用、选择固定版本的 .envrc 片段",
+	"Without a version it uses the nearest .govm-version; `govm use --local` keeps a written snippet up to date.":                                                                          "未指定版本时使用最近的 .govm-version；`govm use --local` 会保持已写入的片段同步。",
+	"merge the snippet into .envrc next to .govm-version (or in the current directory)":                                                                                                    "把片段合并进 .govm-version 所在目录（或当前目录）的 .envrc",
+	"Run a command with GOROOT, PATH and GOVM_VERSION set for an installed version":                                                                                                        "以已安装版本的 GOROOT、PATH 与 GOVM_VERSION 执行命令",
+	"The resolved GOROOT is cached per version; with goShim enabled in the config, `GOVM_VERSION=<version> go ...` selects the same version through the go shim, which shares this cache.": "解析出的 GOROOT 按版本缓存；配置中启用 goShim 后，`GOVM_VERSION=<版本> go ...` 经由 go shim 选择同一版本并共用该缓存。",
+	"Time a command under several installed versions and compare the results":                                                                                                              "在多个已安装版本下计时执行命令并比较结果",
+	"Each version runs the command in the same environment as `govm run`; the delta compares mean wall-clock time with the first version. Command output is shown only when a run fails.":  "每个版本都在与 `govm run` 相同的环境中执行命令；DELTA 为平均耗时相对第一个版本的变化。命令的输出只在执行失败时显示。",
+	"timed runs per version": "每个版本计时执行的次数",
+	"untimed runs per version before timing, e.g. to fill the build cache": "计时前每个版本不计时执行的次数，例如用于填充构建缓存",
+	"Benchmarking go%[1]s: %[2]d warmup and %[3]d timed run(s)\n":          "正在测量 go%[1]s：预热 %[2]d 次，计时 %[3]d 次\n",
+	"Show files or release notes that changed between two versions":        "显示两个版本之间变化的文件或发布说明",
+	"--notes lists the releases on the upgrade path: every release of the target's series newer than <from>, plus the first release of each series in between. Notes come from go.dev and are cached for offline use.": "--notes 列出升级路径上的发布：目标系列中晚于 <from> 的每个版本，以及途经各系列的首个发布。发布说明来自 go.dev，并缓存以便离线查看。",
+	"summarize the release notes between the versions instead of comparing files; neither version needs to be installed":                                                                                               "汇总两个版本之间的发布说明而不是比较文件，两个版本都无需安装",
+	"with --notes, use only the cached release notes":                   "与 --notes 一起使用时只读取缓存的发布说明",
+	"go%[1]s -> go%[2]s: %[3]d release(s), %[4]d with security fixes\n": "go%[1]s -> go%[2]s：%[3]d 个发布，其中 %[4]d 个包含安全修复\n",
+	"security fixes":            "含安全修复",
+	"\ngo%[1]s (%[2]s)\n":       "\ngo%[1]s（%[2]s）\n",
+	"go%[1]s satisfies %[2]s\n": "go%[1]s 满足 %[2]s\n",
+	"Exit non-zero unless the active version satisfies a constraint": "当前版本不满足约束表达式时以非零状态退出",
+	"Conditions separated by spaces or commas must all hold; alternatives are separated by ||. Supports >=, >, <=, <, = and != comparisons, series wildcards such as 1.22.x or 1.22, ~1.22.3 for patch releases from 1.22.3, and exact versions. Prereleases match only when a condition names one of the same release, e.g. >=1.23rc1. Intended as a guard step in CI pipelines and git hooks.": "以空格或逗号分隔的条件需全部满足，多组备选以 || 分隔。支持 >=、>、<=、<、= 与 != 比较，1.22.x 或 1.22 这样的系列通配符，表示 1.22.3 起补丁版本的 ~1.22.3，以及精确版本。只有条件写明同一版本的预发布号时才匹配预发布版本，例如 >=1.23rc1。用于 CI 流水线与 git hook 中的检查步骤。",
+	"print nothing when the active version matches":                                                             "当前版本满足约束时不输出任何内容",
+	"No installed versions in channel %s.\n":                                                                    "%s 渠道中没有已安装的版本。\n",
+	"show only versions of these comma-separated channels in -list/-remote (stable, rc, beta, archived)":        "-list/-remote 只列出这些渠道的版本（逗号分隔：stable、rc、beta、archived）",
+	"show only these comma-separated channels: stable, rc, beta, archived":                                      "只列出这些渠道的版本（逗号分隔）：stable、rc、beta、archived",
+	"metadata.json was modified outside govm; review it, then run `govm doctor --repair` to accept the changes": "metadata.json 在 govm 之外被修改；请检查该文件，确认后执行 `govm doctor --repair` 接受修改",
+	"metadata.json was modified outside govm; accepted the current contents and signed them again":              "metadata.json 在 govm 之外被修改；已接受当前内容并重新签名",
+	"metadata.json is corrupt (%[1]v); showing %[2]d salvaged entries read-only":                                "metadata.json 已损坏（%[1]v）；以只读方式显示抢救出的 %[2]d 个条目",
+	", original saved to %s": "，原文件已保存到 %s",
+	"%[1]s; rebuilt metadata.json with %[2]d entries, reinstall or `govm adopt` any missing versions":         "%[1]s；已用 %[2]d 个条目重建 metadata.json，缺失的版本请重新安装或执行 `govm adopt` 登记",
+	"show a diff of the shell config change":                                                                  "输出 shell 配置改动的差异",
+	"show a diff of the shell config change and ask before writing it":                                        "输出 shell 配置改动的差异，写入前请求确认",
+	"Apply this change to %s?":                                                                                "将此改动写入 %s？",
+	"Merged %[1]d duplicate govm blocks in %[2]s\n":                                                           "已合并 %[2]s 中 %[1]d 个重复的 govm 配置块\n",
+	"Replaced %[1]d govm blocks with outdated markers in %[2]s\n":                                             "已替换 %[2]s 中 %[1]d 个使用旧版标记的 govm 配置块\n",
+	"Removed stray %[1]s from %[2]s\n":                                                                        "已从 %[2]s 删除多余的 %[1]s\n",
+	"%[1]s=%[2]s is set in the current environment and overrides the govm block until the shell is restarted": "当前环境中设置了 %[1]s=%[2]s，重启 shell 之前会覆盖 govm 配置块",
+	"%[1]s sets %[2]s=%[3]s outside the govm block":                                                           "%[1]s 在 govm 配置块之外设置了 %[2]s=%[3]s",
+	"Comment out the conflicting lines in %s?":                                                                "是否注释掉 %s 中冲突的行？",
+	"Updated %[1]s; reload the VS Code window to use go%[2]s\n":                                               "已更新 %[1]s；重新加载 VS Code 窗口后即使用 go%[2]s\n",
+	"Updated %[1]s; reopen the project in GoLand to use go%[2]s\n":                                            "已更新 %[1]s；在 GoLand 中重新打开项目后即使用 go%[2]s\n",
+	"Wrote %[1]s; applications started from the desktop use go%[2]s after the next login\n":                   "已写入 %[1]s；下次登录后从桌面启动的程序将使用 go%[2]s\n",
+	"Updated the systemd user session; applications started from now on use go%s\n":                           "已更新 systemd 用户会话；此后启动的程序将使用 go%s\n",
+	"Point editors and the desktop session at the active or given version":                                    "让编辑器与桌面会话使用当前版本或指定版本",
+	"Editors started from the desktop do not read .bashrc/.zshrc. vscode sets go.goroot and go.alternateTools in the user settings (the go shim when goShim is enabled); goland sets the GOROOT of the project in the current directory; session writes ~/.config/environment.d/60-govm.conf for systemd sessions. Run it again after switching versions.": "从桌面启动的编辑器不会读取 .bashrc/.zshrc。vscode 在用户设置中写入 go.goroot 与 go.alternateTools（启用 goShim 时指向 go shim）；goland 设置当前目录中项目的 GOROOT；session 为 systemd 会话写入 ~/.config/environment.d/60-govm.conf。切换版本后需重新执行。",
+	"vscode: write .vscode/settings.json in the current directory instead of the user settings": "vscode：写入当前目录的 .vscode/settings.json，而不是用户设置",
+	"session: also update the running systemd user session":                                     "session：同时更新正在运行的 systemd 用户会话",
+	"(none)":        "（无）",
+	"not installed": "未安装",
+	"Print the active GOROOT, installed versions and shim paths for editor plugins": "输出供编辑器插件使用的当前 GOROOT、已安装版本与 shim 路径",
+	"The JSON document carries a schema number that only changes when existing fields are removed or change meaning. watch lists the files whose changes can change the document; they are replaced atomically, so watch their directories. --watch prints the document as one JSON line at start and again whenever it changes.": "JSON 文档带有 schema 版本号，只有删除已有字段或改变其含义时才会递增。watch 列出内容变化时文档可能随之变化的文件；这些文件通过重命名原子替换，应监听其所在目录。--watch 启动时以一行 JSON 输出文档，之后每次变化再输出一行。",
+	"print the document as JSON": "以 JSON 输出文档",
+	"keep running and print the document as one JSON line every time it changes": "持续运行，每当文档变化时以一行 JSON 输出",
+	"how often --watch checks for changes":                                       "--watch 检查变化的间隔",
+	"Print the active version compactly for shell prompts and tmux status lines": "为 shell 提示符与 tmux 状态栏输出简短的当前版本",
+	"Reads only the current version marker; its location is cached and the config is loaded again only when GOVM_HOME, GOVM_CONFIG and similar variables or the config file change. Prints nothing and exits 0 when no version is active or the state cannot be read. GOVM_VERSION takes precedence, as it does for the go shim.": "只读取当前版本标记；标记的位置会被缓存，只有 GOVM_HOME、GOVM_CONFIG 等变量或配置文件变化时才重新加载配置。没有激活版本或状态不可读时不输出任何内容并以 0 退出。与 go shim 一样，GOVM_VERSION 优先。",
+	"print only the version number, without the go prefix":                                                "只输出版本号，不带 go 前缀",
+	"after the command, print how long region detection, the release list, downloads and extraction took": "命令结束后输出地区探测、获取版本列表、下载与解压各自的耗时",
+	"%s no network or install step ran\n":                                                                 "%s 没有执行网络或安装步骤\n",
+	"region detection":                                                                                    "地区探测",
+	"release list":                                                                                        "版本列表",
+	"download":                                                                                            "下载",
+	"extract":                                                                                             "解压",
+	"download + extract":                                                                                  "下载并解压",
+	"Share identical files between installed versions to reclaim disk space": "让已安装版本之间内容相同的文件共享数据以回收磁盘空间",
+	"Patch releases of a series share most of their files. Files whose content, size and permissions match the install manifests are replaced by reflinks on file systems that support them (btrfs, xfs) or by hard links otherwise; each file is compared byte by byte first. A hard-linked file is the same file in every version that shares it, so editing it in one GOROOT changes the others. External versions and versions installed without a manifest are left alone.": "同一系列的补丁版本绝大部分文件相同。内容、大小与权限都与安装清单一致的文件，在支持的文件系统（btrfs、xfs）上替换为 reflink，否则替换为硬链接；替换前逐字节比较。硬链接的文件在共享它的各版本中是同一个文件，在一个 GOROOT 中修改会影响其他版本。external 版本与没有清单的版本不受影响。",
+	"only report how much space would be reclaimed, based on the manifests":                                    "只根据清单报告可回收的空间",
+	"auto uses reflinks when supported and hard links otherwise; reflink fails instead of falling back":        "auto 在支持时使用 reflink，否则使用硬链接；reflink 不支持时直接失败而不回退",
+	"go%s: %s no file manifest recorded, reinstall to include it\n":                                            "go%s: %s 没有记录文件清单，重新安装后才能参与去重\n",
+	"No duplicate files to share across %d versions\n":                                                         "%d 个版本之间没有可共享的重复文件\n",
+	"%[1]d duplicate files across %[2]d versions; dedup would reclaim %[3]s\n":                                 "%[2]d 个版本之间有 %[1]d 个重复文件，去重可回收 %[3]s\n",
+	"Reclaimed %[1]s by sharing %[2]d files across %[3]d versions (%[4]d reflinks, %[5]d hard links)\n":        "在 %[3]d 个版本之间共享 %[2]d 个文件，回收了 %[1]s（reflink %[4]d 个，硬链接 %[5]d 个）\n",
+	"%d files were already shared\n":                                                                           "%d 个文件此前已经共享\n",
+	"%s hard-linked files are the same file in every version; editing one in a GOROOT changes it everywhere\n": "%s 硬链接的文件在各版本中是同一个文件，在一个 GOROOT 中修改会影响所有版本\n",
+	"govm version %s (packaged)\n":                                                                             "govm 版本 %s（发行版打包）\n",
+	"govm version %s\n":                                                                                        "govm 版本 %s\n",
+	"%s no problems found\n":                                                                                   "%s 未发现问题\n",
+	"No plugins found; put an executable named %s<name> on PATH to add `govm <name>`.\n":                       "未找到插件；把名为 %s<name> 的可执行文件放入 PATH 即可添加 `govm <name>`。\n",
+	"go%s: %s no file manifest recorded, reinstall to enable verification\n":                                   "go%s: %s 没有记录文件清单，重新安装后才能校验\n",
+	"go%s: %s (%d files)\n":                                                                                    "go%s: %s（%d 个文件）\n",
+	"go%s: %s %d modified, %d missing (%d files checked)\n":                                                    "go%s: %s %d 个被修改，%d 个缺失（共检查 %d 个文件）\n",
+	"  modified: %s\n": "  已修改：%s\n",
+	"  missing:  %s\n": "  缺失：  %s\n",
+	"go%s -> go%s: %d added, %d removed, %d changed\n":                                          "go%s -> go%s：新增 %d，删除 %d，修改 %d\n",
+	"%s %v, downloading %s directly\n":                                                          "%s %v，直接下载 %s\n",
+	"%s %v; only bash and zsh rc files are managed\n":                                           "%s %v；只管理 bash 与 zsh 的 rc 文件\n",
+	"%s cannot look up the latest release: %v\n":                                                "%s 无法查询最新版本：%v\n",
+	"%s go%s is recorded but not installed at %s, run `govm install %s`\n":                      "%s go%s 已记录但未安装在 %s，请运行 `govm install %s`\n",
+	"Backed up govm state to %s\n":                                                              "已将 govm 状态备份到 %s\n",
+	"Restored %d file(s) from %s\n":                                                             "已从 %[2]s 恢复 %[1]d 个文件\n",
+	"Exported %d versions to %s\n":                                                              "已导出 %d 个版本到 %s\n",
+	"Imported %d versions (%d already installed)\n":                                             "已导入 %d 个版本（%d 个已安装）\n",
+	"Moved %d item(s) to the XDG layout.\n":                                                     "已将 %d 项移动到 XDG 目录布局。\n",
+	"%d item(s) would be moved, re-run without --dry-run to apply\n":                            "将移动 %d 项，去掉 --dry-run 重新执行以应用\n",
+	"Run `govm use <version>` to point GOROOT in your shell configuration at the new location.": "执行 `govm use <版本>`，让 shell 配置中的 GOROOT 指向新位置。",
+	"Rehashed %d shim(s) for %d version(s) in %s\n":                                             "已在 %[3]s 中为 %[2]d 个版本重建 %[1]d 个 shim\n",
+
+	// 登记与清理
+	"Adopted go%s at %s as an external version (uninstall only unregisters it)\n":                                      "已将 %[2]s 中的 go%[1]s 登记为外部版本（卸载时只取消登记）\n",
+	"No existing Go installations found outside govm.":                                                                 "没有发现 govm 之外的 Go 安装。",
+	"Found existing Go installation(s): %s. Run `govm adopt` to manage them with govm.\n":                              "发现已有的 Go 安装：%s。执行 `govm adopt` 交给 govm 管理。\n",
+	"This removes %s. Continue?":                                                                                       "这将删除 %s。是否继续？",
+	"Removed govm block from %s\n":                                                                                     "已从 %s 中移除 govm 配置块\n",
+	"Run `govm use <version>` to activate one.":                                                                        "执行 `govm use <版本>` 激活一个版本。",
+	"govm has been removed; delete the govm binary itself and open a new shell to finish.":                             "govm 已被移除；删除 govm 可执行文件并打开新的 shell 即可完成。",
+	"govm data has been removed; uninstall the govm package with your package manager and open a new shell to finish.": "govm 数据已被移除；使用包管理器卸载 govm 软件包并打开新的 shell 即可完成。",
+	"govm API listening on http://%s\n":                                                                                "govm API 正在监听 http://%s\n",
+
+	// 帮助
+	"govm - Go version manager":              "govm - Go 版本管理器",
+	"Usage:":                                 "用法：",
+	"  govm [global flags] <command> [args]": "  govm [全局 flag] <命令> [参数]",
+	"Commands:":                              "命令：",
+	"Global flags:":                          "全局 flag：",
+	"Flags:":                                 "flag：",
+	"Examples:":                              "示例：",
+	"Run `govm help <command>` for usage, flags and examples of a command.":     "执行 `govm help <命令>` 查看命令的用法、flag 与示例。",
+	"answer yes to every confirmation prompt (for scripts and CI)":              "对所有确认提示回答 yes（用于脚本与 CI）",
+	"exit N instead of 0 when install/use changed state; warnings go to stdout": "install/use 实际改变状态时以 N 而不是 0 退出，警告改为写入 stdout",
+	"print stable key=value progress events":                                    "输出稳定的 key=value 进度事件",
+	"add channel, arch, size and install date columns to version tables":        "在版本表格中增加渠道、架构、大小与安装时间列",
+	"print each version of -list/-remote/current with a Go text/template":       "用 Go text/template 输出 -list/-remote/current 的每个版本",
+	"TCP connect and TLS handshake timeout":                                     "TCP 连接与 TLS 握手超时",
+	"total timeout for metadata requests":                                       "元数据请求的总超时",
+	"total timeout for downloading an archive":                                  "下载归档的总超时",
+	"show this message": "显示本帮助",
+	"show govm version": "显示 govm 版本",
+	"Guided setup: download source, GOPATH, install root, latest stable Go": "引导式配置：下载源、GOPATH、安装根目录与最新稳定版 Go",
+	"accept every default without prompting":                                "不提问，全部使用默认值",
+	"List installed versions":                                               "列出已安装版本",
+	"add channel, arch, size and install date columns":                      "增加渠道、架构、大小与安装时间列",
+	"print each version with a Go text/template":                            "用 Go text/template 输出每个版本",
+	"List remote versions grouped by major.minor series":                    "按 major.minor 系列列出远程版本",
+	"list every remote archive instead of grouping by series":               "逐个列出远程归档，不按系列分组",
+	"show at most N remote versions per page (0 shows all)":                 "每页最多显示 N 个远程版本（0 表示全部）",
+	"page number used with -limit":                                          "与 -limit 一起使用的页码",
+	"Install one or more versions":                                          "安装一个或多个版本",
+	"Several versions are downloaded concurrently and summarized in a table. A version range such as 1.22.x or \">=1.21 <1.23\" installs the newest matching stable release. An archive name or URL installs that exact archive; its checksum comes from --sha256 or the release list.": "多个版本并发下载，完成后以表格汇总。1.22.x 或 \">=1.21 <1.23\" 这样的版本范围安装满足条件的最新正式版。指定归档文件名或 URL 时安装该归档，校验值来自 --sha256 或发布列表。",
+	"print only the GOROOT path": "只输出 GOROOT 路径",
+	"activate without editing rc files and export to GITHUB_PATH/GITHUB_ENV":         "不修改 rc 文件直接激活，并写入 GITHUB_PATH/GITHUB_ENV",
+	"derive the download URL and fetch the .sha256 file without the version catalog": "不使用版本列表，直接推导下载地址并获取 .sha256 文件",
+	"number of concurrent downloads when installing several versions":                "安装多个版本时的并发下载数",
+	"install every version listed in a file (one per line, YAML or JSON manifest)":   "安装文件中列出的所有版本（每行一个，或 YAML/JSON 清单）",
+	"skip versions that are already installed instead of failing":                    "跳过已安装的版本而不是报错",
+	"stop starting new installs after the first failure":                             "第一个失败后不再开始新的安装",
+	"expected SHA256 when installing from an archive name or URL":                    "从归档文件名或 URL 安装时期望的 SHA256",
+	"Switch to an installed version, or back to the previous one with -":             "切换到已安装的版本，- 表示切回上一个版本",
+	"Show the active version":                                   "显示当前激活的版本",
+	"print only the version number; exit 1 when none is active": "只输出版本号；没有激活版本时以 1 退出",
+	"shorthand for --quiet":                                     "--quiet 的简写",
+	"print only the GOROOT of the active version":               "只输出当前版本的 GOROOT",
+	"print the active version with a Go text/template":          "用 Go text/template 输出当前版本",
+	"Summarize active/default version, installs, disk usage, mirror, cache, Go build caches and updates": "汇总当前与默认版本、安装数量、磁盘占用、镜像、缓存、Go 构建缓存与可用更新",
+	"print the summary as JSON": "以 JSON 输出汇总",
+	"Run go clean -cache/-modcache with the active go and report the freed space":                                               "用当前的 go 执行 go clean -cache/-modcache 并报告释放的空间",
+	"run go clean -cache to empty the build cache (GOCACHE)":                                                                    "执行 go clean -cache 清空构建缓存（GOCACHE）",
+	"run go clean -modcache to empty the module cache (GOMODCACHE)":                                                             "执行 go clean -modcache 清空模块缓存（GOMODCACHE）",
+	"Print the path of a tool (default go) in the active version":                                                               "输出当前版本中某个工具（默认 go）的路径",
+	"exit 1 without a message when no version is active":                                                                        "没有激活版本时不输出提示，以 1 退出",
+	"Print export lines for the active version, or bare values of GOROOT/GOPATH/GOVERSION":                                      "输出当前版本的 export 语句，或 GOROOT/GOPATH/GOVERSION 的取值",
+	"print only bare values (GOROOT when no name is given); exit 1 when none is active":                                         "只输出取值（未指定变量名时输出 GOROOT）；没有激活版本时以 1 退出",
+	"Remove an installed version, asking first if it is active":                                                                 "卸载已安装的版本，卸载当前版本前会先询问",
+	"remove the version even if it is active or locked, without asking":                                                         "即使版本正在使用或已锁定也直接卸载，不再询问",
+	"confirm removing the active version without prompting":                                                                     "卸载当前版本时不再询问",
+	"also remove GOTOOLCHAIN module downloads of this version from GOMODCACHE":                                                  "同时删除 GOMODCACHE 中该版本的 GOTOOLCHAIN 模块",
+	"Protect versions from uninstall unless --force is given":                                                                   "保护版本，未加 --force 时不能卸载",
+	"Export installed versions and default":                                                                                     "导出已安装版本与默认版本",
+	"manifest format: json or yaml":                                                                                             "清单格式：json 或 yaml",
+	"Install missing versions from a manifest and apply default":                                                                "按清单安装缺失的版本并设置默认版本",
+	"Also serves Prometheus metrics (installs, failures, downloaded bytes, active version) at /metrics.":                        "同时在 /metrics 提供 Prometheus 指标（安装次数、失败次数、下载字节数与当前版本）。",
+	"Run the local REST API daemon":                                                                                             "运行本地 REST API 守护进程",
+	"listen address":                                                                                                            "监听地址",
+	"Show local per-version usage (requires \"usageStats\": true)":                                                              "显示本地各版本的使用情况（需要 \"usageStats\": true）",
+	"Re-hash installed files against the install manifest":                                                                      "按安装清单重新校验已安装的文件",
+	"verify every installed version":                                                                                            "校验所有已安装版本",
+	"Print the current version, then a line every time it changes":                                                              "输出当前版本，之后每次变化输出一行",
+	"Prints an empty line when no version is active. Runs until interrupted; intended for tmux status bars and editor plugins.": "没有激活版本时输出空行。持续运行直到被中断，适用于 tmux 状态栏与编辑器插件。",
+	"how often to check the current version marker":                                                                             "检查当前版本标记的间隔",
+	"print each change as a JSON object with previous and current":                                                              "以包含 previous 与 current 的 JSON 对象输出每次变化",
+	"run this shell command on every change, with GOVM_PREVIOUS and GOVM_CURRENT set":                                           "每次变化时执行该 shell 命令，并设置 GOVM_PREVIOUS 与 GOVM_CURRENT",
+	"Check installs for drift and user-added files in GOROOT":                                                                   "检查安装是否被改动以及 GOROOT 中用户添加的文件",
+	"Also reports a metadata.json that cannot be parsed (govm then lists the salvaged entries read-only), a current version marker that names a version missing from metadata or without a go binary, a signed metadata.json edited outside govm (signMetadata), and a GOTOOLCHAIN setting that lets the go command download toolchains behind govm's back.": "同时报告无法解析的 metadata.json（此时 govm 以只读方式列出抢救出的条目）、指向元数据中不存在或缺少 go 可执行文件的版本的当前版本标记、在 govm 之外被修改的已签名 metadata.json（signMetadata），以及允许 go 命令绕过 govm 自行下载工具链的 GOTOOLCHAIN 设置。",
+	"rebuild a corrupt metadata.json from the salvaged entries, re-register the marked version from its GOROOT or clear the marker when it cannot be recovered, and accept outside edits to a signed metadata.json":                                                                                                                                          "用抢救出的条目重建损坏的 metadata.json，根据 GOROOT 重新登记标记中的版本（无法恢复时清空标记），并接受对已签名 metadata.json 的外部修改",
+	"print only the summary line":                                                   "只输出汇总行",
+	"Snapshot metadata, manifests, config and current marker":                       "备份元数据、清单、配置与当前版本标记",
+	"include archives kept in downloads/":                                           "包含 downloads/ 中保留的归档",
+	"Restore govm state from a backup snapshot":                                     "从备份恢复 govm 状态",
+	"Register existing Go installs (PATH, /usr/local/go, ...) as external versions": "把已有的 Go 安装（PATH、/usr/local/go 等）登记为外部版本",
+	"Regenerate go<version>/gofmt<version> shims for every installed version":       "为所有已安装版本重建 go<版本>/gofmt<版本> shim",
+	"Move ~/.govm into XDG data, cache and config directories":                      "把 ~/.govm 迁移到 XDG 数据、缓存与配置目录",
+	"print the planned moves without changing anything":                             "只输出计划的移动，不做任何修改",
+	"Remove govm data, rc blocks and (optionally) installed versions":               "删除 govm 数据、rc 配置块以及（可选）已安装的版本",
+	"keep installed Go versions on disk":                                            "保留已安装的 Go 版本",
+	"do not ask for confirmation":                                                   "不再确认",
+	"Show when the active version changed and which command changed it":             "显示当前版本何时由哪个命令改变",
+	"show the last N entries (0 shows all)":                                         "显示最近 N 条（0 表示全部）",
+	"print entries as JSON":                                                         "以 JSON 输出记录",
+	"Show or reset downloads, catalog and region caches":                            "查看或清理下载、版本列表与测速缓存",
+	"print what would be removed (cache clear)":                                     "只输出将被删除的内容（cache clear）",
+	"List govm-<name> executables on PATH that extend govm":                         "列出 PATH 中扩展 govm 的 govm-<name> 可执行文件",
+	"Unknown commands run the govm-<name> plugin from PATH with GOVM_PLUGIN_ROOT, GOVM_CONFIG, GOVM_SELF_VERSION, GOVM_BIN, GOVM_ACTIVE_VERSION and GOVM_ACTIVE_GOROOT exported.": "未知命令会执行 PATH 中的 govm-<name> 插件，并导出 GOVM_PLUGIN_ROOT、GOVM_CONFIG、GOVM_SELF_VERSION、GOVM_BIN、GOVM_ACTIVE_VERSION 与 GOVM_ACTIVE_GOROOT。",
+	"Show help for govm or for one command":                                                "显示 govm 或某个命令的帮助",
+	"Generate man pages or Markdown docs from the command metadata":                        "根据命令元数据生成 man 手册页或 Markdown 文档",
+	"write one file per command into this directory instead of printing a single document": "为每个命令在该目录中写入一个文件，而不是输出单个文档",
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"runtime"
 
+	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
 
@@ -17,17 +18,28 @@ var supportedArch = map[string]struct{}{
 
 // Checker 校验当前系统是否满足 govm 的运行要求。
 type Checker struct {
-	cfg    models.Config
-	goos   func() string
-	goarch func() string
+	cfg     models.Config
+	goos    func() string
+	goarch  func() string
+	geteuid func() int
+	getenv  func(string) string
+	// 以下字段用于探测挂载选项与安全模块，测试中可替换。
+	readFile func(string) ([]byte, error)
+	label    func(string) string
+	run      func(string) error
 }
 
 // NewChecker 创建平台检测器。
 func NewChecker(cfg models.Config) *Checker {
 	return &Checker{
-		cfg:    cfg,
-		goos:   func() string { return runtime.GOOS },
-		goarch: func() string { return runtime.GOARCH },
+		cfg:      cfg,
+		goos:     func() string { return runtime.GOOS },
+		goarch:   func() string { return runtime.GOARCH },
+		geteuid:  os.Geteuid,
+		getenv:   os.Getenv,
+		readFile: os.ReadFile,
+		label:    selinuxLabel,
+		run:      runProbe,
 	}
 }
 
@@ -40,19 +52,74 @@ func (c *Checker) Validate() error {
 		return fmt.Errorf("platform: unsupported architecture %s", c.goarch())
 	}
 
-	root := c.resolveRoot()
+	root, err := c.resolveRoot()
+	if err != nil {
+		return err
+	}
+	if c.cfg.SystemMode {
+		// 共享根目录由管理员创建，普通用户只需可读即可查询与切换版本。
+		if info, err := os.Stat(root); err == nil && !info.IsDir() {
+			return fmt.Errorf("platform: shared root %s is not a directory", root)
+		}
+		return nil
+	}
 	if err := os.MkdirAll(root, 0o755); err != nil {
-		return fmt.Errorf("platform: cannot access install directory %s: %w", root, err)
+		return c.diagnose(nearestExisting(root), "write", err)
 	}
 	return nil
 }
 
-func (c *Checker) resolveRoot() string {
-	if c.cfg.RootDir != "" {
-		return c.cfg.RootDir
+// CheckWritable 通过创建探测文件校验安装根目录可写，并确认其中的文件可以执行；
+// 共享安装模式下给出 sudo 提示，其余失败附带挂载选项与 SELinux/AppArmor 相关的修复建议。
+func (c *Checker) CheckWritable() error {
+	root, err := c.resolveRoot()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(root, 0o755)
+	if err == nil {
+		var probe *os.File
+		probe, err = os.CreateTemp(root, ".govm-write-*")
+		if err == nil {
+			probe.Close()
+			os.Remove(probe.Name())
+			if err := c.probeExec(root); err != nil {
+				return c.diagnose(root, "execute", err)
+			}
+			return nil
+		}
+	}
+	if c.cfg.SystemMode && c.geteuid() != 0 {
+		return fmt.Errorf("platform: shared root %s is not writable, re-run with sudo: %w", root, err)
+	}
+	return c.diagnose(nearestExisting(root), "write", err)
+}
+
+// nearestExisting 返回 path 自身或其最近的已存在祖先目录，用于在目录尚未创建时定位挂载点与标签。
+func nearestExisting(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// SudoUser 返回通过 sudo 提权前的用户名，未使用 sudo 时返回空串。
+func (c *Checker) SudoUser() string {
+	if c.geteuid() != 0 {
+		return ""
 	}
-	if home, err := os.UserHomeDir(); err == nil {
-		return filepath.Join(home, ".govm")
+	return c.getenv("SUDO_USER")
+}
+
+func (c *Checker) resolveRoot() (string, error) {
+	if c.cfg.RootDir != "" {
+		return c.cfg.RootDir, nil
 	}
-	return filepath.Join(os.TempDir(), "govm")
+	return storage.ResolveRootDir()
 }
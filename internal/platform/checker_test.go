@@ -1,8 +1,12 @@
 package platform
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/liangyou/govm/pkg/models"
@@ -63,3 +67,92 @@ func TestCheckerPermissionError(t *testing.T) {
 		t.Fatal("expected error due to invalid directory")
 	}
 }
+
+func TestCheckerSystemModeWritableAndSudo(t *testing.T) {
+	t.Parallel()
+
+	temp := t.TempDir()
+	blocker := filepath.Join(temp, "file")
+	if err := os.WriteFile(blocker, []byte("content"), 0o644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	checker := NewChecker(models.Config{RootDir: filepath.Join(blocker, "govm"), SystemMode: true})
+	checker.goos = func() string { return "linux" }
+	checker.goarch = func() string { return "amd64" }
+	checker.geteuid = func() int { return 1000 }
+	checker.getenv = func(string) string { return "alice" }
+
+	if err := checker.Validate(); err != nil {
+		t.Fatalf("system mode validate should not require write access: %v", err)
+	}
+	err := checker.CheckWritable()
+	if err == nil || !strings.Contains(err.Error(), "sudo") {
+		t.Fatalf("expected sudo hint, got %v", err)
+	}
+	if user := checker.SudoUser(); user != "" {
+		t.Fatalf("non-root process should not report sudo user, got %q", user)
+	}
+
+	checker.geteuid = func() int { return 0 }
+	if user := checker.SudoUser(); user != "alice" {
+		t.Fatalf("expected sudo user alice, got %q", user)
+	}
+
+	writable := NewChecker(models.Config{RootDir: filepath.Join(temp, "shared"), SystemMode: true})
+	if err := writable.CheckWritable(); err != nil {
+		t.Fatalf("expected writable root: %v", err)
+	}
+}
+
+func TestCheckWritableDiagnosesExecFailures(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "govm")
+	checker := NewChecker(models.Config{RootDir: root})
+	checker.run = func(string) error { return &os.PathError{Op: "fork/exec", Path: root, Err: syscall.EACCES} }
+	host := map[string]string{
+		"/proc/self/mountinfo":    "22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n" + "40 22 0:35 / " + filepath.Dir(root) + " rw,nosuid,noexec shared:2 - tmpfs tmpfs rw\n",
+		"/sys/fs/selinux/enforce": "1\n",
+	}
+	checker.readFile = func(name string) ([]byte, error) {
+		if v, ok := host[name]; ok {
+			return []byte(v), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	checker.label = func(string) string { return "system_u:object_r:tmp_t:s0" }
+
+	err := checker.CheckWritable()
+	if err == nil {
+		t.Fatal("expected exec probe failure")
+	}
+	for _, want := range []string{"cannot execute", "mounted noexec", "tmp_t", "restorecon"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "AppArmor") {
+		t.Fatalf("AppArmor hint should only appear when it is enabled: %v", err)
+	}
+
+	checker.run = func(string) error { return errors.New("exec: no such file") }
+	if err := checker.CheckWritable(); err != nil {
+		t.Fatalf("non-permission exec errors should be ignored: %v", err)
+	}
+}
+
+func TestLookupOwner(t *testing.T) {
+	t.Parallel()
+
+	uid, gid, err := LookupOwner(strconv.Itoa(os.Getuid()))
+	if err != nil {
+		t.Fatalf("LookupOwner failed: %v", err)
+	}
+	if uid != os.Getuid() || gid != os.Getgid() {
+		t.Fatalf("LookupOwner = %d:%d, want %d:%d", uid, gid, os.Getuid(), os.Getgid())
+	}
+	if _, _, err := LookupOwner(":staff"); err == nil {
+		t.Fatal("expected error for missing user")
+	}
+}
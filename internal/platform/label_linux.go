@@ -0,0 +1,18 @@
+//go:build linux
+
+package platform
+
+import (
+	"strings"
+	"syscall"
+)
+
+// selinuxLabel 读取 security.selinux 扩展属性，未启用或读取失败时返回空串。
+func selinuxLabel(path string) string {
+	buf := make([]byte, 256)
+	n, err := syscall.Getxattr(path, "security.selinux", buf)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	return strings.TrimRight(string(buf[:n]), "\x00")
+}
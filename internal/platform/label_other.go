@@ -0,0 +1,5 @@
+//go:build !linux
+
+package platform
+
+func selinuxLabel(string) string { return "" }
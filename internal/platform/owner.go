@@ -0,0 +1,45 @@
+package platform
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// LookupOwner 解析 user 或 user:group 形式的属主配置，名称与数字 ID 均可；省略组时使用该用户的主组。
+func LookupOwner(spec string) (uid, gid int, err error) {
+	name, group, hasGroup := strings.Cut(strings.TrimSpace(spec), ":")
+	if name == "" {
+		return 0, 0, fmt.Errorf("platform: invalid owner %q", spec)
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		if _, convErr := strconv.Atoi(name); convErr != nil {
+			return 0, 0, fmt.Errorf("platform: lookup owner %q: %w", name, err)
+		}
+		if u, err = user.LookupId(name); err != nil {
+			return 0, 0, fmt.Errorf("platform: lookup owner %q: %w", name, err)
+		}
+	}
+	if uid, err = strconv.Atoi(u.Uid); err != nil {
+		return 0, 0, fmt.Errorf("platform: owner %q has non-numeric uid %q", name, u.Uid)
+	}
+	gidStr := u.Gid
+	if hasGroup && group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			if _, convErr := strconv.Atoi(group); convErr != nil {
+				return 0, 0, fmt.Errorf("platform: lookup group %q: %w", group, err)
+			}
+			if g, err = user.LookupGroupId(group); err != nil {
+				return 0, 0, fmt.Errorf("platform: lookup group %q: %w", group, err)
+			}
+		}
+		gidStr = g.Gid
+	}
+	if gid, err = strconv.Atoi(gidStr); err != nil {
+		return 0, 0, fmt.Errorf("platform: group of %q has non-numeric gid %q", spec, gidStr)
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,128 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// probeScript 为执行探测使用的最小脚本：能运行说明该目录允许执行解压后的 go 二进制。
+const probeScript = "#!/bin/sh\nexit 0\n"
+
+// probeExec 在 dir 中创建一个可执行脚本并尝试运行，用于发现 noexec 挂载或 SELinux/AppArmor 拒绝执行的情况。
+// 仅权限类错误被视为失败，缺少 /bin/sh 等与目录无关的问题会被忽略。
+func (c *Checker) probeExec(dir string) error {
+	probe, err := os.CreateTemp(dir, ".govm-exec-*")
+	if err != nil {
+		return err
+	}
+	name := probe.Name()
+	defer os.Remove(name)
+	_, err = probe.WriteString(probeScript)
+	if closeErr := probe.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(name, 0o755); err != nil {
+		return err
+	}
+	if err := c.run(name); err != nil && isPermission(err) {
+		return err
+	}
+	return nil
+}
+
+func isPermission(err error) bool {
+	return errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.EPERM)
+}
+
+func runProbe(path string) error {
+	return exec.Command(path).Run()
+}
+
+// diagnose 为写入或执行失败补充针对性的修复建议：只读或 noexec 挂载、SELinux 标签、AppArmor 配置。
+func (c *Checker) diagnose(dir, op string, err error) error {
+	var hints []string
+	if opts, mountPoint := c.mountOptions(dir); mountPoint != "" {
+		switch {
+		case op == "write" && opts["ro"]:
+			hints = append(hints, fmt.Sprintf("%s is mounted read-only; set GOVM_HOME or rootDir to a writable filesystem", mountPoint))
+		case op == "execute" && opts["noexec"]:
+			hints = append(hints, fmt.Sprintf("%s is mounted noexec; remount it with exec or set GOVM_HOME to a filesystem that allows execution", mountPoint))
+		}
+	}
+	if c.selinuxEnforcing() {
+		label := c.label(dir)
+		if label == "" {
+			label = "unknown"
+		}
+		hints = append(hints, fmt.Sprintf("SELinux is enforcing and %s is labeled %s; relabel it, e.g. semanage fcontext -a -t bin_t '%s(/.*)?' && restorecon -R %s", dir, label, dir, dir))
+	}
+	if c.apparmorEnabled() {
+		hints = append(hints, fmt.Sprintf("AppArmor is enabled; check aa-status and the kernel log for apparmor=\"DENIED\" entries mentioning %s", dir))
+	}
+	if len(hints) == 0 {
+		hints = append(hints, "check the directory owner and mode, or set GOVM_HOME to a directory you own")
+	}
+	return fmt.Errorf("platform: cannot %s in install directory %s: %w\n  hint: %s", op, dir, err, strings.Join(hints, "\n  hint: "))
+}
+
+// mountOptions 从 mountinfo 中找到包含 dir 的最深挂载点，返回其挂载选项与超级块选项的并集。
+func (c *Checker) mountOptions(dir string) (map[string]bool, string) {
+	data, err := c.readFile("/proc/self/mountinfo")
+	if err != nil {
+		return nil, ""
+	}
+	path := filepath.Clean(dir)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	var best string
+	var opts map[string]bool
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if len(fields) < 6 || sep < 0 || sep+3 >= len(fields) {
+			continue
+		}
+		mountPoint := strings.ReplaceAll(fields[4], `\040`, " ")
+		if !within(path, mountPoint) || len(mountPoint) < len(best) {
+			continue
+		}
+		best = mountPoint
+		opts = map[string]bool{}
+		for _, o := range strings.Split(fields[5]+","+fields[sep+3], ",") {
+			opts[o] = true
+		}
+	}
+	return opts, best
+}
+
+func within(path, dir string) bool {
+	if dir == "/" || path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func (c *Checker) selinuxEnforcing() bool {
+	data, err := c.readFile("/sys/fs/selinux/enforce")
+	return err == nil && strings.TrimSpace(string(data)) == "1"
+}
+
+func (c *Checker) apparmorEnabled() bool {
+	data, err := c.readFile("/sys/module/apparmor/parameters/enabled")
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(data)), "Y")
+}
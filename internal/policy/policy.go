@@ -0,0 +1,154 @@
+// Package policy 实现团队级版本策略：允许/禁止列表、最低版本与指定镜像。
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// 策略执行模式。
+const (
+	ModeEnforce = "enforce"
+	ModeWarn    = "warn"
+)
+
+// Policy 描述策略文件内容。
+//
+// allow/deny 中的条目可以是精确版本（1.22.3）或带通配段的系列（1.22.x、1.22.*）。
+type Policy struct {
+	Allow          []string `json:"allow,omitempty"`
+	Deny           []string `json:"deny,omitempty"`
+	MinVersion     string   `json:"minVersion,omitempty"`
+	RequiredMirror string   `json:"requiredMirror,omitempty"`
+	Mode           string   `json:"mode,omitempty"`
+}
+
+// Violation 表示某个版本违反了策略。
+type Violation struct {
+	Version string
+	Reason  string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("policy: go%s %s", v.Version, v.Reason)
+}
+
+// Load 读取策略文件，path 为空时返回 nil 策略。
+func Load(path string) (*Policy, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy: read %s: %w", path, err)
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("policy: decode %s: %w", path, err)
+	}
+	switch p.Mode {
+	case "":
+		p.Mode = ModeEnforce
+	case ModeEnforce, ModeWarn:
+	default:
+		return nil, fmt.Errorf("policy: unknown mode %q", p.Mode)
+	}
+	p.MinVersion = normalize(p.MinVersion)
+	return &p, nil
+}
+
+// WarnOnly 表示违规时只提示而不拒绝。
+func (p *Policy) WarnOnly() bool {
+	return p != nil && p.Mode == ModeWarn
+}
+
+// CheckVersion 校验版本是否满足策略，违规时返回 *Violation。
+func (p *Policy) CheckVersion(ver string) error {
+	if p == nil {
+		return nil
+	}
+	ver = normalize(ver)
+	for _, pattern := range p.Deny {
+		if Match(pattern, ver) {
+			return &Violation{Version: ver, Reason: fmt.Sprintf("is denied by policy (%s)", strings.TrimSpace(pattern))}
+		}
+	}
+	if len(p.Allow) > 0 {
+		allowed := false
+		for _, pattern := range p.Allow {
+			if Match(pattern, ver) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return &Violation{Version: ver, Reason: "is not in the policy allowlist"}
+		}
+	}
+	if p.MinVersion != "" && goversion.Compare(ver, p.MinVersion) < 0 {
+		return &Violation{Version: ver, Reason: fmt.Sprintf("is older than the policy minimum go%s", p.MinVersion)}
+	}
+	return nil
+}
+
+// CheckMirror 校验下载地址是否为策略要求的镜像。
+func (p *Policy) CheckMirror(downloadBase string) error {
+	if p == nil || strings.TrimSpace(p.RequiredMirror) == "" {
+		return nil
+	}
+	if strings.TrimRight(downloadBase, "/") == strings.TrimRight(p.RequiredMirror, "/") {
+		return nil
+	}
+	return fmt.Errorf("policy: mirror %s is not the required mirror %s", downloadBase, p.RequiredMirror)
+}
+
+// CheckEndpoint 校验版本列表等 HTTP 地址是否位于策略要求的镜像之下，本地文件不受限制。
+func (p *Policy) CheckEndpoint(endpoint string) error {
+	if p == nil || strings.TrimSpace(p.RequiredMirror) == "" {
+		return nil
+	}
+	lower := strings.ToLower(endpoint)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return nil
+	}
+	if strings.HasPrefix(endpoint, strings.TrimRight(p.RequiredMirror, "/")+"/") {
+		return nil
+	}
+	return fmt.Errorf("policy: release list %s is not served by the required mirror %s", endpoint, p.RequiredMirror)
+}
+
+// Match 判断版本是否匹配策略条目，x 或 * 段匹配任意值并覆盖其后的所有段。
+func Match(pattern, ver string) bool {
+	pattern = normalize(pattern)
+	ver = normalize(ver)
+	if pattern == "" || ver == "" {
+		return false
+	}
+	pp := strings.Split(pattern, ".")
+	vp := strings.Split(ver, ".")
+	for i, seg := range pp {
+		if seg == "x" || seg == "X" || seg == "*" {
+			return true
+		}
+		if i >= len(vp) || vp[i] != seg {
+			return false
+		}
+	}
+	return len(pp) == len(vp)
+}
+
+// IsViolation 判断错误是否为策略违规。
+func IsViolation(err error) bool {
+	var v *Violation
+	return errors.As(err, &v)
+}
+
+func normalize(v string) string {
+	return strings.TrimPrefix(strings.TrimSpace(v), "go")
+}
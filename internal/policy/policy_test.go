@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		version string
+		want    bool
+	}{
+		{"1.22.x", "1.22.3", true},
+		{"1.22.*", "1.22.0", true},
+		{"go1.22.3", "1.22.3", true},
+		{"1.22.3", "1.22.4", false},
+		{"1.22", "1.22.3", false},
+		{"1.x", "1.19.2", true},
+		{"1.21.x", "1.22.3", false},
+	}
+	for _, tc := range cases {
+		if got := Match(tc.pattern, tc.version); got != tc.want {
+			t.Fatalf("Match(%q,%q)=%v want %v", tc.pattern, tc.version, got, tc.want)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	t.Parallel()
+
+	p := &Policy{
+		Allow:      []string{"1.21.x", "1.22.x"},
+		Deny:       []string{"1.21.0"},
+		MinVersion: "1.21.3",
+	}
+
+	if err := p.CheckVersion("1.22.3"); err != nil {
+		t.Fatalf("expected 1.22.3 allowed: %v", err)
+	}
+	for _, v := range []string{"1.21.0", "1.20.5", "1.21.2"} {
+		if err := p.CheckVersion(v); !IsViolation(err) {
+			t.Fatalf("expected violation for %s, got %v", v, err)
+		}
+	}
+
+	var nilPolicy *Policy
+	if err := nilPolicy.CheckVersion("1.0"); err != nil {
+		t.Fatalf("nil policy should allow everything: %v", err)
+	}
+}
+
+func TestLoadAndMirror(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	content := `{"deny":["1.19.x"],"requiredMirror":"https://mirror.corp/go/","mode":"warn"}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !p.WarnOnly() {
+		t.Fatal("expected warn mode")
+	}
+	if err := p.CheckMirror("https://mirror.corp/go"); err != nil {
+		t.Fatalf("mirror should match: %v", err)
+	}
+	if err := p.CheckMirror("https://go.dev/dl/"); err == nil {
+		t.Fatal("expected mirror mismatch")
+	}
+	if err := p.CheckEndpoint("https://mirror.corp/go/releases.json"); err != nil {
+		t.Fatalf("release list under the mirror should be allowed: %v", err)
+	}
+	if err := p.CheckEndpoint("https://go.dev/dl/?mode=json"); err == nil {
+		t.Fatal("expected release list outside the mirror to be rejected")
+	}
+	if err := p.CheckEndpoint("/srv/catalog.json"); err != nil {
+		t.Fatalf("local catalogs are not mirror endpoints: %v", err)
+	}
+
+	if p, err := Load(""); err != nil || p != nil {
+		t.Fatalf("empty path should yield nil policy, got %v %v", p, err)
+	}
+}
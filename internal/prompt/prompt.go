@@ -0,0 +1,123 @@
+// Package prompt 实现 `govm prompt`：为 shell 提示符与 tmux 状态栏输出当前版本，例如 go1.22.3。
+// 提示符每次刷新都会执行它，因此只读取当前版本标记：标记文件的位置缓存在用户缓存目录中，只有缓存缺失或失效时才加载配置。
+package prompt
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// cacheFile 为用户缓存目录下记录标记位置的文件。
+const cacheFile = "govm/prompt"
+
+// keyEnv 为影响标记位置的环境变量，任一取值变化都会使缓存失效。
+var keyEnv = []string{storage.EnvHome, storage.EnvRoot, config.EnvConfigPath, config.EnvSysConfDir, config.EnvSystemMode, "HOME", "XDG_DATA_HOME", "XDG_CONFIG_HOME", storage.EnvStateDirectory}
+
+// Run 执行 `govm prompt` 并返回退出码：没有激活版本或状态不可读时什么也不输出并返回 0，不影响提示符。
+func Run(out, errOut io.Writer, args []string) int {
+	fs := flag.NewFlagSet("prompt", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	number := fs.Bool("number", false, "print only the version number, without the go prefix")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	current := Current(os.Getenv)
+	if current == "" {
+		return 0
+	}
+	if !*number {
+		current = "go" + current
+	}
+	fmt.Fprintln(out, current)
+	return 0
+}
+
+// Current 返回应显示的版本号：与 go shim 一样 GOVM_VERSION 优先，否则为当前版本标记，都没有时为空。
+func Current(getenv func(string) string) string {
+	if v := strings.TrimSpace(getenv("GOVM_VERSION")); v != "" {
+		return strings.TrimPrefix(v, "go")
+	}
+	path := markerPath(getenv)
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// markerPath 返回当前版本标记文件：缓存中的环境变量取值与各层配置文件（厂商、系统与用户配置）的修改时间都一致时
+// 直接使用缓存的路径，否则加载配置重新确定并写回缓存。
+func markerPath(getenv func(string) string) string {
+	key := cacheKey(getenv)
+	cache := cachePath()
+	if data, err := os.ReadFile(cache); err == nil {
+		// 缓存为四行：环境变量取值、以制表符分隔的配置文件路径、它们的修改时间、标记文件路径。
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if len(lines) == 4 && lines[0] == key && lines[2] == configStamp(strings.Split(lines[1], "\t")) {
+			return lines[3]
+		}
+	}
+
+	configPath := config.Path()
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return ""
+	}
+	if cfg, err = storage.ResolveDirs(cfg); err != nil {
+		return ""
+	}
+	path := storage.CurrentMarkerPath(cfg)
+	if cache != "" && os.MkdirAll(filepath.Dir(cache), 0o755) == nil {
+		// 缓存只是加速，写入失败时下次仍走慢路径。
+		files := config.Files(configPath)
+		_ = os.WriteFile(cache, []byte(strings.Join([]string{key, strings.Join(files, "\t"), configStamp(files), path}, "\n")+"\n"), 0o644)
+	}
+	return path
+}
+
+// cacheKey 把影响标记位置的环境变量取值拼成一行。
+func cacheKey(getenv func(string) string) string {
+	values := make([]string, len(keyEnv))
+	for i, name := range keyEnv {
+		values[i] = name + "=" + strconv.Quote(getenv(name))
+	}
+	return strings.Join(values, " ")
+}
+
+// cachePath 返回缓存文件路径，无法确定用户缓存目录时为空。
+func cachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, cacheFile)
+}
+
+// configStamp 把各配置文件的修改时间拼成一行，任一文件被修改、创建或删除都会改变结果。
+func configStamp(files []string) string {
+	stamps := make([]string, len(files))
+	for i, f := range files {
+		stamps[i] = modTime(f)
+	}
+	return strings.Join(stamps, "\t")
+}
+
+// modTime 返回文件的修改时间，文件不存在时为 "0"。
+func modTime(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "0"
+	}
+	return strconv.FormatInt(info.ModTime().UnixNano(), 10)
+}
@@ -0,0 +1,116 @@
+package prompt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunReadsMarkerThroughCache(t *testing.T) {
+	root := t.TempDir()
+	cache := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("GOVM_HOME", root)
+	t.Setenv("GOVM_CONFIG", filepath.Join(root, "config.json"))
+	t.Setenv("GOVM_VERSION", "")
+
+	run := func(args ...string) string {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		if code := Run(buf, buf, args); code != 0 {
+			t.Fatalf("Run(%q) = %d: %s", args, code, buf.String())
+		}
+		return buf.String()
+	}
+
+	if got := run(); got != "" {
+		t.Fatalf("no active version must print nothing, got %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(root, "current"), []byte("1.22.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(); got != "go1.22.3\n" {
+		t.Fatalf("prompt = %q", got)
+	}
+	data, err := os.ReadFile(filepath.Join(cache, cacheFile))
+	if err != nil || !strings.HasSuffix(string(data), filepath.Join(root, "current")+"\n") {
+		t.Fatalf("marker location not cached: %q, %v", data, err)
+	}
+	if got := run("--number"); got != "1.22.3\n" {
+		t.Fatalf("prompt --number = %q", got)
+	}
+
+	// 换一个根目录后缓存失效，不会继续读取旧标记。
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "current"), []byte("1.21.8"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOVM_HOME", other)
+	if got := run(); got != "go1.21.8\n" {
+		t.Fatalf("prompt after changing GOVM_HOME = %q", got)
+	}
+
+	t.Setenv("GOVM_VERSION", "go1.20.14")
+	if got := run(); got != "go1.20.14\n" {
+		t.Fatalf("GOVM_VERSION must take precedence, got %q", got)
+	}
+}
+
+func TestRunToleratesBrokenState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("GOVM_HOME", "relative/root")
+	t.Setenv("GOVM_VERSION", "")
+
+	buf := &bytes.Buffer{}
+	if code := Run(buf, buf, nil); code != 0 || buf.Len() != 0 {
+		t.Fatalf("broken state must print nothing and exit 0, got %d %q", code, buf.String())
+	}
+}
+
+func TestRunNoticesSystemConfigChanges(t *testing.T) {
+	home := t.TempDir()
+	sysconf := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("GOVM_HOME", "")
+	t.Setenv("GOVM_ROOT", "")
+	t.Setenv("GOVM_CONFIG", filepath.Join(home, "config.json"))
+	t.Setenv("GOVM_SYSCONFDIR", sysconf)
+	t.Setenv("GOVM_VERSION", "")
+
+	systemConfig := filepath.Join(sysconf, "govm", "config.json")
+	if err := os.MkdirAll(filepath.Dir(systemConfig), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	useRoot := func(version string, stamp time.Time) {
+		t.Helper()
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, "current"), []byte(version), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(systemConfig, []byte(`{"rootDir": "`+root+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		// 两次写入可能落在同一个时间戳内，显式设置修改时间。
+		if err := os.Chtimes(systemConfig, stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	useRoot("1.22.3", time.Unix(1000, 0))
+	buf := &bytes.Buffer{}
+	if Run(buf, buf, nil); buf.String() != "go1.22.3\n" {
+		t.Fatalf("prompt = %q", buf.String())
+	}
+	// 只改系统配置中的根目录，用户配置与环境变量都不变，缓存也必须失效。
+	useRoot("1.21.8", time.Unix(2000, 0))
+	buf.Reset()
+	if Run(buf, buf, nil); buf.String() != "go1.21.8\n" {
+		t.Fatalf("prompt after editing the system config = %q", buf.String())
+	}
+}
@@ -0,0 +1,226 @@
+package provision
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/remote"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// Runner 在远程主机上以 sh 执行脚本，stdin 作为脚本的标准输入（可为 nil）。
+type Runner interface {
+	Run(host, script string, stdin io.Reader, stdout io.Writer) error
+}
+
+// SSHRunner 通过本机的 ssh 客户端执行脚本，认证、跳板机等沿用 ~/.ssh/config。
+type SSHRunner struct {
+	// Options 为附加在 host 之前的 ssh 参数，例如 -p 2222。
+	Options []string
+}
+
+// Run 执行 `ssh [options] host sh -c <script>`，stderr 直接输出到本机终端。
+func (r SSHRunner) Run(host, script string, stdin io.Reader, stdout io.Writer) error {
+	args := append(append([]string{}, r.Options...), "--", host, "sh -c "+shellQuote(script))
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("provision: ssh %s: %w", host, err)
+	}
+	return nil
+}
+
+// Options 配置一次远程安装。
+type Options struct {
+	// Dir 为远程主机上的版本目录，为空时使用 $HOME/.govm/versions，与本机布局一致。
+	Dir string
+	// NoEnv 为 true 时只安装，不修改远程 shell 配置。
+	NoEnv bool
+	// GoPath 为配置块中 GOPATH 的默认值，为空时使用 $HOME/go。
+	GoPath string
+	// SSHOptions 为传给 ssh 的附加参数。
+	SSHOptions []string
+}
+
+// Result 描述远程安装的结果。
+type Result struct {
+	Version     models.Version
+	GoRoot      string
+	ShellConfig string // 写入配置块的文件，NoEnv 时为空
+}
+
+// Provisioner 把本机缓存（必要时下载）的 Go 归档通过 SSH 安装到远程 Linux 主机。
+type Provisioner struct {
+	catalog    remote.RemoteClient
+	downloader version.ArtifactDownloader
+	runner     Runner
+}
+
+// Option 配置 Provisioner。
+type Option func(*Provisioner)
+
+// WithRunner 替换执行远程脚本的方式，设置后忽略 Options.SSHOptions，主要用于测试。
+func WithRunner(r Runner) Option {
+	return func(p *Provisioner) {
+		p.runner = r
+	}
+}
+
+// New 创建 Provisioner，默认通过本机 ssh 客户端连接远程主机。
+func New(catalog remote.RemoteClient, downloader version.ArtifactDownloader, opts ...Option) *Provisioner {
+	p := &Provisioner{catalog: catalog, downloader: downloader}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// hostInfo 为探测到的远程主机信息。
+type hostInfo struct {
+	arch  string
+	home  string
+	shell string
+	rc    string // 配置块写入的文件，相对于 home
+}
+
+// probeScript 依次输出内核名、机器架构、HOME、登录 shell 以及 ~/.bashrc 是否存在。
+const probeScript = `uname -s; uname -m; printf '%s\n' "$HOME"; basename "${SHELL:-sh}"; if [ -f "$HOME/.bashrc" ]; then echo yes; else echo no; fi`
+
+// Provision 在 host 上安装 number 指定的版本：探测架构、复用本机下载缓存取得归档、
+// 经 SSH 解压到临时目录并校验 go 可执行后替换目标目录，最后更新 shell 配置块。
+func (p *Provisioner) Provision(host, number string, opts Options) (Result, error) {
+	runner := p.runner
+	if runner == nil {
+		runner = SSHRunner{Options: opts.SSHOptions}
+	}
+	info, err := probe(runner, host)
+	if err != nil {
+		return Result{}, err
+	}
+	versions, err := p.catalog.FetchVersions(context.Background())
+	if err != nil {
+		return Result{}, fmt.Errorf("provision: %w", err)
+	}
+	target, ok := version.FindArchive(versions, number, info.arch)
+	if !ok {
+		return Result{}, fmt.Errorf("provision: go%s for linux/%s is not in the release list", number, info.arch)
+	}
+	archive, err := p.downloader.Download(target)
+	if err != nil {
+		return Result{}, err
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = path.Join(info.home, ".govm", "versions")
+	}
+	result := Result{Version: target, GoRoot: path.Join(dir, "go"+target.Number)}
+	if err := install(runner, host, archive, result.GoRoot); err != nil {
+		return Result{}, err
+	}
+	if opts.NoEnv {
+		return result, nil
+	}
+	result.ShellConfig = path.Join(info.home, info.rc)
+	if err := writeBlock(runner, host, result.ShellConfig, env.Block(result.GoRoot, opts.GoPath)); err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+func probe(runner Runner, host string) (hostInfo, error) {
+	var out bytes.Buffer
+	if err := runner.Run(host, probeScript, nil, &out); err != nil {
+		return hostInfo{}, err
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		return hostInfo{}, fmt.Errorf("provision: unexpected probe output from %s: %q", host, out.String())
+	}
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	if lines[0] != "Linux" {
+		return hostInfo{}, fmt.Errorf("provision: %s runs %s; only Linux hosts are supported", host, lines[0])
+	}
+	arch, ok := goArch(lines[1])
+	if !ok {
+		return hostInfo{}, fmt.Errorf("provision: unsupported architecture %q on %s", lines[1], host)
+	}
+	if !path.IsAbs(lines[2]) {
+		return hostInfo{}, fmt.Errorf("provision: HOME on %s is not an absolute path: %q", host, lines[2])
+	}
+	info := hostInfo{arch: arch, home: lines[2], shell: lines[3]}
+	switch {
+	case info.shell == "zsh":
+		info.rc = ".zshrc"
+	case info.shell == "bash" && lines[4] == "yes":
+		info.rc = ".bashrc"
+	case info.shell == "bash":
+		info.rc = ".bash_profile"
+	default:
+		info.rc = ".profile"
+	}
+	return info, nil
+}
+
+// installScript 从标准输入解压归档到临时目录，确认 go 可以运行后再替换目标目录，失败时目标保持原样。
+const installScript = `set -e
+root=%s
+tmp="$root.govm-tmp"
+rm -rf "$tmp"
+mkdir -p "$tmp"
+tar -xzf - -C "$tmp" --strip-components=1
+"$tmp/bin/go" version >/dev/null
+rm -rf "$root"
+mv "$tmp" "$root"`
+
+func install(runner Runner, host, archive, goRoot string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("provision: %w", err)
+	}
+	defer f.Close()
+	return runner.Run(host, fmt.Sprintf(installScript, shellQuote(goRoot)), f, io.Discard)
+}
+
+// writeBlock 读取远程配置文件，按与本机相同的规则替换或追加 govm 配置块；
+// 先写临时文件再覆盖原文件内容，保留原文件的权限以及指向它的符号链接。
+func writeBlock(runner Runner, host, rc, block string) error {
+	var existing bytes.Buffer
+	if err := runner.Run(host, fmt.Sprintf("cat %s 2>/dev/null || true", shellQuote(rc)), nil, &existing); err != nil {
+		return err
+	}
+	merged := env.MergeBlock(existing.String(), block)
+	script := fmt.Sprintf(`set -e
+rc=%s
+cat > "$rc.govm-tmp"
+cat "$rc.govm-tmp" > "$rc"
+rm -f "$rc.govm-tmp"`, shellQuote(rc))
+	return runner.Run(host, script, strings.NewReader(merged), io.Discard)
+}
+
+// goArch 把 uname -m 的输出映射为 Go 的架构名。
+func goArch(machine string) (string, bool) {
+	switch machine {
+	case "x86_64", "amd64":
+		return "amd64", true
+	case "aarch64", "arm64":
+		return "arm64", true
+	case "i386", "i486", "i586", "i686":
+		return "386", true
+	}
+	return "", false
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,154 @@
+package provision
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// localRunner 在本机以独立的 HOME 执行远程脚本，模拟一台 SSH 主机。
+type localRunner struct {
+	home  string
+	shell string
+	hosts []string
+}
+
+func (r *localRunner) Run(host, script string, stdin io.Reader, stdout io.Writer) error {
+	r.hosts = append(r.hosts, host)
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Env = append(os.Environ(), "HOME="+r.home, "SHELL=/bin/"+r.shell)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, os.Stderr
+	return cmd.Run()
+}
+
+type fakeCatalog struct{ versions []models.Version }
+
+func (f fakeCatalog) FetchVersions(context.Context) ([]models.Version, error) { return f.versions, nil }
+
+type fakeDownloader struct {
+	path  string
+	calls int
+}
+
+func (f *fakeDownloader) Download(models.Version) (string, error) {
+	f.calls++
+	return f.path, nil
+}
+
+func writeArchive(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "go.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"go/bin/go": "#!/bin/sh\necho go1.22.3\n", "go/VERSION": "go1.22.3\n"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProvisionInstallsAndWritesBlock(t *testing.T) {
+	t.Parallel()
+
+	catalog := fakeCatalog{}
+	for _, arch := range []string{"amd64", "arm64", "386"} {
+		catalog.versions = append(catalog.versions, models.Version{Number: "1.22.3", FullName: "go1.22.3", Arch: arch, OS: "linux"})
+	}
+	home := t.TempDir()
+	rc := filepath.Join(home, ".zshrc")
+	if err := os.WriteFile(rc, []byte("alias ll='ls -l'\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runner := &localRunner{home: home, shell: "zsh"}
+	dl := &fakeDownloader{path: writeArchive(t)}
+	p := New(catalog, dl, WithRunner(runner))
+
+	result, err := p.Provision("ci@build-01", "1.22.3", Options{})
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	wantRoot := filepath.Join(home, ".govm", "versions", "go1.22.3")
+	if result.GoRoot != wantRoot || result.ShellConfig != rc {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if _, err := os.Stat(filepath.Join(wantRoot, "bin", "go")); err != nil {
+		t.Fatalf("go binary not extracted: %v", err)
+	}
+	if _, err := os.Stat(wantRoot + ".govm-tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary directory left behind: %v", err)
+	}
+	data, err := os.ReadFile(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "alias ll='ls -l'\n") || !strings.Contains(content, `export GOROOT="`+wantRoot+`"`) {
+		t.Fatalf("rc not updated in place:\n%s", content)
+	}
+	if info, err := os.Stat(rc); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("rc permissions must be kept: %v %v", info.Mode(), err)
+	}
+
+	// 再次执行时替换而不是重复追加配置块。
+	if _, err := p.Provision("ci@build-01", "1.22.3", Options{}); err != nil {
+		t.Fatalf("second Provision: %v", err)
+	}
+	data, _ = os.ReadFile(rc)
+	if n := strings.Count(string(data), "# >>> govm initialize >>>"); n != 1 {
+		t.Fatalf("expected a single govm block, got %d:\n%s", n, data)
+	}
+	if dl.calls != 2 || runner.hosts[0] != "ci@build-01" {
+		t.Fatalf("unexpected calls: downloads=%d hosts=%v", dl.calls, runner.hosts)
+	}
+}
+
+func TestProvisionNoEnvAndMissingVersion(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	runner := &localRunner{home: home, shell: "bash"}
+	catalog := fakeCatalog{versions: []models.Version{{Number: "1.21.0", Arch: "s390x"}}}
+	p := New(catalog, &fakeDownloader{path: writeArchive(t)}, WithRunner(runner))
+	if _, err := p.Provision("host", "1.21.0", Options{}); err == nil || !strings.Contains(err.Error(), "not in the release list") {
+		t.Fatalf("missing architecture must fail, got %v", err)
+	}
+
+	for _, arch := range []string{"amd64", "arm64", "386"} {
+		catalog.versions = append(catalog.versions, models.Version{Number: "1.21.0", FullName: "go1.21.0", Arch: arch})
+	}
+	p = New(catalog, &fakeDownloader{path: writeArchive(t)}, WithRunner(runner))
+	dir := filepath.Join(home, "opt")
+	result, err := p.Provision("host", "1.21.0", Options{Dir: dir, NoEnv: true})
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if result.GoRoot != filepath.Join(dir, "go1.21.0") || result.ShellConfig != "" {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".bash_profile")); !os.IsNotExist(err) {
+		t.Fatalf("--no-env must not touch shell config: %v", err)
+	}
+}
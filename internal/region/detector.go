@@ -21,6 +21,23 @@ const (
 
 type responseParser func([]byte) (string, error)
 
+// Provider 描述一个公网 IP 归属地查询接口，Parse 从响应体中取出国家代码。
+type Provider struct {
+	URL   string
+	Parse func([]byte) (string, error)
+}
+
+// defaultProviders 为默认同时查询的接口：任意一个返回即可，某个接口被屏蔽或限流时不会拖慢启动。
+func defaultProviders() []Provider {
+	return []Provider{
+		{URL: defaultEndpoint, Parse: parsePlainCountry},
+		{URL: defaultFallback, Parse: parseJSONCountry},
+		{URL: "https://api.country.is/", Parse: parseCountryField},
+		{URL: "https://ipwho.is/", Parse: parseJSONCountry},
+		{URL: "https://www.cloudflare.com/cdn-cgi/trace", Parse: parseTraceCountry},
+	}
+}
+
 // HTTPClient 最小化 HTTP 客户端接口，便于测试替换。
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -28,13 +45,9 @@ type HTTPClient interface {
 
 // Detector 实现 RegionDetector，负责探测公网 IP 所在国家。
 type Detector struct {
-	endpoint         string
-	fallbackEndpoint string
-	client           HTTPClient
-	timeout          time.Duration
-
-	parsePrimary  responseParser
-	parseFallback responseParser
+	providers []Provider
+	client    HTTPClient
+	timeout   time.Duration
 
 	mu    sync.Mutex
 	cache string
@@ -43,19 +56,31 @@ type Detector struct {
 // Option 用于配置 Detector。
 type Option func(*Detector)
 
-// WithEndpoint 设置自定义探测接口地址。
+// WithEndpoint 设置首选探测接口地址，响应体为纯文本国家代码。
 func WithEndpoint(endpoint string) Option {
 	return func(d *Detector) {
 		if endpoint != "" {
-			d.endpoint = endpoint
+			d.providers[0] = Provider{URL: endpoint, Parse: parsePlainCountry}
 		}
 	}
 }
 
-// WithFallbackEndpoint 设置自定义备选接口地址。
+// WithFallbackEndpoint 用单个返回 {"country_code": ...} 的接口替换全部备选接口，为空时只查询首选接口。
 func WithFallbackEndpoint(endpoint string) Option {
 	return func(d *Detector) {
-		d.fallbackEndpoint = endpoint
+		d.providers = d.providers[:1]
+		if endpoint != "" {
+			d.providers = append(d.providers, Provider{URL: endpoint, Parse: parseJSONCountry})
+		}
+	}
+}
+
+// WithProviders 替换全部探测接口。
+func WithProviders(providers ...Provider) Option {
+	return func(d *Detector) {
+		if len(providers) > 0 {
+			d.providers = append([]Provider(nil), providers...)
+		}
 	}
 }
 
@@ -68,7 +93,7 @@ func WithHTTPClient(client HTTPClient) Option {
 	}
 }
 
-// WithTimeout 设置探测请求超时时间。
+// WithTimeout 设置探测请求超时时间，所有接口并发查询，因此这也是探测的最长耗时。
 func WithTimeout(timeout time.Duration) Option {
 	return func(d *Detector) {
 		if timeout > 0 {
@@ -80,12 +105,9 @@ func WithTimeout(timeout time.Duration) Option {
 // NewDetector 创建 Detector 实例。
 func NewDetector(opts ...Option) *Detector {
 	detector := &Detector{
-		endpoint:         defaultEndpoint,
-		fallbackEndpoint: defaultFallback,
-		client:           http.DefaultClient,
-		timeout:          defaultTimeout,
-		parsePrimary:     parsePlainCountry,
-		parseFallback:    parseJSONCountry,
+		providers: defaultProviders(),
+		client:    http.DefaultClient,
+		timeout:   defaultTimeout,
 	}
 	for _, opt := range opts {
 		opt(detector)
@@ -116,25 +138,50 @@ func (d *Detector) CountryCode(ctx context.Context) (string, error) {
 	return code, nil
 }
 
+// lookup 并发查询全部接口并采用第一个成功的结果，随后取消其余请求；全部失败时汇总各接口的错误。
 func (d *Detector) lookup(ctx context.Context) (string, error) {
 	if d.client == nil {
 		return "", errors.New("region: http client is nil")
 	}
+	if len(d.providers) == 0 {
+		return "", errors.New("region: no providers configured")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
 
-	code, err := d.fetchCountry(ctx, d.endpoint, d.parsePrimary)
-	if err == nil {
-		return code, nil
+	type result struct {
+		index int
+		code  string
+		err   error
 	}
+	results := make(chan result, len(d.providers))
+	var wg sync.WaitGroup
+	for i, p := range d.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, err := d.fetchCountry(ctx, p.URL, p.Parse)
+			results <- result{index: i, code: code, err: err}
+		}()
+	}
+	// 返回前取消其余请求并等待它们退出，避免调用返回后还有 goroutine 持有连接。
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
 
-	if d.fallbackEndpoint != "" {
-		if fallbackCode, fbErr := d.fetchCountry(ctx, d.fallbackEndpoint, d.parseFallback); fbErr == nil {
-			return fallbackCode, nil
-		} else {
-			return "", fmt.Errorf("%v (fallback: %v)", err, fbErr)
+	errs := make([]any, len(d.providers))
+	for range d.providers {
+		r := <-results
+		if r.err == nil {
+			return r.code, nil
 		}
+		errs[r.index] = fmt.Errorf("%s: %w", d.providers[r.index].URL, r.err)
 	}
-
-	return "", err
+	format := strings.TrimSuffix(strings.Repeat("%w; ", len(errs)), "; ")
+	return "", fmt.Errorf("region: all providers failed: "+format, errs...)
 }
 
 func (d *Detector) fetchCountry(ctx context.Context, endpoint string, parser responseParser) (string, error) {
@@ -195,3 +242,24 @@ func parseJSONCountry(data []byte) (string, error) {
 	}
 	return parsePlainCountry([]byte(payload.CountryCode))
 }
+
+// parseCountryField 解析 {"country": "US"} 形式的响应（api.country.is）。
+func parseCountryField(data []byte) (string, error) {
+	var payload struct {
+		Country string `json:"country"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("region: decode response: %w", err)
+	}
+	return parsePlainCountry([]byte(payload.Country))
+}
+
+// parseTraceCountry 解析 Cloudflare cdn-cgi/trace 的 key=value 响应中的 loc 字段。
+func parseTraceCountry(data []byte) (string, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "loc="); ok {
+			return parsePlainCountry([]byte(v))
+		}
+	}
+	return "", errors.New(errEmptyCountryMessage)
+}
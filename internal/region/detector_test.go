@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDetectorCachesCountryCode(t *testing.T) {
@@ -96,3 +98,83 @@ func TestDetectorFallsBackToJSONEndpoint(t *testing.T) {
 		t.Fatalf("expected CN, got %s", code)
 	}
 }
+
+func TestDetectorRacesProvidersAndCancelsLosers(t *testing.T) {
+	t.Parallel()
+
+	cancelled := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+			w.Write([]byte("US"))
+		}
+	}))
+	t.Cleanup(slow.Close)
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"country":"cn"}`))
+	}))
+	t.Cleanup(fast.Close)
+
+	detector := NewDetector(
+		WithProviders(
+			Provider{URL: slow.URL, Parse: parsePlainCountry},
+			Provider{URL: fast.URL, Parse: parseCountryField},
+		),
+		WithTimeout(10*time.Second),
+	)
+
+	start := time.Now()
+	code, err := detector.CountryCode(context.Background())
+	if err != nil {
+		t.Fatalf("CountryCode error: %v", err)
+	}
+	if code != "CN" {
+		t.Fatalf("expected CN from the fast provider, got %s", code)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("detection waited for the slow provider: %v", elapsed)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow provider request was not cancelled")
+	}
+}
+
+func TestDetectorReportsEveryProviderFailure(t *testing.T) {
+	t.Parallel()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(down.Close)
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fl=1\nloc=\n"))
+	}))
+	t.Cleanup(empty.Close)
+
+	detector := NewDetector(WithProviders(
+		Provider{URL: down.URL, Parse: parsePlainCountry},
+		Provider{URL: empty.URL, Parse: parseTraceCountry},
+	))
+	_, err := detector.CountryCode(context.Background())
+	if err == nil {
+		t.Fatal("expected error when every provider fails")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "status 503") || !strings.Contains(msg, errEmptyCountryMessage) || strings.Contains(msg, "\n") {
+		t.Fatalf("error should list each failure on one line: %q", msg)
+	}
+}
+
+func TestParseTraceCountry(t *testing.T) {
+	t.Parallel()
+
+	code, err := parseTraceCountry([]byte("fl=28f\nh=www.cloudflare.com\nip=203.0.113.7\nloc=JP\ntls=TLSv1.3\n"))
+	if err != nil || code != "JP" {
+		t.Fatalf("parseTraceCountry = %q, %v", code, err)
+	}
+}
@@ -0,0 +1,89 @@
+package region
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cnTimezones 为中国大陆使用的 IANA 时区名（含已废弃的别名）。
+var cnTimezones = map[string]bool{
+	"Asia/Shanghai":  true,
+	"Asia/Chongqing": true,
+	"Asia/Chungking": true,
+	"Asia/Harbin":    true,
+	"Asia/Urumqi":    true,
+	"Asia/Kashgar":   true,
+	"PRC":            true,
+}
+
+// Heuristic 在公网 IP 探测全部失败时（常见于防火墙之后）根据时区与语言环境推断地区。
+type Heuristic struct {
+	getenv   func(string) string
+	readlink func(string) (string, error)
+	readFile func(string) ([]byte, error)
+}
+
+// NewHeuristic 创建读取当前进程环境与系统时区配置的 Heuristic。
+func NewHeuristic() *Heuristic {
+	return &Heuristic{getenv: os.Getenv, readlink: os.Readlink, readFile: os.ReadFile}
+}
+
+// Guess 返回推断出的国家代码及依据（如 TZ=Asia/Shanghai），无法推断时返回空串。
+// 目前只识别中国大陆：其他地区使用默认源即可，误判的代价远大于收益。
+func (h *Heuristic) Guess() (code, reason string) {
+	if tz, source := h.timezone(); cnTimezones[tz] {
+		return "CN", source + "=" + tz
+	}
+	if key, locale := h.locale(); localeCountry(locale) == "CN" {
+		return "CN", key + "=" + locale
+	}
+	return "", ""
+}
+
+// timezone 依次读取 TZ、/etc/localtime 链接目标与 /etc/timezone。
+func (h *Heuristic) timezone() (tz, source string) {
+	if v := strings.TrimPrefix(strings.TrimSpace(h.getenv("TZ")), ":"); v != "" {
+		return zoneName(v), "TZ"
+	}
+	if target, err := h.readlink("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(filepath.ToSlash(target), "zoneinfo/"); ok {
+			return name, "/etc/localtime"
+		}
+	}
+	if data, err := h.readFile("/etc/timezone"); err == nil {
+		if v := strings.TrimSpace(string(data)); v != "" {
+			return v, "/etc/timezone"
+		}
+	}
+	return "", ""
+}
+
+// zoneName 去掉 TZ 中可能出现的 zoneinfo 绝对路径前缀。
+func zoneName(tz string) string {
+	if _, name, ok := strings.Cut(filepath.ToSlash(tz), "zoneinfo/"); ok {
+		return name
+	}
+	return tz
+}
+
+// locale 按 POSIX 优先级返回生效的语言环境：LC_ALL、LC_MESSAGES、LANG。
+func (h *Heuristic) locale() (key, value string) {
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := strings.TrimSpace(h.getenv(k)); v != "" {
+			return k, v
+		}
+	}
+	return "", ""
+}
+
+// localeCountry 从 zh_CN.UTF-8、zh-CN 等形式中取出国家部分。
+func localeCountry(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	_, country, ok := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	if !ok {
+		return ""
+	}
+	return strings.ToUpper(country)
+}
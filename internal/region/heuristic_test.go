@@ -0,0 +1,50 @@
+package region
+
+import (
+	"errors"
+	"testing"
+)
+
+func fakeHeuristic(env map[string]string, localtime, timezone string) *Heuristic {
+	return &Heuristic{
+		getenv: func(k string) string { return env[k] },
+		readlink: func(string) (string, error) {
+			if localtime == "" {
+				return "", errors.New("not a link")
+			}
+			return localtime, nil
+		},
+		readFile: func(string) ([]byte, error) {
+			if timezone == "" {
+				return nil, errors.New("missing")
+			}
+			return []byte(timezone + "\n"), nil
+		},
+	}
+}
+
+func TestHeuristicGuess(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name                string
+		env                 map[string]string
+		localtime, timezone string
+		code, reason        string
+	}{
+		{name: "tz env", env: map[string]string{"TZ": "Asia/Shanghai"}, code: "CN", reason: "TZ=Asia/Shanghai"},
+		{name: "tz path", env: map[string]string{"TZ": ":/usr/share/zoneinfo/PRC"}, code: "CN", reason: "TZ=PRC"},
+		{name: "localtime link", localtime: "../usr/share/zoneinfo/Asia/Urumqi", code: "CN", reason: "/etc/localtime=Asia/Urumqi"},
+		{name: "timezone file", timezone: "Asia/Chongqing", code: "CN", reason: "/etc/timezone=Asia/Chongqing"},
+		{name: "lang", env: map[string]string{"LANG": "zh_CN.UTF-8"}, localtime: "/usr/share/zoneinfo/UTC", code: "CN", reason: "LANG=zh_CN.UTF-8"},
+		{name: "lc_all wins", env: map[string]string{"LC_ALL": "en_US.UTF-8", "LANG": "zh_CN.UTF-8"}},
+		{name: "taiwan", env: map[string]string{"TZ": "Asia/Taipei", "LANG": "zh_TW.UTF-8"}},
+		{name: "nothing"},
+	}
+	for _, tc := range cases {
+		code, reason := fakeHeuristic(tc.env, tc.localtime, tc.timezone).Guess()
+		if code != tc.code || reason != tc.reason {
+			t.Errorf("%s: Guess() = %q, %q; want %q, %q", tc.name, code, reason, tc.code, tc.reason)
+		}
+	}
+}
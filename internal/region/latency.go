@@ -0,0 +1,200 @@
+package region
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultProbeTTL 为测速结果的默认缓存时间。
+	DefaultProbeTTL = 24 * time.Hour
+	// defaultProbeFile 为各镜像都提供的归档，只下载开头一小段用于测速。
+	defaultProbeFile  = "go1.21.0.src.tar.gz"
+	defaultProbeBytes = 128 << 10
+)
+
+// DownloadCandidates 为默认参与测速的下载地址。
+var DownloadCandidates = []string{
+	GoDevMirror.DownloadBase,
+	StudyGolangMirror.DownloadBase,
+	"https://golang.google.cn/dl/",
+	"https://mirrors.aliyun.com/golang/",
+	"https://mirrors.ustc.edu.cn/golang/",
+}
+
+// ProbeResult 为单个下载地址的测速结果。
+type ProbeResult struct {
+	Base    string
+	Elapsed time.Duration // 收到探测数据所用的总时间，包含建立连接与首字节延迟
+	Bytes   int64
+	Err     error
+}
+
+// LatencyProber 通过 Range 请求下载一小段归档，比较各下载地址的实际延迟与吞吐。
+type LatencyProber struct {
+	client  HTTPClient
+	timeout time.Duration
+	file    string
+	bytes   int64
+	// now 只用于判断缓存是否过期。
+	now func() time.Time
+}
+
+// ProbeOption 配置 LatencyProber。
+type ProbeOption func(*LatencyProber)
+
+// WithProbeHTTPClient 设置测速使用的 HTTP 客户端。
+func WithProbeHTTPClient(client HTTPClient) ProbeOption {
+	return func(p *LatencyProber) {
+		if client != nil {
+			p.client = client
+		}
+	}
+}
+
+// WithProbeTimeout 设置单个地址的测速超时，所有地址并发测速。
+func WithProbeTimeout(timeout time.Duration) ProbeOption {
+	return func(p *LatencyProber) {
+		if timeout > 0 {
+			p.timeout = timeout
+		}
+	}
+}
+
+// WithProbeFile 指定用于测速的文件名（相对于下载地址）。
+func WithProbeFile(name string) ProbeOption {
+	return func(p *LatencyProber) {
+		if name != "" {
+			p.file = name
+		}
+	}
+}
+
+// NewLatencyProber 创建 LatencyProber。
+func NewLatencyProber(opts ...ProbeOption) *LatencyProber {
+	p := &LatencyProber{
+		client:  http.DefaultClient,
+		timeout: defaultTimeout,
+		file:    defaultProbeFile,
+		bytes:   defaultProbeBytes,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Rank 并发测速，按耗时从短到长返回结果，失败的地址排在最后。
+func (p *LatencyProber) Rank(ctx context.Context, bases []string) []ProbeResult {
+	results := make([]ProbeResult, len(bases))
+	var wg sync.WaitGroup
+	for i, base := range bases {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = p.probe(ctx, base)
+		}()
+	}
+	wg.Wait()
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Elapsed < results[j].Elapsed
+	})
+	return results
+}
+
+func (p *LatencyProber) probe(ctx context.Context, base string) ProbeResult {
+	result := ProbeResult{Base: base}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, withSlash(base)+p.file, nil)
+	if err != nil {
+		result.Err = fmt.Errorf("region: build request: %w", err)
+		return result
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", p.bytes-1))
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		result.Err = fmt.Errorf("region: request failed: %w", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		result.Err = fmt.Errorf("region: unexpected status %d", resp.StatusCode)
+		return result
+	}
+	// 不支持 Range 的服务器会返回完整文件，只读取所需的长度。
+	result.Bytes, err = io.Copy(io.Discard, io.LimitReader(resp.Body, p.bytes))
+	if err != nil {
+		result.Err = fmt.Errorf("region: read body: %w", err)
+		return result
+	}
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+// probeCache 为写入缓存目录的测速结论。
+type probeCache struct {
+	Candidates []string  `json:"candidates"`
+	Chosen     string    `json:"chosen"`
+	ProbedAt   time.Time `json:"probedAt"`
+}
+
+// Choose 返回 bases 中最快的下载地址；cachePath 中存在相同候选列表且未超过 ttl 的结论时直接复用。
+// 全部地址测速失败时返回错误，调用方应继续使用按地区选择的地址。
+func (p *LatencyProber) Choose(ctx context.Context, bases []string, cachePath string, ttl time.Duration) (base string, cached bool, err error) {
+	if len(bases) == 0 {
+		return "", false, errors.New("region: no download candidates")
+	}
+	if ttl <= 0 {
+		ttl = DefaultProbeTTL
+	}
+	if cachePath != "" {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var c probeCache
+			if json.Unmarshal(data, &c) == nil && slices.Equal(c.Candidates, bases) && p.now().Sub(c.ProbedAt) < ttl && c.Chosen != "" {
+				return c.Chosen, true, nil
+			}
+		}
+	}
+
+	results := p.Rank(ctx, bases)
+	if results[0].Err != nil {
+		msgs := make([]string, len(results))
+		for i, r := range results {
+			msgs[i] = fmt.Sprintf("%s: %v", r.Base, r.Err)
+		}
+		return "", false, fmt.Errorf("region: every download candidate failed: %s", strings.Join(msgs, "; "))
+	}
+	base = results[0].Base
+	if cachePath != "" {
+		data, _ := json.Marshal(probeCache{Candidates: bases, Chosen: base, ProbedAt: p.now().UTC()})
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+	return base, false, nil
+}
+
+func withSlash(base string) string {
+	if strings.HasSuffix(base, "/") {
+		return base
+	}
+	return base + "/"
+}
@@ -0,0 +1,74 @@
+package region
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newMirror(t *testing.T, delay time.Duration, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/"+defaultProbeFile) || r.Header.Get("Range") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		time.Sleep(delay)
+		w.WriteHeader(status)
+		w.Write(make([]byte, 1024))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLatencyProberRanksByElapsed(t *testing.T) {
+	t.Parallel()
+
+	slow := newMirror(t, 150*time.Millisecond, http.StatusPartialContent)
+	fast := newMirror(t, 0, http.StatusOK)
+	broken := newMirror(t, 0, http.StatusForbidden)
+
+	results := NewLatencyProber().Rank(context.Background(), []string{broken.URL, slow.URL + "/dl", fast.URL})
+	if results[0].Base != fast.URL || results[1].Base != slow.URL+"/dl" || results[2].Err == nil {
+		t.Fatalf("unexpected ranking: %+v", results)
+	}
+	if results[0].Bytes != 1024 {
+		t.Fatalf("expected 1024 probe bytes, got %d", results[0].Bytes)
+	}
+}
+
+func TestLatencyProberCachesChoice(t *testing.T) {
+	t.Parallel()
+
+	slow := newMirror(t, 150*time.Millisecond, http.StatusPartialContent)
+	fast := newMirror(t, 0, http.StatusPartialContent)
+	cache := filepath.Join(t.TempDir(), "cache", "mirror-probe.json")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prober := NewLatencyProber()
+	prober.now = func() time.Time { return now }
+
+	candidates := []string{slow.URL, fast.URL}
+	base, cached, err := prober.Choose(context.Background(), candidates, cache, time.Hour)
+	if err != nil || base != fast.URL || cached {
+		t.Fatalf("Choose = %q, %v, %v", base, cached, err)
+	}
+
+	fast.Close()
+	now = now.Add(30 * time.Minute)
+	if base, cached, err = prober.Choose(context.Background(), candidates, cache, time.Hour); err != nil || base != fast.URL || !cached {
+		t.Fatalf("expected cached choice, got %q, %v, %v", base, cached, err)
+	}
+
+	now = now.Add(time.Hour)
+	if base, cached, err = prober.Choose(context.Background(), candidates, cache, time.Hour); err != nil || base != slow.URL || cached {
+		t.Fatalf("expired cache should re-probe, got %q, %v, %v", base, cached, err)
+	}
+
+	if _, _, err = prober.Choose(context.Background(), []string{fast.URL}, cache, time.Hour); err == nil {
+		t.Fatal("changed candidates should re-probe and fail when every mirror is down")
+	}
+}
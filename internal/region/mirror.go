@@ -21,6 +21,35 @@ var (
 	}
 )
 
+// FromPreference 将配置中的下载源偏好转换为国家代码，detect 为 true 时需要按公网 IP 探测。
+func FromPreference(pref string) (countryCode string, detect bool) {
+	switch strings.ToLower(strings.TrimSpace(pref)) {
+	case "", "auto":
+		return "", true
+	case "global", "go.dev":
+		return "", false
+	default:
+		return strings.ToUpper(strings.TrimSpace(pref)), false
+	}
+}
+
+// ValidPreference 报告 pref 是否为 auto、global（go.dev）、cn 或两位字母的 ISO 国家代码。
+func ValidPreference(pref string) bool {
+	code, detect := FromPreference(pref)
+	if detect || code == "" {
+		return true
+	}
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
 // SelectMirror 根据国家代码返回镜像配置。
 func SelectMirror(countryCode string) MirrorConfig {
 	if strings.EqualFold(strings.TrimSpace(countryCode), "CN") {
@@ -23,3 +23,18 @@ func TestSelectMirror(t *testing.T) {
 		}
 	}
 }
+
+func TestValidPreference(t *testing.T) {
+	t.Parallel()
+
+	for _, pref := range []string{"", "auto", "global", "go.dev", "cn", "CN", " us "} {
+		if !ValidPreference(pref) {
+			t.Errorf("ValidPreference(%q) = false", pref)
+		}
+	}
+	for _, pref := range []string{"china", "usa", "c1"} {
+		if ValidPreference(pref) {
+			t.Errorf("ValidPreference(%q) = true", pref)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// cacheFileName 为版本列表在磁盘上的缓存文件名。
+const cacheFileName = "releases.json"
+
+// diskCache 保存原始响应体以及用于条件请求的校验头，响应体按当前下载镜像重新解析。
+type diskCache struct {
+	URL          string          `json:"url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	FetchedAt    time.Time       `json:"fetchedAt"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// WithCacheDir 启用磁盘缓存：TTL 内直接复用，过期后携带 If-None-Match/If-Modified-Since 刷新。
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		c.cacheDir = dir
+	}
+}
+
+// StaleFunc 在版本源不可达、改用过期磁盘缓存时被调用。
+type StaleFunc func(err error, fetchedAt time.Time)
+
+// WithStaleHandler 设置使用过期缓存时的回调，通常用于输出警告。
+func WithStaleHandler(fn StaleFunc) Option {
+	return func(c *Client) {
+		c.onStale = fn
+	}
+}
+
+// staleFallback 在刷新失败时返回磁盘缓存中的版本列表，无可用缓存时返回原错误。
+func (c *Client) staleFallback(cached *diskCache, err error) ([]models.Version, error) {
+	if cached == nil {
+		return nil, err
+	}
+	versions, parseErr := c.parseVersions(cached.Body)
+	if parseErr != nil {
+		return nil, err
+	}
+	if c.onStale != nil {
+		c.onStale(err, cached.FetchedAt)
+	}
+	return versions, nil
+}
+
+func (c *Client) cachePath() string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.cacheDir, cacheFileName)
+}
+
+// loadDiskCache 读取与当前 baseURL 匹配的磁盘缓存，缓存缺失或损坏时返回 nil。
+func (c *Client) loadDiskCache() *diskCache {
+	path := c.cachePath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry diskCache
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != c.baseURL || len(entry.Body) == 0 {
+		return nil
+	}
+	return &entry
+}
+
+// CacheFetchedAt 返回磁盘缓存中版本列表的刷新时间，没有可用缓存时 ok 为 false。
+func (c *Client) CacheFetchedAt() (time.Time, bool) {
+	cached := c.loadDiskCache()
+	if cached == nil {
+		return time.Time{}, false
+	}
+	return cached.FetchedAt, true
+}
+
+// saveDiskCache 原子写入缓存；缓存仅用于加速，写入失败不影响本次结果。
+func (c *Client) saveDiskCache(entry diskCache) error {
+	path := c.cachePath()
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "releases-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// SeedCache 用离线安装包中的版本列表快照替换磁盘缓存，刷新时间记为当前时间；快照无法解析时不写入。
+func (c *Client) SeedCache(body []byte) error {
+	if c.cachePath() == "" {
+		return fmt.Errorf("remote: release list cache is disabled")
+	}
+	versions, err := c.parseVersions(body)
+	if err != nil {
+		return err
+	}
+	if err := c.saveDiskCache(diskCache{URL: c.baseURL, FetchedAt: c.now().UTC(), Body: body}); err != nil {
+		return fmt.Errorf("remote: seed cache: %w", err)
+	}
+	c.setCache(versions)
+	return nil
+}
@@ -0,0 +1,173 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// 版本目录（catalog）的来源类型，通过配置 catalog 选择。
+const (
+	CatalogOfficial = "official" // go.dev JSON API（默认）
+	CatalogStatic   = "static"   // 本地文件或 URL 提供的 go.dev JSON 格式清单
+	CatalogListing  = "listing"  // 抓取只提供目录索引页面的普通镜像
+)
+
+// ProviderConfig 描述如何构建版本目录来源。
+type ProviderConfig struct {
+	Type         string // 为空时等同于 CatalogOfficial
+	URL          string // static 为清单路径或 URL；listing 为目录页地址，默认使用 DownloadBase
+	APIBase      string // official 使用的 JSON API 地址
+	DownloadBase string // 拼接下载地址的基础路径
+	HTTPClient   HTTPClient
+	CacheDir     string
+	OnStale      StaleFunc // official 源不可达而改用过期缓存时的回调
+}
+
+// NewProvider 按配置返回 RemoteClient 实现。
+func NewProvider(cfg ProviderConfig) (RemoteClient, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Type)) {
+	case "", CatalogOfficial:
+		return NewClient(
+			WithHTTPClient(cfg.HTTPClient),
+			WithCacheDir(cfg.CacheDir),
+			WithStaleHandler(cfg.OnStale),
+			WithBaseURL(cfg.APIBase),
+			WithDownloadBase(cfg.DownloadBase),
+		), nil
+	case CatalogStatic:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("remote: static catalog requires catalogURL")
+		}
+		return &StaticCatalog{source: cfg.URL, downloadBase: withSlash(orDefault(cfg.DownloadBase, defaultDownloadBase)), httpClient: orDefaultClient(cfg.HTTPClient)}, nil
+	case CatalogListing:
+		base := withSlash(orDefault(cfg.URL, orDefault(cfg.DownloadBase, defaultDownloadBase)))
+		return &ListingCatalog{indexURL: base, downloadBase: base, httpClient: orDefaultClient(cfg.HTTPClient)}, nil
+	default:
+		return nil, fmt.Errorf("remote: unknown catalog type %q", cfg.Type)
+	}
+}
+
+// StaticCatalog 从本地文件或 URL 读取 go.dev JSON 格式的版本清单，适用于离线或自建索引。
+type StaticCatalog struct {
+	source       string
+	downloadBase string
+	httpClient   HTTPClient
+}
+
+// FetchVersions 读取并解析静态清单。
+func (s *StaticCatalog) FetchVersions(ctx context.Context) ([]models.Version, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if isHTTPURL(s.source) {
+		data, err = httpGet(ctx, s.httpClient, s.source)
+	} else {
+		data, err = os.ReadFile(strings.TrimPrefix(s.source, "file://"))
+		if err != nil {
+			err = fmt.Errorf("remote: read catalog: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseReleases(data, s.downloadBase)
+}
+
+// listingPattern 匹配目录页中的 Linux 归档文件名。
+var listingPattern = regexp.MustCompile(`go(\d+(?:\.\d+)*(?:(?:rc|beta)\d+)?)\.(linux)-([a-z0-9]+)\.tar\.gz`)
+
+// ListingCatalog 解析镜像目录索引页面中的归档文件名，校验值通过对应的 .sha256 文件按需获取。
+type ListingCatalog struct {
+	indexURL     string
+	downloadBase string
+	httpClient   HTTPClient
+}
+
+// FetchVersions 抓取目录页并提取受支持架构的归档。
+func (l *ListingCatalog) FetchVersions(ctx context.Context) ([]models.Version, error) {
+	data, err := httpGet(ctx, l.httpClient, l.indexURL)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var versions []models.Version
+	for _, m := range listingPattern.FindAllStringSubmatch(string(data), -1) {
+		fileName, number, goos, arch := m[0], m[1], m[2], m[3]
+		if _, ok := supportedArch[arch]; !ok {
+			continue
+		}
+		if _, dup := seen[fileName]; dup {
+			continue
+		}
+		seen[fileName] = struct{}{}
+		versions = append(versions, models.Version{
+			Number:      number,
+			FullName:    "go" + number,
+			DownloadURL: l.downloadBase + fileName,
+			FileName:    fileName,
+			ChecksumURL: l.downloadBase + fileName + ".sha256",
+			OS:          goos,
+			Arch:        arch,
+		})
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("remote: no Go archives found at %s", l.indexURL)
+	}
+	sortVersions(versions)
+	assignChannels(versions, nil)
+	return versions, nil
+}
+
+func httpGet(ctx context.Context, client HTTPClient, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("remote: build request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote: unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("remote: read body: %w", err)
+	}
+	return data, nil
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func withSlash(s string) string {
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+	return s
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func orDefaultClient(c HTTPClient) HTTPClient {
+	if c == nil {
+		return http.DefaultClient
+	}
+	return c
+}
@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticCatalogReadsFile(t *testing.T) {
+	t.Parallel()
+
+	releases := []release{{Version: "go1.22.0", Files: []releaseFile{
+		{Filename: "go1.22.0.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Checksum: "abc", Kind: "archive"},
+		{Filename: "go1.22.0.darwin-amd64.tar.gz", OS: "darwin", Arch: "amd64", Checksum: "def", Kind: "archive"},
+	}}}
+	data, err := json.Marshal(releases)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+
+	provider, err := NewProvider(ProviderConfig{Type: CatalogStatic, URL: path, DownloadBase: "https://mirror.example.com/go"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	versions, err := provider.FetchVersions(context.Background())
+	if err != nil {
+		t.Fatalf("FetchVersions: %v", err)
+	}
+	if len(versions) != 1 || versions[0].DownloadURL != "https://mirror.example.com/go/go1.22.0.linux-amd64.tar.gz" {
+		t.Fatalf("unexpected versions: %#v", versions)
+	}
+}
+
+func TestListingCatalogScrapesIndex(t *testing.T) {
+	t.Parallel()
+
+	page := `<html><body>
+<a href="go1.21.6.linux-amd64.tar.gz">go1.21.6.linux-amd64.tar.gz</a>
+<a href="go1.21.6.linux-amd64.tar.gz.sha256">go1.21.6.linux-amd64.tar.gz.sha256</a>
+<a href="go1.22rc1.linux-arm64.tar.gz">go1.22rc1.linux-arm64.tar.gz</a>
+<a href="go1.22.0.linux-armv6l.tar.gz">go1.22.0.linux-armv6l.tar.gz</a>
+<a href="go1.22.0.windows-amd64.zip">go1.22.0.windows-amd64.zip</a>
+</body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(ProviderConfig{Type: CatalogListing, URL: server.URL + "/golang", HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	versions, err := provider.FetchVersions(context.Background())
+	if err != nil {
+		t.Fatalf("FetchVersions: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 linux archives, got %#v", versions)
+	}
+	if versions[0].Number != "1.22rc1" || versions[1].Number != "1.21.6" {
+		t.Fatalf("unexpected order: %s, %s", versions[0].Number, versions[1].Number)
+	}
+	if versions[1].ChecksumURL != server.URL+"/golang/go1.21.6.linux-amd64.tar.gz.sha256" {
+		t.Fatalf("unexpected checksum url: %s", versions[1].ChecksumURL)
+	}
+}
+
+func TestNewProviderRejectsUnknownType(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewProvider(ProviderConfig{Type: "ftp"}); err == nil {
+		t.Fatal("expected error for unknown catalog type")
+	}
+	if _, err := NewProvider(ProviderConfig{Type: CatalogStatic}); err == nil {
+		t.Fatal("expected error for static catalog without URL")
+	}
+}
+
+func TestURLResolver(t *testing.T) {
+	t.Parallel()
+
+	r := &URLResolver{downloadBase: "https://mirror.example.com/golang/", goos: "linux", goarch: "arm64"}
+	v, err := r.Resolve("go1.13.15")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if v.FileName != "go1.13.15.linux-arm64.tar.gz" || v.ChecksumURL != "https://mirror.example.com/golang/go1.13.15.linux-arm64.tar.gz.sha256" {
+		t.Fatalf("unexpected version: %#v", v)
+	}
+	if _, err := r.Resolve("latest"); err == nil {
+		t.Fatal("expected error for unparseable version")
+	}
+}
+
+func TestURLResolverArtifact(t *testing.T) {
+	t.Parallel()
+
+	r := &URLResolver{downloadBase: "https://mirror.example.com/golang/", goos: "linux", goarch: "amd64"}
+	v, err := r.ResolveArtifact("go1.23rc1.linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatalf("ResolveArtifact(file): %v", err)
+	}
+	if v.Number != "1.23rc1" || v.DownloadURL != "https://mirror.example.com/golang/go1.23rc1.linux-amd64.tar.gz" || v.ChecksumURL != "" {
+		t.Fatalf("unexpected version: %#v", v)
+	}
+
+	const url = "https://dl.example.org/pre/go1.22.3.linux-amd64.tar.gz?token=x"
+	if v, err = r.ResolveArtifact(url); err != nil || v.Number != "1.22.3" || v.DownloadURL != url || v.FileName != "go1.22.3.linux-amd64.tar.gz" {
+		t.Fatalf("ResolveArtifact(url) = %#v, %v", v, err)
+	}
+
+	for _, ref := range []string{"go1.22.3.linux-arm64.tar.gz", "go1.22.3.src.tar.gz", "ftp://host/go1.22.3.linux-amd64.tar.gz", "https:///go1.22.3.linux-amd64.tar.gz"} {
+		if _, err := r.ResolveArtifact(ref); err == nil {
+			t.Errorf("expected error for %s", ref)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package remote
+
+import (
+	"slices"
+
+	"github.com/liangyou/govm/pkg/goversion"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// supportedSeries 为官方同时维护的主版本系列数（最新的两个），更早系列的正式版标记为 archived。
+const supportedSeries = 2
+
+// assignChannels 为已按版本降序排列的列表填写 Channel：预发布版本按版本号标记为 rc 或 beta；正式版属于
+// 最新的两个系列、且版本源没有标记为非 stable 时为 stable，否则为 archived。notStable 为版本源中
+// stable 为 false 的 FullName，没有该字段的版本源（目录页、旧的静态清单）只按系列判断。
+func assignChannels(versions []models.Version, notStable map[string]bool) {
+	var supported []string
+	for i := range versions {
+		v := &versions[i]
+		if pre := goversion.Parse(v.Number).Pre; pre != "" {
+			v.Channel = pre
+			continue
+		}
+		series := goversion.Series(v.Number)
+		if len(supported) < supportedSeries && !slices.Contains(supported, series) {
+			supported = append(supported, series)
+		}
+		v.Channel = models.ChannelStable
+		if notStable[v.FullName] || !slices.Contains(supported, series) {
+			v.Channel = models.ChannelArchived
+		}
+	}
+}
+
+// stableFlag 返回写入 go.dev JSON 格式时的 stable 字段，渠道未知时省略。
+func stableFlag(channel string) *bool {
+	if channel == "" {
+		return nil
+	}
+	stable := channel == models.ChannelStable
+	return &stable
+}
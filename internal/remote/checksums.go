@@ -0,0 +1,90 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 官方 JSON API 地址，golang.google.cn 与 go.dev 由同一方维护，内容一致。
+const (
+	officialAPI   = defaultBaseURL
+	officialCNAPI = "https://golang.google.cn/dl/?mode=json&include=all"
+)
+
+// ErrChecksumNotListed 表示官方版本列表中没有该归档。
+var ErrChecksumNotListed = errors.New("remote: archive is not listed in the official release index")
+
+// ChecksumIndex 从官方版本列表查询归档的权威 SHA256，依次尝试 go.dev 与 golang.google.cn，
+// 用于交叉校验镜像提供的校验值。
+type ChecksumIndex struct {
+	sources []RemoteClient
+
+	mu    sync.Mutex
+	index map[string]string
+}
+
+// NewChecksumIndex 创建使用官方 API 的 ChecksumIndex，cacheDir 非空时各来源分别缓存到其子目录。
+func NewChecksumIndex(h HTTPClient, cacheDir string) *ChecksumIndex {
+	var sources []RemoteClient
+	for _, src := range []struct{ name, api string }{{"go.dev", officialAPI}, {"golang.google.cn", officialCNAPI}} {
+		dir := ""
+		if cacheDir != "" {
+			dir = filepath.Join(cacheDir, "checksums", src.name)
+		}
+		sources = append(sources, NewClient(WithBaseURL(src.api), WithHTTPClient(h), WithCacheDir(dir)))
+	}
+	return &ChecksumIndex{sources: sources}
+}
+
+// NewChecksumIndexFrom 使用给定的版本源创建 ChecksumIndex，主要用于测试或自建的可信索引。
+func NewChecksumIndexFrom(sources ...RemoteClient) *ChecksumIndex {
+	return &ChecksumIndex{sources: sources}
+}
+
+// Checksum 返回 fileName 的官方 SHA256；全部来源不可达时返回各来源的错误，列表中没有该文件时返回 ErrChecksumNotListed。
+func (c *ChecksumIndex) Checksum(fileName string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.index == nil {
+		var errs []string
+		for _, src := range c.sources {
+			versions, err := src.FetchVersions(context.Background())
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			c.index = make(map[string]string, len(versions))
+			for _, v := range versions {
+				if v.Checksum != "" {
+					c.index[v.FileName] = strings.ToLower(v.Checksum)
+				}
+			}
+			break
+		}
+		if c.index == nil {
+			return "", fmt.Errorf("remote: official release index unavailable: %s", strings.Join(errs, "; "))
+		}
+	}
+	sum, ok := c.index[fileName]
+	if !ok {
+		return "", ErrChecksumNotListed
+	}
+	return sum, nil
+}
+
+// IsOfficialAPI 判断 api 是否指向 go.dev 或 golang.google.cn 的官方 JSON API（忽略查询参数），空串视为默认的 go.dev。
+func IsOfficialAPI(api string) bool {
+	if strings.TrimSpace(api) == "" {
+		return true
+	}
+	trim := func(u string) string {
+		u, _, _ = strings.Cut(strings.TrimSpace(u), "?")
+		return strings.TrimSuffix(u, "/")
+	}
+	return trim(api) == trim(officialAPI) || trim(api) == trim(officialCNAPI)
+}
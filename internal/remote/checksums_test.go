@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// fakeSource 为返回固定版本列表或错误的版本源，并记录调用次数。
+type fakeSource struct {
+	versions []models.Version
+	err      error
+	calls    int
+}
+
+func (f *fakeSource) FetchVersions(context.Context) ([]models.Version, error) {
+	f.calls++
+	return f.versions, f.err
+}
+
+func TestChecksumIndexFallsBackAndCaches(t *testing.T) {
+	down := &fakeSource{err: errors.New("go.dev unreachable")}
+	cn := &fakeSource{versions: []models.Version{{FileName: "go1.22.0.linux-amd64.tar.gz", Checksum: "ABCDEF"}}}
+	idx := NewChecksumIndexFrom(down, cn)
+
+	sum, err := idx.Checksum("go1.22.0.linux-amd64.tar.gz")
+	if err != nil || sum != "abcdef" {
+		t.Fatalf("Checksum = %q, %v; want abcdef", sum, err)
+	}
+	if _, err := idx.Checksum("go1.99.0.linux-amd64.tar.gz"); !errors.Is(err, ErrChecksumNotListed) {
+		t.Fatalf("unlisted archive error = %v, want ErrChecksumNotListed", err)
+	}
+	if down.calls != 1 || cn.calls != 1 {
+		t.Fatalf("sources fetched %d/%d times, want once each", down.calls, cn.calls)
+	}
+}
+
+func TestChecksumIndexAllSourcesFail(t *testing.T) {
+	idx := NewChecksumIndexFrom(&fakeSource{err: errors.New("a")}, &fakeSource{err: errors.New("b")})
+	if _, err := idx.Checksum("go.tar.gz"); err == nil || errors.Is(err, ErrChecksumNotListed) {
+		t.Fatalf("Checksum error = %v, want unavailable error", err)
+	}
+}
+
+func TestIsOfficialAPI(t *testing.T) {
+	for api, want := range map[string]bool{
+		"":                                true,
+		"https://go.dev/dl/?mode=json":    true,
+		"https://golang.google.cn/dl/":    true,
+		"https://mirrors.example.com/dl/": false,
+		"https://go.dev.example.com/dl/":  false,
+	} {
+		if got := IsOfficialAPI(api); got != want {
+			t.Errorf("IsOfficialAPI(%q) = %v, want %v", api, got, want)
+		}
+	}
+}
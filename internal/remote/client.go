@@ -1,16 +1,18 @@
 package remote
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/liangyou/govm/pkg/goversion"
 	"github.com/liangyou/govm/pkg/models"
 )
 
@@ -26,9 +28,9 @@ var supportedArch = map[string]struct{}{
 	"386":   {},
 }
 
-// RemoteClient 定义远程版本源应具备的能力。
+// RemoteClient 定义远程版本源应具备的能力，ctx 结束时应中断进行中的请求。
 type RemoteClient interface {
-	FetchVersions() ([]models.Version, error)
+	FetchVersions(ctx context.Context) ([]models.Version, error)
 }
 
 // HTTPClient 描述最小化的 HTTP 客户端接口，方便测试时替换。
@@ -39,15 +41,31 @@ type HTTPClient interface {
 // Option 用于配置 Client。
 type Option func(*Client)
 
-// WithBaseURL 设置自定义远程源地址。
+// WithBaseURL 设置自定义远程源地址，JSON API 地址会被补齐 include=all。
 func WithBaseURL(base string) Option {
 	return func(c *Client) {
 		if base != "" {
-			c.baseURL = base
+			c.baseURL = ensureIncludeAll(base)
 		}
 	}
 }
 
+// ensureIncludeAll 为 go.dev 风格的 JSON API 地址补齐 include=all，
+// 否则接口只返回最近两个受支持的系列，不同镜像的结果会不一致。
+func ensureIncludeAll(base string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	q := u.Query()
+	if q.Get("mode") != "json" || q.Get("include") == "all" {
+		return base
+	}
+	q.Set("include", "all")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 // WithHTTPClient 设置 HTTP 客户端。
 func WithHTTPClient(h HTTPClient) Option {
 	return func(c *Client) {
@@ -86,6 +104,10 @@ type Client struct {
 	cacheTTL   time.Duration
 	// downloadBase 用于拼接安装包下载 URL。
 	downloadBase string
+	// cacheDir 为空时不使用磁盘缓存。
+	cacheDir string
+	onStale  StaleFunc
+	now      func() time.Time
 
 	mu       sync.Mutex
 	cached   []models.Version
@@ -99,6 +121,7 @@ func NewClient(opts ...Option) *Client {
 		httpClient:   http.DefaultClient,
 		cacheTTL:     defaultCacheTTL,
 		downloadBase: defaultDownloadBase,
+		now:          time.Now,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -107,48 +130,97 @@ func NewClient(opts ...Option) *Client {
 }
 
 // FetchVersions 获取远程可用版本并进行过滤与排序。
-func (c *Client) FetchVersions() ([]models.Version, error) {
+func (c *Client) FetchVersions(ctx context.Context) ([]models.Version, error) {
 	if versions, ok := c.getCached(); ok {
 		return versions, nil
 	}
 
-	req, err := http.NewRequest(http.MethodGet, c.baseURL, nil)
+	cached := c.loadDiskCache()
+	if cached != nil && c.cacheTTL > 0 && c.now().Sub(cached.FetchedAt) <= c.cacheTTL {
+		if versions, err := c.parseVersions(cached.Body); err == nil {
+			c.setCache(versions)
+			return versions, nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("remote: build request: %w", err)
 	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("remote: request failed: %w", err)
+		// 调用方取消不是版本源故障，不退回过期缓存。
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("remote: request failed: %w", ctx.Err())
+		}
+		return c.staleFallback(cached, fmt.Errorf("remote: request failed: %w", err))
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	var body []byte
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		body = cached.Body
+	case resp.StatusCode == http.StatusOK:
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return c.staleFallback(cached, fmt.Errorf("remote: read body: %w", err))
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return c.staleFallback(cached, fmt.Errorf("remote: unexpected status %d", resp.StatusCode))
+	default:
 		return nil, fmt.Errorf("remote: unexpected status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("remote: read body: %w", err)
-	}
-
 	versions, err := c.parseVersions(body)
 	if err != nil {
 		return nil, err
 	}
 
+	entry := diskCache{URL: c.baseURL, FetchedAt: c.now().UTC(), Body: body}
+	entry.ETag = resp.Header.Get("ETag")
+	entry.LastModified = resp.Header.Get("Last-Modified")
+	if resp.StatusCode == http.StatusNotModified {
+		// 304 可能省略校验头，沿用缓存中的值。
+		if entry.ETag == "" {
+			entry.ETag = cached.ETag
+		}
+		if entry.LastModified == "" {
+			entry.LastModified = cached.LastModified
+		}
+	}
+	_ = c.saveDiskCache(entry)
+
 	c.setCache(versions)
 	return versions, nil
 }
 
 func (c *Client) parseVersions(data []byte) ([]models.Version, error) {
+	return parseReleases(data, c.downloadBase)
+}
+
+// parseReleases 解析 go.dev JSON 格式的版本列表，官方源与静态清单共用。
+func parseReleases(data []byte, downloadBase string) ([]models.Version, error) {
 	var releases []release
 	if err := json.Unmarshal(data, &releases); err != nil {
 		return nil, fmt.Errorf("remote: decode response: %w", err)
 	}
 
 	var versions []models.Version
+	notStable := map[string]bool{}
 	for _, rel := range releases {
+		if rel.Stable != nil && !*rel.Stable {
+			notStable[rel.Version] = true
+		}
 		for _, file := range rel.Files {
 			if !shouldInclude(file) {
 				continue
@@ -156,8 +228,9 @@ func (c *Client) parseVersions(data []byte) ([]models.Version, error) {
 			versions = append(versions, models.Version{
 				Number:      strings.TrimPrefix(rel.Version, "go"),
 				FullName:    rel.Version,
-				DownloadURL: c.downloadBase + file.Filename,
+				DownloadURL: downloadBase + file.Filename,
 				FileName:    file.Filename,
+				Size:        file.Size,
 				Checksum:    file.Checksum,
 				OS:          file.OS,
 				Arch:        file.Arch,
@@ -165,15 +238,43 @@ func (c *Client) parseVersions(data []byte) ([]models.Version, error) {
 		}
 	}
 
+	sortVersions(versions)
+	assignChannels(versions, notStable)
+	return versions, nil
+}
+
+// EncodeReleases 把版本列表编码为 go.dev JSON 格式，parseReleases 可以原样读回，用于离线安装包中的版本列表快照。
+func EncodeReleases(versions []models.Version) ([]byte, error) {
+	var releases []release
+	index := map[string]int{}
+	for _, v := range versions {
+		i, ok := index[v.FullName]
+		if !ok {
+			i = len(releases)
+			index[v.FullName] = i
+			releases = append(releases, release{Version: v.FullName, Stable: stableFlag(v.Channel)})
+		}
+		releases[i].Files = append(releases[i].Files, releaseFile{
+			Filename: v.FileName,
+			OS:       v.OS,
+			Arch:     v.Arch,
+			Checksum: v.Checksum,
+			Size:     v.Size,
+			Kind:     "archive",
+		})
+	}
+	return json.Marshal(releases)
+}
+
+// sortVersions 按版本号降序排列，同版本按架构名排序。
+func sortVersions(versions []models.Version) {
 	sort.SliceStable(versions, func(i, j int) bool {
-		cmp := compareVersionStrings(versions[i].FullName, versions[j].FullName)
+		cmp := goversion.Compare(versions[i].FullName, versions[j].FullName)
 		if cmp == 0 {
 			return versions[i].Arch < versions[j].Arch
 		}
 		return cmp > 0
 	})
-
-	return versions, nil
 }
 
 func shouldInclude(f releaseFile) bool {
@@ -212,6 +313,7 @@ func (c *Client) setCache(versions []models.Version) {
 // release 表示 Go 官方 API 中的版本记录。
 type release struct {
 	Version string        `json:"version"`
+	Stable  *bool         `json:"stable,omitempty"` // 旧的静态清单可能没有该字段
 	Files   []releaseFile `json:"files"`
 }
 
@@ -221,122 +323,6 @@ type releaseFile struct {
 	OS       string `json:"os"`
 	Arch     string `json:"arch"`
 	Checksum string `json:"sha256"`
+	Size     int64  `json:"size"`
 	Kind     string `json:"kind"`
 }
-
-// compareVersionStrings 比较两个 go 版本号，返回 1 表示 a>b。
-func compareVersionStrings(a, b string) int {
-	pa := normalizeVersion(a)
-	pb := normalizeVersion(b)
-
-	if pa.major != pb.major {
-		return cmpInt(pa.major, pb.major)
-	}
-	if pa.minor != pb.minor {
-		return cmpInt(pa.minor, pb.minor)
-	}
-	if pa.patch != pb.patch {
-		return cmpInt(pa.patch, pb.patch)
-	}
-
-	return comparePrerelease(pa, pb)
-}
-
-func cmpInt(a, b int) int {
-	switch {
-	case a > b:
-		return 1
-	case a < b:
-		return -1
-	default:
-		return 0
-	}
-}
-
-var prereleaseRank = map[string]int{
-	"":     3,
-	"rc":   2,
-	"beta": 1,
-}
-
-func comparePrerelease(a, b versionParts) int {
-	if a.prerelease == b.prerelease {
-		return cmpInt(a.prereleaseNum, b.prereleaseNum)
-	}
-
-	ra := prereleaseRank[a.prerelease]
-	rb := prereleaseRank[b.prerelease]
-
-	return cmpInt(ra, rb)
-}
-
-func normalizeVersion(v string) versionParts {
-	trimmed := strings.TrimPrefix(v, "go")
-	parts := strings.Split(trimmed, ".")
-
-	result := versionParts{prerelease: "", prereleaseNum: 0}
-
-	if len(parts) > 0 {
-		result.major = parseInt(parts[0])
-	}
-	if len(parts) > 1 {
-		minor, suffix := parseNumericPrefix(parts[1])
-		result.minor = minor
-		if suffix != "" {
-			setPrerelease(&result, suffix)
-			return result
-		}
-	}
-	if len(parts) > 2 {
-		patch, suffix := parseNumericPrefix(parts[2])
-		result.patch = patch
-		if suffix != "" {
-			setPrerelease(&result, suffix)
-		}
-	}
-
-	return result
-}
-
-func parseInt(value string) int {
-	n, err := strconv.Atoi(value)
-	if err != nil {
-		return 0
-	}
-	return n
-}
-
-func parseNumericPrefix(input string) (int, string) {
-	idx := 0
-	for idx < len(input) && input[idx] >= '0' && input[idx] <= '9' {
-		idx++
-	}
-	if idx == 0 {
-		return 0, input
-	}
-	value := parseInt(input[:idx])
-	return value, input[idx:]
-}
-
-func setPrerelease(parts *versionParts, suffix string) {
-	idx := 0
-	for idx < len(suffix) && (suffix[idx] < '0' || suffix[idx] > '9') {
-		idx++
-	}
-	label := suffix[:idx]
-	num := 0
-	if idx < len(suffix) {
-		num = parseInt(suffix[idx:])
-	}
-
-	parts.prerelease = label
-	parts.prereleaseNum = num
-}
-
-type versionParts struct {
-	major         int
-	minor         int
-	patch         int
-	prerelease    string
-	prereleaseNum int
-}
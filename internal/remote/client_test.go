@@ -1,9 +1,11 @@
 package remote
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -43,7 +45,7 @@ func TestFetchVersionsFiltersAndSorts(t *testing.T) {
 		WithCacheTTL(time.Minute),
 	)
 
-	versions, err := client.FetchVersions()
+	versions, err := client.FetchVersions(context.Background())
 	if err != nil {
 		t.Fatalf("FetchVersions error: %v", err)
 	}
@@ -66,6 +68,58 @@ func TestFetchVersionsFiltersAndSorts(t *testing.T) {
 	}
 }
 
+func TestParseReleasesAssignsChannels(t *testing.T) {
+	t.Parallel()
+
+	yes, no := true, false
+	file := func(v string) []releaseFile {
+		return []releaseFile{{Filename: v + ".linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: "archive"}}
+	}
+	releases := []release{
+		{Version: "go1.23rc1", Stable: &no, Files: file("go1.23rc1")},
+		{Version: "go1.22.3", Stable: &yes, Files: file("go1.22.3")},
+		{Version: "go1.22.2", Stable: &no, Files: file("go1.22.2")},
+		{Version: "go1.21.10", Stable: &yes, Files: file("go1.21.10")},
+		{Version: "go1.20.14", Files: file("go1.20.14")},
+		{Version: "go1.21beta1", Files: file("go1.21beta1")},
+	}
+	data, err := json.Marshal(releases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := parseReleases(data, "https://go.dev/dl/")
+	if err != nil {
+		t.Fatalf("parseReleases: %v", err)
+	}
+	want := map[string]string{
+		"1.23rc1":   "rc",
+		"1.22.3":    "stable",
+		"1.22.2":    "archived",
+		"1.21.10":   "stable",
+		"1.21beta1": "beta",
+		"1.20.14":   "archived",
+	}
+	for _, v := range versions {
+		if v.Channel != want[v.Number] {
+			t.Errorf("go%s channel = %q, want %q", v.Number, v.Channel, want[v.Number])
+		}
+	}
+
+	encoded, err := EncodeReleases(versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := parseReleases(encoded, "https://go.dev/dl/")
+	if err != nil || len(again) != len(versions) {
+		t.Fatalf("round trip: %v", err)
+	}
+	for i := range again {
+		if again[i].Channel != versions[i].Channel {
+			t.Fatalf("channel of go%s lost in EncodeReleases: %q", again[i].Number, again[i].Channel)
+		}
+	}
+}
+
 func TestFetchVersionsHandlesHTTPError(t *testing.T) {
 	t.Parallel()
 
@@ -80,7 +134,7 @@ func TestFetchVersionsHandlesHTTPError(t *testing.T) {
 		WithCacheTTL(time.Minute),
 	)
 
-	if _, err := client.FetchVersions(); err == nil {
+	if _, err := client.FetchVersions(context.Background()); err == nil {
 		t.Fatal("expected error for non-200 status")
 	}
 }
@@ -106,7 +160,7 @@ func TestFetchVersionsUsesCache(t *testing.T) {
 	)
 
 	for i := 0; i < 2; i++ {
-		versions, err := client.FetchVersions()
+		versions, err := client.FetchVersions(context.Background())
 		if err != nil {
 			t.Fatalf("FetchVersions error: %v", err)
 		}
@@ -120,28 +174,6 @@ func TestFetchVersionsUsesCache(t *testing.T) {
 	}
 }
 
-func TestCompareVersionStrings(t *testing.T) {
-	t.Parallel()
-
-	cases := []struct {
-		a, b string
-		want int
-	}{
-		{"go1.21.1", "go1.21", 1},
-		{"go1.21", "go1.21rc1", 1},
-		{"go1.21rc2", "go1.21rc1", 1},
-		{"go1.21beta1", "go1.21rc1", -1},
-		{"go1.20.5", "go1.21beta1", -1},
-		{"go1.20", "go1.20", 0},
-	}
-
-	for _, tc := range cases {
-		if got := compareVersionStrings(tc.a, tc.b); got != tc.want {
-			t.Fatalf("compareVersionStrings(%s,%s)=%d want %d", tc.a, tc.b, got, tc.want)
-		}
-	}
-}
-
 func TestFetchVersionsUsesCustomDownloadBase(t *testing.T) {
 	t.Parallel()
 
@@ -167,7 +199,7 @@ func TestFetchVersionsUsesCustomDownloadBase(t *testing.T) {
 		WithDownloadBase("https://mirror.example.com/go"),
 	)
 
-	versions, err := client.FetchVersions()
+	versions, err := client.FetchVersions(context.Background())
 	if err != nil {
 		t.Fatalf("FetchVersions error: %v", err)
 	}
@@ -183,3 +215,130 @@ func TestFetchVersionsUsesCustomDownloadBase(t *testing.T) {
 
 // compile-time检查，确保 Client 满足 RemoteClient 接口
 var _ RemoteClient = (*Client)(nil)
+
+func TestFetchVersionsConditionalRefresh(t *testing.T) {
+	t.Parallel()
+
+	releases := []release{{Version: "go1.22.0", Files: []releaseFile{
+		{Filename: "go1.22.0.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Checksum: "abc", Kind: "archive"},
+	}}}
+	var full, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Tue, 06 Feb 2024 18:00:00 GMT")
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	now := time.Date(2024, 2, 7, 0, 0, 0, 0, time.UTC)
+	newClient := func() *Client {
+		c := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCacheTTL(time.Minute), WithCacheDir(cacheDir))
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	if _, err := newClient().FetchVersions(context.Background()); err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	// 新进程在 TTL 内直接读取磁盘缓存，不发请求。
+	if _, err := newClient().FetchVersions(context.Background()); err != nil {
+		t.Fatalf("cached fetch: %v", err)
+	}
+	if full != 1 || notModified != 0 {
+		t.Fatalf("expected only one request so far, got full=%d notModified=%d", full, notModified)
+	}
+
+	now = now.Add(time.Hour)
+	versions, err := newClient().FetchVersions(context.Background())
+	if err != nil {
+		t.Fatalf("revalidated fetch: %v", err)
+	}
+	if full != 1 || notModified != 1 {
+		t.Fatalf("expected conditional request, got full=%d notModified=%d", full, notModified)
+	}
+	if len(versions) != 1 || versions[0].Number != "1.22.0" {
+		t.Fatalf("unexpected versions from 304: %#v", versions)
+	}
+}
+
+func TestFetchVersionsFallsBackToStaleCache(t *testing.T) {
+	t.Parallel()
+
+	releases := []release{{Version: "go1.22.0", Files: []releaseFile{
+		{Filename: "go1.22.0.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Checksum: "abc", Kind: "archive"},
+	}}}
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(releases)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	var staleCalls int
+	newClient := func() *Client {
+		return NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCacheTTL(time.Nanosecond),
+			WithCacheDir(cacheDir), WithStaleHandler(func(error, time.Time) { staleCalls++ }))
+	}
+	if _, err := newClient().FetchVersions(context.Background()); err != nil {
+		t.Fatalf("initial fetch: %v", err)
+	}
+
+	down.Store(true)
+	versions, err := newClient().FetchVersions(context.Background())
+	if err != nil {
+		t.Fatalf("expected stale cache fallback, got %v", err)
+	}
+	if len(versions) != 1 || staleCalls != 1 {
+		t.Fatalf("unexpected fallback result: %d versions, %d stale calls", len(versions), staleCalls)
+	}
+
+	empty := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithCacheDir(t.TempDir()))
+	if _, err := empty.FetchVersions(context.Background()); err == nil {
+		t.Fatal("expected error without any cache")
+	}
+}
+
+func TestEnsureIncludeAllAndOldReleases(t *testing.T) {
+	t.Parallel()
+
+	if got := ensureIncludeAll("https://mirror.example.com/dl/?mode=json"); got != "https://mirror.example.com/dl/?include=all&mode=json" {
+		t.Fatalf("include=all not added: %s", got)
+	}
+	if got := ensureIncludeAll(defaultBaseURL); got != defaultBaseURL {
+		t.Fatalf("url with include=all should be unchanged: %s", got)
+	}
+	if got := ensureIncludeAll("file:///srv/catalog.json"); got != "file:///srv/catalog.json" {
+		t.Fatalf("non-API url should be unchanged: %s", got)
+	}
+
+	releases := []release{
+		{Version: "go1.5", Files: []releaseFile{{Filename: "go1.5.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Checksum: "a", Kind: "archive"}}},
+		{Version: "go1.10", Files: []releaseFile{{Filename: "go1.10.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Checksum: "b", Kind: "archive"}}},
+		{Version: "go1.5.4", Files: []releaseFile{{Filename: "go1.5.4.linux-386.tar.gz", OS: "linux", Arch: "386", Checksum: "c", Kind: "archive"}}},
+	}
+	data, err := json.Marshal(releases)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	versions, err := parseReleases(data, defaultDownloadBase)
+	if err != nil {
+		t.Fatalf("parseReleases: %v", err)
+	}
+	want := []string{"1.10", "1.5.4", "1.5"}
+	for i, v := range versions {
+		if v.Number != want[i] {
+			t.Fatalf("unexpected order at %d: %s", i, v.Number)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/pkg/goversion"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+const (
+	defaultNotesURL = "https://go.dev/doc/devel/release"
+	notesCacheFile  = "release-notes.json"
+	// notesCacheTTL 内直接使用缓存；小版本大约每月发布一次，一天的延迟可以接受。
+	notesCacheTTL = 24 * time.Hour
+)
+
+var (
+	// noteEntryRe 匹配发布历史中的版本条目：主版本为 <h2 id="go1.22.0">，小版本为 <p id="go1.22.1">。
+	noteEntryRe = regexp.MustCompile(`(?s)<(h2|p)\s+id="go(\d+(?:\.\d+)*)"[^>]*>(.*?)</(?:h2|p)>`)
+	paragraphRe = regexp.MustCompile(`(?s)<p>(.*?)</p>`)
+	tagRe       = regexp.MustCompile(`<[^>]+>`)
+	releasedRe  = regexp.MustCompile(`\(released (\d{4}-\d{2}-\d{2})\)`)
+)
+
+// notesCache 为发布说明的磁盘缓存。
+type notesCache struct {
+	URL       string               `json:"url"`
+	FetchedAt time.Time            `json:"fetchedAt"`
+	Notes     []models.ReleaseNote `json:"notes"`
+}
+
+// ReleaseNotes 从 go.dev 的发布历史页面读取各版本的说明摘要，解析结果缓存到磁盘，离线时也可查看。
+type ReleaseNotes struct {
+	url        string
+	httpClient HTTPClient
+	cacheDir   string
+	onStale    StaleFunc
+	now        func() time.Time
+}
+
+// NewReleaseNotes 创建读取官方发布历史的 ReleaseNotes；cacheDir 为空时不缓存，onStale 在改用过期缓存时被调用。
+func NewReleaseNotes(h HTTPClient, cacheDir string, onStale StaleFunc) *ReleaseNotes {
+	return &ReleaseNotes{url: defaultNotesURL, httpClient: orDefaultClient(h), cacheDir: cacheDir, onStale: onStale, now: time.Now}
+}
+
+// ReleaseNotes 返回全部版本的说明摘要：缓存未过期时直接使用，offline 时只读缓存，刷新失败时退回过期缓存。
+func (n *ReleaseNotes) ReleaseNotes(offline bool) ([]models.ReleaseNote, error) {
+	cached := n.loadCache()
+	if cached != nil && (offline || n.now().Sub(cached.FetchedAt) <= notesCacheTTL) {
+		return cached.Notes, nil
+	}
+	if offline {
+		return nil, errors.New("remote: release notes are not cached yet")
+	}
+	data, err := httpGet(context.Background(), n.httpClient, n.url)
+	if err == nil {
+		var notes []models.ReleaseNote
+		if notes, err = parseReleaseNotes(string(data)); err == nil {
+			// 缓存只用于加速与离线查看，写入失败不影响本次结果。
+			_ = n.saveCache(notesCache{URL: n.url, FetchedAt: n.now(), Notes: notes})
+			return notes, nil
+		}
+	}
+	if cached == nil {
+		return nil, fmt.Errorf("remote: fetch release notes: %w", err)
+	}
+	if n.onStale != nil {
+		n.onStale(err, cached.FetchedAt)
+	}
+	return cached.Notes, nil
+}
+
+func (n *ReleaseNotes) cachePath() string {
+	if n.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(n.cacheDir, notesCacheFile)
+}
+
+// loadCache 读取与当前地址匹配的缓存，缺失或损坏时返回 nil。
+func (n *ReleaseNotes) loadCache() *notesCache {
+	path := n.cachePath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry notesCache
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != n.url || len(entry.Notes) == 0 {
+		return nil
+	}
+	return &entry
+}
+
+func (n *ReleaseNotes) saveCache(entry notesCache) error {
+	path := n.cachePath()
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "release-notes-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// parseReleaseNotes 解析发布历史页面。主版本的摘要取标题后的第一段，小版本去掉开头的版本与日期
+// 以及结尾指向 issue tracker 的 "See the ..." 句子。
+func parseReleaseNotes(page string) ([]models.ReleaseNote, error) {
+	matches := noteEntryRe.FindAllStringSubmatchIndex(page, -1)
+	var notes []models.ReleaseNote
+	for i, m := range matches {
+		tag, number, body := page[m[2]:m[3]], page[m[4]:m[5]], page[m[6]:m[7]]
+		text := plainText(body)
+		note := models.ReleaseNote{Version: number}
+		if d := releasedRe.FindStringSubmatch(text); d != nil {
+			note.Date = d[1]
+		}
+		if tag == "h2" {
+			next := len(page)
+			if i+1 < len(matches) {
+				next = matches[i+1][0]
+			}
+			if p := paragraphRe.FindStringSubmatch(page[m[1]:next]); p != nil {
+				note.Summary = plainText(p[1])
+			}
+			note.URL = "https://go.dev/doc/go" + goversion.Series(number)
+		} else {
+			if _, rest, ok := strings.Cut(text, ") "); ok && note.Date != "" {
+				text = rest
+			}
+			if before, _, ok := strings.Cut(text, " See the "); ok {
+				text = before
+			}
+			if text != "" {
+				text = strings.ToUpper(text[:1]) + text[1:]
+			}
+			note.Summary = text
+			note.URL = defaultNotesURL + "#go" + number
+		}
+		note.Security = strings.Contains(strings.ToLower(note.Summary), "security fix")
+		notes = append(notes, note)
+	}
+	if len(notes) == 0 {
+		return nil, errors.New("remote: no releases found in the release history page")
+	}
+	return notes, nil
+}
+
+// plainText 去掉 HTML 标签与实体并合并空白。
+func plainText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(tagRe.ReplaceAllString(s, ""))), " ")
+}
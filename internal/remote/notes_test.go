@@ -0,0 +1,81 @@
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const releaseHistory = `<h2 id="go1.22.0">go1.22.0 (released 2024-02-06)</h2>
+<p>
+Go 1.22 is a major release of Go.
+Read the <a href="/doc/go1.22">Go 1.22 Release Notes</a> for more information.
+</p>
+<h3 id="go1.22.minor">Minor revisions</h3>
+<p id="go1.22.1">
+go1.22.1 (released 2024-03-05) includes security fixes to the <code>crypto/x509</code>
+and <code>net/mail</code> packages, as well as bug fixes to the compiler &amp; the runtime.
+See the <a href="https://github.com/golang/go/issues?q=milestone%3AGo1.22.1">Go
+1.22.1 milestone</a> on our issue tracker for details.
+</p>
+<h2 id="go1.21.0">go1.21.0 (released 2023-08-08)</h2>
+<p>Go 1.21 is a major release of Go.</p>
+<p id="go1.21.8">go1.21.8 (released 2024-03-05) includes bug fixes to the go command.</p>
+`
+
+func TestReleaseNotesParsesAndCaches(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(releaseHistory))
+	}))
+	defer server.Close()
+
+	var stale error
+	notes := NewReleaseNotes(server.Client(), t.TempDir(), func(err error, _ time.Time) { stale = err })
+	notes.url = server.URL
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	notes.now = func() time.Time { return now }
+
+	if _, err := notes.ReleaseNotes(true); err == nil {
+		t.Fatal("offline lookup without a cache must fail")
+	}
+	got, err := notes.ReleaseNotes(false)
+	if err != nil {
+		t.Fatalf("ReleaseNotes: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 releases, got %#v", got)
+	}
+	major, minor := got[0], got[1]
+	if major.Version != "1.22.0" || major.Date != "2024-02-06" || major.URL != "https://go.dev/doc/go1.22" ||
+		major.Summary != "Go 1.22 is a major release of Go. Read the Go 1.22 Release Notes for more information." {
+		t.Fatalf("unexpected major release: %#v", major)
+	}
+	if minor.Version != "1.22.1" || !minor.Security || !strings.HasPrefix(minor.Summary, "Includes security fixes to the crypto/x509") ||
+		!strings.HasSuffix(minor.Summary, "the compiler & the runtime.") {
+		t.Fatalf("unexpected minor release: %#v", minor)
+	}
+	if got[3].Security {
+		t.Fatalf("bug-fix release marked as security: %#v", got[3])
+	}
+
+	if _, err := notes.ReleaseNotes(false); err != nil || hits.Load() != 1 {
+		t.Fatalf("fresh cache must be reused: %v, %d requests", err, hits.Load())
+	}
+	fail.Store(true)
+	now = now.Add(48 * time.Hour)
+	if got, err := notes.ReleaseNotes(false); err != nil || len(got) != 4 || stale == nil {
+		t.Fatalf("expected the stale cache after a failed refresh: %v, %v", err, stale)
+	}
+}
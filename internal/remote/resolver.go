@@ -0,0 +1,88 @@
+package remote
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// versionPattern 匹配可按官方命名规则推导归档文件名的版本号。
+var versionPattern = regexp.MustCompile(`^\d+\.\d+(?:\.\d+)?(?:(?:rc|beta)\d+)?$`)
+
+// artifactPattern 匹配官方命名的归档文件名，例如 go1.22.3.linux-amd64.tar.gz。
+var artifactPattern = regexp.MustCompile(`^go(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta)\d+)?)\.([a-z0-9]+)-([a-z0-9]+)\.tar\.gz$`)
+
+// URLResolver 在无法获取版本目录时按 go<ver>.<os>-<arch>.tar.gz 规则推导下载地址，
+// 校验值由下载器从同名 .sha256 文件获取。
+type URLResolver struct {
+	downloadBase string
+	goos         string
+	goarch       string
+}
+
+// NewURLResolver 创建面向当前平台的 URLResolver。
+func NewURLResolver(downloadBase string) *URLResolver {
+	return &URLResolver{
+		downloadBase: withSlash(orDefault(downloadBase, defaultDownloadBase)),
+		goos:         runtime.GOOS,
+		goarch:       runtime.GOARCH,
+	}
+}
+
+// Resolve 推导指定版本的下载信息，number 不带 go 前缀。
+func (r *URLResolver) Resolve(number string) (models.Version, error) {
+	number = strings.TrimPrefix(strings.TrimSpace(number), "go")
+	if !versionPattern.MatchString(number) {
+		return models.Version{}, fmt.Errorf("remote: cannot derive download URL for version %q", number)
+	}
+	if _, ok := supportedArch[r.goarch]; !ok || r.goos != "linux" {
+		return models.Version{}, fmt.Errorf("remote: unsupported platform %s/%s", r.goos, r.goarch)
+	}
+	fileName := fmt.Sprintf("go%s.%s-%s.tar.gz", number, r.goos, r.goarch)
+	return models.Version{
+		Number:      number,
+		FullName:    "go" + number,
+		DownloadURL: r.downloadBase + fileName,
+		FileName:    fileName,
+		ChecksumURL: r.downloadBase + fileName + ".sha256",
+		OS:          r.goos,
+		Arch:        r.goarch,
+	}, nil
+}
+
+// ResolveArtifact 解析归档文件名或完整 URL：文件名相对于下载地址，URL 原样使用。
+// 任意 URL 旁的 .sha256 并不可信，返回值不带 ChecksumURL，校验值需由调用方提供。
+func (r *URLResolver) ResolveArtifact(ref string) (models.Version, error) {
+	ref = strings.TrimSpace(ref)
+	name, downloadURL := ref, ""
+	if strings.Contains(ref, "://") {
+		u, err := url.Parse(ref)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return models.Version{}, fmt.Errorf("remote: invalid artifact URL %q", ref)
+		}
+		name, downloadURL = path.Base(u.Path), ref
+	}
+	m := artifactPattern.FindStringSubmatch(name)
+	if m == nil {
+		return models.Version{}, fmt.Errorf("remote: %q is not a Go release archive (want go<version>.<os>-<arch>.tar.gz)", name)
+	}
+	if m[2] != r.goos || m[3] != r.goarch {
+		return models.Version{}, fmt.Errorf("remote: %s is built for %s/%s, this machine is %s/%s", name, m[2], m[3], r.goos, r.goarch)
+	}
+	if downloadURL == "" {
+		downloadURL = r.downloadBase + name
+	}
+	return models.Version{
+		Number:      m[1],
+		FullName:    "go" + m[1],
+		DownloadURL: downloadURL,
+		FileName:    name,
+		OS:          m[2],
+		Arch:        m[3],
+	}, nil
+}
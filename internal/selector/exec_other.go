@@ -0,0 +1,25 @@
+//go:build !unix
+
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// execTool 在不支持替换进程的平台上以子进程执行 bin 并转发其退出码。
+func execTool(bin string, args, env []string) (int, error) {
+	cmd := exec.Command(bin, args...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, fmt.Errorf("govm: run %s: %w", bin, err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package selector
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// execTool 以 bin 替换当前进程，信号与退出码因此直接属于真实的工具；只有替换失败时才会返回。
+func execTool(bin string, args, env []string) (int, error) {
+	if err := syscall.Exec(bin, append([]string{bin}, args...), env); err != nil {
+		return 1, fmt.Errorf("govm: exec %s: %w", bin, err)
+	}
+	return 0, nil
+}
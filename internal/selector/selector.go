@@ -0,0 +1,155 @@
+// Package selector 实现 govm 以 go、gofmt 之名被调用时的版本选择：每次执行时按 GOVM_VERSION、项目的
+// .govm-version、默认版本的顺序解析版本，再执行该版本的真实工具，不依赖 shell 中的 GOROOT 与 PATH。
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/liangyou/govm/internal/config"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// Tools 为 govm 以其名称被调用时充当选择器的工具。
+var Tools = []string{"go", "gofmt"}
+
+// SourceDefault 为 Selection.Source 取自默认版本时的值。
+const SourceDefault = "default"
+
+// Store 为选择器读取的存储。
+type Store interface {
+	GetCurrentVersionMarker() (string, error)
+	LoadMetadata() ([]models.Version, error)
+}
+
+// Selection 为解析出的版本。
+type Selection struct {
+	Number string
+	// Source 为版本来源：环境变量名 GOVM_VERSION、.govm-version 文件路径或 SourceDefault。
+	Source string
+}
+
+// Handles 报告以 name 调用的 govm 是否应充当选择器；name 可以带 .exe 后缀。
+func Handles(name string) bool {
+	return slices.Contains(Tools, strings.TrimSuffix(name, ".exe"))
+}
+
+// Resolve 按优先级解析在 dir 中应使用的版本：GOVM_VERSION、从 dir 向上找到的 .govm-version，最后是默认版本。
+func Resolve(dir string, getenv func(string) string, store Store) (Selection, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	if v := strings.TrimSpace(getenv("GOVM_VERSION")); v != "" {
+		return Selection{Number: strings.TrimPrefix(v, "go"), Source: "GOVM_VERSION"}, nil
+	}
+	number, path, err := version.FindPin(dir)
+	if err != nil {
+		return Selection{}, err
+	}
+	if number != "" {
+		return Selection{Number: number, Source: path}, nil
+	}
+	number, err = store.GetCurrentVersionMarker()
+	if err != nil {
+		return Selection{}, fmt.Errorf("govm: read current version: %w", err)
+	}
+	if number == "" {
+		return Selection{}, errors.New("govm: no Go version selected; run `govm use <version>` or `govm use --local <version>`")
+	}
+	return Selection{Number: number, Source: SourceDefault}, nil
+}
+
+// GoRoot 返回所选版本的 GOROOT：优先使用 runCache 中的缓存，未命中时查询元数据并写入缓存。
+func GoRoot(store Store, runCache string, sel Selection) (string, error) {
+	if sel.Number == "" || strings.ContainsAny(sel.Number, `/\`) {
+		return "", fmt.Errorf("govm: invalid version %q (from %s)", sel.Number, sel.Source)
+	}
+	if root := CachedRoot(runCache, sel.Number); root != "" {
+		return root, nil
+	}
+	versions, err := store.LoadMetadata()
+	if err != nil {
+		return "", fmt.Errorf("govm: load metadata: %w", err)
+	}
+	for _, v := range versions {
+		if v.Number == sel.Number && strings.TrimSpace(v.InstallPath) != "" {
+			SaveRoot(runCache, sel.Number, v.InstallPath)
+			return v.InstallPath, nil
+		}
+	}
+	return "", fmt.Errorf("govm: go%s (from %s) is not installed; run `govm install %s`", sel.Number, sel.Source, sel.Number)
+}
+
+// RunCacheDir 返回 cacheDir 下 `govm run` 与选择器共用的各版本 GOROOT 缓存目录。
+func RunCacheDir(cacheDir string) string {
+	return filepath.Join(cacheDir, "run")
+}
+
+// CachedRoot 读取缓存的 GOROOT，缓存缺失或其中的 go 已不存在时返回空。
+// 缓存文件为 <runCache>/<version>，内容只有 GOROOT 一行。
+func CachedRoot(runCache, number string) string {
+	if runCache == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(runCache, number))
+	if err != nil {
+		return ""
+	}
+	root := strings.TrimSpace(string(data))
+	if info, err := os.Stat(filepath.Join(root, "bin", "go")); err != nil || info.IsDir() {
+		return ""
+	}
+	return root
+}
+
+// SaveRoot 写入版本的 GOROOT 缓存；缓存只是加速，写入失败时忽略。
+func SaveRoot(runCache, number, root string) {
+	if runCache == "" || os.MkdirAll(runCache, 0o755) != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(runCache, number), []byte(root+"\n"), 0o644)
+}
+
+// Main 按 govm 的配置解析版本并执行其中的 tool，返回进程退出码。
+func Main(tool string, args []string) int {
+	code, err := run(strings.TrimSuffix(tool, ".exe"), args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+func run(tool string, args []string) (int, error) {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return 1, err
+	}
+	cfg, err = storage.ResolveDirs(cfg)
+	if err != nil {
+		return 1, err
+	}
+	store := storage.NewFileStorage(cfg)
+	dir, err := os.Getwd()
+	if err != nil {
+		return 1, fmt.Errorf("govm: %w", err)
+	}
+	sel, err := Resolve(dir, os.Getenv, store)
+	if err != nil {
+		return 1, err
+	}
+	root, err := GoRoot(store, RunCacheDir(store.CacheDir()), sel)
+	if err != nil {
+		return 1, err
+	}
+	env := append(os.Environ(), "GOROOT="+root)
+	if cfg.GoToolchain != "" && os.Getenv("GOTOOLCHAIN") == "" {
+		env = append(env, "GOTOOLCHAIN="+cfg.GoToolchain)
+	}
+	return execTool(filepath.Join(root, "bin", tool), args, env)
+}
@@ -0,0 +1,85 @@
+package selector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeStore struct {
+	current  string
+	versions []models.Version
+	loads    int
+}
+
+func (s *fakeStore) GetCurrentVersionMarker() (string, error) { return s.current, nil }
+
+func (s *fakeStore) LoadMetadata() ([]models.Version, error) {
+	s.loads++
+	return s.versions, nil
+}
+
+func TestResolvePrefersEnvThenPinThenDefault(t *testing.T) {
+	t.Parallel()
+
+	project := t.TempDir()
+	sub := filepath.Join(project, "cmd", "tool")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	store := &fakeStore{current: "1.22.0"}
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	if sel, err := Resolve(sub, getenv, store); err != nil || sel != (Selection{Number: "1.22.0", Source: SourceDefault}) {
+		t.Fatalf("expected the default version, got %#v, %v", sel, err)
+	}
+	pin := filepath.Join(project, ".govm-version")
+	if err := os.WriteFile(pin, []byte("go1.21.8\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if sel, err := Resolve(sub, getenv, store); err != nil || sel != (Selection{Number: "1.21.8", Source: pin}) {
+		t.Fatalf("expected the project pin, got %#v, %v", sel, err)
+	}
+	env["GOVM_VERSION"] = "go1.20.14"
+	if sel, _ := Resolve(sub, getenv, store); sel.Number != "1.20.14" || sel.Source != "GOVM_VERSION" {
+		t.Fatalf("GOVM_VERSION must win: %#v", sel)
+	}
+
+	if _, err := Resolve(t.TempDir(), func(string) string { return "" }, &fakeStore{}); err == nil || !strings.Contains(err.Error(), "no Go version selected") {
+		t.Fatalf("expected a missing version error, got %v", err)
+	}
+}
+
+func TestGoRootUsesRunCache(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "go1.22.0")
+	if err := os.MkdirAll(filepath.Join(root, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bin", "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cache := filepath.Join(t.TempDir(), "run")
+	store := &fakeStore{versions: []models.Version{{Number: "1.22.0", InstallPath: root}}}
+	sel := Selection{Number: "1.22.0", Source: SourceDefault}
+
+	for i := 0; i < 2; i++ {
+		got, err := GoRoot(store, cache, sel)
+		if err != nil || got != root {
+			t.Fatalf("GoRoot = %q, %v", got, err)
+		}
+	}
+	if store.loads != 1 {
+		t.Fatalf("second lookup must come from the cache, metadata loaded %d times", store.loads)
+	}
+
+	_, err := GoRoot(store, cache, Selection{Number: "1.21.8", Source: "/src/.govm-version"})
+	if err == nil || !strings.Contains(err.Error(), "go1.21.8 (from /src/.govm-version) is not installed") {
+		t.Fatalf("expected a not installed error naming the source, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/liangyou/govm/internal/events"
+)
+
+// metrics 汇总守护进程启动以来的计数器，计数来自事件总线与 API 调用，进程重启后归零。
+type metrics struct {
+	mu         sync.Mutex
+	installs   map[string]uint64 // 按 Done 事件的 status 分类
+	failures   uint64
+	bytes      int64
+	inFlight   map[string]int64 // 进行中的下载已计入的字节数，按版本
+	switches   uint64
+	uninstalls uint64
+}
+
+// newMetrics 创建 metrics 并订阅 bus 上的安装事件，bus 为 nil 时只统计 API 调用。
+func newMetrics(bus *events.Bus) *metrics {
+	m := &metrics{
+		installs: map[string]uint64{"installed": 0, "already_installed": 0},
+		inFlight: map[string]int64{},
+	}
+	bus.Subscribe(m.observe)
+	return m
+}
+
+// observe 根据安装事件更新计数器；下载字节数按进度事件中累计值的增量计入。
+func (m *metrics) observe(e events.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ver := e.Get("version")
+	switch e.Type {
+	case events.DownloadStart:
+		m.inFlight[ver] = 0
+	case events.DownloadProgress:
+		downloaded, err := strconv.ParseInt(e.Get("downloaded"), 10, 64)
+		if err != nil {
+			return
+		}
+		if delta := downloaded - m.inFlight[ver]; delta > 0 {
+			m.bytes += delta
+			m.inFlight[ver] = downloaded
+		}
+	case events.Done:
+		delete(m.inFlight, ver)
+		if status := e.Get("status"); status != "" {
+			m.installs[status]++
+		}
+	case events.Error:
+		delete(m.inFlight, ver)
+		m.failures++
+	}
+}
+
+func (m *metrics) countSwitch() {
+	m.mu.Lock()
+	m.switches++
+	m.mu.Unlock()
+}
+
+func (m *metrics) countUninstall() {
+	m.mu.Lock()
+	m.uninstalls++
+	m.mu.Unlock()
+}
+
+// handleMetrics 以 Prometheus 文本格式输出计数器，以及已安装版本数与当前版本的信息指标。
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	local, err := s.lister.LocalVersions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	current, err := s.lister.CurrentVersion()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var b strings.Builder
+	m := s.metrics
+	m.mu.Lock()
+	writeMetric(&b, "govm_installs_total", "counter", "Install requests completed, by result status.")
+	statuses := make([]string, 0, len(m.installs))
+	for status := range m.installs {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "govm_installs_total{status=%s} %d\n", labelValue(status), m.installs[status])
+	}
+	writeMetric(&b, "govm_install_failures_total", "counter", "Install requests that failed.")
+	fmt.Fprintf(&b, "govm_install_failures_total %d\n", m.failures)
+	writeMetric(&b, "govm_downloaded_bytes_total", "counter", "Archive bytes downloaded.")
+	fmt.Fprintf(&b, "govm_downloaded_bytes_total %d\n", m.bytes)
+	writeMetric(&b, "govm_switches_total", "counter", "Successful version switches through the API.")
+	fmt.Fprintf(&b, "govm_switches_total %d\n", m.switches)
+	writeMetric(&b, "govm_uninstalls_total", "counter", "Successful uninstalls through the API.")
+	fmt.Fprintf(&b, "govm_uninstalls_total %d\n", m.uninstalls)
+	m.mu.Unlock()
+
+	writeMetric(&b, "govm_installed_versions", "gauge", "Installed Go versions.")
+	fmt.Fprintf(&b, "govm_installed_versions %d\n", len(local))
+	writeMetric(&b, "govm_active_version_info", "gauge", "Active Go version; absent when none is active.")
+	if current != nil {
+		fmt.Fprintf(&b, "govm_active_version_info{version=%s,goroot=%s} 1\n", labelValue(current.Number), labelValue(current.InstallPath))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(w, b.String())
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labelValue 按 Prometheus 文本格式转义标签值并加上引号。
+func labelValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+}
@@ -0,0 +1,358 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// protoPath 为 REST API 对应的 gRPC 接口定义。
+const protoPath = "../../api/govm/v1/govm.proto"
+
+type protoField struct {
+	name, typ string
+	repeated  bool
+}
+
+type protoRPC struct {
+	name, request, response string
+	stream                  bool
+	method, path            string
+}
+
+// protoFile 是一致性测试所需的 .proto 子集：消息字段与带有 `// Name 对应 METHOD PATH` 注释的 rpc。
+type protoFile struct {
+	messages map[string][]protoField
+	rpcs     []protoRPC
+}
+
+var (
+	protoRouteRe   = regexp.MustCompile(`^// (\w+) 对应 (GET|POST|DELETE) (/[\w/{}]+)`)
+	protoRPCRe     = regexp.MustCompile(`^rpc (\w+)\((\w+)\) returns \((stream )?(\w+)\);`)
+	protoMessageRe = regexp.MustCompile(`^message (\w+) \{(\})?`)
+	protoFieldRe   = regexp.MustCompile(`^(repeated )?([\w.]+) (\w+) = \d+;`)
+)
+
+func parseProto(t *testing.T) protoFile {
+	t.Helper()
+	f, err := os.Open(protoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	file := protoFile{messages: map[string][]protoField{}}
+	routes := map[string][2]string{}
+	var message string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := protoRouteRe.FindStringSubmatch(line); m != nil {
+			routes[m[1]] = [2]string{m[2], m[3]}
+			continue
+		}
+		if m := protoRPCRe.FindStringSubmatch(line); m != nil {
+			route, ok := routes[m[1]]
+			if !ok {
+				t.Fatalf("rpc %s does not name its REST route", m[1])
+			}
+			file.rpcs = append(file.rpcs, protoRPC{name: m[1], request: m[2], stream: m[3] != "", response: m[4], method: route[0], path: route[1]})
+			continue
+		}
+		if m := protoMessageRe.FindStringSubmatch(line); m != nil {
+			file.messages[m[1]] = nil
+			if m[2] == "" {
+				message = m[1]
+			}
+			continue
+		}
+		if message == "" {
+			continue
+		}
+		if line == "}" {
+			message = ""
+			continue
+		}
+		if m := protoFieldRe.FindStringSubmatch(line); m != nil {
+			file.messages[message] = append(file.messages[message], protoField{name: m[3], typ: m[2], repeated: m[1] != ""})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.rpcs) == 0 {
+		t.Fatalf("no rpcs found in %s", protoPath)
+	}
+	return file
+}
+
+// jsonName 返回字段在 proto3 JSON 映射中的名称。
+func jsonName(field string) string {
+	parts := strings.Split(field, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// protoChecker 按 proto3 JSON 映射校验 REST 响应，并记录出现过的字段。
+type protoChecker struct {
+	t    *testing.T
+	file protoFile
+	seen map[string]bool
+}
+
+func (c *protoChecker) message(where, name string, v any) {
+	c.t.Helper()
+	if v == nil {
+		return
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		c.t.Fatalf("%s: %s must be a JSON object, got %T", where, name, v)
+	}
+	fields := c.file.messages[name]
+	for key, value := range obj {
+		i := -1
+		for j, f := range fields {
+			if jsonName(f.name) == key {
+				i = j
+			}
+		}
+		if i < 0 {
+			c.t.Fatalf("%s: field %q is not part of message %s", where, key, name)
+		}
+		c.seen[name+"."+fields[i].name] = true
+		c.field(where+"."+key, fields[i], value)
+	}
+}
+
+func (c *protoChecker) field(where string, f protoField, v any) {
+	c.t.Helper()
+	if f.repeated {
+		items, ok := v.([]any)
+		if !ok {
+			c.t.Fatalf("%s: repeated field must be a JSON array, got %T", where, v)
+		}
+		for _, item := range items {
+			c.field(where, protoField{name: f.name, typ: f.typ}, item)
+		}
+		return
+	}
+	var ok bool
+	switch f.typ {
+	case "string":
+		_, ok = v.(string)
+	case "bool":
+		_, ok = v.(bool)
+	case "int64":
+		s, isString := v.(string)
+		_, err := strconv.ParseInt(s, 10, 64)
+		ok = isString && err == nil
+	case "google.protobuf.Timestamp":
+		s, isString := v.(string)
+		_, err := time.Parse(time.RFC3339Nano, s)
+		ok = isString && err == nil
+	default:
+		if _, known := c.file.messages[f.typ]; !known {
+			c.t.Fatalf("%s: unsupported field type %s", where, f.typ)
+		}
+		c.message(where, f.typ, v)
+		return
+	}
+	if !ok {
+		c.t.Fatalf("%s: %v does not encode a %s", where, v, f.typ)
+	}
+}
+
+// response 校验 REST 响应体；List 类接口直接返回数组，对应只有一个 repeated 字段的消息。
+func (c *protoChecker) response(where, name string, v any) {
+	c.t.Helper()
+	if items, ok := v.([]any); ok {
+		fields := c.file.messages[name]
+		if len(fields) != 1 || !fields[0].repeated {
+			c.t.Fatalf("%s: an array body needs a message with a single repeated field, %s has %v", where, name, fields)
+		}
+		c.seen[name+"."+fields[0].name] = true
+		c.field(where, fields[0], items)
+		return
+	}
+	c.message(where, name, v)
+}
+
+// protoInstaller 与真实安装器发布同样字段的进度事件。
+type protoInstaller struct{ bus *events.Bus }
+
+func (p *protoInstaller) Install(v models.Version) error {
+	p.bus.Publish(events.New(events.DownloadStart, "version", v.Number, "url", "https://go.dev/dl/go1.22.0.linux-amd64.tar.gz", "total", "10"))
+	p.bus.Publish(events.New(events.DownloadProgress, "version", v.Number, "downloaded", "10", "total", "10"))
+	p.bus.Publish(events.New(events.Extract, "version", v.Number, "archive", "https://go.dev/dl/go1.22.0.linux-amd64.tar.gz", "mode", "stream"))
+	p.bus.Publish(events.New(events.Done, "version", v.Number, "path", "/opt/go1.22.0", "status", "installed"))
+	return nil
+}
+
+func TestProtoMatchesRESTHandlers(t *testing.T) {
+	t.Parallel()
+
+	file := parseProto(t)
+	bus := events.NewBus()
+	installed := time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC)
+	lister := &fakeLister{
+		remote: []models.Version{{Number: "1.22.0", FullName: "go1.22.0", OS: "linux", Arch: "amd64"}},
+		local:  []models.Version{{Number: "1.21.0", FullName: "go1.21.0", OS: "linux", Arch: "amd64", InstallPath: "/opt/go1.21.0", IsCurrent: true, InstalledAt: installed}},
+	}
+	uninstaller := &remainingUninstaller{remaining: lister.local}
+	// warn 模式的策略让响应带上 warning 字段与事件。
+	srv := httptest.NewServer(New(lister, &protoInstaller{bus: bus}, &fakeSwitcher{}, uninstaller, bus, WithPolicy(fakePolicy{warnOnly: true})).Handler())
+	t.Cleanup(srv.Close)
+
+	checker := &protoChecker{t: t, file: file, seen: map[string]bool{}}
+	for _, rpc := range file.rpcs {
+		req := protoRequest(t, file, rpc, srv.URL)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", rpc.name, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: %s %s = %d: %s", rpc.name, rpc.method, rpc.path, resp.StatusCode, body)
+		}
+
+		if !rpc.stream {
+			var v any
+			if err := json.Unmarshal(body, &v); err != nil {
+				t.Fatalf("%s: decode: %v", rpc.name, err)
+			}
+			checker.response(rpc.name, rpc.response, v)
+			continue
+		}
+		var last string
+		for _, block := range strings.Split(strings.TrimSpace(string(body)), "\n\n") {
+			typ, data, ok := strings.Cut(block, "\ndata: ")
+			if !ok || !strings.HasPrefix(typ, "event: ") {
+				t.Fatalf("%s: malformed SSE event %q", rpc.name, block)
+			}
+			var event map[string]any
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				t.Fatalf("%s: decode %q: %v", rpc.name, data, err)
+			}
+			last = strings.TrimPrefix(typ, "event: ")
+			event["type"] = last
+			checker.message(rpc.name, rpc.response, event)
+		}
+		if last != events.Done && last != events.Error {
+			t.Fatalf("%s: the stream must end with done or error, got %q", rpc.name, last)
+		}
+	}
+
+	// 每个响应消息的字段都应出现在 REST 响应中，避免 .proto 声明了 API 不再返回的字段。
+	for _, rpc := range file.rpcs {
+		for _, name := range messagesReachable(file, rpc.response) {
+			for _, f := range file.messages[name] {
+				if !checker.seen[name+"."+f.name] {
+					t.Errorf("%s.%s is never returned by %s %s", name, f.name, rpc.method, rpc.path)
+				}
+			}
+		}
+	}
+	if len(uninstaller.force) != 1 || !uninstaller.force[0] {
+		t.Errorf("UninstallRequest.force must reach the uninstaller: %v", uninstaller.force)
+	}
+}
+
+// protoRequest 按请求消息构造 REST 请求：string 字段取 1.22.0、bool 字段取 true；路径变量之外的字段 POST 时放入
+// JSON 请求体，其余方法放入查询参数。
+func protoRequest(t *testing.T, file protoFile, rpc protoRPC, base string) *http.Request {
+	t.Helper()
+	path := rpc.path
+	body := map[string]any{}
+	query := url.Values{}
+	for _, f := range file.messages[rpc.request] {
+		var value any
+		switch f.typ {
+		case "string":
+			value = "1.22.0"
+		case "bool":
+			value = true
+		default:
+			t.Fatalf("%s: unsupported request field type %s", rpc.name, f.typ)
+		}
+		if placeholder := "{" + f.name + "}"; strings.Contains(path, placeholder) {
+			path = strings.ReplaceAll(path, placeholder, url.PathEscape(value.(string)))
+			continue
+		}
+		if rpc.method == http.MethodPost {
+			body[jsonName(f.name)] = value
+		} else {
+			query.Set(jsonName(f.name), strconv.FormatBool(value == true))
+		}
+	}
+	if strings.Contains(path, "{") {
+		t.Fatalf("%s: %s has a path variable without a request field", rpc.name, rpc.path)
+	}
+	var reader io.Reader
+	if rpc.method == http.MethodPost {
+		data, _ := json.Marshal(body)
+		reader = strings.NewReader(string(data))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(rpc.method, base+path, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpc.method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if rpc.stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	return req
+}
+
+// messagesReachable 返回 name 及其字段引用的全部消息。
+func messagesReachable(file protoFile, name string) []string {
+	seen := map[string]bool{}
+	var walk func(string)
+	walk = func(n string) {
+		if seen[n] {
+			return
+		}
+		seen[n] = true
+		for _, f := range file.messages[n] {
+			if _, ok := file.messages[f.typ]; ok {
+				walk(f.typ)
+			}
+		}
+	}
+	walk(name)
+	names := make([]string, 0, len(seen))
+	for n := range seen {
+		names = append(names, n)
+	}
+	return names
+}
+
+type remainingUninstaller struct {
+	remaining []models.Version
+	force     []bool
+}
+
+func (u *remainingUninstaller) Uninstall(v string, force bool) ([]models.Version, error) {
+	u.force = append(u.force, force)
+	return u.remaining, nil
+}
@@ -0,0 +1,443 @@
+// Package server 提供 govm serve 使用的本地 REST API，复用与 CLI 相同的版本服务。
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// DefaultAddr 为默认监听地址，只绑定本机回环地址。
+const DefaultAddr = "127.0.0.1:7070"
+
+// ListService 描述版本查询能力。
+type ListService interface {
+	RemoteVersions() ([]models.Version, error)
+	LocalVersions() ([]models.Version, error)
+	CurrentVersion() (*models.Version, error)
+}
+
+// InstallService 描述安装能力。
+type InstallService interface {
+	Install(models.Version) error
+}
+
+// SwitchService 描述版本切换能力。
+type SwitchService interface {
+	UseVersion(string) error
+}
+
+// UninstallService 描述卸载能力。
+type UninstallService interface {
+	Uninstall(version string, force bool) ([]models.Version, error)
+}
+
+// Locker 描述与 CLI 共享的跨进程状态锁。
+type Locker interface {
+	Lock(blocking bool) (func() error, error)
+}
+
+// PolicyService 描述团队策略校验能力，与 CLI 的 install/use 使用同一份策略。
+type PolicyService interface {
+	CheckVersion(version string) error
+	WarnOnly() bool
+}
+
+// warningEvent 为 warn 模式下违反策略时在 SSE 流中发送的事件类型。
+const warningEvent = "warning"
+
+// Option 配置 Server。
+type Option func(*Server)
+
+// WithLocker 指定修改状态时额外持有的跨进程锁，与 CLI 使用同一把锁。
+func WithLocker(l Locker) Option {
+	return func(s *Server) {
+		s.locker = l
+	}
+}
+
+// WithPolicy 指定 install/use 前执行的团队策略校验：enforce 模式下返回 403，warn 模式下在响应中附带警告。
+func WithPolicy(p PolicyService) Option {
+	return func(s *Server) {
+		s.policy = p
+	}
+}
+
+// Server 将版本服务暴露为 HTTP 接口。
+type Server struct {
+	lister      ListService
+	installer   InstallService
+	switcher    SwitchService
+	uninstaller UninstallService
+	events      *events.Bus
+	locker      Locker
+	policy      PolicyService
+	metrics     *metrics
+
+	// mu 串行化进程内所有会修改本地状态的操作。
+	mu  sync.Mutex
+	mux *http.ServeMux
+}
+
+// New 创建 Server。
+func New(lister ListService, installer InstallService, switcher SwitchService, uninstaller UninstallService, bus *events.Bus, opts ...Option) *Server {
+	s := &Server{
+		lister:      lister,
+		installer:   installer,
+		switcher:    switcher,
+		uninstaller: uninstaller,
+		events:      bus,
+		metrics:     newMetrics(bus),
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.routes()
+	return s
+}
+
+// exclusive 在进程内互斥锁与跨进程锁保护下执行 fn。
+func (s *Server) exclusive(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locker != nil {
+		unlock, err := s.locker.Lock(true)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	return fn()
+}
+
+// Handler 返回 HTTP 处理器，便于测试或嵌入其他服务。
+func (s *Server) Handler() http.Handler {
+	return localOnly(s.mux)
+}
+
+// localOnly 拒绝可能来自浏览器页面的请求，防止网页借用户的浏览器跨站调用本地接口：
+// Host 必须是回环地址（防 DNS 重绑定），不接受带 Origin 的请求，POST 与 DELETE 必须声明 JSON 内容类型
+// （简单跨站请求无法设置该类型而不触发预检）。
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not a loopback address", r.Host))
+			return
+		}
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, errors.New("cross-origin requests are not allowed"))
+			return
+		}
+		if r.Method == http.MethodPost || r.Method == http.MethodDelete {
+			if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, errors.New("Content-Type must be application/json"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost 判断 Host 请求头是否指向本机：localhost 或回环 IP，端口可选。
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListenAndServe 在 addr 上提供服务，ctx 取消时优雅关闭。
+func (s *Server) ListenAndServe(ctx context.Context, addr string, ready func(net.Addr)) error {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("server: listen %s: %w", addr, err)
+	}
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+	if ready != nil {
+		ready(ln.Addr())
+	}
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("server: shutdown: %w", err)
+		}
+		return nil
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server: serve: %w", err)
+	}
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /v1/versions", s.handleLocal)
+	s.mux.HandleFunc("GET /v1/remote", s.handleRemote)
+	s.mux.HandleFunc("GET /v1/current", s.handleCurrent)
+	s.mux.HandleFunc("POST /v1/install", s.handleInstall)
+	s.mux.HandleFunc("POST /v1/use", s.handleUse)
+	s.mux.HandleFunc("DELETE /v1/versions/{version}", s.handleUninstall)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+}
+
+// versionView 是 API 返回的版本结构，字段名保持稳定。
+type versionView struct {
+	Number      string    `json:"number"`
+	FullName    string    `json:"fullName"`
+	OS          string    `json:"os,omitempty"`
+	Arch        string    `json:"arch,omitempty"`
+	InstallPath string    `json:"installPath,omitempty"`
+	IsCurrent   bool      `json:"isCurrent"`
+	InstalledAt time.Time `json:"installedAt,omitzero"`
+}
+
+func toView(v models.Version) versionView {
+	name := v.FullName
+	if name == "" {
+		name = "go" + v.Number
+	}
+	return versionView{
+		Number:      v.Number,
+		FullName:    name,
+		OS:          v.OS,
+		Arch:        v.Arch,
+		InstallPath: v.InstallPath,
+		IsCurrent:   v.IsCurrent,
+		InstalledAt: v.InstalledAt,
+	}
+}
+
+func toViews(versions []models.Version) []versionView {
+	views := make([]versionView, 0, len(versions))
+	for _, v := range versions {
+		views = append(views, toView(v))
+	}
+	return views
+}
+
+type versionRequest struct {
+	Version string `json:"version"`
+}
+
+func (s *Server) handleLocal(w http.ResponseWriter, r *http.Request) {
+	versions, err := s.lister.LocalVersions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toViews(versions))
+}
+
+func (s *Server) handleRemote(w http.ResponseWriter, r *http.Request) {
+	versions, err := s.lister.RemoteVersions()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toViews(versions))
+}
+
+func (s *Server) handleCurrent(w http.ResponseWriter, r *http.Request) {
+	current, err := s.lister.CurrentVersion()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if current == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"current": nil})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"current": toView(*current)})
+}
+
+func (s *Server) handleInstall(w http.ResponseWriter, r *http.Request) {
+	ver, ok := decodeVersion(w, r)
+	if !ok {
+		return
+	}
+	versions, err := s.lister.RemoteVersions()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	var target *models.Version
+	for i := range versions {
+		if versions[i].Number == ver {
+			target = &versions[i]
+			break
+		}
+	}
+	if target == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("version %s not found in remote list", ver))
+		return
+	}
+	warning, ok := s.checkPolicy(w, ver)
+	if !ok {
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		err := s.exclusive(func() error { return s.installer.Install(*target) })
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, withWarning(map[string]string{"status": "installed", "version": ver}, warning))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming unsupported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if warning != "" {
+		writeSSE(w, events.New(warningEvent, "version", ver, "message", warning))
+		flusher.Flush()
+	}
+
+	var writeMu sync.Mutex
+	unsubscribe := s.events.Subscribe(func(e events.Event) {
+		if e.Get("version") != ver {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		writeSSE(w, e)
+		flusher.Flush()
+	})
+	err = s.exclusive(func() error { return s.installer.Install(*target) })
+	unsubscribe()
+
+	if err != nil && s.events == nil {
+		writeSSE(w, events.New(events.Error, "version", ver, "message", err.Error()))
+	}
+	flusher.Flush()
+}
+
+func (s *Server) handleUse(w http.ResponseWriter, r *http.Request) {
+	ver, ok := decodeVersion(w, r)
+	if !ok {
+		return
+	}
+	warning, ok := s.checkPolicy(w, ver)
+	if !ok {
+		return
+	}
+	err := s.exclusive(func() error { return s.switcher.UseVersion(ver) })
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.metrics.countSwitch()
+	writeJSON(w, http.StatusOK, withWarning(map[string]string{"status": "active", "version": ver}, warning))
+}
+
+// checkPolicy 与 CLI 一样在修改状态前执行策略校验：enforce 模式下违反策略时写入 403 并返回 false，
+// warn 模式下返回警告内容。
+func (s *Server) checkPolicy(w http.ResponseWriter, ver string) (string, bool) {
+	if s.policy == nil {
+		return "", true
+	}
+	err := s.policy.CheckVersion(ver)
+	switch {
+	case err == nil:
+		return "", true
+	case s.policy.WarnOnly():
+		return err.Error(), true
+	}
+	writeError(w, http.StatusForbidden, err)
+	return "", false
+}
+
+// withWarning 在 warning 非空时把它加入响应。
+func withWarning(resp map[string]string, warning string) map[string]string {
+	if warning != "" {
+		resp["warning"] = warning
+	}
+	return resp
+}
+
+func (s *Server) handleUninstall(w http.ResponseWriter, r *http.Request) {
+	ver := normalizeVersion(r.PathValue("version"))
+	force := r.URL.Query().Get("force") == "true"
+	var remaining []models.Version
+	err := s.exclusive(func() error {
+		var err error
+		remaining, err = s.uninstaller.Uninstall(ver, force)
+		return err
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.metrics.countUninstall()
+	writeJSON(w, http.StatusOK, map[string]any{"status": "uninstalled", "version": ver, "remaining": toViews(remaining)})
+}
+
+func decodeVersion(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req versionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode request: %w", err))
+		return "", false
+	}
+	ver := normalizeVersion(req.Version)
+	if ver == "" {
+		writeError(w, http.StatusBadRequest, errors.New("version is required"))
+		return "", false
+	}
+	return ver, true
+}
+
+func writeSSE(w http.ResponseWriter, e events.Event) {
+	payload := make(map[string]string, len(e.Fields))
+	for _, f := range e.Fields {
+		payload[f.Key] = f.Value
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func normalizeVersion(input string) string {
+	return strings.TrimPrefix(strings.TrimSpace(input), "go")
+}
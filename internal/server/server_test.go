@@ -0,0 +1,297 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type fakeLister struct {
+	remote []models.Version
+	local  []models.Version
+}
+
+func (f *fakeLister) RemoteVersions() ([]models.Version, error) { return f.remote, nil }
+func (f *fakeLister) LocalVersions() ([]models.Version, error)  { return f.local, nil }
+func (f *fakeLister) CurrentVersion() (*models.Version, error) {
+	for i := range f.local {
+		if f.local[i].IsCurrent {
+			return &f.local[i], nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeInstaller struct {
+	bus       *events.Bus
+	installed []string
+}
+
+func (f *fakeInstaller) Install(v models.Version) error {
+	f.bus.Publish(events.New(events.DownloadStart, "version", v.Number, "total", "10"))
+	f.bus.Publish(events.New(events.Done, "version", v.Number, "status", "installed"))
+	f.installed = append(f.installed, v.Number)
+	return nil
+}
+
+type fakeSwitcher struct{ used []string }
+
+func (f *fakeSwitcher) UseVersion(v string) error {
+	f.used = append(f.used, v)
+	return nil
+}
+
+type fakeUninstaller struct{ removed []string }
+
+func (f *fakeUninstaller) Uninstall(v string, force bool) ([]models.Version, error) {
+	f.removed = append(f.removed, v)
+	return nil, nil
+}
+
+func newTestServer(t *testing.T, opts ...Option) (*httptest.Server, *fakeInstaller, *fakeSwitcher, *fakeUninstaller) {
+	t.Helper()
+	bus := events.NewBus()
+	lister := &fakeLister{
+		remote: []models.Version{{Number: "1.22.0", FullName: "go1.22.0"}},
+		local:  []models.Version{{Number: "1.21.0", InstallPath: "/opt/go1.21.0", IsCurrent: true}},
+	}
+	installer := &fakeInstaller{bus: bus}
+	switcher := &fakeSwitcher{}
+	uninstaller := &fakeUninstaller{}
+	srv := httptest.NewServer(New(lister, installer, switcher, uninstaller, bus, opts...).Handler())
+	t.Cleanup(srv.Close)
+	return srv, installer, switcher, uninstaller
+}
+
+func TestServerListsVersions(t *testing.T) {
+	t.Parallel()
+
+	srv, _, _, _ := newTestServer(t)
+	resp, err := http.Get(srv.URL + "/v1/versions")
+	if err != nil {
+		t.Fatalf("GET versions: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var views []versionView
+	if err := json.NewDecoder(resp.Body).Decode(&views); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(views) != 1 || views[0].Number != "1.21.0" || !views[0].IsCurrent || views[0].FullName != "go1.21.0" {
+		t.Fatalf("unexpected versions: %#v", views)
+	}
+}
+
+func TestServerInstallStreamsEvents(t *testing.T) {
+	t.Parallel()
+
+	srv, installer, _, _ := newTestServer(t)
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/install", strings.NewReader(`{"version":"go1.22.0"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST install: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if !strings.Contains(string(body), "event: download_start\n") || !strings.Contains(string(body), "event: done\n") {
+		t.Fatalf("unexpected SSE body: %s", body)
+	}
+	if len(installer.installed) != 1 || installer.installed[0] != "1.22.0" {
+		t.Fatalf("installer not invoked: %v", installer.installed)
+	}
+}
+
+type fakePolicy struct{ warnOnly bool }
+
+func (p fakePolicy) CheckVersion(v string) error {
+	if v != "1.21.0" {
+		return errors.New("policy: version " + v + " is not allowed")
+	}
+	return nil
+}
+
+func (p fakePolicy) WarnOnly() bool { return p.warnOnly }
+
+func TestServerEnforcesPolicy(t *testing.T) {
+	t.Parallel()
+
+	srv, installer, switcher, _ := newTestServer(t, WithPolicy(fakePolicy{}))
+	for _, path := range []string{"/v1/install", "/v1/use"} {
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(`{"version":"1.22.0"}`))
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("POST %s status = %d, want 403", path, resp.StatusCode)
+		}
+	}
+	if len(installer.installed) != 0 || len(switcher.used) != 0 {
+		t.Fatalf("a rejected version must not be installed or used: %v %v", installer.installed, switcher.used)
+	}
+}
+
+func TestServerWarnsOnPolicyViolation(t *testing.T) {
+	t.Parallel()
+
+	srv, installer, switcher, _ := newTestServer(t, WithPolicy(fakePolicy{warnOnly: true}))
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/install", strings.NewReader(`{"version":"1.22.0"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST install: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "event: warning\n") || !strings.Contains(string(body), "is not allowed") {
+		t.Fatalf("SSE stream must carry the policy warning: %s", body)
+	}
+
+	resp, err = http.Post(srv.URL+"/v1/use", "application/json", strings.NewReader(`{"version":"1.22.0"}`))
+	if err != nil {
+		t.Fatalf("POST use: %v", err)
+	}
+	defer resp.Body.Close()
+	var got map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.Contains(got["warning"], "is not allowed") {
+		t.Fatalf("use response = %d %v, want a warning", resp.StatusCode, got)
+	}
+	if len(installer.installed) != 1 || len(switcher.used) != 1 {
+		t.Fatalf("warn mode must still install and use: %v %v", installer.installed, switcher.used)
+	}
+}
+
+func TestServerUseAndUninstall(t *testing.T) {
+	t.Parallel()
+
+	srv, _, switcher, uninstaller := newTestServer(t)
+	resp, err := http.Post(srv.URL+"/v1/use", "application/json", strings.NewReader(`{"version":"1.21.0"}`))
+	if err != nil {
+		t.Fatalf("POST use: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(switcher.used) != 1 {
+		t.Fatalf("use failed: status=%d used=%v", resp.StatusCode, switcher.used)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/v1/versions/go1.21.0?force=true", nil)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE version: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(uninstaller.removed) != 1 || uninstaller.removed[0] != "1.21.0" {
+		t.Fatalf("uninstall failed: status=%d removed=%v", resp.StatusCode, uninstaller.removed)
+	}
+
+	resp, err = http.Post(srv.URL+"/v1/use", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("POST use: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for missing version, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerRejectsBrowserRequests(t *testing.T) {
+	t.Parallel()
+
+	srv, installer, switcher, uninstaller := newTestServer(t)
+	cases := []struct {
+		name, method, path, contentType, host, origin string
+		want                                          int
+	}{
+		{name: "form post", method: http.MethodPost, path: "/v1/install", contentType: "text/plain", want: http.StatusUnsupportedMediaType},
+		{name: "missing content type", method: http.MethodPost, path: "/v1/use", want: http.StatusUnsupportedMediaType},
+		{name: "delete without content type", method: http.MethodDelete, path: "/v1/versions/1.21.0", want: http.StatusUnsupportedMediaType},
+		{name: "origin", method: http.MethodPost, path: "/v1/use", contentType: "application/json", origin: "https://evil.example", want: http.StatusForbidden},
+		{name: "origin on read", method: http.MethodGet, path: "/v1/versions", origin: "https://evil.example", want: http.StatusForbidden},
+		{name: "rebound host", method: http.MethodPost, path: "/v1/use", contentType: "application/json", host: "evil.example:7070", want: http.StatusForbidden},
+		{name: "rebound host on read", method: http.MethodGet, path: "/v1/versions", host: "evil.example", want: http.StatusForbidden},
+		{name: "localhost", method: http.MethodGet, path: "/v1/versions", host: "localhost:7070", want: http.StatusOK},
+		{name: "json with charset", method: http.MethodPost, path: "/v1/use", contentType: "application/json; charset=utf-8", want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(tc.method, srv.URL+tc.path, strings.NewReader(`{"version":"1.21.0"}`))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		if tc.host != "" {
+			req.Host = tc.host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Fatalf("%s: status = %d, want %d", tc.name, resp.StatusCode, tc.want)
+		}
+	}
+	if len(installer.installed) != 0 || len(switcher.used) != 1 || len(uninstaller.removed) != 0 {
+		t.Fatalf("only the accepted request may reach the services: %v %v %v", installer.installed, switcher.used, uninstaller.removed)
+	}
+}
+
+func TestServerMetrics(t *testing.T) {
+	t.Parallel()
+
+	srv, installer, _, _ := newTestServer(t)
+	installer.bus.Publish(events.New(events.DownloadProgress, "version", "1.22.0", "downloaded", "4", "total", "10"))
+	installer.bus.Publish(events.New(events.DownloadProgress, "version", "1.22.0", "downloaded", "10", "total", "10"))
+	installer.bus.Publish(events.New(events.Error, "version", "1.23.0", "message", "checksum mismatch"))
+	resp, err := http.Post(srv.URL+"/v1/install", "application/json", strings.NewReader(`{"version":"1.22.0"}`))
+	if err != nil {
+		t.Fatalf("POST install: %v", err)
+	}
+	resp.Body.Close()
+	resp, err = http.Post(srv.URL+"/v1/use", "application/json", strings.NewReader(`{"version":"1.21.0"}`))
+	if err != nil {
+		t.Fatalf("POST use: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{
+		"# TYPE govm_installs_total counter\n",
+		`govm_installs_total{status="installed"} 1` + "\n",
+		`govm_installs_total{status="already_installed"} 0` + "\n",
+		"govm_install_failures_total 1\n",
+		"govm_downloaded_bytes_total 10\n",
+		"govm_switches_total 1\n",
+		"govm_uninstalls_total 0\n",
+		"govm_installed_versions 1\n",
+		`govm_active_version_info{version="1.21.0",goroot="/opt/go1.21.0"} 1` + "\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package shims
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// selectorTools 为生成选择器的工具。
+var selectorTools = []string{"go", "gofmt"}
+
+// Option 配置 Manager。
+type Option func(*Manager)
+
+// WithSelectors 额外生成 go、gofmt 选择器：它们是指向 govmBin 的符号链接，govm 以这些名字被调用时
+// 在运行时解析项目的 .govm-version 或默认版本并执行对应的真实工具，shell 无需设置 GOROOT。
+func WithSelectors(govmBin string) Option {
+	return func(m *Manager) {
+		m.govmBin = govmBin
+	}
+}
+
+// linkSelectors 以符号链接指向 govm 可执行文件；未启用时删除以前生成的选择器。
+func (m *Manager) linkSelectors() error {
+	if m.govmBin != "" {
+		if err := os.MkdirAll(m.dir, 0o755); err != nil {
+			return fmt.Errorf("shims: create dir: %w", err)
+		}
+	}
+	for _, tool := range selectorTools {
+		path := filepath.Join(m.dir, tool)
+		if m.govmBin == "" {
+			if IsSelector(path) || generated(path) {
+				if err := os.Remove(path); err != nil {
+					return fmt.Errorf("shims: remove %s: %w", path, err)
+				}
+			}
+			continue
+		}
+		if target, err := os.Readlink(path); err == nil && target == m.govmBin {
+			continue
+		}
+		if err := m.checkOwned(path); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("shims: remove %s: %w", path, err)
+		}
+		if err := os.Symlink(m.govmBin, path); err != nil {
+			return fmt.Errorf("shims: link %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// IsSelector 报告 path 是否为指向 govm 可执行文件的选择器，包括 govm 移动位置后残留的旧链接；
+// 选择器总是转交给某个已安装版本，不会遮蔽 govm 管理的 go。
+func IsSelector(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return false
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(filepath.Base(target), "govm") {
+		return true
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	a, errA := os.Stat(path)
+	b, errB := os.Stat(exe)
+	return errA == nil && errB == nil && os.SameFile(a, b)
+}
@@ -0,0 +1,68 @@
+package shims
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSelectorsLinkToGovm(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	govm := filepath.Join(base, "govm")
+	if err := os.WriteFile(govm, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	active := fakeGoRoot(t, "go1.22.0")
+	m := New(filepath.Join(base, "bin"), WithSelectors(govm))
+	// 早期版本写入的 sh 选择器带有 govm 标记，应被符号链接替换。
+	if err := os.MkdirAll(m.Dir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Dir(), "gofmt"), []byte("#!/bin/sh\n"+marker+"\n# govm-selector: gofmt\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.Rehash(map[string]string{"1.22.0": active}); err != nil {
+			t.Fatalf("Rehash: %v", err)
+		}
+	}
+	for _, tool := range []string{"go", "gofmt"} {
+		path := filepath.Join(m.Dir(), tool)
+		if target, err := os.Readlink(path); err != nil || target != govm {
+			t.Fatalf("%s must link to govm: %q, %v", tool, target, err)
+		}
+		if !IsSelector(path) {
+			t.Fatalf("%s not recognized as a selector", tool)
+		}
+	}
+	if IsSelector(filepath.Join(m.Dir(), "go1.22.0")) {
+		t.Fatal("versioned shims are not selectors")
+	}
+
+	if _, err := New(m.Dir()).Rehash(map[string]string{"1.22.0": active}); err != nil {
+		t.Fatalf("Rehash: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(m.Dir(), "go")); !os.IsNotExist(err) {
+		t.Fatalf("selectors must be removed once disabled: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(m.Dir(), "go1.22.0")); err != nil {
+		t.Fatalf("versioned shims must stay: %v", err)
+	}
+}
+
+func TestSelectorsKeepForeignFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go"), []byte("#!/bin/sh\necho mine\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := New(dir, WithSelectors(filepath.Join(dir, "govm"))).Rehash(nil)
+	if err == nil || !strings.Contains(err.Error(), "was not created by govm") {
+		t.Fatalf("expected refusal to overwrite, got %v", err)
+	}
+}
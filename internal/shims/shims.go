@@ -0,0 +1,239 @@
+// Package shims 在 bin 目录中维护按版本命名的命令（如 go1.22.0、gofmt1.22.0），无需切换即可直接调用任意已安装版本。
+package shims
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// marker 标识由 govm 生成的 shim，清理时不会误删用户自己的文件。
+	marker = "# generated by govm, do not edit"
+	// versionTag 记录 shim 所属版本，卸载与 rehash 据此识别。
+	versionTag = "# govm-version: "
+)
+
+// Manager 维护 shim 目录。
+type Manager struct {
+	dir string
+
+	govmBin string
+}
+
+// New 创建在 dir 中维护 shim 的 Manager。
+func New(dir string, opts ...Option) *Manager {
+	m := &Manager{dir: dir}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Dir 返回 shim 目录，需要加入 PATH。
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Link 为 GOROOT/bin 中的每个工具（go、gofmt 等）生成 <工具><version> shim，
+// 执行时固定 GOROOT 为 goRoot，不受当前激活版本影响；`go<version> tool cover` 等子工具随之可用。
+func (m *Manager) Link(version, goRoot string) error {
+	_, err := m.link(version, goRoot)
+	return err
+}
+
+func (m *Manager) link(version, goRoot string) ([]string, error) {
+	version = strings.TrimSpace(version)
+	if version == "" || strings.ContainsAny(version, `/\`) {
+		return nil, fmt.Errorf("shims: invalid version %q", version)
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("shims: create dir: %w", err)
+	}
+	tools, err := binTools(goRoot)
+	if err != nil {
+		return nil, err
+	}
+	var created []string
+	for _, tool := range tools {
+		name := tool + version
+		path := filepath.Join(m.dir, name)
+		if err := m.checkOwned(path); err != nil {
+			return created, err
+		}
+		if err := writeScript(path, script(version, goRoot, filepath.Join(goRoot, "bin", tool))); err != nil {
+			return created, err
+		}
+		created = append(created, name)
+	}
+	return created, m.linkSelectors()
+}
+
+// Unlink 删除 version 的全部 shim，不存在时忽略。
+func (m *Manager) Unlink(version string) error {
+	version = strings.TrimSpace(version)
+	owned, err := m.owned()
+	if err != nil {
+		return err
+	}
+	for path, v := range owned {
+		if v != version {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("shims: remove %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Rehash 删除目录中全部 govm shim 后按 installs（版本 -> GOROOT）重新生成，返回生成的命令名。
+func (m *Manager) Rehash(installs map[string]string) ([]string, error) {
+	owned, err := m.owned()
+	if err != nil {
+		return nil, err
+	}
+	for path := range owned {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("shims: remove %s: %w", path, err)
+		}
+	}
+
+	versions := make([]string, 0, len(installs))
+	for v := range installs {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	var created []string
+	for _, v := range versions {
+		names, err := m.link(v, installs[v])
+		created = append(created, names...)
+		if err != nil {
+			return created, err
+		}
+	}
+	return created, m.linkSelectors()
+}
+
+// owned 返回目录中由 govm 生成的 shim 及其所属版本。
+func (m *Manager) owned() (map[string]string, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("shims: read dir: %w", err)
+	}
+	owned := map[string]string{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(m.dir, entry.Name())
+		if v, ok := shimVersion(path); ok {
+			owned[path] = v
+		}
+	}
+	return owned, nil
+}
+
+// binTools 列出 GOROOT/bin 中的可执行文件。
+func binTools(goRoot string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(goRoot, "bin"))
+	if err != nil {
+		return nil, fmt.Errorf("shims: read %s: %w", filepath.Join(goRoot, "bin"), err)
+	}
+	var tools []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		tools = append(tools, entry.Name())
+	}
+	return tools, nil
+}
+
+// checkOwned 拒绝覆盖不是由 govm 生成的同名文件。
+func (m *Manager) checkOwned(path string) error {
+	if generated(path) || IsSelector(path) {
+		return nil
+	}
+	if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return fmt.Errorf("shims: %s exists and was not created by govm", path)
+}
+
+// shimVersion 读取 shim 头部的版本标记，非 govm 文件返回 false。
+func shimVersion(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	owned := false
+	for i := 0; i < 4 && scanner.Scan(); i++ {
+		line := scanner.Bytes()
+		if bytes.Equal(line, []byte(marker)) {
+			owned = true
+		}
+		if v, ok := bytes.CutPrefix(line, []byte(versionTag)); ok && owned {
+			return string(v), true
+		}
+	}
+	return "", false
+}
+
+// generated 报告 path 头部是否带有 govm 的生成标记，早期版本写入的 sh 选择器也由此识别。
+func generated(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < 4 && scanner.Scan(); i++ {
+		if bytes.Equal(scanner.Bytes(), []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+func script(version, goRoot, target string) string {
+	return fmt.Sprintf("#!/bin/sh\n%s\n%s%s\nGOROOT=%s exec %s \"$@\"\n",
+		marker, versionTag, version, shellQuote(goRoot), shellQuote(target))
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeScript(path, content string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".shim-*")
+	if err != nil {
+		return fmt.Errorf("shims: write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("shims: write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("shims: write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return fmt.Errorf("shims: write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("shims: write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package shims
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fakeGoRoot(t *testing.T, name string) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(filepath.Join(root, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"$GOROOT $*\"\n"
+	for _, tool := range []string{"go", "gofmt"} {
+		if err := os.WriteFile(filepath.Join(root, "bin", tool), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLinkRunsVersionWithItsOwnGoroot(t *testing.T) {
+	t.Parallel()
+
+	m := New(filepath.Join(t.TempDir(), "bin"))
+	goRoot := fakeGoRoot(t, "go 1.22.0")
+	if err := m.Link("1.22.0", goRoot); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+
+	cmd := exec.Command(filepath.Join(m.Dir(), "go1.22.0"), "version")
+	cmd.Env = append(os.Environ(), "GOROOT=/somewhere/else")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("run shim: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != goRoot+" version" {
+		t.Fatalf("unexpected shim output %q", got)
+	}
+
+	if err := m.Unlink("1.22.0"); err != nil {
+		t.Fatalf("Unlink: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(m.Dir(), "go1.22.0")); !os.IsNotExist(err) {
+		t.Fatalf("shim not removed: %v", err)
+	}
+	if err := m.Unlink("1.22.0"); err != nil {
+		t.Fatalf("Unlink missing shim: %v", err)
+	}
+}
+
+func TestLinkRefusesForeignFiles(t *testing.T) {
+	t.Parallel()
+
+	m := New(t.TempDir())
+	foreign := filepath.Join(m.Dir(), "go1.21.0")
+	if err := os.WriteFile(foreign, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Link("1.21.0", fakeGoRoot(t, "go")); err == nil {
+		t.Fatal("expected Link to refuse overwriting a foreign file")
+	}
+	if err := m.Unlink("1.21.0"); err != nil {
+		t.Fatalf("Unlink: %v", err)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Fatalf("foreign file must be left alone: %v", err)
+	}
+}
+
+func TestRehashRegeneratesToolShims(t *testing.T) {
+	t.Parallel()
+
+	m := New(t.TempDir())
+	old := fakeGoRoot(t, "old")
+	if err := m.Link("1.20.0", old); err != nil {
+		t.Fatal(err)
+	}
+	user := filepath.Join(m.Dir(), "mytool")
+	if err := os.WriteFile(user, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	goRoot := fakeGoRoot(t, "new")
+	created, err := m.Rehash(map[string]string{"1.22.0": goRoot})
+	if err != nil {
+		t.Fatalf("Rehash: %v", err)
+	}
+	if strings.Join(created, ",") != "go1.22.0,gofmt1.22.0" {
+		t.Fatalf("unexpected shims %v", created)
+	}
+	for _, name := range []string{"go1.20.0", "gofmt1.20.0"} {
+		if _, err := os.Stat(filepath.Join(m.Dir(), name)); !os.IsNotExist(err) {
+			t.Fatalf("stale shim %s kept: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(user); err != nil {
+		t.Fatalf("user file removed: %v", err)
+	}
+	out, err := exec.Command(filepath.Join(m.Dir(), "gofmt1.22.0"), "-l").Output()
+	if err != nil || strings.TrimSpace(string(out)) != goRoot+" -l" {
+		t.Fatalf("unexpected gofmt shim output %q: %v", out, err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package state 负责 govm 环境状态清单的序列化，用于在机器之间导出与导入已安装版本。
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 支持的清单格式。
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Manifest 描述一台机器上的 govm 状态：已安装版本与默认版本。
+type Manifest struct {
+	Default  string   `json:"default,omitempty"`
+	Versions []string `json:"versions"`
+}
+
+// Encode 以指定格式写出清单。
+func Encode(w io.Writer, m Manifest, format string) error {
+	if m.Versions == nil {
+		m.Versions = []string{}
+	}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatJSON:
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return fmt.Errorf("state: encode json: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case FormatYAML, "yml":
+		var b strings.Builder
+		if m.Default != "" {
+			fmt.Fprintf(&b, "default: %s\n", m.Default)
+		}
+		if len(m.Versions) == 0 {
+			b.WriteString("versions: []\n")
+		} else {
+			b.WriteString("versions:\n")
+			for _, v := range m.Versions {
+				fmt.Fprintf(&b, "  - %s\n", v)
+			}
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("state: unsupported format %q", format)
+	}
+}
+
+// Decode 读取清单，自动识别 JSON、YAML 子集或每行一个版本号的纯文本。
+func Decode(r io.Reader) (Manifest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("state: read manifest: %w", err)
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return Manifest{}, errors.New("state: empty manifest")
+	}
+	if trimmed[0] == '{' {
+		var m Manifest
+		if err := json.Unmarshal(trimmed, &m); err != nil {
+			return Manifest{}, fmt.Errorf("state: decode json: %w", err)
+		}
+		m.Versions = normalizeList(m.Versions)
+		m.Default = normalizeVersion(m.Default)
+		return m, nil
+	}
+	return decodeText(trimmed)
+}
+
+// decodeText 解析 `default:`/`versions:` 形式的 YAML 子集，同时兼容每行一个版本的纯文本。
+func decodeText(data []byte) (Manifest, error) {
+	var m Manifest
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "- "), line == "-":
+			item := strings.TrimSpace(strings.TrimPrefix(line, "-"))
+			if item != "" {
+				m.Versions = append(m.Versions, unquote(item))
+			}
+		case strings.HasPrefix(line, "default:"):
+			m.Default = unquote(strings.TrimSpace(strings.TrimPrefix(line, "default:")))
+		case strings.HasPrefix(line, "versions:"):
+			rest := strings.TrimSpace(strings.TrimPrefix(line, "versions:"))
+			if rest != "" && rest != "[]" {
+				rest = strings.TrimSuffix(strings.TrimPrefix(rest, "["), "]")
+				for _, item := range strings.Split(rest, ",") {
+					if item = strings.TrimSpace(item); item != "" {
+						m.Versions = append(m.Versions, unquote(item))
+					}
+				}
+			}
+		case strings.Contains(line, ":"):
+			return Manifest{}, fmt.Errorf("state: line %d: unknown key in %q", lineNo, line)
+		default:
+			m.Versions = append(m.Versions, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Manifest{}, fmt.Errorf("state: scan manifest: %w", err)
+	}
+	m.Versions = normalizeList(m.Versions)
+	m.Default = normalizeVersion(m.Default)
+	return m, nil
+}
+
+func normalizeList(values []string) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = normalizeVersion(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+func normalizeVersion(v string) string {
+	return strings.TrimPrefix(strings.TrimSpace(v), "go")
+}
+
+func unquote(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	original := Manifest{Default: "1.22.0", Versions: []string{"1.22.0", "1.21.5"}}
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		var buf bytes.Buffer
+		if err := Encode(&buf, original, format); err != nil {
+			t.Fatalf("Encode(%s) failed: %v", format, err)
+		}
+		decoded, err := Decode(&buf)
+		if err != nil {
+			t.Fatalf("Decode(%s) failed: %v", format, err)
+		}
+		if !reflect.DeepEqual(decoded, original) {
+			t.Fatalf("%s round trip mismatch: %#v", format, decoded)
+		}
+	}
+}
+
+func TestDecodePlainListAndInlineYAML(t *testing.T) {
+	t.Parallel()
+
+	plain, err := Decode(strings.NewReader("# toolchains\ngo1.21.0\n1.22.3\n\n1.21.0\n"))
+	if err != nil {
+		t.Fatalf("Decode plain failed: %v", err)
+	}
+	if !reflect.DeepEqual(plain.Versions, []string{"1.21.0", "1.22.3"}) {
+		t.Fatalf("unexpected plain versions: %v", plain.Versions)
+	}
+
+	inline, err := Decode(strings.NewReader("default: \"go1.22.3\"\nversions: [1.22.3, '1.20.1']\n"))
+	if err != nil {
+		t.Fatalf("Decode inline failed: %v", err)
+	}
+	if inline.Default != "1.22.3" || !reflect.DeepEqual(inline.Versions, []string{"1.22.3", "1.20.1"}) {
+		t.Fatalf("unexpected inline manifest: %#v", inline)
+	}
+}
+
+func TestDecodeRejectsUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Decode(strings.NewReader("aliases: {}\n")); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+	if _, err := Decode(strings.NewReader("  \n")); err == nil {
+		t.Fatal("expected error for empty manifest")
+	}
+}
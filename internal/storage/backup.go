@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupOptions 配置 govm 状态快照包含的内容。
+type BackupOptions struct {
+	// ConfigPath 为 config.json 路径，为空时不包含配置文件。
+	ConfigPath string
+	// IncludeDownloads 为 true 时同时打包 downloads/ 中保留的归档。
+	IncludeDownloads bool
+}
+
+// RestoreOptions 配置恢复时配置文件的写入位置。
+type RestoreOptions struct {
+	ConfigPath string
+}
+
+// 快照中的固定条目名称。
+const (
+	backupMetadata  = "metadata.json"
+	backupCurrent   = "current"
+	backupUsage     = "usage.json"
+	backupConfig    = "config.json"
+	backupManifests = "manifests"
+	backupDownloads = "downloads"
+)
+
+// Backup 将元数据、文件清单、当前版本标记、使用统计与配置写成 tar.gz 快照；版本安装目录不包含在内。
+func (s *FileStorage) Backup(w io.Writer, opts BackupOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	files := []struct{ name, src string }{
+		{backupMetadata, s.metadataPath},
+		{backupCurrent, s.currentPath},
+		{backupUsage, s.usagePath()},
+	}
+	if opts.ConfigPath != "" {
+		files = append(files, struct{ name, src string }{backupConfig, opts.ConfigPath})
+	}
+	for _, f := range files {
+		if err := addBackupFile(tw, f.name, f.src); err != nil {
+			return err
+		}
+	}
+
+	dirs := []struct{ name, src string }{{backupManifests, s.manifestsDir()}}
+	if opts.IncludeDownloads {
+		dirs = append(dirs, struct{ name, src string }{backupDownloads, s.downloadsDir()})
+	}
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d.src)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("storage: backup %s: %w", d.name, err)
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			if err := addBackupFile(tw, path.Join(d.name, entry.Name()), filepath.Join(d.src, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("storage: backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("storage: backup: %w", err)
+	}
+	return nil
+}
+
+// addBackupFile 写入单个文件，源文件不存在时跳过。
+func addBackupFile(tw *tar.Writer, name, src string) error {
+	f, err := os.Open(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("storage: backup %s: %w", name, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("storage: backup %s: %w", name, err)
+	}
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(info.Mode().Perm()),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("storage: backup %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("storage: backup %s: %w", name, err)
+	}
+	return nil
+}
+
+// Restore 从 Backup 生成的快照恢复状态文件，返回已恢复的条目名称；未知条目会被拒绝。
+func (s *FileStorage) Restore(r io.Reader, opts RestoreOptions) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("storage: restore: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var restored []string
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return restored, fmt.Errorf("storage: restore: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return restored, fmt.Errorf("storage: restore: unexpected entry %s", hdr.Name)
+		}
+		dest, err := s.restoreTarget(hdr.Name, opts)
+		if err != nil {
+			return restored, err
+		}
+		if dest == "" {
+			continue
+		}
+		if err := writeRestored(dest, tr, fs.FileMode(hdr.Mode).Perm(), hdr.ModTime); err != nil {
+			return restored, fmt.Errorf("storage: restore %s: %w", hdr.Name, err)
+		}
+		restored = append(restored, hdr.Name)
+		if hdr.Name == backupMetadata {
+			if data, err := os.ReadFile(s.metadataPath); err == nil {
+				if err := s.signLocked(data); err != nil {
+					return restored, err
+				}
+			}
+		}
+	}
+	// 恢复的元数据可能来自其他结构版本，下次读取时重新判断。
+	s.diskSchema = 0
+	return restored, nil
+}
+
+// restoreTarget 将快照条目映射到本机路径；未配置配置文件路径时跳过 config.json。
+func (s *FileStorage) restoreTarget(name string, opts RestoreOptions) (string, error) {
+	switch name {
+	case backupMetadata:
+		return s.metadataPath, nil
+	case backupCurrent:
+		return s.currentPath, nil
+	case backupUsage:
+		return s.usagePath(), nil
+	case backupConfig:
+		return opts.ConfigPath, nil
+	}
+	dir, file := path.Split(name)
+	if file == "" || strings.HasPrefix(file, ".") || strings.ContainsAny(file, `/\`) {
+		return "", fmt.Errorf("storage: restore: unexpected entry %s", name)
+	}
+	switch dir {
+	case backupManifests + "/":
+		return filepath.Join(s.manifestsDir(), file), nil
+	case backupDownloads + "/":
+		return filepath.Join(s.downloadsDir(), file), nil
+	}
+	return "", fmt.Errorf("storage: restore: unexpected entry %s", name)
+}
+
+// writeRestored 先写临时文件再重命名，避免恢复中断时留下半截文件。
+func writeRestored(dest string, r io.Reader, perm fs.FileMode, mtime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = 0o644
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return err
+	}
+	return os.Chtimes(dest, mtime, mtime)
+}
+
+func (s *FileStorage) manifestsDir() string {
+	return filepath.Join(filepath.Dir(s.metadataPath), "manifests")
+}
+
+func (s *FileStorage) downloadsDir() string {
+	if s.cfg.CacheDir != "" {
+		return filepath.Join(s.cfg.CacheDir, "downloads")
+	}
+	return filepath.Join(s.cfg.RootDir, "downloads")
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: src})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0", FullName: "go1.22.0"}); err != nil {
+		t.Fatalf("SaveMetadata: %v", err)
+	}
+	if err := store.SetCurrentVersionMarker("1.22.0"); err != nil {
+		t.Fatalf("SetCurrentVersion: %v", err)
+	}
+	if err := store.SaveManifest(models.FileManifest{Version: "1.22.0"}); err != nil {
+		t.Fatalf("SaveManifest: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(src, "downloads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "downloads", "go1.22.0.linux-amd64.tar.gz"), []byte("archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(src, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"usageStats":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := store.Backup(&buf, BackupOptions{ConfigPath: configPath}); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if strings.Contains(buf.String(), "downloads/") {
+		t.Fatal("downloads must be excluded by default")
+	}
+
+	dst := t.TempDir()
+	restoredStore := NewFileStorage(models.Config{RootDir: dst})
+	restoredConfig := filepath.Join(dst, "config.json")
+	restored, err := restoredStore.Restore(bytes.NewReader(buf.Bytes()), RestoreOptions{ConfigPath: restoredConfig})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(restored) != 4 {
+		t.Fatalf("expected metadata, current, config and manifest, got %v", restored)
+	}
+
+	versions, err := restoredStore.LoadMetadata()
+	if err != nil || len(versions) != 1 || versions[0].Number != "1.22.0" {
+		t.Fatalf("unexpected restored metadata: %#v, %v", versions, err)
+	}
+	current, err := restoredStore.GetCurrentVersionMarker()
+	if err != nil || current != "1.22.0" {
+		t.Fatalf("unexpected current marker %q: %v", current, err)
+	}
+	if _, err := restoredStore.LoadManifest("1.22.0"); err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if data, err := os.ReadFile(restoredConfig); err != nil || string(data) != `{"usageStats":true}` {
+		t.Fatalf("unexpected restored config %q: %v", data, err)
+	}
+}
+
+func TestBackupIncludesDownloadsWhenRequested(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root})
+	if err := os.MkdirAll(filepath.Join(root, "downloads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "downloads", "go1.22.0.linux-amd64.tar.gz"), []byte("archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := store.Backup(&buf, BackupOptions{IncludeDownloads: true}); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	dst := t.TempDir()
+	if _, err := NewFileStorage(models.Config{RootDir: dst}).Restore(&buf, RestoreOptions{}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "downloads", "go1.22.0.linux-amd64.tar.gz")); err != nil {
+		t.Fatalf("archive not restored: %v", err)
+	}
+}
+
+func TestRestoreRejectsUnknownEntries(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"../escape", "manifests/../../escape", "versions/go1.22.0/bin/go"} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte("x"))
+		tw.Close()
+		gz.Close()
+
+		root := t.TempDir()
+		if _, err := NewFileStorage(models.Config{RootDir: root}).Restore(&buf, RestoreOptions{}); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 缓存分类，供 `govm cache` 按名称查看或清理。
+const (
+	CacheDownloads = "downloads" // 保留的归档与未完成的 .partial 文件
+	CacheCatalog   = "catalog"   // 版本列表与官方校验值索引缓存
+	CacheRegion    = "region"    // 下载地址测速结论
+)
+
+// mirrorProbeFile 为测速结论在缓存目录中的文件名。
+const mirrorProbeFile = "mirror-probe.json"
+
+// CacheEntry 为缓存中的单个文件。
+type CacheEntry struct {
+	Name    string // 相对于所在缓存目录的路径
+	Size    int64
+	ModTime time.Time
+}
+
+// CacheArea 为一类缓存及其包含的文件，目录不存在时 Entries 为空。
+type CacheArea struct {
+	Name    string
+	Path    string
+	Entries []CacheEntry
+}
+
+// Size 返回该类缓存的总大小。
+func (a CacheArea) Size() int64 {
+	var total int64
+	for _, e := range a.Entries {
+		total += e.Size
+	}
+	return total
+}
+
+// Oldest 返回最早写入的缓存文件时间，没有文件时返回零值。
+func (a CacheArea) Oldest() time.Time {
+	var oldest time.Time
+	for _, e := range a.Entries {
+		if oldest.IsZero() || e.ModTime.Before(oldest) {
+			oldest = e.ModTime
+		}
+	}
+	return oldest
+}
+
+// MirrorProbePath 返回下载地址测速结论的缓存文件路径。
+func (s *FileStorage) MirrorProbePath() string {
+	return filepath.Join(s.CacheDir(), mirrorProbeFile)
+}
+
+// Caches 按 downloads、catalog、region 的顺序列出各类缓存。
+// 缓存目录中不属于下载与测速结论的文件都归入 catalog。
+func (s *FileStorage) Caches() ([]CacheArea, error) {
+	downloads := s.downloadsDir()
+	probe := s.MirrorProbePath()
+
+	areas := []CacheArea{
+		{Name: CacheDownloads, Path: downloads},
+		{Name: CacheCatalog, Path: s.CacheDir()},
+		{Name: CacheRegion, Path: probe},
+	}
+	var err error
+	if areas[0].Entries, err = cacheEntries(downloads, nil); err != nil {
+		return nil, err
+	}
+	if areas[1].Entries, err = cacheEntries(s.CacheDir(), func(path string) bool { return path == downloads || path == probe }); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(probe); err == nil {
+		areas[2].Entries = []CacheEntry{{Name: mirrorProbeFile, Size: info.Size(), ModTime: info.ModTime()}}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("storage: stat %s: %w", probe, err)
+	}
+	return areas, nil
+}
+
+// ClearCache 删除指定类别的缓存（为空时删除全部类别），返回被删除的文件与目录。
+func (s *FileStorage) ClearCache(names ...string) ([]string, error) {
+	areas, err := s.Caches()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = []string{CacheDownloads, CacheCatalog, CacheRegion}
+	}
+
+	var removed []string
+	for _, name := range names {
+		var area *CacheArea
+		for i := range areas {
+			if areas[i].Name == name {
+				area = &areas[i]
+			}
+		}
+		if area == nil {
+			return removed, fmt.Errorf("storage: unknown cache %q (use %s, %s or %s)", name, CacheDownloads, CacheCatalog, CacheRegion)
+		}
+		dir := area.Path
+		if name == CacheRegion {
+			dir = filepath.Dir(area.Path)
+		}
+		for _, e := range area.Entries {
+			path := filepath.Join(dir, e.Name)
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return removed, fmt.Errorf("storage: remove %s: %w", path, err)
+			}
+			removed = append(removed, path)
+			pruneEmptyDirs(filepath.Dir(path), dir)
+		}
+	}
+	return removed, nil
+}
+
+// cacheEntries 递归列出 root 下的普通文件并按名称排序，skip 返回 true 的路径（及其子树）被跳过。
+func cacheEntries(root string, skip func(string) bool) ([]CacheEntry, error) {
+	var entries []CacheEntry
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if skip != nil && path != root && skip(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, CacheEntry{Name: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: scan cache %s: %w", root, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// pruneEmptyDirs 自 dir 向上删除空目录，直到 stop（不含）。
+func pruneEmptyDirs(dir, stop string) {
+	for within(dir, stop) && filepath.Clean(dir) != filepath.Clean(stop) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// seedCaches 写入三类缓存各至少一个文件：两个归档、版本列表与校验值索引、测速结论。
+func seedCaches(t *testing.T, s *FileStorage) {
+	t.Helper()
+	files := map[string]string{
+		filepath.Join(s.downloadsDir(), "go1.22.0.linux-amd64.tar.gz"):         "archive",
+		filepath.Join(s.downloadsDir(), "go1.21.0.linux-amd64.tar.gz.partial"): "part",
+		filepath.Join(s.CacheDir(), "releases.json"):                           "{}",
+		filepath.Join(s.CacheDir(), "checksums", "go.dev", "releases.json"):    "{}",
+		s.MirrorProbePath(): `{"chosen":"https://go.dev/dl/"}`,
+	}
+	for path, body := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCachesGroupsFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	s := NewFileStorage(models.Config{RootDir: root, CacheDir: filepath.Join(root, "cache")})
+	seedCaches(t, s)
+
+	areas, err := s.Caches()
+	if err != nil {
+		t.Fatalf("Caches: %v", err)
+	}
+	got := map[string][]string{}
+	for _, a := range areas {
+		for _, e := range a.Entries {
+			got[a.Name] = append(got[a.Name], e.Name)
+		}
+	}
+	want := map[string][]string{
+		CacheDownloads: {"go1.21.0.linux-amd64.tar.gz.partial", "go1.22.0.linux-amd64.tar.gz"},
+		CacheCatalog:   {"checksums/go.dev/releases.json", "releases.json"},
+		CacheRegion:    {"mirror-probe.json"},
+	}
+	for name, files := range want {
+		if len(got[name]) != len(files) {
+			t.Fatalf("%s entries = %v, want %v", name, got[name], files)
+		}
+		for i := range files {
+			if got[name][i] != files[i] {
+				t.Fatalf("%s entries = %v, want %v", name, got[name], files)
+			}
+		}
+	}
+	if size := areas[0].Size(); size != int64(len("archive")+len("part")) {
+		t.Fatalf("downloads size = %d", size)
+	}
+}
+
+func TestClearCacheByName(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	s := NewFileStorage(models.Config{RootDir: root, CacheDir: filepath.Join(root, "cache")})
+	seedCaches(t, s)
+
+	removed, err := s.ClearCache(CacheCatalog)
+	if err != nil {
+		t.Fatalf("ClearCache: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Fatalf("removed = %v, want the two catalog files", removed)
+	}
+	if _, err := os.Stat(filepath.Join(s.CacheDir(), "checksums")); !os.IsNotExist(err) {
+		t.Fatalf("empty checksums dir left behind: %v", err)
+	}
+	for _, path := range []string{s.MirrorProbePath(), filepath.Join(s.downloadsDir(), "go1.22.0.linux-amd64.tar.gz")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("%s should survive clearing the catalog: %v", path, err)
+		}
+	}
+
+	if _, err := s.ClearCache("bogus"); err == nil {
+		t.Fatal("expected error for unknown cache")
+	}
+	if _, err := s.ClearCache(); err != nil {
+		t.Fatalf("ClearCache all: %v", err)
+	}
+	areas, _ := s.Caches()
+	for _, a := range areas {
+		if len(a.Entries) != 0 {
+			t.Fatalf("%s not cleared: %v", a.Name, a.Entries)
+		}
+	}
+}
+
+func TestCachesMissingDirs(t *testing.T) {
+	t.Parallel()
+
+	areas, err := NewFileStorage(models.Config{RootDir: filepath.Join(t.TempDir(), "absent")}).Caches()
+	if err != nil || len(areas) != 3 {
+		t.Fatalf("Caches on empty root = %v, %v", areas, err)
+	}
+}
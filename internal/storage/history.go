@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// AppendHistory 向 history 文件追加一条当前版本变化记录，文件只追加不改写。
+func (s *FileStorage) AppendHistory(entry models.HistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.At = entry.At.UTC()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	path := s.historyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadHistory 按写入顺序返回全部记录；进程中断留下的半行会被跳过。
+func (s *FileStorage) LoadHistory() ([]models.HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.historyPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []models.HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry models.HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("storage: read history: %w", err)
+	}
+	return entries, nil
+}
+
+// historyPath 与当前版本标记放在同一目录，默认为 ~/.govm/history。
+func (s *FileStorage) historyPath() string {
+	return filepath.Join(filepath.Dir(s.currentPath), "history")
+}
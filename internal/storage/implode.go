@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Implode 删除 govm 的全部本地数据并返回被删除的路径；keepVersions 为 true 时保留版本安装目录。
+// 共享安装模式下只删除当前用户的状态目录，共享根目录需由管理员处理。
+func (s *FileStorage) Implode(keepVersions bool) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root := filepath.Clean(s.cfg.RootDir)
+	if err := checkRemovable(root); err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	remove := func(path string) error {
+		if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err := checkRemovable(path); err != nil {
+			return err
+		}
+		// 只读安装的版本目录没有写权限，直接删除会因 EACCES 失败。
+		if err := RemoveTree(path); err != nil {
+			return fmt.Errorf("storage: remove %s: %w", path, err)
+		}
+		removed = append(removed, path)
+		return nil
+	}
+
+	versionsDir := filepath.Clean(s.versionsDir)
+	if !s.cfg.SystemMode {
+		if keepVersions && filepath.Dir(versionsDir) == root {
+			entries, err := os.ReadDir(root)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("storage: read %s: %w", root, err)
+			}
+			for _, entry := range entries {
+				path := filepath.Join(root, entry.Name())
+				if path == versionsDir {
+					continue
+				}
+				if err := remove(path); err != nil {
+					return removed, err
+				}
+			}
+		} else {
+			if err := remove(root); err != nil {
+				return removed, err
+			}
+			if !keepVersions && !within(versionsDir, root) {
+				if err := remove(versionsDir); err != nil {
+					return removed, err
+				}
+			}
+		}
+	}
+
+	for _, dir := range []string{s.CacheDir(), userStateDir(s.cfg)} {
+		dir = filepath.Clean(dir)
+		if within(dir, root) {
+			continue
+		}
+		if err := remove(dir); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// checkRemovable 拒绝删除根目录或用户主目录这类明显配置错误的路径。
+func checkRemovable(path string) error {
+	path = filepath.Clean(path)
+	if path == "" || path == "." || path == string(filepath.Separator) {
+		return fmt.Errorf("storage: refusing to remove %q", path)
+	}
+	if home, err := os.UserHomeDir(); err == nil && filepath.Clean(home) == path {
+		return fmt.Errorf("storage: refusing to remove home directory %s", path)
+	}
+	return nil
+}
+
+// within 判断 path 是否为 dir 本身或位于其下。
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func seedRoot(t *testing.T, root string) {
+	t.Helper()
+	for _, dir := range []string{"versions/go1.22.0/bin", "downloads", "cache", "bin"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "metadata.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImplodeRemovesRoot(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".govm")
+	seedRoot(t, root)
+	removed, err := NewFileStorage(models.Config{RootDir: root}).Implode(false)
+	if err != nil {
+		t.Fatalf("Implode: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != root {
+		t.Fatalf("unexpected removed paths: %v", removed)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("root still exists: %v", err)
+	}
+}
+
+func TestImplodeRemovesReadOnlyVersions(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".govm")
+	seedRoot(t, root)
+	version := filepath.Join(root, "versions", "go1.22.0")
+	if err := os.WriteFile(filepath.Join(version, "bin", "go"), []byte("go"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+	// 与 readOnlyGoRoot 一样去掉整棵版本目录树的写权限。
+	for _, dir := range []string{filepath.Join(version, "bin"), version} {
+		if err := os.Chmod(dir, 0o555); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { Thaw(root) })
+
+	if _, err := NewFileStorage(models.Config{RootDir: root}).Implode(false); err != nil {
+		t.Fatalf("Implode: %v", err)
+	}
+	if _, err := os.Lstat(root); !os.IsNotExist(err) {
+		t.Fatalf("read-only versions must be removed too: %v", err)
+	}
+}
+
+func TestImplodeKeepVersions(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), ".govm")
+	seedRoot(t, root)
+	removed, err := NewFileStorage(models.Config{RootDir: root}).Implode(true)
+	if err != nil {
+		t.Fatalf("Implode: %v", err)
+	}
+	if len(removed) != 4 {
+		t.Fatalf("expected everything but versions/ removed, got %v", removed)
+	}
+	if _, err := os.Stat(filepath.Join(root, "versions", "go1.22.0", "bin")); err != nil {
+		t.Fatalf("versions must be kept: %v", err)
+	}
+}
+
+func TestImplodeRefusesDangerousRoot(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewFileStorage(models.Config{RootDir: "/"}).Implode(false); err == nil {
+		t.Fatal("expected refusal for /")
+	}
+}
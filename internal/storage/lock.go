@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked 表示其他 govm 进程正持有状态锁。
+var ErrLocked = errors.New("storage: another govm process holds the lock")
+
+// Lock 对根目录下的 govm.lock 加排他锁，串行化 CLI 与守护进程的安装、卸载操作。
+// blocking=false 时若锁已被占用立即返回 ErrLocked。
+func (s *FileStorage) Lock(blocking bool) (func() error, error) {
+	if err := s.ensureRoot(); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(filepath.Dir(s.metadataPath), "govm.lock")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("storage: open lock: %w", err)
+	}
+	if err := lockFile(f, blocking); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() error {
+		defer f.Close()
+		return unlockFile(f)
+	}, nil
+}
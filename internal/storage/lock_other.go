@@ -0,0 +1,9 @@
+//go:build !unix
+
+package storage
+
+import "os"
+
+func lockFile(*os.File, bool) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
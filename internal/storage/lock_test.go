@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestLockIsExclusiveAcrossInstances(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	first := NewFileStorage(models.Config{RootDir: root})
+	second := NewFileStorage(models.Config{RootDir: root})
+
+	unlock, err := first.Lock(false)
+	if err != nil {
+		t.Fatalf("first Lock failed: %v", err)
+	}
+	if _, err := second.Lock(false); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+
+	unlockSecond, err := second.Lock(false)
+	if err != nil {
+		t.Fatalf("Lock after release failed: %v", err)
+	}
+	unlockSecond()
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ErrNoManifest 表示该版本没有记录文件清单（例如在支持清单之前安装）。
+var ErrNoManifest = errors.New("storage: no file manifest recorded")
+
+// ManifestStorage 定义安装文件清单的读写接口，FileStorage 将其保存在 manifests/ 目录。
+type ManifestStorage interface {
+	SaveManifest(manifest models.FileManifest) error
+	LoadManifest(version string) (models.FileManifest, error)
+	DeleteManifest(version string) error
+}
+
+// SaveManifest 保存指定版本的文件清单。
+func (s *FileStorage) SaveManifest(manifest models.FileManifest) error {
+	path, err := s.manifestPath(manifest.Version)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadManifest 读取指定版本的文件清单，不存在时返回 ErrNoManifest。
+func (s *FileStorage) LoadManifest(version string) (models.FileManifest, error) {
+	path, err := s.manifestPath(version)
+	if err != nil {
+		return models.FileManifest{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return models.FileManifest{}, ErrNoManifest
+		}
+		return models.FileManifest{}, err
+	}
+	var manifest models.FileManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return models.FileManifest{}, fmt.Errorf("storage: decode manifest %s: %w", version, err)
+	}
+	return manifest, nil
+}
+
+// DeleteManifest 删除指定版本的文件清单。
+func (s *FileStorage) DeleteManifest(version string) error {
+	path, err := s.manifestPath(version)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStorage) manifestPath(version string) (string, error) {
+	version = strings.TrimSpace(version)
+	if version == "" || strings.ContainsAny(version, `/\`) {
+		return "", fmt.Errorf("storage: invalid manifest version %q", version)
+	}
+	if s.metadataPath == "" {
+		return "", errors.New("metadata path is not configured")
+	}
+	return filepath.Join(s.manifestsDir(), "go"+version+".json"), nil
+}
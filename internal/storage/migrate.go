@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CurrentSchemaVersion 为 metadata.json 的当前结构版本。缺少 schemaVersion 的旧文件视为版本 1。
+const CurrentSchemaVersion = 3
+
+// ErrNewerSchema 表示 metadata.json 由更新版本的 govm 写入，当前版本只能读取、不能改写。
+var ErrNewerSchema = errors.New("storage: metadata was written by a newer govm, upgrade govm to modify it")
+
+// migration 将 metadata 从 from 升级到 from+1，直接操作 JSON 对象以免受当前结构体定义影响。
+type migration struct {
+	from  int
+	apply func(doc map[string]any) error
+}
+
+// migrations 按版本顺序排列；新增结构变更时追加一项并提升 CurrentSchemaVersion。
+var migrations = []migration{
+	{from: 1, apply: migrateV1ToV2},
+	{from: 2, apply: migrateV2ToV3},
+}
+
+// migrateV1ToV2 统一版本号格式：Number 去掉 go 前缀，缺失的 FullName 补齐为 go<Number>。
+func migrateV1ToV2(doc map[string]any) error {
+	list, _ := doc["versions"].([]any)
+	for _, item := range list {
+		v, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("storage: unexpected version entry %v", item)
+		}
+		number, _ := v["Number"].(string)
+		number = strings.TrimPrefix(strings.TrimSpace(number), "go")
+		v["Number"] = number
+		if name, _ := v["FullName"].(string); name == "" && number != "" {
+			v["FullName"] = "go" + number
+		}
+	}
+	return nil
+}
+
+// migrateV2ToV3 引入 External 字段，旧条目均为 govm 安装，无需改写；
+// 提升版本号是为了让不认识 External 的旧版 govm 只读打开，避免其卸载时删除外部 GOROOT。
+func migrateV2ToV3(map[string]any) error {
+	return nil
+}
+
+// schemaOf 返回文件声明的结构版本。
+func schemaOf(doc map[string]any) int {
+	n, ok := doc["schemaVersion"].(float64)
+	if !ok || n < 1 {
+		return 1
+	}
+	return int(n)
+}
+
+// migrateMetadata 将原始 metadata 升级到当前版本，返回升级后的内容、原始版本以及是否发生变化。
+func migrateMetadata(data []byte) ([]byte, int, bool, error) {
+	// 先只解码 schemaVersion：已是当前版本时无需构建完整的通用结构。
+	var probe struct {
+		SchemaVersion float64 `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(data, &probe); err == nil && int(probe.SchemaVersion) >= CurrentSchemaVersion {
+		return data, int(probe.SchemaVersion), false, nil
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, false, err
+	}
+	from := schemaOf(doc)
+	if from >= CurrentSchemaVersion {
+		return data, from, false, nil
+	}
+	for _, m := range migrations {
+		if m.from < from {
+			continue
+		}
+		if err := m.apply(doc); err != nil {
+			return nil, from, false, fmt.Errorf("storage: migrate metadata v%d: %w", m.from, err)
+		}
+	}
+	doc["schemaVersion"] = CurrentSchemaVersion
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, from, false, err
+	}
+	return out, from, true, nil
+}
+
+// persistMigration 先备份原文件再写入升级结果；共享安装模式下无写权限时仅在内存中使用升级结果。
+func (s *FileStorage) persistMigration(original, migrated []byte, from int) error {
+	backup := fmt.Sprintf("%s.v%d.bak", s.metadataPath, from)
+	if err := os.WriteFile(backup, original, 0o644); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return nil
+		}
+		return fmt.Errorf("storage: back up metadata: %w", err)
+	}
+	if err := os.WriteFile(s.metadataPath, migrated, 0o644); err != nil {
+		return fmt.Errorf("storage: write migrated metadata: %w", err)
+	}
+	return s.signLocked(migrated)
+}
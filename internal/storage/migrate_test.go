@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestLoadMetadataMigratesLegacyFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root})
+	legacy := []byte(`{"versions":[{"Number":"go1.20.0","InstallPath":"/tmp/go1.20.0"}]}`)
+	path := filepath.Join(root, "metadata.json")
+	if err := os.WriteFile(path, legacy, 0o644); err != nil {
+		t.Fatalf("write legacy: %v", err)
+	}
+
+	versions, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatalf("LoadMetadata: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Number != "1.20.0" || versions[0].FullName != "go1.20.0" {
+		t.Fatalf("unexpected migrated versions: %#v", versions)
+	}
+
+	backup, err := os.ReadFile(path + ".v1.bak")
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if string(backup) != string(legacy) {
+		t.Fatalf("backup differs from original: %s", backup)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	var file MetadataFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("decode metadata: %v", err)
+	}
+	if file.SchemaVersion != CurrentSchemaVersion {
+		t.Fatalf("expected schema %d on disk, got %d", CurrentSchemaVersion, file.SchemaVersion)
+	}
+}
+
+func TestNewerSchemaIsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root})
+	future := []byte(`{"schemaVersion":99,"versions":[{"Number":"1.30.0","Future":"x"}]}`)
+	path := filepath.Join(root, "metadata.json")
+	if err := os.WriteFile(path, future, 0o644); err != nil {
+		t.Fatalf("write metadata: %v", err)
+	}
+
+	versions, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatalf("LoadMetadata: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Number != "1.30.0" {
+		t.Fatalf("unexpected versions: %#v", versions)
+	}
+
+	err = store.SaveMetadata(models.Version{Number: "1.22.0"})
+	if !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("expected ErrNewerSchema, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != string(future) {
+		t.Fatalf("newer metadata was rewritten: %s", data)
+	}
+}
+
+func TestSaveMetadataWritesSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("SaveMetadata: %v", err)
+	}
+	if _, err := store.LoadMetadata(); err != nil {
+		t.Fatalf("LoadMetadata: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "metadata.json.v1.bak")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("current files must not be backed up: %v", err)
+	}
+}
+
+func TestLoadMetadataUpgradesSchemaV2(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := filepath.Join(root, "metadata.json")
+	if err := os.WriteFile(path, []byte(`{"schemaVersion":2,"versions":[{"Number":"1.22.0"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := NewFileStorage(models.Config{RootDir: root}).LoadMetadata()
+	if err != nil || len(versions) != 1 || versions[0].External {
+		t.Fatalf("unexpected versions: %#v, %v", versions, err)
+	}
+	if _, err := os.Stat(path + ".v2.bak"); err != nil {
+		t.Fatalf("expected v2 backup: %v", err)
+	}
+}
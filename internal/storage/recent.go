@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxRecentVersions 为 previous 文件中保留的历史版本数，供 `govm use -` 在最近的版本被卸载后继续回退。
+const maxRecentVersions = 5
+
+// RecentVersions 返回此前激活过的版本，最近的在前，不含当前版本。
+func (s *FileStorage) RecentVersions() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readRecentLocked()
+}
+
+// recentPath 与当前版本标记放在同一目录，共享安装模式下按用户独立记录。
+func (s *FileStorage) recentPath() string {
+	return filepath.Join(filepath.Dir(s.currentPath), "previous")
+}
+
+func (s *FileStorage) readRecentLocked() ([]string, error) {
+	data, err := os.ReadFile(s.recentPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			versions = append(versions, line)
+		}
+	}
+	return versions, nil
+}
+
+// pushRecentLocked 在当前版本从 previous 切换到 next 时把 previous 移到历史最前面，并移除 next。
+// 清空标记（卸载当前版本）不算一次切换。
+func (s *FileStorage) pushRecentLocked(previous, next string) error {
+	if previous == "" || next == "" || previous == next {
+		return nil
+	}
+	recent, err := s.readRecentLocked()
+	if err != nil {
+		return err
+	}
+	recent = slices.DeleteFunc(recent, func(v string) bool { return v == previous || v == next })
+	recent = append([]string{previous}, recent...)
+	if len(recent) > maxRecentVersions {
+		recent = recent[:maxRecentVersions]
+	}
+	return os.WriteFile(s.recentPath(), []byte(strings.Join(recent, "\n")+"\n"), 0o644)
+}
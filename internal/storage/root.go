@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// EnvStateDirectory 为 systemd 按 StateDirectory= 为服务创建的状态目录，多个目录以冒号分隔时取第一个。
+const EnvStateDirectory = "STATE_DIRECTORY"
+
+// ErrNoRootDir 表示无法确定 govm 根目录：未配置 rootDir、未设置 GOVM_HOME，且既没有可用的 HOME 也没有 STATE_DIRECTORY。
+var ErrNoRootDir = errors.New("storage: cannot determine the govm root directory; set GOVM_HOME or rootDir in the config file")
+
+// ResolveRootDir 返回未显式配置 rootDir 时的根目录，依次尝试 GOVM_HOME/GOVM_ROOT、$HOME/.govm
+// 与 systemd 服务的 $STATE_DIRECTORY；都不可用时返回包装了 ErrNoRootDir 的错误，说明 HOME 为何不可用。
+func ResolveRootDir() (string, error) {
+	if root := EnvRootDir(); root != "" {
+		if !filepath.IsAbs(root) {
+			return "", fmt.Errorf("storage: %s %q must be an absolute path", EnvHome, root)
+		}
+		return root, nil
+	}
+	return homeStateDir(ErrNoRootDir)
+}
+
+// ResolveDirs 为 cfg 填入根目录，共享安装模式下同时填入当前用户的状态目录；
+// 无法确定或不是绝对路径时返回错误，而不是退回临时目录或当前工作目录。
+func ResolveDirs(cfg models.Config) (models.Config, error) {
+	if cfg.RootDir == "" {
+		root, err := ResolveRootDir()
+		if err != nil {
+			return cfg, err
+		}
+		cfg.RootDir = root
+	} else if !filepath.IsAbs(cfg.RootDir) {
+		return cfg, fmt.Errorf("storage: root directory %q must be an absolute path", cfg.RootDir)
+	}
+	if cfg.SystemMode && cfg.UserDir == "" {
+		dir, err := homeStateDir(errors.New("storage: cannot determine the per-user state directory for the shared install; set HOME or STATE_DIRECTORY"))
+		if err != nil {
+			return cfg, err
+		}
+		cfg.UserDir = dir
+	}
+	return cfg, nil
+}
+
+// homeStateDir 返回 $HOME/.govm，HOME 未设置、不是绝对路径或不存在时改用 $STATE_DIRECTORY，都不可用时返回 base 及原因。
+func homeStateDir(base error) (string, error) {
+	reason := "HOME is not set"
+	if home := strings.TrimSpace(os.Getenv("HOME")); home != "" {
+		info, err := os.Stat(home)
+		switch {
+		case !filepath.IsAbs(home):
+			reason = fmt.Sprintf("HOME %q is not an absolute path", home)
+		case err != nil || !info.IsDir():
+			reason = fmt.Sprintf("HOME %s does not exist", home)
+		default:
+			return filepath.Join(home, ".govm"), nil
+		}
+	}
+	if dir, _, _ := strings.Cut(os.Getenv(EnvStateDirectory), ":"); filepath.IsAbs(strings.TrimSpace(dir)) {
+		return filepath.Clean(strings.TrimSpace(dir)), nil
+	}
+	return "", fmt.Errorf("%w (%s)", base, reason)
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// clearRootEnv 清除影响根目录解析的环境变量。
+func clearRootEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{EnvHome, EnvRoot, EnvStateDirectory, "HOME"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestResolveRootDirFallbackOrder(t *testing.T) {
+	clearRootEnv(t)
+	home := t.TempDir()
+	state := t.TempDir()
+
+	t.Setenv(EnvStateDirectory, state+":/var/lib/other")
+	if root, err := ResolveRootDir(); err != nil || root != state {
+		t.Fatalf("without HOME expected STATE_DIRECTORY %s, got %q (%v)", state, root, err)
+	}
+
+	t.Setenv("HOME", home)
+	if root, err := ResolveRootDir(); err != nil || root != filepath.Join(home, ".govm") {
+		t.Fatalf("expected ~/.govm, got %q (%v)", root, err)
+	}
+
+	t.Setenv(EnvHome, filepath.Join(home, "data"))
+	if root, err := ResolveRootDir(); err != nil || root != filepath.Join(home, "data") {
+		t.Fatalf("expected GOVM_HOME, got %q (%v)", root, err)
+	}
+
+	t.Setenv(EnvHome, "relative/govm")
+	if _, err := ResolveRootDir(); err == nil || !strings.Contains(err.Error(), "absolute") {
+		t.Fatalf("expected relative GOVM_HOME to be rejected, got %v", err)
+	}
+}
+
+func TestResolveRootDirWithoutHomeFails(t *testing.T) {
+	clearRootEnv(t)
+	_, err := ResolveRootDir()
+	if !errors.Is(err, ErrNoRootDir) || !strings.Contains(err.Error(), "HOME is not set") {
+		t.Fatalf("expected ErrNoRootDir naming HOME, got %v", err)
+	}
+
+	t.Setenv("HOME", "/nonexistent")
+	if _, err := ResolveRootDir(); !errors.Is(err, ErrNoRootDir) || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected missing HOME to be reported, got %v", err)
+	}
+}
+
+func TestFileStorageWithoutRootReturnsErrors(t *testing.T) {
+	clearRootEnv(t)
+	store := NewFileStorage(models.Config{})
+	if !errors.Is(store.Err(), ErrNoRootDir) {
+		t.Fatalf("Err = %v, want ErrNoRootDir", store.Err())
+	}
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0"}); !errors.Is(err, ErrNoRootDir) {
+		t.Fatalf("SaveMetadata must fail instead of writing elsewhere, got %v", err)
+	}
+	if err := store.SetCurrentVersionMarker("1.22.0"); !errors.Is(err, ErrNoRootDir) {
+		t.Fatalf("SetCurrentVersionMarker must fail, got %v", err)
+	}
+	if _, err := store.Lock(false); !errors.Is(err, ErrNoRootDir) {
+		t.Fatalf("Lock must fail, got %v", err)
+	}
+	if path := store.GetInstallPath("1.22.0"); path != "" {
+		t.Fatalf("GetInstallPath must not fall back to a temp dir, got %s", path)
+	}
+}
+
+func TestResolveDirsSystemModeNeedsUserDir(t *testing.T) {
+	clearRootEnv(t)
+	if _, err := ResolveDirs(models.Config{RootDir: "/srv/govm", SystemMode: true}); err == nil || !strings.Contains(err.Error(), "per-user state directory") {
+		t.Fatalf("expected per-user state error, got %v", err)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg, err := ResolveDirs(models.Config{RootDir: "/srv/govm", SystemMode: true})
+	if err != nil || cfg.UserDir != filepath.Join(home, ".govm") {
+		t.Fatalf("unexpected system dirs %#v (%v)", cfg, err)
+	}
+}
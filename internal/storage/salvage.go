@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ErrCorruptMetadata 表示 metadata.json 无法解析，存储处于只读模式，需先执行 `govm doctor --repair`。
+var ErrCorruptMetadata = errors.New("storage: metadata.json is corrupt, run `govm doctor --repair` before changing installs")
+
+// MetadataDamage 描述无法解析的 metadata.json：Err 为解析错误，Backup 为原文件的备份（无写权限时为空），
+// Salvaged 为按条目宽松解析后保留下来的版本。
+type MetadataDamage struct {
+	Err      error
+	Backup   string
+	Salvaged []models.Version
+}
+
+// MetadataDamage 返回 metadata.json 的损坏情况，文件完好或不存在时返回 nil。
+func (s *FileStorage) MetadataDamage() *MetadataDamage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.readMetadataLocked(); err != nil || s.damage == nil {
+		return nil
+	}
+	damage := *s.damage
+	damage.Salvaged = slices.Clone(damage.Salvaged)
+	return &damage
+}
+
+// RepairMetadata 用抢救出的条目重写 metadata.json 并退出只读模式，返回保留的版本数；文件完好时不做改动。
+func (s *FileStorage) RepairMetadata() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.readMetadataLocked(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	if s.damage == nil {
+		return 0, nil
+	}
+	versions := s.damage.Salvaged
+	s.damage, s.diskSchema = nil, CurrentSchemaVersion
+	if err := s.writeMetadataLocked(versions); err != nil {
+		return 0, err
+	}
+	return len(versions), nil
+}
+
+// salvageLocked 在 metadata.json 无法解析时备份原文件并逐条抢救版本记录，进入只读模式。
+func (s *FileStorage) salvageLocked(data []byte, cause error) []models.Version {
+	versions := salvageVersions(data)
+	backup := s.metadataPath + ".corrupt"
+	if existing, err := os.ReadFile(backup); err != nil || !bytes.Equal(existing, data) {
+		// 共享安装模式下普通用户没有写权限，仍可只读查看抢救出的条目。
+		if err := writeFileAtomic(backup, data, 0o644); err != nil {
+			backup = ""
+		}
+	}
+	s.damage = &MetadataDamage{Err: cause, Backup: backup, Salvaged: versions}
+	s.cachedRaw, s.cached = data, slices.Clone(versions)
+	return versions
+}
+
+// salvageVersions 宽松解析 versions 数组：逐个解码条目，跳过字段类型不符的条目，遇到语法错误（例如文件被截断）时
+// 保留此前已解码的条目。
+func salvageVersions(data []byte) []models.Version {
+	versions := []models.Version{}
+	start := bytes.Index(data, []byte(`"versions"`))
+	if start < 0 {
+		return versions
+	}
+	rest := data[start+len(`"versions"`):]
+	open := bytes.IndexByte(rest, '[')
+	if open < 0 || string(bytes.TrimSpace(rest[:open])) != ":" {
+		return versions
+	}
+	dec := json.NewDecoder(bytes.NewReader(rest[open:]))
+	if _, err := dec.Token(); err != nil {
+		return versions
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			break
+		}
+		var v models.Version
+		if err := json.Unmarshal(raw, &v); err != nil || v.Number == "" {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
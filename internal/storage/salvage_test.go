@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestCorruptMetadataIsSalvagedReadOnly(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := filepath.Join(root, "metadata.json")
+	// 第二个条目的字段类型错误，第三个条目被截断。
+	corrupt := `{"schemaVersion":3,"versions":[{"Number":"1.22.0"},{"Number":1},{"Number":"1.21.0"},{"Number":"1.20`
+	if err := os.WriteFile(path, []byte(corrupt), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStorage(models.Config{RootDir: root})
+
+	versions, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatalf("corrupt metadata must still load: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Number != "1.22.0" || versions[1].Number != "1.21.0" {
+		t.Fatalf("unexpected salvaged versions: %#v", versions)
+	}
+	damage := store.MetadataDamage()
+	if damage == nil || damage.Err == nil || damage.Backup != path+".corrupt" {
+		t.Fatalf("unexpected damage report: %#v", damage)
+	}
+	if backup, err := os.ReadFile(damage.Backup); err != nil || string(backup) != corrupt {
+		t.Fatalf("backup must keep the original file: %v", err)
+	}
+	if err := store.SaveMetadata(models.Version{Number: "1.23.0"}); !errors.Is(err, ErrCorruptMetadata) {
+		t.Fatalf("writes must be refused while degraded, got %v", err)
+	}
+
+	kept, err := store.RepairMetadata()
+	if err != nil || kept != 2 {
+		t.Fatalf("RepairMetadata = %d, %v", kept, err)
+	}
+	if store.MetadataDamage() != nil {
+		t.Fatal("repaired metadata must parse")
+	}
+	if err := store.SaveMetadata(models.Version{Number: "1.23.0"}); err != nil {
+		t.Fatalf("SaveMetadata after repair: %v", err)
+	}
+	if versions, err := NewFileStorage(models.Config{RootDir: root}).LoadMetadata(); err != nil || len(versions) != 3 {
+		t.Fatalf("unexpected metadata after repair: %#v, %v", versions, err)
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrMetadataTampered 表示 metadata.json 与 govm 上次写入时的签名不符，即文件在 govm 之外被修改。
+var ErrMetadataTampered = errors.New("storage: metadata.json was modified outside govm")
+
+// signingKeySize 为本机 HMAC 密钥的字节数。
+const signingKeySize = 32
+
+// signaturePath 返回 metadata.json 签名文件的路径。
+func (s *FileStorage) signaturePath() string {
+	return s.metadataPath + ".sig"
+}
+
+// signingKeyPath 返回本机签名密钥的路径；密钥只对属主可读，不随备份导出。
+func (s *FileStorage) signingKeyPath() string {
+	return filepath.Join(s.cfg.RootDir, "metadata.key")
+}
+
+// VerifyMetadata 在启用 signMetadata 时用本机密钥校验 metadata.json 的签名，文件被外部修改、签名或密钥被删除时返回
+// ErrMetadataTampered。首次启用（密钥与签名都不存在）时直接为当前内容签名；密钥不可读（共享安装中的普通用户）时不做校验。
+func (s *FileStorage) VerifyMetadata() error {
+	if s.err != nil || !s.cfg.SignMetadata {
+		return s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.metadataPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	key, err := os.ReadFile(s.signingKeyPath())
+	switch {
+	case errors.Is(err, os.ErrNotExist) && s.signatureExists():
+		// 删除密钥后再改写 metadata.json 不能让新密钥的签名掩盖修改。
+		return fmt.Errorf("%w (signing key %s is missing)", ErrMetadataTampered, s.signingKeyPath())
+	case errors.Is(err, os.ErrNotExist):
+		return s.signLocked(data)
+	case errors.Is(err, os.ErrPermission):
+		return nil
+	case err != nil:
+		return fmt.Errorf("storage: read signing key: %w", err)
+	}
+	stored, err := os.ReadFile(s.signaturePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w (signature file %s is missing)", ErrMetadataTampered, s.signaturePath())
+	}
+	if err != nil {
+		return err
+	}
+	want, err := hex.DecodeString(string(bytes.TrimSpace(stored)))
+	if err != nil || !hmac.Equal(want, metadataMAC(key, data)) {
+		return ErrMetadataTampered
+	}
+	return nil
+}
+
+// AcceptMetadata 以当前内容重新签名 metadata.json，确认外部修改后由 `govm doctor --repair` 调用。
+func (s *FileStorage) AcceptMetadata() error {
+	if s.err != nil || !s.cfg.SignMetadata {
+		return s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.metadataPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.signLocked(data)
+}
+
+// signatureExists 报告签名文件是否存在。
+func (s *FileStorage) signatureExists() bool {
+	_, err := os.Lstat(s.signaturePath())
+	return err == nil
+}
+
+// signLocked 在启用 signMetadata 时为 data 写入签名，密钥不存在时先生成。
+func (s *FileStorage) signLocked(data []byte) error {
+	if !s.cfg.SignMetadata {
+		return nil
+	}
+	key, err := s.signingKeyLocked()
+	if err != nil {
+		return err
+	}
+	return s.writeSignatureLocked(key, data)
+}
+
+// writeKeyLocked 在写入 metadata.json 之前取得签名密钥，使密钥问题在文件改动之前暴露。返回 nil 密钥表示本次写入不签名：
+// 密钥不可读（共享安装中的普通用户，与 VerifyMetadata 一致），或签名存在而密钥已被删除——此时保留旧签名，
+// 篡改提示持续到 `govm doctor --repair` 接受当前内容为止。
+func (s *FileStorage) writeKeyLocked() ([]byte, error) {
+	if !s.cfg.SignMetadata {
+		return nil, nil
+	}
+	if _, err := os.Lstat(s.signingKeyPath()); errors.Is(err, os.ErrNotExist) && s.signatureExists() {
+		return nil, nil
+	}
+	key, err := s.signingKeyLocked()
+	if errors.Is(err, os.ErrPermission) {
+		return nil, nil
+	}
+	return key, err
+}
+
+// writeSignatureLocked 用 key 为 data 写入签名，key 为 nil 时不做任何事。
+func (s *FileStorage) writeSignatureLocked(key, data []byte) error {
+	if key == nil {
+		return nil
+	}
+	sig := hex.EncodeToString(metadataMAC(key, data)) + "\n"
+	if err := writeFileAtomic(s.signaturePath(), []byte(sig), 0o644); err != nil {
+		return fmt.Errorf("storage: write metadata signature: %w", err)
+	}
+	return nil
+}
+
+// signingKeyLocked 读取本机签名密钥，不存在时生成一个随机密钥并以 0600 权限保存。
+func (s *FileStorage) signingKeyLocked() ([]byte, error) {
+	path := s.signingKeyPath()
+	key, err := os.ReadFile(path)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("storage: read signing key: %w", err)
+	}
+	key = make([]byte, signingKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("storage: generate signing key: %w", err)
+	}
+	if err := writeFileAtomic(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("storage: save signing key: %w", err)
+	}
+	return key, nil
+}
+
+// metadataMAC 计算 metadata.json 内容的 HMAC-SHA256。
+func metadataMAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
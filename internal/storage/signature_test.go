@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestMetadataSignatureDetectsOutsideEdits(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root, SignMetadata: true})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("SaveMetadata failed: %v", err)
+	}
+	if err := store.VerifyMetadata(); err != nil {
+		t.Fatalf("metadata written by govm must verify: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(root, "metadata.key")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private signing key: %v", err)
+	}
+
+	path := filepath.Join(root, "metadata.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.VerifyMetadata(); !errors.Is(err, ErrMetadataTampered) {
+		t.Fatalf("edited metadata must be reported, got %v", err)
+	}
+	if err := store.AcceptMetadata(); err != nil {
+		t.Fatalf("AcceptMetadata failed: %v", err)
+	}
+	if err := store.VerifyMetadata(); err != nil {
+		t.Fatalf("accepted metadata must verify: %v", err)
+	}
+
+	if err := os.Remove(path + ".sig"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.VerifyMetadata(); !errors.Is(err, ErrMetadataTampered) {
+		t.Fatalf("a removed signature must be reported, got %v", err)
+	}
+}
+
+func TestMetadataSignatureTrustsFirstUse(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := NewFileStorage(models.Config{RootDir: root}).SaveMetadata(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("SaveMetadata failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "metadata.json.sig")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unsigned store must not write a signature: %v", err)
+	}
+
+	store := NewFileStorage(models.Config{RootDir: root, SignMetadata: true})
+	if err := store.VerifyMetadata(); err != nil {
+		t.Fatalf("enabling signMetadata must sign the existing file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "metadata.json.sig")); err != nil {
+		t.Fatalf("expected a signature after first use: %v", err)
+	}
+}
+
+func TestMetadataSignatureReportsMissingKey(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root, SignMetadata: true})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("SaveMetadata failed: %v", err)
+	}
+	path := filepath.Join(root, "metadata.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, "metadata.key")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.VerifyMetadata(); !errors.Is(err, ErrMetadataTampered) {
+		t.Fatalf("a removed key must be reported, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "metadata.key")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("verification must not create a new key: %v", err)
+	}
+
+	// 写入不会用新密钥为改动重新签名，篡改提示一直保留到 AcceptMetadata。
+	if err := store.SaveMetadata(models.Version{Number: "1.21.0"}); err != nil {
+		t.Fatalf("SaveMetadata failed: %v", err)
+	}
+	if err := store.VerifyMetadata(); !errors.Is(err, ErrMetadataTampered) {
+		t.Fatalf("a write must not hide the missing key, got %v", err)
+	}
+	if err := store.AcceptMetadata(); err != nil {
+		t.Fatalf("AcceptMetadata failed: %v", err)
+	}
+	if err := store.VerifyMetadata(); err != nil {
+		t.Fatalf("accepted metadata must verify: %v", err)
+	}
+}
+
+func TestMetadataWriteToleratesUnreadableKey(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("root can read any file")
+	}
+
+	root := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root, SignMetadata: true})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("SaveMetadata failed: %v", err)
+	}
+	key := filepath.Join(root, "metadata.key")
+	if err := os.Chmod(key, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(key, 0o600) })
+
+	other := NewFileStorage(models.Config{RootDir: root, SignMetadata: true})
+	if err := other.SaveMetadata(models.Version{Number: "1.21.0"}); err != nil {
+		t.Fatalf("an unreadable key must not fail a write: %v", err)
+	}
+	versions, err := other.LoadMetadata()
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("LoadMetadata = %v, %v; want both versions", versions, err)
+	}
+}
@@ -1,12 +1,13 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
@@ -30,39 +31,97 @@ type FileStorage struct {
 	currentPath  string
 	versionsDir  string
 	mu           sync.Mutex
+	// diskSchema 为最近一次读取到的文件结构版本，高于当前版本时拒绝写入。
+	diskSchema int
+	// cachedRaw 与 cached 为最近一次读写的文件内容及其解析结果，内容未变时跳过 JSON 解码。
+	cachedRaw []byte
+	cached    []models.Version
+	// damage 非空时 metadata.json 无法解析，读取返回抢救出的条目，写入被拒绝，直到 RepairMetadata。
+	damage *MetadataDamage
+	// err 为构造时无法确定目录的原因。
+	err error
 }
 
 // MetadataFile 表示 metadata.json 的结构。
 type MetadataFile struct {
-	Versions []models.Version `json:"versions"`
+	SchemaVersion int              `json:"schemaVersion"`
+	Versions      []models.Version `json:"versions"`
 }
 
-// NewFileStorage 构造一个文件系统存储实例。
-func NewFileStorage(cfg models.Config) *FileStorage {
-	root := cfg.RootDir
-	if root == "" {
-		if home, err := os.UserHomeDir(); err == nil {
-			root = filepath.Join(home, ".govm")
+const (
+	// EnvHome 指定 govm 根目录，未配置 rootDir 时替代 ~/.govm。
+	EnvHome = "GOVM_HOME"
+	// EnvRoot 为 EnvHome 的别名，两者同时设置时以 GOVM_HOME 为准。
+	EnvRoot = "GOVM_ROOT"
+)
+
+// EnvRootDir 返回 GOVM_HOME（或 GOVM_ROOT）指定的根目录，未设置时返回空串。
+func EnvRootDir() string {
+	for _, key := range []string{EnvHome, EnvRoot} {
+		v := strings.TrimSpace(os.Getenv(key))
+		if v == "" {
+			continue
+		}
+		if v == "~" || strings.HasPrefix(v, "~/") {
+			if home, err := os.UserHomeDir(); err == nil && home != "" {
+				v = filepath.Join(home, strings.TrimPrefix(v, "~"))
+			}
 		}
+		return filepath.Clean(v)
+	}
+	return ""
+}
+
+// DefaultRootDir 返回未显式配置时的根目录（顺序见 ResolveRootDir），无法确定时返回空串。
+func DefaultRootDir() string {
+	root, _ := ResolveRootDir()
+	return root
+}
+
+// NewFileStorage 构造一个文件系统存储实例；无法确定根目录时，读写操作均返回 ResolveDirs 给出的错误。
+func NewFileStorage(cfg models.Config) *FileStorage {
+	cfg, err := ResolveDirs(cfg)
+	if err != nil {
+		return &FileStorage{cfg: cfg, err: err}
 	}
 	versionsDir := cfg.VersionsDir
 	if versionsDir == "" {
-		if root != "" {
-			versionsDir = filepath.Join(root, "versions")
-		}
+		versionsDir = filepath.Join(cfg.RootDir, "versions")
 	}
-	cfg.RootDir = root
 	cfg.VersionsDir = versionsDir
 	return &FileStorage{
 		cfg:          cfg,
-		metadataPath: filepath.Join(root, "metadata.json"),
-		currentPath:  filepath.Join(root, "current"),
+		metadataPath: filepath.Join(cfg.RootDir, "metadata.json"),
+		currentPath:  CurrentMarkerPath(cfg),
 		versionsDir:  versionsDir,
 	}
 }
 
+// Err 返回构造时确定目录失败的原因，目录可用时返回 nil。
+func (s *FileStorage) Err() error {
+	return s.err
+}
+
+// CurrentMarkerPath 返回 cfg（已经过 ResolveDirs）对应的当前版本标记文件，供无需构造 FileStorage 的快速路径读取。
+func CurrentMarkerPath(cfg models.Config) string {
+	return filepath.Join(userStateDir(cfg), "current")
+}
+
+// userStateDir 返回当前版本标记所在目录：共享安装模式下每个用户各自维护（由 ResolveDirs 填入），否则与根目录一致。
+func userStateDir(cfg models.Config) string {
+	if cfg.UserDir != "" {
+		return cfg.UserDir
+	}
+	return cfg.RootDir
+}
+
 // SaveMetadata 保存或更新版本元数据。
 func (s *FileStorage) SaveMetadata(version models.Version) error {
+	return s.SaveMetadataBatch([]models.Version{version})
+}
+
+// SaveMetadataBatch 在一次读写中保存或更新多个版本的元数据。
+func (s *FileStorage) SaveMetadataBatch(updates []models.Version) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -79,19 +138,20 @@ func (s *FileStorage) SaveMetadata(version models.Version) error {
 		}
 	}
 
-	updated := false
+	for _, version := range updates {
+		versions = upsertVersion(versions, version)
+	}
+	return s.writeMetadataLocked(versions)
+}
+
+func upsertVersion(versions []models.Version, version models.Version) []models.Version {
 	for i := range versions {
 		if versions[i].Number == version.Number {
 			versions[i] = version
-			updated = true
-			break
+			return versions
 		}
 	}
-	if !updated {
-		versions = append(versions, version)
-	}
-
-	return s.writeMetadataLocked(versions)
+	return append(versions, version)
 }
 
 // LoadMetadata 读取所有本地元数据。
@@ -131,15 +191,17 @@ func (s *FileStorage) DeleteMetadata(version string) error {
 
 // GetInstallPath 返回指定版本的安装目录。
 func (s *FileStorage) GetInstallPath(version string) string {
-	dir := s.versionsDir
-	if dir == "" {
-		dir = filepath.Join(os.TempDir(), "govm", "versions")
+	if s.versionsDir == "" {
+		return ""
 	}
-	return filepath.Join(dir, fmt.Sprintf("go%s", version))
+	return filepath.Join(s.versionsDir, fmt.Sprintf("go%s", version))
 }
 
 // GetCurrentVersionMarker 读取当前版本标记。
 func (s *FileStorage) GetCurrentVersionMarker() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -153,19 +215,88 @@ func (s *FileStorage) GetCurrentVersionMarker() (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
-// SetCurrentVersionMarker 写入当前版本标记。
+// SetCurrentVersionMarker 写入当前版本标记，并把被替换的版本记入最近使用的历史。
 func (s *FileStorage) SetCurrentVersionMarker(version string) error {
+	if s.err != nil {
+		return s.err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if err := s.ensureRoot(); err != nil {
+	if err := os.MkdirAll(filepath.Dir(s.currentPath), 0o755); err != nil {
+		return err
+	}
+
+	version = strings.TrimSpace(version)
+	previous, _ := os.ReadFile(s.currentPath)
+	// 先写临时文件再重命名，WatchCurrent 等并发读者不会读到被截断的标记。
+	if err := writeFileAtomic(s.currentPath, []byte(version), 0o644); err != nil {
 		return err
 	}
+	// 历史只用于 `govm use -`，写入失败不影响切换结果。
+	_ = s.pushRecentLocked(strings.TrimSpace(string(previous)), version)
+	return nil
+}
+
+// CacheDir 返回当前用户的缓存目录，共享安装模式下同样位于用户自己的状态目录中。
+func (s *FileStorage) CacheDir() string {
+	if s.cfg.CacheDir != "" {
+		return s.cfg.CacheDir
+	}
+	return filepath.Join(userStateDir(s.cfg), "cache")
+}
+
+// ShimDir 返回版本化 shim（go1.22.0 等）所在目录，位于根目录下，与安装目录一同共享。
+func (s *FileStorage) ShimDir() string {
+	return filepath.Join(s.cfg.RootDir, "bin")
+}
+
+// SetLocked 设置版本的锁定状态，版本未安装时返回错误。
+func (s *FileStorage) SetLocked(version string, locked bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.readMetadataLocked()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := range versions {
+		if versions[i].Number == version {
+			if versions[i].Locked == locked {
+				return nil
+			}
+			versions[i].Locked = locked
+			return s.writeMetadataLocked(versions)
+		}
+	}
+	return fmt.Errorf("storage: version %s not installed", version)
+}
+
+// RelocateInstalls 将元数据中位于 oldDir 下的安装路径改写到 newDir，用于目录布局迁移。
+func (s *FileStorage) RelocateInstalls(oldDir, newDir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return os.WriteFile(s.currentPath, []byte(strings.TrimSpace(version)), 0o644)
+	versions, err := s.readMetadataLocked()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	prefix := filepath.Clean(oldDir) + string(filepath.Separator)
+	for i, v := range versions {
+		if strings.HasPrefix(v.InstallPath, prefix) {
+			versions[i].InstallPath = filepath.Join(newDir, strings.TrimPrefix(v.InstallPath, prefix))
+		}
+	}
+	return s.writeMetadataLocked(versions)
 }
 
 func (s *FileStorage) ensureRoot() error {
+	if s.err != nil {
+		return s.err
+	}
 	if s.metadataPath == "" {
 		return errors.New("metadata path is not configured")
 	}
@@ -173,48 +304,126 @@ func (s *FileStorage) ensureRoot() error {
 }
 
 func (s *FileStorage) readMetadataLocked() ([]models.Version, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
 	if s.metadataPath == "" {
 		return nil, errors.New("metadata path is not configured")
 	}
 
-	file, err := os.Open(s.metadataPath)
+	data, err := os.ReadFile(s.metadataPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return []models.Version{}, os.ErrNotExist
 		}
 		return nil, err
 	}
-	defer file.Close()
 
-	bytes, err := io.ReadAll(file)
-	if err != nil {
-		return nil, err
+	if len(data) == 0 {
+		return []models.Version{}, nil
+	}
+	if s.cached != nil && bytes.Equal(data, s.cachedRaw) {
+		return slices.Clone(s.cached), nil
 	}
 
-	if len(bytes) == 0 {
-		return []models.Version{}, nil
+	migrated, from, changed, err := migrateMetadata(data)
+	if err != nil {
+		return s.salvageLocked(data, err), nil
+	}
+	s.diskSchema = from
+	if changed {
+		if err := s.persistMigration(data, migrated, from); err != nil {
+			return nil, err
+		}
+		s.diskSchema = CurrentSchemaVersion
 	}
 
 	var metadata MetadataFile
-	if err := json.Unmarshal(bytes, &metadata); err != nil {
-		return nil, err
+	if err := json.Unmarshal(migrated, &metadata); err != nil {
+		return s.salvageLocked(data, err), nil
 	}
 	if metadata.Versions == nil {
 		metadata.Versions = []models.Version{}
 	}
+	s.damage = nil
+	s.cachedRaw, s.cached = data, slices.Clone(metadata.Versions)
 	return metadata.Versions, nil
 }
 
-func (s *FileStorage) writeMetadataLocked(versions []models.Version) error {
+// WriteGuard 描述在改动磁盘前确认元数据可以写入的能力，由 FileStorage 实现；安装、卸载与登记据此在开始前放弃，
+// 而不是删除或解压完文件后才在写入元数据时失败。
+type WriteGuard interface {
+	CheckWritable() error
+}
+
+// CheckWritable 重新读取 metadata.json，文件损坏（ErrCorruptMetadata）或由更新的 govm 写入（ErrNewerSchema）时返回错误。
+func (s *FileStorage) CheckWritable() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.readMetadataLocked(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return s.writableLocked()
+}
+
+// writableLocked 报告最近一次读取的元数据是否允许写入。
+func (s *FileStorage) writableLocked() error {
+	if s.err != nil {
+		return s.err
+	}
 	if s.metadataPath == "" {
 		return errors.New("metadata path is not configured")
 	}
+	if s.damage != nil {
+		return fmt.Errorf("%w (%v)", ErrCorruptMetadata, s.damage.Err)
+	}
+	if s.diskSchema > CurrentSchemaVersion {
+		return fmt.Errorf("%w (file schema %d, supported %d)", ErrNewerSchema, s.diskSchema, CurrentSchemaVersion)
+	}
+	return nil
+}
+
+func (s *FileStorage) writeMetadataLocked(versions []models.Version) error {
+	if err := s.writableLocked(); err != nil {
+		return err
+	}
 
-	metadata := MetadataFile{Versions: versions}
+	metadata := MetadataFile{SchemaVersion: CurrentSchemaVersion, Versions: versions}
 	data, err := json.MarshalIndent(metadata, "", "  ")
 	if err != nil {
 		return err
 	}
+	key, err := s.writeKeyLocked()
+	if err != nil {
+		return err
+	}
 
-	return os.WriteFile(s.metadataPath, data, 0o644)
+	if err := os.WriteFile(s.metadataPath, data, 0o644); err != nil {
+		s.cachedRaw, s.cached = nil, nil
+		return err
+	}
+	s.diskSchema = CurrentSchemaVersion
+	s.cachedRaw, s.cached = data, slices.Clone(versions)
+	return s.writeSignatureLocked(key, data)
+}
+
+// writeFileAtomic 在 path 所在目录写入临时文件后重命名为 path，读者只会看到旧内容或完整的新内容。
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
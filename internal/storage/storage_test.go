@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -101,3 +103,199 @@ func TestDeleteMetadata(t *testing.T) {
 		t.Fatalf("unexpected metadata after delete: %#v", loaded)
 	}
 }
+
+func TestSystemModeKeepsMarkerPerUser(t *testing.T) {
+	t.Parallel()
+
+	shared := t.TempDir()
+	user := t.TempDir()
+	store := NewFileStorage(models.Config{RootDir: shared, SystemMode: true, UserDir: user})
+
+	if err := store.SetCurrentVersionMarker("1.22.0"); err != nil {
+		t.Fatalf("SetCurrentVersionMarker failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(user, "current")); err != nil {
+		t.Fatalf("marker not written to user dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(shared, "current")); !os.IsNotExist(err) {
+		t.Fatalf("marker should not be written to shared root: %v", err)
+	}
+	if got := store.GetInstallPath("1.22.0"); got != filepath.Join(shared, "versions", "go1.22.0") {
+		t.Fatalf("unexpected shared install path: %s", got)
+	}
+}
+
+// metadataBudget 为 LoadMetadata 在文件未变化时允许的最大分配次数：只读文件与复制切片，不做 JSON 解码。
+const metadataBudget = 10
+
+// seedMetadata 写入 n 个版本的元数据，模拟长期使用后积累的大量版本。
+func seedMetadata(tb testing.TB, n int) *FileStorage {
+	tb.Helper()
+	root := tb.TempDir()
+	store := NewFileStorage(models.Config{RootDir: root})
+	versions := make([]models.Version, n)
+	for i := range versions {
+		number := fmt.Sprintf("1.%d.%d", i/10, i%10)
+		versions[i] = models.Version{
+			Number:      number,
+			FullName:    "go" + number,
+			FileName:    "go" + number + ".linux-amd64.tar.gz",
+			Checksum:    "abc123",
+			OS:          "linux",
+			Arch:        "amd64",
+			InstallPath: store.GetInstallPath(number),
+			InstalledAt: time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC),
+		}
+	}
+	if err := store.SaveMetadataBatch(versions); err != nil {
+		tb.Fatalf("SaveMetadataBatch: %v", err)
+	}
+	return store
+}
+
+func TestSaveMetadataBatchUpserts(t *testing.T) {
+	t.Parallel()
+
+	store := seedMetadata(t, 3)
+	if err := store.SaveMetadataBatch([]models.Version{{Number: "1.0.1", IsCurrent: true}, {Number: "1.22.0"}}); err != nil {
+		t.Fatalf("SaveMetadataBatch: %v", err)
+	}
+	loaded, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 4 || !loaded[1].IsCurrent || loaded[1].InstallPath != "" || loaded[3].Number != "1.22.0" {
+		t.Fatalf("unexpected metadata: %#v", loaded)
+	}
+}
+
+func TestLoadMetadataReusesParsedFile(t *testing.T) {
+	store := seedMetadata(t, 300)
+	first, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0].Number = "mutated"
+
+	allocs := testing.AllocsPerRun(20, func() {
+		if _, err := store.LoadMetadata(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > metadataBudget {
+		t.Fatalf("LoadMetadata on an unchanged file made %.0f allocations, budget %d", allocs, metadataBudget)
+	}
+
+	again, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0].Number != "1.0.0" {
+		t.Fatalf("callers share the cached slice: %q", again[0].Number)
+	}
+
+	// 其他进程改写文件后必须重新解析。
+	other := NewFileStorage(models.Config{RootDir: store.cfg.RootDir})
+	if err := other.DeleteMetadata("1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded) != 299 || reloaded[0].Number != "1.0.1" {
+		t.Fatalf("stale metadata after external write: %d versions, first %q", len(reloaded), reloaded[0].Number)
+	}
+}
+
+func BenchmarkLoadMetadata(b *testing.B) {
+	for _, n := range []int{50, 500} {
+		b.Run(fmt.Sprintf("versions=%d", n), func(b *testing.B) {
+			store := seedMetadata(b, n)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := store.LoadMetadata(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLoadMetadataCold(b *testing.B) {
+	store := seedMetadata(b, 500)
+	b.ReportAllocs()
+	for b.Loop() {
+		cold := NewFileStorage(models.Config{RootDir: store.cfg.RootDir})
+		if _, err := cold.LoadMetadata(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSaveMetadata(b *testing.B) {
+	store := seedMetadata(b, 500)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := store.SaveMetadata(models.Version{Number: "1.10.0", IsCurrent: true}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCurrentMarkerKeepsRecentVersions(t *testing.T) {
+	t.Parallel()
+
+	s := NewFileStorage(models.Config{RootDir: t.TempDir()})
+	for _, v := range []string{"1.20.0", "1.21.0", "1.22.0", "1.21.0", "", "1.23.0"} {
+		if err := s.SetCurrentVersionMarker(v); err != nil {
+			t.Fatalf("SetCurrentVersionMarker(%q): %v", v, err)
+		}
+	}
+	recent, err := s.RecentVersions()
+	if err != nil {
+		t.Fatalf("RecentVersions: %v", err)
+	}
+	// 清空标记（卸载当前版本）不算切换，被卸载的 1.21.0 不会进入历史。
+	if want := []string{"1.22.0", "1.20.0"}; !reflect.DeepEqual(recent, want) {
+		t.Fatalf("RecentVersions = %v, want %v", recent, want)
+	}
+
+	for i := range 10 {
+		if err := s.SetCurrentVersionMarker(fmt.Sprintf("1.%d.0", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if recent, _ := s.RecentVersions(); len(recent) != maxRecentVersions || recent[0] != "1.8.0" {
+		t.Fatalf("history not capped: %v", recent)
+	}
+}
+
+func TestHistoryAppendsAndSkipsTornLines(t *testing.T) {
+	t.Parallel()
+
+	s := NewFileStorage(models.Config{RootDir: t.TempDir()})
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []models.HistoryEntry{
+		{At: at, Version: "1.22.0", Command: "govm use 1.22.0"},
+		{At: at.Add(time.Hour), Version: "1.21.0", Previous: "1.22.0", Command: "govm use 1.21.0"},
+	} {
+		if err := s.AppendHistory(e); err != nil {
+			t.Fatalf("AppendHistory: %v", err)
+		}
+	}
+	f, err := os.OpenFile(s.historyPath(), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"at":"2024-06-01T14:00:00Z","vers`)
+	f.Close()
+
+	entries, err := s.LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Previous != "1.22.0" || !entries[1].At.Equal(at.Add(time.Hour)) {
+		t.Fatalf("unexpected history: %#v", entries)
+	}
+}
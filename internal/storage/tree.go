@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Thaw 为 root 下的目录补回属主写权限，使其中的条目可以被删除或改名；文件本身保持原权限，root 不存在时忽略。
+// 只读安装（readOnlyGoRoot）的版本目录在删除或改名前都要先经过它。
+func Thaw(root string) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if mode := info.Mode().Perm(); mode&0o200 == 0 {
+			return os.Chmod(path, mode|0o200)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("make %s writable: %w", root, err)
+	}
+	return nil
+}
+
+// RemoveTree 先恢复目录的写权限再整体删除，只读安装同样可以清理。
+func RemoveTree(root string) error {
+	if err := Thaw(root); err != nil {
+		return err
+	}
+	return os.RemoveAll(root)
+}
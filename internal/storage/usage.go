@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// usageFile 表示 usage.json 的结构。
+type usageFile struct {
+	Versions map[string]models.Usage `json:"versions"`
+}
+
+// RecordUsage 为指定版本累加一次激活计数并更新最近使用时间。
+func (s *FileStorage) RecordUsage(version string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage, err := s.readUsageLocked()
+	if err != nil {
+		return err
+	}
+	version = strings.TrimSpace(version)
+	entry := usage[version]
+	entry.Count++
+	entry.LastUsedAt = at.UTC()
+	usage[version] = entry
+
+	path := s.usagePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(usageFile{Versions: usage}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadUsage 读取所有版本的使用统计。
+func (s *FileStorage) LoadUsage() (map[string]models.Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readUsageLocked()
+}
+
+// usagePath 与当前版本标记放在同一目录，共享安装模式下按用户独立统计。
+func (s *FileStorage) usagePath() string {
+	return filepath.Join(filepath.Dir(s.currentPath), "usage.json")
+}
+
+func (s *FileStorage) readUsageLocked() (map[string]models.Usage, error) {
+	data, err := os.ReadFile(s.usagePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]models.Usage{}, nil
+		}
+		return nil, err
+	}
+	var file usageFile
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	}
+	if file.Versions == nil {
+		file.Versions = map[string]models.Usage{}
+	}
+	return file.Versions, nil
+}
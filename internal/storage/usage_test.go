@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestRecordUsageAccumulates(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileStorage(models.Config{RootDir: t.TempDir()})
+	first := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	if err := store.RecordUsage("1.22.0", first); err != nil {
+		t.Fatalf("RecordUsage failed: %v", err)
+	}
+	if err := store.RecordUsage("1.22.0", second); err != nil {
+		t.Fatalf("RecordUsage failed: %v", err)
+	}
+
+	usage, err := store.LoadUsage()
+	if err != nil {
+		t.Fatalf("LoadUsage failed: %v", err)
+	}
+	got := usage["1.22.0"]
+	if got.Count != 2 || !got.LastUsedAt.Equal(second) {
+		t.Fatalf("unexpected usage: %#v", got)
+	}
+	if _, ok := usage["1.21.0"]; ok {
+		t.Fatalf("unexpected entry for unused version: %#v", usage)
+	}
+}
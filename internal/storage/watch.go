@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultWatchInterval 为 WatchCurrent 未指定间隔时的轮询间隔。
+const DefaultWatchInterval = time.Second
+
+// CurrentChange 描述一次当前版本变化，版本号为空表示没有激活版本。
+type CurrentChange struct {
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// WatchCurrent 返回当前版本标记的初始值，并在标记变化时向通道发送 CurrentChange；ctx 结束后关闭通道。
+// 标记文件只有几个字节，这里采用轮询而非 inotify：不依赖平台接口，标记被原子替换或所在目录重建时也不会丢失变化。
+// 轮询中的读取错误（例如目录暂时不存在）会被忽略，下一次轮询再试。
+func (s *FileStorage) WatchCurrent(ctx context.Context, interval time.Duration) (string, <-chan CurrentChange, error) {
+	current, err := s.GetCurrentVersionMarker()
+	if err != nil {
+		return "", nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	changes := make(chan CurrentChange)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last := current
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			next, err := s.GetCurrentVersionMarker()
+			if err != nil || next == last {
+				continue
+			}
+			select {
+			case changes <- CurrentChange{Previous: last, Current: next}:
+				last = next
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return current, changes, nil
+}
+
+// StateFiles 返回记录当前版本与已安装版本的文件，编辑器插件可监听它们而不必轮询 govm。两者都通过重命名原子替换，
+// 监听时应关注所在目录中同名文件的创建事件，而不是原文件的修改事件。
+func (s *FileStorage) StateFiles() []string {
+	return []string{s.currentPath, s.metadataPath}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestWatchCurrentReportsChanges(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileStorage(models.Config{RootDir: t.TempDir()})
+	if err := store.SetCurrentVersionMarker("1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	current, changes, err := store.WatchCurrent(ctx, 5*time.Millisecond)
+	if err != nil || current != "1.21.0" {
+		t.Fatalf("WatchCurrent = %q, %v", current, err)
+	}
+
+	for _, next := range []string{"1.22.0", ""} {
+		if err := store.SetCurrentVersionMarker(next); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case change := <-changes:
+			if change.Current != next || change.Previous != current {
+				t.Fatalf("unexpected change %#v, want %q -> %q", change, current, next)
+			}
+			current = next
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no change reported for %q", next)
+		}
+	}
+
+	cancel()
+	for range changes {
+	}
+}
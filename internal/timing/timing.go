@@ -0,0 +1,135 @@
+// Package timing 记录一次命令中各阶段的耗时，供 -verbose 输出汇总以及慢路径警告使用。
+package timing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liangyou/govm/internal/events"
+)
+
+// 阶段名称，同时作为 -verbose 汇总中的标签。
+const (
+	Region   = "region detection"
+	Catalog  = "release list"
+	Download = "download"
+	Extract  = "extract"
+	// Stream 为流式安装中重叠进行的下载与解压，两者无法分开计时。
+	Stream = "download + extract"
+)
+
+// Phase 为一个阶段的累计耗时，Count 为该阶段发生的次数。
+type Phase struct {
+	Name     string
+	Duration time.Duration
+	Count    int
+}
+
+// Recorder 按首次出现的顺序累计各阶段耗时，可并发使用；nil Recorder 上的操作是安全的空操作。
+type Recorder struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	phases []Phase
+}
+
+// Option 调整 Recorder。
+type Option func(*Recorder)
+
+// WithClock 替换时钟，测试中可以注入固定步长的时钟。
+func WithClock(now func() time.Time) Option {
+	return func(r *Recorder) {
+		r.now = now
+	}
+}
+
+// NewRecorder 创建 Recorder。
+func NewRecorder(opts ...Option) *Recorder {
+	r := &Recorder{now: time.Now}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Add 把 d 计入 name 阶段。
+func (r *Recorder) Add(name string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.phases {
+		if r.phases[i].Name == name {
+			r.phases[i].Duration += d
+			r.phases[i].Count++
+			return
+		}
+	}
+	r.phases = append(r.phases, Phase{Name: name, Duration: d, Count: 1})
+}
+
+// Start 开始计时，调用返回的函数结束计时、计入 name 阶段并返回本次耗时。
+func (r *Recorder) Start(name string) func() time.Duration {
+	if r == nil {
+		return func() time.Duration { return 0 }
+	}
+	start := r.now()
+	return func() time.Duration {
+		d := r.now().Sub(start)
+		r.Add(name, d)
+		return d
+	}
+}
+
+// Phases 返回目前记录的全部阶段。
+func (r *Recorder) Phases() []Phase {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Phase(nil), r.phases...)
+}
+
+// Observe 根据安装事件为下载与解压计时，返回的函数用于取消订阅：
+// download_start 到 extract 为下载，extract 到 done 为解压；流式安装的 extract 先于 download_start，整段计为 Stream。
+func (r *Recorder) Observe(bus *events.Bus) func() {
+	if r == nil {
+		return func() {}
+	}
+	type running struct {
+		phase string
+		start time.Time
+	}
+	var mu sync.Mutex
+	inFlight := map[string]running{}
+	return bus.Subscribe(func(e events.Event) {
+		ver := e.Get("version")
+		now := r.now()
+		mu.Lock()
+		defer mu.Unlock()
+		current, ok := inFlight[ver]
+		finish := func() {
+			if ok {
+				r.Add(current.phase, now.Sub(current.start))
+			}
+		}
+		switch e.Type {
+		case events.DownloadStart:
+			if ok && current.phase == Stream {
+				return
+			}
+			inFlight[ver] = running{phase: Download, start: now}
+		case events.Extract:
+			finish()
+			phase := Extract
+			if e.Get("mode") == "stream" {
+				phase = Stream
+			}
+			inFlight[ver] = running{phase: phase, start: now}
+		case events.Done, events.Error:
+			finish()
+			delete(inFlight, ver)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package timing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liangyou/govm/internal/events"
+)
+
+// stepClock 每次调用前进一秒。
+func stepClock() func() time.Time {
+	now := time.Unix(0, 0)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func TestObserveTimesInstallPhases(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder(WithClock(stepClock()))
+	bus := events.NewBus()
+	unsubscribe := r.Observe(bus)
+	defer unsubscribe()
+
+	stop := r.Start(Region)
+	if d := stop(); d != time.Second {
+		t.Fatalf("Start/stop = %s, want 1s", d)
+	}
+	for _, e := range []events.Event{
+		events.New(events.DownloadStart, "version", "1.22.0"),
+		events.New(events.DownloadProgress, "version", "1.22.0"),
+		events.New(events.Extract, "version", "1.22.0"),
+		events.New(events.Done, "version", "1.22.0"),
+		// 流式安装：extract 先于 download_start，整段计为一个阶段。
+		events.New(events.Extract, "version", "1.21.0", "mode", "stream"),
+		events.New(events.DownloadStart, "version", "1.21.0"),
+		events.New(events.Done, "version", "1.21.0"),
+		events.New(events.Done, "version", "1.20.0", "status", "already_installed"),
+	} {
+		bus.Publish(e)
+	}
+
+	want := []Phase{
+		{Name: Region, Duration: time.Second, Count: 1},
+		{Name: Download, Duration: 2 * time.Second, Count: 1},
+		{Name: Extract, Duration: time.Second, Count: 1},
+		{Name: Stream, Duration: 2 * time.Second, Count: 1},
+	}
+	got := r.Phases()
+	if len(got) != len(want) {
+		t.Fatalf("Phases = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Phases = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	t.Parallel()
+
+	var r *Recorder
+	r.Add(Catalog, time.Second)
+	r.Start(Catalog)()
+	r.Observe(events.NewBus())()
+	if r.Phases() != nil {
+		t.Fatal("nil recorder must not record anything")
+	}
+}
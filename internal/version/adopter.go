@@ -0,0 +1,186 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// commonGoRoots 为发行版与官方安装包常用的 GOROOT 位置，glob 形式。
+var commonGoRoots = []string{
+	"/usr/local/go",
+	"/usr/lib/go",
+	"/usr/lib/go-*",
+	"/usr/lib/golang",
+	"/snap/go/current",
+	"/opt/go",
+}
+
+// Adopter 发现 govm 之外已有的 Go 安装，并以 external 版本登记到元数据中。
+type Adopter struct {
+	storage    storage.LocalStorage
+	shims      ShimLinker
+	candidates []string
+	getenv     func(string) string
+	now        func() time.Time
+}
+
+// AdopterOption 配置 Adopter。
+type AdopterOption func(*Adopter)
+
+// WithAdoptShims 登记外部版本时同时生成 go<version> shim。
+func WithAdoptShims(s ShimLinker) AdopterOption {
+	return func(a *Adopter) {
+		a.shims = s
+	}
+}
+
+// NewAdopter 创建 Adopter。
+func NewAdopter(store storage.LocalStorage, opts ...AdopterOption) *Adopter {
+	a := &Adopter{storage: store, candidates: commonGoRoots, getenv: os.Getenv, now: time.Now}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Detect 在 PATH 与常见位置中查找尚未登记的 Go 安装，govm 自己管理的版本不会出现在结果中。
+func (a *Adopter) Detect() ([]models.Version, error) {
+	var roots []string
+	for _, dir := range filepath.SplitList(a.getenv("PATH")) {
+		if bin := filepath.Join(dir, "go"); isFile(bin) {
+			if resolved, err := filepath.EvalSymlinks(bin); err == nil {
+				roots = append(roots, filepath.Dir(filepath.Dir(resolved)))
+			}
+		}
+	}
+	for _, pattern := range a.candidates {
+		matches, _ := filepath.Glob(pattern)
+		roots = append(roots, matches...)
+	}
+	return a.unregistered(roots)
+}
+
+// DetectToolchains 查找 go 命令按 GOTOOLCHAIN 下载到 modCache 中、尚未登记的本机平台工具链。
+func (a *Adopter) DetectToolchains(modCache string) ([]models.Version, error) {
+	roots, err := ToolchainRoots(modCache)
+	if err != nil {
+		return nil, err
+	}
+	return a.unregistered(roots)
+}
+
+// unregistered 检查 roots 中的 GOROOT，跳过已登记的路径与版本号，同一版本只保留第一个。
+func (a *Adopter) unregistered(roots []string) ([]models.Version, error) {
+	installed, err := a.storage.LoadMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("adopter: load metadata: %w", err)
+	}
+	known := map[string]bool{}
+	for _, v := range installed {
+		known[v.Number] = true
+		if v.InstallPath != "" {
+			known[canonicalPath(v.InstallPath)] = true
+		}
+	}
+
+	var found []models.Version
+	for _, root := range roots {
+		root = canonicalPath(root)
+		if known[root] {
+			continue
+		}
+		v, err := inspectGoRoot(root)
+		if err != nil || known[v.Number] {
+			continue
+		}
+		known[root] = true
+		known[v.Number] = true
+		found = append(found, v)
+	}
+	return found, nil
+}
+
+// Adopt 将 goRoot 登记为 external 版本；卸载时只删除登记，不会删除其文件。
+func (a *Adopter) Adopt(goRoot string) (models.Version, error) {
+	if err := checkWritable(a.storage); err != nil {
+		return models.Version{}, err
+	}
+	v, err := inspectGoRoot(canonicalPath(goRoot))
+	if err != nil {
+		return models.Version{}, err
+	}
+	installed, err := a.storage.LoadMetadata()
+	if err != nil {
+		return models.Version{}, fmt.Errorf("adopter: load metadata: %w", err)
+	}
+	for _, existing := range installed {
+		if existing.Number == v.Number {
+			return models.Version{}, fmt.Errorf("adopter: go%s is already registered at %s", v.Number, existing.InstallPath)
+		}
+	}
+	v.InstalledAt = a.now().UTC()
+	if err := a.storage.SaveMetadata(v); err != nil {
+		return models.Version{}, fmt.Errorf("adopter: save metadata: %w", err)
+	}
+	if a.shims != nil {
+		if err := a.shims.Link(v.Number, v.InstallPath); err != nil {
+			return v, fmt.Errorf("adopter: create shim: %w", err)
+		}
+	}
+	return v, nil
+}
+
+// inspectGoRoot 读取 GOROOT 的版本号：优先 VERSION 文件，其次执行 go env GOVERSION。
+func inspectGoRoot(root string) (models.Version, error) {
+	goBin := filepath.Join(root, "bin", "go")
+	if !isFile(goBin) {
+		return models.Version{}, fmt.Errorf("adopter: %s is not a Go installation", root)
+	}
+	var full string
+	if data, err := os.ReadFile(filepath.Join(root, "VERSION")); err == nil {
+		full = strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+	}
+	if !strings.HasPrefix(full, "go") {
+		cmd := exec.Command(goBin, "env", "GOVERSION")
+		cmd.Env = append(os.Environ(), "GOROOT="+root, "GOTOOLCHAIN=local")
+		out, err := cmd.Output()
+		if err != nil {
+			return models.Version{}, fmt.Errorf("adopter: query version of %s: %w", root, err)
+		}
+		full = strings.TrimSpace(string(out))
+	}
+	number := strings.TrimPrefix(full, "go")
+	if number == "" || number == full {
+		return models.Version{}, errors.New("adopter: unrecognized version in " + root)
+	}
+	return models.Version{
+		Number:      number,
+		FullName:    full,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Channel:     Channel(number),
+		InstallPath: root,
+		External:    true,
+	}, nil
+}
+
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
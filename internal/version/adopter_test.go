@@ -0,0 +1,114 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func fakeSystemGo(t *testing.T, root, ver string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bin", "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "VERSION"), []byte(ver+"\ntime 2024-01-01\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdopterDetectsAndRegistersExternalGo(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	system := filepath.Join(base, "usr", "local", "go")
+	fakeSystemGo(t, system, "go1.21.5")
+	distro := filepath.Join(base, "usr", "lib", "go-1.19")
+	fakeSystemGo(t, distro, "go1.19.8")
+
+	store := storage.NewFileStorage(models.Config{RootDir: filepath.Join(base, "govm")})
+	adopter := NewAdopter(store)
+	adopter.candidates = []string{filepath.Join(base, "usr", "lib", "go-*")}
+	adopter.getenv = func(string) string { return filepath.Join(system, "bin") }
+
+	found, err := adopter.Detect()
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(found) != 2 || found[0].Number != "1.21.5" || found[1].Number != "1.19.8" {
+		t.Fatalf("unexpected detection: %#v", found)
+	}
+
+	v, err := adopter.Adopt(system)
+	if err != nil {
+		t.Fatalf("Adopt: %v", err)
+	}
+	if !v.External || v.InstallPath != system {
+		t.Fatalf("unexpected adopted version: %#v", v)
+	}
+	if _, err := adopter.Adopt(system); err == nil {
+		t.Fatal("adopting the same version twice must fail")
+	}
+	found, err = adopter.Detect()
+	if err != nil || len(found) != 1 || found[0].Number != "1.19.8" {
+		t.Fatalf("adopted install must not be detected again: %#v, %v", found, err)
+	}
+
+	if _, err := NewUninstaller(store).Uninstall("1.21.5", false); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(system, "bin", "go")); err != nil {
+		t.Fatalf("uninstalling an external version must keep its files: %v", err)
+	}
+}
+
+func TestAdopterDetectsModuleCacheToolchains(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	modCache := filepath.Join(base, "pkg", "mod")
+	toolchain := func(number, platform string) string {
+		return filepath.Join(modCache, "golang.org", "toolchain@v0.0.1-go"+number+"."+platform)
+	}
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	fakeSystemGo(t, toolchain("1.22.0", platform), "go1.22.0")
+	fakeSystemGo(t, toolchain("1.23.1", platform), "go1.23.1")
+	fakeSystemGo(t, toolchain("1.23.1", "plan9-386"), "go1.23.1")
+
+	store := storage.NewFileStorage(models.Config{RootDir: filepath.Join(base, "govm")})
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0", InstallPath: filepath.Join(base, "govm", "versions", "go1.22.0")}); err != nil {
+		t.Fatal(err)
+	}
+	adopter := NewAdopter(store)
+	found, err := adopter.DetectToolchains(modCache)
+	if err != nil {
+		t.Fatalf("DetectToolchains: %v", err)
+	}
+	if len(found) != 1 || found[0].Number != "1.23.1" || found[0].InstallPath != toolchain("1.23.1", platform) {
+		t.Fatalf("only the unregistered toolchain for this platform must be found: %#v", found)
+	}
+	if _, err := adopter.Adopt(found[0].InstallPath); err != nil {
+		t.Fatalf("Adopt: %v", err)
+	}
+	if found, err := adopter.DetectToolchains(modCache); err != nil || len(found) != 0 {
+		t.Fatalf("adopted toolchain must not be detected again: %#v, %v", found, err)
+	}
+	if found, err := adopter.DetectToolchains(filepath.Join(base, "missing")); err != nil || len(found) != 0 {
+		t.Fatalf("a missing module cache is not an error: %#v, %v", found, err)
+	}
+}
+
+func TestAdoptRejectsNonGoRoot(t *testing.T) {
+	t.Parallel()
+
+	store := storage.NewFileStorage(models.Config{RootDir: t.TempDir()})
+	if _, err := NewAdopter(store).Adopt(t.TempDir()); err == nil {
+		t.Fatal("expected error for a directory without bin/go")
+	}
+}
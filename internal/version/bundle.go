@@ -0,0 +1,286 @@
+package version
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/remote"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// BundleSchema 为离线安装包格式的版本号，格式变化时递增。
+const BundleSchema = 1
+
+// 离线安装包中的固定条目名称。
+const (
+	bundleManifestName = "bundle.json"
+	bundleCatalogName  = "catalog.json"
+	bundleArchivesDir  = "archives"
+)
+
+// BundleCatalogFile 为 apply 在缓存目录中保存版本列表快照的文件名，非官方版本源可将 catalogURL 指向它。
+const BundleCatalogFile = "bundle-catalog.json"
+
+// BundleManifest 描述离线安装包的内容，作为包内第一个条目 bundle.json 保存。
+type BundleManifest struct {
+	Schema    int             `json:"schema"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Archives  []BundleArchive `json:"archives"`
+}
+
+// BundleArchive 描述包内的一个 Go 归档。
+type BundleArchive struct {
+	Version  string `json:"version"`
+	FileName string `json:"fileName"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+}
+
+// BundleResult 为 Apply 的结果。
+type BundleResult struct {
+	Manifest BundleManifest
+	// CatalogPath 为版本列表快照在本机保存的位置。
+	CatalogPath string
+	// CatalogSeeded 为 true 时快照已写入版本源缓存，list-remote/install 无需网络即可使用。
+	CatalogSeeded bool
+}
+
+// CatalogSeeder 是版本源的可选能力：用版本列表快照填充磁盘缓存。
+type CatalogSeeder interface {
+	SeedCache(body []byte) error
+}
+
+// Bundler 在联网机器上打包所选版本的归档与版本列表快照，并在离线机器上把它们放入下载缓存与版本源缓存。
+type Bundler struct {
+	catalog      remote.RemoteClient
+	downloader   ArtifactDownloader
+	downloadsDir string
+	cacheDir     string
+	now          func() time.Time
+}
+
+// NewBundler 创建 Bundler；downloadsDir 需与 Downloader 使用的下载目录一致，cacheDir 用于保存版本列表快照。
+func NewBundler(catalog remote.RemoteClient, downloader ArtifactDownloader, downloadsDir, cacheDir string) *Bundler {
+	return &Bundler{catalog: catalog, downloader: downloader, downloadsDir: downloadsDir, cacheDir: cacheDir, now: time.Now}
+}
+
+// Create 下载 numbers 中每个版本在 arches 下的归档，连同完整的版本列表快照写成 tar 包。
+func (b *Bundler) Create(w io.Writer, numbers, arches []string) (BundleManifest, error) {
+	versions, err := b.catalog.FetchVersions(context.Background())
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("bundle: %w", err)
+	}
+	catalog, err := remote.EncodeReleases(versions)
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("bundle: encode catalog: %w", err)
+	}
+
+	manifest := BundleManifest{Schema: BundleSchema, CreatedAt: b.now().UTC()}
+	var paths []string
+	seen := map[string]bool{}
+	for _, number := range numbers {
+		for _, arch := range arches {
+			target, ok := FindArchive(versions, number, arch)
+			if !ok {
+				return BundleManifest{}, fmt.Errorf("bundle: go%s for linux/%s is not in the release list", number, arch)
+			}
+			if seen[target.FileName] {
+				continue
+			}
+			seen[target.FileName] = true
+			archive, err := b.downloader.Download(target)
+			if err != nil {
+				return BundleManifest{}, err
+			}
+			info, err := os.Stat(archive)
+			if err != nil {
+				return BundleManifest{}, fmt.Errorf("bundle: %w", err)
+			}
+			manifest.Archives = append(manifest.Archives, BundleArchive{
+				Version:  target.Number,
+				FileName: target.FileName,
+				OS:       target.OS,
+				Arch:     target.Arch,
+				SHA256:   strings.ToLower(target.Checksum),
+				Size:     info.Size(),
+			})
+			paths = append(paths, archive)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("bundle: encode manifest: %w", err)
+	}
+	tw := tar.NewWriter(w)
+	if err := writeBundleEntry(tw, bundleManifestName, data); err != nil {
+		return BundleManifest{}, err
+	}
+	if err := writeBundleEntry(tw, bundleCatalogName, catalog); err != nil {
+		return BundleManifest{}, err
+	}
+	for i, archive := range manifest.Archives {
+		if err := writeBundleFile(tw, path.Join(bundleArchivesDir, archive.FileName), paths[i]); err != nil {
+			return BundleManifest{}, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return BundleManifest{}, fmt.Errorf("bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// Apply 读取 Create 写出的包：逐个校验归档的 SHA256 后放入下载目录，并保存版本列表快照；
+// 版本源支持 CatalogSeeder 时同时填充其缓存。任一归档校验失败都会中止，已放入的归档保持可用。
+func (b *Bundler) Apply(r io.Reader) (BundleResult, error) {
+	tr := tar.NewReader(r)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != bundleManifestName {
+		return BundleResult{}, errors.New("bundle: not a govm bundle (missing bundle.json)")
+	}
+	var manifest BundleManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return BundleResult{}, fmt.Errorf("bundle: decode manifest: %w", err)
+	}
+	if manifest.Schema != BundleSchema {
+		return BundleResult{}, fmt.Errorf("bundle: unsupported bundle schema %d", manifest.Schema)
+	}
+	expected := make(map[string]BundleArchive, len(manifest.Archives))
+	for _, archive := range manifest.Archives {
+		if archive.FileName == "" || archive.FileName != filepath.Base(archive.FileName) || strings.HasPrefix(archive.FileName, ".") {
+			return BundleResult{}, fmt.Errorf("bundle: invalid archive name %q in bundle.json", archive.FileName)
+		}
+		expected[archive.FileName] = archive
+	}
+	if err := os.MkdirAll(b.downloadsDir, 0o755); err != nil {
+		return BundleResult{}, fmt.Errorf("bundle: %w", err)
+	}
+
+	result := BundleResult{Manifest: manifest}
+	var catalog []byte
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("bundle: read: %w", err)
+		}
+		switch dir, name := path.Split(hdr.Name); {
+		case hdr.Name == bundleCatalogName:
+			if catalog, err = io.ReadAll(tr); err != nil {
+				return result, fmt.Errorf("bundle: read catalog: %w", err)
+			}
+		case dir == bundleArchivesDir+"/":
+			archive, ok := expected[name]
+			if !ok {
+				return result, fmt.Errorf("bundle: %s is not listed in bundle.json", hdr.Name)
+			}
+			if err := b.placeArchive(tr, archive); err != nil {
+				return result, err
+			}
+			delete(expected, name)
+		}
+	}
+	for _, archive := range manifest.Archives {
+		if _, missing := expected[archive.FileName]; missing {
+			return result, fmt.Errorf("bundle: %s is listed in bundle.json but missing from the bundle", archive.FileName)
+		}
+	}
+	if catalog == nil {
+		return result, errors.New("bundle: missing catalog.json")
+	}
+
+	result.CatalogPath = filepath.Join(b.cacheDir, BundleCatalogFile)
+	if err := os.MkdirAll(b.cacheDir, 0o755); err != nil {
+		return result, fmt.Errorf("bundle: %w", err)
+	}
+	if err := os.WriteFile(result.CatalogPath, catalog, 0o644); err != nil {
+		return result, fmt.Errorf("bundle: save catalog: %w", err)
+	}
+	if seeder, ok := b.catalog.(CatalogSeeder); ok {
+		if err := seeder.SeedCache(catalog); err != nil {
+			return result, fmt.Errorf("bundle: %w", err)
+		}
+		result.CatalogSeeded = true
+	}
+	return result, nil
+}
+
+// placeArchive 把归档写入下载目录的临时文件，SHA256 与清单一致后才替换为正式文件名。
+func (b *Bundler) placeArchive(r io.Reader, archive BundleArchive) error {
+	tmp, err := os.CreateTemp(b.downloadsDir, "bundle-*.tmp")
+	if err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hasher), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("bundle: write %s: %w", archive.FileName, err)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, archive.SHA256) {
+		return fmt.Errorf("bundle: checksum mismatch for %s, got %s want %s", archive.FileName, actual, archive.SHA256)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(b.downloadsDir, archive.FileName)); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
+
+// FindArchive 在版本列表中查找指定版本与架构的归档。
+func FindArchive(versions []models.Version, number, arch string) (models.Version, bool) {
+	for _, v := range versions {
+		if v.Number == number && v.Arch == arch {
+			return v, true
+		}
+	}
+	return models.Version{}, false
+}
+
+func writeBundleEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
+
+func writeBundleFile(tw *tar.Writer, name, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package version
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liangyou/govm/internal/remote"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+type offlineTransport struct{}
+
+func (offlineTransport) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network is unreachable")
+}
+
+func TestBundleCreateApplyRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("go1.22.0 archive")
+	sum := sha256.Sum256(payload)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	online := &fakeRemoteClient{versions: []models.Version{{
+		Number:      "1.22.0",
+		FullName:    "go1.22.0",
+		DownloadURL: server.URL + "/go1.22.0.linux-amd64.tar.gz",
+		FileName:    "go1.22.0.linux-amd64.tar.gz",
+		Checksum:    hex.EncodeToString(sum[:]),
+		Size:        int64(len(payload)),
+		OS:          "linux",
+		Arch:        "amd64",
+	}}}
+	onlineRoot := t.TempDir()
+	onlineDownloader := NewDownloader(models.Config{RootDir: onlineRoot}, WithHTTPClient(server.Client()))
+	var bundle bytes.Buffer
+	manifest, err := NewBundler(online, onlineDownloader, onlineDownloader.DownloadsDir(), onlineRoot).Create(&bundle, []string{"1.22.0"}, []string{"amd64"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(manifest.Archives) != 1 || manifest.Archives[0].Size != int64(len(payload)) {
+		t.Fatalf("unexpected manifest: %#v", manifest)
+	}
+	if _, err := NewBundler(online, onlineDownloader, onlineDownloader.DownloadsDir(), onlineRoot).Create(&bytes.Buffer{}, []string{"1.22.0"}, []string{"arm64"}); err == nil || !strings.Contains(err.Error(), "linux/arm64") {
+		t.Fatalf("missing architecture must fail, got %v", err)
+	}
+
+	offlineRoot := t.TempDir()
+	cacheDir := filepath.Join(offlineRoot, "cache")
+	catalog := remote.NewClient(remote.WithHTTPClient(offlineTransport{}), remote.WithCacheDir(cacheDir))
+	offlineDownloader := NewDownloader(models.Config{RootDir: offlineRoot}, WithHTTPClient(offlineTransport{}))
+	result, err := NewBundler(catalog, offlineDownloader, offlineDownloader.DownloadsDir(), cacheDir).Apply(bytes.NewReader(bundle.Bytes()))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !result.CatalogSeeded || result.CatalogPath != filepath.Join(cacheDir, BundleCatalogFile) {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+
+	versions, err := catalog.FetchVersions(context.Background())
+	if err != nil || len(versions) != 1 || versions[0].Checksum != online.versions[0].Checksum {
+		t.Fatalf("seeded catalog must work offline: %#v (%v)", versions, err)
+	}
+	path, err := offlineDownloader.Download(versions[0])
+	if err != nil || path != filepath.Join(offlineDownloader.DownloadsDir(), "go1.22.0.linux-amd64.tar.gz") {
+		t.Fatalf("Download must reuse the bundled archive: %q (%v)", path, err)
+	}
+}
+
+func TestBundleApplyRejectsTamperedArchive(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("original")
+	sum := sha256.Sum256(payload)
+	root := t.TempDir()
+	dl := NewDownloader(models.Config{RootDir: root}, WithHTTPClient(offlineTransport{}))
+	online := &fakeRemoteClient{versions: []models.Version{{
+		Number: "1.21.0", FullName: "go1.21.0", FileName: "go1.21.0.linux-amd64.tar.gz",
+		Checksum: hex.EncodeToString(sum[:]), OS: "linux", Arch: "amd64",
+	}}}
+	if err := os.MkdirAll(dl.DownloadsDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dl.DownloadsDir(), "go1.21.0.linux-amd64.tar.gz"), payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var bundle bytes.Buffer
+	if _, err := NewBundler(online, dl, dl.DownloadsDir(), root).Create(&bundle, []string{"1.21.0"}, []string{"amd64"}); err != nil {
+		t.Fatalf("Create from cached archive: %v", err)
+	}
+
+	tampered := bytes.Replace(bundle.Bytes(), payload, []byte("tampered"), 1)
+	target := t.TempDir()
+	_, err := NewBundler(online, dl, target, t.TempDir()).Apply(bytes.NewReader(tampered))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("tampered archive must be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "go1.21.0.linux-amd64.tar.gz")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("tampered archive must not reach the download cache: %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+package version
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/goversion"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// 去重方式：auto 在支持的文件系统（btrfs、xfs 等）上使用 reflink，否则使用硬链接。
+const (
+	DedupAuto     = "auto"
+	DedupReflink  = "reflink"
+	DedupHardlink = "hardlink"
+)
+
+// errReflinkUnsupported 表示当前平台或文件系统不支持 reflink。
+var errReflinkUnsupported = errors.New("reflinks are not supported on this file system")
+
+// dedupSuffix 为替换文件时在同一目录中暂存的文件后缀，改名覆盖原文件前才会出现。
+const dedupSuffix = ".govm-dedup"
+
+// DedupReport 汇总一次去重：Files 与 Saved 为已替换（dry-run 时为可替换）的重复文件数与字节数。
+type DedupReport struct {
+	Versions   []string
+	Files      int
+	Saved      int64
+	Reflinked  int
+	Hardlinked int
+	// Shared 为已经与其他版本共用同一份数据的文件数，不再计入 Saved。
+	Shared int
+	// Skipped 为没有文件清单、无法参与去重的版本。
+	Skipped []string
+}
+
+// Deduplicator 根据安装清单找出不同版本中内容、大小与权限都相同的文件，用 reflink 或硬链接共享同一份数据。
+type Deduplicator struct {
+	storage   storage.LocalStorage
+	manifests storage.ManifestStorage
+	reflink   func(src, dst string) error
+}
+
+// NewDeduplicator 创建 Deduplicator，store 需同时实现 ManifestStorage。
+func NewDeduplicator(store storage.LocalStorage) *Deduplicator {
+	d := &Deduplicator{storage: store, reflink: reflinkFile}
+	if m, ok := store.(storage.ManifestStorage); ok {
+		d.manifests = m
+	}
+	return d
+}
+
+// Dedup 对 govm 安装的全部版本去重，external 版本不受影响；dryRun 时只根据清单与文件属性估算可回收的空间。
+// 硬链接让各版本共用同一个文件，修改其中一个 GOROOT 中的文件会影响其他版本，reflink 没有这个问题。
+func (d *Deduplicator) Dedup(mode string, dryRun bool) (DedupReport, error) {
+	var report DedupReport
+	if mode == "" {
+		mode = DedupAuto
+	}
+	if mode != DedupAuto && mode != DedupReflink && mode != DedupHardlink {
+		return report, fmt.Errorf("dedup: unknown mode %q (use auto, reflink or hardlink)", mode)
+	}
+	if d.storage == nil || d.manifests == nil {
+		return report, errors.New("dedup: manifest storage is required")
+	}
+	installed, err := d.storage.LoadMetadata()
+	if err != nil {
+		return report, fmt.Errorf("dedup: load metadata: %w", err)
+	}
+	var versions []models.Version
+	for _, v := range installed {
+		if !v.External && v.InstallPath != "" {
+			versions = append(versions, v)
+		}
+	}
+	// 最新的版本通常保留得最久，作为各组重复文件的原件。
+	sort.Slice(versions, func(i, j int) bool { return goversion.Compare(versions[i].Number, versions[j].Number) > 0 })
+
+	originals := map[dedupKey]string{}
+	for _, v := range versions {
+		manifest, err := d.manifests.LoadManifest(v.Number)
+		if errors.Is(err, storage.ErrNoManifest) {
+			report.Skipped = append(report.Skipped, v.Number)
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("dedup: load manifest for %s: %w", v.Number, err)
+		}
+		report.Versions = append(report.Versions, v.Number)
+		for _, entry := range manifest.Files {
+			// 空文件不占数据块，符号链接与旧清单中没有摘要的条目无法比较。
+			if entry.Link != "" || entry.SHA256 == "" || entry.Size == 0 {
+				continue
+			}
+			path := filepath.Join(v.InstallPath, filepath.FromSlash(entry.Path))
+			key := dedupKey{sha256: entry.SHA256, size: entry.Size, mode: entry.Mode}
+			original, ok := originals[key]
+			if !ok {
+				if matchesAttrs(path, entry) {
+					originals[key] = path
+				}
+				continue
+			}
+			done, err := d.share(original, path, entry, mode, dryRun)
+			if err != nil {
+				return report, err
+			}
+			switch done {
+			case "":
+				continue
+			case dedupShared:
+				report.Shared++
+				continue
+			case DedupReflink:
+				report.Reflinked++
+			case DedupHardlink:
+				report.Hardlinked++
+				if mode == DedupAuto {
+					// reflink 已失败过一次，同一文件系统上不必每个文件都再试。
+					mode = DedupHardlink
+				}
+			}
+			report.Files++
+			report.Saved += entry.Size
+		}
+	}
+	return report, nil
+}
+
+// dedupKey 为判断文件可以共享的清单属性。
+type dedupKey struct {
+	sha256 string
+	size   int64
+	mode   fs.FileMode
+}
+
+// dedupShared 表示两个路径已经是同一个文件。
+const dedupShared = "shared"
+
+// share 用 original 的数据替换 path，返回实际采用的方式；文件已被改动、属性不一致或无法链接时返回空字符串并跳过。
+// dryRun 时只检查属性并返回 mode。
+func (d *Deduplicator) share(original, path string, entry models.FileEntry, mode string, dryRun bool) (string, error) {
+	origInfo, err := os.Lstat(original)
+	if err != nil {
+		return "", nil
+	}
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() != entry.Size || info.Mode().Perm() != origInfo.Mode().Perm() {
+		return "", nil
+	}
+	if os.SameFile(origInfo, info) {
+		return dedupShared, nil
+	}
+	if dryRun {
+		return mode, nil
+	}
+	// 清单可能已过时，替换前逐字节确认内容相同。
+	if same, err := sameContent(original, path); err != nil || !same {
+		return "", nil
+	}
+
+	dir := filepath.Dir(path)
+	restore, err := makeWritable(dir)
+	if err != nil {
+		return "", fmt.Errorf("dedup: %w", err)
+	}
+	defer restore()
+	tmp := path + dedupSuffix
+	os.Remove(tmp)
+
+	used := ""
+	if mode != DedupHardlink {
+		err := d.reflink(original, tmp)
+		switch {
+		case err == nil:
+			used = DedupReflink
+		case mode == DedupReflink:
+			os.Remove(tmp)
+			return "", fmt.Errorf("dedup: reflink %s: %w", path, err)
+		default:
+			os.Remove(tmp)
+		}
+	}
+	if used == DedupReflink {
+		// reflink 得到的是新文件，恢复原文件的权限、属主与修改时间。
+		if err := restoreAttrs(tmp, info); err != nil {
+			os.Remove(tmp)
+			return "", fmt.Errorf("dedup: %w", err)
+		}
+	} else {
+		if !sameOwner(origInfo, info) {
+			return "", nil
+		}
+		if err := os.Link(original, tmp); err != nil {
+			// 不同文件系统之间无法硬链接，跳过该文件。
+			return "", nil
+		}
+		used = DedupHardlink
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("dedup: replace %s: %w", path, err)
+	}
+	return used, nil
+}
+
+// matchesAttrs 报告 path 是否仍是清单记录的普通文件，只比较大小与权限，不重新计算摘要。
+func matchesAttrs(path string, entry models.FileEntry) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() == entry.Size && (entry.Mode == 0 || info.Mode().Perm() == entry.Mode)
+}
+
+// sameContent 逐块比较两个文件的内容。
+func sameContent(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+	bufA, bufB := make([]byte, 64<<10), make([]byte, 64<<10)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if na != nb || !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		endA := errors.Is(errA, io.EOF) || errors.Is(errA, io.ErrUnexpectedEOF)
+		endB := errors.Is(errB, io.EOF) || errors.Is(errB, io.ErrUnexpectedEOF)
+		switch {
+		case errA != nil && !endA:
+			return false, errA
+		case errB != nil && !endB:
+			return false, errB
+		case endA || endB:
+			return endA && endB, nil
+		}
+	}
+}
+
+// makeWritable 为只读安装中的目录临时补回属主写权限，返回恢复原权限的函数。
+func makeWritable(dir string) (func(), error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	mode := info.Mode().Perm()
+	if mode&0o200 != 0 {
+		return func() {}, nil
+	}
+	if err := os.Chmod(dir, mode|0o200); err != nil {
+		return nil, err
+	}
+	return func() { os.Chmod(dir, mode) }, nil
+}
+
+// restoreAttrs 把 info 记录的权限、属主与修改时间应用到 path。
+func restoreAttrs(path string, info fs.FileInfo) error {
+	if err := copyOwner(path, info); err != nil {
+		return err
+	}
+	if err := os.Chmod(path, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(path, info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,81 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestDeduplicatorLinksIdenticalFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	for ver, print := range map[string]string{"1.22.0": "package fmt // 1.22.0", "1.22.1": "package fmt // 1.22.1"} {
+		archive := createGoArchive(t, map[string]string{
+			"bin/go":           "binary",
+			"src/fmt/print.go": print,
+			"VERSION":          "go1.22",
+		})
+		if err := NewInstaller(store, &stubDownloader{path: archive}).Install(models.Version{Number: ver, FullName: "go" + ver}); err != nil {
+			t.Fatalf("install %s: %v", ver, err)
+		}
+	}
+	// 被改动过的文件与清单不一致，不参与去重。
+	tampered := filepath.Join(store.GetInstallPath("1.22.0"), "VERSION")
+	if err := os.WriteFile(tampered, []byte("go1.22-custom"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dedup := NewDeduplicator(store)
+	dedup.reflink = func(string, string) error { return errReflinkUnsupported }
+	dry, err := dedup.Dedup(DedupAuto, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Files != 1 || dry.Saved != int64(len("binary")) || len(dry.Versions) != 2 {
+		t.Fatalf("unexpected dry run report: %+v", dry)
+	}
+	newer, older := store.GetInstallPath("1.22.1"), store.GetInstallPath("1.22.0")
+	if sameInode(t, filepath.Join(newer, "bin", "go"), filepath.Join(older, "bin", "go")) {
+		t.Fatal("dry run must not link files")
+	}
+
+	report, err := dedup.Dedup(DedupAuto, false)
+	if err != nil {
+		t.Fatalf("Dedup: %v", err)
+	}
+	if report.Files != 1 || report.Hardlinked != 1 || report.Saved != int64(len("binary")) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if !sameInode(t, filepath.Join(newer, "bin", "go"), filepath.Join(older, "bin", "go")) {
+		t.Fatal("bin/go is not shared")
+	}
+	if sameInode(t, filepath.Join(newer, "VERSION"), tampered) || sameInode(t, filepath.Join(newer, "src", "fmt", "print.go"), filepath.Join(older, "src", "fmt", "print.go")) {
+		t.Fatal("different files must not be linked")
+	}
+	if verify, err := NewVerifier(store).Verify("1.22.1"); err != nil || !verify.OK() {
+		t.Fatalf("deduplicated install must still verify: %+v, %v", verify, err)
+	}
+
+	again, err := dedup.Dedup(DedupHardlink, false)
+	if err != nil || again.Files != 0 || again.Shared != 1 {
+		t.Fatalf("second pass = %+v, %v", again, err)
+	}
+}
+
+func sameInode(t *testing.T, a, b string) bool {
+	t.Helper()
+	ia, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(ia, ib)
+}
@@ -5,15 +5,25 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
 
+// ErrStreamInterrupted 表示流式下载在传输过程中中断，调用方可改用两阶段下载重试。
+var ErrStreamInterrupted = errors.New("downloader: stream interrupted")
+
 // ProgressFunc 在下载过程中回调当前已完成的字节数以及总字节数。
 type ProgressFunc func(downloaded, total int64)
 
@@ -22,8 +32,24 @@ type Downloader struct {
 	httpClient   HTTPClient
 	downloadsDir string
 	progressFunc ProgressFunc
+	events       *events.Bus
+
+	canonical        ChecksumSource
+	strictChecksums  bool
+	onMirrorChecksum MirrorChecksumFunc
+
+	mu         sync.Mutex
+	provenance map[string]models.Provenance // 按归档文件名记录最近一次下载的来源
+}
+
+// ChecksumSource 提供归档的权威 SHA256，通常来自 go.dev 官方版本列表。
+type ChecksumSource interface {
+	Checksum(fileName string) (string, error)
 }
 
+// MirrorChecksumFunc 在无法取得权威校验值、只能使用镜像自身提供的校验值时被调用。
+type MirrorChecksumFunc func(version string, reason error)
+
 // HTTPClient 定义 Downloader 所需的 HTTP 客户端能力。
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -57,13 +83,31 @@ func WithProgressFunc(fn ProgressFunc) DownloaderOption {
 	}
 }
 
+// WithDownloadEvents 指定事件总线，用于发布 download_start/download_progress 事件。
+func WithDownloadEvents(bus *events.Bus) DownloaderOption {
+	return func(d *Downloader) {
+		d.events = bus
+	}
+}
+
+// WithCanonicalChecksums 使用 src 交叉校验镜像提供的校验值：两者不一致时拒绝下载。
+// strict 为 true 时取不到权威校验值即失败，否则调用 onMirror 后继续使用镜像的校验值。
+func WithCanonicalChecksums(src ChecksumSource, strict bool, onMirror MirrorChecksumFunc) DownloaderOption {
+	return func(d *Downloader) {
+		d.canonical = src
+		d.strictChecksums = strict
+		d.onMirrorChecksum = onMirror
+	}
+}
+
 // NewDownloader 创建 Downloader。
 func NewDownloader(cfg models.Config, opts ...DownloaderOption) *Downloader {
 	dir := cfg.RootDir
 	if dir == "" {
-		if home, err := os.UserHomeDir(); err == nil {
-			dir = filepath.Join(home, ".govm")
-		}
+		dir = storage.DefaultRootDir()
+	}
+	if cfg.CacheDir != "" {
+		dir = cfg.CacheDir
 	}
 	downloads := filepath.Join(dir, "downloads")
 	d := &Downloader{
@@ -78,25 +122,43 @@ func NewDownloader(cfg models.Config, opts ...DownloaderOption) *Downloader {
 
 // Download 获取指定版本的压缩包并校验 SHA256，返回本地文件路径。
 func (d *Downloader) Download(version models.Version) (string, error) {
+	return d.download(version, "")
+}
+
+// DownloadCached 与 Download 相同，cached 为 CachedArchive 刚校验过的归档；核对后的校验值未变时直接使用，不再重新计算摘要。
+func (d *Downloader) DownloadCached(version models.Version, cached string) (string, error) {
+	return d.download(version, cached)
+}
+
+func (d *Downloader) download(version models.Version, cached string) (string, error) {
 	if err := os.MkdirAll(d.downloadsDir, 0o755); err != nil {
 		return "", fmt.Errorf("downloader: create dir: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodGet, version.DownloadURL, nil)
+	checked := version.Checksum
+	source, err := d.resolveChecksum(&version)
 	if err != nil {
-		return "", fmt.Errorf("downloader: build request: %w", err)
+		return "", err
+	}
+	if version.Checksum == "" {
+		return "", fmt.Errorf("downloader: empty checksum for %s", version.FileName)
+	}
+	if cached != "" && version.Checksum == checked {
+		d.record(version, source, true)
+		return cached, nil
+	}
+	// 只有复用已下载的归档时才需要重新读取文件计算摘要。
+	if path, ok := d.CachedArchive(version); ok {
+		d.record(version, source, true)
+		return path, nil
 	}
 
-	resp, err := d.httpClient.Do(req)
+	resp, err := d.get(version)
 	if err != nil {
-		return "", fmt.Errorf("downloader: request failed: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("downloader: unexpected status %d", resp.StatusCode)
-	}
-
 	tempFile, err := os.CreateTemp(d.downloadsDir, "download-*.tmp")
 	if err != nil {
 		return "", fmt.Errorf("downloader: temp file: %w", err)
@@ -108,20 +170,26 @@ func (d *Downloader) Download(version models.Version) (string, error) {
 	}()
 
 	total := resp.ContentLength
-	reader := d.wrapProgress(resp.Body, total)
+	d.events.Publish(events.New(events.DownloadStart,
+		"version", version.Number,
+		"url", version.DownloadURL,
+		"total", strconv.FormatInt(total, 10),
+	))
+	reader := d.wrapProgress(version.Number, resp.Body, total)
 
-	if _, err := io.Copy(tempFile, reader); err != nil {
+	// 写入的同时计算摘要，250MB 的归档不必落盘后再完整读一遍。
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tempFile, hasher), reader); err != nil {
 		return "", fmt.Errorf("downloader: write file: %w", err)
 	}
+	if err := matchChecksum(hasher, version.Checksum); err != nil {
+		return "", err
+	}
 
 	if err := tempFile.Sync(); err != nil {
 		return "", fmt.Errorf("downloader: sync file: %w", err)
 	}
 
-	if err := d.verifyChecksum(tempPath, version.Checksum); err != nil {
-		return "", err
-	}
-
 	finalPath := filepath.Join(d.downloadsDir, version.FileName)
 	if err := os.Remove(finalPath); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return "", fmt.Errorf("downloader: remove existing: %w", err)
@@ -130,18 +198,238 @@ func (d *Downloader) Download(version models.Version) (string, error) {
 		return "", fmt.Errorf("downloader: finalize file: %w", err)
 	}
 
+	d.record(version, source, false)
 	return finalPath, nil
 }
 
-func (d *Downloader) wrapProgress(reader io.Reader, total int64) io.Reader {
-	if d.progressFunc == nil {
+// CachedArchive 返回 downloads 目录中与 version 校验值一致的归档（例如 `govm bundle apply` 放入的文件），
+// 存在时 Download 不再访问网络；校验值未知或不一致时 ok 为 false。
+func (d *Downloader) CachedArchive(version models.Version) (string, bool) {
+	if version.Checksum == "" || version.FileName == "" {
+		return "", false
+	}
+	path := filepath.Join(d.downloadsDir, filepath.Base(version.FileName))
+	if d.verifyChecksum(path, version.Checksum) != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// DownloadsDir 返回保存归档的目录。
+func (d *Downloader) DownloadsDir() string {
+	return d.downloadsDir
+}
+
+// Stream 下载归档并把字节流交给 consume，同时计算 SHA256；consume 返回后读完剩余字节再校验。
+// 校验失败时返回错误，调用方需丢弃 consume 已产生的结果。
+func (d *Downloader) Stream(version models.Version, consume func(io.Reader) error) error {
+	source, err := d.resolveChecksum(&version)
+	if err != nil {
+		return err
+	}
+	if version.Checksum == "" {
+		return fmt.Errorf("downloader: empty checksum for %s", version.FileName)
+	}
+
+	resp, err := d.get(version)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	total := resp.ContentLength
+	d.events.Publish(events.New(events.DownloadStart,
+		"version", version.Number,
+		"url", version.DownloadURL,
+		"total", strconv.FormatInt(total, 10),
+	))
+	src := &transportReader{r: d.wrapProgress(version.Number, resp.Body, total)}
+	hasher := sha256.New()
+
+	consumeErr := consume(io.TeeReader(src, hasher))
+	if src.err != nil {
+		return fmt.Errorf("%w: %v", ErrStreamInterrupted, src.err)
+	}
+	if consumeErr != nil {
+		return consumeErr
+	}
+	// gzip 尾部与 tar 填充块不会被解压器读取，需要补齐才能得到完整摘要。
+	if _, err := io.Copy(hasher, src); err != nil {
+		return fmt.Errorf("%w: %v", ErrStreamInterrupted, err)
+	}
+
+	if err := matchChecksum(hasher, version.Checksum); err != nil {
+		return err
+	}
+	d.record(version, source, false)
+	return nil
+}
+
+// Provenance 返回最近一次下载（或复用）该归档时记录的来源，安装器据此写入元数据。
+func (d *Downloader) Provenance(fileName string) (models.Provenance, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p, ok := d.provenance[fileName]
+	return p, ok
+}
+
+// record 在归档校验通过后记录其来源；cached 表示复用了 downloads 目录中已有的归档。
+func (d *Downloader) record(version models.Version, source string, cached bool) {
+	p := models.Provenance{ChecksumSource: source, FetchedAt: time.Now().UTC(), Cached: cached}
+	if !cached {
+		p.URL = version.DownloadURL
+		if u, err := url.Parse(version.DownloadURL); err == nil && u.Host != "" {
+			p.Mirror = u.Scheme + "://" + u.Host
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.provenance == nil {
+		d.provenance = map[string]models.Provenance{}
+	}
+	d.provenance[version.FileName] = p
+}
+
+// resolveChecksum 在版本缺少内联校验值时下载 ChecksumURL 指向的 .sha256 文件，并按配置与权威校验值交叉核对，
+// 返回最终校验值的来源（models.Checksum*）。
+func (d *Downloader) resolveChecksum(version *models.Version) (string, error) {
+	source := models.ChecksumCatalog
+	if version.Provenance != nil && version.Provenance.ChecksumSource != "" {
+		source = version.Provenance.ChecksumSource
+	}
+	fromFile := version.Checksum == "" && version.ChecksumURL != ""
+	// 镜像的 .sha256 不可用时，只要能取得权威校验值仍可继续。
+	fetchErr := d.fetchChecksumFile(version)
+	if fromFile && fetchErr == nil {
+		source = models.ChecksumFile
+	}
+	official, err := d.crossCheckChecksum(version)
+	if err != nil {
+		return "", err
+	}
+	if official {
+		source = models.ChecksumOfficial
+	}
+	if version.Checksum == "" && fetchErr != nil {
+		return "", fetchErr
+	}
+	return source, nil
+}
+
+// crossCheckChecksum 用权威来源核对校验值；镜像未提供校验值时直接采用权威值。取得了权威值时返回 true。
+func (d *Downloader) crossCheckChecksum(version *models.Version) (bool, error) {
+	if d.canonical == nil {
+		return false, nil
+	}
+	want, err := d.canonical.Checksum(version.FileName)
+	if err != nil {
+		if d.strictChecksums {
+			return false, fmt.Errorf("downloader: no official checksum for %s: %w", version.FileName, err)
+		}
+		if version.Checksum != "" && d.onMirrorChecksum != nil {
+			d.onMirrorChecksum(version.Number, err)
+		}
+		return false, nil
+	}
+	if version.Checksum != "" && !strings.EqualFold(version.Checksum, want) {
+		return false, fmt.Errorf("downloader: mirror checksum %s for %s does not match the official %s", version.Checksum, version.FileName, want)
+	}
+	version.Checksum = want
+	return true, nil
+}
+
+func (d *Downloader) fetchChecksumFile(version *models.Version) error {
+	if version.Checksum != "" || version.ChecksumURL == "" {
+		return nil
+	}
+	resp, err := d.get(models.Version{DownloadURL: version.ChecksumURL})
+	if err != nil {
+		return fmt.Errorf("downloader: fetch checksum: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return fmt.Errorf("downloader: read checksum: %w", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return fmt.Errorf("downloader: invalid checksum file %s", version.ChecksumURL)
+	}
+	if _, err := hex.DecodeString(fields[0]); err != nil {
+		return fmt.Errorf("downloader: invalid checksum file %s", version.ChecksumURL)
+	}
+	version.Checksum = strings.ToLower(fields[0])
+	return nil
+}
+
+func (d *Downloader) get(version models.Version) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, version.DownloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloader: build request: %w", err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloader: request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("downloader: unexpected status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (d *Downloader) wrapProgress(ver string, reader io.Reader, total int64) io.Reader {
+	report := d.progressFunc
+	if d.events != nil {
+		emit := newProgressEmitter(d.events, ver)
+		if report == nil {
+			report = emit
+		} else {
+			user := report
+			report = func(downloaded, total int64) {
+				user(downloaded, total)
+				emit(downloaded, total)
+			}
+		}
+	}
+	if report == nil {
 		return reader
 	}
 
-	pr := &progressReader{r: reader, total: total, report: d.progressFunc}
+	pr := &progressReader{r: reader, total: total, report: report}
 	return pr
 }
 
+// progressEventStep 为总大小未知时发布进度事件的字节间隔。
+const progressEventStep = 1 << 20
+
+// newProgressEmitter 返回一个节流的进度回调：总大小已知时按整数百分比变化发布，否则每 1MiB 发布一次。
+func newProgressEmitter(bus *events.Bus, ver string) ProgressFunc {
+	lastPercent := int64(-1)
+	var lastBytes int64
+	return func(downloaded, total int64) {
+		if total > 0 {
+			percent := downloaded * 100 / total
+			if percent == lastPercent {
+				return
+			}
+			lastPercent = percent
+		} else {
+			if downloaded-lastBytes < progressEventStep {
+				return
+			}
+			lastBytes = downloaded
+		}
+		bus.Publish(events.New(events.DownloadProgress,
+			"version", ver,
+			"downloaded", strconv.FormatInt(downloaded, 10),
+			"total", strconv.FormatInt(total, 10),
+		))
+	}
+}
+
 func (d *Downloader) verifyChecksum(path, expected string) error {
 	if expected == "" {
 		return fmt.Errorf("downloader: empty checksum for %s", filepath.Base(path))
@@ -157,7 +445,11 @@ func (d *Downloader) verifyChecksum(path, expected string) error {
 	if _, err := io.Copy(hasher, file); err != nil {
 		return fmt.Errorf("downloader: hash file: %w", err)
 	}
+	return matchChecksum(hasher, expected)
+}
 
+// matchChecksum 比较 hasher 已累计的摘要与期望的十六进制校验值。
+func matchChecksum(hasher hash.Hash, expected string) error {
 	actual := hex.EncodeToString(hasher.Sum(nil))
 	if !strings.EqualFold(actual, expected) {
 		return fmt.Errorf("downloader: checksum mismatch, got %s want %s", actual, expected)
@@ -180,3 +472,17 @@ func (p *progressReader) Read(b []byte) (int, error) {
 	}
 	return n, err
 }
+
+// transportReader 记录底层连接的读取错误，以便与解压错误区分。
+type transportReader struct {
+	r   io.Reader
+	err error
+}
+
+func (t *transportReader) Read(b []byte) (int, error) {
+	n, err := t.r.Read(b)
+	if err != nil && err != io.EOF && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
@@ -3,10 +3,13 @@ package version
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"testing"
 
@@ -59,6 +62,25 @@ func TestDownloaderDownloadSuccess(t *testing.T) {
 	if got := atomic.LoadInt64(&lastProgress); got != int64(len(payload)) {
 		t.Fatalf("unexpected progress: %d", got)
 	}
+
+	p, ok := dl.Provenance(version.FileName)
+	if !ok || p.Mirror != server.URL || p.URL != server.URL || p.ChecksumSource != models.ChecksumCatalog || p.Cached || p.FetchedAt.IsZero() {
+		t.Fatalf("unexpected provenance: %#v", p)
+	}
+	if _, err := dl.Download(version); err != nil {
+		t.Fatalf("cached Download failed: %v", err)
+	}
+	if p, _ := dl.Provenance(version.FileName); !p.Cached || p.URL != "" {
+		t.Fatalf("reused archive must be recorded as cached: %#v", p)
+	}
+
+	cached, ok := dl.CachedArchive(version)
+	if !ok || cached != path {
+		t.Fatalf("CachedArchive = %q, %v", cached, ok)
+	}
+	if got, err := dl.DownloadCached(version, cached); err != nil || got != cached {
+		t.Fatalf("DownloadCached = %q, %v; want the verified archive", got, err)
+	}
 }
 
 func TestDownloaderChecksumMismatch(t *testing.T) {
@@ -85,9 +107,24 @@ func TestDownloaderChecksumMismatch(t *testing.T) {
 		t.Fatal("expected checksum mismatch error")
 	}
 
-	finalPath := filepath.Join(cfg.RootDir, "downloads", version.FileName)
-	if _, err := os.Stat(finalPath); !os.IsNotExist(err) {
-		t.Fatalf("expected no file at %s", finalPath)
+	if entries, err := os.ReadDir(filepath.Join(cfg.RootDir, "downloads")); err != nil || len(entries) != 0 {
+		t.Fatalf("a mismatched download must leave nothing behind: %v, %v", entries, err)
+	}
+}
+
+func TestDownloaderRequiresChecksumBeforeFetching(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	dl := NewDownloader(models.Config{RootDir: t.TempDir()}, WithHTTPClient(server.Client()))
+	_, err := dl.Download(models.Version{DownloadURL: server.URL, FileName: "go1.20.linux-amd64.tar.gz"})
+	if err == nil || !strings.Contains(err.Error(), "empty checksum") || hits.Load() != 0 {
+		t.Fatalf("expected an empty checksum error without a request, got %v after %d requests", err, hits.Load())
 	}
 }
 
@@ -108,3 +145,156 @@ func TestDownloaderHTTPError(t *testing.T) {
 		t.Fatal("expected http error")
 	}
 }
+
+func TestDownloaderStreamHashesTrailingBytes(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("archive-bytes-with-trailer")
+	sum := sha256.Sum256(payload)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	dl := NewDownloader(models.Config{RootDir: t.TempDir()}, WithHTTPClient(server.Client()))
+	version := models.Version{DownloadURL: server.URL, FileName: "go.tar.gz", Checksum: hex.EncodeToString(sum[:])}
+
+	// consume 只读取前几个字节，剩余部分仍需计入摘要。
+	err := dl.Stream(version, func(r io.Reader) error {
+		_, err := io.ReadFull(r, make([]byte, 7))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	version.Checksum = strings.Repeat("0", 64)
+	err = dl.Stream(version, func(r io.Reader) error {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestDownloaderStreamInterrupted(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		_, _ = w.Write([]byte("partial"))
+	}))
+	defer server.Close()
+
+	dl := NewDownloader(models.Config{RootDir: t.TempDir()}, WithHTTPClient(server.Client()))
+	version := models.Version{DownloadURL: server.URL, FileName: "go.tar.gz", Checksum: "abc"}
+
+	err := dl.Stream(version, func(r io.Reader) error {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	})
+	if !errors.Is(err, ErrStreamInterrupted) {
+		t.Fatalf("expected ErrStreamInterrupted, got %v", err)
+	}
+}
+
+func TestDownloaderFetchesChecksumFile(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("archive")
+	sum := sha256.Sum256(payload)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			_, _ = w.Write([]byte(hex.EncodeToString(sum[:]) + "  go.tar.gz\n"))
+			return
+		}
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	dl := NewDownloader(models.Config{RootDir: t.TempDir()}, WithHTTPClient(server.Client()))
+	version := models.Version{
+		DownloadURL: server.URL + "/go.tar.gz",
+		ChecksumURL: server.URL + "/go.tar.gz.sha256",
+		FileName:    "go.tar.gz",
+	}
+	if _, err := dl.Download(version); err != nil {
+		t.Fatalf("Download with checksum file failed: %v", err)
+	}
+	if p, _ := dl.Provenance("go.tar.gz"); p.ChecksumSource != models.ChecksumFile || p.URL != version.DownloadURL {
+		t.Fatalf("unexpected provenance: %#v", p)
+	}
+}
+
+// fixedChecksums 为按文件名返回固定校验值的权威来源。
+type fixedChecksums struct {
+	sums map[string]string
+	err  error
+}
+
+func (f fixedChecksums) Checksum(name string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.sums[name], nil
+}
+
+func TestDownloaderCanonicalChecksums(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte("archive")
+	sum := sha256.Sum256(payload)
+	good := hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	official := fixedChecksums{sums: map[string]string{"go.tar.gz": good}}
+	unavailable := fixedChecksums{err: errors.New("offline")}
+	tests := []struct {
+		name     string
+		mirror   string
+		source   ChecksumSource
+		strict   bool
+		wantErr  string
+		wantWarn bool
+	}{
+		{name: "fills missing mirror checksum", source: official},
+		{name: "matching mirror checksum", mirror: strings.ToUpper(good), source: official},
+		{name: "mirror disagrees", mirror: strings.Repeat("0", 64), source: official, wantErr: "does not match the official"},
+		{name: "official unavailable warns", mirror: good, source: unavailable, wantWarn: true},
+		{name: "official unavailable strict", mirror: good, source: unavailable, strict: true, wantErr: "no official checksum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warned string
+			dl := NewDownloader(models.Config{RootDir: t.TempDir()},
+				WithHTTPClient(server.Client()),
+				WithCanonicalChecksums(tt.source, tt.strict, func(v string, err error) { warned = v }),
+			)
+			_, err := dl.Download(models.Version{Number: "1.22.0", DownloadURL: server.URL + "/go.tar.gz", FileName: "go.tar.gz", Checksum: tt.mirror})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Download error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+			if (warned != "") != tt.wantWarn || (tt.wantWarn && warned != "1.22.0") {
+				t.Fatalf("mirror-only warning for %q, want warning %v", warned, tt.wantWarn)
+			}
+			want := models.ChecksumOfficial
+			if tt.wantWarn {
+				want = models.ChecksumCatalog
+			}
+			if p, _ := dl.Provenance("go.tar.gz"); p.ChecksumSource != want {
+				t.Fatalf("checksum source = %q, want %q", p.ChecksumSource, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// ModuleRequirement 为 go.mod 或 go.work 中与工具链相关的指令。
+type ModuleRequirement struct {
+	Path      string // 读取的 go.mod/go.work 路径
+	Go        string // go 指令，例如 1.22 或 1.22.3
+	Toolchain string // toolchain 指令去掉 go 前缀，未设置或为 default 时为空
+}
+
+// Minimum 返回满足该模块所需的最低版本及其来源指令（"go" 或 "toolchain"），两者都未设置时版本为空。
+func (r ModuleRequirement) Minimum() (string, string) {
+	if r.Toolchain != "" && goversion.Compare(r.Toolchain, r.Go) > 0 {
+		return r.Toolchain, "toolchain"
+	}
+	if r.Go != "" {
+		return r.Go, "go"
+	}
+	return "", ""
+}
+
+// Satisfied 判断 number 是否不低于模块要求的最低版本。
+func (r ModuleRequirement) Satisfied(number string) bool {
+	minimum, _ := r.Minimum()
+	return minimum == "" || goversion.Compare(number, minimum) >= 0
+}
+
+// FindModule 从 dir 向上查找 go.work 与 go.mod 并读取其中的指令：与 go 命令一致，找到 go.work 时以工作区为准，
+// gowork 为 GOWORK 环境变量的取值，off 表示忽略工作区，其他非空值直接作为 go.work 路径。不在模块内时 ok 为 false。
+func FindModule(dir, gowork string) (ModuleRequirement, bool, error) {
+	var work, mod string
+	switch gowork {
+	case "", "auto":
+	case "off":
+		work = "-"
+	default:
+		work = gowork
+	}
+	for current := dir; ; {
+		if work == "" && isFile(filepath.Join(current, "go.work")) {
+			work = filepath.Join(current, "go.work")
+		}
+		if mod == "" && isFile(filepath.Join(current, "go.mod")) {
+			mod = filepath.Join(current, "go.mod")
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	path := mod
+	if work != "" && work != "-" {
+		path = work
+	}
+	if path == "" {
+		return ModuleRequirement{}, false, nil
+	}
+	req, err := readModuleRequirement(path)
+	if err != nil {
+		return ModuleRequirement{}, false, err
+	}
+	return req, true, nil
+}
+
+// readModuleRequirement 只解析顶层的 go 与 toolchain 指令，require 等块中的内容整体跳过。
+func readModuleRequirement(path string) (ModuleRequirement, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ModuleRequirement{}, fmt.Errorf("version: read %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+
+	req := ModuleRequirement{Path: path}
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case inBlock:
+			inBlock = len(fields) == 0 || fields[0] != ")"
+			continue
+		case len(fields) > 0 && fields[len(fields)-1] == "(":
+			inBlock = true
+			continue
+		case len(fields) != 2:
+			continue
+		}
+		switch fields[0] {
+		case "go":
+			req.Go = fields[1]
+		case "toolchain":
+			if fields[1] != "default" {
+				req.Toolchain = strings.TrimPrefix(fields[1], "go")
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ModuleRequirement{}, fmt.Errorf("version: read %s: %w", filepath.Base(path), err)
+	}
+	return req, nil
+}
@@ -0,0 +1,50 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindModulePrefersWorkspace(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	svc := filepath.Join(root, "svc", "cmd")
+	if err := os.MkdirAll(svc, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(root, "svc", "go.mod"), "module example.com/svc\n\ngo 1.21 // minimum\n\ntoolchain go1.22.4\n\nrequire (\n\tgo 1.0\n)\n")
+
+	req, ok, err := FindModule(svc, "")
+	if err != nil || !ok || req.Go != "1.21" || req.Toolchain != "1.22.4" {
+		t.Fatalf("FindModule = %#v, %v, %v", req, ok, err)
+	}
+	if minimum, directive := req.Minimum(); minimum != "1.22.4" || directive != "toolchain" {
+		t.Fatalf("Minimum = %s (%s)", minimum, directive)
+	}
+	if req.Satisfied("1.22.3") || !req.Satisfied("1.22.4") || !req.Satisfied("1.23.0") {
+		t.Fatal("Satisfied must compare against the toolchain directive")
+	}
+
+	write(filepath.Join(root, "go.work"), "go 1.23.1\n\ntoolchain default\n\nuse ./svc\n")
+	req, ok, err = FindModule(svc, "")
+	if err != nil || !ok || req.Path != filepath.Join(root, "go.work") || req.Toolchain != "" {
+		t.Fatalf("go.work must take precedence: %#v, %v, %v", req, ok, err)
+	}
+	if minimum, directive := req.Minimum(); minimum != "1.23.1" || directive != "go" {
+		t.Fatalf("Minimum = %s (%s)", minimum, directive)
+	}
+	if req, _, _ = FindModule(svc, "off"); req.Path != filepath.Join(root, "svc", "go.mod") {
+		t.Fatalf("GOWORK=off must ignore go.work, got %s", req.Path)
+	}
+	if _, ok, err := FindModule(t.TempDir(), ""); ok || err != nil {
+		t.Fatalf("outside a module ok must be false, got %v, %v", ok, err)
+	}
+}
@@ -3,6 +3,8 @@ package version
 import (
 	"archive/tar"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -10,8 +12,10 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/liangyou/govm/internal/events"
 	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
@@ -21,33 +25,108 @@ type ArtifactDownloader interface {
 	Download(models.Version) (string, error)
 }
 
+// ArchiveCache 是可选能力：返回本地已有且校验通过的归档，命中时安装不再走流式下载，
+// 并通过 DownloadCached 直接使用该归档，不再计算一遍摘要。
+type ArchiveCache interface {
+	CachedArchive(version models.Version) (string, bool)
+	DownloadCached(version models.Version, cached string) (string, error)
+}
+
+// StreamDownloader 是可选能力：把归档字节流交给 consume 处理，同时计算并校验 SHA256。
+type StreamDownloader interface {
+	Stream(version models.Version, consume func(io.Reader) error) error
+}
+
+// ProvenanceSource 是可选能力：返回最近一次获取该归档时的镜像、地址与校验值来源，安装器将其写入元数据。
+type ProvenanceSource interface {
+	Provenance(fileName string) (models.Provenance, bool)
+}
+
 // Installer 负责将下载好的 Go 版本安装到本地。
 type Installer struct {
 	storage    storage.LocalStorage
 	downloader ArtifactDownloader
 	now        func() time.Time
+	events     *events.Bus
+	streaming  bool
+	shims      ShimLinker
+	perms      Permissions
+	// recovered 保证每个进程只在首次安装前清理一次中断的安装。
+	recovered        sync.Once
+	recoveryReporter RecoveryFunc
+}
+
+// ShimLinker 维护按版本命名的命令，安装后创建、卸载后删除。
+type ShimLinker interface {
+	Link(version, goRoot string) error
+	Unlink(version string) error
+}
+
+// InstallerOption 配置 Installer。
+type InstallerOption func(*Installer)
+
+// WithInstallEvents 指定事件总线，用于发布 extract/done/error 事件。
+func WithInstallEvents(bus *events.Bus) InstallerOption {
+	return func(i *Installer) {
+		i.events = bus
+	}
+}
+
+// WithStreamingExtract 在下载器支持时边下载边解压，不再落盘完整归档。
+func WithStreamingExtract() InstallerOption {
+	return func(i *Installer) {
+		i.streaming = true
+	}
+}
+
+// WithInstallShims 在安装完成后为该版本生成 go<version> shim。
+func WithInstallShims(s ShimLinker) InstallerOption {
+	return func(i *Installer) {
+		i.shims = s
+	}
 }
 
 // NewInstaller 创建 Installer。
-func NewInstaller(store storage.LocalStorage, downloader ArtifactDownloader) *Installer {
-	return &Installer{
+func NewInstaller(store storage.LocalStorage, downloader ArtifactDownloader, opts ...InstallerOption) *Installer {
+	i := &Installer{
 		storage:    store,
 		downloader: downloader,
 		now:        time.Now,
 	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
 }
 
 // Install 执行完整的安装流程，满足需求 3 的验收标准。
 func (i *Installer) Install(version models.Version) error {
+	if err := i.install(version); err != nil {
+		i.events.Publish(events.New(events.Error, "version", version.Number, "message", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (i *Installer) install(version models.Version) error {
 	if i.storage == nil || i.downloader == nil {
 		return errors.New("installer: missing dependencies")
 	}
+	if err := checkWritable(i.storage); err != nil {
+		return err
+	}
 
+	i.recoverOnce()
 	installed, err := i.isVersionInstalled(version.Number)
 	if err != nil {
 		return err
 	}
 	if installed {
+		i.events.Publish(events.New(events.Done,
+			"version", version.Number,
+			"path", i.storage.GetInstallPath(version.Number),
+			"status", "already_installed",
+		))
 		return nil
 	}
 
@@ -56,44 +135,211 @@ func (i *Installer) Install(version models.Version) error {
 		return fmt.Errorf("installer: prepare parent dir: %w", err)
 	}
 
-	archivePath, err := i.downloader.Download(version)
+	tempDir, err := os.MkdirTemp(filepath.Dir(installPath), "install-*")
 	if err != nil {
-		return err
+		return fmt.Errorf("installer: create temp dir: %w", err)
 	}
+	keepTemp := false
+	defer func() {
+		if !keepTemp {
+			storage.RemoveTree(tempDir)
+		}
+	}()
 
-	tempDir, err := os.MkdirTemp(filepath.Dir(installPath), "install-*")
+	version.InstallPath = installPath
+	version.InstalledAt = i.now().UTC()
+	_, statErr := os.Lstat(installPath)
+	journal, err := writeJournal(filepath.Dir(installPath), installJournal{
+		Version:     version.Number,
+		TempDir:     tempDir,
+		InstallPath: installPath,
+		InstalledAt: version.InstalledAt,
+		Previous:    statErr == nil,
+	})
 	if err != nil {
-		return fmt.Errorf("installer: create temp dir: %w", err)
+		return err
 	}
-	defer os.RemoveAll(tempDir)
 
-	destDir := filepath.Join(tempDir, "root")
-	if err := os.MkdirAll(destDir, 0o755); err != nil {
-		return fmt.Errorf("installer: prepare extract dir: %w", err)
+	txn := &installTxn{}
+	if err := i.apply(txn, version, tempDir); err != nil {
+		if rbErr := txn.rollback(); rbErr != nil {
+			// 暂存目录中可能还有旧安装的备份，连同日志一起保留，下次运行时由 Recover 继续恢复。
+			keepTemp = true
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		os.Remove(journal)
+		return err
+	}
+	if err := os.Remove(journal); err != nil {
+		return fmt.Errorf("installer: remove journal: %w", err)
+	}
+
+	i.events.Publish(events.New(events.Done, "version", version.Number, "path", installPath, "status", "installed"))
+	return nil
+}
+
+// checkWritable 在 store 实现 storage.WriteGuard 时确认元数据可以写入，供修改安装的操作在改动磁盘前调用。
+func checkWritable(store storage.LocalStorage) error {
+	if guard, ok := store.(storage.WriteGuard); ok {
+		return guard.CheckWritable()
 	}
+	return nil
+}
 
-	if err := extractTarGz(archivePath, destDir); err != nil {
+// apply 依次执行安装步骤，每完成一步就在 txn 中登记对应的补偿动作。
+// 安装目录已存在时先移入 tempDir 作为备份，失败时移回，成功后随 tempDir 一起删除。
+func (i *Installer) apply(txn *installTxn, version models.Version, tempDir string) error {
+	installPath := version.InstallPath
+	destDir := filepath.Join(tempDir, "root")
+	files, err := i.fetchAndExtract(version, destDir)
+	if err != nil {
+		return err
+	}
+	if err := applyPermissions(destDir, i.perms, files); err != nil {
 		return err
 	}
 
-	if err := os.RemoveAll(installPath); err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("installer: cleanup previous install: %w", err)
+	_, err = os.Lstat(installPath)
+	hadPrevious := err == nil
+	if hadPrevious {
+		// 在不同父目录间移动目录需要该目录本身可写，以前的只读安装需先恢复写权限。
+		if err := storage.Thaw(installPath); err != nil {
+			return fmt.Errorf("installer: back up previous install: %w", err)
+		}
+		backup := filepath.Join(tempDir, previousDirName)
+		if err := moveDir(installPath, backup); err != nil {
+			return fmt.Errorf("installer: back up previous install: %w", err)
+		}
+		txn.onRollback(func() error {
+			if err := i.restoreBackup(backup, installPath); err != nil {
+				return err
+			}
+			// 以前的安装仍在使用，shim 按移回后的目录重新生成。
+			if i.shims != nil {
+				return i.shims.Link(version.Number, installPath)
+			}
+			return nil
+		})
 	}
 
-	if err := os.Rename(destDir, installPath); err != nil {
+	if err := moveDir(destDir, installPath); err != nil {
 		return fmt.Errorf("installer: move install directory: %w", err)
 	}
+	txn.onRollback(func() error { return storage.RemoveTree(installPath) })
+	if i.perms.ReadOnly {
+		if err := freeze(installPath, files); err != nil {
+			return err
+		}
+	}
 
-	version.InstallPath = installPath
-	version.InstalledAt = i.now().UTC()
+	if manifests, ok := i.storage.(storage.ManifestStorage); ok {
+		previous, err := manifests.LoadManifest(version.Number)
+		hadPrevious := err == nil
+		if err := manifests.SaveManifest(models.FileManifest{Version: version.Number, Files: files}); err != nil {
+			return fmt.Errorf("installer: save manifest: %w", err)
+		}
+		txn.onRollback(func() error {
+			if hadPrevious {
+				return manifests.SaveManifest(previous)
+			}
+			return manifests.DeleteManifest(version.Number)
+		})
+	}
 
+	existing, err := i.storage.LoadMetadata()
+	if err != nil {
+		return fmt.Errorf("installer: load metadata: %w", err)
+	}
+	// 重新安装不应悄悄解除 govm lock。
+	for _, v := range existing {
+		if v.Number == version.Number {
+			version.Locked = v.Locked
+		}
+	}
+	version.Channel = ChannelOf(version)
+	if source, ok := i.downloader.(ProvenanceSource); ok {
+		if p, ok := source.Provenance(version.FileName); ok {
+			version.Provenance = &p
+		}
+	}
 	if err := i.storage.SaveMetadata(version); err != nil {
 		return fmt.Errorf("installer: save metadata: %w", err)
 	}
+	txn.onRollback(func() error {
+		for _, v := range existing {
+			if v.Number == version.Number {
+				return i.storage.SaveMetadata(v)
+			}
+		}
+		return i.storage.DeleteMetadata(version.Number)
+	})
 
+	if i.shims != nil {
+		if !hadPrevious {
+			txn.onRollback(func() error { return i.shims.Unlink(version.Number) })
+		}
+		if err := i.shims.Link(version.Number, installPath); err != nil {
+			return fmt.Errorf("installer: create shim: %w", err)
+		}
+	}
 	return nil
 }
 
+// fetchAndExtract 在启用流式模式时边下载边解压到 destDir，传输中断时清空暂存目录并回退到先下载后解压。
+func (i *Installer) fetchAndExtract(version models.Version, destDir string) ([]models.FileEntry, error) {
+	cache, cached := i.cachedArchive(version)
+	if streamer, ok := i.downloader.(StreamDownloader); ok && i.streaming && cached == "" {
+		if err := os.MkdirAll(destDir, 0o755); err != nil {
+			return nil, fmt.Errorf("installer: prepare extract dir: %w", err)
+		}
+		i.events.Publish(events.New(events.Extract, "version", version.Number, "archive", version.DownloadURL, "mode", "stream"))
+		var files []models.FileEntry
+		err := streamer.Stream(version, func(r io.Reader) error {
+			var err error
+			files, err = extractTarGzStream(r, destDir)
+			return err
+		})
+		if err == nil {
+			return files, nil
+		}
+		if !errors.Is(err, ErrStreamInterrupted) {
+			return nil, err
+		}
+		if err := os.RemoveAll(destDir); err != nil {
+			return nil, fmt.Errorf("installer: reset extract dir: %w", err)
+		}
+	}
+
+	var archivePath string
+	var err error
+	if cached != "" {
+		archivePath, err = cache.DownloadCached(version, cached)
+	} else {
+		archivePath, err = i.downloader.Download(version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("installer: prepare extract dir: %w", err)
+	}
+	i.events.Publish(events.New(events.Extract, "version", version.Number, "archive", archivePath))
+	return extractTarGz(archivePath, destDir)
+}
+
+// cachedArchive 返回 downloads 目录中已校验的该版本归档，没有时路径为空。
+func (i *Installer) cachedArchive(version models.Version) (ArchiveCache, string) {
+	cache, ok := i.downloader.(ArchiveCache)
+	if !ok {
+		return nil, ""
+	}
+	path, hit := cache.CachedArchive(version)
+	if !hit {
+		return nil, ""
+	}
+	return cache, path
+}
+
 func (i *Installer) isVersionInstalled(version string) (bool, error) {
 	versions, err := i.storage.LoadMetadata()
 	if err != nil {
@@ -116,20 +362,34 @@ func (i *Installer) isVersionInstalled(version string) (bool, error) {
 	return false, nil
 }
 
-func extractTarGz(archivePath, dest string) error {
+// extractTarGz 解压归档到 dest，并返回记录了每个文件权限与 SHA256 的清单条目。
+func extractTarGz(archivePath, dest string) ([]models.FileEntry, error) {
 	file, err := os.Open(archivePath)
 	if err != nil {
-		return fmt.Errorf("installer: open archive: %w", err)
+		return nil, fmt.Errorf("installer: open archive: %w", err)
 	}
 	defer file.Close()
 
-	gz, err := gzip.NewReader(file)
+	return extractTarGzStream(file, dest)
+}
+
+// extractTarGzStream 从任意 gzip 流解压，供文件与边下载边解压两种路径共用。
+func extractTarGzStream(r io.Reader, dest string) ([]models.FileEntry, error) {
+	gz, err := gzip.NewReader(r)
 	if err != nil {
-		return fmt.Errorf("installer: gzip reader: %w", err)
+		return nil, fmt.Errorf("installer: gzip reader: %w", err)
 	}
 	defer gz.Close()
 
-	tr := tar.NewReader(gz)
+	return extractTar(tar.NewReader(gz), dest)
+}
+
+// extractTar 逐条解压 tar 流。硬链接会被还原为指向归档内已有文件的链接，
+// PAX 全局头等元数据条目被忽略，文件与目录的修改时间按归档记录保留。
+func extractTar(tr *tar.Reader, dest string) ([]models.FileEntry, error) {
+	var files []models.FileEntry
+	byPath := make(map[string]int)
+	dirTimes := make(map[string]time.Time)
 
 	for {
 		header, err := tr.Next()
@@ -137,7 +397,13 @@ func extractTarGz(archivePath, dest string) error {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("installer: read archive: %w", err)
+			return nil, fmt.Errorf("installer: read archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader, tar.TypeXHeader:
+			// archive/tar 已将 PAX 记录合并进后续条目，独立出现时无需落盘。
+			continue
 		}
 
 		relPath, skip := normalizeTarPath(header.Name)
@@ -147,37 +413,122 @@ func extractTarGz(archivePath, dest string) error {
 
 		target := filepath.Join(dest, relPath)
 		if err := ensureWithinRoot(dest, target); err != nil {
-			return err
+			return nil, err
 		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
 			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
-				return fmt.Errorf("installer: mkdir %s: %w", target, err)
+				return nil, fmt.Errorf("installer: mkdir %s: %w", target, err)
 			}
-		case tar.TypeReg, tar.TypeRegA:
-			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
-				return fmt.Errorf("installer: mkdir for file %s: %w", target, err)
+			if !header.ModTime.IsZero() {
+				dirTimes[target] = header.ModTime
 			}
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+		case tar.TypeReg, tar.TypeRegA, tar.TypeGNUSparse:
+			entry, err := writeTarFile(tr, header, target, relPath)
 			if err != nil {
-				return fmt.Errorf("installer: create file %s: %w", target, err)
+				return nil, err
 			}
-			if _, err := io.Copy(f, tr); err != nil {
-				f.Close()
-				return fmt.Errorf("installer: copy file %s: %w", target, err)
+			byPath[relPath] = len(files)
+			files = append(files, entry)
+		case tar.TypeLink:
+			entry, err := writeHardLink(header, dest, target, relPath, files, byPath)
+			if err != nil {
+				return nil, err
 			}
-			f.Close()
+			byPath[relPath] = len(files)
+			files = append(files, entry)
 		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return nil, fmt.Errorf("installer: mkdir for symlink %s: %w", target, err)
+			}
 			if err := os.Symlink(header.Linkname, target); err != nil {
-				return fmt.Errorf("installer: symlink %s: %w", target, err)
+				return nil, fmt.Errorf("installer: symlink %s: %w", target, err)
 			}
+			files = append(files, models.FileEntry{Path: relPath, Link: header.Linkname})
 		default:
-			return fmt.Errorf("installer: unsupported tar entry %q", header.Name)
+			return nil, fmt.Errorf("installer: unsupported tar entry %q", header.Name)
 		}
 	}
 
-	return nil
+	// 目录时间最后设置，避免写入子项时被刷新。
+	for dir, mtime := range dirTimes {
+		if err := os.Chtimes(dir, mtime, mtime); err != nil {
+			return nil, fmt.Errorf("installer: set mtime %s: %w", dir, err)
+		}
+	}
+
+	return files, nil
+}
+
+// copyBuffers 复用解压时的拷贝缓冲区：Go 发行版约有 12k 个文件，io.Copy 会为每个文件分配一个 32KiB 的缓冲区。
+var copyBuffers = sync.Pool{New: func() any {
+	buf := make([]byte, 128<<10)
+	return &buf
+}}
+
+func writeTarFile(r io.Reader, header *tar.Header, target, relPath string) (models.FileEntry, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return models.FileEntry{}, fmt.Errorf("installer: mkdir for file %s: %w", target, err)
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+	if err != nil {
+		return models.FileEntry{}, fmt.Errorf("installer: create file %s: %w", target, err)
+	}
+	preallocate(f, header.Size)
+	hasher := sha256.New()
+	buf := copyBuffers.Get().(*[]byte)
+	size, err := io.CopyBuffer(io.MultiWriter(f, hasher), r, *buf)
+	copyBuffers.Put(buf)
+	if err != nil {
+		f.Close()
+		return models.FileEntry{}, fmt.Errorf("installer: copy file %s: %w", target, err)
+	}
+	// 以落盘后的权限为准，umask 可能改变归档中记录的值。
+	info, err := f.Stat()
+	f.Close()
+	if err != nil {
+		return models.FileEntry{}, fmt.Errorf("installer: stat file %s: %w", target, err)
+	}
+	if !header.ModTime.IsZero() {
+		if err := os.Chtimes(target, header.ModTime, header.ModTime); err != nil {
+			return models.FileEntry{}, fmt.Errorf("installer: set mtime %s: %w", target, err)
+		}
+	}
+	return models.FileEntry{
+		Path:   relPath,
+		Size:   size,
+		Mode:   info.Mode().Perm(),
+		SHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// writeHardLink 为归档中的硬链接创建链接，链接源必须是此前已解压的普通文件。
+func writeHardLink(header *tar.Header, dest, target, relPath string, files []models.FileEntry, byPath map[string]int) (models.FileEntry, error) {
+	linkRel, skip := normalizeTarPath(header.Linkname)
+	if skip {
+		return models.FileEntry{}, fmt.Errorf("installer: hard link %q has invalid target %q", header.Name, header.Linkname)
+	}
+	idx, ok := byPath[linkRel]
+	if !ok {
+		return models.FileEntry{}, fmt.Errorf("installer: hard link %q points to unknown file %q", header.Name, header.Linkname)
+	}
+	source := filepath.Join(dest, linkRel)
+	if err := ensureWithinRoot(dest, source); err != nil {
+		return models.FileEntry{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return models.FileEntry{}, fmt.Errorf("installer: mkdir for link %s: %w", target, err)
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return models.FileEntry{}, fmt.Errorf("installer: replace %s: %w", target, err)
+	}
+	if err := os.Link(source, target); err != nil {
+		return models.FileEntry{}, fmt.Errorf("installer: hard link %s: %w", target, err)
+	}
+	entry := files[idx]
+	entry.Path = relPath
+	return entry, nil
 }
 
 func normalizeTarPath(name string) (string, bool) {
@@ -2,13 +2,19 @@ package version
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/liangyou/govm/internal/events"
 	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
@@ -40,7 +46,8 @@ func TestInstallerInstallAndIdempotent(t *testing.T) {
 	})
 
 	down := &stubDownloader{path: tarPath}
-	installer := NewInstaller(store, down)
+	shims := &recordingShims{}
+	installer := NewInstaller(store, down, WithInstallShims(shims))
 
 	version := models.Version{
 		Number:      "1.21.0",
@@ -63,9 +70,13 @@ func TestInstallerInstallAndIdempotent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadMetadata failed: %v", err)
 	}
-	if len(meta) != 1 || meta[0].Number != "1.21.0" {
+	// 按命名规则推导的版本没有渠道，安装时按版本号补齐并写入元数据。
+	if len(meta) != 1 || meta[0].Number != "1.21.0" || meta[0].Channel != models.ChannelStable {
 		t.Fatalf("unexpected metadata: %#v", meta)
 	}
+	if shims.linked["1.21.0"] != installPath {
+		t.Fatalf("expected shim for %s, got %v", installPath, shims.linked)
+	}
 
 	if err := installer.Install(version); err != nil {
 		t.Fatalf("second install failed: %v", err)
@@ -76,6 +87,48 @@ func TestInstallerInstallAndIdempotent(t *testing.T) {
 	}
 }
 
+// provenanceDownloader 在 stubDownloader 之上报告固定的归档来源。
+type provenanceDownloader struct {
+	stubDownloader
+	provenance models.Provenance
+}
+
+func (p *provenanceDownloader) Provenance(string) (models.Provenance, bool) {
+	return p.provenance, true
+}
+
+func TestInstallerRecordsProvenance(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	fetched := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	down := &provenanceDownloader{
+		stubDownloader: stubDownloader{path: createGoArchive(t, map[string]string{"bin/go": "binary"})},
+		provenance: models.Provenance{
+			Mirror:         "https://mirrors.example",
+			URL:            "https://mirrors.example/go1.21.0.tar.gz",
+			ChecksumSource: models.ChecksumOfficial,
+			FetchedAt:      fetched,
+		},
+	}
+	version := models.Version{Number: "1.21.0", FileName: "go1.21.0.tar.gz", Checksum: "checksum"}
+	if err := NewInstaller(store, down).Install(version); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	meta, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatalf("LoadMetadata failed: %v", err)
+	}
+	if len(meta) != 1 || meta[0].Provenance == nil {
+		t.Fatalf("expected provenance in metadata: %#v", meta)
+	}
+	if got := *meta[0].Provenance; got.Mirror != down.provenance.Mirror || got.ChecksumSource != models.ChecksumOfficial || !got.FetchedAt.Equal(fetched) {
+		t.Fatalf("unexpected provenance: %#v", got)
+	}
+}
+
 func TestInstallerFailureCleansUp(t *testing.T) {
 	t.Parallel()
 
@@ -111,6 +164,370 @@ func TestInstallerFailureCleansUp(t *testing.T) {
 	}
 }
 
+// failingMetadataStore 在 SaveMetadata 时失败，模拟目录已就位但元数据写入出错。
+type failingMetadataStore struct {
+	*storage.FileStorage
+}
+
+func (f failingMetadataStore) SaveMetadata(models.Version) error {
+	return errors.New("disk full")
+}
+
+func TestInstallerRollsBackWhenMetadataFails(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+	store := storage.NewFileStorage(cfg)
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "binary"})
+	installer := NewInstaller(failingMetadataStore{store}, &stubDownloader{path: tarPath})
+
+	err := installer.Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected metadata failure, got %v", err)
+	}
+	entries, err := os.ReadDir(cfg.VersionsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected versions dir to be empty after rollback, got %v", entries)
+	}
+	if _, err := store.LoadManifest("1.21.0"); !errors.Is(err, storage.ErrNoManifest) {
+		t.Fatalf("expected manifest to be rolled back, got %v", err)
+	}
+}
+
+func TestRecoverRestoresBackup(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+	store := storage.NewFileStorage(cfg)
+	installPath := store.GetInstallPath("1.21.0")
+	tempDir := filepath.Join(cfg.VersionsDir, "install-456")
+	backup := filepath.Join(tempDir, previousDirName)
+	// 模拟旧目录已移入备份、新目录刚改名就位时进程退出。
+	for _, dir := range []string{backup, installPath} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(backup, "VERSION"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeJournal(cfg.VersionsDir, installJournal{Version: "1.21.0", TempDir: tempDir, InstallPath: installPath, Previous: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	rolledBack, err := NewInstaller(store, &stubDownloader{}).Recover()
+	if err != nil || len(rolledBack) != 1 {
+		t.Fatalf("Recover = %v, %v", rolledBack, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(installPath, "VERSION")); err != nil || string(data) != "old" {
+		t.Fatalf("expected backup to be restored, got %q err=%v", data, err)
+	}
+	if _, err := os.Stat(tempDir); !os.IsNotExist(err) {
+		t.Fatalf("expected temp dir to be removed, err=%v", err)
+	}
+}
+
+func TestInstallerRestoresPreviousTreeOnFailure(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+	store := storage.NewFileStorage(cfg)
+	installPath := store.GetInstallPath("1.21.0")
+	if err := os.MkdirAll(filepath.Join(installPath, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "bin", "go"), []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "new"})
+	installer := NewInstaller(failingMetadataStore{store}, &stubDownloader{path: tarPath})
+	if err := installer.Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err == nil {
+		t.Fatal("expected metadata failure")
+	}
+	data, err := os.ReadFile(filepath.Join(installPath, "bin", "go"))
+	if err != nil || string(data) != "old" {
+		t.Fatalf("expected previous tree to be restored, got %q err=%v", data, err)
+	}
+	entries, _ := os.ReadDir(cfg.VersionsDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected only the restored install dir, got %v", entries)
+	}
+
+	if err := NewInstaller(store, &stubDownloader{path: tarPath}).Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err != nil {
+		t.Fatalf("reinstall failed: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(installPath, "bin", "go"))
+	if string(data) != "new" {
+		t.Fatalf("expected new tree after reinstall, got %q", data)
+	}
+	entries, _ = os.ReadDir(cfg.VersionsDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected backup to be deleted after reinstall, got %v", entries)
+	}
+}
+
+func TestInstallerRecoversInterruptedInstall(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+	store := storage.NewFileStorage(cfg)
+
+	// 模拟进程在目录改名之后、写入元数据之前被杀死。
+	installPath := store.GetInstallPath("1.20.0")
+	tempDir := filepath.Join(cfg.VersionsDir, "install-123")
+	for _, dir := range []string{filepath.Join(installPath, "bin"), tempDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := writeJournal(cfg.VersionsDir, installJournal{Version: "1.20.0", TempDir: tempDir, InstallPath: installPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported []string
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "binary"})
+	installer := NewInstaller(store, &stubDownloader{path: tarPath}, WithRecoveryReporter(func(ver string, err error) {
+		if err != nil {
+			t.Errorf("unexpected recovery error: %v", err)
+		}
+		reported = append(reported, ver)
+	}))
+	if err := installer.Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	if len(reported) != 1 || reported[0] != "1.20.0" {
+		t.Fatalf("expected go1.20.0 to be rolled back, got %v", reported)
+	}
+	for _, p := range []string{installPath, tempDir, journalPath(cfg.VersionsDir, "1.20.0")} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, err=%v", p, err)
+		}
+	}
+	if _, err := os.Stat(journalPath(cfg.VersionsDir, "1.21.0")); !os.IsNotExist(err) {
+		t.Fatalf("journal of a committed install should be removed, err=%v", err)
+	}
+}
+
+func TestInstallerAppliesPermissions(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+	store := storage.NewFileStorage(cfg)
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "binary", "VERSION": "go1.21.0"})
+	perms := Permissions{DirMode: 0o775, FileMode: 0o640, Chown: true, UID: os.Getuid(), GID: os.Getgid()}
+	installer := NewInstaller(store, &stubDownloader{path: tarPath}, WithInstallPermissions(perms))
+
+	if err := installer.Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	installPath := store.GetInstallPath("1.21.0")
+	for path, want := range map[string]os.FileMode{
+		installPath:                             0o775,
+		filepath.Join(installPath, "bin"):       0o775,
+		filepath.Join(installPath, "bin", "go"): 0o750,
+		filepath.Join(installPath, "VERSION"):   0o750,
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Fatalf("%s mode = %#o, want %#o", path, got, want)
+		}
+	}
+	if got := perms.fileMode(0o644); got != 0o640 {
+		t.Fatalf("non-executable files should get fileMode, got %#o", got)
+	}
+	manifest, err := store.LoadManifest("1.21.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range manifest.Files {
+		if f.Path == "VERSION" && f.Mode != 0o750 {
+			t.Fatalf("manifest should record the applied mode, got %#o", f.Mode)
+		}
+	}
+}
+
+func TestInstallerRelinksPreviousInstallWhenReinstallFails(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	installPath := store.GetInstallPath("1.21.0")
+	if err := os.MkdirAll(filepath.Join(installPath, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "bin", "go"), []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	shims := &recordingShims{failNext: errors.New("shim dir is read-only")}
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "new"})
+	installer := NewInstaller(store, &stubDownloader{path: tarPath}, WithInstallShims(shims))
+	if err := installer.Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err == nil {
+		t.Fatal("expected shim failure")
+	}
+	if data, err := os.ReadFile(filepath.Join(installPath, "bin", "go")); err != nil || string(data) != "old" {
+		t.Fatalf("expected previous tree to be restored, got %q err=%v", data, err)
+	}
+	if len(shims.unlinked) != 0 {
+		t.Fatalf("shims of the restored install must not be removed: %v", shims.unlinked)
+	}
+	if shims.linked["1.21.0"] != installPath {
+		t.Fatalf("expected shims to be re-linked to %s, got %v", installPath, shims.linked)
+	}
+}
+
+func TestInstallerReadOnlyGoRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+	store := storage.NewFileStorage(cfg)
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "binary", "src/fmt/print.go": "package fmt"})
+	installer := NewInstaller(store, &stubDownloader{path: tarPath}, WithInstallPermissions(Permissions{ReadOnly: true}))
+
+	if err := installer.Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	installPath := store.GetInstallPath("1.21.0")
+	for _, path := range []string{installPath, filepath.Join(installPath, "src", "fmt"), filepath.Join(installPath, "src", "fmt", "print.go")} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm()&0o222 != 0 {
+			t.Fatalf("%s must be read-only, got %#o", path, info.Mode().Perm())
+		}
+	}
+	manifest, err := store.LoadManifest("1.21.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range manifest.Files {
+		if f.Mode&0o222 != 0 {
+			t.Fatalf("manifest must record the read-only mode of %s, got %#o", f.Path, f.Mode)
+		}
+	}
+
+	if err := storage.Thaw(installPath); err != nil {
+		t.Fatalf("Thaw: %v", err)
+	}
+	if info, _ := os.Stat(filepath.Join(installPath, "src")); info.Mode().Perm()&0o200 == 0 {
+		t.Fatalf("Thaw must make directories writable again, got %#o", info.Mode().Perm())
+	}
+	if info, _ := os.Stat(filepath.Join(installPath, "bin", "go")); info.Mode().Perm()&0o222 != 0 {
+		t.Fatalf("Thaw must leave files read-only, got %#o", info.Mode().Perm())
+	}
+	if err := freeze(installPath, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.RemoveTree(installPath); err != nil {
+		t.Fatalf("RemoveTree: %v", err)
+	}
+	if _, err := os.Lstat(installPath); !os.IsNotExist(err) {
+		t.Fatalf("read-only tree must be removed: %v", err)
+	}
+}
+
+func TestReadOnlyBackupIsFrozenAgainAfterRestore(t *testing.T) {
+	t.Parallel()
+
+	readOnly := WithInstallPermissions(Permissions{ReadOnly: true})
+	assertFrozen := func(t *testing.T, path string) {
+		t.Helper()
+		for _, p := range []string{path, filepath.Join(path, "bin")} {
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm()&0o222 != 0 {
+				t.Fatalf("restored %s must be read-only again, got %#o", p, info.Mode().Perm())
+			}
+		}
+	}
+
+	t.Run("rollback", func(t *testing.T) {
+		root := t.TempDir()
+		store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+		installPath := store.GetInstallPath("1.21.0")
+		t.Cleanup(func() { storage.RemoveTree(installPath) })
+		tarPath := createGoArchive(t, map[string]string{"bin/go": "binary"})
+		if err := NewInstaller(store, &stubDownloader{path: tarPath}, readOnly).Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+		if err := store.DeleteMetadata("1.21.0"); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewInstaller(failingMetadataStore{store}, &stubDownloader{path: tarPath}, readOnly).Install(models.Version{Number: "1.21.0", FullName: "go1.21.0"}); err == nil {
+			t.Fatal("expected metadata failure")
+		}
+		assertFrozen(t, installPath)
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		root := t.TempDir()
+		cfg := models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")}
+		store := storage.NewFileStorage(cfg)
+		installPath := store.GetInstallPath("1.21.0")
+		t.Cleanup(func() { storage.RemoveTree(installPath) })
+		tempDir := filepath.Join(cfg.VersionsDir, "install-789")
+		// 备份时已为移动恢复了目录写权限。
+		backup := filepath.Join(tempDir, previousDirName)
+		for _, dir := range []string{filepath.Join(backup, "bin"), installPath} {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := writeJournal(cfg.VersionsDir, installJournal{Version: "1.21.0", TempDir: tempDir, InstallPath: installPath, Previous: true}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewInstaller(store, &stubDownloader{}, readOnly).Recover(); err != nil {
+			t.Fatalf("Recover: %v", err)
+		}
+		assertFrozen(t, installPath)
+	})
+}
+
+func TestInstallerPublishesEvents(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	tarPath := createGoArchive(t, map[string]string{"bin/go": "binary"})
+
+	bus := events.NewBus()
+	var got []string
+	bus.Subscribe(func(e events.Event) {
+		got = append(got, e.Type+":"+e.Get("status"))
+	})
+
+	installer := NewInstaller(store, &stubDownloader{path: tarPath}, WithInstallEvents(bus))
+	version := models.Version{Number: "1.21.0", FullName: "go1.21.0"}
+	if err := installer.Install(version); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if err := installer.Install(version); err != nil {
+		t.Fatalf("second install failed: %v", err)
+	}
+
+	want := []string{"extract:", "done:installed", "done:already_installed"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected events: %v", got)
+	}
+}
+
 func createGoArchive(t *testing.T, files map[string]string) string {
 	t.Helper()
 
@@ -204,3 +621,259 @@ func createInvalidArchive(t *testing.T) string {
 	}
 	return pathOnDisk
 }
+
+func buildTar(t testing.TB, entries []tarEntry) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		if err := tw.WriteHeader(e.header); err != nil {
+			t.Fatalf("write header %s: %v", e.header.Name, err)
+		}
+		if e.body != "" {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatalf("write body %s: %v", e.header.Name, err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+type tarEntry struct {
+	header *tar.Header
+	body   string
+}
+
+func TestExtractTarHandlesHardLinksPaxAndMtimes(t *testing.T) {
+	t.Parallel()
+
+	mtime := time.Date(2024, 2, 6, 18, 0, 0, 0, time.UTC)
+	data := buildTar(t, []tarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header", PAXRecords: map[string]string{"comment": "release"}}},
+		{header: &tar.Header{Typeflag: tar.TypeDir, Name: "go/bin/", Mode: 0o755, ModTime: mtime}},
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "go/bin/go", Mode: 0o755, Size: 6, ModTime: mtime, Format: tar.FormatPAX,
+			PAXRecords: map[string]string{"GOVM.test": "1"}}, body: "binary"},
+		{header: &tar.Header{Typeflag: tar.TypeLink, Name: "go/bin/go-link", Linkname: "go/bin/go", ModTime: mtime}},
+	})
+
+	dest := t.TempDir()
+	files, err := extractTar(tar.NewReader(bytes.NewReader(data)), dest)
+	if err != nil {
+		t.Fatalf("extractTar failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected two manifest entries, got %#v", files)
+	}
+	if files[1].Path != "bin/go-link" || files[1].SHA256 != files[0].SHA256 {
+		t.Fatalf("hard link entry should mirror its source: %#v", files[1])
+	}
+
+	orig, err := os.Stat(filepath.Join(dest, "bin", "go"))
+	if err != nil {
+		t.Fatalf("stat go: %v", err)
+	}
+	link, err := os.Stat(filepath.Join(dest, "bin", "go-link"))
+	if err != nil {
+		t.Fatalf("stat link: %v", err)
+	}
+	if !os.SameFile(orig, link) {
+		t.Fatal("expected go-link to be a hard link to go")
+	}
+	if !orig.ModTime().Equal(mtime) {
+		t.Fatalf("file mtime not preserved: %v", orig.ModTime())
+	}
+	dir, err := os.Stat(filepath.Join(dest, "bin"))
+	if err != nil {
+		t.Fatalf("stat dir: %v", err)
+	}
+	if !dir.ModTime().Equal(mtime) {
+		t.Fatalf("dir mtime not preserved: %v", dir.ModTime())
+	}
+}
+
+func TestExtractTarRejectsDanglingHardLink(t *testing.T) {
+	t.Parallel()
+
+	data := buildTar(t, []tarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeLink, Name: "go/bin/go", Linkname: "go/../../etc/passwd"}},
+	})
+	if _, err := extractTar(tar.NewReader(bytes.NewReader(data)), t.TempDir()); err == nil {
+		t.Fatal("expected error for hard link outside archive")
+	}
+}
+
+func FuzzExtractTar(f *testing.F) {
+	f.Add(buildTar(f, []tarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "go/VERSION", Mode: 0o644, Size: 8}, body: "go1.22.0"},
+	}))
+	f.Add(buildTar(f, []tarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "go/a", Mode: 0o644, Size: 1}, body: "a"},
+		{header: &tar.Header{Typeflag: tar.TypeLink, Name: "go/b", Linkname: "go/a"}},
+		{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "go/c", Linkname: "a"}},
+	}))
+	f.Add(buildTar(f, []tarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "go/../../escape", Mode: 0o644, Size: 1}, body: "x"},
+	}))
+	f.Add([]byte("not a tar archive"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dest := t.TempDir()
+		files, err := extractTar(tar.NewReader(bytes.NewReader(data)), dest)
+		if err != nil {
+			return
+		}
+		for _, entry := range files {
+			target := filepath.Join(dest, filepath.FromSlash(entry.Path))
+			if err := ensureWithinRoot(dest, target); err != nil {
+				t.Fatalf("manifest entry escapes destination: %s", entry.Path)
+			}
+			if _, err := os.Lstat(target); err != nil {
+				t.Fatalf("manifest entry %s not on disk: %v", entry.Path, err)
+			}
+		}
+	})
+}
+
+// BenchmarkExtractTar 解压结构近似 Go 发行版的归档：大量小源文件加少量大的工具二进制。
+func BenchmarkExtractTar(b *testing.B) {
+	var entries []tarEntry
+	small := strings.Repeat("package p\n", 400)
+	for i := 0; i < 2000; i++ {
+		entries = append(entries, tarEntry{
+			header: &tar.Header{Typeflag: tar.TypeReg, Name: fmt.Sprintf("go/src/pkg%d/file%d.go", i/20, i), Mode: 0o644, Size: int64(len(small))},
+			body:   small,
+		})
+	}
+	large := strings.Repeat("\x7fELF", 1<<20)
+	for i := 0; i < 4; i++ {
+		entries = append(entries, tarEntry{
+			header: &tar.Header{Typeflag: tar.TypeReg, Name: fmt.Sprintf("go/pkg/tool/tool%d", i), Mode: 0o755, Size: int64(len(large))},
+			body:   large,
+		})
+	}
+	data := buildTar(b, entries)
+	root := b.TempDir()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dest := filepath.Join(root, "go")
+		if _, err := extractTar(tar.NewReader(bytes.NewReader(data)), dest); err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		if err := os.RemoveAll(dest); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+	}
+}
+
+type streamingDownloader struct {
+	stubDownloader
+	interrupt bool
+	streams   int
+}
+
+func (s *streamingDownloader) Stream(_ models.Version, consume func(io.Reader) error) error {
+	s.streams++
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if s.interrupt {
+		_ = consume(io.LimitReader(f, 32))
+		return fmt.Errorf("%w: connection reset", ErrStreamInterrupted)
+	}
+	return consume(f)
+}
+
+func TestInstallerStreamsAndFallsBack(t *testing.T) {
+	t.Parallel()
+
+	archive := createGoArchive(t, map[string]string{"bin/go": "binary", "VERSION": "go1.22.0"})
+
+	for _, interrupt := range []bool{false, true} {
+		root := t.TempDir()
+		store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+		dl := &streamingDownloader{stubDownloader: stubDownloader{path: archive}, interrupt: interrupt}
+		installer := NewInstaller(store, dl, WithStreamingExtract())
+
+		if err := installer.Install(models.Version{Number: "1.22.0"}); err != nil {
+			t.Fatalf("install (interrupt=%v) failed: %v", interrupt, err)
+		}
+		if dl.streams != 1 {
+			t.Fatalf("expected one streaming attempt, got %d", dl.streams)
+		}
+		wantCalls := 0
+		if interrupt {
+			wantCalls = 1
+		}
+		if dl.calls != wantCalls {
+			t.Fatalf("interrupt=%v: expected %d two-phase downloads, got %d", interrupt, wantCalls, dl.calls)
+		}
+		data, err := os.ReadFile(filepath.Join(store.GetInstallPath("1.22.0"), "VERSION"))
+		if err != nil || string(data) != "go1.22.0" {
+			t.Fatalf("installed VERSION mismatch: %q %v", data, err)
+		}
+	}
+}
+
+// cachingDownloader 模拟 downloads 目录中已有校验通过的归档，记录校验次数。
+type cachingDownloader struct {
+	streamingDownloader
+	checks int
+	reused string
+}
+
+func (c *cachingDownloader) CachedArchive(models.Version) (string, bool) {
+	c.checks++
+	return c.path, true
+}
+
+func (c *cachingDownloader) DownloadCached(_ models.Version, cached string) (string, error) {
+	c.reused = cached
+	return cached, nil
+}
+
+func TestInstallerVerifiesCachedArchiveOnce(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	archive := createGoArchive(t, map[string]string{"bin/go": "binary"})
+	dl := &cachingDownloader{streamingDownloader: streamingDownloader{stubDownloader: stubDownloader{path: archive}}}
+	if err := NewInstaller(store, dl, WithStreamingExtract()).Install(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if dl.checks != 1 || dl.reused != archive {
+		t.Fatalf("expected one checksum pass whose path is reused, got checks=%d reused=%q", dl.checks, dl.reused)
+	}
+	if dl.streams != 0 || dl.calls != 0 {
+		t.Fatalf("a cached archive must not be fetched again: streams=%d downloads=%d", dl.streams, dl.calls)
+	}
+}
+
+func TestInstallerRefusesCorruptMetadataBeforeDownloading(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+	if err := store.SaveMetadata(models.Version{Number: "1.21.0", InstallPath: store.GetInstallPath("1.21.0")}); err != nil {
+		t.Fatal(err)
+	}
+	damageMetadata(t, root, true)
+
+	down := &stubDownloader{path: createGoArchive(t, map[string]string{"bin/go": "binary"})}
+	err := NewInstaller(store, down).Install(models.Version{Number: "1.22.0", FullName: "go1.22.0"})
+	if !errors.Is(err, storage.ErrCorruptMetadata) || down.calls != 0 {
+		t.Fatalf("Install = %v after %d downloads, want ErrCorruptMetadata before downloading", err, down.calls)
+	}
+	if _, err := os.Stat(store.GetInstallPath("1.22.0")); !os.IsNotExist(err) {
+		t.Fatalf("nothing may be extracted: %v", err)
+	}
+}
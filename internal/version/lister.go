@@ -1,14 +1,18 @@
 package version
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/liangyou/govm/internal/remote"
 	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/goversion"
 	"github.com/liangyou/govm/pkg/models"
 )
 
@@ -25,16 +29,133 @@ func NewLister(remoteClient remote.RemoteClient, store storage.LocalStorage) *Li
 
 // RemoteVersions 返回远程版本并格式化。
 func (l *Lister) RemoteVersions() ([]models.Version, error) {
+	return l.remoteVersions(context.Background())
+}
+
+func (l *Lister) remoteVersions(ctx context.Context) ([]models.Version, error) {
 	if l.remote == nil {
 		return nil, fmt.Errorf("lister: remote client is required")
 	}
-	versions, err := l.remote.FetchVersions()
+	versions, err := l.remote.FetchVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return versions, nil
 }
 
+// Listing 为 ListAll 的结果：Remote 中已安装的版本带有 InstallPath 与 IsCurrent，获取失败的一方为 nil。
+type Listing struct {
+	Remote []models.Version
+	Local  []models.Version
+}
+
+// ListAll 并发获取远程与本地版本，用于远程列表标记已安装版本等组合视图。任一方失败时仍返回另一方的结果，
+// 错误用 errors.Join 合并；ctx 结束时立即返回，并通过 ctx 中断进行中的版本源请求。
+func (l *Lister) ListAll(ctx context.Context) (Listing, error) {
+	type result struct {
+		versions []models.Version
+		err      error
+	}
+	// 缓冲为 1，ctx 提前结束时 goroutine 仍能写入结果并退出。
+	remoteCh, localCh := make(chan result, 1), make(chan result, 1)
+	go func() {
+		versions, err := l.remoteVersions(ctx)
+		remoteCh <- result{versions, err}
+	}()
+	go func() {
+		versions, err := l.LocalVersions()
+		localCh <- result{versions, err}
+	}()
+
+	var (
+		listing Listing
+		errs    []error
+	)
+	for pending := 2; pending > 0; pending-- {
+		select {
+		case <-ctx.Done():
+			return listing, errors.Join(append(errs, ctx.Err())...)
+		case r := <-remoteCh:
+			if r.err != nil {
+				errs = append(errs, r.err)
+				continue
+			}
+			// 版本源可能复用缓存中的切片，标记前先复制。
+			listing.Remote = slices.Clone(r.versions)
+		case r := <-localCh:
+			if r.err != nil {
+				errs = append(errs, r.err)
+				continue
+			}
+			listing.Local = r.versions
+		}
+	}
+	markInstalled(listing.Remote, listing.Local)
+	return listing, errors.Join(errs...)
+}
+
+// markInstalled 按版本号为远程条目补齐本地安装路径与当前版本标记。
+func markInstalled(remote, local []models.Version) {
+	installed := make(map[string]models.Version, len(local))
+	for _, v := range local {
+		installed[v.Number] = v
+	}
+	for i := range remote {
+		if v, ok := installed[remote[i].Number]; ok {
+			remote[i].InstallPath = v.InstallPath
+			remote[i].IsCurrent = v.IsCurrent
+		}
+	}
+}
+
+// Paginate 返回第 page 页（从 1 开始）的条目以及总数，size<=0 时返回全部。
+func Paginate[T any](items []T, page, size int) ([]T, int) {
+	total := len(items)
+	if size <= 0 {
+		return items, total
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * size
+	if start >= total {
+		return nil, total
+	}
+	end := start + size
+	if end > total {
+		end = total
+	}
+	return items[start:end], total
+}
+
+// Series 表示一个 major.minor 发布系列，Versions 为去重后的版本号，最新在前。
+type Series struct {
+	Name     string
+	Versions []string
+}
+
+// GroupBySeries 按 major.minor 分组，保持输入（已按版本降序）的顺序，同一版本的多个架构只保留一次。
+func GroupBySeries(versions []models.Version) []Series {
+	var groups []Series
+	index := make(map[string]int)
+	seen := make(map[string]struct{})
+	for _, v := range versions {
+		if _, dup := seen[v.Number]; dup {
+			continue
+		}
+		seen[v.Number] = struct{}{}
+		name := goversion.Series(v.Number)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, Series{Name: name})
+		}
+		groups[i].Versions = append(groups[i].Versions, v.Number)
+	}
+	return groups
+}
+
 // LocalVersions 返回本地安装版本，标记当前版本。
 func (l *Lister) LocalVersions() ([]models.Version, error) {
 	if l.storage == nil {
@@ -52,10 +173,11 @@ func (l *Lister) LocalVersions() ([]models.Version, error) {
 
 	for i := range versions {
 		versions[i].IsCurrent = versions[i].Number == current
+		versions[i].Channel = ChannelOf(versions[i])
 	}
 
 	sort.SliceStable(versions, func(i, j int) bool {
-		return compareLocalVersions(versions[i].Number, versions[j].Number) > 0
+		return goversion.Compare(versions[i].Number, versions[j].Number) > 0
 	})
 
 	return versions, nil
@@ -94,65 +216,18 @@ func validateExecutable(goRoot string) error {
 	return nil
 }
 
-// FormatRemoteVersion 格式化远程版本输出，包含版本号与架构信息。
-func FormatRemoteVersion(v models.Version) string {
-	name := v.FullName
-	if name == "" {
-		name = "go" + v.Number
+// Channel 根据版本号判断发布渠道：stable、rc 或 beta；是否为 archived 只能由版本源判断。
+func Channel(number string) string {
+	if pre := goversion.Parse(number).Pre; pre != "" {
+		return pre
 	}
-	return fmt.Sprintf("%s (%s/%s)", name, v.OS, v.Arch)
+	return models.ChannelStable
 }
 
-// FormatLocalVersion 格式化本地版本输出，包含安装路径并标记当前版本。
-func FormatLocalVersion(v models.Version) string {
-	marker := " "
-	if v.IsCurrent {
-		marker = "*"
-	}
-	pathInfo := v.InstallPath
-	if pathInfo == "" {
-		pathInfo = "(unknown path)"
-	}
-	name := v.FullName
-	if name == "" {
-		name = "go" + v.Number
-	}
-	return fmt.Sprintf("%s %s - %s", marker, name, pathInfo)
-}
-
-func compareLocalVersions(a, b string) int {
-	ap := strings.Split(a, ".")
-	bp := strings.Split(b, ".")
-	max := len(ap)
-	if len(bp) > max {
-		max = len(bp)
-	}
-	for i := 0; i < max; i++ {
-		ai := 0
-		if i < len(ap) {
-			ai = parseInt(ap[i])
-		}
-		bi := 0
-		if i < len(bp) {
-			bi = parseInt(bp[i])
-		}
-		if ai > bi {
-			return 1
-		}
-		if ai < bi {
-			return -1
-		}
-	}
-	return 0
-}
-
-func parseInt(value string) int {
-	var n int
-	for _, ch := range value {
-		if ch < '0' || ch > '9' {
-			break
-		}
-		n = n*10 + int(ch-'0')
+// ChannelOf 返回版本记录中的发布渠道，没有记录（旧的元数据或按命名规则推导的版本）时按版本号判断。
+func ChannelOf(v models.Version) string {
+	if v.Channel != "" {
+		return v.Channel
 	}
-	return n
+	return Channel(v.Number)
 }
@@ -1,8 +1,14 @@
 package version
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/liangyou/govm/internal/remote"
 	"github.com/liangyou/govm/internal/storage"
@@ -14,7 +20,7 @@ type fakeRemoteClient struct {
 	err      error
 }
 
-func (f *fakeRemoteClient) FetchVersions() ([]models.Version, error) {
+func (f *fakeRemoteClient) FetchVersions(context.Context) ([]models.Version, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
@@ -55,7 +61,9 @@ func TestLocalVersionsMarksCurrentAndSorts(t *testing.T) {
 	store := &fakeStorage{
 		versions: []models.Version{
 			{Number: "1.18.0", InstallPath: "/tmp/go1.18.0"},
+			{Number: "1.21rc2", InstallPath: "/tmp/go1.21rc2"},
 			{Number: "1.20.2", InstallPath: "/tmp/go1.20.2"},
+			{Number: "1.21.0", InstallPath: "/tmp/go1.21.0"},
 		},
 		current: "1.18.0",
 	}
@@ -66,10 +74,15 @@ func TestLocalVersionsMarksCurrentAndSorts(t *testing.T) {
 		t.Fatalf("LocalVersions err: %v", err)
 	}
 
-	if versions[0].Number != "1.20.2" {
-		t.Fatalf("expected descending order, got %#v", versions)
+	// 与远程列表相同，预发布版本排在对应正式版之后（降序）。
+	var order []string
+	for _, v := range versions {
+		order = append(order, v.Number)
 	}
-	if !versions[1].IsCurrent {
+	if strings.Join(order, " ") != "1.21.0 1.21rc2 1.20.2 1.18.0" {
+		t.Fatalf("expected descending order, got %v", order)
+	}
+	if !versions[3].IsCurrent {
 		t.Fatalf("expected current flag on version 1.18.0: %#v", versions)
 	}
 }
@@ -90,21 +103,141 @@ func TestCurrentVersionValidatesExecutable(t *testing.T) {
 	}
 }
 
-func TestFormatRemoteVersion(t *testing.T) {
-	v := models.Version{Number: "1.22.0", FullName: "go1.22.0", OS: "linux", Arch: "amd64"}
-	out := FormatRemoteVersion(v)
-	if !strings.Contains(out, "go1.22.0") || !strings.Contains(out, "linux/amd64") {
-		t.Fatalf("remote format missing fields: %s", out)
-	}
-}
+func TestChannel(t *testing.T) {
+	t.Parallel()
 
-func TestFormatLocalVersion(t *testing.T) {
-	v := models.Version{Number: "1.20.0", InstallPath: "/tmp/go1.20.0", IsCurrent: true}
-	out := FormatLocalVersion(v)
-	if !strings.Contains(out, "*") || !strings.Contains(out, "/tmp/go1.20.0") {
-		t.Fatalf("local format missing info: %s", out)
+	for number, want := range map[string]string{"1.22.0": "stable", "1.23rc1": "rc", "1.21beta2": "beta"} {
+		if got := Channel(number); got != want {
+			t.Fatalf("Channel(%s) = %s, want %s", number, got, want)
+		}
 	}
 }
 
 var _ remote.RemoteClient = (*fakeRemoteClient)(nil)
 var _ storage.LocalStorage = (*fakeStorage)(nil)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	versions := []models.Version{{Number: "1.22.1"}, {Number: "1.22.0"}, {Number: "1.21.8"}}
+	page, total := Paginate(versions, 2, 2)
+	if total != 3 || len(page) != 1 || page[0].Number != "1.21.8" {
+		t.Fatalf("unexpected page: %#v total=%d", page, total)
+	}
+	if all, _ := Paginate(versions, 1, 0); len(all) != 3 {
+		t.Fatalf("size 0 should return all versions, got %d", len(all))
+	}
+	if out, _ := Paginate(versions, 5, 2); out != nil {
+		t.Fatalf("out of range page should be empty, got %#v", out)
+	}
+}
+
+func TestGroupBySeries(t *testing.T) {
+	t.Parallel()
+
+	versions := []models.Version{
+		{Number: "1.23rc1", Arch: "amd64"},
+		{Number: "1.22.1", Arch: "amd64"},
+		{Number: "1.22.1", Arch: "arm64"},
+		{Number: "1.22.0", Arch: "amd64"},
+		{Number: "1.9", Arch: "amd64"},
+	}
+	groups := GroupBySeries(versions)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 series, got %#v", groups)
+	}
+	if groups[0].Name != "1.23" || groups[1].Name != "1.22" || groups[2].Name != "1.9" {
+		t.Fatalf("unexpected series order: %#v", groups)
+	}
+	if len(groups[1].Versions) != 2 || groups[1].Versions[0] != "1.22.1" {
+		t.Fatalf("series should dedupe archs and keep newest first: %#v", groups[1])
+	}
+}
+
+// seedLocalVersions 在真实存储中登记 n 个版本，并把其中一个设为当前版本。
+func seedLocalVersions(tb testing.TB, n int) *storage.FileStorage {
+	tb.Helper()
+	store := storage.NewFileStorage(models.Config{RootDir: tb.TempDir()})
+	versions := make([]models.Version, n)
+	for i := range versions {
+		number := fmt.Sprintf("1.%d.%d", i/10, i%10)
+		versions[i] = models.Version{Number: number, FullName: "go" + number, InstallPath: store.GetInstallPath(number)}
+	}
+	if err := store.SaveMetadataBatch(versions); err != nil {
+		tb.Fatal(err)
+	}
+	if err := store.SetCurrentVersionMarker(versions[n/2].Number); err != nil {
+		tb.Fatal(err)
+	}
+	return store
+}
+
+func BenchmarkLocalVersions(b *testing.B) {
+	for _, n := range []int{50, 500} {
+		b.Run(fmt.Sprintf("versions=%d", n), func(b *testing.B) {
+			lister := NewLister(nil, seedLocalVersions(b, n))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := lister.LocalVersions(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestListAllMarksInstalledAndJoinsErrors(t *testing.T) {
+	t.Parallel()
+
+	remoteVersions := []models.Version{{Number: "1.22.1"}, {Number: "1.22.0"}, {Number: "1.21.8"}}
+	store := &fakeStorage{
+		versions: []models.Version{{Number: "1.22.0", InstallPath: "/opt/go1.22.0"}, {Number: "1.21.8", InstallPath: "/opt/go1.21.8"}},
+		current:  "1.21.8",
+	}
+	listing, err := NewLister(&fakeRemoteClient{versions: remoteVersions}, store).ListAll(context.Background())
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(listing.Local) != 2 || len(listing.Remote) != 3 {
+		t.Fatalf("unexpected listing: %#v", listing)
+	}
+	if r := listing.Remote; r[0].InstallPath != "" || r[1].InstallPath != "/opt/go1.22.0" || r[1].IsCurrent || !r[2].IsCurrent {
+		t.Fatalf("installed versions not marked: %#v", r)
+	}
+	if remoteVersions[1].InstallPath != "" {
+		t.Fatal("ListAll must not modify the remote client's slice")
+	}
+
+	remoteErr, localErr := errors.New("offline"), errors.New("disk")
+	listing, err = NewLister(&fakeRemoteClient{err: remoteErr}, &fakeStorage{err: localErr}).ListAll(context.Background())
+	if !errors.Is(err, remoteErr) || !errors.Is(err, localErr) || listing.Remote != nil || listing.Local != nil {
+		t.Fatalf("expected both errors, got %v, %#v", err, listing)
+	}
+	listing, err = NewLister(&fakeRemoteClient{err: remoteErr}, store).ListAll(context.Background())
+	if !errors.Is(err, remoteErr) || len(listing.Local) != 2 {
+		t.Fatalf("local versions must survive a remote failure: %v, %#v", err, listing)
+	}
+
+	// 版本源在请求被取消前一直不响应；取消 ctx 后请求本身也必须结束，而不只是不再等待。
+	started, stopped := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(stopped)
+	}))
+	defer server.Close()
+	client := remote.NewClient(remote.WithBaseURL(server.URL), remote.WithHTTPClient(server.Client()))
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if _, err := NewLister(client, store).ListAll(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelling ListAll must abort the release list request")
+	}
+}
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"sort"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ManifestDiff 描述两个版本文件清单之间的差异。
+type ManifestDiff struct {
+	From    string
+	To      string
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty 表示两个清单完全一致。
+func (d ManifestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffManifests 按路径比较两个清单，内容、权限或链接目标不同均视为变更。
+func DiffManifests(from, to models.FileManifest) ManifestDiff {
+	diff := ManifestDiff{From: from.Version, To: to.Version}
+	old := make(map[string]models.FileEntry, len(from.Files))
+	for _, entry := range from.Files {
+		old[entry.Path] = entry
+	}
+	for _, entry := range to.Files {
+		prev, ok := old[entry.Path]
+		if !ok {
+			diff.Added = append(diff.Added, entry.Path)
+			continue
+		}
+		delete(old, entry.Path)
+		if !sameEntry(prev, entry) {
+			diff.Changed = append(diff.Changed, entry.Path)
+		}
+	}
+	for p := range old {
+		diff.Removed = append(diff.Removed, p)
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+func sameEntry(a, b models.FileEntry) bool {
+	if a.Link != "" || b.Link != "" {
+		return a.Link == b.Link
+	}
+	if a.Mode != 0 && b.Mode != 0 && a.Mode != b.Mode {
+		return false
+	}
+	return a.Size == b.Size && a.SHA256 == b.SHA256
+}
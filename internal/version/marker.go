@@ -0,0 +1,90 @@
+package version
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+const (
+	// MarkerUnregistered 表示当前版本标记指向元数据中没有的版本。
+	MarkerUnregistered = "unregistered"
+	// MarkerBroken 表示当前版本标记指向的版本缺少 go 可执行文件。
+	MarkerBroken = "broken"
+
+	// RepairRegistered 表示根据磁盘上仍然完好的 GOROOT 恢复了元数据。
+	RepairRegistered = "registered"
+	// RepairCleared 表示清空了当前版本标记。
+	RepairCleared = "cleared"
+)
+
+// MarkerIssue 描述当前版本标记与元数据之间的不一致。
+type MarkerIssue struct {
+	Kind    string // MarkerUnregistered 或 MarkerBroken
+	Version string // 标记中记录的版本号
+	// GoRoot 为该版本的安装目录；MarkerUnregistered 时仅在目录中存在可用的 go 时非空，表示可以据此恢复元数据。
+	GoRoot string
+}
+
+// MarkerChecker 检查当前版本标记是否与元数据一致，并按需修复。
+type MarkerChecker struct {
+	storage storage.LocalStorage
+	now     func() time.Time
+}
+
+// NewMarkerChecker 创建 MarkerChecker。
+func NewMarkerChecker(store storage.LocalStorage) *MarkerChecker {
+	return &MarkerChecker{storage: store, now: time.Now}
+}
+
+// CheckMarker 返回当前版本标记的问题，标记为空或一致时返回 nil。
+func (c *MarkerChecker) CheckMarker() (*MarkerIssue, error) {
+	current, err := c.storage.GetCurrentVersionMarker()
+	if err != nil {
+		return nil, fmt.Errorf("marker: read current marker: %w", err)
+	}
+	if current == "" {
+		return nil, nil
+	}
+	versions, err := c.storage.LoadMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("marker: load metadata: %w", err)
+	}
+	for _, v := range versions {
+		if v.Number != current {
+			continue
+		}
+		if validateExecutable(v.InstallPath) != nil {
+			return &MarkerIssue{Kind: MarkerBroken, Version: current, GoRoot: v.InstallPath}, nil
+		}
+		return nil, nil
+	}
+	issue := &MarkerIssue{Kind: MarkerUnregistered, Version: current}
+	if root := c.storage.GetInstallPath(current); root != "" && isFile(filepath.Join(root, "bin", "go")) {
+		issue.GoRoot = root
+	}
+	return issue, nil
+}
+
+// RepairMarker 修复 issue：未登记但 GOROOT 完好且版本一致时恢复元数据，其余情况清空标记；返回 RepairRegistered 或 RepairCleared。
+func (c *MarkerChecker) RepairMarker(issue MarkerIssue) (string, error) {
+	if issue.Kind == MarkerUnregistered && issue.GoRoot != "" {
+		v, err := inspectGoRoot(issue.GoRoot)
+		if err == nil && v.Number == issue.Version {
+			// 位于 govm 版本目录中的安装由 govm 管理，不是 adopt 登记的外部版本。
+			v.External = false
+			v.InstallPath = issue.GoRoot
+			v.InstalledAt = c.now().UTC()
+			if err := c.storage.SaveMetadata(v); err != nil {
+				return "", fmt.Errorf("marker: save metadata: %w", err)
+			}
+			return RepairRegistered, nil
+		}
+	}
+	if err := c.storage.SetCurrentVersionMarker(""); err != nil {
+		return "", fmt.Errorf("marker: clear current marker: %w", err)
+	}
+	return RepairCleared, nil
+}
@@ -0,0 +1,76 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestMarkerCheckerRepairsUnregisteredVersion(t *testing.T) {
+	t.Parallel()
+
+	store := storage.NewFileStorage(models.Config{RootDir: t.TempDir()})
+	checker := NewMarkerChecker(store)
+	if issue, err := checker.CheckMarker(); err != nil || issue != nil {
+		t.Fatalf("empty marker must be healthy, got %#v (%v)", issue, err)
+	}
+
+	root := store.GetInstallPath("1.22.0")
+	fakeSystemGo(t, root, "go1.22.0")
+	if err := store.SetCurrentVersionMarker("1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+	issue, err := checker.CheckMarker()
+	if err != nil || issue == nil || issue.Kind != MarkerUnregistered || issue.GoRoot != root {
+		t.Fatalf("expected recoverable unregistered marker, got %#v (%v)", issue, err)
+	}
+	action, err := checker.RepairMarker(*issue)
+	if err != nil || action != RepairRegistered {
+		t.Fatalf("RepairMarker = %q, %v", action, err)
+	}
+	versions, err := store.LoadMetadata()
+	if err != nil || len(versions) != 1 || versions[0].Number != "1.22.0" || versions[0].External {
+		t.Fatalf("metadata not restored as a managed version: %#v (%v)", versions, err)
+	}
+	if issue, err := checker.CheckMarker(); err != nil || issue != nil {
+		t.Fatalf("repaired marker must be healthy, got %#v (%v)", issue, err)
+	}
+}
+
+func TestMarkerCheckerClearsBrokenMarker(t *testing.T) {
+	t.Parallel()
+
+	store := storage.NewFileStorage(models.Config{RootDir: t.TempDir()})
+	checker := NewMarkerChecker(store)
+	root := store.GetInstallPath("1.21.0")
+	if err := store.SaveMetadata(models.Version{Number: "1.21.0", InstallPath: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetCurrentVersionMarker("1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	issue, err := checker.CheckMarker()
+	if err != nil || issue == nil || issue.Kind != MarkerBroken {
+		t.Fatalf("expected broken marker, got %#v (%v)", issue, err)
+	}
+	if action, err := checker.RepairMarker(*issue); err != nil || action != RepairCleared {
+		t.Fatalf("RepairMarker = %q, %v", action, err)
+	}
+	if current, _ := store.GetCurrentVersionMarker(); current != "" {
+		t.Fatalf("marker not cleared: %q", current)
+	}
+
+	// 目录中的版本与标记不符时不能据此恢复元数据。
+	fakeSystemGo(t, store.GetInstallPath("1.20.0"), "go1.19.0")
+	if err := store.SetCurrentVersionMarker("1.20.0"); err != nil {
+		t.Fatal(err)
+	}
+	issue, err = checker.CheckMarker()
+	if err != nil || issue == nil || issue.Kind != MarkerUnregistered {
+		t.Fatalf("expected unregistered marker, got %#v (%v)", issue, err)
+	}
+	if action, err := checker.RepairMarker(*issue); err != nil || action != RepairCleared {
+		t.Fatalf("mismatched GOROOT must only clear the marker, got %q, %v", action, err)
+	}
+}
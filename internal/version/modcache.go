@@ -0,0 +1,122 @@
+package version
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// toolchainModule 为 GOTOOLCHAIN 自动下载的工具链模块路径，版本形如 v0.0.1-go1.22.0.linux-amd64。
+const toolchainModule = "golang.org/toolchain"
+
+// ToolchainModules 返回 GOMODCACHE 中确定属于 number 的工具链模块（解压目录与 cache/download 中的文件）及其总大小。
+// GOCACHE 与普通依赖模块由各版本共享，无法归属到单个版本，因此不在其列。
+func ToolchainModules(modCache, number string) (paths []string, size int64, err error) {
+	if modCache == "" {
+		return nil, 0, nil
+	}
+	prefix := "v0.0.1-go" + number + "."
+	// 解压目录为 golang.org/toolchain@<版本>，下载文件为 cache/download/golang.org/toolchain/@v/<版本>.zip 等。
+	dirs := []struct {
+		dir, prefix string
+	}{
+		{filepath.Join(modCache, filepath.FromSlash(path.Dir(toolchainModule))), path.Base(toolchainModule) + "@" + prefix},
+		{filepath.Join(modCache, "cache", "download", filepath.FromSlash(toolchainModule), "@v"), prefix},
+	}
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d.dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, 0, fmt.Errorf("version: read module cache: %w", err)
+		}
+		for _, e := range entries {
+			rest, ok := strings.CutPrefix(e.Name(), d.prefix)
+			// 余下部分必须以 <os>-<arch> 开头，避免 go1.20 误匹配 go1.20.1 的模块。
+			if platform, _, _ := strings.Cut(rest, "."); !ok || !strings.Contains(platform, "-") {
+				continue
+			}
+			p := filepath.Join(d.dir, e.Name())
+			paths = append(paths, p)
+			size += treeSize(p)
+		}
+	}
+	return paths, size, nil
+}
+
+// ToolchainRoots 返回 modCache 中为本机平台解压的工具链目录，每个目录本身就是完整的 GOROOT，按版本从新到旧排列。
+func ToolchainRoots(modCache string) ([]string, error) {
+	if modCache == "" {
+		return nil, nil
+	}
+	dir := filepath.Join(modCache, filepath.FromSlash(path.Dir(toolchainModule)))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("version: read module cache: %w", err)
+	}
+	prefix := path.Base(toolchainModule) + "@v0.0.1-go"
+	suffix := "." + runtime.GOOS + "-" + runtime.GOARCH
+	type toolchain struct{ number, root string }
+	var found []toolchain
+	for _, e := range entries {
+		rest, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok || !e.IsDir() || !strings.HasSuffix(rest, suffix) {
+			continue
+		}
+		found = append(found, toolchain{strings.TrimSuffix(rest, suffix), filepath.Join(dir, e.Name())})
+	}
+	sort.Slice(found, func(i, j int) bool { return goversion.Compare(found[i].number, found[j].number) > 0 })
+	roots := make([]string, 0, len(found))
+	for _, t := range found {
+		roots = append(roots, t.root)
+	}
+	return roots, nil
+}
+
+// PurgeToolchainModules 删除 ToolchainModules 找到的路径并返回释放的空间；模块缓存是只读的，删除前先恢复写权限。
+func PurgeToolchainModules(modCache, number string) (removed []string, freed int64, err error) {
+	paths, _, err := ToolchainModules(modCache, number)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, p := range paths {
+		size := treeSize(p)
+		filepath.WalkDir(p, func(sub string, d fs.DirEntry, err error) error {
+			if err == nil && d.IsDir() {
+				os.Chmod(sub, 0o755)
+			}
+			return nil
+		})
+		if err := os.RemoveAll(p); err != nil {
+			return removed, freed, fmt.Errorf("version: purge module cache: %w", err)
+		}
+		removed = append(removed, p)
+		freed += size
+	}
+	return removed, freed, nil
+}
+
+// treeSize 统计路径下普通文件的总大小，读取失败的条目被忽略。
+func treeSize(root string) int64 {
+	var total int64
+	filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
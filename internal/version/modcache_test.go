@@ -0,0 +1,67 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPurgeToolchainModules(t *testing.T) {
+	t.Parallel()
+
+	modCache := t.TempDir()
+	extracted := filepath.Join(modCache, "golang.org")
+	download := filepath.Join(modCache, "cache", "download", "golang.org", "toolchain", "@v")
+	write := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(extracted, "toolchain@v0.0.1-go1.20.linux-amd64", "bin", "go"), "12345")
+	write(filepath.Join(download, "v0.0.1-go1.20.linux-amd64.zip"), "123")
+	write(filepath.Join(download, "v0.0.1-go1.20.linux-amd64.info"), "1")
+	// 1.20.1 与 1.20 前缀相同，不能被一并删除。
+	write(filepath.Join(extracted, "toolchain@v0.0.1-go1.20.1.linux-amd64", "bin", "go"), "keep")
+	write(filepath.Join(download, "v0.0.1-go1.20.1.linux-amd64.zip"), "keep")
+	// 模块缓存中的解压目录是只读的。
+	if err := os.Chmod(filepath.Join(extracted, "toolchain@v0.0.1-go1.20.linux-amd64", "bin"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, size, err := ToolchainModules(modCache, "1.20")
+	if err != nil {
+		t.Fatalf("ToolchainModules: %v", err)
+	}
+	if len(paths) != 3 || size != 9 {
+		t.Fatalf("unexpected match: %v size=%d", paths, size)
+	}
+
+	removed, freed, err := PurgeToolchainModules(modCache, "1.20")
+	if err != nil {
+		t.Fatalf("PurgeToolchainModules: %v", err)
+	}
+	if len(removed) != 3 || freed != 9 {
+		t.Fatalf("unexpected purge: %v freed=%d", removed, freed)
+	}
+	for _, path := range removed {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("%s should be removed: %v", path, err)
+		}
+	}
+	if paths, _, _ := ToolchainModules(modCache, "1.20.1"); len(paths) != 2 {
+		t.Fatalf("go1.20.1 modules must be kept: %v", paths)
+	}
+}
+
+func TestToolchainModulesMissingCache(t *testing.T) {
+	t.Parallel()
+
+	paths, size, err := ToolchainModules(filepath.Join(t.TempDir(), "missing"), "1.22.0")
+	if err != nil || len(paths) != 0 || size != 0 {
+		t.Fatalf("missing cache should be empty: %v %d %v", paths, size, err)
+	}
+}
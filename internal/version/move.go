@@ -0,0 +1,134 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+// partialSuffix 为跨文件系统复制时暂存副本的后缀，复制完成后才改名为目标路径。
+const partialSuffix = ".govm-partial"
+
+// moveDir 把 src 改名为 dst。两者不在同一文件系统时 rename 返回 EXDEV，例如 overlayfs 下层中的旧安装、
+// 经符号链接或绑定挂载指向其他挂载点的目录，此时改为复制后删除 src。
+func moveDir(src, dst string) error {
+	return moveTree(os.Rename, src, dst)
+}
+
+// moveTree 使用 rename 移动目录树，EXDEV 时在 dst 旁复制出完整副本、改名就位后再删除 src，
+// 中途失败时 src 保持完整，不会留下只复制了一半的 dst。
+func moveTree(rename func(string, string) error, src, dst string) error {
+	err := rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	staging := dst + partialSuffix
+	if err := storage.RemoveTree(staging); err != nil {
+		return err
+	}
+	if err := copyTree(src, staging); err != nil {
+		storage.RemoveTree(staging)
+		return fmt.Errorf("copy %s across file systems: %w", src, err)
+	}
+	if err := os.Rename(staging, dst); err != nil {
+		storage.RemoveTree(staging)
+		return err
+	}
+	return storage.RemoveTree(src)
+}
+
+// copyTree 把 src 复制为 dst，保留权限位、修改时间与符号链接；目录的权限在其内容复制完成后才设置，
+// 只读安装同样可以复制。以 root 运行时同时保留属主。
+func copyTree(src, dst string) error {
+	type dirMode struct {
+		path string
+		info fs.FileInfo
+	}
+	var dirs []dirMode
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if err := os.Mkdir(target, 0o700); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirMode{target, info})
+			return nil
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+			return copyOwner(target, info)
+		case d.Type().IsRegular():
+			if err := copyFile(path, target, info); err != nil {
+				return err
+			}
+			if err := copyOwner(target, info); err != nil {
+				return err
+			}
+			return os.Chtimes(target, info.ModTime(), info.ModTime())
+		default:
+			return fmt.Errorf("%s: unsupported file type %s", path, info.Mode().Type())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	// 逆序处理，子目录先于父目录去掉写权限。
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dir := dirs[i]
+		if err := copyOwner(dir.path, dir.info); err != nil {
+			return err
+		}
+		if err := os.Chmod(dir.path, dir.info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Chtimes(dir.path, dir.info.ModTime(), dir.info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile 以 src 的权限位创建 dst 并复制内容。
+func copyFile(src, dst string, info fs.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// OpenFile 的权限受 umask 影响，显式设置一次。
+	return os.Chmod(dst, info.Mode().Perm())
+}
@@ -0,0 +1,72 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+)
+
+func TestMoveTreeCopiesAcrossFileSystems(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "install-1", "root")
+	if err := os.MkdirAll(filepath.Join(src, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "bin", "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("bin/go", filepath.Join(src, "go")); err != nil {
+		t.Fatal(err)
+	}
+	// 只读安装的目录同样要能复制。
+	if err := os.Chmod(filepath.Join(src, "bin"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+
+	crossDevice := func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	dst := filepath.Join(dir, "go1.22.0")
+	if err := moveTree(crossDevice, src, dst); err != nil {
+		t.Fatalf("moveTree: %v", err)
+	}
+
+	if _, err := os.Lstat(src); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("source must be removed after copying, got %v", err)
+	}
+	if _, err := os.Lstat(dst + partialSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("staging copy left behind: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "bin", "go"))
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("bin/go = %v, %v", info, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "bin")); err != nil || info.Mode().Perm() != 0o555 {
+		t.Fatalf("bin dir mode = %v, %v", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "go")); err != nil || link != "bin/go" {
+		t.Fatalf("symlink = %q, %v", link, err)
+	}
+	if err := storage.RemoveTree(dst); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveTreeKeepsOtherRenameErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	denied := func(string, string) error { return os.ErrPermission }
+	if err := moveTree(denied, dir, filepath.Join(dir, "dst")); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("moveTree = %v, want the rename error", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("source must stay in place: %v", err)
+	}
+}
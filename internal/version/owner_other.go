@@ -0,0 +1,11 @@
+//go:build !unix
+
+package version
+
+import "io/fs"
+
+// copyOwner 在没有 Unix 属主的平台上不做任何事。
+func copyOwner(string, fs.FileInfo) error { return nil }
+
+// sameOwner 在没有 Unix 属主的平台上总是成立。
+func sameOwner(fs.FileInfo, fs.FileInfo) bool { return true }
@@ -0,0 +1,25 @@
+//go:build unix
+
+package version
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// copyOwner 以 root 运行时把 path 的属主设为 info 记录的属主，例如配置了 owner 的安装；其他用户无权修改属主，直接跳过。
+func copyOwner(path string, info fs.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || os.Geteuid() != 0 {
+		return nil
+	}
+	return os.Lchown(path, int(st.Uid), int(st.Gid))
+}
+
+// sameOwner 报告两个文件的属主与属组是否相同，硬链接会让二者共用原件的属主。
+func sameOwner(a, b fs.FileInfo) bool {
+	sa, okA := a.Sys().(*syscall.Stat_t)
+	sb, okB := b.Sys().(*syscall.Stat_t)
+	return okA && okB && sa.Uid == sb.Uid && sa.Gid == sb.Gid
+}
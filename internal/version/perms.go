@@ -0,0 +1,110 @@
+package version
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// Permissions 描述安装目录树的权限与属主，零值表示沿用归档权限（受 umask 影响）且不修改属主。
+type Permissions struct {
+	DirMode  fs.FileMode
+	FileMode fs.FileMode
+	// Chown 为 true 时把整棵目录树的属主改为 UID/GID，需要以 root 运行。
+	Chown bool
+	UID   int
+	GID   int
+	// ReadOnly 为 true 时安装就位后去掉整棵目录树的写权限，防止误改标准库；卸载与重新安装时先临时恢复目录的写权限。
+	ReadOnly bool
+}
+
+// WithInstallPermissions 指定安装目录树的权限与属主，在目录改名就位之前应用。
+func WithInstallPermissions(p Permissions) InstallerOption {
+	return func(i *Installer) {
+		i.perms = p
+	}
+}
+
+func (p Permissions) isZero() bool {
+	return p.DirMode == 0 && p.FileMode == 0 && !p.Chown
+}
+
+// fileMode 返回普通文件的目标权限：可执行文件在 FileMode 基础上为每个可读的类别补齐执行位。
+func (p Permissions) fileMode(orig fs.FileMode) fs.FileMode {
+	if p.FileMode == 0 {
+		return orig
+	}
+	mode := p.FileMode
+	if orig&0o111 != 0 {
+		mode |= (mode & 0o444) >> 2
+	}
+	return mode
+}
+
+// applyPermissions 按 p 调整 root 下的权限与属主，并同步更新清单中记录的权限位。
+func applyPermissions(root string, p Permissions, files []models.FileEntry) error {
+	if p.isZero() {
+		return nil
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir() && p.DirMode != 0:
+			if err := os.Chmod(path, p.DirMode); err != nil {
+				return err
+			}
+		case d.Type().IsRegular() && p.FileMode != 0:
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if err := os.Chmod(path, p.fileMode(info.Mode().Perm())); err != nil {
+				return err
+			}
+		}
+		if p.Chown {
+			return os.Lchown(path, p.UID, p.GID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("installer: apply permissions: %w", err)
+	}
+	for i := range files {
+		if files[i].Link == "" {
+			files[i].Mode = p.fileMode(files[i].Mode)
+		}
+	}
+	return nil
+}
+
+// freeze 去掉 root 下目录与普通文件的全部写权限，并同步更新清单中记录的权限位；符号链接不受影响。
+func freeze(root string, files []models.FileEntry) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.Chmod(path, info.Mode().Perm()&^0o222)
+	})
+	if err != nil {
+		return fmt.Errorf("installer: make %s read-only: %w", root, err)
+	}
+	for i := range files {
+		if files[i].Link == "" {
+			files[i].Mode &^= 0o222
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PinFile 为项目目录中记录固定版本的文件名，由 `govm use --local` 写入。
+const PinFile = ".govm-version"
+
+// FindPin 从 dir 向上查找 PinFile 并返回其中的版本号及文件路径，没有找到时 number 为空。
+func FindPin(dir string) (number, path string, err error) {
+	for current := dir; ; {
+		candidate := filepath.Join(current, PinFile)
+		if isFile(candidate) {
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				return "", "", fmt.Errorf("version: read %s: %w", candidate, err)
+			}
+			number = strings.TrimPrefix(strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0]), "go")
+			if number == "" {
+				return "", "", fmt.Errorf("version: %s is empty", candidate)
+			}
+			return number, candidate, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", "", nil
+		}
+		current = parent
+	}
+}
+
+// WritePin 在 dir 中写入 PinFile 并返回其路径。
+func WritePin(dir, number string) (string, error) {
+	path := filepath.Join(dir, PinFile)
+	if err := os.WriteFile(path, []byte(number+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("version: write %s: %w", PinFile, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,34 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindPinWalksUp(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "cmd", "tool")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if number, _, err := FindPin(nested); err != nil || number != "" {
+		t.Fatalf("no pin expected, got %q, %v", number, err)
+	}
+	path, err := WritePin(root, "1.21.8")
+	if err != nil {
+		t.Fatalf("WritePin: %v", err)
+	}
+	number, found, err := FindPin(nested)
+	if err != nil || number != "1.21.8" || found != path {
+		t.Fatalf("FindPin = %q, %q, %v", number, found, err)
+	}
+	if err := os.WriteFile(path, []byte("go1.22.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if number, _, _ := FindPin(root); number != "1.22.0" {
+		t.Fatalf("go prefix must be stripped, got %q", number)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build linux
+
+package version
+
+import (
+	"os"
+	"syscall"
+)
+
+// preallocateMin 以下的文件直接写入，为小文件多做一次系统调用得不偿失。
+const preallocateMin = 256 << 10
+
+// preallocate 按归档记录的大小一次性分配文件空间，减少大文件写入时的碎片；
+// 文件系统不支持 fallocate 时静默跳过，写入仍会正常扩展文件。
+func preallocate(f *os.File, size int64) {
+	if size < preallocateMin {
+		return
+	}
+	_ = syscall.Fallocate(int(f.Fd()), 0, 0, size)
+}
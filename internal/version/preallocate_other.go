@@ -0,0 +1,8 @@
+//go:build !linux
+
+package version
+
+import "os"
+
+// preallocate 在没有 fallocate 的平台上不做任何事；只截断到目标大小会得到稀疏文件，并不能减少碎片。
+func preallocate(*os.File, int64) {}
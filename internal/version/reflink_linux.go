@@ -0,0 +1,37 @@
+//go:build linux
+
+package version
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// ficlone 为 FICLONE ioctl（_IOW(0x94, 9, int)），btrfs、xfs 等文件系统据此让两个文件共享数据块。
+const ficlone = 0x40049409
+
+// reflinkFile 创建与 src 共享数据块的新文件 dst；文件系统不支持时返回 errReflinkUnsupported。
+func reflinkFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, out.Fd(), ficlone, in.Fd())
+	if err := out.Close(); err != nil && errno == 0 {
+		return err
+	}
+	switch {
+	case errno == 0:
+		return nil
+	case errors.Is(errno, syscall.EOPNOTSUPP), errors.Is(errno, syscall.ENOTTY), errors.Is(errno, syscall.EINVAL), errors.Is(errno, syscall.EXDEV):
+		return errReflinkUnsupported
+	default:
+		return errno
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !linux
+
+package version
+
+// reflinkFile 在 Linux 之外尚未实现，auto 模式下改用硬链接。
+func reflinkFile(string, string) error { return errReflinkUnsupported }
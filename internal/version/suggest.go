@@ -0,0 +1,120 @@
+package version
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/liangyou/govm/pkg/goversion"
+)
+
+// maxSuggestDistance 为跨系列建议允许的最大编辑距离，更远的版本号多半不是拼写错误。
+const maxSuggestDistance = 2
+
+// Suggest 返回 candidates 中与 target 最接近的至多 n 个版本号，用于 "did you mean" 提示。
+// 存在同一 major.minor 系列的版本时只在系列内按补丁号远近挑选，否则只保留编辑距离（不超过 2）最小的版本。
+func Suggest(target string, candidates []string, n int) []string {
+	type scored struct {
+		number   string
+		series   bool
+		distance int
+	}
+	targetSeries, targetPatch := splitSeries(target)
+	seen := make(map[string]bool, len(candidates))
+	var matches []scored
+	for _, c := range candidates {
+		if c == "" || c == target || seen[c] {
+			continue
+		}
+		seen[c] = true
+		if series, patch := splitSeries(c); series == targetSeries && targetSeries != "" {
+			matches = append(matches, scored{number: c, series: true, distance: abs(patch - targetPatch)})
+			continue
+		}
+		if d := editDistance(target, c); d <= maxSuggestDistance {
+			matches = append(matches, scored{number: c, distance: d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.series != b.series {
+			return a.series
+		}
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return goversion.Compare(a.number, b.number) > 0
+	})
+	// 排序后同系列的版本在前；只保留与第一名同一档次的结果，避免混入明显无关的版本。
+	for i := range matches {
+		if matches[i].series != matches[0].series || (!matches[0].series && matches[i].distance != matches[0].distance) {
+			matches = matches[:i]
+			break
+		}
+	}
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.number
+	}
+	// 按版本号升序展示，读起来更自然。
+	sort.Slice(out, func(i, j int) bool { return goversion.Compare(out[i], out[j]) < 0 })
+	return out
+}
+
+// splitSeries 把 1.22.3 拆成系列 1.22 与补丁号 3；1.22rc1 等预发布版本的补丁号为 0。
+func splitSeries(number string) (series string, patch int) {
+	major, rest, ok := strings.Cut(number, ".")
+	if !ok || major == "" {
+		return "", 0
+	}
+	minor, patchPart, _ := strings.Cut(rest, ".")
+	end := 0
+	for end < len(minor) && minor[end] >= '0' && minor[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return "", 0
+	}
+	return major + "." + minor[:end], parseInt(patchPart)
+}
+
+// editDistance 计算两个字符串的 Levenshtein 距离。
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func parseInt(value string) int {
+	var n int
+	for _, ch := range value {
+		if ch < '0' || ch > '9' {
+			break
+		}
+		n = n*10 + int(ch-'0')
+	}
+	return n
+}
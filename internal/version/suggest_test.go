@@ -0,0 +1,28 @@
+package version
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	remote := []string{"1.22.4", "1.22.3", "1.22.2", "1.22.1", "1.22.0", "1.21.9", "1.21.0", "1.20.14", "1.22rc1"}
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{target: "1.20.9", want: []string{"1.20.14"}},
+		{target: "1.21.1", want: []string{"1.21.0", "1.21.9"}},
+		{target: "1.2.0", want: []string{"1.21.0", "1.22.0"}},
+		{target: "1.222.1", want: []string{"1.22.1"}},
+		{target: "2.0.0", want: []string{}},
+		{target: "1.22.9", want: []string{"1.22.2", "1.22.3", "1.22.4"}},
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.target, remote, 3); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
@@ -1,29 +1,74 @@
 package version
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/liangyou/govm/internal/env"
 	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
 
+// UsageRecorder 记录版本激活统计。
+type UsageRecorder interface {
+	RecordUsage(version string, at time.Time) error
+}
+
+// ShadowFunc 在切换后发现 PATH 中有其他 go 排在 govm 版本之前时被调用。
+type ShadowFunc func(version string, check env.PathCheck)
+
 // Switcher 负责切换当前使用的 Go 版本。
 type Switcher struct {
-	storage storage.LocalStorage
-	env     env.EnvManager
+	storage  storage.LocalStorage
+	env      env.EnvManager
+	usage    UsageRecorder
+	onShadow ShadowFunc
+	now      func() time.Time
+	getenv   func(string) string
+}
+
+// SwitcherOption 配置 Switcher。
+type SwitcherOption func(*Switcher)
+
+// WithUsageRecorder 启用本地使用统计，每次激活版本时累加计数。
+func WithUsageRecorder(r UsageRecorder) SwitcherOption {
+	return func(s *Switcher) {
+		s.usage = r
+	}
+}
+
+// WithShadowReporter 在切换后检查 PATH，发现被系统 go 遮蔽时回调 fn。
+func WithShadowReporter(fn ShadowFunc) SwitcherOption {
+	return func(s *Switcher) {
+		s.onShadow = fn
+	}
 }
 
 // NewSwitcher 创建 Switcher。
-func NewSwitcher(store storage.LocalStorage, envManager env.EnvManager) *Switcher {
-	return &Switcher{storage: store, env: envManager}
+func NewSwitcher(store storage.LocalStorage, envManager env.EnvManager, opts ...SwitcherOption) *Switcher {
+	s := &Switcher{storage: store, env: envManager, now: time.Now, getenv: os.Getenv}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // UseVersion 将指定版本设置为当前版本。
 func (s *Switcher) UseVersion(version string) error {
+	return s.activate(version, true)
+}
+
+// Activate 将指定版本设置为当前版本但不修改 shell 配置文件，适用于 CI/容器环境。
+func (s *Switcher) Activate(version string) error {
+	return s.activate(version, false)
+}
+
+func (s *Switcher) activate(version string, configureShell bool) error {
 	version = strings.TrimSpace(version)
 	if version == "" {
 		return fmt.Errorf("switcher: version is required")
@@ -56,21 +101,62 @@ func (s *Switcher) UseVersion(version string) error {
 		return err
 	}
 
-	if err := s.env.ConfigureEnvironment(target.InstallPath); err != nil {
-		return fmt.Errorf("switcher: configure environment: %w", err)
+	if configureShell {
+		if err := s.env.ConfigureEnvironment(target.InstallPath); err != nil {
+			return fmt.Errorf("switcher: configure environment: %w", err)
+		}
 	}
 
 	if err := s.env.SetCurrentVersion(target.Number); err != nil {
 		return fmt.Errorf("switcher: set current version: %w", err)
 	}
 
+	// 当前版本标记才是权威来源；共享安装模式下普通用户对元数据只读，此时跳过同步。
+	if err := s.syncCurrentFlags(versions, target.Number); err != nil && !errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("switcher: update metadata: %w", err)
+	}
+
+	// 使用统计只是辅助信息，写入失败不影响切换结果。
+	if s.usage != nil {
+		_ = s.usage.RecordUsage(target.Number, s.now())
+	}
+
+	if s.onShadow != nil {
+		check := env.CheckPath(s.getenv("PATH"), env.ManagedBy(filepath.Dir(target.InstallPath)))
+		if check.Shadowed() {
+			s.onShadow(target.Number, check)
+		}
+	}
+
+	return nil
+}
+
+// batchSaver 由支持一次写入多条元数据的存储实现，避免切换时逐个版本重写整个文件。
+type batchSaver interface {
+	SaveMetadataBatch(versions []models.Version) error
+}
+
+// syncCurrentFlags 把元数据中的 IsCurrent 与新的当前版本对齐，只写入发生变化的条目。
+func (s *Switcher) syncCurrentFlags(versions []models.Version, current string) error {
+	var changed []models.Version
 	for _, ver := range versions {
-		ver.IsCurrent = ver.Number == target.Number
+		isCurrent := ver.Number == current
+		if ver.IsCurrent != isCurrent {
+			ver.IsCurrent = isCurrent
+			changed = append(changed, ver)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if b, ok := s.storage.(batchSaver); ok {
+		return b.SaveMetadataBatch(changed)
+	}
+	for _, ver := range changed {
 		if err := s.storage.SaveMetadata(ver); err != nil {
-			return fmt.Errorf("switcher: update metadata: %w", err)
+			return err
 		}
 	}
-
 	return nil
 }
 
@@ -1,10 +1,13 @@
 package version
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/liangyou/govm/internal/env"
 	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
@@ -131,3 +134,153 @@ func TestSwitcherFailsWhenGoBinaryMissing(t *testing.T) {
 		t.Fatal("expected missing binary error")
 	}
 }
+
+type recordingUsage struct {
+	versions []string
+}
+
+func (r *recordingUsage) RecordUsage(version string, at time.Time) error {
+	r.versions = append(r.versions, version)
+	return nil
+}
+
+func TestSwitcherRecordsUsage(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+	installPath := store.GetInstallPath("1.22.0")
+	if err := os.MkdirAll(filepath.Join(installPath, "bin"), 0o755); err != nil {
+		t.Fatalf("create bin: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "bin", "go"), []byte("#!/bin/sh"), 0o755); err != nil {
+		t.Fatalf("write go binary: %v", err)
+	}
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0", InstallPath: installPath}); err != nil {
+		t.Fatalf("SaveMetadata: %v", err)
+	}
+
+	usage := &recordingUsage{}
+	switcher := NewSwitcher(store, &fakeEnvManager{}, WithUsageRecorder(usage))
+	if err := switcher.UseVersion("1.22.0"); err != nil {
+		t.Fatalf("UseVersion failed: %v", err)
+	}
+	if err := switcher.Activate("1.22.0"); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	if len(usage.versions) != 2 {
+		t.Fatalf("expected two recorded activations, got %v", usage.versions)
+	}
+}
+
+func TestSwitcherReportsPathShadowing(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+	installPath := store.GetInstallPath("1.22.0")
+	for _, dir := range []string{filepath.Join(installPath, "bin"), filepath.Join(root, "usr", "bin")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go"), []byte("#!/bin/sh"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SaveMetadata(models.Version{Number: "1.22.0", InstallPath: installPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	var shadows []string
+	switcher := NewSwitcher(store, &fakeEnvManager{}, WithShadowReporter(func(ver string, check env.PathCheck) {
+		shadows = append(shadows, check.Shadows...)
+	}))
+	switcher.getenv = func(string) string {
+		return filepath.Join(root, "usr", "bin") + string(os.PathListSeparator) + filepath.Join(installPath, "bin")
+	}
+	if err := switcher.UseVersion("1.22.0"); err != nil {
+		t.Fatalf("UseVersion failed: %v", err)
+	}
+	if len(shadows) != 1 || shadows[0] != filepath.Join(root, "usr", "bin", "go") {
+		t.Fatalf("expected shadowing report, got %v", shadows)
+	}
+}
+
+// countingStore 统计元数据写入次数，用于确认切换版本只重写一次元数据文件。
+type countingStore struct {
+	*storage.FileStorage
+	writes int
+}
+
+func (c *countingStore) SaveMetadata(v models.Version) error {
+	c.writes++
+	return c.FileStorage.SaveMetadata(v)
+}
+
+func (c *countingStore) SaveMetadataBatch(versions []models.Version) error {
+	c.writes++
+	return c.FileStorage.SaveMetadataBatch(versions)
+}
+
+// seedSwitchable 登记 n 个带 bin/go 的版本，全部标记为当前版本以制造最多的待同步条目。
+func seedSwitchable(tb testing.TB, n int) *storage.FileStorage {
+	tb.Helper()
+	store := storage.NewFileStorage(models.Config{RootDir: tb.TempDir()})
+	versions := make([]models.Version, n)
+	for i := range versions {
+		number := fmt.Sprintf("1.%d.%d", i/10, i%10)
+		versions[i] = models.Version{Number: number, InstallPath: store.GetInstallPath(number), IsCurrent: true}
+	}
+	if err := store.SaveMetadataBatch(versions); err != nil {
+		tb.Fatal(err)
+	}
+	bin := filepath.Join(versions[0].InstallPath, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	return store
+}
+
+func TestSwitcherWritesMetadataOnce(t *testing.T) {
+	t.Parallel()
+
+	store := &countingStore{FileStorage: seedSwitchable(t, 200)}
+	switcher := NewSwitcher(store, &fakeEnvManager{})
+	if err := switcher.Activate("1.0.0"); err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	if store.writes != 1 {
+		t.Fatalf("metadata written %d times, want 1", store.writes)
+	}
+	meta, err := store.LoadMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range meta {
+		if v.IsCurrent != (v.Number == "1.0.0") {
+			t.Fatalf("IsCurrent not synced for %s", v.Number)
+		}
+	}
+
+	store.writes = 0
+	if err := switcher.Activate("1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if store.writes != 0 {
+		t.Fatalf("re-activating the current version wrote metadata %d times", store.writes)
+	}
+}
+
+func BenchmarkSwitcherActivate(b *testing.B) {
+	store := seedSwitchable(b, 500)
+	switcher := NewSwitcher(store, &fakeEnvManager{})
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := switcher.Activate("1.0.0"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
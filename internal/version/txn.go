@@ -0,0 +1,219 @@
+package version
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// RecoveryFunc 接收一次被中断安装的清理结果，err 为 nil 表示已回滚。
+type RecoveryFunc func(version string, err error)
+
+// WithRecoveryReporter 指定首次安装前清理中断安装时的回调。
+func WithRecoveryReporter(fn RecoveryFunc) InstallerOption {
+	return func(i *Installer) {
+		i.recoveryReporter = fn
+	}
+}
+
+const (
+	journalPrefix = ".install-go"
+	journalSuffix = ".journal"
+	// previousDirName 为重装时旧安装目录在暂存目录中的备份名。
+	previousDirName = "previous"
+)
+
+// installJournal 记录一次进行中的安装，进程在提交前退出时下次运行据此清理残留。
+type installJournal struct {
+	Version     string    `json:"version"`
+	TempDir     string    `json:"tempDir"`
+	InstallPath string    `json:"installPath"`
+	InstalledAt time.Time `json:"installedAt"`
+	// Previous 表示开始安装时安装目录已存在，恢复时需要还原备份而不是直接删除。
+	Previous bool `json:"previous,omitempty"`
+}
+
+func journalPath(dir, ver string) string {
+	return filepath.Join(dir, journalPrefix+ver+journalSuffix)
+}
+
+func writeJournal(dir string, j installJournal) (string, error) {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return "", err
+	}
+	path := journalPath(dir, j.Version)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("installer: write journal: %w", err)
+	}
+	return path, nil
+}
+
+// installTxn 按执行顺序登记每一步的补偿动作，失败时逆序回滚。
+type installTxn struct {
+	undo []func() error
+}
+
+func (t *installTxn) onRollback(fn func() error) {
+	t.undo = append(t.undo, fn)
+}
+
+func (t *installTxn) rollback() error {
+	var errs []error
+	for j := len(t.undo) - 1; j >= 0; j-- {
+		if err := t.undo[j](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.undo = nil
+	return errors.Join(errs...)
+}
+
+// versionsDir 返回安装目录的父目录，日志与暂存目录都位于其中。
+func (i *Installer) versionsDir() string {
+	return filepath.Dir(i.storage.GetInstallPath(""))
+}
+
+// recoverOnce 在进程内首次安装前执行一次 Recover，避免把同一进程中并发安装的日志当作残留。
+func (i *Installer) recoverOnce() {
+	i.recovered.Do(func() {
+		results, err := i.Recover()
+		if i.recoveryReporter == nil {
+			return
+		}
+		for _, ver := range results {
+			i.recoveryReporter(ver, nil)
+		}
+		if err != nil {
+			i.recoveryReporter("", err)
+		}
+	})
+}
+
+// Recover 清理上次进程在提交前中断的安装：元数据已写入的视为完成，否则删除半成品目录、暂存目录与清单。
+// 返回被回滚的版本号。
+func (i *Installer) Recover() ([]string, error) {
+	if i.storage == nil {
+		return nil, nil
+	}
+	dir := i.versionsDir()
+	paths, err := filepath.Glob(filepath.Join(dir, journalPrefix+"*"+journalSuffix))
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	versions, err := i.storage.LoadMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("installer: load metadata: %w", err)
+	}
+
+	var rolledBack []string
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var j installJournal
+		if err := json.Unmarshal(data, &j); err != nil || j.Version == "" {
+			errs = append(errs, os.Remove(path))
+			continue
+		}
+		if committed(versions, j) {
+			if j.TempDir != "" && filepath.Dir(j.TempDir) == filepath.Clean(dir) {
+				errs = append(errs, storage.RemoveTree(j.TempDir))
+			}
+			errs = append(errs, os.Remove(path))
+			continue
+		}
+		if err := i.discard(dir, versions, j); err != nil {
+			errs = append(errs, fmt.Errorf("installer: roll back go%s: %w", j.Version, err))
+			continue
+		}
+		errs = append(errs, os.Remove(path))
+		rolledBack = append(rolledBack, j.Version)
+	}
+	return rolledBack, errors.Join(errs...)
+}
+
+// committed 判断日志对应的安装是否已写入元数据。
+func committed(versions []models.Version, j installJournal) bool {
+	for _, v := range versions {
+		if v.Number == j.Version && v.InstallPath == j.InstallPath && v.InstalledAt.Equal(j.InstalledAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// discard 删除未提交安装留下的目录与清单，只处理位于 dir 下的路径。
+func (i *Installer) discard(dir string, versions []models.Version, j installJournal) error {
+	clean := filepath.Clean(dir)
+	if j.TempDir != "" && (filepath.Dir(j.TempDir) != clean || !strings.HasPrefix(filepath.Base(j.TempDir), "install-")) {
+		return fmt.Errorf("refusing to remove unexpected temp dir %s", j.TempDir)
+	}
+	if j.InstallPath != "" && (filepath.Dir(j.InstallPath) != clean || filepath.Base(j.InstallPath) != "go"+j.Version) {
+		return fmt.Errorf("refusing to remove unexpected install path %s", j.InstallPath)
+	}
+	if err := i.restoreInstallDir(j); err != nil {
+		return err
+	}
+	// 跨文件系统移动中断时安装目录旁可能留有未完成的副本。
+	if j.InstallPath != "" {
+		if err := storage.RemoveTree(j.InstallPath + partialSuffix); err != nil {
+			return err
+		}
+	}
+	if err := storage.RemoveTree(j.TempDir); err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if v.Number == j.Version {
+			// 元数据仍指向旧记录，保留旧清单。
+			return nil
+		}
+	}
+	if manifests, ok := i.storage.(storage.ManifestStorage); ok {
+		return manifests.DeleteManifest(j.Version)
+	}
+	return nil
+}
+
+// restoreInstallDir 把安装目录恢复到安装开始前的状态：有备份时还原备份，
+// 原本不存在时删除新目录，原本存在但尚未备份时说明旧目录未被改动。
+func (i *Installer) restoreInstallDir(j installJournal) error {
+	if j.InstallPath == "" {
+		return nil
+	}
+	if j.TempDir != "" {
+		backup := filepath.Join(j.TempDir, previousDirName)
+		if _, err := os.Lstat(backup); err == nil {
+			if err := storage.RemoveTree(j.InstallPath); err != nil {
+				return err
+			}
+			return i.restoreBackup(backup, j.InstallPath)
+		}
+	}
+	if j.Previous {
+		return nil
+	}
+	return storage.RemoveTree(j.InstallPath)
+}
+
+// restoreBackup 把备份移回安装目录；备份前为移动恢复了写权限，启用只读安装时移回后重新冻结。
+func (i *Installer) restoreBackup(backup, installPath string) error {
+	if err := moveDir(backup, installPath); err != nil {
+		return err
+	}
+	if i.perms.ReadOnly {
+		return freeze(installPath, nil)
+	}
+	return nil
+}
@@ -3,24 +3,53 @@ package version
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/liangyou/govm/internal/storage"
 	"github.com/liangyou/govm/pkg/models"
 )
 
+// LeftoverFunc 在精确卸载后安装目录中仍残留用户自行添加的文件时被调用。
+type LeftoverFunc func(version, dir string, files []string)
+
+// UninstallerOption 配置 Uninstaller。
+type UninstallerOption func(*Uninstaller)
+
+// WithLeftoverReporter 设置残留文件的回调。
+func WithLeftoverReporter(fn LeftoverFunc) UninstallerOption {
+	return func(u *Uninstaller) {
+		u.onLeftover = fn
+	}
+}
+
+// WithUninstallShims 在卸载时删除该版本的 shim。
+func WithUninstallShims(s ShimLinker) UninstallerOption {
+	return func(u *Uninstaller) {
+		u.shims = s
+	}
+}
+
 // Uninstaller 删除本地已安装的 Go 版本。
 type Uninstaller struct {
-	storage storage.LocalStorage
+	storage    storage.LocalStorage
+	onLeftover LeftoverFunc
+	shims      ShimLinker
 }
 
 // NewUninstaller 创建卸载器。
-func NewUninstaller(store storage.LocalStorage) *Uninstaller {
-	return &Uninstaller{storage: store}
+func NewUninstaller(store storage.LocalStorage, opts ...UninstallerOption) *Uninstaller {
+	u := &Uninstaller{storage: store}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
-// Uninstall 删除指定版本。当 force=true 时允许卸载当前版本。
+// Uninstall 删除指定版本。当 force=true 时允许卸载当前版本或被锁定的版本。
 func (u *Uninstaller) Uninstall(version string, force bool) ([]models.Version, error) {
 	version = strings.TrimSpace(version)
 	if version == "" {
@@ -29,6 +58,9 @@ func (u *Uninstaller) Uninstall(version string, force bool) ([]models.Version, e
 	if u.storage == nil {
 		return nil, errors.New("uninstaller: storage is required")
 	}
+	if err := checkWritable(u.storage); err != nil {
+		return nil, err
+	}
 
 	versions, err := u.storage.LoadMetadata()
 	if err != nil {
@@ -46,6 +78,10 @@ func (u *Uninstaller) Uninstall(version string, force bool) ([]models.Version, e
 		return nil, fmt.Errorf("uninstaller: version %s not installed", version)
 	}
 
+	if target.Locked && !force {
+		return nil, fmt.Errorf("uninstaller: version %s is locked, run govm unlock %s or pass force to remove", version, version)
+	}
+
 	current, err := u.storage.GetCurrentVersionMarker()
 	if err != nil {
 		return nil, fmt.Errorf("uninstaller: read current marker: %w", err)
@@ -54,9 +90,10 @@ func (u *Uninstaller) Uninstall(version string, force bool) ([]models.Version, e
 		return nil, fmt.Errorf("uninstaller: version %s is active, pass force to remove", version)
 	}
 
-	if target.InstallPath != "" {
-		if err := os.RemoveAll(target.InstallPath); err != nil && !errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("uninstaller: remove dir: %w", err)
+	// 外部安装只取消登记，文件归其原有的包管理器所有。
+	if !target.External {
+		if err := u.removeInstallDir(target); err != nil {
+			return nil, err
 		}
 	}
 
@@ -64,6 +101,18 @@ func (u *Uninstaller) Uninstall(version string, force bool) ([]models.Version, e
 		return nil, fmt.Errorf("uninstaller: delete metadata: %w", err)
 	}
 
+	if u.shims != nil {
+		if err := u.shims.Unlink(target.Number); err != nil {
+			return nil, fmt.Errorf("uninstaller: remove shim: %w", err)
+		}
+	}
+
+	if manifests, ok := u.storage.(storage.ManifestStorage); ok {
+		if err := manifests.DeleteManifest(target.Number); err != nil {
+			return nil, fmt.Errorf("uninstaller: delete manifest: %w", err)
+		}
+	}
+
 	if current == target.Number {
 		if err := u.storage.SetCurrentVersionMarker(""); err != nil {
 			return nil, fmt.Errorf("uninstaller: clear current marker: %w", err)
@@ -77,3 +126,84 @@ func (u *Uninstaller) Uninstall(version string, force bool) ([]models.Version, e
 
 	return remaining, nil
 }
+
+// removeInstallDir 有清单时只删除安装时写入的文件并清理空目录，否则整体删除。
+func (u *Uninstaller) removeInstallDir(target *models.Version) error {
+	if target.InstallPath == "" {
+		return nil
+	}
+	manifests, ok := u.storage.(storage.ManifestStorage)
+	if !ok {
+		return removeAll(target.InstallPath)
+	}
+	manifest, err := manifests.LoadManifest(target.Number)
+	if errors.Is(err, storage.ErrNoManifest) {
+		return removeAll(target.InstallPath)
+	}
+	if err != nil {
+		return fmt.Errorf("uninstaller: load manifest: %w", err)
+	}
+	if err := storage.Thaw(target.InstallPath); err != nil {
+		return fmt.Errorf("uninstaller: %w", err)
+	}
+
+	for _, entry := range manifest.Files {
+		path := filepath.Join(target.InstallPath, filepath.FromSlash(entry.Path))
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("uninstaller: remove %s: %w", entry.Path, err)
+		}
+	}
+	pruneEmptyDirs(target.InstallPath)
+
+	leftovers, err := collectFiles(target.InstallPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("uninstaller: scan leftovers: %w", err)
+	}
+	if len(leftovers) > 0 && u.onLeftover != nil {
+		u.onLeftover(target.Number, target.InstallPath, leftovers)
+	}
+	return nil
+}
+
+func removeAll(dir string) error {
+	if err := storage.RemoveTree(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("uninstaller: remove dir: %w", err)
+	}
+	return nil
+}
+
+// pruneEmptyDirs 自底向上删除 root 下（含 root）的空目录，非空目录保持不变。
+func pruneEmptyDirs(root string) {
+	var dirs []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+}
+
+// collectFiles 返回 root 下所有非目录条目的相对路径（使用 / 分隔）。
+func collectFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
@@ -1,8 +1,11 @@
 package version
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/liangyou/govm/internal/storage"
@@ -72,6 +75,37 @@ func TestUninstallCurrentVersionNeedsForce(t *testing.T) {
 	}
 }
 
+func TestUninstallLockedVersionNeedsForce(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+	version := models.Version{Number: "1.22.0", InstallPath: store.GetInstallPath("1.22.0")}
+	if err := os.MkdirAll(version.InstallPath, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := store.SaveMetadata(version); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	if err := store.SetLocked("1.22.0", true); err != nil {
+		t.Fatalf("SetLocked: %v", err)
+	}
+	if err := store.SetLocked("1.99.0", true); err == nil {
+		t.Fatal("expected SetLocked to reject a missing version")
+	}
+
+	u := NewUninstaller(store)
+	if _, err := u.Uninstall("1.22.0", false); err == nil {
+		t.Fatal("expected locked version to require force")
+	}
+	if _, err := os.Stat(version.InstallPath); err != nil {
+		t.Fatalf("locked install removed: %v", err)
+	}
+	if _, err := u.Uninstall("1.22.0", true); err != nil {
+		t.Fatalf("forced uninstall: %v", err)
+	}
+}
+
 func TestUninstallNonexistentVersion(t *testing.T) {
 	t.Parallel()
 
@@ -82,3 +116,134 @@ func TestUninstallNonexistentVersion(t *testing.T) {
 		t.Fatal("expected error for missing version")
 	}
 }
+
+func TestUninstallKeepsUserAddedFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	archive := createGoArchive(t, map[string]string{
+		"bin/go":           "binary",
+		"src/fmt/print.go": "package fmt",
+	})
+	if err := NewInstaller(store, &stubDownloader{path: archive}).Install(models.Version{Number: "1.22.0"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	installPath := store.GetInstallPath("1.22.0")
+	if err := os.WriteFile(filepath.Join(installPath, "bin", "mytool"), []byte("custom"), 0o755); err != nil {
+		t.Fatalf("write user file: %v", err)
+	}
+
+	var leftovers []string
+	u := NewUninstaller(store, WithLeftoverReporter(func(ver, dir string, files []string) {
+		leftovers = files
+	}))
+	if _, err := u.Uninstall("1.22.0", false); err != nil {
+		t.Fatalf("Uninstall error: %v", err)
+	}
+
+	if len(leftovers) != 1 || leftovers[0] != "bin/mytool" {
+		t.Fatalf("unexpected leftovers: %v", leftovers)
+	}
+	if _, err := os.Stat(filepath.Join(installPath, "bin", "mytool")); err != nil {
+		t.Fatalf("user file should be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installPath, "src")); !os.IsNotExist(err) {
+		t.Fatalf("installed tree should be pruned, got %v", err)
+	}
+}
+
+type recordingShims struct {
+	linked   map[string]string
+	unlinked []string
+	// failNext 不为空时下一次 Link 返回该错误。
+	failNext error
+}
+
+func (r *recordingShims) Link(version, goRoot string) error {
+	if err := r.failNext; err != nil {
+		r.failNext = nil
+		return err
+	}
+	if r.linked == nil {
+		r.linked = map[string]string{}
+	}
+	r.linked[version] = goRoot
+	return nil
+}
+
+func (r *recordingShims) Unlink(version string) error {
+	r.unlinked = append(r.unlinked, version)
+	return nil
+}
+
+func TestUninstallRemovesShim(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root})
+	version := models.Version{Number: "1.21.0", InstallPath: store.GetInstallPath("1.21.0")}
+	if err := os.MkdirAll(version.InstallPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveMetadata(version); err != nil {
+		t.Fatal(err)
+	}
+
+	shims := &recordingShims{}
+	if _, err := NewUninstaller(store, WithUninstallShims(shims)).Uninstall("1.21.0", false); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	if len(shims.unlinked) != 1 || shims.unlinked[0] != "1.21.0" {
+		t.Fatalf("shim not removed: %v", shims.unlinked)
+	}
+}
+
+// damageMetadata 把 metadata.json 改成无法写入的状态：corrupt 时截断文件，否则写成更新的结构版本。
+func damageMetadata(t *testing.T, root string, corrupt bool) {
+	t.Helper()
+	path := filepath.Join(root, "metadata.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrupt {
+		data = data[:len(data)-3]
+	} else {
+		data = []byte(strings.Replace(string(data), fmt.Sprintf(`"schemaVersion": %d`, storage.CurrentSchemaVersion), `"schemaVersion": 99`, 1))
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUninstallRefusesReadOnlyMetadataBeforeRemovingFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		corrupt bool
+		want    error
+	}{
+		{name: "corrupt", corrupt: true, want: storage.ErrCorruptMetadata},
+		{name: "newer schema", want: storage.ErrNewerSchema},
+	} {
+		root := t.TempDir()
+		store := storage.NewFileStorage(models.Config{RootDir: root})
+		version := models.Version{Number: "1.21.0", InstallPath: store.GetInstallPath("1.21.0")}
+		if err := os.MkdirAll(version.InstallPath, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SaveMetadata(version); err != nil {
+			t.Fatal(err)
+		}
+		damageMetadata(t, root, tc.corrupt)
+
+		if _, err := NewUninstaller(store).Uninstall("1.21.0", true); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: Uninstall = %v, want %v", tc.name, err, tc.want)
+		}
+		if _, err := os.Stat(version.InstallPath); err != nil {
+			t.Fatalf("%s: install dir must be left in place: %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// VerifyReport 描述一次安装目录校验的结果。
+type VerifyReport struct {
+	Version  string
+	Checked  int
+	Modified []string
+	Missing  []string
+	Added    []string // 清单之外、由用户自行写入 GOROOT 的文件
+}
+
+// OK 表示清单中的文件全部存在且未被修改，不考虑用户新增的文件。
+func (r VerifyReport) OK() bool {
+	return len(r.Modified) == 0 && len(r.Missing) == 0
+}
+
+// Verifier 根据安装时记录的文件清单重新校验安装目录。
+type Verifier struct {
+	storage   storage.LocalStorage
+	manifests storage.ManifestStorage
+}
+
+// NewVerifier 创建 Verifier，store 需同时实现 ManifestStorage。
+func NewVerifier(store storage.LocalStorage) *Verifier {
+	v := &Verifier{storage: store}
+	if m, ok := store.(storage.ManifestStorage); ok {
+		v.manifests = m
+	}
+	return v
+}
+
+// Verify 重新计算指定版本所有文件的 SHA256 并与清单比对。
+func (v *Verifier) Verify(version string) (VerifyReport, error) {
+	version = strings.TrimSpace(version)
+	report := VerifyReport{Version: version}
+	if v.storage == nil || v.manifests == nil {
+		return report, errors.New("verifier: manifest storage is required")
+	}
+
+	target, err := v.findInstalled(version)
+	if err != nil {
+		return report, err
+	}
+	manifest, err := v.manifests.LoadManifest(version)
+	if err != nil {
+		return report, fmt.Errorf("verifier: load manifest for %s: %w", version, err)
+	}
+
+	known := make(map[string]struct{}, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		known[entry.Path] = struct{}{}
+		report.Checked++
+		path := filepath.Join(target.InstallPath, filepath.FromSlash(entry.Path))
+		ok, err := matchesEntry(path, entry)
+		if errors.Is(err, os.ErrNotExist) {
+			report.Missing = append(report.Missing, entry.Path)
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("verifier: check %s: %w", entry.Path, err)
+		}
+		if !ok {
+			report.Modified = append(report.Modified, entry.Path)
+		}
+	}
+
+	added, err := unknownFiles(target.InstallPath, known)
+	if err != nil {
+		return report, fmt.Errorf("verifier: scan %s: %w", target.InstallPath, err)
+	}
+	report.Added = added
+	return report, nil
+}
+
+// unknownFiles 返回 root 下不在 known 中的文件相对路径。
+func unknownFiles(root string, known map[string]struct{}) ([]string, error) {
+	var added []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := known[rel]; !ok {
+			added = append(added, rel)
+		}
+		return nil
+	})
+	sort.Strings(added)
+	return added, err
+}
+
+func (v *Verifier) findInstalled(version string) (*models.Version, error) {
+	versions, err := v.storage.LoadMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("verifier: load metadata: %w", err)
+	}
+	for i := range versions {
+		if versions[i].Number == version {
+			if versions[i].InstallPath == "" {
+				return nil, fmt.Errorf("verifier: version %s missing install path", version)
+			}
+			return &versions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("verifier: version %s not installed", version)
+}
+
+func matchesEntry(path string, entry models.FileEntry) (bool, error) {
+	if entry.Link != "" {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		return link == entry.Link, nil
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+	if !info.Mode().IsRegular() || info.Size() != entry.Size {
+		return false, nil
+	}
+	if entry.Mode != 0 && info.Mode().Perm() != entry.Mode {
+		return false, nil
+	}
+	sum, err := hashFile(path)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(sum, entry.SHA256), nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
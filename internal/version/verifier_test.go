@@ -0,0 +1,97 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestVerifierDetectsDrift(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	store := storage.NewFileStorage(models.Config{RootDir: root, VersionsDir: filepath.Join(root, "versions")})
+	archive := createGoArchive(t, map[string]string{
+		"bin/go":           "binary",
+		"bin/gofmt":        "fmt",
+		"src/fmt/print.go": "package fmt",
+	})
+	installer := NewInstaller(store, &stubDownloader{path: archive})
+	if err := installer.Install(models.Version{Number: "1.22.0", FullName: "go1.22.0"}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	verifier := NewVerifier(store)
+	report, err := verifier.Verify("1.22.0")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK() || report.Checked != 3 {
+		t.Fatalf("fresh install should verify cleanly: %#v", report)
+	}
+
+	installPath := store.GetInstallPath("1.22.0")
+	if err := os.WriteFile(filepath.Join(installPath, "src", "fmt", "print.go"), []byte("package evil"), 0o644); err != nil {
+		t.Fatalf("tamper file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(installPath, "bin", "gofmt")); err != nil {
+		t.Fatalf("remove file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "bin", "mytool"), []byte("custom"), 0o755); err != nil {
+		t.Fatalf("add file: %v", err)
+	}
+
+	report, err = verifier.Verify("1.22.0")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(report.Modified) != 1 || report.Modified[0] != "src/fmt/print.go" {
+		t.Fatalf("unexpected modified list: %v", report.Modified)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "bin/gofmt" {
+		t.Fatalf("unexpected missing list: %v", report.Missing)
+	}
+	if len(report.Added) != 1 || report.Added[0] != "bin/mytool" {
+		t.Fatalf("unexpected added list: %v", report.Added)
+	}
+
+	if _, err := NewUninstaller(store).Uninstall("1.22.0", true); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if _, err := store.LoadManifest("1.22.0"); !errors.Is(err, storage.ErrNoManifest) {
+		t.Fatalf("manifest should be removed on uninstall, got %v", err)
+	}
+}
+
+func TestDiffManifests(t *testing.T) {
+	t.Parallel()
+
+	from := models.FileManifest{Version: "1.22.0", Files: []models.FileEntry{
+		{Path: "bin/go", Size: 3, Mode: 0o755, SHA256: "aaa"},
+		{Path: "src/old.go", Size: 1, SHA256: "bbb"},
+		{Path: "VERSION", Size: 7, Mode: 0o644, SHA256: "ccc"},
+	}}
+	to := models.FileManifest{Version: "1.22.1", Files: []models.FileEntry{
+		{Path: "bin/go", Size: 3, Mode: 0o755, SHA256: "aaa"},
+		{Path: "src/new.go", Size: 1, SHA256: "ddd"},
+		{Path: "VERSION", Size: 7, Mode: 0o644, SHA256: "eee"},
+	}}
+
+	diff := DiffManifests(from, to)
+	if len(diff.Added) != 1 || diff.Added[0] != "src/new.go" {
+		t.Fatalf("unexpected added: %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "src/old.go" {
+		t.Fatalf("unexpected removed: %v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0] != "VERSION" {
+		t.Fatalf("unexpected changed: %v", diff.Changed)
+	}
+	if DiffManifests(from, from).Empty() != true {
+		t.Fatal("identical manifests should produce an empty diff")
+	}
+}
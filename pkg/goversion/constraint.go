@@ -0,0 +1,186 @@
+package goversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 约束条件的运算符；opSeries 为 1.22.x、1.22 这类系列匹配。
+const (
+	opEqual    = "="
+	opNotEqual = "!="
+	opGreater  = ">"
+	opAtLeast  = ">="
+	opLess     = "<"
+	opAtMost   = "<="
+	opTilde    = "~"
+	opSeries   = "series"
+)
+
+// term 为约束中的单个条件；depth 为系列匹配时需要相等的段数（1.x 为 1，1.22.x 为 2）。
+type term struct {
+	op    string
+	v     Version
+	depth int
+}
+
+// Constraint 为解析后的版本约束：由 "||" 分隔的任一组条件全部满足即匹配。
+type Constraint struct {
+	expr   string
+	groups [][]term
+}
+
+// IsConstraint 判断参数是版本范围而不是单个版本号，例如 1.22.x、">=1.21 <1.23"、~1.22.3。
+func IsConstraint(s string) bool {
+	return strings.ContainsAny(strings.TrimSpace(s), "<>=!~*|, \t") || strings.Contains(s, "x")
+}
+
+// ParseConstraint 解析约束表达式。同一组内的条件以空白或逗号分隔且需全部满足，组之间以 "||" 分隔，支持：
+//   - 比较：>=1.21、<1.23、=1.22.3、!=1.22.0（1.22 与 1.22.0 相等）
+//   - 系列：1.22.x、1.22.*、1.x，以及不带运算符的 1.22
+//   - 补丁范围：~1.22.3 表示 >=1.22.3 且属于 1.22 系列
+//   - 精确版本：1.22.3、1.23rc1
+//
+// 与 semver 一致，预发布版本只在同一组内有条件写明同一版本的预发布号时才匹配，例如 ">=1.23rc1"。
+func ParseConstraint(expr string) (Constraint, error) {
+	c := Constraint{expr: strings.TrimSpace(expr)}
+	for _, group := range strings.Split(expr, "||") {
+		terms, err := parseGroup(group)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("goversion: invalid constraint %q: %w", c.expr, err)
+		}
+		c.groups = append(c.groups, terms)
+	}
+	return c, nil
+}
+
+func parseGroup(group string) ([]term, error) {
+	fields := strings.FieldsFunc(group, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	var terms []term
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		op := operator(field)
+		number := field[len(op):]
+		// 允许运算符与版本之间有空格，例如 ">= 1.21"。
+		if number == "" && op != "" && i+1 < len(fields) {
+			i++
+			number = fields[i]
+		}
+		number = strings.TrimPrefix(number, "go")
+		if !validNumber(number) {
+			return nil, fmt.Errorf("bad version %q", field)
+		}
+		t := term{op: op, v: Parse(number)}
+		switch wild := strings.TrimSuffix(strings.TrimSuffix(number, ".x"), ".*"); {
+		case wild != number:
+			if op != "" && op != opEqual {
+				return nil, fmt.Errorf("wildcard %q cannot be combined with %s", number, op)
+			}
+			t.op, t.v, t.depth = opSeries, Parse(wild), strings.Count(wild, ".")+1
+		case op == "" && strings.Count(number, ".") < 2 && !IsPrerelease(number):
+			t.op, t.depth = opSeries, strings.Count(number, ".")+1
+		case op == "":
+			t.op = opEqual
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return terms, nil
+}
+
+// String 返回原始表达式。
+func (c Constraint) String() string {
+	return c.expr
+}
+
+// Matches 判断版本号是否满足约束。
+func (c Constraint) Matches(number string) bool {
+	v := Parse(number)
+	for _, group := range c.groups {
+		if groupMatches(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Best 返回 candidates 中满足约束的最高版本。
+func (c Constraint) Best(candidates []string) (string, bool) {
+	best := ""
+	for _, number := range candidates {
+		if c.Matches(number) && (best == "" || Compare(number, best) > 0) {
+			best = number
+		}
+	}
+	return best, best != ""
+}
+
+func groupMatches(group []term, v Version) bool {
+	if v.Pre != "" && !allowsPrerelease(group, v) {
+		return false
+	}
+	for _, t := range group {
+		if !t.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsPrerelease 判断组内是否有条件写明了与 v 同一版本的预发布号。
+func allowsPrerelease(group []term, v Version) bool {
+	for _, t := range group {
+		if t.v.Pre != "" && t.v.Major == v.Major && t.v.Minor == v.Minor && t.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (t term) matches(v Version) bool {
+	cmp := v.Compare(t.v)
+	switch t.op {
+	case opEqual:
+		return cmp == 0
+	case opNotEqual:
+		return cmp != 0
+	case opGreater:
+		return cmp > 0
+	case opAtLeast:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opAtMost:
+		return cmp <= 0
+	case opTilde:
+		return cmp >= 0 && v.Major == t.v.Major && v.Minor == t.v.Minor
+	case opSeries:
+		return v.Major == t.v.Major && (t.depth < 2 || v.Minor == t.v.Minor) && (t.depth < 3 || v.Patch == t.v.Patch)
+	}
+	return false
+}
+
+func operator(field string) string {
+	for _, op := range []string{opAtLeast, opAtMost, opNotEqual, opGreater, opLess, opEqual, opTilde} {
+		if strings.HasPrefix(field, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// validNumber 检查版本号以数字开头，只包含数字、点与预发布标记，通配符只能作为最后一段。
+func validNumber(number string) bool {
+	if number == "" || number[0] < '0' || number[0] > '9' {
+		return false
+	}
+	body := strings.TrimSuffix(strings.TrimSuffix(number, ".x"), ".*")
+	for _, ch := range body {
+		if !(ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'w' || ch == '.') {
+			return false
+		}
+	}
+	return !strings.HasSuffix(body, ".")
+}
@@ -0,0 +1,70 @@
+package goversion
+
+import "testing"
+
+func TestConstraintMatches(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr  string
+		match []string
+		miss  []string
+	}{
+		{">=1.21 <1.23", []string{"1.21.0", "1.21.8", "1.22.3"}, []string{"1.20.14", "1.23.0", "1.22rc1"}},
+		{">= 1.21, < 1.23", []string{"1.22.0"}, []string{"1.23.1"}},
+		{"1.22.x", []string{"1.22.0", "1.22.10"}, []string{"1.21.8", "1.2.2", "1.220.0", "1.22rc1"}},
+		{"go1.22.*", []string{"1.22.3"}, []string{"1.23.0"}},
+		{"1.22", []string{"1.22.3"}, []string{"1.23.0"}},
+		{"1.x", []string{"1.21.0", "1.23.4"}, []string{"2.0.0"}},
+		{"~1.22.3", []string{"1.22.3", "1.22.9"}, []string{"1.22.2", "1.23.0"}},
+		{"1.22.3", []string{"1.22.3", "go1.22.3"}, []string{"1.22.4"}},
+		{"=1.20", []string{"1.20", "1.20.0"}, []string{"1.20.1"}},
+		{"1.22.x !=1.22.0", []string{"1.22.1"}, []string{"1.22.0"}},
+		{"1.20.x || >=1.22", []string{"1.20.14", "1.23.0"}, []string{"1.21.8"}},
+		{">=1.23rc1", []string{"1.23rc1", "1.23rc2", "1.23.0"}, []string{"1.23beta1", "1.24rc1"}},
+		{"1.23rc1", []string{"1.23rc1"}, []string{"1.23.0"}},
+	}
+	for _, tc := range cases {
+		c, err := ParseConstraint(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q): %v", tc.expr, err)
+		}
+		for _, number := range tc.match {
+			if !c.Matches(number) {
+				t.Errorf("%q must match %s", tc.expr, number)
+			}
+		}
+		for _, number := range tc.miss {
+			if c.Matches(number) {
+				t.Errorf("%q must not match %s", tc.expr, number)
+			}
+		}
+	}
+
+	for _, expr := range []string{"", "  ", ">=", "latest", ">=1.22.x", "1.22;rm", "1.x.3", "1.22 ||"} {
+		if _, err := ParseConstraint(expr); err == nil {
+			t.Errorf("ParseConstraint(%q) must fail", expr)
+		}
+	}
+}
+
+func TestConstraintBest(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseConstraint("1.22.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best, ok := c.Best([]string{"1.22.9", "1.23rc1", "1.22.10", "1.22rc2", "1.21.8"}); !ok || best != "1.22.10" {
+		t.Fatalf("Best = %q, %v", best, ok)
+	}
+	if _, ok := c.Best([]string{"1.21.8"}); ok {
+		t.Fatal("Best must report no match")
+	}
+
+	for in, want := range map[string]bool{"1.22.x": true, ">=1.21": true, "~1.22": true, "1.22 || 1.23": true, "1.22.3": false, "1.23rc1": false, "1.22": false} {
+		if got := IsConstraint(in); got != want {
+			t.Errorf("IsConstraint(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
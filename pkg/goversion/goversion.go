@@ -0,0 +1,113 @@
+// Package goversion 解析与比较 Go 版本号（1.22.3、1.23rc1、go1.9beta2），并支持 semver 风格的版本约束。
+//
+// govm 的远程列表、本地列表与版本范围解析都使用这里的排序规则，嵌入 govm 的宿主程序可以直接复用以保持一致。
+package goversion
+
+import "strconv"
+
+// prereleaseRank 为预发布标记的排序：beta < rc < 正式版；未知标记排在 beta 之前。
+var prereleaseRank = map[string]int{
+	"beta": 1,
+	"rc":   2,
+	"":     3,
+}
+
+// Version 为解析后的版本号，例如 go1.22rc1 为 {Major: 1, Minor: 22, Pre: "rc", PreNum: 1}。
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+	PreNum              int
+}
+
+// Parse 解析版本号，可带 go 前缀；缺少的段视为 0，无法识别的字符之后的内容被忽略，因此不会失败。
+func Parse(s string) Version {
+	if len(s) >= 2 && s[:2] == "go" {
+		s = s[2:]
+	}
+	var v Version
+	fields := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	for i := 0; i < len(fields); i++ {
+		n, rest := leadingInt(s)
+		*fields[i] = n
+		s = rest
+		if s == "" {
+			break
+		}
+		if s[0] != '.' {
+			v.Pre, v.PreNum = prerelease(s)
+			break
+		}
+		s = s[1:]
+	}
+	return v
+}
+
+// IsPrerelease 判断版本号是否为 beta、rc 等预发布版本。
+func IsPrerelease(s string) bool {
+	return Parse(s).Pre != ""
+}
+
+// Compare 比较两个版本号，返回 1 表示 a>b，-1 表示 a<b；1.22 与 1.22.0 相等，预发布版本排在对应正式版之前。
+func Compare(a, b string) int {
+	return Parse(a).Compare(Parse(b))
+}
+
+// Compare 比较两个已解析的版本号。
+func (v Version) Compare(o Version) int {
+	if c := cmpInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	if v.Pre != o.Pre {
+		return cmpInt(prereleaseRank[v.Pre], prereleaseRank[o.Pre])
+	}
+	return cmpInt(v.PreNum, o.PreNum)
+}
+
+// Series 返回版本号所属的 major.minor 系列，例如 1.23rc1 属于 1.23；没有次版本号时原样返回。
+func Series(s string) string {
+	trimmed := s
+	if len(trimmed) >= 2 && trimmed[:2] == "go" {
+		trimmed = trimmed[2:]
+	}
+	if _, rest := leadingInt(trimmed); rest == "" || rest[0] != '.' {
+		return s
+	}
+	v := Parse(trimmed)
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// leadingInt 解析开头的数字，返回其值与剩余部分。
+func leadingInt(s string) (int, string) {
+	n, i := 0, 0
+	for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, s[i:]
+}
+
+// prerelease 拆分 "rc1" 这样的预发布后缀。
+func prerelease(s string) (string, int) {
+	i := 0
+	for i < len(s) && (s[i] < '0' || s[i] > '9') {
+		i++
+	}
+	n, _ := leadingInt(s[i:])
+	return s[:i], n
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	default:
+		return 0
+	}
+}
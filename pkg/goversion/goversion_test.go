@@ -0,0 +1,43 @@
+package goversion
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.22.0", "1.22", 0},
+		{"go1.21.1", "go1.21", 1},
+		{"1.9", "1.10", -1},
+		{"1.22.10", "1.22.9", 1},
+		{"1.21", "1.21rc1", 1},
+		{"1.21rc2", "1.21rc1", 1},
+		{"1.21beta1", "1.21rc1", -1},
+		{"1.20.5", "1.21beta1", -1},
+		{"go1.9beta2", "1.9", -1},
+		{"1.22.", "1.22.0", 0},
+		{"", "1", -1},
+	}
+	for _, c := range cases {
+		if got := Compare(c.a, c.b); got != c.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := Compare(c.b, c.a); got != -c.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", c.b, c.a, got, -c.want)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { Compare("1.22rc1", "1.22beta2") }); allocs != 0 {
+		t.Fatalf("Compare allocates %.0f times per call", allocs)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{"1.23rc1": "1.23", "1.22.3": "1.22", "go1.021": "1.21", "1": "1"} {
+		if got := Series(in); got != want {
+			t.Errorf("Series(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Package govm 提供可嵌入的 Go 版本管理能力，供 GUI、编辑器插件等宿主程序直接调用。
+//
+// 库本身不会向 stdout/stderr 写入任何内容：进度通过 WithProgress 回调、日志通过
+// WithLogger 注入的 slog.Logger、需要用户确认的操作通过 WithConfirm 回调交给宿主处理。
+package govm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liangyou/govm/internal/env"
+	"github.com/liangyou/govm/internal/events"
+	"github.com/liangyou/govm/internal/region"
+	"github.com/liangyou/govm/internal/remote"
+	"github.com/liangyou/govm/internal/storage"
+	"github.com/liangyou/govm/internal/version"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+// ErrAborted 表示确认回调拒绝了操作。
+var ErrAborted = errors.New("govm: operation aborted by confirmation hook")
+
+// ProgressEvent 描述安装过程中的一次进度事件。
+type ProgressEvent struct {
+	Type       string // download_start、download_progress、extract、done、error
+	Version    string
+	Downloaded int64
+	Total      int64
+	Path       string
+	Status     string
+	Message    string
+}
+
+// CurrentChange 描述一次当前版本变化，版本号为空表示没有激活版本。
+type CurrentChange struct {
+	Previous string
+	Current  string
+}
+
+// Listing 为 ListAll 的结果：Remote 中已安装的版本带有 InstallPath 与 IsCurrent，获取失败的一方为 nil。
+type Listing struct {
+	Remote []models.Version
+	Local  []models.Version
+}
+
+// ProgressFunc 接收进度事件。
+type ProgressFunc func(ProgressEvent)
+
+// ConfirmFunc 在执行破坏性操作前被调用，返回 false 表示取消。
+type ConfirmFunc func(prompt string) bool
+
+// Option 配置 Manager。
+type Option func(*Manager)
+
+// WithConfig 指定根目录、版本目录与 GOPATH 等配置。
+func WithConfig(cfg models.Config) Option {
+	return func(m *Manager) {
+		m.cfg = cfg
+	}
+}
+
+// WithMirror 指定远程 API 与下载地址，默认使用 go.dev 官方源。
+func WithMirror(mirror region.MirrorConfig) Option {
+	return func(m *Manager) {
+		m.mirror = mirror
+	}
+}
+
+// WithProgress 注册进度回调。
+func WithProgress(fn ProgressFunc) Option {
+	return func(m *Manager) {
+		m.progress = fn
+	}
+}
+
+// WithLogger 注入结构化日志记录器，默认丢弃所有日志。
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Manager) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+// WithConfirm 注册确认回调，未注册时破坏性操作（如卸载当前版本）会直接返回 ErrAborted。
+func WithConfirm(fn ConfirmFunc) Option {
+	return func(m *Manager) {
+		m.confirm = fn
+	}
+}
+
+// Manager 是 govm 的库级入口。
+type Manager struct {
+	cfg      models.Config
+	mirror   region.MirrorConfig
+	progress ProgressFunc
+	logger   *slog.Logger
+	confirm  ConfirmFunc
+
+	store       *storage.FileStorage
+	lister      *version.Lister
+	installer   *version.Installer
+	switcher    *version.Switcher
+	uninstaller *version.Uninstaller
+}
+
+// New 创建 Manager 并完成内部服务装配；无法确定根目录时不会退回临时目录，Install 等操作返回 storage 的错误。
+func New(opts ...Option) *Manager {
+	m := &Manager{
+		mirror: region.GoDevMirror,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	bus := events.NewBus()
+	bus.Subscribe(m.forwardProgress)
+
+	m.store = storage.NewFileStorage(m.cfg)
+	remoteClient := remote.NewClient(
+		remote.WithBaseURL(m.mirror.APIBase),
+		remote.WithDownloadBase(m.mirror.DownloadBase),
+	)
+	downloader := version.NewDownloader(m.cfg, version.WithDownloadEvents(bus))
+	m.installer = version.NewInstaller(m.store, downloader, version.WithInstallEvents(bus))
+	m.switcher = version.NewSwitcher(m.store, env.NewManager(m.store, m.cfg))
+	m.uninstaller = version.NewUninstaller(m.store)
+	m.lister = version.NewLister(remoteClient, m.store)
+	return m
+}
+
+// RemoteVersions 返回远程可安装版本。
+func (m *Manager) RemoteVersions() ([]models.Version, error) {
+	return m.lister.RemoteVersions()
+}
+
+// LocalVersions 返回本地已安装版本。
+func (m *Manager) LocalVersions() ([]models.Version, error) {
+	return m.lister.LocalVersions()
+}
+
+// ListAll 并发获取远程与本地版本；任一方失败时仍返回另一方的结果，错误合并返回，ctx 结束时立即返回。
+func (m *Manager) ListAll(ctx context.Context) (Listing, error) {
+	listing, err := m.lister.ListAll(ctx)
+	return Listing{Remote: listing.Remote, Local: listing.Local}, err
+}
+
+// CurrentVersion 返回当前激活版本，未激活时返回 nil。
+func (m *Manager) CurrentVersion() (*models.Version, error) {
+	return m.lister.CurrentVersion()
+}
+
+// Install 从远程列表中解析并安装指定版本。
+func (m *Manager) Install(ver string) error {
+	if err := m.store.Err(); err != nil {
+		return err
+	}
+	ver = normalizeVersion(ver)
+	m.logger.Info("install requested", "version", ver)
+	versions, err := m.lister.RemoteVersions()
+	if err != nil {
+		m.logger.Error("fetch remote versions failed", "error", err)
+		return err
+	}
+	for _, v := range versions {
+		if v.Number != ver {
+			continue
+		}
+		if err := m.installer.Install(v); err != nil {
+			m.logger.Error("install failed", "version", ver, "error", err)
+			return err
+		}
+		m.logger.Info("install finished", "version", ver)
+		return nil
+	}
+	return fmt.Errorf("govm: version %s not found in remote list", ver)
+}
+
+// Use 切换到已安装的版本。
+func (m *Manager) Use(ver string) error {
+	if err := m.store.Err(); err != nil {
+		return err
+	}
+	ver = normalizeVersion(ver)
+	m.logger.Info("switch requested", "version", ver)
+	if err := m.switcher.UseVersion(ver); err != nil {
+		m.logger.Error("switch failed", "version", ver, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Uninstall 卸载指定版本；若为当前版本，需确认回调同意后才会执行。
+func (m *Manager) Uninstall(ver string) error {
+	ver = normalizeVersion(ver)
+	current, err := m.store.GetCurrentVersionMarker()
+	if err != nil {
+		return err
+	}
+	force := false
+	if current == ver {
+		if m.confirm == nil || !m.confirm(fmt.Sprintf("go%s is active, uninstall anyway?", ver)) {
+			m.logger.Warn("uninstall aborted", "version", ver)
+			return ErrAborted
+		}
+		force = true
+	}
+	m.logger.Info("uninstall requested", "version", ver, "force", force)
+	if _, err := m.uninstaller.Uninstall(ver, force); err != nil {
+		m.logger.Error("uninstall failed", "version", ver, "error", err)
+		return err
+	}
+	return nil
+}
+
+// WatchCurrent 返回当前版本，并在每次切换（包括其他进程执行的 govm use）后向通道发送变化；
+// interval 为轮询间隔，不大于 0 时使用一秒，ctx 结束后通道关闭。
+func (m *Manager) WatchCurrent(ctx context.Context, interval time.Duration) (string, <-chan CurrentChange, error) {
+	current, changes, err := m.store.WatchCurrent(ctx, interval)
+	if err != nil {
+		return "", nil, err
+	}
+	out := make(chan CurrentChange)
+	go func() {
+		defer close(out)
+		for c := range changes {
+			select {
+			case out <- CurrentChange{Previous: c.Previous, Current: c.Current}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return current, out, nil
+}
+
+func (m *Manager) forwardProgress(e events.Event) {
+	m.logger.Debug("event", "type", e.Type, "version", e.Get("version"))
+	if m.progress == nil {
+		return
+	}
+	downloaded, _ := strconv.ParseInt(e.Get("downloaded"), 10, 64)
+	total, _ := strconv.ParseInt(e.Get("total"), 10, 64)
+	m.progress(ProgressEvent{
+		Type:       e.Type,
+		Version:    e.Get("version"),
+		Downloaded: downloaded,
+		Total:      total,
+		Path:       e.Get("path"),
+		Status:     e.Get("status"),
+		Message:    e.Get("message"),
+	})
+}
+
+func normalizeVersion(input string) string {
+	return strings.TrimPrefix(strings.TrimSpace(input), "go")
+}
@@ -0,0 +1,146 @@
+package govm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liangyou/govm/internal/region"
+	"github.com/liangyou/govm/pkg/models"
+)
+
+func TestManagerInstallReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	archive := buildArchive(t)
+	sum := sha256.Sum256(archive)
+	fileName := "go1.21.0.linux-amd64.tar.gz"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		feed := []map[string]any{{
+			"version": "go1.21.0",
+			"files": []map[string]string{{
+				"filename": fileName,
+				"os":       "linux",
+				"arch":     "amd64",
+				"sha256":   hex.EncodeToString(sum[:]),
+				"kind":     "archive",
+			}},
+		}}
+		if err := json.NewEncoder(w).Encode(feed); err != nil {
+			t.Errorf("encode feed: %v", err)
+		}
+	})
+	mux.HandleFunc("/dl/"+fileName, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	root := t.TempDir()
+	var types []string
+	m := New(
+		WithConfig(models.Config{RootDir: root}),
+		WithMirror(region.MirrorConfig{APIBase: server.URL + "/feed", DownloadBase: server.URL + "/dl/"}),
+		WithProgress(func(e ProgressEvent) { types = append(types, e.Type) }),
+	)
+
+	if err := m.Install("go1.21.0"); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "versions", "go1.21.0", "bin", "go")); err != nil {
+		t.Fatalf("go binary not installed: %v", err)
+	}
+	if len(types) < 3 || types[0] != "download_start" || types[len(types)-1] != "done" {
+		t.Fatalf("unexpected progress events: %v", types)
+	}
+}
+
+func TestManagerUninstallActiveRequiresConfirmation(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	cfg := models.Config{RootDir: root}
+	installPath := filepath.Join(root, "versions", "go1.20.0")
+	if err := os.MkdirAll(installPath, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	var prompts []string
+	answer := false
+	m := New(WithConfig(cfg), WithConfirm(func(prompt string) bool {
+		prompts = append(prompts, prompt)
+		return answer
+	}))
+	if err := m.store.SaveMetadata(models.Version{Number: "1.20.0", InstallPath: installPath}); err != nil {
+		t.Fatalf("SaveMetadata: %v", err)
+	}
+	if err := m.store.SetCurrentVersionMarker("1.20.0"); err != nil {
+		t.Fatalf("SetCurrentVersionMarker: %v", err)
+	}
+
+	if err := m.Uninstall("1.20.0"); !errors.Is(err, ErrAborted) {
+		t.Fatalf("expected ErrAborted, got %v", err)
+	}
+	if _, err := os.Stat(installPath); err != nil {
+		t.Fatalf("install path removed despite refusal: %v", err)
+	}
+
+	answer = true
+	if err := m.Uninstall("1.20.0"); err != nil {
+		t.Fatalf("confirmed uninstall failed: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 prompts, got %v", prompts)
+	}
+	if _, err := os.Stat(installPath); !os.IsNotExist(err) {
+		t.Fatalf("install path still present: %v", err)
+	}
+}
+
+func buildArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	entries := []struct {
+		name string
+		body string
+		dir  bool
+	}{
+		{name: "go/", dir: true},
+		{name: "go/bin/", dir: true},
+		{name: "go/bin/go", body: "binary"},
+	}
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o755, Typeflag: tar.TypeReg, Size: int64(len(e.body))}
+		if e.dir {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatalf("write body: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+	return buf.Bytes()
+}
@@ -1,9 +1,52 @@
 package models
 
+import (
+	"io/fs"
+	"time"
+)
+
+// Config.ShellMode 的取值。
+const (
+	ShellInline  = "inline"  // 配置块直接写入 rc 文件
+	ShellManaged = "managed" // 配置块写入 govm 目录下的 env.sh，rc 文件只保留一行 source
+)
+
 // Config 保存 govm 的全局配置，与用户主目录下的资源保持一致。
 type Config struct {
 	RootDir        string // govm 安装根目录，默认 ~/.govm
 	VersionsDir    string // 各版本安装目录，默认 ~/.govm/versions
 	CurrentVersion string // 当前激活的纯版本号
 	GoPath         string // GOPATH 配置
+	PolicyFile     string // 团队策略文件路径，可由 GOVM_POLICY 覆盖
+	SystemMode     bool   // 多用户共享安装模式，版本安装在共享根目录
+	UserDir        string // 每用户状态目录（当前版本标记），默认 ~/.govm
+	CacheDir       string // 下载归档与版本列表缓存目录，为空时位于根目录与用户状态目录下
+	UsageStats     bool   // 是否在本地记录版本使用统计
+	Catalog        string // 版本目录来源：official、static 或 listing
+	CatalogURL     string // static/listing 目录来源的地址
+	Region         string // 下载源偏好：auto（默认，按公网 IP 探测）、global、cn 或 ISO 国家代码
+	GoToolchain    string // 写入受管环境的 GOTOOLCHAIN，为空时不设置
+	GoShim         bool   // 在 shim 目录中把 go/gofmt 链接到 govm，执行时解析版本，shell 不再固定 GOROOT
+	ShellRC        string // 接收配置块的 rc 文件，为空时按 shell 选择 .bashrc/.bash_profile/.zshrc
+	ShellMode      string // 配置块写法：inline（默认，直接写入 rc 文件）或 managed（写入 env.sh，rc 文件只 source 它）
+
+	MirrorProbe    bool          // 启用后对候选下载地址测速并选用最快的一个
+	MirrorProbeTTL time.Duration // 测速结论的缓存时间，0 表示使用默认值
+	Mirrors        []string      // 参与测速的下载地址，为空时使用内置列表
+	ChecksumSource string        // 校验值来源：auto（默认）、go.dev（必须取得官方校验值）或 mirror（信任镜像）
+
+	ConnectTimeout  time.Duration // 建立连接超时，0 表示使用默认值
+	RequestTimeout  time.Duration // 元数据请求总超时，0 表示使用默认值
+	DownloadTimeout time.Duration // 下载归档总超时，0 表示使用默认值
+
+	CABundle           string // 额外信任的 CA 证书路径
+	ClientCert         string // mTLS 客户端证书路径
+	ClientKey          string // mTLS 客户端私钥路径
+	InsecureSkipVerify bool   // 跳过 TLS 证书校验（不安全）
+
+	DirMode        fs.FileMode // 安装目录树中目录的权限，0 表示沿用归档权限（受 umask 影响）
+	FileMode       fs.FileMode // 普通文件的权限，可执行文件在此基础上补齐执行位，0 表示沿用归档权限
+	Owner          string      // 以 root 运行时安装目录树的属主，形如 user 或 user:group
+	ReadOnlyGoRoot bool        // 安装后去掉 GOROOT 目录树的写权限，卸载与重新安装时临时恢复
+	SignMetadata   bool        // 用本机密钥为 metadata.json 签名，发现 govm 之外的修改时警告
 }
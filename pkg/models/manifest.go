@@ -0,0 +1,18 @@
+package models
+
+import "io/fs"
+
+// FileEntry 描述安装目录中的单个文件，路径相对于 GOROOT。
+type FileEntry struct {
+	Path   string      `json:"path"`
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode,omitempty"` // 权限位，旧清单中为 0
+	SHA256 string      `json:"sha256,omitempty"`
+	Link   string      `json:"link,omitempty"` // 符号链接目标
+}
+
+// FileManifest 是安装时记录的文件清单，用于校验、精确卸载以及版本间差异比较。
+type FileManifest struct {
+	Version string      `json:"version"`
+	Files   []FileEntry `json:"files"`
+}
@@ -2,16 +2,70 @@ package models
 
 import "time"
 
+// 发布渠道，见 Version.Channel。
+const (
+	ChannelStable   = "stable"
+	ChannelRC       = "rc"
+	ChannelBeta     = "beta"
+	ChannelArchived = "archived"
+)
+
 // Version 描述远程或本地 Go 版本的核心元数据。
 type Version struct {
-	Number      string    // 纯版本号，例如 1.21.0
-	FullName    string    // 完整版本字符串，例如 go1.21.0
-	DownloadURL string    // 可下载的 URL
-	FileName    string    // 下载安装包的文件名
-	Checksum    string    // 官方提供的 SHA256 校验值
-	OS          string    // 操作系统标识
-	Arch        string    // 架构标识
-	InstallPath string    // 本地安装路径（如果已安装）
-	IsCurrent   bool      // 是否为当前激活版本
-	InstalledAt time.Time // 安装时间
+	Number      string      // 纯版本号，例如 1.21.0
+	FullName    string      // 完整版本字符串，例如 go1.21.0
+	DownloadURL string      // 可下载的 URL
+	FileName    string      // 下载安装包的文件名
+	Size        int64       // 安装包字节数，未知时为 0
+	Checksum    string      // 官方提供的 SHA256 校验值
+	ChecksumURL string      // Checksum 为空时从该地址获取 .sha256 文件
+	OS          string      // 操作系统标识
+	Arch        string      // 架构标识
+	Channel     string      // 发布渠道：stable、rc、beta 或 archived（已不在官方维护范围内的正式版）
+	InstallPath string      // 本地安装路径（如果已安装）
+	IsCurrent   bool        // 是否为当前激活版本
+	External    bool        // 由 govm adopt 登记的外部安装，卸载时不删除其文件
+	Locked      bool        // 由 govm lock 保护，卸载时必须显式 force
+	InstalledAt time.Time   // 安装时间
+	Provenance  *Provenance // 归档来源；govm adopt 登记的版本与旧元数据为 nil
+}
+
+// 校验值来源，见 Provenance.ChecksumSource。
+const (
+	ChecksumCatalog  = "catalog"     // 版本列表中的校验值
+	ChecksumFile     = "sha256-file" // 镜像上与归档同名的 .sha256 文件
+	ChecksumOfficial = "official"    // go.dev 官方版本列表（交叉核对一致或直接采用）
+	ChecksumManual   = "manual"      // install --sha256 指定
+)
+
+// Provenance 记录安装所用归档的来源，供 `govm -list -wide` 与安全审计查看。
+type Provenance struct {
+	Mirror         string    // 下载镜像（scheme://host）；复用本地已有归档时为空
+	URL            string    // 实际下载地址；复用本地已有归档时为空
+	ChecksumSource string    // 校验值来源：catalog、sha256-file、official 或 manual
+	FetchedAt      time.Time // 下载完成（或确认复用本地归档）的时间
+	Cached         bool      // 归档来自 downloads 目录中已有的文件，例如 govm bundle apply 放入的离线包
+}
+
+// Usage 记录单个版本的本地使用统计，仅保存在本机。
+type Usage struct {
+	Count      int       // 被激活的次数
+	LastUsedAt time.Time // 最近一次激活时间
+}
+
+// HistoryEntry 记录一次当前版本的变化，按行追加到本机的 history 文件。
+type HistoryEntry struct {
+	At       time.Time `json:"at"`
+	Version  string    `json:"version"` // 新的当前版本，为空表示不再有激活的版本
+	Previous string    `json:"previous,omitempty"`
+	Command  string    `json:"command"` // 触发变化的命令行，例如 govm use 1.22.0
+}
+
+// ReleaseNote 是 go.dev 发布历史中单个版本的说明摘要。
+type ReleaseNote struct {
+	Version  string `json:"version"`  // 纯版本号，例如 1.22.3；主版本首个发布可能没有补丁号，例如 1.9
+	Date     string `json:"date"`     // 发布日期，例如 2024-05-07
+	Summary  string `json:"summary"`  // 纯文本摘要
+	URL      string `json:"url"`      // 详细说明：主版本为发布说明页面，小版本为发布历史中的锚点
+	Security bool   `json:"security"` // 是否包含安全修复
 }